@@ -36,6 +36,9 @@ const (
 	TokenInvalidErr     = 110002
 	TokenExpiredErr     = 110003
 	PermissionDeniedErr = 110004
+	LoginLockedErr      = 110005
+	WebhookSignatureErr = 110006
+	WebhookReplayErr    = 110007
 )
 
 var AuthErrorMsgMap = gerror.CodeMsgMap{
@@ -44,4 +47,7 @@ var AuthErrorMsgMap = gerror.CodeMsgMap{
 	TokenInvalidErr:     "invalid token",
 	TokenExpiredErr:     "token expired",
 	PermissionDeniedErr: "permission denied",
+	LoginLockedErr:      "too many failed login attempts, please try again later",
+	WebhookSignatureErr: "invalid webhook signature",
+	WebhookReplayErr:    "webhook request expired or replayed",
 }
@@ -7,6 +7,7 @@ const (
 	KeyUserID    = "userID"
 	KeyUserType  = "userType"
 	KeyTenantID  = "tenantID"
+	KeyTenant    = "tenant"
 	KeyDeptID    = "deptID"
 	KeyOrgID     = "orgID"
 	KeyAuthToken = "authToken"
@@ -0,0 +1,105 @@
+package gincontext
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/gerror"
+)
+
+// sniffLen MIME 嗅探读取的字节数，与 http.DetectContentType 一致
+const sniffLen = 512
+
+// UploadFile 通过大小和 MIME 校验的上传文件
+type UploadFile struct {
+	Field    string                // 表单字段名
+	Header   *multipart.FileHeader // 原始文件头
+	MIMEType string                // 根据文件内容嗅探出的 MIME 类型
+}
+
+// Open 打开上传文件
+func (f *UploadFile) Open() (multipart.File, error) {
+	return f.Header.Open()
+}
+
+// BindMultipart 将 multipart 表单字段绑定到 T，并校验表单中的所有文件。
+// maxFileSize 为单个文件的最大字节数，<= 0 时不限制；
+// allowedMIME 为允许的 MIME 类型，支持 "image/*" 形式的通配，为空时不限制。
+// MIME 类型根据文件内容嗅探，不信任客户端上传的 Content-Type。
+// 校验失败返回 gconstant.ParamInvalidErr 错误码的 gerror.Error。
+func BindMultipart[T any](ctx *gin.Context, maxFileSize int64, allowedMIME ...string) (*T, []*UploadFile, error) {
+	var obj T
+	if err := ctx.ShouldBindWith(&obj, binding.FormMultipart); err != nil {
+		return nil, nil, paramInvalidError("invalid form: " + err.Error())
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return nil, nil, paramInvalidError("invalid multipart form: " + err.Error())
+	}
+
+	var files []*UploadFile
+	for field, headers := range form.File {
+		for _, header := range headers {
+			file, err := validateUploadFile(field, header, maxFileSize, allowedMIME)
+			if err != nil {
+				return nil, nil, err
+			}
+			files = append(files, file)
+		}
+	}
+	return &obj, files, nil
+}
+
+func validateUploadFile(field string, header *multipart.FileHeader, maxFileSize int64, allowedMIME []string) (*UploadFile, error) {
+	if maxFileSize > 0 && header.Size > maxFileSize {
+		return nil, paramInvalidError(fmt.Sprintf("file %s exceeds max size %d bytes", header.Filename, maxFileSize))
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open file %s: %w", header.Filename, err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("read file %s: %w", header.Filename, err)
+	}
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+
+	if len(allowedMIME) > 0 && !matchMIME(mimeType, allowedMIME) {
+		return nil, paramInvalidError(fmt.Sprintf("file %s type %s is not allowed", header.Filename, mimeType))
+	}
+
+	return &UploadFile{
+		Field:    field,
+		Header:   header,
+		MIMEType: mimeType,
+	}, nil
+}
+
+// matchMIME 判断 MIME 类型是否在允许列表中，支持 "image/*" 形式的通配
+func matchMIME(mimeType string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == mimeType || pattern == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(mimeType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func paramInvalidError(msg string) error {
+	return gerror.Error{Code: gconstant.ParamInvalidErr, Msg: msg}
+}
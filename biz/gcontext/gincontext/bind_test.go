@@ -0,0 +1,137 @@
+package gincontext
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	pngData  = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	textData = []byte("plain text content")
+)
+
+type uploadForm struct {
+	Title string `form:"title" binding:"required"`
+}
+
+type multipartFile struct {
+	field       string
+	filename    string
+	contentType string
+	data        []byte
+}
+
+// newMultipartContext 构造 multipart 请求，contentType 为客户端声明的文件类型
+func newMultipartContext(t *testing.T, fields map[string]string, files ...multipartFile) *gin.Context {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for k, v := range fields {
+		require.NoError(t, writer.WriteField(k, v))
+	}
+	for _, f := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="`+f.field+`"; filename="`+f.filename+`"`)
+		header.Set("Content-Type", f.contentType)
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		_, err = part.Write(f.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/upload", &body)
+	ctx.Request.Header.Set("Content-Type", writer.FormDataContentType())
+	return ctx
+}
+
+func TestBindMultipart(t *testing.T) {
+	ctx := newMultipartContext(t, map[string]string{"title": "avatar"},
+		multipartFile{field: "avatar", filename: "a.png", contentType: "image/png", data: pngData},
+	)
+	form, files, err := BindMultipart[uploadForm](ctx, 1024, "image/*")
+	require.NoError(t, err)
+	assert.Equal(t, "avatar", form.Title)
+	require.Len(t, files, 1)
+	assert.Equal(t, "avatar", files[0].Field)
+	assert.Equal(t, "image/png", files[0].MIMEType)
+	assert.Equal(t, "a.png", files[0].Header.Filename)
+
+	f, err := files[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	var content bytes.Buffer
+	_, err = content.ReadFrom(f)
+	require.NoError(t, err)
+	assert.Equal(t, pngData, content.Bytes())
+}
+
+func TestBindMultipartSniffsType(t *testing.T) {
+	ctx := newMultipartContext(t, map[string]string{"title": "doc"},
+		multipartFile{field: "file", filename: "a.png", contentType: "image/png", data: textData},
+	)
+	_, files, err := BindMultipart[uploadForm](ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "text/plain", files[0].MIMEType, "client Content-Type should be ignored")
+}
+
+func TestBindMultipartRejects(t *testing.T) {
+	tests := []struct {
+		name        string
+		fields      map[string]string
+		file        multipartFile
+		maxFileSize int64
+		allowed     []string
+	}{
+		{
+			name:    "spoofed content type",
+			fields:  map[string]string{"title": "avatar"},
+			file:    multipartFile{field: "avatar", filename: "a.png", contentType: "image/png", data: textData},
+			allowed: []string{"image/*"},
+		},
+		{
+			name:    "type not allowed",
+			fields:  map[string]string{"title": "avatar"},
+			file:    multipartFile{field: "avatar", filename: "a.png", contentType: "image/png", data: pngData},
+			allowed: []string{"image/jpeg", "application/pdf"},
+		},
+		{
+			name:        "too large",
+			fields:      map[string]string{"title": "avatar"},
+			file:        multipartFile{field: "avatar", filename: "a.png", contentType: "image/png", data: pngData},
+			maxFileSize: int64(len(pngData)) - 1,
+		},
+		{
+			name: "missing required field",
+			file: multipartFile{field: "avatar", filename: "a.png", contentType: "image/png", data: pngData},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newMultipartContext(t, tt.fields, tt.file)
+			form, files, err := BindMultipart[uploadForm](ctx, tt.maxFileSize, tt.allowed...)
+			assertParamInvalid(t, err)
+			assert.Nil(t, form)
+			assert.Nil(t, files)
+		})
+	}
+}
+
+func TestMatchMIME(t *testing.T) {
+	assert.True(t, matchMIME("image/png", []string{"image/png"}))
+	assert.True(t, matchMIME("image/png", []string{"image/*"}))
+	assert.True(t, matchMIME("text/plain", []string{"*/*"}))
+	assert.False(t, matchMIME("text/plain", []string{"image/*"}))
+	assert.False(t, matchMIME("imagex/png", []string{"image/*"}))
+}
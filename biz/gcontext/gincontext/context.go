@@ -3,6 +3,7 @@ package gincontext
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/morehao/golib/biz/gcontext"
+	"github.com/morehao/golib/biz/gobject"
 )
 
 func GetClientIP(ctx *gin.Context) string {
@@ -29,6 +30,11 @@ func GetTenantID(ctx *gin.Context) uint {
 	return ctx.GetUint(gcontext.KeyTenantID)
 }
 
+func GetTenant(ctx *gin.Context) *gobject.Tenant {
+	tenant, _ := gcontext.GetTenant(ctx)
+	return tenant
+}
+
 func GetDeptID(ctx *gin.Context) uint {
 	return ctx.GetUint(gcontext.KeyDeptID)
 }
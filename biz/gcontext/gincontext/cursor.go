@@ -0,0 +1,102 @@
+package gincontext
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gobject"
+	"github.com/morehao/golib/gcrypto"
+)
+
+// minCursorKeyLen 游标签名密钥最小长度
+const minCursorKeyLen = 16
+
+// cursorLabel 签名内容前缀，避免同一密钥用于其他 HMAC 场景时签名可互换
+const cursorLabel = "gincontext-cursor-v1:"
+
+// cursorSumLen 游标中 HMAC-SHA256 签名的字节数
+const cursorSumLen = 32
+
+// CursorCodec 游标编解码器，将排序键等位置信息编码为不透明的 base64 游标，附带 HMAC-SHA256 签名防止客户端篡改
+type CursorCodec struct {
+	key []byte
+}
+
+// NewCursorCodec 创建游标编解码器，key 至少16字节，多实例部署需使用相同的密钥
+func NewCursorCodec(key []byte) (*CursorCodec, error) {
+	if len(key) < minCursorKeyLen {
+		return nil, fmt.Errorf("cursor key must be at least %d bytes", minCursorKeyLen)
+	}
+	return &CursorCodec{key: append([]byte(nil), key...)}, nil
+}
+
+// Encode 将位置信息按 JSON 序列化并签名，返回 URL 安全的 base64 游标
+func (c *CursorCodec) Encode(position any) (string, error) {
+	payload, err := json.Marshal(position)
+	if err != nil {
+		return "", fmt.Errorf("marshal cursor: %w", err)
+	}
+	data := append(payload, c.sign(payload)...)
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decode 校验游标签名并将位置信息反序列化到 position，游标为空时返回 false 表示查询第一页。
+// 游标格式错误或签名不匹配时返回 gconstant.ParamInvalidErr 错误码的 gerror.Error
+func (c *CursorCodec) Decode(cursor string, position any) (bool, error) {
+	if cursor == "" {
+		return false, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(data) <= cursorSumLen {
+		return false, paramInvalidError("invalid cursor")
+	}
+	payload, sum := data[:len(data)-cursorSumLen], data[len(data)-cursorSumLen:]
+	if !gcrypto.VerifyHMACSHA256(c.key, c.signed(payload), sum) {
+		return false, paramInvalidError("invalid cursor")
+	}
+	if err := json.Unmarshal(payload, position); err != nil {
+		return false, paramInvalidError("invalid cursor")
+	}
+	return true, nil
+}
+
+// NewCursorResult 根据查询结果构造游标分页结果。list 需多查询一条用于判断是否有下一页：
+// 长度超过 pageSize 时截断到 pageSize，并以最后一条数据的 position 生成 NextCursor
+func NewCursorResult[T any](c *CursorCodec, list []T, pageSize int, position func(last T) any) (*gobject.CursorResult[T], error) {
+	result := &gobject.CursorResult[T]{List: list}
+	if pageSize <= 0 || len(list) <= pageSize {
+		return result, nil
+	}
+	result.List = list[:pageSize]
+	cursor, err := c.Encode(position(result.List[pageSize-1]))
+	if err != nil {
+		return nil, err
+	}
+	result.NextCursor, result.HasMore = cursor, true
+	return result, nil
+}
+
+// BindCursorQuery 绑定游标分页查询参数并解码游标到 position，返回的 bool 为 false 时表示查询第一页。
+// 参数绑定失败或游标无效时返回 gconstant.ParamInvalidErr 错误码的 gerror.Error
+func BindCursorQuery(ctx *gin.Context, c *CursorCodec, position any) (*gobject.CursorQuery, bool, error) {
+	var query gobject.CursorQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		return nil, false, paramInvalidError("invalid cursor query: " + err.Error())
+	}
+	ok, err := c.Decode(query.Cursor, position)
+	if err != nil {
+		return nil, false, err
+	}
+	return &query, ok, nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	return gcrypto.HMACSHA256(c.key, c.signed(payload))
+}
+
+// signed 返回参与签名的内容
+func (c *CursorCodec) signed(payload []byte) []byte {
+	return append([]byte(cursorLabel), payload...)
+}
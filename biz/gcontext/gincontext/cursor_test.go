@@ -0,0 +1,146 @@
+package gincontext
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/gerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPosition struct {
+	ID        uint64 `json:"id"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+func newTestCursorCodec(t *testing.T, key string) *CursorCodec {
+	t.Helper()
+	codec, err := NewCursorCodec([]byte(key))
+	require.NoError(t, err)
+	return codec
+}
+
+func assertParamInvalid(t *testing.T, err error) {
+	t.Helper()
+	var gErr gerror.Error
+	require.True(t, errors.As(err, &gErr), "got %v", err)
+	assert.Equal(t, gconstant.ParamInvalidErr, gErr.Code)
+}
+
+func TestNewCursorCodec(t *testing.T) {
+	_, err := NewCursorCodec([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestCursorCodecRoundTrip(t *testing.T) {
+	codec := newTestCursorCodec(t, "0123456789abcdef")
+	want := testPosition{ID: 42, CreatedAt: 1700000000}
+
+	cursor, err := codec.Encode(want)
+	require.NoError(t, err)
+
+	var got testPosition
+	ok, err := codec.Decode(cursor, &got)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, want, got)
+
+	ok, err = codec.Decode("", &got)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCursorCodecRejectsInvalid(t *testing.T) {
+	codec := newTestCursorCodec(t, "0123456789abcdef")
+	cursor, err := codec.Encode(testPosition{ID: 42})
+	require.NoError(t, err)
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	require.NoError(t, err)
+
+	tamperedPayload := append([]byte(nil), data...)
+	tamperedPayload[len(`{"id":`)] = '9'
+	tamperedSum := append([]byte(nil), data...)
+	tamperedSum[len(tamperedSum)-1] ^= 0x01
+	otherKey, err := newTestCursorCodec(t, "fedcba9876543210").Encode(testPosition{ID: 42})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "tampered payload", cursor: base64.RawURLEncoding.EncodeToString(tamperedPayload)},
+		{name: "tampered signature", cursor: base64.RawURLEncoding.EncodeToString(tamperedSum)},
+		{name: "wrong key", cursor: otherKey},
+		{name: "malformed base64", cursor: "not*base64!"},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString(data)},
+		{name: "too short", cursor: base64.RawURLEncoding.EncodeToString(data[:cursorSumLen])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got testPosition
+			ok, err := codec.Decode(tt.cursor, &got)
+			assertParamInvalid(t, err)
+			assert.False(t, ok)
+			assert.Zero(t, got)
+		})
+	}
+}
+
+func TestNewCursorResult(t *testing.T) {
+	codec := newTestCursorCodec(t, "0123456789abcdef")
+	position := func(last testPosition) any { return last }
+	list := []testPosition{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	result, err := NewCursorResult(codec, list, 3, position)
+	require.NoError(t, err)
+	assert.Equal(t, list, result.List)
+	assert.False(t, result.HasMore)
+	assert.Empty(t, result.NextCursor)
+
+	result, err = NewCursorResult(codec, list, 2, position)
+	require.NoError(t, err)
+	assert.Equal(t, list[:2], result.List)
+	assert.True(t, result.HasMore)
+	var next testPosition
+	ok, err := codec.Decode(result.NextCursor, &next)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, testPosition{ID: 2}, next)
+}
+
+func TestBindCursorQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	codec := newTestCursorCodec(t, "0123456789abcdef")
+	cursor, err := codec.Encode(testPosition{ID: 7})
+	require.NoError(t, err)
+
+	newCtx := func(target string) *gin.Context {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		return ctx
+	}
+
+	var pos testPosition
+	query, ok, err := BindCursorQuery(newCtx("/items?pageSize=20&cursor="+cursor), codec, &pos)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 20, query.PageSize)
+	assert.Equal(t, testPosition{ID: 7}, pos)
+
+	query, ok, err = BindCursorQuery(newCtx("/items?pageSize=20"), codec, &pos)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, query.Cursor)
+
+	_, _, err = BindCursorQuery(newCtx("/items?pageSize=abc"), codec, &pos)
+	assertParamInvalid(t, err)
+
+	_, _, err = BindCursorQuery(newCtx("/items?cursor="+cursor+"x"), codec, &pos)
+	assertParamInvalid(t, err)
+}
@@ -0,0 +1,60 @@
+package gcontext
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/morehao/golib/biz/gobject"
+	"github.com/morehao/golib/gutil"
+)
+
+// WithTenant 将租户信息存入 context，供非 gin 场景（如异步任务、RPC 服务端）使用
+func WithTenant(ctx context.Context, tenant *gobject.Tenant) context.Context {
+	ctx = context.WithValue(ctx, KeyTenant, tenant)
+	return context.WithValue(ctx, KeyTenantID, tenant.ID)
+}
+
+// GetTenant 从 context 中获取租户信息，*gin.Context 同样适用
+func GetTenant(ctx context.Context) (*gobject.Tenant, bool) {
+	if NilCtx(ctx) {
+		return nil, false
+	}
+	tenant, ok := ctx.Value(KeyTenant).(*gobject.Tenant)
+	return tenant, ok && tenant != nil
+}
+
+// GetTenantID 从 context 中获取租户ID，未设置时返回 0
+func GetTenantID(ctx context.Context) uint {
+	if tenant, ok := GetTenant(ctx); ok {
+		return tenant.ID
+	}
+	if NilCtx(ctx) {
+		return 0
+	}
+	return uint(gutil.VToInt64(ctx.Value(KeyTenantID)))
+}
+
+// GetTenantSchema 获取租户的数据库 schema，用于按租户切换 schema
+func GetTenantSchema(ctx context.Context) string {
+	if tenant, ok := GetTenant(ctx); ok {
+		return tenant.Schema
+	}
+	return ""
+}
+
+// GetTenantDBName 获取租户的数据库实例名，用于按租户路由数据库连接
+func GetTenantDBName(ctx context.Context) string {
+	if tenant, ok := GetTenant(ctx); ok {
+		return tenant.DBName
+	}
+	return ""
+}
+
+// TenantCacheKey 为缓存 key 加上租户前缀（tenant:{id}:key），避免租户间缓存串用；无租户时原样返回
+func TenantCacheKey(ctx context.Context, key string) string {
+	tenantID := GetTenantID(ctx)
+	if tenantID == 0 {
+		return key
+	}
+	return fmt.Sprintf("tenant:%d:%s", tenantID, key)
+}
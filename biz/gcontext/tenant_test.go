@@ -0,0 +1,30 @@
+package gcontext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/morehao/golib/biz/gobject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenant(t *testing.T) {
+	ctx := context.Background()
+	_, ok := GetTenant(ctx)
+	assert.False(t, ok)
+	assert.Equal(t, uint(0), GetTenantID(ctx))
+	assert.Equal(t, "user:1", TenantCacheKey(ctx, "user:1"))
+
+	ctx = WithTenant(ctx, &gobject.Tenant{ID: 7, Code: "acme", Schema: "tenant_acme", DBName: "db_acme"})
+	tenant, ok := GetTenant(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant.Code)
+	assert.Equal(t, uint(7), GetTenantID(ctx))
+	assert.Equal(t, "tenant_acme", GetTenantSchema(ctx))
+	assert.Equal(t, "db_acme", GetTenantDBName(ctx))
+	assert.Equal(t, "tenant:7:user:1", TenantCacheKey(ctx, "user:1"))
+
+	// 仅有租户ID（如 JWTAuth 写入）时同样生效
+	ctx = context.WithValue(context.Background(), KeyTenantID, uint(3))
+	assert.Equal(t, uint(3), GetTenantID(ctx))
+}
@@ -25,10 +25,16 @@ type accessLogConfig struct {
 	ReqBodyMaxLen  int
 	RespBodyMaxLen int
 	ReqQueryMaxLen int
+	FieldFuncs     []AccessLogFieldFunc
+	LevelSamplers  []LogLevelSampler
 }
 
 type AccessLogOption func(*accessLogConfig)
 
+// AccessLogFieldFunc 从 gin 上下文中提取额外字段追加到访问日志，返回 key、value 交替排列的 kvs，
+// 如租户、灰度标记、AB 实验分桶等。在请求处理完成后调用，可读取 handler 中设置的上下文值
+type AccessLogFieldFunc func(ctx *gin.Context) []any
+
 func WithReqBodyMaxLen(maxLen int) AccessLogOption {
 	return func(c *accessLogConfig) {
 		c.ReqBodyMaxLen = maxLen
@@ -47,6 +53,43 @@ func WithReqQueryMaxLen(maxLen int) AccessLogOption {
 	}
 }
 
+// WithFieldFuncs 注册访问日志的额外字段提取函数，按注册顺序追加到日志字段末尾
+func WithFieldFuncs(fns ...AccessLogFieldFunc) AccessLogOption {
+	return func(c *accessLogConfig) {
+		c.FieldFuncs = append(c.FieldFuncs, fns...)
+	}
+}
+
+// LogLevelSampler 判断请求是否需要提升日志级别，ok 为 true 时该请求上下文中的日志按 level 输出，不受模块级别限制
+type LogLevelSampler func(ctx *gin.Context) (level glog.Level, ok bool)
+
+// WithLogLevelSamplers 注册请求级日志级别的采样函数，按注册顺序判断，第一个命中的生效
+func WithLogLevelSamplers(samplers ...LogLevelSampler) AccessLogOption {
+	return func(c *accessLogConfig) {
+		c.LevelSamplers = append(c.LevelSamplers, samplers...)
+	}
+}
+
+// DebugHeaderSampler 请求头 header 的值等于 token 时将该请求的日志级别提升为 Debug，token 避免外部随意开启
+func DebugHeaderSampler(header, token string) LogLevelSampler {
+	return func(ctx *gin.Context) (glog.Level, bool) {
+		if token == "" || ctx.GetHeader(header) != token {
+			return "", false
+		}
+		return glog.DebugLevel, true
+	}
+}
+
+// TraceSampledSampler 请求的 trace 被采样时将该请求的日志级别提升为 level，使详细日志与 trace 同时留存
+func TraceSampledSampler(level glog.Level) LogLevelSampler {
+	return func(ctx *gin.Context) (glog.Level, bool) {
+		if !trace.SpanContextFromContext(ctx.Request.Context()).IsSampled() {
+			return "", false
+		}
+		return level, true
+	}
+}
+
 func AccessLog(opts ...AccessLogOption) gin.HandlerFunc {
 	config := defaultConfig
 	for _, opt := range opts {
@@ -66,6 +109,19 @@ func AccessLog(opts ...AccessLogOption) gin.HandlerFunc {
 			ctx.Writer.Header().Set(glog.HeaderTraceParent, formatTraceParent(spanCtx))
 		}
 
+		// 绑定 Notice，handler 及下游通过 glog.AddNotice 追加的字段合并到访问日志
+		reqCtx, notice := glog.WithNotice(ctx.Request.Context())
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Set(glog.NoticeContextKey, notice)
+
+		for _, sampler := range config.LevelSamplers {
+			if level, ok := sampler(ctx); ok {
+				ctx.Request = ctx.Request.WithContext(glog.CtxWithLevel(ctx.Request.Context(), level))
+				ctx.Set(glog.LevelContextKey, level)
+				break
+			}
+		}
+
 		urlFull := ctx.Request.URL.String()
 		ctx.Set(gcontext.KeyUrlFull, urlFull)
 
@@ -139,6 +195,10 @@ func AccessLog(opts ...AccessLogOption) gin.HandlerFunc {
 			glog.KeyAppRequestDurationMs, glog.GetRequestCost(start, end),
 			glog.KeyAppRequestError, requestErr,
 		}
+		keysAndValues = append(keysAndValues, notice.KVs()...)
+		for _, fn := range config.FieldFuncs {
+			keysAndValues = append(keysAndValues, fn(ctx)...)
+		}
 
 		if statusCode >= 500 {
 			glog.Errorw(ctx, glog.MsgEventNotice, keysAndValues...)
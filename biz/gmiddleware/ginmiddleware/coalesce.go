@@ -0,0 +1,235 @@
+package ginmiddleware
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gcontext/gincontext"
+	"github.com/morehao/golib/gcrypto"
+	"golang.org/x/sync/singleflight"
+)
+
+// HeaderCoalesced 复用其他请求的响应时设置的响应头，值为 true
+const HeaderCoalesced = "X-Coalesced"
+
+const defaultCoalescingMaxBodySize = 1 << 20
+
+type coalescingConfig struct {
+	identityFunc func(ctx *gin.Context) string
+	maxBodySize  int
+	window       time.Duration
+}
+
+type CoalescingOption func(*coalescingConfig)
+
+// WithCoalescingIdentityFunc 设置请求身份的提取方法，身份不同的请求不会合并。
+// 默认使用租户 ID、用户 ID 及 Authorization、Cookie 请求头的摘要
+func WithCoalescingIdentityFunc(fn func(ctx *gin.Context) string) CoalescingOption {
+	return func(c *coalescingConfig) {
+		c.identityFunc = fn
+	}
+}
+
+// WithCoalescingMaxBodySize 设置可共享的响应体上限，超出时其他请求各自执行处理函数，默认 1MB
+func WithCoalescingMaxBodySize(size int) CoalescingOption {
+	return func(c *coalescingConfig) {
+		c.maxBodySize = size
+	}
+}
+
+// WithCoalescingWindow 设置响应完成后的复用时长，窗口内到达的相同请求直接复用响应，默认 0 表示只合并并发请求
+func WithCoalescingWindow(window time.Duration) CoalescingOption {
+	return func(c *coalescingConfig) {
+		c.window = window
+	}
+}
+
+// coalescedResponse 可共享的响应，headers 只包含处理函数设置的响应头
+type coalescedResponse struct {
+	shareable bool
+	status    int
+	headers   http.Header
+	body      []byte
+	panicVal  any
+}
+
+type coalescedEntry struct {
+	resp     *coalescedResponse
+	expireAt time.Time
+}
+
+// RequestCoalescing 请求合并中间件，用于开销较大的只读接口。路由、路径、查询参数和身份相同的并发 GET 请求只执行一次处理函数，
+// 其余请求等待并复用其响应。只共享 2xx、未设置 Cookie 且不超过大小上限的响应，不满足条件时等待的请求各自执行处理函数。
+// 需注册在认证中间件之后以获取用户身份，不适用于流式响应
+func RequestCoalescing(opts ...CoalescingOption) gin.HandlerFunc {
+	cfg := &coalescingConfig{
+		identityFunc: defaultCoalescingIdentity,
+		maxBodySize:  defaultCoalescingMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var (
+		group  singleflight.Group
+		mu     sync.Mutex
+		recent = make(map[string]coalescedEntry)
+	)
+	getRecent := func(key string) *coalescedResponse {
+		mu.Lock()
+		defer mu.Unlock()
+		entry, ok := recent[key]
+		if !ok {
+			return nil
+		}
+		if time.Now().After(entry.expireAt) {
+			delete(recent, key)
+			return nil
+		}
+		return entry.resp
+	}
+	setRecent := func(key string, resp *coalescedResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		now := time.Now()
+		// 写入时顺带清理过期的响应，避免低频的 key 长期占用内存
+		for k, entry := range recent {
+			if now.After(entry.expireAt) {
+				delete(recent, k)
+			}
+		}
+		recent[key] = coalescedEntry{resp: resp, expireAt: now.Add(cfg.window)}
+	}
+
+	return func(ctx *gin.Context) {
+		if !isCoalescableRequest(ctx.Request) {
+			ctx.Next()
+			return
+		}
+		key := coalescingKey(ctx, cfg.identityFunc(ctx))
+		if cfg.window > 0 {
+			if resp := getRecent(key); resp != nil {
+				replayCoalescedResponse(ctx, resp)
+				return
+			}
+		}
+
+		var executed bool
+		value, _, _ := group.Do(key, func() (any, error) {
+			executed = true
+			return executeCoalescedHandler(ctx, cfg.maxBodySize), nil
+		})
+		resp := value.(*coalescedResponse)
+		if executed {
+			if resp.panicVal != nil {
+				panic(resp.panicVal)
+			}
+			if cfg.window > 0 && resp.shareable {
+				setRecent(key, resp)
+			}
+			return
+		}
+		if !resp.shareable {
+			ctx.Next()
+			return
+		}
+		replayCoalescedResponse(ctx, resp)
+	}
+}
+
+// isCoalescableRequest 只合并 GET 请求，客户端要求不使用缓存时不合并
+func isCoalescableRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	cacheControl := strings.ToLower(req.Header.Get("Cache-Control"))
+	return !strings.Contains(cacheControl, "no-cache") && !strings.Contains(cacheControl, "no-store")
+}
+
+func defaultCoalescingIdentity(ctx *gin.Context) string {
+	credential := ctx.GetHeader(AuthHeaderKey) + "\n" + ctx.GetHeader("Cookie")
+	return strconv.FormatUint(uint64(gincontext.GetTenantID(ctx)), 10) + ":" +
+		strconv.FormatUint(uint64(gincontext.GetUserID(ctx)), 10) + ":" + gcrypto.SHA256Hash(credential)
+}
+
+// coalescingKey 由路由、路径、按参数名排序的查询参数和身份组成
+func coalescingKey(ctx *gin.Context, identity string) string {
+	return strings.Join([]string{ctx.FullPath(), ctx.Request.URL.Path, ctx.Request.URL.Query().Encode(), identity}, "\n")
+}
+
+// executeCoalescedHandler 执行处理函数并记录响应，响应同时正常写给当前请求
+func executeCoalescedHandler(ctx *gin.Context, maxBodySize int) (resp *coalescedResponse) {
+	resp = &coalescedResponse{}
+	before := ctx.Writer.Header().Clone()
+	writer := &coalescingWriter{ResponseWriter: ctx.Writer, maxBodySize: maxBodySize}
+	ctx.Writer = writer
+	defer func() {
+		ctx.Writer = writer.ResponseWriter
+		// panic 交由当前请求的 recovery 中间件处理，等待的请求各自执行处理函数
+		if r := recover(); r != nil {
+			resp.shareable = false
+			resp.panicVal = r
+		}
+	}()
+
+	ctx.Next()
+
+	resp.status = writer.Status()
+	resp.body = writer.body.Bytes()
+	resp.headers = make(http.Header)
+	for k, values := range writer.Header() {
+		if !slices.Equal(before[k], values) {
+			resp.headers[k] = append([]string(nil), values...)
+		}
+	}
+	resp.shareable = !writer.overflow && resp.status >= http.StatusOK && resp.status < http.StatusMultipleChoices &&
+		len(resp.headers.Values("Set-Cookie")) == 0 && ctx.Request.Context().Err() == nil
+	return resp
+}
+
+func replayCoalescedResponse(ctx *gin.Context, resp *coalescedResponse) {
+	header := ctx.Writer.Header()
+	for k, values := range resp.headers {
+		header[k] = append([]string(nil), values...)
+	}
+	header.Set(HeaderCoalesced, "true")
+	ctx.Writer.WriteHeader(resp.status)
+	_, _ = ctx.Writer.Write(resp.body)
+	ctx.Abort()
+}
+
+// coalescingWriter 记录响应体，超出上限后停止记录，响应仍正常写出
+type coalescingWriter struct {
+	gin.ResponseWriter
+	body        bytes.Buffer
+	maxBodySize int
+	overflow    bool
+}
+
+func (w *coalescingWriter) Write(b []byte) (int, error) {
+	w.record(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *coalescingWriter) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *coalescingWriter) record(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(b) > w.maxBodySize {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
@@ -0,0 +1,194 @@
+package ginmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHandler 第一次调用阻塞到 release 关闭，用于让并发请求在处理函数执行期间到达
+type blockingHandler struct {
+	calls   atomic.Int32
+	release chan struct{}
+	respond func(ctx *gin.Context, call int32)
+}
+
+func newBlockingHandler(respond func(ctx *gin.Context, call int32)) *blockingHandler {
+	return &blockingHandler{release: make(chan struct{}), respond: respond}
+}
+
+func (h *blockingHandler) handle(ctx *gin.Context) {
+	call := h.calls.Add(1)
+	if call == 1 {
+		<-h.release
+	}
+	h.respond(ctx, call)
+}
+
+// serveConcurrently 并发发出 n 个相同的 GET 请求，第一个请求进入处理函数后再放行
+func serveConcurrently(t *testing.T, engine *gin.Engine, h *blockingHandler, n int) []*httptest.ResponseRecorder {
+	t.Helper()
+	recorders := make([]*httptest.ResponseRecorder, n)
+	var wg sync.WaitGroup
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(w *httptest.ResponseRecorder) {
+			defer wg.Done()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items?b=2&a=1", nil))
+		}(recorders[i])
+	}
+	require.Eventually(t, func() bool { return h.calls.Load() >= 1 }, time.Second, time.Millisecond)
+	// 等待其余请求进入 singleflight 后再放行第一个请求
+	time.Sleep(50 * time.Millisecond)
+	close(h.release)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("requests did not complete")
+	}
+	return recorders
+}
+
+func newCoalescingEngine(h *blockingHandler, opts ...CoalescingOption) *gin.Engine {
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(ctx *gin.Context, _ any) {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	engine.GET("/items", RequestCoalescing(opts...), h.handle)
+	return engine
+}
+
+func TestRequestCoalescingShared(t *testing.T) {
+	h := newBlockingHandler(func(ctx *gin.Context, call int32) {
+		ctx.Header("X-Call", "1")
+		ctx.String(http.StatusOK, "items")
+	})
+	recorders := serveConcurrently(t, newCoalescingEngine(h), h, 5)
+
+	assert.EqualValues(t, 1, h.calls.Load())
+	coalesced := 0
+	for _, w := range recorders {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "items", w.Body.String())
+		assert.Equal(t, "1", w.Header().Get("X-Call"))
+		if w.Header().Get(HeaderCoalesced) == "true" {
+			coalesced++
+		}
+	}
+	assert.Equal(t, 4, coalesced)
+}
+
+func TestRequestCoalescingNotShareable(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(ctx *gin.Context)
+	}{
+		{name: "non-2xx", respond: func(ctx *gin.Context) {
+			ctx.String(http.StatusInternalServerError, "err")
+		}},
+		{name: "set-cookie", respond: func(ctx *gin.Context) {
+			ctx.SetCookie("session", "s1", 60, "/", "", false, true)
+			ctx.String(http.StatusOK, "items")
+		}},
+		{name: "body too large", respond: func(ctx *gin.Context) {
+			ctx.String(http.StatusOK, "0123456789")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newBlockingHandler(func(ctx *gin.Context, call int32) {
+				tt.respond(ctx)
+			})
+			engine := newCoalescingEngine(h, WithCoalescingMaxBodySize(8), WithCoalescingWindow(time.Minute))
+			recorders := serveConcurrently(t, engine, h, 3)
+
+			assert.EqualValues(t, 3, h.calls.Load(), "each request should run the handler itself")
+			for _, w := range recorders {
+				assert.Empty(t, w.Header().Get(HeaderCoalesced))
+			}
+
+			// 不可共享的响应也不进入复用窗口
+			engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items?a=1&b=2", nil))
+			assert.EqualValues(t, 4, h.calls.Load())
+		})
+	}
+}
+
+func TestRequestCoalescingWindow(t *testing.T) {
+	var calls atomic.Int32
+	engine := gin.New()
+	engine.GET("/items", RequestCoalescing(WithCoalescingWindow(50*time.Millisecond)), func(ctx *gin.Context) {
+		calls.Add(1)
+		ctx.String(http.StatusOK, "items")
+	})
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	assert.Empty(t, serve("/items?a=1&b=2").Header().Get(HeaderCoalesced))
+	// 查询参数顺序不同视为相同请求
+	w := serve("/items?b=2&a=1")
+	assert.Equal(t, "true", w.Header().Get(HeaderCoalesced))
+	assert.Equal(t, "items", w.Body.String())
+	assert.EqualValues(t, 1, calls.Load())
+
+	req := httptest.NewRequest(http.MethodGet, "/items?a=1&b=2", nil)
+	req.Header.Set("Cache-Control", "no-cache")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+	assert.EqualValues(t, 2, calls.Load(), "no-cache should bypass the window")
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Empty(t, serve("/items?a=1&b=2").Header().Get(HeaderCoalesced))
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestRequestCoalescingIdentity(t *testing.T) {
+	var calls atomic.Int32
+	engine := gin.New()
+	engine.GET("/items", RequestCoalescing(WithCoalescingWindow(time.Minute)), func(ctx *gin.Context) {
+		calls.Add(1)
+		ctx.String(http.StatusOK, ctx.GetHeader(AuthHeaderKey))
+	})
+	for _, token := range []string{"alice", "bob"} {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set(AuthHeaderKey, token)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, token, w.Body.String())
+	}
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestRequestCoalescingLeaderPanic(t *testing.T) {
+	h := newBlockingHandler(func(ctx *gin.Context, call int32) {
+		if call == 1 {
+			panic("boom")
+		}
+		ctx.String(http.StatusOK, "items")
+	})
+	recorders := serveConcurrently(t, newCoalescingEngine(h), h, 3)
+
+	assert.EqualValues(t, 3, h.calls.Load())
+	statuses := map[int]int{}
+	for _, w := range recorders {
+		statuses[w.Code]++
+		assert.Empty(t, w.Header().Get(HeaderCoalesced))
+	}
+	assert.Equal(t, map[int]int{http.StatusInternalServerError: 1, http.StatusOK: 2}, statuses)
+}
@@ -0,0 +1,90 @@
+package ginmiddleware
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gcontext/gincontext"
+	"github.com/morehao/golib/gauth/loginguard"
+	"github.com/morehao/golib/glog"
+)
+
+const keyLoginResult = "loginguard.result"
+
+type loginResult struct {
+	account string
+	success bool
+}
+
+type loginGuardConfig struct {
+	accountFunc func(ctx *gin.Context) string
+}
+
+type LoginGuardOption func(*loginGuardConfig)
+
+// WithLoginAccountFunc 设置登录前获取账号的方法，用于检查账号是否锁定。
+// 读取请求体时应使用 ShouldBindBodyWith，避免后续处理函数无法再次读取
+func WithLoginAccountFunc(fn func(ctx *gin.Context) string) LoginGuardOption {
+	return func(c *loginGuardConfig) {
+		c.accountFunc = fn
+	}
+}
+
+// LoginGuard 登录防爆破中间件，登录前检查账号和客户端 IP 是否锁定，锁定时返回 gconstant.LoginLockedErr 并设置 Retry-After 响应头。
+// 处理函数通过 SetLoginResult 上报登录结果，中间件据此记录失败次数或清除计数，未上报时不做记录
+func LoginGuard(guard *loginguard.Guard, opts ...LoginGuardOption) gin.HandlerFunc {
+	cfg := &loginGuardConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx *gin.Context) {
+		ip := ctx.ClientIP()
+		var account string
+		if cfg.accountFunc != nil {
+			account = cfg.accountFunc(ctx)
+		}
+		if err := guard.Check(ctx, account, ip); err != nil {
+			var lockedErr *loginguard.LockedError
+			if errors.As(err, &lockedErr) {
+				retryAfter := int(math.Ceil(lockedErr.RetryAfter.Seconds()))
+				ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+				gincontext.Abort(ctx, err)
+				return
+			}
+			// 存储不可用时放行，避免防爆破组件故障导致无法登录
+			glog.Errorf(ctx, "login guard check failed, err: %v", err)
+		}
+
+		ctx.Next()
+
+		value, ok := ctx.Get(keyLoginResult)
+		if !ok {
+			return
+		}
+		result := value.(loginResult)
+		if result.account == "" {
+			result.account = account
+		}
+		if result.success {
+			if err := guard.Succeed(ctx, result.account); err != nil {
+				glog.Errorf(ctx, "login guard reset failed, account: %s, err: %v", result.account, err)
+			}
+			return
+		}
+		// 响应已由处理函数写出，此处仅记录失败次数
+		if err := guard.Fail(ctx, result.account, ip); err != nil {
+			var lockedErr *loginguard.LockedError
+			if !errors.As(err, &lockedErr) {
+				glog.Errorf(ctx, "login guard record failed, account: %s, err: %v", result.account, err)
+			}
+		}
+	}
+}
+
+// SetLoginResult 在登录处理函数中上报登录结果，account 为空时使用 WithLoginAccountFunc 获取的账号
+func SetLoginResult(ctx *gin.Context, account string, success bool) {
+	ctx.Set(keyLoginResult, loginResult{account: account, success: success})
+}
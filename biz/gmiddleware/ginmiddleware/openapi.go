@@ -0,0 +1,774 @@
+package ginmiddleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-openapi/spec"
+	"github.com/morehao/golib/biz/gcontext/gincontext"
+	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/gutil"
+	"github.com/swaggo/swag"
+)
+
+// OpenAPI 契约不一致的位置
+const (
+	OpenAPIInPath     = "path"
+	OpenAPIInQuery    = "query"
+	OpenAPIInHeader   = "header"
+	OpenAPIInFormData = "formData"
+	OpenAPIInBody     = "body"
+	OpenAPIInRoute    = "route"    // 路由或请求方法未在文档中声明
+	OpenAPIInResponse = "response" // 响应状态码或响应体
+)
+
+const (
+	// openAPIMaxSchemaDepth schema 引用的最大展开深度，避免循环引用
+	openAPIMaxSchemaDepth = 32
+	// openAPIMaxMultipartMemory 解析 multipart 表单时的内存上限，与 gin 默认的 MaxMultipartMemory 一致
+	openAPIMaxMultipartMemory = 32 << 20
+)
+
+// OpenAPIViolation 请求或响应与 OpenAPI 文档不一致之处
+type OpenAPIViolation struct {
+	Route   string // 文档中的接口，如 GET /user/{id}
+	In      string // 不一致的位置，取值见 OpenAPIIn 开头的常量
+	Field   string // 参数名或 JSON 路径，如 items[0].name，为空表示整体
+	Message string
+}
+
+func (v OpenAPIViolation) String() string {
+	if v.Field == "" {
+		return fmt.Sprintf("%s %s: %s", v.Route, v.In, v.Message)
+	}
+	return fmt.Sprintf("%s %s.%s: %s", v.Route, v.In, v.Field, v.Message)
+}
+
+// OpenAPIViolationHandler 处理一次请求中发现的所有不一致，请求阶段和响应阶段分别调用
+type OpenAPIViolationHandler func(ctx *gin.Context, violations []OpenAPIViolation)
+
+type openAPIConfig struct {
+	instanceName     string
+	doc              []byte
+	validateResponse bool
+	enableInRelease  bool
+	skipPaths        []string
+	violationHandler OpenAPIViolationHandler
+}
+
+type OpenAPIOption func(*openAPIConfig)
+
+// WithOpenAPIInstanceName 设置 swag 文档的实例名，与 gindocs.Register 的 appName 一致，默认 swag.Name
+func WithOpenAPIInstanceName(name string) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.instanceName = name
+	}
+}
+
+// WithOpenAPIDoc 直接指定 Swagger 2.0 JSON 文档，设置后不再从 swag 读取
+func WithOpenAPIDoc(doc []byte) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.doc = doc
+	}
+}
+
+// WithOpenAPIValidateResponse 是否校验响应状态码和 JSON 响应体，需要缓存响应体，默认关闭
+func WithOpenAPIValidateResponse(validate bool) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.validateResponse = validate
+	}
+}
+
+// WithOpenAPIEnableInRelease 是否在 gin.ReleaseMode 下启用，默认关闭，生产环境不产生额外开销
+func WithOpenAPIEnableInRelease(enable bool) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.enableInRelease = enable
+	}
+}
+
+// WithOpenAPISkipPaths 跳过校验的路径前缀，如健康检查、文档路由
+func WithOpenAPISkipPaths(paths ...string) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.skipPaths = append(c.skipPaths, paths...)
+	}
+}
+
+// WithOpenAPIViolationHandler 设置不一致的处理方法，默认每条以 Warn 级别记录日志
+func WithOpenAPIViolationHandler(handler OpenAPIViolationHandler) OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.violationHandler = handler
+	}
+}
+
+// OpenAPIValidator 返回按 swag 生成的 Swagger 文档校验请求参数、请求体及（可选）响应的中间件，
+// 只记录不一致，不拦截请求，用于发现接口注释与 handler 实现之间的偏差。
+// 文档在创建时读取，文档无法读取、解析或包含不合法的 pattern 时返回错误；gin.ReleaseMode 下默认不启用。
+// 未在文档中声明的路由每个只报告一次
+//
+// 使用示例:
+//
+//	gindocs.Register(group, appName)
+//	validator, err := ginmiddleware.OpenAPIValidator(
+//	    ginmiddleware.WithOpenAPIInstanceName(appName),
+//	    ginmiddleware.WithOpenAPIValidateResponse(true),
+//	    ginmiddleware.WithOpenAPISkipPaths("/v1/app/docs", "/v1/app/redocs"),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	group.Use(validator)
+func OpenAPIValidator(opts ...OpenAPIOption) (gin.HandlerFunc, error) {
+	config := openAPIConfig{
+		instanceName:     swag.Name,
+		violationHandler: logOpenAPIViolations,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if gin.Mode() == gin.ReleaseMode && !config.enableInRelease {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}, nil
+	}
+
+	validator, err := newOpenAPIValidator(config)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx *gin.Context) {
+		if isSkippedPath(ctx.Request.URL.Path, config.skipPaths) || ctx.FullPath() == "" {
+			ctx.Next()
+			return
+		}
+
+		route, pathItem, op := validator.findOperation(ctx)
+		if op == nil {
+			if _, reported := validator.undocumented.LoadOrStore(route, struct{}{}); !reported {
+				config.violationHandler(ctx, []OpenAPIViolation{{Route: route, In: OpenAPIInRoute, Message: "route is not documented"}})
+			}
+			ctx.Next()
+			return
+		}
+		if violations := validator.validateRequest(ctx, route, pathItem, op); len(violations) > 0 {
+			config.violationHandler(ctx, violations)
+		}
+		if !config.validateResponse {
+			ctx.Next()
+			return
+		}
+
+		respBodyWriter := &gincontext.RespWriter{
+			Body:           bytes.NewBufferString(""),
+			ResponseWriter: ctx.Writer,
+		}
+		ctx.Writer = respBodyWriter
+		ctx.Next()
+		if violations := validator.validateResponse(ctx, route, op, respBodyWriter.Body.Bytes()); len(violations) > 0 {
+			config.violationHandler(ctx, violations)
+		}
+	}, nil
+}
+
+func logOpenAPIViolations(ctx *gin.Context, violations []OpenAPIViolation) {
+	for _, v := range violations {
+		glog.Warnw(ctx, "openapi contract violation",
+			glog.KeyHttpRoute, v.Route,
+			"in", v.In,
+			"field", v.Field,
+			glog.KeyErrorMessage, v.Message,
+		)
+	}
+}
+
+type openAPIValidator struct {
+	config       openAPIConfig
+	doc          *spec.Swagger
+	patterns     map[string]*regexp.Regexp       // 文档中所有 pattern 预编译的结果
+	undocumented gutil.SyncMap[string, struct{}] // 已报告的未声明路由
+}
+
+// newOpenAPIValidator 读取并解析文档，预编译文档中的 pattern
+func newOpenAPIValidator(config openAPIConfig) (*openAPIValidator, error) {
+	data := config.doc
+	if len(data) == 0 {
+		doc, err := swag.ReadDoc(config.instanceName)
+		if err != nil {
+			return nil, fmt.Errorf("openapi validator read doc fail, instance: %s, err: %w", config.instanceName, err)
+		}
+		data = []byte(doc)
+	}
+	var doc spec.Swagger
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("openapi validator parse doc fail, err: %w", err)
+	}
+	v := &openAPIValidator{
+		config:   config,
+		doc:      &doc,
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	if err := v.compilePatterns(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// compilePatterns 编译参数、definitions 及请求和响应 schema 中的 pattern，引用只在被引用处展开一次
+func (v *openAPIValidator) compilePatterns() error {
+	var errs []error
+	compile := func(location, pattern string) {
+		if pattern == "" {
+			return
+		}
+		if _, ok := v.patterns[pattern]; ok {
+			return
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("openapi validator invalid pattern at %s: %w", location, err))
+			return
+		}
+		v.patterns[pattern] = re
+	}
+	var walkItems func(location string, items *spec.Items)
+	walkItems = func(location string, items *spec.Items) {
+		for depth := 0; items != nil && depth < openAPIMaxSchemaDepth; depth++ {
+			compile(location, items.Pattern)
+			location += ".items"
+			items = items.Items
+		}
+	}
+	var walkSchema func(location string, schema *spec.Schema, depth int)
+	walkSchema = func(location string, schema *spec.Schema, depth int) {
+		if schema == nil || depth > openAPIMaxSchemaDepth {
+			return
+		}
+		compile(location, schema.Pattern)
+		for name, prop := range schema.Properties {
+			walkSchema(location+".properties."+name, &prop, depth+1)
+		}
+		for i := range schema.AllOf {
+			walkSchema(fmt.Sprintf("%s.allOf[%d]", location, i), &schema.AllOf[i], depth+1)
+		}
+		if schema.Items != nil {
+			walkSchema(location+".items", schema.Items.Schema, depth+1)
+		}
+		if schema.AdditionalProperties != nil {
+			walkSchema(location+".additionalProperties", schema.AdditionalProperties.Schema, depth+1)
+		}
+	}
+	walkParam := func(location string, param *spec.Parameter) {
+		compile(location, param.Pattern)
+		walkItems(location+".items", param.Items)
+		walkSchema(location+".schema", param.Schema, 0)
+	}
+	walkResponse := func(location string, resp *spec.Response) {
+		walkSchema(location+".schema", resp.Schema, 0)
+	}
+
+	for name, def := range v.doc.Definitions {
+		walkSchema("definitions."+name, &def, 0)
+	}
+	for name, param := range v.doc.Parameters {
+		walkParam("parameters."+name, &param)
+	}
+	for name, resp := range v.doc.Responses {
+		walkResponse("responses."+name, &resp)
+	}
+	if v.doc.Paths != nil {
+		for path, pathItem := range v.doc.Paths.Paths {
+			for i := range pathItem.Parameters {
+				walkParam(fmt.Sprintf("paths.%s.parameters[%d]", path, i), &pathItem.Parameters[i])
+			}
+			ops := map[string]*spec.Operation{
+				"get": pathItem.Get, "post": pathItem.Post, "put": pathItem.Put, "delete": pathItem.Delete,
+				"patch": pathItem.Patch, "head": pathItem.Head, "options": pathItem.Options,
+			}
+			for method, op := range ops {
+				if op == nil {
+					continue
+				}
+				location := "paths." + path + "." + method
+				for i := range op.Parameters {
+					walkParam(fmt.Sprintf("%s.parameters[%d]", location, i), &op.Parameters[i])
+				}
+				if op.Responses == nil {
+					continue
+				}
+				if op.Responses.Default != nil {
+					walkResponse(location+".responses.default", op.Responses.Default)
+				}
+				for status, resp := range op.Responses.StatusCodeResponses {
+					walkResponse(fmt.Sprintf("%s.responses.%d", location, status), &resp)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// findOperation 按 gin 路由查找文档中的接口，返回的 route 形如 GET /user/{id}
+func (v *openAPIValidator) findOperation(ctx *gin.Context) (string, *spec.PathItem, *spec.Operation) {
+	doc := v.doc
+	path := ginPathToOpenAPI(ctx.FullPath())
+	if basePath := strings.TrimSuffix(doc.BasePath, "/"); basePath != "" && strings.HasPrefix(path, basePath+"/") {
+		path = strings.TrimPrefix(path, basePath)
+	}
+	route := ctx.Request.Method + " " + path
+	if doc.Paths == nil {
+		return route, nil, nil
+	}
+	pathItem, ok := doc.Paths.Paths[path]
+	if !ok {
+		return route, nil, nil
+	}
+	var op *spec.Operation
+	switch ctx.Request.Method {
+	case http.MethodGet:
+		op = pathItem.Get
+	case http.MethodPost:
+		op = pathItem.Post
+	case http.MethodPut:
+		op = pathItem.Put
+	case http.MethodDelete:
+		op = pathItem.Delete
+	case http.MethodPatch:
+		op = pathItem.Patch
+	case http.MethodHead:
+		op = pathItem.Head
+	case http.MethodOptions:
+		op = pathItem.Options
+	}
+	return route, &pathItem, op
+}
+
+// ginPathToOpenAPI 将 gin 路由中的 :id、*path 转为 {id}、{path}
+func ginPathToOpenAPI(fullPath string) string {
+	segments := strings.Split(fullPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationParams 合并路径和接口上声明的参数，接口上的同名参数优先
+func (v *openAPIValidator) operationParams(pathItem *spec.PathItem, op *spec.Operation) []spec.Parameter {
+	var params []spec.Parameter
+	index := make(map[string]int)
+	for _, list := range [][]spec.Parameter{pathItem.Parameters, op.Parameters} {
+		for _, param := range list {
+			param = v.resolveParam(param)
+			key := param.In + ":" + param.Name
+			if i, ok := index[key]; ok {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+func (v *openAPIValidator) resolveParam(param spec.Parameter) spec.Parameter {
+	ref := param.Ref.String()
+	if name, ok := strings.CutPrefix(ref, "#/parameters/"); ok {
+		if resolved, found := v.doc.Parameters[name]; found {
+			return resolved
+		}
+	}
+	return param
+}
+
+func (v *openAPIValidator) validateRequest(ctx *gin.Context, route string, pathItem *spec.PathItem, op *spec.Operation) []OpenAPIViolation {
+	var violations []OpenAPIViolation
+	add := func(in, field, message string) {
+		violations = append(violations, OpenAPIViolation{Route: route, In: in, Field: field, Message: message})
+	}
+	for _, param := range v.operationParams(pathItem, op) {
+		if param.In == OpenAPIInBody {
+			for _, violation := range v.validateRequestBody(ctx, &param) {
+				violation.Route = route
+				violations = append(violations, violation)
+			}
+			continue
+		}
+		values, present := requestParamValues(ctx, &param)
+		if !present {
+			if param.Required {
+				add(param.In, param.Name, "required parameter is missing")
+			}
+			continue
+		}
+		if param.Type == "file" {
+			continue
+		}
+		for _, message := range v.validateParamValues(&param, values) {
+			add(param.In, param.Name, message)
+		}
+	}
+	return violations
+}
+
+// requestParamValues 读取非 body 参数的值，present 为 false 表示请求中未携带
+func requestParamValues(ctx *gin.Context, param *spec.Parameter) (values []string, present bool) {
+	switch param.In {
+	case OpenAPIInPath:
+		value := ctx.Param(param.Name)
+		return []string{value}, value != ""
+	case OpenAPIInQuery:
+		values, present = ctx.Request.URL.Query()[param.Name]
+	case OpenAPIInHeader:
+		values = ctx.Request.Header.Values(param.Name)
+		present = len(values) > 0
+	case OpenAPIInFormData:
+		// 解析结果缓存在 Request 上，不影响 handler 再次绑定
+		if err := ctx.Request.ParseMultipartForm(openAPIMaxMultipartMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return nil, false
+		}
+		if param.Type == "file" {
+			form := ctx.Request.MultipartForm
+			return nil, form != nil && len(form.File[param.Name]) > 0
+		}
+		values, present = ctx.Request.PostForm[param.Name]
+	}
+	return values, present
+}
+
+// validateParamValues 按参数的类型、数组格式和约束校验参数值
+func (v *openAPIValidator) validateParamValues(param *spec.Parameter, values []string) []string {
+	if param.Type != "array" {
+		if msg := v.validateSimpleValue(values[0], param.Type, &param.CommonValidations); msg != "" {
+			return []string{msg}
+		}
+		return nil
+	}
+	items := values
+	if param.CollectionFormat != "multi" && len(values) > 0 {
+		items = splitCollection(values[0], param.CollectionFormat)
+	}
+	var messages []string
+	if msg := validateItemCount(len(items), param.MinItems, param.MaxItems); msg != "" {
+		messages = append(messages, msg)
+	}
+	if param.Items != nil {
+		for i, item := range items {
+			if msg := v.validateSimpleValue(item, param.Items.Type, &param.Items.CommonValidations); msg != "" {
+				messages = append(messages, fmt.Sprintf("[%d] %s", i, msg))
+			}
+		}
+	}
+	return messages
+}
+
+func splitCollection(value, format string) []string {
+	switch format {
+	case "ssv":
+		return strings.Split(value, " ")
+	case "tsv":
+		return strings.Split(value, "\t")
+	case "pipes":
+		return strings.Split(value, "|")
+	default:
+		return strings.Split(value, ",")
+	}
+}
+
+// validateSimpleValue 校验字符串形式的参数值，返回不一致的描述，一致时返回空字符串
+func (v *openAPIValidator) validateSimpleValue(value, typ string, validations *spec.CommonValidations) string {
+	switch typ {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Sprintf("expected integer, got %q", value)
+		}
+		if msg := validateRange(float64(n), validations); msg != "" {
+			return msg
+		}
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Sprintf("expected number, got %q", value)
+		}
+		if msg := validateRange(n, validations); msg != "" {
+			return msg
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Sprintf("expected boolean, got %q", value)
+		}
+	case "string":
+		if msg := v.validateString(value, validations); msg != "" {
+			return msg
+		}
+	}
+	return validateEnum(value, validations.Enum)
+}
+
+func validateRange(n float64, validations *spec.CommonValidations) string {
+	if minimum := validations.Minimum; minimum != nil && (n < *minimum || validations.ExclusiveMinimum && n == *minimum) {
+		return fmt.Sprintf("%v is less than minimum %v", n, *minimum)
+	}
+	if maximum := validations.Maximum; maximum != nil && (n > *maximum || validations.ExclusiveMaximum && n == *maximum) {
+		return fmt.Sprintf("%v is greater than maximum %v", n, *maximum)
+	}
+	return ""
+}
+
+func (v *openAPIValidator) validateString(s string, validations *spec.CommonValidations) string {
+	length := int64(utf8.RuneCountInString(s))
+	if validations.MinLength != nil && length < *validations.MinLength {
+		return fmt.Sprintf("length %d is less than minLength %d", length, *validations.MinLength)
+	}
+	if validations.MaxLength != nil && length > *validations.MaxLength {
+		return fmt.Sprintf("length %d is greater than maxLength %d", length, *validations.MaxLength)
+	}
+	if re, ok := v.patterns[validations.Pattern]; ok {
+		if !re.MatchString(s) {
+			return fmt.Sprintf("%q does not match pattern %s", s, validations.Pattern)
+		}
+	}
+	return ""
+}
+
+func validateItemCount(count int, minItems, maxItems *int64) string {
+	if minItems != nil && int64(count) < *minItems {
+		return fmt.Sprintf("%d items is less than minItems %d", count, *minItems)
+	}
+	if maxItems != nil && int64(count) > *maxItems {
+		return fmt.Sprintf("%d items is greater than maxItems %d", count, *maxItems)
+	}
+	return ""
+}
+
+// validateEnum 按字符串形式比较，文档中的数字枚举值解析为 float64，与参数值的字符串形式一致
+func validateEnum(value any, enum []any) string {
+	if len(enum) == 0 {
+		return ""
+	}
+	s := fmt.Sprint(value)
+	if n, ok := value.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			s = fmt.Sprint(f)
+		}
+	}
+	for _, e := range enum {
+		if fmt.Sprint(e) == s {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s is not one of %v", s, enum)
+}
+
+func (v *openAPIValidator) validateRequestBody(ctx *gin.Context, param *spec.Parameter) []OpenAPIViolation {
+	body, err := gincontext.GetReqBody(ctx)
+	if err != nil {
+		return nil
+	}
+	if strings.TrimSpace(body) == "" {
+		if param.Required {
+			return []OpenAPIViolation{{In: OpenAPIInBody, Message: "required body is missing"}}
+		}
+		return nil
+	}
+	if param.Schema == nil || !isJSONContentType(ctx.ContentType()) {
+		return nil
+	}
+	return v.validateJSON(OpenAPIInBody, param.Schema, []byte(body))
+}
+
+func (v *openAPIValidator) validateResponse(ctx *gin.Context, route string, op *spec.Operation, body []byte) []OpenAPIViolation {
+	if op.Responses == nil {
+		return nil
+	}
+	status := ctx.Writer.Status()
+	resp, ok := op.Responses.StatusCodeResponses[status]
+	if !ok {
+		if op.Responses.Default == nil {
+			return []OpenAPIViolation{{Route: route, In: OpenAPIInResponse, Message: fmt.Sprintf("status code %d is not documented", status)}}
+		}
+		resp = *op.Responses.Default
+	}
+	if name, found := strings.CutPrefix(resp.Ref.String(), "#/responses/"); found {
+		if resolved, exist := v.doc.Responses[name]; exist {
+			resp = resolved
+		}
+	}
+	if resp.Schema == nil || len(bytes.TrimSpace(body)) == 0 || !isJSONContentType(ctx.Writer.Header().Get("Content-Type")) {
+		return nil
+	}
+	violations := v.validateJSON(OpenAPIInResponse, resp.Schema, body)
+	for i := range violations {
+		violations[i].Route = route
+	}
+	return violations
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == gin.MIMEJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+func (v *openAPIValidator) validateJSON(in string, schema *spec.Schema, data []byte) []OpenAPIViolation {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return []OpenAPIViolation{{In: in, Message: fmt.Sprintf("invalid json: %v", err)}}
+	}
+	var violations []OpenAPIViolation
+	v.validateSchema(schema, value, "", 0, func(field, message string) {
+		violations = append(violations, OpenAPIViolation{In: in, Field: field, Message: message})
+	})
+	return violations
+}
+
+// validateSchema 校验 JSON 值，null 视为合法，未知的类型和格式不校验
+func (v *openAPIValidator) validateSchema(schema *spec.Schema, value any, field string, depth int, add func(field, message string)) {
+	if depth > openAPIMaxSchemaDepth {
+		return
+	}
+	if name, ok := strings.CutPrefix(schema.Ref.String(), "#/definitions/"); ok {
+		resolved, found := v.doc.Definitions[name]
+		if !found {
+			return
+		}
+		schema = &resolved
+	}
+	for i := range schema.AllOf {
+		v.validateSchema(&schema.AllOf[i], value, field, depth+1, add)
+	}
+	if value == nil {
+		return
+	}
+
+	switch {
+	case schema.Type.Contains("object"), len(schema.Type) == 0 && len(schema.Properties) > 0:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			add(field, fmt.Sprintf("expected object, got %s", jsonTypeName(value)))
+			return
+		}
+		v.validateObject(schema, obj, field, depth, add)
+	case schema.Type.Contains("array"):
+		arr, ok := value.([]any)
+		if !ok {
+			add(field, fmt.Sprintf("expected array, got %s", jsonTypeName(value)))
+			return
+		}
+		if msg := validateItemCount(len(arr), schema.MinItems, schema.MaxItems); msg != "" {
+			add(field, msg)
+		}
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for i, item := range arr {
+				v.validateSchema(schema.Items.Schema, item, fmt.Sprintf("%s[%d]", field, i), depth+1, add)
+			}
+		}
+	case schema.Type.Contains("string"):
+		s, ok := value.(string)
+		if !ok {
+			add(field, fmt.Sprintf("expected string, got %s", jsonTypeName(value)))
+			return
+		}
+		if msg := v.validateString(s, schemaValidations(schema)); msg != "" {
+			add(field, msg)
+		}
+	case schema.Type.Contains("integer"), schema.Type.Contains("number"):
+		n, ok := value.(json.Number)
+		if !ok {
+			add(field, fmt.Sprintf("expected %s, got %s", schema.Type[0], jsonTypeName(value)))
+			return
+		}
+		f, _ := n.Float64()
+		if schema.Type.Contains("integer") && f != float64(int64(f)) {
+			add(field, fmt.Sprintf("expected integer, got %s", n))
+			return
+		}
+		if msg := validateRange(f, schemaValidations(schema)); msg != "" {
+			add(field, msg)
+		}
+	case schema.Type.Contains("boolean"):
+		if _, ok := value.(bool); !ok {
+			add(field, fmt.Sprintf("expected boolean, got %s", jsonTypeName(value)))
+			return
+		}
+	}
+	if msg := validateEnum(value, schema.Enum); msg != "" {
+		add(field, msg)
+	}
+}
+
+func (v *openAPIValidator) validateObject(schema *spec.Schema, obj map[string]any, field string, depth int, add func(field, message string)) {
+	join := func(name string) string {
+		if field == "" {
+			return name
+		}
+		return field + "." + name
+	}
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			add(join(name), "required property is missing")
+		}
+	}
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if propSchema, ok := schema.Properties[name]; ok {
+			v.validateSchema(&propSchema, obj[name], join(name), depth+1, add)
+			continue
+		}
+		if additional := schema.AdditionalProperties; additional != nil {
+			if additional.Schema != nil {
+				v.validateSchema(additional.Schema, obj[name], join(name), depth+1, add)
+			} else if !additional.Allows {
+				add(join(name), "property is not documented")
+			}
+		}
+	}
+}
+
+func schemaValidations(schema *spec.Schema) *spec.CommonValidations {
+	return &spec.CommonValidations{
+		Maximum:          schema.Maximum,
+		ExclusiveMaximum: schema.ExclusiveMaximum,
+		Minimum:          schema.Minimum,
+		ExclusiveMinimum: schema.ExclusiveMinimum,
+		MaxLength:        schema.MaxLength,
+		MinLength:        schema.MinLength,
+		Pattern:          schema.Pattern,
+	}
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,193 @@
+package ginmiddleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSwagger(namePattern string) *spec.Swagger {
+	user := new(spec.Schema).Typed("object", "").WithRequired("name")
+	user.SetProperty("name", *spec.StringProperty().WithPattern(namePattern))
+	user.SetProperty("age", *spec.Int64Property().WithMinimum(0, false).WithMaximum(150, false))
+	userRef := spec.RefSchema("#/definitions/User")
+
+	getUser := spec.NewOperation("getUser").
+		AddParam(spec.PathParam("id").Typed("integer", "").WithMinimum(1, false)).
+		AddParam(spec.QueryParam("status").Typed("string", "").WithEnum("active", "disabled")).
+		AddParam(spec.QueryParam("name").Typed("string", "").WithPattern(namePattern).WithMinLength(2).WithMaxLength(5)).
+		AddParam(spec.QueryParam("tags").CollectionOf(spec.NewItems().Typed("string", ""), "csv").WithMaxItems(2)).
+		AddParam(spec.HeaderParam("X-Trace-ID").Typed("string", "").AsRequired()).
+		RespondsWith(http.StatusOK, spec.NewResponse().WithSchema(userRef))
+	putUser := spec.NewOperation("putUser").
+		AddParam(spec.PathParam("id").Typed("integer", "")).
+		AddParam(spec.BodyParam("user", userRef).AsRequired()).
+		RespondsWith(http.StatusOK, spec.NewResponse().WithSchema(userRef))
+
+	return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Swagger:  "2.0",
+		BasePath: "/api",
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/users/{id}": {PathItemProps: spec.PathItemProps{Get: getUser, Put: putUser}},
+		}},
+		Definitions: spec.Definitions{"User": *user},
+	}}
+}
+
+// newOpenAPIEngine 返回挂载校验中间件的路由及收集到的不一致，handler 原样返回请求体
+func newOpenAPIEngine(t *testing.T, opts ...OpenAPIOption) (*gin.Engine, *[]string) {
+	t.Helper()
+	doc, err := json.Marshal(newTestSwagger("^[a-z]+$"))
+	require.NoError(t, err)
+	var violations []string
+	opts = append([]OpenAPIOption{
+		WithOpenAPIDoc(doc),
+		WithOpenAPIViolationHandler(func(_ *gin.Context, vs []OpenAPIViolation) {
+			for _, v := range vs {
+				violations = append(violations, v.String())
+			}
+		}),
+	}, opts...)
+	validator, err := OpenAPIValidator(opts...)
+	require.NoError(t, err)
+
+	echo := func(ctx *gin.Context) {
+		body, _ := io.ReadAll(ctx.Request.Body)
+		status := http.StatusOK
+		if ctx.Query("fail") != "" {
+			status = http.StatusInternalServerError
+		}
+		ctx.Data(status, gin.MIMEJSON, body)
+	}
+	engine := gin.New()
+	group := engine.Group("/api", validator)
+	group.GET("/users/:id", echo)
+	group.PUT("/users/:id", echo)
+	group.POST("/users/:id", echo)
+	group.GET("/undocumented", echo)
+	return engine, &violations
+}
+
+func TestOpenAPIValidatorRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header bool
+		body   string
+		want   []string
+	}{
+		{name: "valid query", method: http.MethodGet, target: "/api/users/5?status=active&name=abc&tags=a,b", header: true},
+		{name: "missing header", method: http.MethodGet, target: "/api/users/5", want: []string{
+			"GET /users/{id} header.X-Trace-ID: required parameter is missing",
+		}},
+		{name: "path type", method: http.MethodGet, target: "/api/users/abc", header: true, want: []string{
+			`GET /users/{id} path.id: expected integer, got "abc"`,
+		}},
+		{name: "path minimum", method: http.MethodGet, target: "/api/users/0", header: true, want: []string{
+			"GET /users/{id} path.id: 0 is less than minimum 1",
+		}},
+		{name: "enum", method: http.MethodGet, target: "/api/users/1?status=deleted", header: true, want: []string{
+			"GET /users/{id} query.status: deleted is not one of [active disabled]",
+		}},
+		{name: "pattern", method: http.MethodGet, target: "/api/users/1?name=AB", header: true, want: []string{
+			`GET /users/{id} query.name: "AB" does not match pattern ^[a-z]+$`,
+		}},
+		{name: "min length", method: http.MethodGet, target: "/api/users/1?name=a", header: true, want: []string{
+			"GET /users/{id} query.name: length 1 is less than minLength 2",
+		}},
+		{name: "max length", method: http.MethodGet, target: "/api/users/1?name=abcdef", header: true, want: []string{
+			"GET /users/{id} query.name: length 6 is greater than maxLength 5",
+		}},
+		{name: "max items", method: http.MethodGet, target: "/api/users/1?tags=a,b,c", header: true, want: []string{
+			"GET /users/{id} query.tags: 3 items is greater than maxItems 2",
+		}},
+		{name: "valid body", method: http.MethodPut, target: "/api/users/1", body: `{"name":"bob","age":3}`},
+		{name: "missing body", method: http.MethodPut, target: "/api/users/1", want: []string{
+			"PUT /users/{id} body: required body is missing",
+		}},
+		{name: "invalid body", method: http.MethodPut, target: "/api/users/1", body: `{"age":151}`, want: []string{
+			"PUT /users/{id} body.name: required property is missing",
+			"PUT /users/{id} body.age: 151 is greater than maximum 150",
+		}},
+		{name: "body types", method: http.MethodPut, target: "/api/users/1", body: `{"name":"Bob","age":"3"}`, want: []string{
+			"PUT /users/{id} body.age: expected integer, got string",
+			`PUT /users/{id} body.name: "Bob" does not match pattern ^[a-z]+$`,
+		}},
+		{name: "malformed json", method: http.MethodPut, target: "/api/users/1", body: `{"name":`, want: []string{
+			"PUT /users/{id} body: invalid json: unexpected EOF",
+		}},
+		{name: "undocumented method", method: http.MethodPost, target: "/api/users/1", want: []string{
+			"POST /users/{id} route: route is not documented",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, violations := newOpenAPIEngine(t)
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", gin.MIMEJSON)
+			if tt.header {
+				req.Header.Set("X-Trace-ID", "t1")
+			}
+			engine.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.want, *violations)
+		})
+	}
+}
+
+func TestOpenAPIValidatorUndocumentedRoute(t *testing.T) {
+	engine, violations := newOpenAPIEngine(t)
+	for i := 0; i < 2; i++ {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/undocumented", nil))
+	}
+	assert.Equal(t, []string{"GET /undocumented route: route is not documented"}, *violations)
+}
+
+func TestOpenAPIValidatorResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		want   []string
+	}{
+		{name: "valid", target: "/api/users/1", body: `{"name":"bob"}`},
+		{name: "invalid body", target: "/api/users/1", body: `{"name":1}`, want: []string{
+			"PUT /users/{id} body.name: expected string, got number",
+			"PUT /users/{id} response.name: expected string, got number",
+		}},
+		{name: "undocumented status", target: "/api/users/1?fail=1", body: `{"name":"bob"}`, want: []string{
+			"PUT /users/{id} response: status code 500 is not documented",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, violations := newOpenAPIEngine(t, WithOpenAPIValidateResponse(true))
+			req := httptest.NewRequest(http.MethodPut, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", gin.MIMEJSON)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			assert.Equal(t, tt.body, w.Body.String(), "response should still reach the client")
+			assert.Equal(t, tt.want, *violations)
+		})
+	}
+}
+
+func TestOpenAPIValidatorInvalidDoc(t *testing.T) {
+	_, err := OpenAPIValidator(WithOpenAPIDoc([]byte(`{"swagger":`)))
+	assert.Error(t, err)
+
+	doc, err := json.Marshal(newTestSwagger("[a-z"))
+	require.NoError(t, err)
+	_, err = OpenAPIValidator(WithOpenAPIDoc(doc))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "definitions.User.properties.name")
+	assert.Contains(t, err.Error(), "paths./users/{id}.get.parameters[2]")
+}
@@ -0,0 +1,53 @@
+package ginmiddleware
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/biz/gcontext/gincontext"
+	"github.com/morehao/golib/gcrypto"
+	"github.com/morehao/golib/gerror"
+	"github.com/morehao/golib/glog"
+)
+
+type signedURLConfig struct {
+	skipPaths []string
+}
+
+type SignedURLOption func(*signedURLConfig)
+
+func WithSignedURLSkipPaths(paths ...string) SignedURLOption {
+	return func(c *signedURLConfig) {
+		c.skipPaths = append(c.skipPaths, paths...)
+	}
+}
+
+// SignedURL 校验 gcrypto.URLSigner 生成的签名链接，用于临时下载等无需登录的接口，
+// 签名无效或过期时返回 gconstant.ForbiddenErr
+func SignedURL(signer *gcrypto.URLSigner, opts ...SignedURLOption) gin.HandlerFunc {
+	cfg := &signedURLConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx *gin.Context) {
+		if isSkippedPath(ctx.Request.URL.Path, cfg.skipPaths) {
+			ctx.Next()
+			return
+		}
+		if err := signer.VerifyURL(ctx.Request.URL); err != nil {
+			glog.Warnf(ctx, "signed url verify failed, path: %s, err: %v", ctx.Request.URL.Path, err)
+			msg := "链接签名无效"
+			if errors.Is(err, gcrypto.ErrSignedURLExpired) {
+				msg = "链接已过期"
+			}
+			gincontext.Abort(ctx, gerror.Error{
+				Code: gconstant.ForbiddenErr,
+				Msg:  msg,
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,167 @@
+package ginmiddleware
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/biz/gcontext"
+	"github.com/morehao/golib/biz/gcontext/gincontext"
+	"github.com/morehao/golib/biz/gobject"
+	"github.com/morehao/golib/gerror"
+	"github.com/morehao/golib/glog"
+)
+
+const TenantHeaderKey = "X-Tenant-Id"
+
+// TenantResolver 从请求中解析租户，未解析到时返回 nil
+type TenantResolver func(ctx *gin.Context) *gobject.Tenant
+
+// TenantLoader 根据解析结果加载完整的租户信息（如 schema、数据库实例），返回错误时中断请求
+type TenantLoader func(ctx *gin.Context, tenant *gobject.Tenant) (*gobject.Tenant, error)
+
+type tenantConfig struct {
+	resolvers []TenantResolver
+	loader    TenantLoader
+	required  bool
+	skipPaths []string
+}
+
+type TenantOption func(*tenantConfig)
+
+// WithTenantResolvers 设置租户解析器，按顺序尝试，第一个解析成功的生效；默认只从 JWT claims 解析。
+// 请求头、子域名等客户端可控的来源需显式启用，并配合 WithTenantLoader 校验调用方属于该租户
+func WithTenantResolvers(resolvers ...TenantResolver) TenantOption {
+	return func(c *tenantConfig) {
+		c.resolvers = resolvers
+	}
+}
+
+func WithTenantLoader(loader TenantLoader) TenantOption {
+	return func(c *tenantConfig) {
+		c.loader = loader
+	}
+}
+
+// WithTenantRequired 设置是否必须解析到租户，为 true 时未解析到租户直接返回错误
+func WithTenantRequired(required bool) TenantOption {
+	return func(c *tenantConfig) {
+		c.required = required
+	}
+}
+
+func WithTenantSkipPaths(paths ...string) TenantOption {
+	return func(c *tenantConfig) {
+		c.skipPaths = append(c.skipPaths, paths...)
+	}
+}
+
+// TenantFromClaims 从 JWTAuth 写入的 claims 中解析租户ID，需在 JWTAuth 之后使用
+func TenantFromClaims() TenantResolver {
+	return func(ctx *gin.Context) *gobject.Tenant {
+		tenantID := gincontext.GetTenantID(ctx)
+		if tenantID == 0 {
+			return nil
+		}
+		return &gobject.Tenant{ID: tenantID}
+	}
+}
+
+// TenantFromHeader 从请求头解析租户，值为数字时作为租户ID，否则作为租户编码。
+// 请求头由客户端任意设置，必须配合 WithTenantLoader 校验当前用户属于该租户，否则可越权访问其他租户的数据
+func TenantFromHeader(header string) TenantResolver {
+	return func(ctx *gin.Context) *gobject.Tenant {
+		return parseTenant(ctx.GetHeader(header))
+	}
+}
+
+// TenantFromHost 从子域名解析租户编码，如 baseDomain 为 example.com 时 acme.example.com 解析为 acme
+func TenantFromHost(baseDomain string) TenantResolver {
+	suffix := "." + strings.TrimPrefix(strings.ToLower(baseDomain), ".")
+	return func(ctx *gin.Context) *gobject.Tenant {
+		host := strings.ToLower(ctx.Request.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !strings.HasSuffix(host, suffix) {
+			return nil
+		}
+		code := strings.TrimSuffix(host, suffix)
+		if code == "" || strings.Contains(code, ".") {
+			return nil
+		}
+		return &gobject.Tenant{Code: code}
+	}
+}
+
+// TenantContext 解析当前请求的租户并写入上下文：
+// gin 上下文和 request context 中写入 gobject.Tenant 和租户ID，gormplugin 的租户条件、gcontext.TenantCacheKey 等据此生效；
+// 同时写入 glog.KeyAppTenantID，日志配置的 ExtraKeys 包含该 key 时业务日志自动带上租户ID。
+func TenantContext(opts ...TenantOption) gin.HandlerFunc {
+	cfg := &tenantConfig{
+		resolvers: []TenantResolver{TenantFromClaims()},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx *gin.Context) {
+		if isSkippedPath(ctx.Request.URL.Path, cfg.skipPaths) {
+			ctx.Next()
+			return
+		}
+
+		var tenant *gobject.Tenant
+		for _, resolve := range cfg.resolvers {
+			if tenant = resolve(ctx); tenant != nil {
+				break
+			}
+		}
+		if tenant != nil && cfg.loader != nil {
+			loaded, err := cfg.loader(ctx, tenant)
+			if err != nil {
+				glog.Warnf(ctx, "tenant load failed, id: %d, code: %s, err: %v", tenant.ID, tenant.Code, err)
+				gincontext.Abort(ctx, gerror.Error{
+					Code: gconstant.ForbiddenErr,
+					Msg:  "租户不存在或已停用",
+				})
+				return
+			}
+			tenant = loaded
+		}
+		if tenant == nil {
+			if cfg.required {
+				gincontext.Abort(ctx, gerror.Error{
+					Code: gconstant.ParamInvalidErr,
+					Msg:  "缺少租户信息",
+				})
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		ctx.Set(gcontext.KeyTenant, tenant)
+		ctx.Set(gcontext.KeyTenantID, tenant.ID)
+		ctx.Set(glog.KeyAppTenantID, tenant.ID)
+		ctx.Request = ctx.Request.WithContext(gcontext.WithTenant(ctx.Request.Context(), tenant))
+
+		ctx.Next()
+	}
+}
+
+func parseTenant(value string) *gobject.Tenant {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+		if id == 0 {
+			return nil
+		}
+		return &gobject.Tenant{ID: uint(id)}
+	}
+	return &gobject.Tenant{Code: value}
+}
@@ -0,0 +1,294 @@
+package ginmiddleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/biz/gcontext/gincontext"
+	"github.com/morehao/golib/gcrypto"
+	"github.com/morehao/golib/gerror"
+	"github.com/morehao/golib/glog"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultWebhookTolerance    = 5 * time.Minute
+	defaultWebhookReplayWindow = 24 * time.Hour
+	defaultWebhookKeyPrefix    = "webhook:replay:"
+	defaultWebhookMaxBody      = 5 << 20
+)
+
+var (
+	ErrWebhookSignatureMissing = errors.New("webhook: missing signature")
+	ErrWebhookSignatureInvalid = errors.New("webhook: invalid signature")
+	ErrWebhookTimestampInvalid = errors.New("webhook: invalid timestamp")
+)
+
+// WebhookDelivery 签名校验通过的回调请求信息
+type WebhookDelivery struct {
+	// ID 回调请求的唯一标识，用于防重放，为空时不做重放检查
+	ID string
+	// Timestamp 签名中的时间戳，为零值时不校验时间窗口
+	Timestamp time.Time
+}
+
+// WebhookVerifier 校验回调请求签名，body 为完整的请求体
+type WebhookVerifier func(ctx *gin.Context, body []byte) (*WebhookDelivery, error)
+
+// GitHubWebhook 校验 GitHub 的 X-Hub-Signature-256 签名（sha256=hex(HMAC-SHA256(secret, body))），
+// 以 X-GitHub-Delivery 作为防重放标识
+func GitHubWebhook(secret string) WebhookVerifier {
+	return func(ctx *gin.Context, body []byte) (*WebhookDelivery, error) {
+		header := ctx.GetHeader("X-Hub-Signature-256")
+		if header == "" {
+			return nil, ErrWebhookSignatureMissing
+		}
+		sum, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+		if err != nil || !strings.HasPrefix(header, "sha256=") {
+			return nil, ErrWebhookSignatureInvalid
+		}
+		if !gcrypto.VerifyHMACSHA256([]byte(secret), body, sum) {
+			return nil, ErrWebhookSignatureInvalid
+		}
+		return &WebhookDelivery{ID: ctx.GetHeader("X-GitHub-Delivery")}, nil
+	}
+}
+
+// StripeWebhook 校验 Stripe 风格的时间戳签名，header 为签名请求头名称，为空时使用 Stripe-Signature。
+// 请求头格式为 t={unix 秒},v1={hex 签名}[,v1=...]，签名为 HMAC-SHA256(secret, "{t}.{body}")，任一 v1 匹配即通过，
+// 支持轮换密钥期间同时携带多个签名
+func StripeWebhook(secret, header string) WebhookVerifier {
+	if header == "" {
+		header = "Stripe-Signature"
+	}
+	return func(ctx *gin.Context, body []byte) (*WebhookDelivery, error) {
+		value := ctx.GetHeader(header)
+		if value == "" {
+			return nil, ErrWebhookSignatureMissing
+		}
+		var timestamp string
+		var signatures []string
+		for _, part := range strings.Split(value, ",") {
+			k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch k {
+			case "t":
+				timestamp = v
+			case "v1":
+				signatures = append(signatures, v)
+			}
+		}
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return nil, ErrWebhookTimestampInvalid
+		}
+		if len(signatures) == 0 {
+			return nil, ErrWebhookSignatureMissing
+		}
+		payload := append([]byte(timestamp+"."), body...)
+		for _, signature := range signatures {
+			sum, err := hex.DecodeString(signature)
+			if err == nil && gcrypto.VerifyHMACSHA256([]byte(secret), payload, sum) {
+				return &WebhookDelivery{ID: timestamp + "." + signature, Timestamp: time.Unix(ts, 0)}, nil
+			}
+		}
+		return nil, ErrWebhookSignatureInvalid
+	}
+}
+
+// WeChatWebhook 校验微信公众号/企业微信回调 URL 上的签名，signature 为 token、timestamp、nonce 字典序排序拼接后的 SHA1，
+// 以签名作为防重放标识。仅校验 URL 签名，加密模式下的消息体解密由业务处理
+func WeChatWebhook(token string) WebhookVerifier {
+	return func(ctx *gin.Context, body []byte) (*WebhookDelivery, error) {
+		signature, timestamp, nonce := ctx.Query("signature"), ctx.Query("timestamp"), ctx.Query("nonce")
+		if signature == "" {
+			return nil, ErrWebhookSignatureMissing
+		}
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return nil, ErrWebhookTimestampInvalid
+		}
+		parts := []string{token, timestamp, nonce}
+		sort.Strings(parts)
+		sum := sha1.Sum([]byte(strings.Join(parts, "")))
+		expected, err := hex.DecodeString(signature)
+		if err != nil || subtle.ConstantTimeCompare(sum[:], expected) != 1 {
+			return nil, ErrWebhookSignatureInvalid
+		}
+		return &WebhookDelivery{ID: signature, Timestamp: time.Unix(ts, 0)}, nil
+	}
+}
+
+// WebhookReplayStore 防重放标识的存储，实现需保证并发安全
+type WebhookReplayStore interface {
+	// SetNX key 不存在时写入并设置过期时间，返回是否写入成功
+	SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// RedisWebhookReplayStore 基于 Redis 的 WebhookReplayStore，多实例部署时共享
+type RedisWebhookReplayStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisWebhookReplayStore 创建 RedisWebhookReplayStore
+func NewRedisWebhookReplayStore(client redis.UniversalClient) *RedisWebhookReplayStore {
+	return &RedisWebhookReplayStore{client: client}
+}
+
+func (s *RedisWebhookReplayStore) SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, key, 1, ttl).Result()
+}
+
+// memoryReplaySweepEvery 每写入多少个 key 清理一次过期的 key，避免每次写入都遍历全部 key
+const memoryReplaySweepEvery = 1024
+
+// MemoryWebhookReplayStore 基于内存的 WebhookReplayStore，仅适用于单实例部署和测试，
+// 过期的 key 在访问时清理，并每写入 memoryReplaySweepEvery 个 key 清理一次全部过期的 key
+type MemoryWebhookReplayStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	writes  int
+	now     func() time.Time
+}
+
+// NewMemoryWebhookReplayStore 创建 MemoryWebhookReplayStore
+func NewMemoryWebhookReplayStore() *MemoryWebhookReplayStore {
+	return &MemoryWebhookReplayStore{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryWebhookReplayStore) SetNX(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if expireAt, ok := s.entries[key]; ok && now.Before(expireAt) {
+		return false, nil
+	}
+	s.entries[key] = now.Add(ttl)
+	s.writes++
+	if s.writes >= memoryReplaySweepEvery {
+		s.writes = 0
+		for k, expireAt := range s.entries {
+			if !now.Before(expireAt) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	return true, nil
+}
+
+type webhookConfig struct {
+	tolerance    time.Duration
+	replayStore  WebhookReplayStore
+	replayWindow time.Duration
+	keyPrefix    string
+	maxBody      int64
+}
+
+type WebhookOption func(*webhookConfig)
+
+// WithWebhookTolerance 设置签名时间戳与当前时间允许的最大偏差，默认 5 分钟，仅对带时间戳的签名生效
+func WithWebhookTolerance(d time.Duration) WebhookOption {
+	return func(c *webhookConfig) {
+		c.tolerance = d
+	}
+}
+
+// WithWebhookReplayStore 开启防重放，同一回调标识在 window 内只处理一次，window <= 0 时默认 24 小时；
+// 带时间戳的签名 window 不应小于 2 倍的 tolerance
+func WithWebhookReplayStore(store WebhookReplayStore, window time.Duration) WebhookOption {
+	return func(c *webhookConfig) {
+		c.replayStore = store
+		c.replayWindow = window
+	}
+}
+
+// WithWebhookKeyPrefix 设置防重放标识的 key 前缀，默认 webhook:replay:，多个回调来源共用存储时用于区分
+func WithWebhookKeyPrefix(prefix string) WebhookOption {
+	return func(c *webhookConfig) {
+		c.keyPrefix = prefix
+	}
+}
+
+// WithWebhookMaxBody 设置请求体的最大字节数，默认 5MB
+func WithWebhookMaxBody(n int64) WebhookOption {
+	return func(c *webhookConfig) {
+		c.maxBody = n
+	}
+}
+
+// Webhook 回调签名校验中间件，读取请求体后会还原，后续处理函数可再次读取。
+// 签名缺失或无效时返回 gconstant.WebhookSignatureErr，时间戳超出允许范围或重复投递时返回 gconstant.WebhookReplayErr
+func Webhook(verifier WebhookVerifier, opts ...WebhookOption) gin.HandlerFunc {
+	cfg := &webhookConfig{
+		tolerance: defaultWebhookTolerance,
+		keyPrefix: defaultWebhookKeyPrefix,
+		maxBody:   defaultWebhookMaxBody,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.replayWindow <= 0 {
+		cfg.replayWindow = defaultWebhookReplayWindow
+	}
+
+	return func(ctx *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, cfg.maxBody+1))
+		if err != nil || int64(len(body)) > cfg.maxBody {
+			glog.Warnf(ctx, "webhook read body failed, path: %s, size: %d, err: %v", ctx.Request.URL.Path, len(body), err)
+			abortWebhook(ctx, gconstant.ParamInvalidErr)
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		delivery, err := verifier(ctx, body)
+		if err != nil {
+			glog.Warnf(ctx, "webhook verify failed, path: %s, err: %v", ctx.Request.URL.Path, err)
+			abortWebhook(ctx, gconstant.WebhookSignatureErr)
+			return
+		}
+		if !delivery.Timestamp.IsZero() && cfg.tolerance > 0 {
+			if skew := time.Since(delivery.Timestamp); skew > cfg.tolerance || skew < -cfg.tolerance {
+				glog.Warnf(ctx, "webhook timestamp out of tolerance, path: %s, timestamp: %s", ctx.Request.URL.Path, delivery.Timestamp)
+				abortWebhook(ctx, gconstant.WebhookReplayErr)
+				return
+			}
+		}
+		if cfg.replayStore != nil && delivery.ID != "" {
+			key := cfg.keyPrefix + gcrypto.SHA256Hash(delivery.ID)
+			ok, err := cfg.replayStore.SetNX(ctx, key, cfg.replayWindow)
+			if err != nil {
+				glog.Errorf(ctx, "webhook replay check failed, key: %s, err: %v", key, err)
+				abortWebhook(ctx, gconstant.SystemErrorErr)
+				return
+			}
+			if !ok {
+				glog.Warnf(ctx, "webhook replayed, path: %s, id: %s", ctx.Request.URL.Path, delivery.ID)
+				abortWebhook(ctx, gconstant.WebhookReplayErr)
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+func abortWebhook(ctx *gin.Context, code int) {
+	msgMap := gconstant.AuthErrorMsgMap
+	if code == gconstant.ParamInvalidErr || code == gconstant.SystemErrorErr {
+		msgMap = gconstant.SystemErrorMsgMap
+	}
+	gincontext.Abort(ctx, gerror.Error{Code: code, Msg: msgMap[code]})
+}
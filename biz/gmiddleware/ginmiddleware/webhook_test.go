@@ -0,0 +1,212 @@
+package ginmiddleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve 使用 middleware 处理请求，handler 为最终的业务处理函数
+func serve(req *http.Request, handler gin.HandlerFunc, middleware ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	engine := gin.New()
+	engine.Any("/*path", append(middleware, handler)...)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+// responseCode 返回业务错误码，请求未被中断时返回 0
+func responseCode(t *testing.T, w *httptest.ResponseRecorder) int {
+	t.Helper()
+	var resp struct {
+		Code int `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Code
+}
+
+func okHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"code": 0})
+}
+
+func hmacHex(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newGitHubRequest(body, signature, delivery string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	req.Header.Set("X-GitHub-Delivery", delivery)
+	return req
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	const secret, body = "gh-secret", `{"action":"opened"}`
+	var received string
+	handler := func(ctx *gin.Context) {
+		b, _ := io.ReadAll(ctx.Request.Body)
+		received = string(b)
+		okHandler(ctx)
+	}
+	middleware := Webhook(GitHubWebhook(secret))
+
+	w := serve(newGitHubRequest(body, "sha256="+hmacHex(secret, body), "d1"), handler, middleware)
+	assert.Equal(t, 0, responseCode(t, w))
+	assert.Equal(t, body, received, "body should be restored for the handler")
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+	}{
+		{name: "missing", body: body},
+		{name: "tampered body", body: `{"action":"closed"}`, signature: "sha256=" + hmacHex(secret, body)},
+		{name: "wrong secret", body: body, signature: "sha256=" + hmacHex("other", body)},
+		{name: "no prefix", body: body, signature: hmacHex(secret, body)},
+		{name: "not hex", body: body, signature: "sha256=zz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newGitHubRequest(tt.body, tt.signature, "d1"), okHandler, middleware)
+			assert.Equal(t, gconstant.WebhookSignatureErr, responseCode(t, w))
+		})
+	}
+}
+
+func TestStripeWebhook(t *testing.T) {
+	const secret, body = "whsec", `{"id":"evt_1"}`
+	middleware := Webhook(StripeWebhook(secret, ""))
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{name: "valid", header: "t=" + now + ",v1=" + hmacHex(secret, now+"."+body), want: 0},
+		{name: "rotated secret", header: "t=" + now + ",v1=" + hmacHex("old", now+"."+body) + ",v1=" + hmacHex(secret, now+"."+body), want: 0},
+		{name: "missing", header: "", want: gconstant.WebhookSignatureErr},
+		{name: "no v1", header: "t=" + now, want: gconstant.WebhookSignatureErr},
+		{name: "bad timestamp", header: "t=abc,v1=" + hmacHex(secret, "abc."+body), want: gconstant.WebhookSignatureErr},
+		{name: "tampered signature", header: "t=" + now + ",v1=" + hmacHex(secret, now+"."+body+"x"), want: gconstant.WebhookSignatureErr},
+		{name: "timestamp swapped", header: "t=" + old + ",v1=" + hmacHex(secret, now+"."+body), want: gconstant.WebhookSignatureErr},
+		{name: "outside tolerance", header: "t=" + old + ",v1=" + hmacHex(secret, old+"."+body), want: gconstant.WebhookReplayErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+			if tt.header != "" {
+				req.Header.Set("Stripe-Signature", tt.header)
+			}
+			assert.Equal(t, tt.want, responseCode(t, serve(req, okHandler, middleware)))
+		})
+	}
+}
+
+func TestWeChatWebhook(t *testing.T) {
+	const token = "wx-token"
+	sign := func(token, timestamp, nonce string) string {
+		parts := []string{token, timestamp, nonce}
+		sort.Strings(parts)
+		sum := sha1.Sum([]byte(strings.Join(parts, "")))
+		return hex.EncodeToString(sum[:])
+	}
+	middleware := Webhook(WeChatWebhook(token))
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "valid", query: "signature=" + sign(token, now, "n1") + "&timestamp=" + now + "&nonce=n1", want: 0},
+		{name: "missing", query: "timestamp=" + now + "&nonce=n1", want: gconstant.WebhookSignatureErr},
+		{name: "wrong token", query: "signature=" + sign("other", now, "n1") + "&timestamp=" + now + "&nonce=n1", want: gconstant.WebhookSignatureErr},
+		{name: "tampered nonce", query: "signature=" + sign(token, now, "n1") + "&timestamp=" + now + "&nonce=n2", want: gconstant.WebhookSignatureErr},
+		{name: "bad timestamp", query: "signature=" + sign(token, "x", "n1") + "&timestamp=x&nonce=n1", want: gconstant.WebhookSignatureErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/hook?"+tt.query, strings.NewReader("<xml/>"))
+			assert.Equal(t, tt.want, responseCode(t, serve(req, okHandler, middleware)))
+		})
+	}
+}
+
+func TestWebhookReplay(t *testing.T) {
+	const secret, body = "gh-secret", `{}`
+	signature := "sha256=" + hmacHex(secret, body)
+	middleware := Webhook(GitHubWebhook(secret), WithWebhookReplayStore(NewMemoryWebhookReplayStore(), time.Hour))
+
+	assert.Equal(t, 0, responseCode(t, serve(newGitHubRequest(body, signature, "d1"), okHandler, middleware)))
+	assert.Equal(t, gconstant.WebhookReplayErr, responseCode(t, serve(newGitHubRequest(body, signature, "d1"), okHandler, middleware)))
+	assert.Equal(t, 0, responseCode(t, serve(newGitHubRequest(body, signature, "d2"), okHandler, middleware)))
+}
+
+func TestWebhookMaxBody(t *testing.T) {
+	const secret = "gh-secret"
+	called := false
+	handler := func(ctx *gin.Context) {
+		called = true
+		okHandler(ctx)
+	}
+	middleware := Webhook(GitHubWebhook(secret), WithWebhookMaxBody(8))
+
+	body := "12345678"
+	assert.Equal(t, 0, responseCode(t, serve(newGitHubRequest(body, "sha256="+hmacHex(secret, body), "d1"), handler, middleware)))
+
+	called = false
+	body = "123456789"
+	w := serve(newGitHubRequest(body, "sha256="+hmacHex(secret, body), "d2"), handler, middleware)
+	assert.Equal(t, gconstant.ParamInvalidErr, responseCode(t, w))
+	assert.False(t, called)
+}
+
+func TestMemoryWebhookReplayStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryWebhookReplayStore()
+	store.now = func() time.Time { return now }
+
+	ok, err := store.SetNX(ctx, "k", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, _ = store.SetNX(ctx, "k", time.Minute)
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute)
+	ok, _ = store.SetNX(ctx, "k", time.Minute)
+	assert.True(t, ok, "expired key should be writable again")
+
+	// 过期的 key 在累计写入 memoryReplaySweepEvery 次后被清理
+	now = now.Add(time.Minute)
+	for i := 0; i < memoryReplaySweepEvery; i++ {
+		_, _ = store.SetNX(ctx, "n"+strconv.Itoa(i), time.Second)
+	}
+	assert.Len(t, store.entries, memoryReplaySweepEvery)
+}
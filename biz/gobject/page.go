@@ -4,3 +4,17 @@ type PageQuery struct {
 	Page     int    `json:"page" form:"page" label:"页码"`             // 页码
 	PageSize int    `json:"pageSize" form:"pageSize" validate:"max=1000" label:"每页数据条数"` // 每页数据条数
 }
+
+// CursorQuery 游标分页查询参数，Cursor 为上一页返回的 NextCursor，为空时查询第一页。
+// 按游标记录的排序键继续查询，不随页数增加扫描更多行，适用于大表翻页
+type CursorQuery struct {
+	Cursor   string `json:"cursor" form:"cursor" label:"游标"`                           // 不透明游标
+	PageSize int    `json:"pageSize" form:"pageSize" validate:"max=1000" label:"每页数据条数"` // 每页数据条数
+}
+
+// CursorResult 游标分页查询结果，HasMore 为 false 时 NextCursor 为空
+type CursorResult[T any] struct {
+	List       []T    `json:"list"`       // 当前页数据
+	NextCursor string `json:"nextCursor"` // 下一页游标
+	HasMore    bool   `json:"hasMore"`    // 是否还有下一页
+}
@@ -0,0 +1,10 @@
+package gobject
+
+// Tenant 多租户服务中当前请求所属的租户
+type Tenant struct {
+	ID     uint   `json:"id"`     // 租户ID
+	Code   string `json:"code"`   // 租户编码，如子域名、请求头中的租户标识
+	Name   string `json:"name"`   // 租户名称
+	Schema string `json:"schema"` // 租户独立的数据库 schema，为空表示共享 schema
+	DBName string `json:"dbName"` // 租户独立的数据库实例名，为空表示共享数据库
+}
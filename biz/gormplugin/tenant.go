@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/morehao/golib/biz/gcontext"
-	"github.com/morehao/golib/gutil"
 	"gorm.io/gorm"
 )
 
@@ -120,8 +119,7 @@ func getTenantID(ctx context.Context) (uint, bool) {
 		return 0, false
 	}
 
-	v := ctx.Value(gcontext.KeyTenantID)
-	tenantID := uint(gutil.VToInt64(v))
+	tenantID := gcontext.GetTenantID(ctx)
 	return tenantID, tenantID > 0
 }
 
@@ -0,0 +1,341 @@
+// golib-gen 基于 codegen 包的模块代码生成工具，从数据库或 DDL 文件读取表结构，按表名规则批量生成 CRUD 代码，
+// 便于在 Makefile 中直接调用。
+//
+// 用法：
+//
+//	golib-gen -dialect mysql -dsn "root:123456@tcp(127.0.0.1:3306)/demo?parseTime=True" \
+//		-tables "user_*,!user_tmp" -out ./internal -import-path github.com/foo/bar/internal
+//	golib-gen -ddl schema.sql -tables user -out ./internal -import-path github.com/foo/bar/internal -dry-run
+//	golib-gen -config golib-gen.yaml
+//	golib-gen -ddl schema.sql -tables "*" -out ./internal -verify
+//
+// 配置文件为 YAML，键名见 config，命令行参数优先于配置文件。-dry-run 只输出将要生成的文件与已有文件的 unified diff，不写入文件；
+// -verify 不生成代码，以 JSON 输出已生成的模型与表结构的差异，存在差异时退出码为 1，可作为 CI 检查
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/morehao/golib/codegen"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	dialectMysql    = "mysql"
+	dialectPostgres = "postgres"
+)
+
+// config 生成配置，DSN 与 DDL 二选一
+type config struct {
+	DSN          string   `yaml:"dsn"`            // 数据库连接串
+	Dialect      string   `yaml:"dialect"`        // 数据库方言，mysql 或 postgres；使用 DDL 时为空则根据语句特征识别
+	DDL          string   `yaml:"ddl"`            // DDL 文件路径，设置后不连接数据库
+	Schema       string   `yaml:"schema"`         // 读取表结构的 schema，postgresql 默认 public，mysql 默认当前连接的库
+	Tables       []string `yaml:"tables"`         // 表名规则，支持 glob、re: 前缀的正则和 ! 前缀的排除
+	Package      string   `yaml:"package"`        // 包名，为空时每张表使用表名作为包名
+	TplDir       string   `yaml:"tpl_dir"`        // 模板目录，为空时使用内置模板
+	Out          string   `yaml:"out"`            // 生成文件的根目录
+	ImportPath   string   `yaml:"import_path"`    // 根目录对应的 Go 导入路径，使用内置模板时必填
+	Client       bool     `yaml:"client"`         // 是否同时生成 ghttp 客户端
+	Proto        bool     `yaml:"proto"`          // 是否同时生成 proto 文件
+	DaoTest      string   `yaml:"dao_test"`       // dao 层单元测试的生成方式，sqlmock 或 testcontainers，为空时不生成
+	ErrCodeBase  int      `yaml:"err_code_base"`  // 大于 0 时生成模块错误码，从该值起为每张表分配号段
+	ErrCodeRange int      `yaml:"err_code_range"` // 每张表的错误码号段大小，默认 100
+	NoPKStrategy string   `yaml:"no_pk_strategy"` // 无主键表的处理策略，unique_index、none 或 error
+	DryRun       bool     `yaml:"dry_run"`        // 只输出 diff，不写入文件
+	Verify       bool     `yaml:"verify"`         // 只校验模型与表结构的差异，不生成代码
+	// 以下配置仅支持配置文件
+	TablePrefixes []string          `yaml:"table_prefixes"` // 转换结构体名时去除的表名前缀，如 t_
+	Acronyms      []string          `yaml:"acronyms"`       // 以全大写输出的缩写词，如 ID、URL
+	TypeOverrides map[string]string `yaml:"type_overrides"` // 按列覆盖字段类型，规则见 codegen.ModuleCfg.TypeOverrides
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 执行命令并返回退出码：0 成功，1 存在生成失败的表或校验出差异，2 参数或配置错误
+func run(args []string, stdout, stderr io.Writer) int {
+	cfg, parseErr := parseConfig(args, stderr)
+	if parseErr != nil {
+		if errors.Is(parseErr, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintln(stderr, "golib-gen:", parseErr)
+		return 2
+	}
+	if cfg.Verify {
+		return runVerify(cfg, stdout, stderr)
+	}
+
+	res, genErr := generate(cfg)
+	if genErr != nil {
+		fmt.Fprintln(stderr, "golib-gen:", genErr)
+		return 2
+	}
+	if cfg.DryRun {
+		if err := writeDiff(stdout, res); err != nil {
+			fmt.Fprintln(stderr, "golib-gen:", err)
+			return 2
+		}
+	}
+	fmt.Fprintln(stdout, res.Report())
+	if len(res.Failed()) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// parseConfig 解析命令行参数，指定 -config 时先加载配置文件，再以显式传入的参数覆盖
+func parseConfig(args []string, output io.Writer) (*config, error) {
+	fs := flag.NewFlagSet("golib-gen", flag.ContinueOnError)
+	fs.SetOutput(output)
+	var (
+		flagCfg    config
+		configPath string
+		tables     string
+	)
+	fs.StringVar(&configPath, "config", "", "YAML 配置文件路径")
+	fs.StringVar(&flagCfg.DSN, "dsn", "", "数据库连接串")
+	fs.StringVar(&flagCfg.Dialect, "dialect", "", "数据库方言，mysql 或 postgres")
+	fs.StringVar(&flagCfg.DDL, "ddl", "", "DDL 文件路径，设置后不连接数据库")
+	fs.StringVar(&flagCfg.Schema, "schema", "", "读取表结构的 schema，postgresql 默认 public，mysql 默认当前连接的库")
+	fs.StringVar(&tables, "tables", "", "表名规则，以逗号分隔，如 user_*,!user_tmp")
+	fs.StringVar(&flagCfg.Package, "package", "", "包名，为空时使用表名")
+	fs.StringVar(&flagCfg.TplDir, "tpl", "", "模板目录，为空时使用内置模板")
+	fs.StringVar(&flagCfg.Out, "out", "", "生成文件的根目录")
+	fs.StringVar(&flagCfg.ImportPath, "import-path", "", "根目录对应的 Go 导入路径")
+	fs.BoolVar(&flagCfg.Client, "client", false, "同时生成 ghttp 客户端")
+	fs.BoolVar(&flagCfg.Proto, "proto", false, "同时生成 proto 文件，重新生成时保持字段编号不变")
+	fs.StringVar(&flagCfg.DaoTest, "dao-test", "", "同时生成 dao 层单元测试，sqlmock 或 testcontainers")
+	fs.IntVar(&flagCfg.ErrCodeBase, "err-code-base", 0, "大于 0 时同时生成模块错误码，从该值起为每张表分配号段并注册到 gerror")
+	fs.IntVar(&flagCfg.ErrCodeRange, "err-code-range", 0, "每张表的错误码号段大小，默认 100")
+	fs.StringVar(&flagCfg.NoPKStrategy, "no-pk-strategy", "", "无主键表的处理策略，unique_index、none 或 error")
+	fs.BoolVar(&flagCfg.DryRun, "dry-run", false, "只输出与已有文件的 diff，不写入文件")
+	fs.BoolVar(&flagCfg.Verify, "verify", false, "只校验已生成的模型与表结构的差异，以 JSON 输出")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg := &config{}
+	if configPath != "" {
+		data, readErr := os.ReadFile(configPath)
+		if readErr != nil {
+			return nil, fmt.Errorf("read config: %w", readErr)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", configPath, err)
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "dsn":
+			cfg.DSN = flagCfg.DSN
+		case "dialect":
+			cfg.Dialect = flagCfg.Dialect
+		case "ddl":
+			cfg.DDL = flagCfg.DDL
+		case "schema":
+			cfg.Schema = flagCfg.Schema
+		case "tables":
+			cfg.Tables = strings.Split(tables, ",")
+		case "package":
+			cfg.Package = flagCfg.Package
+		case "tpl":
+			cfg.TplDir = flagCfg.TplDir
+		case "out":
+			cfg.Out = flagCfg.Out
+		case "import-path":
+			cfg.ImportPath = flagCfg.ImportPath
+		case "client":
+			cfg.Client = flagCfg.Client
+		case "proto":
+			cfg.Proto = flagCfg.Proto
+		case "dao-test":
+			cfg.DaoTest = flagCfg.DaoTest
+		case "err-code-base":
+			cfg.ErrCodeBase = flagCfg.ErrCodeBase
+		case "err-code-range":
+			cfg.ErrCodeRange = flagCfg.ErrCodeRange
+		case "no-pk-strategy":
+			cfg.NoPKStrategy = flagCfg.NoPKStrategy
+		case "dry-run":
+			cfg.DryRun = flagCfg.DryRun
+		case "verify":
+			cfg.Verify = flagCfg.Verify
+		}
+	})
+	return cfg, cfg.validate()
+}
+
+func (cfg *config) validate() error {
+	switch {
+	case cfg.DSN == "" && cfg.DDL == "":
+		return errors.New("dsn or ddl is required")
+	case cfg.DSN != "" && cfg.DDL != "":
+		return errors.New("dsn and ddl are mutually exclusive")
+	case cfg.DSN != "" && cfg.Dialect == "":
+		return errors.New("dialect is required when using dsn")
+	case cfg.Dialect != "" && cfg.Dialect != dialectMysql && cfg.Dialect != dialectPostgres:
+		return fmt.Errorf("unsupported dialect: %s", cfg.Dialect)
+	case cfg.DaoTest != "" && cfg.DaoTest != string(codegen.DaoTestSqlmock) && cfg.DaoTest != string(codegen.DaoTestContainers):
+		return fmt.Errorf("unsupported dao test mode: %s", cfg.DaoTest)
+	case cfg.ErrCodeBase < 0 || cfg.ErrCodeRange < 0:
+		return errors.New("err code base and range must not be negative")
+	case len(cfg.Tables) == 0:
+		return errors.New("tables is required")
+	case cfg.Out == "":
+		return errors.New("out is required")
+	}
+	return nil
+}
+
+func (cfg *config) moduleCfg() *codegen.ModuleCfg {
+	var naming *codegen.NamingStrategy
+	if len(cfg.TablePrefixes) > 0 || len(cfg.Acronyms) > 0 {
+		naming = &codegen.NamingStrategy{TablePrefixes: cfg.TablePrefixes, Acronyms: cfg.Acronyms}
+	}
+	return &codegen.ModuleCfg{
+		CommonConfig: codegen.CommonConfig{
+			PackageName: cfg.Package,
+			TplDir:      cfg.TplDir,
+			RootDir:     cfg.Out,
+		},
+		TableNames:         cfg.Tables,
+		NoPKStrategy:       codegen.NoPKStrategy(cfg.NoPKStrategy),
+		SchemaName:         cfg.Schema,
+		Dialect:            cfg.Dialect,
+		ImportPath:         cfg.ImportPath,
+		GenClient:          cfg.Client,
+		GenProto:           cfg.Proto,
+		DaoTest:            codegen.DaoTestMode(cfg.DaoTest),
+		ErrorCodeBase:      cfg.ErrCodeBase,
+		ErrorCodeRangeSize: cfg.ErrCodeRange,
+		DryRun:             cfg.DryRun,
+		NamingStrategy:     naming,
+		TypeOverrides:      cfg.TypeOverrides,
+	}
+}
+
+func generate(cfg *config) (*codegen.GenerateModulesRes, error) {
+	if cfg.DDL != "" {
+		ddl, readErr := os.ReadFile(cfg.DDL)
+		if readErr != nil {
+			return nil, fmt.Errorf("read ddl: %w", readErr)
+		}
+		return codegen.GenerateModulesFromDDL(string(ddl), cfg.moduleCfg())
+	}
+
+	db, openErr := cfg.openDB()
+	if openErr != nil {
+		return nil, openErr
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	return codegen.GenerateModules(db, cfg.moduleCfg())
+}
+
+// runVerify 以 JSON 输出模型与表结构的差异，汇总输出到 stderr
+func runVerify(cfg *config, stdout, stderr io.Writer) int {
+	res, verifyErr := verify(cfg)
+	if verifyErr != nil {
+		fmt.Fprintln(stderr, "golib-gen:", verifyErr)
+		return 2
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(res); err != nil {
+		fmt.Fprintln(stderr, "golib-gen:", err)
+		return 2
+	}
+	fmt.Fprintln(stderr, res.Report())
+	if res.HasDrift() {
+		return 1
+	}
+	return 0
+}
+
+func verify(cfg *config) (*codegen.VerifyRes, error) {
+	if cfg.DDL != "" {
+		ddl, readErr := os.ReadFile(cfg.DDL)
+		if readErr != nil {
+			return nil, fmt.Errorf("read ddl: %w", readErr)
+		}
+		return codegen.VerifyFromDDL(string(ddl), cfg.moduleCfg())
+	}
+
+	db, openErr := cfg.openDB()
+	if openErr != nil {
+		return nil, openErr
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	return codegen.Verify(db, cfg.moduleCfg())
+}
+
+func (cfg *config) openDB() (*gorm.DB, error) {
+	dialector := mysql.Open(cfg.DSN)
+	if cfg.Dialect == dialectPostgres {
+		dialector = postgres.Open(cfg.DSN)
+	}
+	db, openErr := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if openErr != nil {
+		return nil, fmt.Errorf("open database: %w", openErr)
+	}
+	return db, nil
+}
+
+// writeDiff 输出各文件渲染结果与磁盘内容的 unified diff，新文件与空文件对比，内容相同的文件不输出。
+// 已存在的文件实际生成时会跳过（proto 文件除外），diff 仅用于查看模板或表结构变化带来的差异
+func writeDiff(w io.Writer, res *codegen.GenerateModulesRes) error {
+	for _, table := range res.Tables {
+		for _, file := range table.Files {
+			fromFile, toFile := "/dev/null", file.Path
+			var current []byte
+			if file.Exist {
+				var readErr error
+				if current, readErr = os.ReadFile(file.Path); readErr != nil {
+					return fmt.Errorf("read %s: %w", file.Path, readErr)
+				}
+				fromFile, toFile = file.Path, file.Path+" (generated, skipped)"
+				if file.Overwrite {
+					toFile = file.Path + " (generated)"
+				}
+			}
+			if bytes.Equal(current, file.Content) {
+				continue
+			}
+			diff, diffErr := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        difflib.SplitLines(string(current)),
+				B:        difflib.SplitLines(string(file.Content)),
+				FromFile: fromFile,
+				ToFile:   toFile,
+				Context:  3,
+			})
+			if diffErr != nil {
+				return fmt.Errorf("diff %s: %w", file.Path, diffErr)
+			}
+			if _, err := io.WriteString(w, diff); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
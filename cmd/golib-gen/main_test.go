@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDDL = `
+CREATE TABLE user (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  name varchar(64) NOT NULL DEFAULT '',
+  PRIMARY KEY (id)
+) ENGINE=InnoDB;
+CREATE TABLE user_tmp (id int PRIMARY KEY);
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	ddlPath := filepath.Join(dir, "schema.sql")
+	require.Nil(t, os.WriteFile(ddlPath, []byte(testDDL), 0644))
+	outDir := filepath.Join(dir, "internal")
+	configPath := filepath.Join(dir, "golib-gen.yaml")
+	require.Nil(t, os.WriteFile(configPath, []byte("ddl: "+ddlPath+"\ntables: [user*]\nout: "+outDir+"\nimport_path: example.com/demo/internal\n"), 0644))
+
+	// 命令行参数覆盖配置文件
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-config", configPath, "-tables", "user*,!user_tmp", "-dry-run"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	modelPath := filepath.Join(outDir, "model", "user.go")
+	assert.Contains(t, stdout.String(), "--- /dev/null\n+++ "+modelPath)
+	assert.Contains(t, stdout.String(), "user: package user, 6 generated, 0 skipped")
+	assert.NotContains(t, stdout.String(), "user_tmp")
+	assert.NoFileExists(t, modelPath)
+
+	stdout.Reset()
+	code = run([]string{"-config", configPath, "-tables", "user"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.FileExists(t, modelPath)
+
+	// 已有文件与生成结果一致时不输出 diff，被修改时输出差异
+	require.Nil(t, os.WriteFile(modelPath, []byte("package model\n"), 0644))
+	stdout.Reset()
+	code = run([]string{"-config", configPath, "-tables", "user", "-dry-run"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), "--- "+modelPath+"\n+++ "+modelPath+" (generated, skipped)")
+	assert.Contains(t, stdout.String(), "user: package user, 0 generated, 6 skipped")
+	assert.Equal(t, 1, bytes.Count(stdout.Bytes(), []byte("+++ ")))
+}
+
+func TestRunInvalidArgs(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run([]string{"-tables", "user", "-out", "."}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "dsn or ddl is required")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"-dsn", "x", "-tables", "user", "-out", "."}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "dialect is required when using dsn")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"-ddl", "x.sql", "-tables", "user", "-out", ".", "-dao-test", "gomock"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "unsupported dao test mode: gomock")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"-ddl", "x.sql", "-tables", "user", "-out", ".", "-err-code-base", "-1"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "err code base and range must not be negative")
+}
+
+func TestRunVerify(t *testing.T) {
+	dir := t.TempDir()
+	ddlPath := filepath.Join(dir, "schema.sql")
+	require.Nil(t, os.WriteFile(ddlPath, []byte(testDDL), 0644))
+	outDir := filepath.Join(dir, "internal")
+	args := []string{"-ddl", ddlPath, "-tables", "user", "-out", outDir, "-import-path", "example.com/demo/internal"}
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run(args, &stdout, &stderr), stderr.String())
+	stdout.Reset()
+	stderr.Reset()
+	require.Equal(t, 0, run(append(args, "-verify"), &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), `"drifts": []`)
+
+	// 表结构新增列后校验失败
+	require.Nil(t, os.WriteFile(ddlPath, []byte(strings.Replace(testDDL, "PRIMARY KEY (id)", "age int NOT NULL DEFAULT 0,\n  PRIMARY KEY (id)", 1)), 0644))
+	stdout.Reset()
+	stderr.Reset()
+	assert.Equal(t, 1, run(append(args, "-verify"), &stdout, &stderr))
+	assert.Contains(t, stdout.String(), `"kind": "column_added"`)
+	assert.Contains(t, stderr.String(), "user: column_added: age (Age int32)")
+}
@@ -0,0 +1,232 @@
+package codegen
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	tablePatternRegexpPrefix  = "re:"
+	tablePatternExcludePrefix = "!"
+)
+
+// TableGenerateRes 单张表的生成结果
+type TableGenerateRes struct {
+	TableName   string // 表名
+	PackageName string // 生成代码使用的包名
+	GenerateModuleRes
+	Err error // 生成失败的原因，成功时为 nil
+}
+
+// GenerateModulesRes 批量生成结果，按表名排序
+type GenerateModulesRes struct {
+	Tables []TableGenerateRes
+}
+
+// Failed 返回生成失败的表
+func (r *GenerateModulesRes) Failed() []TableGenerateRes {
+	var res []TableGenerateRes
+	for _, v := range r.Tables {
+		if v.Err != nil {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Report 返回生成结果汇总，每张表一行，末尾为合计
+func (r *GenerateModulesRes) Report() string {
+	var (
+		sb                        strings.Builder
+		generated, skipped, fails int
+	)
+	for _, v := range r.Tables {
+		if v.Err != nil {
+			fails++
+			fmt.Fprintf(&sb, "%s: failed, %v\n", v.TableName, v.Err)
+			continue
+		}
+		generated += len(v.GeneratedFiles)
+		skipped += len(v.SkippedFiles)
+		fmt.Fprintf(&sb, "%s: package %s, %d generated, %d skipped\n", v.TableName, v.PackageName, len(v.GeneratedFiles), len(v.SkippedFiles))
+	}
+	fmt.Fprintf(&sb, "total: %d tables, %d failed, %d files generated, %d skipped", len(r.Tables), fails, generated, skipped)
+	return sb.String()
+}
+
+// GenerateModules 按 cfg.TableNames 批量生成多张表的模块代码，单张表失败不影响其他表，失败原因记录在结果中。
+// cfg.PackageName 为空时每张表使用去除 NamingStrategy.TablePrefixes 前缀后的表名作为包名，生成到各自的目录；指定时所有表共用该包，
+// 此时 router 等按包名命名的文件只为第一张表生成，其余表跳过
+func GenerateModules(db *gorm.DB, cfg *ModuleCfg) (*GenerateModulesRes, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	tableList, listErr := listTables(db, cfg.SchemaName)
+	if listErr != nil {
+		return nil, listErr
+	}
+	return generateModules(cfg, tableList, func(tableCfg *ModuleCfg) (*GenerateModuleRes, error) {
+		return GenerateModule(db, tableCfg)
+	})
+}
+
+// GenerateModulesFromDDL 解析 DDL 并按 cfg.TableNames 批量生成模块代码，规则同 GenerateModules
+func GenerateModulesFromDDL(sqlText string, cfg *ModuleCfg) (*GenerateModulesRes, error) {
+	tables, parseErr := parseDDL(sqlText)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	tableList := make([]string, 0, len(tables))
+	for _, v := range tables {
+		tableList = append(tableList, v.name)
+	}
+	return generateModules(cfg, tableList, func(tableCfg *ModuleCfg) (*GenerateModuleRes, error) {
+		return GenerateFromDDL(sqlText, tableCfg)
+	})
+}
+
+func generateModules(cfg *ModuleCfg, tableList []string, genFunc func(tableCfg *ModuleCfg) (*GenerateModuleRes, error)) (*GenerateModulesRes, error) {
+	tableCfgs, matchErr := batchTableCfgs(cfg, tableList)
+	if matchErr != nil {
+		return nil, matchErr
+	}
+	res := &GenerateModulesRes{}
+	for _, tableCfg := range tableCfgs {
+		tableRes := TableGenerateRes{TableName: tableCfg.TableName, PackageName: tableCfg.PackageName}
+		if genRes, genErr := genFunc(tableCfg); genErr != nil {
+			tableRes.Err = genErr
+		} else {
+			tableRes.GenerateModuleRes = *genRes
+		}
+		res.Tables = append(res.Tables, tableRes)
+	}
+	return res, nil
+}
+
+// batchTableCfgs 按 cfg.TableNames 匹配表名，未设置时使用 cfg.TableName，返回每张表的配置
+func batchTableCfgs(cfg *ModuleCfg, tableList []string) ([]*ModuleCfg, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("cfg is nil")
+	}
+	patterns := cfg.TableNames
+	if len(patterns) == 0 && cfg.TableName != "" {
+		patterns = []string{cfg.TableName}
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("tableNames is required")
+	}
+	matched, matchErr := MatchTableNames(tableList, patterns)
+	if matchErr != nil {
+		return nil, matchErr
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no table matches %s", strings.Join(patterns, ", "))
+	}
+	batchCfg := *cfg
+	batchCfg.format()
+
+	// 多张表时在批次版本号后追加定宽序号，如 20060102150405001，保证版本号互不相同且按表名顺序执行
+	suffixWidth := max(3, len(strconv.Itoa(len(matched))))
+	res := make([]*ModuleCfg, 0, len(matched))
+	for i, tableName := range matched {
+		tableCfg := batchCfg
+		tableCfg.TableName = tableName
+		if len(matched) > 1 {
+			tableCfg.MigrationVersion = fmt.Sprintf("%s%0*d", batchCfg.MigrationVersion, suffixWidth, i+1)
+		}
+		tableCfg.TableNames = nil
+		if tableCfg.PackageName == "" {
+			tableCfg.PackageName = batchCfg.NamingStrategy.TrimTablePrefix(tableName)
+		}
+		// 提前规范化包名，结果中的包名与生成的目录一致
+		tableCfg.format()
+		res = append(res, &tableCfg)
+	}
+	return res, nil
+}
+
+// MatchTableNames 返回与 patterns 匹配的表名，结果按表名排序。pattern 支持：
+// 精确表名；glob，如 user_*、sys_?_log；以 re: 开头的正则，如 re:^(user|role)_；
+// 以 ! 开头表示排除，如 !user_tmp、!re:_bak$，排除优先于包含。只有排除规则时从全部表中排除
+func MatchTableNames(tableList, patterns []string) ([]string, error) {
+	var includes, excludes []func(string) bool
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		exclude := strings.HasPrefix(pattern, tablePatternExcludePrefix)
+		pattern = strings.TrimPrefix(pattern, tablePatternExcludePrefix)
+		matcher, err := newTableMatcher(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if exclude {
+			excludes = append(excludes, matcher)
+		} else {
+			includes = append(includes, matcher)
+		}
+	}
+
+	matchAny := func(matchers []func(string) bool, name string) bool {
+		for _, match := range matchers {
+			if match(name) {
+				return true
+			}
+		}
+		return false
+	}
+	seen := make(map[string]bool, len(tableList))
+	var res []string
+	for _, name := range tableList {
+		if seen[name] || matchAny(excludes, name) {
+			continue
+		}
+		if len(includes) > 0 && !matchAny(includes, name) {
+			continue
+		}
+		seen[name] = true
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+func newTableMatcher(pattern string) (func(string) bool, error) {
+	if expr, ok := strings.CutPrefix(pattern, tablePatternRegexpPrefix); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid table pattern %s: %w", pattern, err)
+		}
+		return re.MatchString, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid table pattern %s: %w", pattern, err)
+	}
+	return func(name string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	}, nil
+}
+
+// listTables 获取 schemaName 中的表名，为空时 mysql 使用当前连接的库，postgresql 使用 public
+func listTables(db *gorm.DB, schemaName string) ([]string, error) {
+	switch dbType := db.Dialector.Name(); dbType {
+	case dbTypeMysql:
+		dbName, err := mysqlSchemaName(db, schemaName)
+		if err != nil {
+			return nil, err
+		}
+		return getTableList(db, dbName)
+	case dbTypePostgresql:
+		return getPostgresqlTableList(db, schemaName)
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", dbType)
+	}
+}
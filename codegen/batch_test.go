@@ -0,0 +1,115 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/morehao/golib/dbaccess/dbgorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMatchTableNames(t *testing.T) {
+	tables := []string{"user_role", "user", "user_tmp", "sys_log", "sys_log_bak", "order"}
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{name: "exact", patterns: []string{"order", "user"}, want: []string{"order", "user"}},
+		{name: "glob", patterns: []string{"user_*"}, want: []string{"user_role", "user_tmp"}},
+		{name: "regexp", patterns: []string{"re:^sys_"}, want: []string{"sys_log", "sys_log_bak"}},
+		{name: "exclude", patterns: []string{"user*", "!user_tmp"}, want: []string{"user", "user_role"}},
+		{name: "only exclude", patterns: []string{"!re:_(bak|tmp)$", "!order"}, want: []string{"sys_log", "user", "user_role"}},
+		{name: "no match", patterns: []string{"missing_*"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchTableNames(tables, tt.patterns)
+			require.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := MatchTableNames(tables, []string{"re:("})
+	assert.ErrorContains(t, err, "invalid table pattern re:(")
+	_, err = MatchTableNames(tables, []string{"user_["})
+	assert.ErrorContains(t, err, "invalid table pattern user_[")
+}
+
+func TestGenerateModulesFromDDL(t *testing.T) {
+	ddl := `
+CREATE TABLE user_account (id bigint PRIMARY KEY, name varchar(32) NOT NULL);
+CREATE TABLE user_profile (id bigint PRIMARY KEY, bio text);
+CREATE TABLE user_log (content text);
+CREATE TABLE sys_config (id int PRIMARY KEY);
+`
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{RootDir: rootDir},
+		TableNames:   []string{"user_*"},
+		ImportPath:   "example.com/demo",
+		Dialect:      dbTypePostgresql,
+		NoPKStrategy: NoPKStrategyError,
+	}
+	res, err := GenerateModulesFromDDL(ddl, cfg)
+	require.Nil(t, err)
+	require.Len(t, res.Tables, 3)
+
+	assert.Equal(t, "user_account", res.Tables[0].TableName)
+	assert.Equal(t, "useraccount", res.Tables[0].PackageName)
+	assert.Len(t, res.Tables[0].GeneratedFiles, 6)
+	assert.FileExists(t, filepath.Join(rootDir, "dao", "daouseraccount", "user_account.go"))
+	assert.FileExists(t, filepath.Join(rootDir, "dao", "daouserprofile", "user_profile.go"))
+	assert.FileExists(t, filepath.Join(rootDir, "router", "userprofile.go"))
+
+	// 无主键且策略为报错的表记录失败原因，不影响其他表
+	failed := res.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "user_log", failed[0].TableName)
+	assert.NotNil(t, failed[0].Err)
+	_, statErr := os.Stat(filepath.Join(rootDir, "model", "user_log.go"))
+	assert.True(t, os.IsNotExist(statErr))
+
+	report := res.Report()
+	assert.Contains(t, report, "user_account: package useraccount, 6 generated, 0 skipped")
+	assert.Contains(t, report, "user_log: failed, ")
+	assert.Contains(t, report, "total: 3 tables, 1 failed, 12 files generated, 0 skipped")
+
+	cfg.TableNames = []string{"order_*"}
+	_, err = GenerateModulesFromDDL(ddl, cfg)
+	assert.ErrorContains(t, err, "no table matches order_*")
+}
+
+func TestBatchTableCfgsMigrationVersion(t *testing.T) {
+	tables := []string{"user_role", "user", "order"}
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{MigrationVersion: "20260101000000"},
+		TableNames:   []string{"user*", "order"},
+	}
+	tableCfgs, err := batchTableCfgs(cfg, tables)
+	require.Nil(t, err)
+	require.Len(t, tableCfgs, 3)
+
+	// 同一批次的迁移可以一起注册，按表名顺序执行
+	migrations := make([]*dbgorm.Migration, 0, len(tableCfgs))
+	for _, tableCfg := range tableCfgs {
+		migrations = append(migrations, &dbgorm.Migration{
+			Version: tableCfg.MigrationVersion,
+			Name:    "create_" + tableCfg.TableName,
+			Up:      func(tx *gorm.DB) error { return nil },
+		})
+	}
+	assert.Equal(t, "20260101000000001", migrations[0].Version)
+	assert.Equal(t, "20260101000000003", migrations[2].Version)
+	_, err = dbgorm.NewMigrator(&gorm.DB{}, migrations)
+	assert.Nil(t, err)
+
+	// 单张表保持指定的版本号
+	cfg.TableNames = []string{"order"}
+	tableCfgs, err = batchTableCfgs(cfg, tables)
+	require.Nil(t, err)
+	assert.Equal(t, "20260101000000", tableCfgs[0].MigrationVersion)
+}
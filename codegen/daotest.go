@@ -0,0 +1,77 @@
+package codegen
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/morehao/golib/gutil"
+)
+
+// DaoTestMode 生成 dao 层单元测试的方式
+type DaoTestMode string
+
+const (
+	// DaoTestNone 不生成 dao 层单元测试
+	DaoTestNone DaoTestMode = ""
+	// DaoTestSqlmock 基于 github.com/DATA-DOG/go-sqlmock，不依赖数据库，校验各方法执行的语句类型和目标表
+	DaoTestSqlmock DaoTestMode = "sqlmock"
+	// DaoTestContainers 基于 testcontainers-go 启动 MySQL 或 PostgreSQL 容器，按模型 AutoMigrate 建表后依次执行 CRUD，
+	// 需要本地 Docker 环境，-short 或 Docker 不可用时跳过
+	DaoTestContainers DaoTestMode = "testcontainers"
+)
+
+const (
+	// LayerNameDaoTest dao 层单元测试，生成到 dao 层目录，文件名为 dao 文件名加 _test 后缀
+	LayerNameDaoTest LayerName = "dao_test"
+
+	defaultDaoTestTplDir = "templates/daotest"
+)
+
+// DefaultDaoTestTplFS 返回内置的 dao 层单元测试模板，模板参数同 ModuleTplParams
+func DefaultDaoTestTplFS(mode DaoTestMode) (fs.FS, error) {
+	switch mode {
+	case DaoTestSqlmock, DaoTestContainers:
+		return fs.Sub(defaultModuleTplFS, defaultDaoTestTplDir+"/"+string(mode))
+	default:
+		return nil, fmt.Errorf("unsupported dao test mode: %s", mode)
+	}
+}
+
+// analysisDaoTestTpl 分析 dao 层单元测试模板，目标目录和包名与 dao 层一致，使 dao 层的目录规则和输出路径模板同样作用于测试文件
+func analysisDaoTestTpl(cfg *ModuleCfg, tplAnalysisList []ModuleTplAnalysisItem) ([]ModuleTplAnalysisItem, error) {
+	var daoItem *ModuleTplAnalysisItem
+	for i, item := range tplAnalysisList {
+		if item.OriginLayerName == LayerNameDao {
+			daoItem = &tplAnalysisList[i]
+			break
+		}
+	}
+	if daoItem == nil {
+		return nil, fmt.Errorf("dao template not found, daoTest requires the dao layer")
+	}
+	tplFS, fsErr := DefaultDaoTestTplFS(cfg.DaoTest)
+	if fsErr != nil {
+		return nil, fsErr
+	}
+	testCfg := cfg.CommonConfig
+	testCfg.TplDir, testCfg.TplFS, testCfg.OutputPathTplMap = "", tplFS, nil
+	testTplList, analysisErr := analysisTplFiles(testCfg, cfg.TableName)
+	if analysisErr != nil {
+		return nil, analysisErr
+	}
+
+	targetFilename := strings.TrimSuffix(daoItem.TargetFilename, goFileExtension) + "_test" + goFileExtension
+	res := make([]ModuleTplAnalysisItem, 0, len(testTplList))
+	for _, v := range testTplList {
+		v.OriginLayerName, v.LayerName, v.LayerPrefix = LayerNameDaoTest, LayerNameDaoTest, daoItem.LayerPrefix
+		v.TargetDir, v.TargetFilename, v.TargetPackage = daoItem.TargetDir, targetFilename, daoItem.TargetPackage
+		v.TargetFileExist = gutil.FileExists(filepath.Join(v.TargetDir, v.TargetFilename))
+		res = append(res, ModuleTplAnalysisItem{
+			TplAnalysisItem: v,
+			ModelFields:     daoItem.ModelFields,
+		})
+	}
+	return res, nil
+}
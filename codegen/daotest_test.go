@@ -0,0 +1,61 @@
+package codegen
+
+import (
+	"go/format"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDaoTest(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     rootDir,
+		},
+		TableName:  "user",
+		ImportPath: "example.com/demo",
+		DryRun:     true,
+		DaoTest:    DaoTestSqlmock,
+	}
+	res, err := GenerateFromDDL(mysqlTestDDL, cfg)
+	require.Nil(t, err)
+	require.Len(t, res.Files, 7)
+	testFile := res.Files[6]
+	assert.Equal(t, filepath.Join(rootDir, "dao", "daouser", "user_test.go"), testFile.Path)
+	_, err = format.Source(testFile.Content)
+	require.Nil(t, err)
+	content := string(testFile.Content)
+	assert.Contains(t, content, "package daouser\n")
+	assert.Contains(t, content, `"gorm.io/driver/mysql"`)
+	assert.Contains(t, content, "func TestUserDao(t *testing.T) {")
+	// deleted_at 为 gorm.DeletedAt，删除期望 UPDATE
+	assert.Contains(t, content, `mock.ExpectExec("^UPDATE " + table).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, dao *UserDao) error {
+				var id uint
+				return dao.DeleteByPK(ctx, id)`)
+
+	res, err = GenerateFromDDL(postgresTestDDL, &ModuleCfg{
+		CommonConfig: cfg.CommonConfig,
+		TableName:    "user_role",
+		ImportPath:   "example.com/demo",
+		DryRun:       true,
+		DaoTest:      DaoTestContainers,
+	})
+	require.Nil(t, err)
+	require.Len(t, res.Files, 7)
+	testFile = res.Files[6]
+	assert.Equal(t, filepath.Join(rootDir, "dao", "daouser", "user_role_test.go"), testFile.Path)
+	_, err = format.Source(testFile.Content)
+	require.Nil(t, err)
+	content = string(testFile.Content)
+	assert.Contains(t, content, `tcpostgres.Run(ctx, "postgres:16-alpine",`)
+	assert.Contains(t, content, "dao.GetByPK(ctx, entity.UserId, entity.RoleId)")
+
+	_, err = DefaultDaoTestTplFS("gomock")
+	assert.NotNil(t, err)
+}
@@ -2,15 +2,16 @@ package codegen
 
 import (
 	"database/sql"
-	"fmt"
 
 	"gorm.io/gorm"
 )
 
 const (
-	dbTypeMysql     = "mysql"
+	dbTypeMysql      = "mysql"
 	dbTypePostgresql = "postgres"
 
+	defaultPostgresqlSchema = "public"
+
 	ColumnKeyPRI = "PRI" // 主键
 )
 
@@ -41,28 +42,34 @@ type postgresqlTableColumn struct {
 	DataType               string         `gorm:"column:data_type"`                // 列的数据类型，如integer
 	UdtName                string         `gorm:"column:udt_name"`                 // PostgreSQL 用户定义类型名，通常与 data_type 相同
 	IsNullable             string         `gorm:"column:is_nullable"`              // 列是否允许 NULL 值。可能的值为 YES 或 NO
-	ColumnDefault          sql.NullString `gorm:"column:column_default"`          // 列的默认值
+	ColumnDefault          sql.NullString `gorm:"column:column_default"`           // 列的默认值
 	CharacterMaximumLength sql.NullInt64  `gorm:"column:character_maximum_length"` // 字符串列的最大长度
 	NumericPrecision       sql.NullInt64  `gorm:"column:numeric_precision"`        // 数值列的精度
 	NumericScale           sql.NullInt64  `gorm:"column:numeric_scale"`            // 数值列的小数位数
-	DatetimePrecision      sql.NullInt64  `gorm:"column:datetime_precision"`      // 日期时间列的精度
+	DatetimePrecision      sql.NullInt64  `gorm:"column:datetime_precision"`       // 日期时间列的精度
 	OrdinalPosition        int64          `gorm:"column:ordinal_position"`         // 列在表中的位置，从 1 开始
-	TableSchema            string         `gorm:"column:table_schema"`            // 表所在的 schema
-	TableName              string         `gorm:"column:table_name"`              // 表名
-	ColumnComment          string         `gorm:"column:column_comment"`          // 列的注释（通过 JOIN pg_description 获取）
+	TableSchema            string         `gorm:"column:table_schema"`             // 表所在的 schema
+	TableName              string         `gorm:"column:table_name"`               // 表名
+	ColumnComment          string         `gorm:"column:column_comment"`           // 列的注释（通过 JOIN pg_description 获取）
+	IsIdentity             string         `gorm:"column:is_identity"`              // 是否为 identity 列，可能的值为 YES 或 NO
 }
 
 type ModelField struct {
-	FieldName      string // 字段名称
-	FieldType      string // 字段数据类型，如int、string
-	ColumnName     string // 列名
-	ColumnType     string // 列数据类型，如varchar(255)
-	ColumnKey      string // 索引类型，如PRI（主键）, UNI（唯一索引）, MUL（非唯一索引）
-	IsNullable     bool   // 是否允许为空
-	DefaultValue   string // 默认值
-	Comment        string // 字段注释
-	IndexName      string // 索引名称
-	IsUniqueIndex  bool   // 是否唯一索引
+	FieldName       string      // 字段名称
+	FieldType       string      // 字段数据类型，如int、string
+	ColumnName      string      // 列名
+	ColumnType      string      // 列数据类型，如varchar(255)
+	ColumnKey       string      // 索引类型，如PRI（主键）, UNI（唯一索引）, MUL（非唯一索引）
+	IsNullable      bool        // 是否允许为空
+	DefaultValue    string      // 默认值
+	Comment         string      // 字段注释
+	IndexName       string      // 索引名称
+	IsUniqueIndex   bool        // 是否唯一索引
+	IsAutoIncrement bool        // 是否自增，mysql 取自 EXTRA，postgresql 为 serial 或 identity 列
+	IndexTag        string      // gorm 索引标签，如 uniqueIndex:uk_name、index:idx_age_status,priority:1，属于多个索引时以 ; 分隔
+	ForeignKey      *ForeignKey // 列所属的外键，非外键列为 nil
+	IsTypeOverride  bool        // 字段类型是否来自 ModuleCfg.TypeOverrides
+	TypeImport      string      // 覆盖的字段类型需要的导入路径，内置类型为空
 }
 
 type mysqlIndexInfo struct {
@@ -91,8 +98,8 @@ func (l TableList) ToMap() map[string]struct{} {
 }
 
 func getTableList(db *gorm.DB, dbName string) (tableList TableList, err error) {
-	getTableSql := fmt.Sprintf("SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = '%s';", dbName)
-	if err = db.Raw(getTableSql).Scan(&tableList).Error; err != nil {
+	getTableSql := "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ?;"
+	if err = db.Raw(getTableSql, dbName).Scan(&tableList).Error; err != nil {
 		return nil, err
 	}
 	return tableList, nil
@@ -108,6 +115,22 @@ func getDbName(db *gorm.DB) (dbName string, err error) {
 	return entity.DbName, nil
 }
 
+// mysqlSchemaName 返回 mysql 查询元数据使用的库名，未指定时为当前连接的库
+func mysqlSchemaName(db *gorm.DB, schemaName string) (string, error) {
+	if schemaName != "" {
+		return schemaName, nil
+	}
+	return getDbName(db)
+}
+
+// postgresqlSchemaName 返回 postgresql 查询元数据使用的 schema，未指定时为 public
+func postgresqlSchemaName(schemaName string) string {
+	if schemaName == "" {
+		return defaultPostgresqlSchema
+	}
+	return schemaName
+}
+
 func getPostgresqlDbName(db *gorm.DB) (dbName string, err error) {
 	var entity struct {
 		DbName string `gorm:"column:current_database"`
@@ -119,11 +142,8 @@ func getPostgresqlDbName(db *gorm.DB) (dbName string, err error) {
 }
 
 func getPostgresqlTableList(db *gorm.DB, schemaName string) (tableList TableList, err error) {
-	if schemaName == "" {
-		schemaName = "public"
-	}
-	getTableSql := fmt.Sprintf("SELECT table_name FROM information_schema.tables WHERE table_schema = '%s' AND table_type = 'BASE TABLE';", schemaName)
-	if err = db.Raw(getTableSql).Scan(&tableList).Error; err != nil {
+	getTableSql := "SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_type = 'BASE TABLE';"
+	if err = db.Raw(getTableSql, postgresqlSchemaName(schemaName)).Scan(&tableList).Error; err != nil {
 		return nil, err
 	}
 	return tableList, nil
@@ -0,0 +1,47 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMetadataQueryParams(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=test dbname=test"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.Nil(t, err)
+	var (
+		sqlList []string
+		vars    [][]any
+	)
+	require.Nil(t, db.Callback().Row().After("gorm:row").Register("test:capture", func(tx *gorm.DB) {
+		sqlList = append(sqlList, tx.Statement.SQL.String())
+		vars = append(vars, tx.Statement.Vars)
+	}))
+
+	// 名称作为参数传递，不拼接到语句中
+	tableName := "user'; DROP TABLE user; --"
+	impl := &postgresqlImpl{}
+	_, _ = getPostgresqlTableList(db, "")
+	_, _ = impl.getPrimaryKeys(db, "tenant", tableName)
+	_, _ = impl.getIndexList(db, "tenant", tableName)
+	_, _ = getTableList(db, "demo")
+	require.Len(t, sqlList, 4)
+	for _, sql := range sqlList {
+		assert.NotContains(t, sql, "DROP TABLE")
+		assert.NotContains(t, sql, "tenant")
+	}
+	assert.Contains(t, sqlList[0], "table_schema = $1")
+	assert.Equal(t, []any{"public"}, vars[0])
+	assert.Equal(t, []any{"tenant", tableName}, vars[1])
+	assert.Contains(t, sqlList[2], "NOT LIKE '%_pkey'")
+	assert.Equal(t, []any{"tenant", tableName}, vars[2])
+	assert.Equal(t, []any{"demo"}, vars[3])
+}
@@ -0,0 +1,842 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/morehao/golib/gutil"
+)
+
+// GenerateFromDDL 解析 CREATE TABLE 语句并生成模块代码，无需连接数据库，适用于 CI 等没有数据库凭据的环境，
+// 模板和文件跳过规则同 GenerateModule
+func GenerateFromDDL(sqlText string, cfg *ModuleCfg) (*GenerateModuleRes, error) {
+	moduleCfg, prepareErr := prepareModuleCfg(cfg)
+	if prepareErr != nil {
+		return nil, prepareErr
+	}
+	analysisRes, analysisErr := AnalysisModuleDDL(sqlText, moduleCfg)
+	if analysisErr != nil {
+		return nil, analysisErr
+	}
+	return genModule(NewGenerator(), moduleCfg, analysisRes)
+}
+
+// AnalysisModuleDDL 从 DDL 中解析 cfg.TableName 的表结构并分析模板，结果与 Generator.AnalysisModuleTpl 一致，
+// 支持 MySQL 和 PostgreSQL 方言的 CREATE TABLE、CREATE INDEX、ALTER TABLE ADD CONSTRAINT 以及 PostgreSQL 的 COMMENT ON COLUMN 语句
+func AnalysisModuleDDL(sqlText string, cfg *ModuleCfg) (*ModuleTplAnalysisRes, error) {
+	if err := (&generatorImpl{}).checkModuleCfg(cfg); err != nil {
+		return nil, err
+	}
+	cfg.format()
+
+	dialect := cfg.Dialect
+	if dialect == "" {
+		dialect = detectDDLDialect(sqlText)
+	}
+	if dialect != dbTypeMysql && dialect != dbTypePostgresql {
+		return nil, fmt.Errorf("unsupported ddl dialect: %s", dialect)
+	}
+	tables, parseErr := parseDDL(sqlText)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	table, ok := tables[strings.ToLower(cfg.TableName)]
+	if !ok {
+		return nil, fmt.Errorf("table %s not found in ddl", cfg.TableName)
+	}
+
+	modelFieldList := table.modelFields(dialect, cfg.ColumnTypeMap)
+	if err := applyModelFieldRules(cfg, modelFieldList); err != nil {
+		return nil, err
+	}
+	indexes, foreignKeys := buildTableIndexes(table.indexes), buildForeignKeys(table.foreignKeys)
+	fillFieldMeta(modelFieldList, indexes, foreignKeys)
+	pkRes, pkErr := analysisPK(cfg.TableName, modelFieldList, table.primaryKeys, table.indexes, cfg.NoPKStrategy)
+	if pkErr != nil {
+		return nil, pkErr
+	}
+
+	// 获取模板文件
+	tplAnalysisList, analysisErr := analysisTplFiles(cfg.CommonConfig, cfg.TableName)
+	if analysisErr != nil {
+		return nil, analysisErr
+	}
+
+	// 构造模板参数
+	var moduleAnalysisList []ModuleTplAnalysisItem
+	for _, v := range tplAnalysisList {
+		moduleAnalysisList = append(moduleAnalysisList, ModuleTplAnalysisItem{
+			TplAnalysisItem: v,
+			ModelFields:     modelFieldList,
+		})
+	}
+	res := &ModuleTplAnalysisRes{
+		Dialect:          dialect,
+		PackageName:      cfg.PackageName,
+		TableName:        cfg.TableName,
+		StructName:       cfg.NamingStrategy.StructName(cfg.TableName),
+		MigrationVersion: cfg.MigrationVersion,
+		PrimaryKeys:      pkRes.PrimaryKeys,
+		PKFields:         pkRes.PKFields,
+		IsCompositePK:    pkRes.IsCompositePK,
+		HasPK:            pkRes.HasPK,
+		Indexes:          indexes,
+		ForeignKeys:      foreignKeys,
+		TplAnalysisList:  moduleAnalysisList,
+	}
+	return res, nil
+}
+
+// detectDDLDialect 根据语句特征识别方言，无法识别时按 MySQL 处理
+func detectDDLDialect(sqlText string) string {
+	upper := strings.ToUpper(sqlText)
+	switch {
+	case strings.Contains(sqlText, "`"), strings.Contains(upper, "AUTO_INCREMENT"), strings.Contains(upper, "ENGINE"):
+		return dbTypeMysql
+	case strings.Contains(upper, "COMMENT ON"), strings.Contains(sqlText, "::"), strings.Contains(upper, "SERIAL"),
+		strings.Contains(upper, "TIMESTAMPTZ"), strings.Contains(upper, "JSONB"), strings.Contains(upper, "BYTEA"):
+		return dbTypePostgresql
+	}
+	return dbTypeMysql
+}
+
+// ---------------------------------------------------------------------------
+// 表结构
+// ---------------------------------------------------------------------------
+
+type ddlColumn struct {
+	name          string
+	dataType      string // 不含长度等参数的类型名，如 varchar、double precision、text[]
+	columnType    string // 完整类型，如 varchar(64)、bigint unsigned
+	notNull       bool
+	defaultValue  string
+	comment       string
+	autoIncrement bool
+}
+
+type ddlTable struct {
+	name        string
+	columns     []*ddlColumn
+	primaryKeys []string
+	indexes     []indexColumn
+	foreignKeys []foreignKeyColumn
+}
+
+func (t *ddlTable) column(name string) *ddlColumn {
+	for _, c := range t.columns {
+		if strings.EqualFold(c.name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (t *ddlTable) addIndex(name string, unique bool, columns []string) {
+	if name == "" {
+		name = fmt.Sprintf("%s_%s_idx", t.name, strings.Join(columns, "_"))
+	}
+	for i, column := range columns {
+		t.indexes = append(t.indexes, indexColumn{IndexName: name, ColumnName: column, IsUnique: unique, SeqInIndex: i + 1})
+	}
+}
+
+func (t *ddlTable) addForeignKey(name string, columns []string, refTable string, refColumns []string) {
+	if name == "" {
+		name = fmt.Sprintf("%s_%s_fkey", t.name, strings.Join(columns, "_"))
+	}
+	for i, column := range columns {
+		var refColumn string
+		if i < len(refColumns) {
+			refColumn = refColumns[i]
+		}
+		t.foreignKeys = append(t.foreignKeys, foreignKeyColumn{
+			ConstraintName: name,
+			ColumnName:     column,
+			RefTableName:   refTable,
+			RefColumnName:  refColumn,
+			SeqInKey:       i + 1,
+		})
+	}
+}
+
+// modelFields 按方言的默认类型映射转换为模型字段，ColumnKey 与 INFORMATION_SCHEMA 的规则一致
+func (t *ddlTable) modelFields(dialect string, columnTypeMap map[string]string) []ModelField {
+	defaultTypeMap := mysqlDefaultColumnTypeMap
+	if dialect == dbTypePostgresql {
+		defaultTypeMap = postgresqlDefaultColumnTypeMap
+	}
+	if len(columnTypeMap) > 0 {
+		defaultTypeMap = columnTypeMap
+	}
+	pkColumns := make(map[string]bool, len(t.primaryKeys))
+	for _, pk := range t.primaryKeys {
+		pkColumns[pk] = true
+	}
+
+	var fields []ModelField
+	for _, c := range t.columns {
+		item := ModelField{
+			FieldName:       gutil.SnakeToPascal(c.name),
+			ColumnName:      c.name,
+			ColumnType:      c.columnType,
+			IsNullable:      !c.notNull && !pkColumns[c.name],
+			DefaultValue:    c.defaultValue,
+			Comment:         c.comment,
+			IsAutoIncrement: c.autoIncrement,
+		}
+		if dialect == dbTypePostgresql {
+			item.FieldType = defaultTypeMap[postgresqlUdtName(c.dataType)]
+			if item.FieldType == "" {
+				item.FieldType = "string"
+			}
+		} else {
+			item.FieldType = defaultTypeMap[strings.Fields(c.dataType)[0]]
+		}
+		for _, index := range t.indexes {
+			if index.ColumnName != c.name {
+				continue
+			}
+			if item.IndexName == "" {
+				item.IndexName = index.IndexName
+				item.IsUniqueIndex = index.IsUnique
+			}
+			if dialect == dbTypeMysql && index.SeqInIndex == 1 && item.ColumnKey == "" {
+				item.ColumnKey = "MUL"
+				if index.IsUnique {
+					item.ColumnKey = "UNI"
+				}
+			}
+		}
+		if pkColumns[c.name] {
+			item.ColumnKey = ColumnKeyPRI
+		}
+		fields = append(fields, item)
+	}
+	return fields
+}
+
+// postgresqlUdtNames PostgreSQL 类型的 SQL 标准名到 udt_name 的映射
+var postgresqlUdtNames = map[string]string{
+	"integer":                     "int4",
+	"int":                         "int4",
+	"smallint":                    "int2",
+	"bigint":                      "int8",
+	"boolean":                     "bool",
+	"real":                        "float4",
+	"double precision":            "float8",
+	"decimal":                     "numeric",
+	"character varying":           "varchar",
+	"character":                   "bpchar",
+	"timestamp with time zone":    "timestamptz",
+	"timestamp without time zone": "timestamp",
+	"time with time zone":         "timetz",
+	"time without time zone":      "time",
+}
+
+// postgresqlUdtName 将 DDL 中的类型名转换为 information_schema 中的 udt_name，数组类型以 _ 为前缀
+func postgresqlUdtName(dataType string) string {
+	elemType, isArray := strings.CutSuffix(dataType, "[]")
+	if udtName, ok := postgresqlUdtNames[elemType]; ok {
+		elemType = udtName
+	}
+	if isArray {
+		return "_" + elemType
+	}
+	return elemType
+}
+
+// ---------------------------------------------------------------------------
+// 词法分析
+// ---------------------------------------------------------------------------
+
+type ddlTokenKind uint8
+
+const (
+	ddlTokenIdent  ddlTokenKind = iota // 关键字或未加引号的标识符
+	ddlTokenQuoted                     // 反引号或双引号包裹的标识符
+	ddlTokenString                     // 单引号字符串
+	ddlTokenSymbol                     // 标点符号
+)
+
+type ddlToken struct {
+	kind ddlTokenKind
+	text string
+}
+
+// is 判断是否为指定关键字，不区分大小写
+func (t ddlToken) is(keyword string) bool {
+	return t.kind == ddlTokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (t ddlToken) isSymbol(symbol string) bool {
+	return t.kind == ddlTokenSymbol && t.text == symbol
+}
+
+// isName 是否可作为表名、列名
+func (t ddlToken) isName() bool {
+	return t.kind == ddlTokenIdent || t.kind == ddlTokenQuoted
+}
+
+const ddlSymbols = "(),;.=[]:"
+
+func tokenizeDDL(sqlText string) ([]ddlToken, error) {
+	var tokens []ddlToken
+	for i := 0; i < len(sqlText); {
+		ch := sqlText[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '#' || strings.HasPrefix(sqlText[i:], "--"):
+			end := strings.IndexByte(sqlText[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+		case strings.HasPrefix(sqlText[i:], "/*"):
+			end := strings.Index(sqlText[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case ch == '\'' || ch == '`' || ch == '"':
+			text, next, err := readDDLQuoted(sqlText, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := ddlTokenQuoted
+			if ch == '\'' {
+				kind = ddlTokenString
+			}
+			tokens = append(tokens, ddlToken{kind: kind, text: text})
+			i = next
+		case strings.IndexByte(ddlSymbols, ch) >= 0:
+			tokens = append(tokens, ddlToken{kind: ddlTokenSymbol, text: string(ch)})
+			i++
+		default:
+			start := i
+			for i < len(sqlText) && !strings.ContainsRune(" \t\n\r'`\""+ddlSymbols, rune(sqlText[i])) {
+				i++
+			}
+			tokens = append(tokens, ddlToken{kind: ddlTokenIdent, text: sqlText[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+// readDDLQuoted 读取从 start 开始的引号内容，连续两个引号表示引号本身，单引号字符串支持反斜杠转义
+func readDDLQuoted(sqlText string, start int) (string, int, error) {
+	quote := sqlText[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(sqlText); i++ {
+		ch := sqlText[i]
+		switch {
+		case ch == '\\' && quote == '\'' && i+1 < len(sqlText):
+			i++
+			switch sqlText[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(sqlText[i])
+			}
+		case ch == quote && i+1 < len(sqlText) && sqlText[i+1] == quote:
+			sb.WriteByte(quote)
+			i++
+		case ch == quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted text starting at offset %d", start)
+}
+
+// ---------------------------------------------------------------------------
+// 语法分析
+// ---------------------------------------------------------------------------
+
+// parseDDL 解析 DDL 中的表结构，返回以小写表名为 key 的表，忽略无关语句
+func parseDDL(sqlText string) (map[string]*ddlTable, error) {
+	tokens, err := tokenizeDDL(sqlText)
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string]*ddlTable)
+	var pending [][]ddlToken // CREATE INDEX、ALTER TABLE、COMMENT ON 可能位于 CREATE TABLE 之前，建表后统一处理
+	for _, stmt := range splitDDLStatements(tokens) {
+		if len(stmt) < 2 {
+			continue
+		}
+		switch {
+		case stmt[0].is("CREATE") && containsKeywordBefore(stmt, "TABLE", "("):
+			table, parseErr := parseCreateTable(stmt)
+			if parseErr != nil {
+				return nil, parseErr
+			}
+			tables[strings.ToLower(table.name)] = table
+		case stmt[0].is("CREATE") && containsKeywordBefore(stmt, "INDEX", "("), stmt[0].is("COMMENT"),
+			stmt[0].is("ALTER") && stmt[1].is("TABLE"):
+			pending = append(pending, stmt)
+		}
+	}
+	for _, stmt := range pending {
+		switch {
+		case stmt[0].is("COMMENT"):
+			applyColumnComment(stmt, tables)
+		case stmt[0].is("ALTER"):
+			applyAlterTable(stmt, tables)
+		default:
+			applyCreateIndex(stmt, tables)
+		}
+	}
+	// REFERENCES 省略引用列时引用目标表的主键
+	for _, table := range tables {
+		for i, fk := range table.foreignKeys {
+			if fk.RefColumnName != "" {
+				continue
+			}
+			if refTable, ok := tables[strings.ToLower(fk.RefTableName)]; ok && fk.SeqInKey <= len(refTable.primaryKeys) {
+				table.foreignKeys[i].RefColumnName = refTable.primaryKeys[fk.SeqInKey-1]
+			}
+		}
+	}
+	return tables, nil
+}
+
+func splitDDLStatements(tokens []ddlToken) [][]ddlToken {
+	var (
+		stmts [][]ddlToken
+		start int
+	)
+	for i, token := range tokens {
+		if token.isSymbol(";") {
+			stmts = append(stmts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(stmts, tokens[start:])
+}
+
+// containsKeywordBefore 判断 stop 符号之前是否出现 keyword
+func containsKeywordBefore(tokens []ddlToken, keyword, stop string) bool {
+	for _, token := range tokens {
+		if token.isSymbol(stop) {
+			return false
+		}
+		if token.is(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseQualifiedName 解析 schema.table 形式的名称，返回各部分和下一个 token 的位置
+func parseQualifiedName(tokens []ddlToken, i int) ([]string, int) {
+	var parts []string
+	for i < len(tokens) && tokens[i].isName() {
+		parts = append(parts, tokens[i].text)
+		i++
+		if i < len(tokens) && tokens[i].isSymbol(".") {
+			i++
+			continue
+		}
+		break
+	}
+	return parts, i
+}
+
+// closingParen 返回与 tokens[open] 处左括号匹配的右括号位置，不匹配时返回 -1
+func closingParen(tokens []ddlToken, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch {
+		case tokens[i].isSymbol("("):
+			depth++
+		case tokens[i].isSymbol(")"):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel 按最外层的逗号切分
+func splitTopLevel(tokens []ddlToken) [][]ddlToken {
+	var (
+		parts [][]ddlToken
+		start int
+		depth int
+	)
+	for i, token := range tokens {
+		switch {
+		case token.isSymbol("("):
+			depth++
+		case token.isSymbol(")"):
+			depth--
+		case token.isSymbol(",") && depth == 0:
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+// parseIndexColumns 解析 tokens 中第一个括号内的列名，忽略前缀长度和排序方向
+func parseIndexColumns(tokens []ddlToken) []string {
+	for i, token := range tokens {
+		if !token.isSymbol("(") {
+			continue
+		}
+		end := closingParen(tokens, i)
+		if end < 0 {
+			return nil
+		}
+		var columns []string
+		for _, part := range splitTopLevel(tokens[i+1 : end]) {
+			if len(part) > 0 && part[0].isName() {
+				columns = append(columns, part[0].text)
+			}
+		}
+		return columns
+	}
+	return nil
+}
+
+func parseCreateTable(stmt []ddlToken) (*ddlTable, error) {
+	i := 0
+	for !stmt[i].is("TABLE") {
+		i++
+	}
+	i++
+	if i+2 < len(stmt) && stmt[i].is("IF") && stmt[i+1].is("NOT") && stmt[i+2].is("EXISTS") {
+		i += 3
+	}
+	nameParts, next := parseQualifiedName(stmt, i)
+	if len(nameParts) == 0 {
+		return nil, fmt.Errorf("invalid create table statement: missing table name")
+	}
+	table := &ddlTable{name: nameParts[len(nameParts)-1]}
+	if next >= len(stmt) || !stmt[next].isSymbol("(") {
+		return nil, fmt.Errorf("table %s: column definitions are required", table.name)
+	}
+	end := closingParen(stmt, next)
+	if end < 0 {
+		return nil, fmt.Errorf("table %s: unbalanced parentheses", table.name)
+	}
+
+	var constraints [][]ddlToken
+	for _, def := range splitTopLevel(stmt[next+1 : end]) {
+		if len(def) == 0 {
+			continue
+		}
+		if isTableConstraint(def[0]) {
+			constraints = append(constraints, def)
+			continue
+		}
+		column, columnErr := parseColumn(table, def)
+		if columnErr != nil {
+			return nil, columnErr
+		}
+		table.columns = append(table.columns, column)
+	}
+	// 表级约束可能引用后定义的列，列解析完成后再处理
+	for _, def := range constraints {
+		parseTableConstraint(table, def)
+	}
+	for _, pk := range table.primaryKeys {
+		if table.column(pk) == nil {
+			return nil, fmt.Errorf("table %s: primary key column %s not defined", table.name, pk)
+		}
+	}
+	return table, nil
+}
+
+func isTableConstraint(token ddlToken) bool {
+	for _, keyword := range []string{"PRIMARY", "UNIQUE", "KEY", "INDEX", "CONSTRAINT", "FOREIGN", "CHECK", "FULLTEXT", "SPATIAL", "EXCLUDE"} {
+		if token.is(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTableConstraint(table *ddlTable, def []ddlToken) {
+	var name string
+	if def[0].is("CONSTRAINT") && len(def) > 2 {
+		name = def[1].text
+		def = def[2:]
+	}
+	switch {
+	case def[0].is("PRIMARY"):
+		table.primaryKeys = append(table.primaryKeys, parseIndexColumns(def)...)
+	case def[0].is("UNIQUE"), def[0].is("KEY"), def[0].is("INDEX"):
+		rest := def[1:]
+		if def[0].is("UNIQUE") && len(rest) > 0 && (rest[0].is("KEY") || rest[0].is("INDEX")) {
+			rest = rest[1:]
+		}
+		if len(rest) > 0 && rest[0].isName() && !rest[0].is("USING") {
+			name = rest[0].text
+		}
+		table.addIndex(name, def[0].is("UNIQUE"), parseIndexColumns(rest))
+	case def[0].is("FOREIGN"):
+		for i, token := range def {
+			if !token.is("REFERENCES") {
+				continue
+			}
+			refTable, refColumns, _ := parseReferences(def, i+1)
+			if refTable != "" {
+				table.addForeignKey(name, parseIndexColumns(def[:i]), refTable, refColumns)
+			}
+			break
+		}
+	}
+}
+
+// parseReferences 解析 REFERENCES 后的 [schema.]table [(columns)]，返回引用的表、列和下一个 token 的位置
+func parseReferences(def []ddlToken, i int) (string, []string, int) {
+	nameParts, next := parseQualifiedName(def, i)
+	if len(nameParts) == 0 {
+		return "", nil, i
+	}
+	var refColumns []string
+	if next < len(def) && def[next].isSymbol("(") {
+		if end := closingParen(def, next); end > 0 {
+			refColumns = parseIndexColumns(def[next : end+1])
+			next = end + 1
+		}
+	}
+	return nameParts[len(nameParts)-1], refColumns, next
+}
+
+// columnConstraintKeywords 出现后表示类型定义结束的关键字
+var columnConstraintKeywords = []string{"NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "KEY", "AUTO_INCREMENT",
+	"COMMENT", "REFERENCES", "CHECK", "COLLATE", "CHARSET", "GENERATED", "AS", "ON", "CONSTRAINT", "STORED", "VIRTUAL"}
+
+func isColumnConstraint(tokens []ddlToken, i int) bool {
+	if tokens[i].is("CHARACTER") && i+1 < len(tokens) && tokens[i+1].is("SET") {
+		return true
+	}
+	for _, keyword := range columnConstraintKeywords {
+		if tokens[i].is(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseColumn(table *ddlTable, def []ddlToken) (*ddlColumn, error) {
+	if !def[0].isName() || len(def) < 2 {
+		return nil, fmt.Errorf("table %s: invalid column definition near %q", table.name, def[0].text)
+	}
+	column := &ddlColumn{name: def[0].text}
+
+	// 类型：多个单词（如 double precision、timestamp with time zone），可带括号参数和数组后缀
+	var dataType, columnType strings.Builder
+	i := 1
+	for i < len(def) && !isColumnConstraint(def, i) {
+		switch {
+		case def[i].isSymbol("("):
+			end := closingParen(def, i)
+			if end < 0 {
+				return nil, fmt.Errorf("table %s: column %s has unbalanced parentheses", table.name, column.name)
+			}
+			columnType.WriteString("(" + joinDDLTokens(def[i+1:end]) + ")")
+			i = end + 1
+		case def[i].isSymbol("[") && i+1 < len(def) && def[i+1].isSymbol("]"):
+			dataType.WriteString("[]")
+			columnType.WriteString("[]")
+			i += 2
+		case def[i].kind == ddlTokenIdent:
+			word := strings.ToLower(def[i].text)
+			if dataType.Len() > 0 {
+				dataType.WriteByte(' ')
+				columnType.WriteByte(' ')
+			}
+			dataType.WriteString(word)
+			columnType.WriteString(word)
+			i++
+		default:
+			i++
+		}
+	}
+	if dataType.Len() == 0 {
+		return nil, fmt.Errorf("table %s: column %s has no type", table.name, column.name)
+	}
+	column.dataType = dataType.String()
+	column.columnType = columnType.String()
+	if strings.HasSuffix(column.dataType, "serial") {
+		column.notNull = true
+		column.autoIncrement = true
+	}
+
+	for i < len(def) {
+		token := def[i]
+		i++
+		switch {
+		case token.is("NOT") && i < len(def) && def[i].is("NULL"):
+			column.notNull = true
+			i++
+		case token.is("DEFAULT") && i < len(def):
+			var value string
+			value, i = parseDefaultValue(def, i)
+			column.defaultValue = value
+		case token.is("PRIMARY"):
+			table.primaryKeys = append(table.primaryKeys, column.name)
+			if i < len(def) && def[i].is("KEY") {
+				i++
+			}
+		case token.is("UNIQUE"):
+			table.addIndex(column.name, true, []string{column.name})
+			if i < len(def) && def[i].is("KEY") {
+				i++
+			}
+		case token.is("AUTO_INCREMENT"):
+			column.autoIncrement = true
+		case token.is("GENERATED"):
+			// GENERATED ALWAYS|BY DEFAULT AS IDENTITY，生成列 GENERATED ALWAYS AS (expr) 不视为自增
+			for j := i; j < len(def) && !def[j].isSymbol("("); j++ {
+				if def[j].is("IDENTITY") {
+					column.autoIncrement = true
+					column.notNull = true
+					break
+				}
+			}
+		case token.is("REFERENCES"):
+			refTable, refColumns, next := parseReferences(def, i)
+			if refTable != "" {
+				table.addForeignKey("", []string{column.name}, refTable, refColumns)
+			}
+			i = next
+		case token.is("COMMENT") && i < len(def) && def[i].kind == ddlTokenString:
+			column.comment = def[i].text
+			i++
+		case token.isSymbol("("):
+			// CHECK、GENERATED AS、REFERENCES 等约束的表达式
+			if end := closingParen(def, i-1); end > 0 {
+				i = end + 1
+			}
+		}
+	}
+	return column, nil
+}
+
+// parseDefaultValue 解析 DEFAULT 后的表达式，字符串常量返回其内容，NULL 返回空字符串
+func parseDefaultValue(def []ddlToken, i int) (string, int) {
+	start := i
+	if def[i].isSymbol("(") {
+		end := closingParen(def, i)
+		if end < 0 {
+			return "", len(def)
+		}
+		return joinDDLTokens(def[i+1 : end]), end + 1
+	}
+	i++
+	if i < len(def) && def[i].isSymbol("(") {
+		if end := closingParen(def, i); end > 0 {
+			i = end + 1
+		}
+	}
+	value := def[start].text
+	if def[start].is("NULL") {
+		value = ""
+	} else if def[start].kind != ddlTokenString {
+		value = joinDDLTokens(def[start:i])
+	}
+	// PostgreSQL 类型转换，如 'draft'::character varying
+	for i+1 < len(def) && def[i].isSymbol(":") && def[i+1].isSymbol(":") {
+		i += 2
+		for i < len(def) && def[i].kind == ddlTokenIdent && !isColumnConstraint(def, i) {
+			i++
+		}
+	}
+	return value, i
+}
+
+// joinDDLTokens 还原 token 序列的文本，用于类型参数和默认值表达式，只在两个非符号 token 之间补空格
+func joinDDLTokens(tokens []ddlToken) string {
+	var sb strings.Builder
+	for i, token := range tokens {
+		if i > 0 && token.kind != ddlTokenSymbol && tokens[i-1].kind != ddlTokenSymbol {
+			sb.WriteByte(' ')
+		}
+		if token.kind == ddlTokenString {
+			sb.WriteString("'" + strings.ReplaceAll(token.text, "'", "''") + "'")
+			continue
+		}
+		sb.WriteString(token.text)
+	}
+	return sb.String()
+}
+
+// applyCreateIndex 处理 CREATE [UNIQUE] INDEX name ON table (columns)
+func applyCreateIndex(stmt []ddlToken, tables map[string]*ddlTable) {
+	onIdx := -1
+	for i, token := range stmt {
+		if token.is("ON") {
+			onIdx = i
+			break
+		}
+	}
+	if onIdx < 0 {
+		return
+	}
+	i := onIdx + 1
+	if i < len(stmt) && stmt[i].is("ONLY") {
+		i++
+	}
+	nameParts, _ := parseQualifiedName(stmt, i)
+	if len(nameParts) == 0 {
+		return
+	}
+	table, ok := tables[strings.ToLower(nameParts[len(nameParts)-1])]
+	if !ok {
+		return
+	}
+	var name string
+	if prev := stmt[onIdx-1]; prev.isName() && !prev.is("INDEX") && !prev.is("EXISTS") && !prev.is("CONCURRENTLY") {
+		name = prev.text
+	}
+	table.addIndex(name, stmt[1].is("UNIQUE"), parseIndexColumns(stmt[onIdx:]))
+}
+
+// applyAlterTable 处理 ALTER TABLE [ONLY] table ADD [CONSTRAINT name] ...，pg_dump 导出的主键、外键均为此形式
+func applyAlterTable(stmt []ddlToken, tables map[string]*ddlTable) {
+	i := 2
+	if i < len(stmt) && stmt[i].is("ONLY") {
+		i++
+	}
+	nameParts, next := parseQualifiedName(stmt, i)
+	if len(nameParts) == 0 {
+		return
+	}
+	table, ok := tables[strings.ToLower(nameParts[len(nameParts)-1])]
+	if !ok {
+		return
+	}
+	for _, action := range splitTopLevel(stmt[next:]) {
+		if len(action) > 1 && action[0].is("ADD") && isTableConstraint(action[1]) {
+			parseTableConstraint(table, action[1:])
+		}
+	}
+}
+
+// applyColumnComment 处理 PostgreSQL 的 COMMENT ON COLUMN [schema.]table.column IS 'comment'
+func applyColumnComment(stmt []ddlToken, tables map[string]*ddlTable) {
+	if len(stmt) < 5 || !stmt[1].is("ON") || !stmt[2].is("COLUMN") {
+		return
+	}
+	nameParts, next := parseQualifiedName(stmt, 3)
+	if len(nameParts) < 2 || next+1 >= len(stmt) || !stmt[next].is("IS") || stmt[next+1].kind != ddlTokenString {
+		return
+	}
+	table, ok := tables[strings.ToLower(nameParts[len(nameParts)-2])]
+	if !ok {
+		return
+	}
+	if column := table.column(nameParts[len(nameParts)-1]); column != nil {
+		column.comment = stmt[next+1].text
+	}
+}
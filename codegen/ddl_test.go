@@ -0,0 +1,222 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mysqlTestDDL = `
+-- 用户表
+CREATE TABLE IF NOT EXISTS ` + "`user`" + ` (
+  ` + "`id`" + ` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  ` + "`name`" + ` varchar(64) NOT NULL DEFAULT '' COMMENT '用户''名',
+  ` + "`age`" + ` int(11) DEFAULT NULL,
+  ` + "`amount`" + ` decimal(10,2) NOT NULL DEFAULT '0.00',
+  ` + "`status`" + ` enum('on','off') CHARACTER SET utf8mb4 NOT NULL DEFAULT 'on',
+  ` + "`created_at`" + ` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  ` + "`deleted_at`" + ` datetime DEFAULT NULL, /* 软删除 */
+  PRIMARY KEY (` + "`id`" + `),
+  UNIQUE KEY ` + "`uk_name`" + ` (` + "`name`" + `(32)),
+  KEY ` + "`idx_age_status`" + ` (` + "`age`" + `, ` + "`status`" + `)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户表';
+
+CREATE TABLE ` + "`order`" + ` (` + "`id`" + ` int PRIMARY KEY);
+`
+
+const postgresTestDDL = `
+CREATE TABLE public.user_role (
+    user_id bigint NOT NULL,
+    role_id integer NOT NULL,
+    tags text[],
+    remark character varying(255) DEFAULT 'none'::character varying,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT user_role_pkey PRIMARY KEY (user_id, role_id)
+);
+CREATE UNIQUE INDEX user_role_remark_key ON public.user_role USING btree (remark);
+COMMENT ON COLUMN public.user_role.role_id IS '角色ID';
+
+CREATE TABLE "audit_log" (
+    "serial_no" bigserial,
+    "trace_id" varchar(64) NOT NULL UNIQUE,
+    "payload" jsonb
+);
+`
+
+func TestParseMysqlDDL(t *testing.T) {
+	tables, err := parseDDL(mysqlTestDDL)
+	require.Nil(t, err)
+	require.Len(t, tables, 2)
+
+	table := tables["user"]
+	fields := table.modelFields(dbTypeMysql, nil)
+	require.Len(t, fields, 7)
+	assert.Equal(t, ModelField{FieldName: "Id", FieldType: "uint", ColumnName: "id", ColumnType: "bigint unsigned", ColumnKey: ColumnKeyPRI, Comment: "主键", IsAutoIncrement: true}, fields[0])
+	assert.Equal(t, ModelField{FieldName: "Name", FieldType: "string", ColumnName: "name", ColumnType: "varchar(64)", ColumnKey: "UNI",
+		Comment: "用户'名", IndexName: "uk_name", IsUniqueIndex: true}, fields[1])
+	assert.Equal(t, "int32", fields[2].FieldType)
+	assert.True(t, fields[2].IsNullable)
+	assert.Equal(t, "MUL", fields[2].ColumnKey)
+	assert.Equal(t, "decimal(10,2)", fields[3].ColumnType)
+	assert.Equal(t, "0.00", fields[3].DefaultValue)
+	assert.Equal(t, "enum('on','off')", fields[4].ColumnType)
+	assert.False(t, fields[4].IsNullable)
+	assert.Equal(t, "CURRENT_TIMESTAMP(3)", fields[5].DefaultValue)
+	assert.Equal(t, "time.Time", fields[6].FieldType)
+	assert.Equal(t, []string{"id"}, table.primaryKeys)
+}
+
+func TestParsePostgresDDL(t *testing.T) {
+	assert.Equal(t, dbTypePostgresql, detectDDLDialect(postgresTestDDL))
+	assert.Equal(t, dbTypeMysql, detectDDLDialect(mysqlTestDDL))
+
+	tables, err := parseDDL(postgresTestDDL)
+	require.Nil(t, err)
+
+	table := tables["user_role"]
+	assert.Equal(t, []string{"user_id", "role_id"}, table.primaryKeys)
+	fields := table.modelFields(dbTypePostgresql, nil)
+	require.Len(t, fields, 5)
+	assert.Equal(t, "int64", fields[0].FieldType)
+	assert.Equal(t, "int32", fields[1].FieldType)
+	assert.Equal(t, "角色ID", fields[1].Comment)
+	assert.Equal(t, "[]string", fields[2].FieldType)
+	assert.Equal(t, "character varying(255)", fields[3].ColumnType)
+	assert.Equal(t, "none", fields[3].DefaultValue)
+	assert.Equal(t, "user_role_remark_key", fields[3].IndexName)
+	assert.True(t, fields[3].IsUniqueIndex)
+	assert.Equal(t, "time.Time", fields[4].FieldType)
+	assert.Equal(t, "now()", fields[4].DefaultValue)
+	assert.False(t, fields[4].IsNullable)
+
+	// 无主键时回退到列均非空的唯一索引
+	logFields := tables["audit_log"].modelFields(dbTypePostgresql, nil)
+	assert.False(t, logFields[0].IsNullable)
+	assert.Equal(t, "json.RawMessage", logFields[2].FieldType)
+	pkRes, err := analysisPK("audit_log", logFields, nil, tables["audit_log"].indexes, NoPKStrategyUniqueIndex)
+	require.Nil(t, err)
+	require.Len(t, pkRes.PKFields, 1)
+	assert.Equal(t, "trace_id", pkRes.PKFields[0].ColumnName)
+}
+
+func TestParseDDLError(t *testing.T) {
+	_, err := parseDDL("CREATE TABLE t (id int, PRIMARY KEY (uid))")
+	assert.ErrorContains(t, err, "primary key column uid not defined")
+	_, err = parseDDL("CREATE TABLE t (id int")
+	assert.ErrorContains(t, err, "unbalanced parentheses")
+	_, err = parseDDL("CREATE TABLE t (name varchar(8) DEFAULT 'x)")
+	assert.ErrorContains(t, err, "unterminated quoted text")
+}
+
+func TestGenerateFromDDL(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     rootDir,
+		},
+		TableName:  "user_role",
+		ImportPath: "example.com/demo",
+	}
+	res, err := GenerateFromDDL(postgresTestDDL, cfg)
+	require.Nil(t, err)
+	assert.Len(t, res.GeneratedFiles, 6)
+
+	model, err := os.ReadFile(filepath.Join(rootDir, "model", "user_role.go"))
+	require.Nil(t, err)
+	assert.Contains(t, string(model), "UserId    int64")
+	assert.Contains(t, string(model), "RoleId    int32     `gorm:\"column:role_id;primaryKey\"` // 角色ID")
+	dao, err := os.ReadFile(filepath.Join(rootDir, "dao", "daouser", "user_role.go"))
+	require.Nil(t, err)
+	assert.Contains(t, string(dao), `Where("user_id = ? AND role_id = ?", userId, roleId)`)
+
+	cfg.TableName = "missing"
+	_, err = GenerateFromDDL(postgresTestDDL, cfg)
+	assert.ErrorContains(t, err, "table missing not found in ddl")
+}
+
+func TestGenerateFromDDLDryRun(t *testing.T) {
+	rootDir := t.TempDir()
+	modelPath := filepath.Join(rootDir, "model", "user_role.go")
+	require.Nil(t, os.MkdirAll(filepath.Dir(modelPath), 0755))
+	require.Nil(t, os.WriteFile(modelPath, []byte("package model\n"), 0644))
+
+	res, err := GenerateFromDDL(postgresTestDDL, &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     rootDir,
+		},
+		TableName:  "user_role",
+		ImportPath: "example.com/demo",
+		DryRun:     true,
+	})
+	require.Nil(t, err)
+	assert.Len(t, res.GeneratedFiles, 5)
+	assert.Equal(t, []string{modelPath}, res.SkippedFiles)
+	require.Len(t, res.Files, 6)
+	for _, file := range res.Files {
+		assert.Equal(t, file.Path == modelPath, file.Exist, file.Path)
+		assert.Contains(t, string(file.Content), "package ", file.Path)
+		if !file.Exist {
+			assert.NoFileExists(t, file.Path)
+		}
+	}
+
+	// 已存在的文件不被修改
+	model, err := os.ReadFile(modelPath)
+	require.Nil(t, err)
+	assert.Equal(t, "package model\n", string(model))
+}
+
+func TestAnalysisModuleDDLTableMeta(t *testing.T) {
+	ddl := `
+CREATE TABLE dept (id bigint PRIMARY KEY);
+CREATE TABLE account (
+    id integer GENERATED ALWAYS AS IDENTITY,
+    tenant_id bigint NOT NULL,
+    dept_id bigint REFERENCES public.dept,
+    email varchar(128) NOT NULL,
+    org_id bigint,
+    org_code varchar(32),
+    PRIMARY KEY (id),
+    CONSTRAINT account_org_fkey FOREIGN KEY (org_id, org_code) REFERENCES org (id, code) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX account_tenant_email_key ON account (tenant_id, email);
+CREATE INDEX account_email_idx ON account (email);
+ALTER TABLE ONLY public.account ADD CONSTRAINT account_tenant_fkey FOREIGN KEY (tenant_id) REFERENCES tenant(id);
+`
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "account",
+			RootDir:     t.TempDir(),
+			TplFS:       DefaultModuleTplFS(),
+		},
+		TableName: "account",
+		Dialect:   dbTypePostgresql,
+	}
+	res, err := AnalysisModuleDDL(ddl, cfg)
+	require.Nil(t, err)
+
+	assert.Equal(t, []TableIndex{
+		{Name: "account_email_idx", Columns: []string{"email"}},
+		{Name: "account_tenant_email_key", Columns: []string{"tenant_id", "email"}, IsUnique: true},
+	}, res.Indexes)
+	assert.Equal(t, []ForeignKey{
+		{Name: "account_dept_id_fkey", Columns: []string{"dept_id"}, RefTable: "dept", RefColumns: []string{"id"}},
+		{Name: "account_org_fkey", Columns: []string{"org_id", "org_code"}, RefTable: "org", RefColumns: []string{"id", "code"}},
+		{Name: "account_tenant_fkey", Columns: []string{"tenant_id"}, RefTable: "tenant", RefColumns: []string{"id"}},
+	}, res.ForeignKeys)
+
+	fields := res.TplAnalysisList[0].ModelFields
+	assert.True(t, fields[0].IsAutoIncrement)
+	assert.False(t, fields[1].IsAutoIncrement)
+	assert.Equal(t, "uniqueIndex:account_tenant_email_key,priority:1", fields[1].IndexTag)
+	assert.Equal(t, "account_tenant_fkey", fields[1].ForeignKey.Name)
+	assert.Equal(t, "dept", fields[2].ForeignKey.RefTable)
+	assert.Equal(t, "index:account_email_idx;uniqueIndex:account_tenant_email_key,priority:2", fields[3].IndexTag)
+	assert.True(t, fields[5].ForeignKey.IsComposite())
+	assert.Same(t, fields[4].ForeignKey, fields[5].ForeignKey)
+}
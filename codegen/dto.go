@@ -0,0 +1,856 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DTOCfg 根据 JSON 示例或 JSON Schema 生成 DTO 结构体的配置
+type DTOCfg struct {
+	PackageName string // 包名
+	StructName  string // 顶层结构体名，JSON Schema 设置了 title 时可为空，嵌套对象命名为 父结构体名+字段名，数组元素再加 Item 后缀
+	// BindingTag 校验标签名，默认 binding，供 gin 绑定时校验，直接使用 go-playground/validator 时设为 validate
+	BindingTag string
+	FormTag    bool // 是否同时生成 form 标签，用于 query、表单参数绑定
+	// Required 仅 JSON 示例使用，为示例中出现且不为 null 的字段添加 required 校验，数组中的对象只有每个元素都包含的字段才视为必填
+	Required bool
+	// NamingStrategy 字段命名规则，JSON 键先转为蛇形再按规则转换，如 userId、user_id 在设置 ID 缩写后均为 UserID
+	NamingStrategy *NamingStrategy
+}
+
+const defaultDTOBindingTag = "binding"
+
+// GenerateDTOFromJSON 根据 JSON 示例推断字段类型并生成 DTO 结构体，返回格式化后的 Go 代码，示例也可以是 YAML。
+// 对象生成结构体，整数为 int64，小数为 float64，RFC3339 格式的字符串为 time.Time，null 和空数组的元素为 any，
+// 数组中的多个对象合并为一个结构体；顶层为数组时以元素生成顶层结构体
+func GenerateDTOFromJSON(sample []byte, cfg *DTOCfg) ([]byte, error) {
+	if err := checkDTOCfg(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.StructName == "" {
+		return nil, fmt.Errorf("structName is required")
+	}
+	root, parseErr := parseDTOInput(sample)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	shape := newSampleShape(root)
+	if shape.kind == sampleKindArray {
+		shape = shape.elem
+	}
+	if shape == nil || shape.kind != sampleKindObject {
+		return nil, fmt.Errorf("json sample should be an object or an array of objects")
+	}
+	b := newDTOBuilder(cfg)
+	b.sampleStruct(cfg.StructName, shape)
+	return b.render()
+}
+
+// GenerateDTOFromJSONSchema 根据 JSON Schema 生成 DTO 结构体，返回格式化后的 Go 代码，schema 可以是 JSON 或 YAML。
+// 支持 properties、required、items、additionalProperties、enum、format、长度和取值范围约束，以及指向同一文档的 $ref
+// （如 #/definitions/Address、#/$defs/Address、#/components/schemas/Address）。
+// 约束转换为校验标签：required、min/max、gte/lte、gt/lt、oneof、email、url、uuid 等，数组元素的约束通过 dive 校验；
+// 非必填的对象字段和可为 null 的字段生成指针，必填的数值和布尔字段也生成指针，避免零值无法通过 required 校验
+func GenerateDTOFromJSONSchema(schema []byte, cfg *DTOCfg) ([]byte, error) {
+	if err := checkDTOCfg(cfg); err != nil {
+		return nil, err
+	}
+	root, parseErr := parseDTOInput(schema)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("json schema should be an object")
+	}
+	b := newDTOBuilder(cfg)
+	b.schemaRoot = root
+	structName := cfg.StructName
+	if structName == "" {
+		title := schemaString(root, "title")
+		if title == "" {
+			return nil, fmt.Errorf("structName is required when schema has no title")
+		}
+		structName = b.structName(title)
+	}
+	target, resolveErr := b.resolveRef(root)
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	if schemaNodeType(target) != "object" {
+		return nil, fmt.Errorf("json schema root should be an object")
+	}
+	if err := b.schemaStruct(structName, target); err != nil {
+		return nil, err
+	}
+	return b.render()
+}
+
+func checkDTOCfg(cfg *DTOCfg) error {
+	if cfg == nil {
+		return fmt.Errorf("cfg is nil")
+	}
+	if cfg.PackageName == "" {
+		return fmt.Errorf("packageName is required")
+	}
+	return nil
+}
+
+// parseDTOInput 解析 JSON 或 YAML，JSON 按原始顺序逐个读取 token，避免 YAML 解析器不接受制表符缩进
+func parseDTOInput(data []byte) (*yaml.Node, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("input is empty")
+	}
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+		node, err := decodeJSONNode(dec)
+		if err != nil {
+			return nil, fmt.Errorf("parse json: %w", err)
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, fmt.Errorf("parse json: unexpected data after top-level value")
+		}
+		return node, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(trimmed, &doc); err != nil {
+		return nil, fmt.Errorf("parse yaml: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("input is empty")
+	}
+	return doc.Content[0], nil
+}
+
+func decodeJSONNode(dec *json.Decoder) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if v == '{' {
+			node.Kind, node.Tag = yaml.MappingNode, "!!map"
+		}
+		for dec.More() {
+			if node.Kind == yaml.MappingNode {
+				keyToken, keyErr := dec.Token()
+				if keyErr != nil {
+					return nil, keyErr
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: keyToken.(string)})
+			}
+			child, childErr := decodeJSONNode(dec)
+			if childErr != nil {
+				return nil, childErr
+			}
+			node.Content = append(node.Content, child)
+		}
+		// 读取结束符
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	default:
+		return nil, fmt.Errorf("unexpected json token %v", token)
+	}
+}
+
+// dtoStruct 生成的结构体
+type dtoStruct struct {
+	Name    string
+	Comment string
+	Fields  []dtoField
+}
+
+// dtoField 生成的结构体字段
+type dtoField struct {
+	Name     string
+	Type     string
+	JSONName string
+	Required bool
+	Rules    []string // 不含 required、omitempty 的校验规则
+	Comment  string
+}
+
+type dtoBuilder struct {
+	cfg        *DTOCfg
+	structs    []*dtoStruct
+	names      map[string]bool
+	imports    map[string]bool
+	schemaRoot *yaml.Node
+	refs       map[string]string // $ref 对应的结构体名
+	building   map[string]bool   // 正在生成的 $ref，自引用时使用指针
+}
+
+func newDTOBuilder(cfg *DTOCfg) *dtoBuilder {
+	return &dtoBuilder{
+		cfg:      cfg,
+		names:    make(map[string]bool),
+		imports:  make(map[string]bool),
+		refs:     make(map[string]string),
+		building: make(map[string]bool),
+	}
+}
+
+// addStruct 按名称去重后登记结构体，先登记的结构体先输出
+func (b *dtoBuilder) addStruct(name, comment string) *dtoStruct {
+	unique := name
+	for i := 2; b.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	b.names[unique] = true
+	s := &dtoStruct{Name: unique, Comment: comment}
+	b.structs = append(b.structs, s)
+	return s
+}
+
+// structName 将标题、定义名等转换为结构体名
+func (b *dtoBuilder) structName(name string) string {
+	return dtoIdentifier(b.cfg.NamingStrategy.StructName(dtoSnake(name)))
+}
+
+// fieldNames JSON 键对应的字段名，同一结构体内重名时添加数字后缀
+func (b *dtoBuilder) fieldNames(keys []string) []string {
+	names := make([]string, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		name := dtoIdentifier(b.cfg.NamingStrategy.FieldName(dtoSnake(key)))
+		unique := name
+		for n := 2; seen[unique]; n++ {
+			unique = name + strconv.Itoa(n)
+		}
+		seen[unique] = true
+		names[i] = unique
+	}
+	return names
+}
+
+// dtoSnake 将 JSON 键转为蛇形，如 userID、user-id 均转为 user_id，非字母数字的字符视为分隔符
+func dtoSnake(key string) string {
+	runes := []rune(key)
+	var sb strings.Builder
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			sb.WriteByte('_')
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	parts := strings.FieldsFunc(sb.String(), func(r rune) bool { return r == '_' })
+	return strings.Join(parts, "_")
+}
+
+// dtoIdentifier 保证名称是导出的 Go 标识符，如以数字开头的键 2fa 转为 Field2fa
+func dtoIdentifier(name string) string {
+	if name == "" {
+		return "Field"
+	}
+	first := []rune(name)[0]
+	if !unicode.IsLetter(first) {
+		return "Field" + name
+	}
+	if !unicode.IsUpper(first) {
+		return "X" + name
+	}
+	return name
+}
+
+func (b *dtoBuilder) render() ([]byte, error) {
+	bindingTag := b.cfg.BindingTag
+	if bindingTag == "" {
+		bindingTag = defaultDTOBindingTag
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s\n", b.cfg.PackageName)
+	if b.imports["time"] {
+		buf.WriteString("\nimport \"time\"\n")
+	}
+	for _, s := range b.structs {
+		buf.WriteByte('\n')
+		if s.Comment != "" {
+			fmt.Fprintf(&buf, "// %s %s\n", s.Name, s.Comment)
+		}
+		fmt.Fprintf(&buf, "type %s struct {\n", s.Name)
+		for _, f := range s.Fields {
+			jsonName := f.JSONName
+			if !f.Required {
+				jsonName += ",omitempty"
+			}
+			tag := fmt.Sprintf("json:%q", jsonName)
+			if b.cfg.FormTag {
+				tag += fmt.Sprintf(" form:%q", f.JSONName)
+			}
+			if rules := f.bindingRules(); rules != "" {
+				tag += fmt.Sprintf(" %s:%q", bindingTag, rules)
+			}
+			fmt.Fprintf(&buf, "\t%s %s `%s`", f.Name, f.Type, tag)
+			if f.Comment != "" {
+				fmt.Fprintf(&buf, " // %s", f.Comment)
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("}\n")
+	}
+	content, formatErr := format.Source(buf.Bytes())
+	if formatErr != nil {
+		return nil, fmt.Errorf("format dto code: %w", formatErr)
+	}
+	return content, nil
+}
+
+func (f dtoField) bindingRules() string {
+	if f.Required {
+		return strings.Join(append([]string{"required"}, f.Rules...), ",")
+	}
+	if len(f.Rules) == 0 {
+		return ""
+	}
+	return strings.Join(append([]string{"omitempty"}, f.Rules...), ",")
+}
+
+// isValueType 是否为零值有意义的类型，必填时生成指针以区分未传和零值
+func isValueType(fieldType string) bool {
+	switch fieldType {
+	case "bool", "int32", "int64", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+// isStructType 是否为生成的结构体类型
+func isStructType(fieldType string) bool {
+	return fieldType != "" && !strings.HasPrefix(fieldType, "*") && !strings.HasPrefix(fieldType, "[]") &&
+		!strings.HasPrefix(fieldType, "map[") && fieldType != "any" && fieldType != "time.Time" &&
+		fieldType != "string" && !isValueType(fieldType)
+}
+
+// fieldType 按必填和可空调整字段类型：必填的数值和布尔、可空的非引用类型及非必填的结构体使用指针
+func fieldType(typ string, required, nullable bool) string {
+	switch {
+	case strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "any":
+		return typ
+	case required && isValueType(typ), nullable, !required && isStructType(typ):
+		return "*" + typ
+	}
+	return typ
+}
+
+const (
+	sampleKindNull   = "null"
+	sampleKindString = "string"
+	sampleKindTime   = "time"
+	sampleKindInt    = "int"
+	sampleKindFloat  = "float"
+	sampleKindBool   = "bool"
+	sampleKindObject = "object"
+	sampleKindArray  = "array"
+	sampleKindAny    = "any"
+)
+
+// sampleShape JSON 示例推断出的结构，数组中的多个值合并为一个
+type sampleShape struct {
+	kind   string
+	keys   []string                // 对象的键，按首次出现的顺序
+	fields map[string]*sampleShape // 对象各键的结构
+	seen   map[string]int          // 各键出现且不为 null 的次数
+	count  int                     // 合并的对象个数
+	elem   *sampleShape            // 数组元素的结构
+}
+
+func newSampleShape(node *yaml.Node) *sampleShape {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		shape := &sampleShape{kind: sampleKindObject, fields: make(map[string]*sampleShape), seen: make(map[string]int), count: 1}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			child := newSampleShape(node.Content[i+1])
+			if _, ok := shape.fields[key]; !ok {
+				shape.keys = append(shape.keys, key)
+			}
+			shape.fields[key] = child
+			if child.kind != sampleKindNull {
+				shape.seen[key] = 1
+			}
+		}
+		return shape
+	case yaml.SequenceNode:
+		shape := &sampleShape{kind: sampleKindArray}
+		for _, item := range node.Content {
+			shape.elem = mergeSampleShape(shape.elem, newSampleShape(item))
+		}
+		return shape
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return &sampleShape{kind: sampleKindNull}
+		case "!!bool":
+			return &sampleShape{kind: sampleKindBool}
+		case "!!int":
+			return &sampleShape{kind: sampleKindInt}
+		case "!!float":
+			return &sampleShape{kind: sampleKindFloat}
+		case "!!timestamp":
+			return &sampleShape{kind: sampleKindTime}
+		}
+		if _, err := time.Parse(time.RFC3339Nano, node.Value); err == nil {
+			return &sampleShape{kind: sampleKindTime}
+		}
+		return &sampleShape{kind: sampleKindString}
+	}
+	return &sampleShape{kind: sampleKindAny}
+}
+
+// mergeSampleShape 合并数组中的两个值：对象取键的并集，整数和小数合并为小数，其他类型不一致时为 any
+func mergeSampleShape(a, b *sampleShape) *sampleShape {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	if a.kind == sampleKindNull || b.kind == sampleKindNull {
+		var merged sampleShape
+		if a.kind == sampleKindNull {
+			merged = *b
+		} else {
+			merged = *a
+		}
+		return &merged
+	}
+	if a.kind != b.kind {
+		if (a.kind == sampleKindInt || a.kind == sampleKindFloat) && (b.kind == sampleKindInt || b.kind == sampleKindFloat) {
+			return &sampleShape{kind: sampleKindFloat}
+		}
+		return &sampleShape{kind: sampleKindAny}
+	}
+	switch a.kind {
+	case sampleKindObject:
+		merged := &sampleShape{kind: sampleKindObject, fields: make(map[string]*sampleShape), seen: make(map[string]int), count: a.count + b.count}
+		for _, src := range []*sampleShape{a, b} {
+			for _, key := range src.keys {
+				if _, ok := merged.fields[key]; !ok {
+					merged.keys = append(merged.keys, key)
+				}
+				merged.fields[key] = mergeSampleShape(merged.fields[key], src.fields[key])
+				merged.seen[key] += src.seen[key]
+			}
+		}
+		return merged
+	case sampleKindArray:
+		return &sampleShape{kind: sampleKindArray, elem: mergeSampleShape(a.elem, b.elem)}
+	}
+	return a
+}
+
+func (b *dtoBuilder) sampleStruct(name string, shape *sampleShape) string {
+	s := b.addStruct(name, "")
+	names := b.fieldNames(shape.keys)
+	for i, key := range shape.keys {
+		child := shape.fields[key]
+		required := b.cfg.Required && child.kind != sampleKindNull && shape.seen[key] == shape.count
+		typ := b.sampleType(s.Name+names[i], child)
+		s.Fields = append(s.Fields, dtoField{
+			Name:     names[i],
+			Type:     fieldType(typ, required, false),
+			JSONName: key,
+			Required: required,
+		})
+	}
+	return s.Name
+}
+
+func (b *dtoBuilder) sampleType(name string, shape *sampleShape) string {
+	if shape == nil {
+		return "any"
+	}
+	switch shape.kind {
+	case sampleKindString:
+		return "string"
+	case sampleKindTime:
+		b.imports["time"] = true
+		return "time.Time"
+	case sampleKindInt:
+		return "int64"
+	case sampleKindFloat:
+		return "float64"
+	case sampleKindBool:
+		return "bool"
+	case sampleKindObject:
+		return b.sampleStruct(name, shape)
+	case sampleKindArray:
+		return "[]" + b.sampleType(name+"Item", shape.elem)
+	}
+	return "any"
+}
+
+// schemaGet 返回 schema 中 key 对应的值
+func schemaGet(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			value := node.Content[i+1]
+			if value.Kind == yaml.AliasNode && value.Alias != nil {
+				return value.Alias
+			}
+			return value
+		}
+	}
+	return nil
+}
+
+func schemaString(node *yaml.Node, key string) string {
+	value := schemaGet(node, key)
+	if value == nil || value.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return value.Value
+}
+
+// schemaTypes 返回 schema 的类型及是否可为 null，type 为数组时取第一个非 null 类型，兼容 OpenAPI 的 nullable
+func schemaTypes(node *yaml.Node) (string, bool) {
+	nullable := schemaString(node, "nullable") == "true"
+	typeNode := schemaGet(node, "type")
+	var typ string
+	switch {
+	case typeNode == nil:
+	case typeNode.Kind == yaml.ScalarNode:
+		typ = typeNode.Value
+	case typeNode.Kind == yaml.SequenceNode:
+		for _, item := range typeNode.Content {
+			if item.Value == "null" {
+				nullable = true
+			} else if typ == "" {
+				typ = item.Value
+			}
+		}
+	}
+	if typ == "null" {
+		return "", true
+	}
+	return typ, nullable
+}
+
+// schemaNodeType 返回 schema 的类型，未设置 type 时根据 properties、items 和 enum 推断
+func schemaNodeType(node *yaml.Node) string {
+	typ, _ := schemaTypes(node)
+	if typ != "" {
+		return typ
+	}
+	switch {
+	case schemaGet(node, "properties") != nil || schemaGet(node, "additionalProperties") != nil:
+		return "object"
+	case schemaGet(node, "items") != nil:
+		return "array"
+	}
+	if enum := schemaGet(node, "enum"); enum != nil && enum.Kind == yaml.SequenceNode && len(enum.Content) > 0 {
+		switch enum.Content[0].Tag {
+		case "!!int":
+			return "integer"
+		case "!!float":
+			return "number"
+		case "!!bool":
+			return "boolean"
+		}
+		return "string"
+	}
+	return ""
+}
+
+// resolveRef 解析 $ref 指向的 schema，只支持同一文档内的 JSON Pointer
+func (b *dtoBuilder) resolveRef(node *yaml.Node) (*yaml.Node, error) {
+	for range 32 {
+		ref := schemaString(node, "$ref")
+		if ref == "" {
+			return node, nil
+		}
+		target, err := b.lookupRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		node = target
+	}
+	return nil, errors.New("too many nested $ref")
+}
+
+func (b *dtoBuilder) lookupRef(ref string) (*yaml.Node, error) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q, only refs within the same document are supported", ref)
+	}
+	node := b.schemaRoot
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if node = schemaGet(node, token); node == nil {
+			return nil, fmt.Errorf("$ref %q not found", ref)
+		}
+	}
+	return node, nil
+}
+
+// schemaStruct 根据对象 schema 生成结构体
+func (b *dtoBuilder) schemaStruct(name string, schema *yaml.Node) error {
+	s := b.addStruct(name, schemaComment(schema))
+	return b.fillSchemaStruct(s, schema)
+}
+
+func (b *dtoBuilder) fillSchemaStruct(s *dtoStruct, schema *yaml.Node) error {
+	required := make(map[string]bool)
+	if requiredNode := schemaGet(schema, "required"); requiredNode != nil && requiredNode.Kind == yaml.SequenceNode {
+		for _, item := range requiredNode.Content {
+			required[item.Value] = true
+		}
+	}
+	properties := schemaGet(schema, "properties")
+	if properties == nil || properties.Kind != yaml.MappingNode {
+		return nil
+	}
+	var keys []string
+	for i := 0; i+1 < len(properties.Content); i += 2 {
+		keys = append(keys, properties.Content[i].Value)
+	}
+	names := b.fieldNames(keys)
+	for i, key := range keys {
+		property := schemaGet(properties, key)
+		typ, rules, nullable, err := b.schemaType(s.Name+names[i], property)
+		if err != nil {
+			return fmt.Errorf("property %s.%s: %w", s.Name, key, err)
+		}
+		s.Fields = append(s.Fields, dtoField{
+			Name:     names[i],
+			Type:     fieldType(typ, required[key], nullable),
+			JSONName: key,
+			Required: required[key],
+			Rules:    rules,
+			Comment:  schemaComment(property),
+		})
+	}
+	return nil
+}
+
+// schemaComment 返回 schema 的 description，没有时使用 title，换行替换为空格
+func schemaComment(schema *yaml.Node) string {
+	comment := schemaString(schema, "description")
+	if comment == "" {
+		comment = schemaString(schema, "title")
+	}
+	return strings.Join(strings.Fields(comment), " ")
+}
+
+// schemaType 返回 schema 对应的 Go 类型、校验规则及是否可为 null，name 为对象 schema 生成结构体时的默认名称
+func (b *dtoBuilder) schemaType(name string, schema *yaml.Node) (typ string, rules []string, nullable bool, err error) {
+	if schema == nil {
+		return "any", nil, false, nil
+	}
+	if ref := schemaString(schema, "$ref"); ref != "" {
+		return b.schemaRefType(ref)
+	}
+	schemaType, nullable := schemaTypes(schema)
+	if schemaType == "" {
+		schemaType = schemaNodeType(schema)
+	}
+	switch schemaType {
+	case "object":
+		if properties := schemaGet(schema, "properties"); properties != nil && len(properties.Content) > 0 {
+			if title := schemaString(schema, "title"); title != "" {
+				name = b.structName(title)
+			}
+			s := b.addStruct(name, schemaComment(schema))
+			if err := b.fillSchemaStruct(s, schema); err != nil {
+				return "", nil, false, err
+			}
+			return s.Name, nil, nullable, nil
+		}
+		additional := schemaGet(schema, "additionalProperties")
+		if additional == nil || additional.Kind != yaml.MappingNode {
+			return "map[string]any", nil, nullable, nil
+		}
+		valueType, _, _, valueErr := b.schemaType(name+"Value", additional)
+		if valueErr != nil {
+			return "", nil, false, valueErr
+		}
+		return "map[string]" + valueType, nil, nullable, nil
+	case "array":
+		rules = appendRule(rules, "min", schemaString(schema, "minItems"))
+		rules = appendRule(rules, "max", schemaString(schema, "maxItems"))
+		items := schemaGet(schema, "items")
+		if items == nil || items.Kind != yaml.MappingNode {
+			return "[]any", rules, nullable, nil
+		}
+		elemType, elemRules, elemNullable, elemErr := b.schemaType(name+"Item", items)
+		if elemErr != nil {
+			return "", nil, false, elemErr
+		}
+		if elemNullable && elemType != "any" && !strings.HasPrefix(elemType, "*") {
+			elemType = "*" + elemType
+		}
+		if len(elemRules) > 0 {
+			rules = append(append(rules, "dive"), elemRules...)
+		}
+		return "[]" + elemType, rules, nullable, nil
+	case "string":
+		typ = "string"
+		switch schemaString(schema, "format") {
+		case "date-time":
+			b.imports["time"] = true
+			typ = "time.Time"
+		case "email":
+			rules = append(rules, "email")
+		case "uri", "url":
+			rules = append(rules, "url")
+		case "uuid":
+			rules = append(rules, "uuid")
+		case "ipv4":
+			rules = append(rules, "ipv4")
+		case "ipv6":
+			rules = append(rules, "ipv6")
+		case "hostname":
+			rules = append(rules, "hostname")
+		}
+		rules = appendRule(rules, "min", schemaString(schema, "minLength"))
+		rules = appendRule(rules, "max", schemaString(schema, "maxLength"))
+	case "integer":
+		typ = "int64"
+		if schemaString(schema, "format") == "int32" {
+			typ = "int32"
+		}
+		rules = appendRangeRules(rules, schema)
+	case "number":
+		typ = "float64"
+		if schemaString(schema, "format") == "float" {
+			typ = "float32"
+		}
+		rules = appendRangeRules(rules, schema)
+	case "boolean":
+		return "bool", nil, nullable, nil
+	default:
+		return "any", nil, false, nil
+	}
+	if oneOf := schemaOneOf(schema); oneOf != "" {
+		rules = append(rules, "oneof="+oneOf)
+	}
+	return typ, rules, nullable, nil
+}
+
+// schemaRefType 返回 $ref 对应的类型，对象生成以定义名命名的结构体，多次引用共用同一结构体，自引用时使用指针
+func (b *dtoBuilder) schemaRefType(ref string) (string, []string, bool, error) {
+	if name, ok := b.refs[ref]; ok {
+		if b.building[ref] {
+			return "*" + name, nil, false, nil
+		}
+		return name, nil, false, nil
+	}
+	target, err := b.lookupRef(ref)
+	if err != nil {
+		return "", nil, false, err
+	}
+	properties := schemaGet(target, "properties")
+	if schemaNodeType(target) != "object" || properties == nil || len(properties.Content) == 0 {
+		return b.schemaType(b.structName(ref[strings.LastIndex(ref, "/")+1:]), target)
+	}
+	name := schemaString(target, "title")
+	if name == "" {
+		name = ref[strings.LastIndex(ref, "/")+1:]
+	}
+	s := b.addStruct(b.structName(name), schemaComment(target))
+	b.refs[ref] = s.Name
+	b.building[ref] = true
+	defer delete(b.building, ref)
+	if err := b.fillSchemaStruct(s, target); err != nil {
+		return "", nil, false, err
+	}
+	_, nullable := schemaTypes(target)
+	return s.Name, nil, nullable, nil
+}
+
+func appendRule(rules []string, rule, value string) []string {
+	if value == "" {
+		return rules
+	}
+	return append(rules, rule+"="+value)
+}
+
+// appendRangeRules 转换数值的取值范围，exclusiveMinimum、exclusiveMaximum 兼容 draft 4 的布尔写法
+func appendRangeRules(rules []string, schema *yaml.Node) []string {
+	minimum, maximum := schemaString(schema, "minimum"), schemaString(schema, "maximum")
+	exclusiveMin, exclusiveMax := schemaString(schema, "exclusiveMinimum"), schemaString(schema, "exclusiveMaximum")
+	switch exclusiveMin {
+	case "true":
+		rules = appendRule(rules, "gt", minimum)
+	case "", "false":
+		rules = appendRule(rules, "gte", minimum)
+	default:
+		rules = appendRule(rules, "gt", exclusiveMin)
+	}
+	switch exclusiveMax {
+	case "true":
+		rules = appendRule(rules, "lt", maximum)
+	case "", "false":
+		rules = appendRule(rules, "lte", maximum)
+	default:
+		rules = appendRule(rules, "lt", exclusiveMax)
+	}
+	return rules
+}
+
+// schemaOneOf 将 enum 转换为 oneof 的参数，包含空格的值使用单引号，值中含有无法写入标签的字符时不生成
+func schemaOneOf(schema *yaml.Node) string {
+	enum := schemaGet(schema, "enum")
+	if enum == nil || enum.Kind != yaml.SequenceNode || len(enum.Content) == 0 {
+		return ""
+	}
+	values := make([]string, 0, len(enum.Content))
+	for _, item := range enum.Content {
+		if item.Kind != yaml.ScalarNode || item.Tag == "!!null" {
+			continue
+		}
+		value := item.Value
+		if value == "" || strings.ContainsAny(value, ",|'\"`") {
+			return ""
+		}
+		if strings.ContainsAny(value, " \t") {
+			value = "'" + value + "'"
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, " ")
+}
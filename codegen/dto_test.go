@@ -0,0 +1,159 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDTOFromJSON(t *testing.T) {
+	sample := `{
+	"orderId": 1001,
+	"amount": 12.5,
+	"paid": true,
+	"createdAt": "2024-01-02T15:04:05Z",
+	"remark": null,
+	"buyer": {"userID": 1, "nick_name": "tom"},
+	"items": [
+		{"sku": "A1", "qty": 1},
+		{"sku": "B2", "qty": 2.5, "gift": true}
+	],
+	"tags": []
+}`
+	content, err := GenerateDTOFromJSON([]byte(sample), &DTOCfg{
+		PackageName:    "dtopartner",
+		StructName:     "OrderNotifyReq",
+		Required:       true,
+		NamingStrategy: &NamingStrategy{Acronyms: []string{"ID"}},
+	})
+	require.NoError(t, err)
+	code := string(content)
+	t.Log(code)
+
+	assert.Contains(t, code, "import \"time\"")
+	assert.Contains(t, code, "OrderID   *int64                    `json:\"orderId\" binding:\"required\"`")
+	assert.Contains(t, code, "Amount    *float64")
+	assert.Contains(t, code, "CreatedAt time.Time")
+	assert.Contains(t, code, "Remark    any                       `json:\"remark,omitempty\"`")
+	assert.Contains(t, code, "Buyer     OrderNotifyReqBuyer       `json:\"buyer\" binding:\"required\"`")
+	assert.Contains(t, code, "Items     []OrderNotifyReqItemsItem `json:\"items\" binding:\"required\"`")
+	assert.Contains(t, code, "Tags      []any")
+	assert.Contains(t, code, "UserID   *int64 `json:\"userID\" binding:\"required\"`")
+	assert.Contains(t, code, "NickName string `json:\"nick_name\" binding:\"required\"`")
+	// 数组中的对象合并，整数和小数合并为小数，不是每个元素都有的字段非必填
+	assert.Contains(t, code, "Qty  *float64 `json:\"qty\" binding:\"required\"`")
+	assert.Contains(t, code, "Gift bool     `json:\"gift,omitempty\"`")
+
+	_, err = GenerateDTOFromJSON([]byte(`"abc"`), &DTOCfg{PackageName: "dto", StructName: "Foo"})
+	assert.Error(t, err)
+	_, err = GenerateDTOFromJSON([]byte(`{}`), &DTOCfg{PackageName: "dto"})
+	assert.Error(t, err)
+}
+
+func TestGenerateDTOFromJSONSchema(t *testing.T) {
+	schema := `
+title: create_user_req
+description: 创建用户请求
+type: object
+required: [name, email, age, address]
+properties:
+  name:
+    type: string
+    description: 用户名
+    minLength: 2
+    maxLength: 32
+  email:
+    type: string
+    format: email
+  age:
+    type: integer
+    minimum: 0
+    exclusiveMaximum: 150
+  status:
+    type: string
+    enum: [active, "on hold"]
+  homepage:
+    type: [string, "null"]
+    format: uri
+  birthday:
+    type: string
+    format: date-time
+  address:
+    $ref: '#/$defs/Address'
+  shipping:
+    $ref: '#/$defs/Address'
+  tags:
+    type: array
+    minItems: 1
+    items:
+      type: string
+      maxLength: 8
+  extra:
+    type: object
+    additionalProperties:
+      type: integer
+  manager:
+    $ref: '#/$defs/Employee'
+$defs:
+  Address:
+    type: object
+    description: 地址
+    required: [city]
+    properties:
+      city: {type: string}
+      zip_code: {type: string}
+  Employee:
+    type: object
+    properties:
+      name: {type: string}
+      reports:
+        type: array
+        items: {$ref: '#/$defs/Employee'}
+      boss: {$ref: '#/$defs/Employee'}
+`
+	content, err := GenerateDTOFromJSONSchema([]byte(schema), &DTOCfg{PackageName: "dto", FormTag: true})
+	require.NoError(t, err)
+	code := string(content)
+	t.Log(code)
+
+	assert.Contains(t, code, "// CreateUserReq 创建用户请求\ntype CreateUserReq struct {")
+	assert.Contains(t, code, "`json:\"name\" form:\"name\" binding:\"required,min=2,max=32\"` // 用户名")
+	assert.Contains(t, code, "`json:\"email\" form:\"email\" binding:\"required,email\"`")
+	assert.Contains(t, code, "Age      *int64")
+	assert.Contains(t, code, "`json:\"age\" form:\"age\" binding:\"required,gte=0,lt=150\"`")
+	assert.Contains(t, code, "binding:\"omitempty,oneof=active 'on hold'\"`")
+	assert.Contains(t, code, "Homepage *string")
+	assert.Contains(t, code, "binding:\"omitempty,url\"`")
+	assert.Contains(t, code, "Birthday time.Time")
+	assert.Contains(t, code, "Address  Address ")
+	assert.Contains(t, code, "Shipping *Address ")
+	assert.Contains(t, code, "binding:\"omitempty,min=1,dive,max=8\"`")
+	assert.Contains(t, code, "Extra    map[string]int64")
+	assert.Contains(t, code, "Manager  *Employee")
+	assert.Contains(t, code, "// Address 地址\ntype Address struct {")
+	assert.Contains(t, code, "ZipCode string")
+	assert.Contains(t, code, "Reports []*Employee")
+	assert.Contains(t, code, "Boss    *Employee")
+	assert.Equal(t, 1, strings.Count(code, "type Address struct"))
+
+	_, err = GenerateDTOFromJSONSchema([]byte(`{"type": "object"}`), &DTOCfg{PackageName: "dto"})
+	assert.Error(t, err)
+	_, err = GenerateDTOFromJSONSchema([]byte(`{"$ref": "other.json#/Foo"}`), &DTOCfg{PackageName: "dto", StructName: "Foo"})
+	assert.Error(t, err)
+}
+
+func TestDTOSnake(t *testing.T) {
+	cases := map[string]string{
+		"userId":      "user_id",
+		"userID":      "user_id",
+		"HTTPServer":  "http_server",
+		"x-trace-id":  "x_trace_id",
+		"@type":       "type",
+		"order__no_1": "order_no_1",
+	}
+	for key, want := range cases {
+		assert.Equal(t, want, dtoSnake(key), key)
+	}
+}
@@ -0,0 +1,214 @@
+package codegen
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/morehao/golib/gutil"
+)
+
+// FieldEnum 字段的枚举定义，来自 mysql 的 enum、set 列或整型列注释中的取值说明，
+// 内置模板在 model 层为其生成类型、常量及 String、IsValid 方法
+type FieldEnum struct {
+	TypeName string      // 枚举类型名，如 UserRoleStatus
+	BaseType string      // 底层类型，如 string、int8
+	IsSet    bool        // 是否为 mysql set 列，值为逗号分隔的多个成员
+	Values   []EnumValue // 枚举值，按定义顺序排列
+}
+
+// EnumValue 枚举值
+type EnumValue struct {
+	ConstName string // 常量名，如 UserRoleStatusEnabled
+	Value     string // 常量值的 Go 字面量，如 1、"on"
+	Label     string // 说明，String 方法的返回值，如 enabled
+}
+
+// IsString 底层类型是否为 string
+func (e *FieldEnum) IsString() bool {
+	return e.BaseType == "string"
+}
+
+// OneOf validator 的 oneof 取值，以空格分隔，set 列或取值中含空格时为空
+func (e *FieldEnum) OneOf() string {
+	if e.IsSet {
+		return ""
+	}
+	values := make([]string, 0, len(e.Values))
+	for _, v := range e.Values {
+		raw, err := strconv.Unquote(v.Value)
+		if err != nil {
+			raw = v.Value
+		}
+		if raw == "" || strings.ContainsAny(raw, " \t'") {
+			return ""
+		}
+		values = append(values, raw)
+	}
+	return strings.Join(values, " ")
+}
+
+// SwagEnums swaggo enums 标签的取值，以逗号分隔，set 列或取值中含逗号时为空
+func (e *FieldEnum) SwagEnums() string {
+	oneOf := e.OneOf()
+	if oneOf == "" || strings.Contains(oneOf, ",") {
+		return ""
+	}
+	return strings.ReplaceAll(oneOf, " ", ",")
+}
+
+var (
+	// columnEnumRegexp 匹配 enum('a','b') 和 set('a','b') 列类型
+	columnEnumRegexp = regexp.MustCompile(`(?is)^\s*(enum|set)\s*\((.*)\)\s*$`)
+	// commentEnumRegexp 匹配注释中的取值说明，如 1-启用、2:禁用、3=deleted
+	commentEnumRegexp = regexp.MustCompile(`(-?\d+)\s*[-:=：]\s*([^\s,，;；、:：=]+)`)
+	// commentEnumSepRegexp 取值说明之间允许的分隔符
+	commentEnumSepRegexp = regexp.MustCompile(`^[\s,，;；、]*$`)
+)
+
+// parseFieldEnum 识别字段的枚举定义，无法识别时返回 nil。
+// mysql 的 enum、set 列按列类型识别；整型列按注释识别，注释需以至少两个 "值-说明" 结尾，
+// 值与说明之间可用 -、:、= 分隔，如 "状态: 1-启用 2-禁用"、"1:enabled,2:disabled"
+func parseFieldEnum(structName string, field ModelField) *FieldEnum {
+	typeName := structName + field.FieldName
+	if matches := columnEnumRegexp.FindStringSubmatch(field.ColumnType); matches != nil {
+		members := parseEnumMembers(matches[2])
+		if len(members) == 0 {
+			return nil
+		}
+		enum := &FieldEnum{TypeName: typeName, BaseType: "string", IsSet: strings.EqualFold(matches[1], "set")}
+		constNames := enumConstNames(typeName, members, nil)
+		for i, member := range members {
+			enum.Values = append(enum.Values, EnumValue{ConstName: constNames[i], Value: strconv.Quote(member), Label: member})
+		}
+		return enum
+	}
+
+	if !isIntegerType(field.FieldType) {
+		return nil
+	}
+	values, labels := parseCommentEnum(field.Comment)
+	if len(values) < 2 {
+		return nil
+	}
+	enum := &FieldEnum{TypeName: typeName, BaseType: field.FieldType}
+	constNames := enumConstNames(typeName, labels, values)
+	for i := range values {
+		enum.Values = append(enum.Values, EnumValue{ConstName: constNames[i], Value: values[i], Label: labels[i]})
+	}
+	return enum
+}
+
+// parseEnumMembers 解析 enum、set 列类型括号内以单引号包裹的成员，两个连续的单引号表示一个单引号
+func parseEnumMembers(s string) []string {
+	var (
+		members []string
+		sb      strings.Builder
+		inQuote bool
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !inQuote {
+			if c == '\'' {
+				inQuote = true
+				sb.Reset()
+			}
+			continue
+		}
+		switch {
+		case c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			sb.WriteByte('\'')
+			i++
+		case c == '\\' && i+1 < len(s):
+			sb.WriteByte(s[i+1])
+			i++
+		case c == '\'':
+			inQuote = false
+			members = append(members, sb.String())
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	if inQuote {
+		return nil
+	}
+	return members
+}
+
+// parseCommentEnum 从注释末尾解析取值说明，取值重复或说明之间存在其他内容时返回空
+func parseCommentEnum(comment string) (values, labels []string) {
+	locs := commentEnumRegexp.FindAllStringSubmatchIndex(comment, -1)
+	if len(locs) < 2 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(locs))
+	for i, loc := range locs {
+		end := len(comment)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		if !commentEnumSepRegexp.MatchString(comment[loc[1]:end]) {
+			return nil, nil
+		}
+		value := comment[loc[2]:loc[3]]
+		if seen[value] {
+			return nil, nil
+		}
+		seen[value] = true
+		values = append(values, value)
+		labels = append(labels, comment[loc[4]:loc[5]])
+	}
+	return values, labels
+}
+
+// enumConstNames 生成枚举常量名，优先使用说明转换的驼峰名，说明无法转为标识符或转换后重名时，
+// 整型枚举使用值（负数以 Neg 开头），字符串枚举使用序号
+func enumConstNames(typeName string, labels, values []string) []string {
+	names := make([]string, len(labels))
+	seen := make(map[string]bool, len(labels))
+	for i, label := range labels {
+		suffix := enumIdentSuffix(label)
+		if suffix == "" || seen[suffix] {
+			return enumFallbackConstNames(typeName, len(labels), values)
+		}
+		seen[suffix] = true
+		names[i] = typeName + suffix
+	}
+	return names
+}
+
+func enumFallbackConstNames(typeName string, n int, values []string) []string {
+	names := make([]string, n)
+	for i := range names {
+		if values == nil {
+			names[i] = typeName + "Value" + strconv.Itoa(i+1)
+			continue
+		}
+		names[i] = typeName + strings.Replace(values[i], "-", "Neg", 1)
+	}
+	return names
+}
+
+// enumIdentSuffix 将说明转为以大写字母开头的标识符片段，包含非 ASCII 字母数字以外的字符时返回空
+func enumIdentSuffix(label string) string {
+	snake := strings.Map(func(r rune) rune {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			return r
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			return '_'
+		}
+		return -1
+	}, label)
+	if len([]rune(snake)) != len([]rune(label)) {
+		return ""
+	}
+	// 合并连续的下划线，SnakeToPascal 不支持空片段
+	parts := strings.FieldsFunc(strings.ToLower(snake), func(r rune) bool { return r == '_' })
+	suffix := gutil.SnakeToPascal(strings.Join(parts, "_"))
+	if suffix == "" || !unicode.IsLetter(rune(suffix[0])) {
+		return ""
+	}
+	return suffix
+}
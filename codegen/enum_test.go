@@ -0,0 +1,56 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFieldEnum(t *testing.T) {
+	enum := parseFieldEnum("User", ModelField{FieldName: "Status", FieldType: "int8", Comment: "状态: 1-enabled 2-disabled -1-deleted"})
+	assert.Equal(t, &FieldEnum{
+		TypeName: "UserStatus",
+		BaseType: "int8",
+		Values: []EnumValue{
+			{ConstName: "UserStatusEnabled", Value: "1", Label: "enabled"},
+			{ConstName: "UserStatusDisabled", Value: "2", Label: "disabled"},
+			{ConstName: "UserStatusDeleted", Value: "-1", Label: "deleted"},
+		},
+	}, enum)
+	assert.Equal(t, "1 2 -1", enum.OneOf())
+	assert.Equal(t, "1,2,-1", enum.SwagEnums())
+
+	// 中文说明使用值作为常量名
+	enum = parseFieldEnum("User", ModelField{FieldName: "Gender", FieldType: "uint8", Comment: "性别 1:男，2:女；-1：未知"})
+	assert.Equal(t, []EnumValue{
+		{ConstName: "UserGender1", Value: "1", Label: "男"},
+		{ConstName: "UserGender2", Value: "2", Label: "女"},
+		{ConstName: "UserGenderNeg1", Value: "-1", Label: "未知"},
+	}, enum.Values)
+
+	enum = parseFieldEnum("User", ModelField{FieldName: "Level", FieldType: "string", ColumnType: "enum('low','HIGH','very high')"})
+	assert.Equal(t, []EnumValue{
+		{ConstName: "UserLevelLow", Value: `"low"`, Label: "low"},
+		{ConstName: "UserLevelHigh", Value: `"HIGH"`, Label: "HIGH"},
+		{ConstName: "UserLevelVeryHigh", Value: `"very high"`, Label: "very high"},
+	}, enum.Values)
+	assert.False(t, enum.IsSet)
+	assert.Empty(t, enum.OneOf())
+
+	// 说明转换后重名或无法转为标识符时使用序号作为常量名
+	enum = parseFieldEnum("User", ModelField{FieldName: "Tags", FieldType: "string", ColumnType: "SET('a','A','it''s')"})
+	assert.True(t, enum.IsSet)
+	assert.Equal(t, EnumValue{ConstName: "UserTagsValue3", Value: `"it's"`, Label: "it's"}, enum.Values[2])
+	assert.Empty(t, enum.OneOf())
+
+	// 无法识别的注释
+	for _, field := range []ModelField{
+		{FieldName: "Status", FieldType: "string", Comment: "1-enabled 2-disabled"},
+		{FieldName: "Status", FieldType: "int", Comment: "1-enabled"},
+		{FieldName: "Status", FieldType: "int", Comment: "1-enabled 1-disabled"},
+		{FieldName: "Status", FieldType: "int", Comment: "1-enabled 2-disabled, see docs"},
+		{FieldName: "Age", FieldType: "int", Comment: "年龄"},
+	} {
+		assert.Nil(t, parseFieldEnum("User", field), field.Comment)
+	}
+}
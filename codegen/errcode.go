@@ -0,0 +1,163 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/morehao/golib/gutil"
+)
+
+const (
+	defaultErrorCodeTplDir    = "templates/code"
+	defaultErrorCodeRangeSize = 100
+)
+
+// ModuleTplErrorCode 模块错误码
+type ModuleTplErrorCode struct {
+	Name string // 常量名，如 UserNotExistErr
+	Code int    // 错误码
+	Msg  string // 错误信息
+}
+
+// DefaultErrorCodeTplFS 返回内置的错误码模板，模板参数同 ModuleTplParams，错误码见 ErrorCodes
+func DefaultErrorCodeTplFS() fs.FS {
+	sub, _ := fs.Sub(defaultModuleTplFS, defaultErrorCodeTplDir)
+	return sub
+}
+
+// analysisErrorCodeTpl 分析内置错误码模板，生成到 code 层目录，文件名为表名，同一包内的多张表各自分配号段。
+// 模板目录中已有 code 层模板时不使用内置模板，模板同样可以使用 ErrorCodes 等参数
+func analysisErrorCodeTpl(cfg *ModuleCfg, tplAnalysisList []ModuleTplAnalysisItem) ([]ModuleTplAnalysisItem, error) {
+	for _, item := range tplAnalysisList {
+		if item.OriginLayerName == LayerNameCode {
+			return nil, nil
+		}
+	}
+	codeCfg := cfg.CommonConfig
+	codeCfg.TplDir, codeCfg.TplFS = "", DefaultErrorCodeTplFS()
+	codeTplList, analysisErr := analysisTplFiles(codeCfg, cfg.TableName)
+	if analysisErr != nil {
+		return nil, analysisErr
+	}
+	_, hasOutputPath := cfg.OutputPathTplMap[LayerNameCode]
+	res := make([]ModuleTplAnalysisItem, 0, len(codeTplList))
+	for _, v := range codeTplList {
+		if !hasOutputPath {
+			v.TargetFilename = gutil.TrimFileExtension(cfg.TableName) + goFileExtension
+			v.TargetFileExist = gutil.FileExists(filepath.Join(v.TargetDir, v.TargetFilename))
+		}
+		res = append(res, ModuleTplAnalysisItem{
+			TplAnalysisItem: v,
+			ModelFields:     tplAnalysisList[0].ModelFields,
+		})
+	}
+	return res, nil
+}
+
+// buildErrorCodes 为模块分配错误码号段并生成错误码。目标文件已存在时沿用其中最小的错误码作为号段起点，
+// 否则从 ErrorCodeBase 起按号段大小查找 code 层目录中未被占用的第一个号段，返回号段的起点、终点（含）和错误码
+func buildErrorCodes(cfg *ModuleCfg, item ModuleTplAnalysisItem, structName, tableName string) (int, int, []ModuleTplErrorCode, error) {
+	rangeSize := cfg.ErrorCodeRangeSize
+	if rangeSize <= 0 {
+		rangeSize = defaultErrorCodeRangeSize
+	}
+	names := []struct{ suffix, msg string }{
+		{"CreateErr", "create " + tableName + " error"},
+		{"DeleteErr", "delete " + tableName + " error"},
+		{"UpdateErr", "update " + tableName + " error"},
+		{"GetDetailErr", "get " + tableName + " detail error"},
+		{"GetPageListErr", "get " + tableName + " page list error"},
+		{"NotExistErr", tableName + " not exist"},
+	}
+	if rangeSize < len(names) {
+		return 0, 0, nil, fmt.Errorf("errorCodeRangeSize %d is less than the %d generated codes", rangeSize, len(names))
+	}
+
+	var start int
+	if item.TargetFileExist {
+		codes, err := fileIntConsts(filepath.Join(item.TargetDir, item.TargetFilename))
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		if len(codes) > 0 {
+			start = codes[0]
+		}
+	}
+	if start == 0 {
+		var err error
+		if start, err = allocErrorCodeRange(item.TargetDir, cfg.ErrorCodeBase, rangeSize); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+
+	codes := make([]ModuleTplErrorCode, 0, len(names))
+	for i, v := range names {
+		codes = append(codes, ModuleTplErrorCode{Name: structName + v.suffix, Code: start + i, Msg: v.msg})
+	}
+	return start, start + rangeSize - 1, codes, nil
+}
+
+// allocErrorCodeRange 返回从 base 起第一个未被 dir 中已有错误码占用的号段起点，号段为 [start, start+size)
+func allocErrorCodeRange(dir string, base, size int) (int, error) {
+	used := make(map[int]bool)
+	entries, readErr := os.ReadDir(dir)
+	if readErr != nil && !os.IsNotExist(readErr) {
+		return 0, readErr
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, goFileExtension) || strings.HasSuffix(name, "_test"+goFileExtension) {
+			continue
+		}
+		codes, err := fileIntConsts(filepath.Join(dir, name))
+		if err != nil {
+			return 0, err
+		}
+		for _, code := range codes {
+			if code >= base {
+				used[(code-base)/size] = true
+			}
+		}
+	}
+	block := 0
+	for used[block] {
+		block++
+	}
+	return base + block*size, nil
+}
+
+// fileIntConsts 返回文件中以整数字面量声明的常量值，按从小到大排列
+func fileIntConsts(filename string) ([]int, error) {
+	file, parseErr := parser.ParseFile(token.NewFileSet(), filename, nil, parser.SkipObjectResolution)
+	if parseErr != nil {
+		return nil, fmt.Errorf("parse %s fail, error: %w", filename, parseErr)
+	}
+	var codes []int
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			for _, value := range spec.(*ast.ValueSpec).Values {
+				lit, ok := value.(*ast.BasicLit)
+				if !ok || lit.Kind != token.INT {
+					continue
+				}
+				if code, err := strconv.Atoi(lit.Value); err == nil {
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+	sort.Ints(codes)
+	return codes, nil
+}
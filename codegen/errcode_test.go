@@ -0,0 +1,88 @@
+package codegen
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateErrorCode(t *testing.T) {
+	rootDir := t.TempDir()
+	codeDir := filepath.Join(rootDir, "code")
+	require.Nil(t, os.MkdirAll(codeDir, 0o755))
+	// 手写的错误码占用 200000-200099 号段
+	require.Nil(t, os.WriteFile(filepath.Join(codeDir, "common.go"), []byte("package code\n\nconst CommonErr = 200005\n"), 0o644))
+
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     rootDir,
+		},
+		TableName:     "user",
+		ImportPath:    "example.com/demo",
+		ErrorCodeBase: 200000,
+	}
+	res, err := GenerateModulesFromDDL(mysqlTestDDL, &ModuleCfg{
+		CommonConfig:  cfg.CommonConfig,
+		TableNames:    []string{"user", "order"},
+		ImportPath:    cfg.ImportPath,
+		ErrorCodeBase: cfg.ErrorCodeBase,
+	})
+	require.Nil(t, err)
+	require.Empty(t, res.Failed())
+
+	readFile := func(elem ...string) string {
+		content, readErr := os.ReadFile(filepath.Join(append([]string{rootDir}, elem...)...))
+		require.Nil(t, readErr)
+		_, fmtErr := format.Source(content)
+		require.Nil(t, fmtErr)
+		return string(content)
+	}
+	// 表按名称排序生成，order 先分配号段
+	orderCode := readFile("code", "order.go")
+	assert.Contains(t, orderCode, "// order 模块错误码 (200100-200199)")
+	assert.Contains(t, orderCode, "OrderCreateErr      = 200100")
+	assert.Contains(t, orderCode, "OrderNotExistErr    = 200105")
+	assert.Contains(t, orderCode, `gerror.MustRegister("order", OrderErrorMsgMap)`)
+	userCode := readFile("code", "user.go")
+	assert.Contains(t, userCode, "// user 模块错误码 (200200-200299)")
+	assert.Contains(t, userCode, `UserNotExistErr:    "user not exist",`)
+	assert.Contains(t, userCode, "var UserErrorMap = UserErrorMsgMap.ToErrorMap()")
+
+	service := readFile("service", "svcuser", "user.go")
+	assert.Contains(t, service, `"example.com/demo/code"`)
+	assert.NotContains(t, service, `"errors"`)
+	assert.Contains(t, service, "return nil, code.UserErrorMap.MustGet(code.UserNotExistErr)")
+
+	// 重新生成时沿用已有文件的号段
+	cfg.DryRun = true
+	genRes, err := GenerateFromDDL(mysqlTestDDL, cfg)
+	require.Nil(t, err)
+	for _, file := range genRes.Files {
+		if file.Path == filepath.Join(codeDir, "user.go") {
+			assert.True(t, file.Exist)
+			assert.Contains(t, string(file.Content), "UserCreateErr      = 200200")
+		}
+	}
+
+	cfg.ErrorCodeRangeSize = 5
+	_, err = GenerateFromDDL(mysqlTestDDL, cfg)
+	assert.NotNil(t, err)
+}
+
+func TestAllocErrorCodeRange(t *testing.T) {
+	dir := t.TempDir()
+	start, err := allocErrorCodeRange(filepath.Join(dir, "missing"), 300000, 100)
+	require.Nil(t, err)
+	assert.Equal(t, 300000, start)
+
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("package code\n\nconst (\n\tA = 300000\n\tB = 300250\n\tC = 100\n)\n"), 0o644))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "a_test.go"), []byte("package code\n\nconst T = 300100\n"), 0o644))
+	start, err = allocErrorCodeRange(dir, 300000, 100)
+	require.Nil(t, err)
+	assert.Equal(t, 300100, start)
+}
@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"github.com/morehao/golib/dbaccess/dbgorm"
+	"gorm.io/gorm"
+)
+
+{{- /* 建表语句中的标识符按方言引用，MySQL 的反引号无法写入 Go 原始字符串，因此整条语句以带引号的字符串输出 */}}
+{{- $sql := print "CREATE TABLE IF NOT EXISTS " (quoteIdent .Dialect .TableName) " (\n"}}
+{{- range $i, $field := .ModelFields}}
+{{- if $i}}{{$sql = print $sql ",\n"}}{{end}}
+{{- $sql = print $sql "\t" (quoteIdent $.Dialect $field.ColumnName) " " $field.ColumnType}}
+{{- if not $field.IsNullable}}{{$sql = print $sql " NOT NULL"}}{{end}}
+{{- end}}
+{{- if .PrimaryKeys}}
+{{- $sql = print $sql ",\n\tPRIMARY KEY ("}}
+{{- range $i, $key := .PrimaryKeys}}
+{{- if $i}}{{$sql = print $sql ", "}}{{end}}
+{{- $sql = print $sql (quoteIdent $.Dialect $key)}}
+{{- end}}
+{{- $sql = print $sql ")"}}
+{{- end}}
+{{- $sql = print $sql "\n)"}}
+
+func init() {
+	dbgorm.RegisterMigration(&dbgorm.Migration{
+		Version: "{{.MigrationVersion}}",
+		Name:    "create_{{.TableName}}",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec({{printf "%q" $sql}}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("{{.TableName}}")
+		},
+	})
+}
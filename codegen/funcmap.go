@@ -0,0 +1,122 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gorm.io/gorm/schema"
+)
+
+// DefaultTplFuncMap 返回模板内置函数，模板解析时自动注册，CommonConfig.TplFuncMap 中的同名函数优先：
+//   - snake: 转为蛇形，如 UserID、userId 转为 user_id
+//   - camel: 转为小驼峰，如 user_name 转为 userName
+//   - pascal: 转为大驼峰，如 user_name 转为 UserName
+//   - plural: 转为英文复数，如 user 转为 users、category 转为 categories，只处理最后一个单词
+//   - zeroValue: Go 类型的零值字面量，如 int64 为 0、*string 为 nil、time.Time 为 time.Time{}
+//   - gormTag: 按 ModuleTplField 生成 gorm 标签，如 gorm:"column:id;primaryKey;autoIncrement"，与内置 model 模板一致
+//   - jsonTag: 按 ModuleTplField 生成 json 标签，可追加选项，如 {{jsonTag . "omitempty"}} 生成 json:"userName,omitempty"
+//   - quoteIdent: 按方言引用 SQL 标识符，如 {{quoteIdent .Dialect .TableName}}，mysql 为 `user`，postgres 为 "user"
+func DefaultTplFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"snake":      tplSnake,
+		"camel":      tplCamel,
+		"pascal":     tplPascal,
+		"plural":     tplPlural,
+		"zeroValue":  tplZeroValue,
+		"gormTag":    tplGormTag,
+		"jsonTag":    tplJSONTag,
+		"quoteIdent": tplQuoteIdent,
+	}
+}
+
+// tplFuncMap 合并内置函数和自定义函数
+func tplFuncMap(custom template.FuncMap) template.FuncMap {
+	funcMap := DefaultTplFuncMap()
+	for name, fn := range custom {
+		funcMap[name] = fn
+	}
+	return funcMap
+}
+
+// tplQuoteIdent 按方言引用标识符，标识符中的引号写两次转义，方言为空时按 MySQL 处理
+func tplQuoteIdent(dialect, name string) string {
+	quote := "`"
+	if dialect == dbTypePostgresql {
+		quote = `"`
+	}
+	return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+}
+
+func tplSnake(s string) string {
+	return schema.NamingStrategy{}.ColumnName("", s)
+}
+
+func tplCamel(s string) string {
+	pascal := tplPascal(s)
+	if pascal == "" {
+		return ""
+	}
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
+
+func tplPascal(s string) string {
+	var sb strings.Builder
+	for _, part := range strings.Split(tplSnake(s), "_") {
+		if part == "" {
+			continue
+		}
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+func tplPlural(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case s == "":
+		return ""
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	case len(lower) > 1 && strings.HasSuffix(lower, "y") && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
+
+func tplZeroValue(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "*"), strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["),
+		strings.HasPrefix(goType, "func("), strings.HasPrefix(goType, "chan "),
+		goType == "any", goType == "interface{}", goType == "error":
+		return "nil"
+	case goType == "string":
+		return `""`
+	case goType == "bool":
+		return "false"
+	case isIntegerType(goType), goType == "float32", goType == "float64", goType == "byte", goType == "rune":
+		return "0"
+	default:
+		return goType + "{}"
+	}
+}
+
+func tplGormTag(field ModuleTplField) string {
+	tag := "column:" + field.ColumnName
+	if field.IsPK {
+		tag += ";primaryKey"
+	}
+	if field.IsAutoIncrement {
+		tag += ";autoIncrement"
+	}
+	if field.IndexTag != "" {
+		tag += ";" + field.IndexTag
+	}
+	return fmt.Sprintf(`gorm:"%s"`, tag)
+}
+
+func tplJSONTag(field ModuleTplField, options ...string) string {
+	return fmt.Sprintf(`json:"%s"`, strings.Join(append([]string{field.JSONName}, options...), ","))
+}
@@ -0,0 +1,77 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTplFuncMap(t *testing.T) {
+	assert.Equal(t, "user_id", tplSnake("UserID"))
+	assert.Equal(t, "user_name", tplSnake("userName"))
+	assert.Equal(t, "user_name", tplSnake("user_name"))
+	assert.Equal(t, "userName", tplCamel("user_name"))
+	assert.Equal(t, "UserRole", tplPascal("user__role"))
+	assert.Equal(t, "", tplCamel(""))
+
+	for s, want := range map[string]string{
+		"user": "users", "user_role": "user_roles", "category": "categories", "key": "keys",
+		"address": "addresses", "box": "boxes", "branch": "branches", "": "",
+	} {
+		assert.Equal(t, want, tplPlural(s), s)
+	}
+
+	for goType, want := range map[string]string{
+		"int64": "0", "uint": "0", "float64": "0", "string": `""`, "bool": "false",
+		"*string": "nil", "[]byte": "nil", "map[string]any": "nil", "any": "nil",
+		"time.Time": "time.Time{}", "gorm.DeletedAt": "gorm.DeletedAt{}",
+	} {
+		assert.Equal(t, want, tplZeroValue(goType), goType)
+	}
+
+	field := ModuleTplField{
+		ModelField: ModelField{ColumnName: "id", IsAutoIncrement: true, IndexTag: "index:idx_id"},
+		IsPK:       true,
+		JSONName:   "id",
+	}
+	assert.Equal(t, `gorm:"column:id;primaryKey;autoIncrement;index:idx_id"`, tplGormTag(field))
+	assert.Equal(t, `json:"id"`, tplJSONTag(field))
+	assert.Equal(t, `json:"id,omitempty,string"`, tplJSONTag(field, "omitempty", "string"))
+}
+
+func TestTplFuncMapCustom(t *testing.T) {
+	rootDir := t.TempDir()
+	tplFS := fstest.MapFS{
+		"dao.go.tpl": {Data: []byte(`package {{.PackageName}}
+
+// {{plural .TableName}} {{upper .TableName}}
+{{- range .ModelFields}}
+// {{.FieldName}} {{camel .ColumnName}} {{zeroValue .FieldType}} {{gormTag .}} {{jsonTag . "omitempty"}}
+{{- end}}
+`)},
+	}
+	res, err := GenerateFromDDL(mysqlTestDDL, &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     rootDir,
+			TplFS:       tplFS,
+			TplFuncMap: template.FuncMap{
+				"upper":  strings.ToUpper,
+				"plural": func(s string) string { return s + "_list" },
+			},
+		},
+		TableName: "user",
+		DryRun:    true,
+	})
+	require.Nil(t, err)
+	require.Len(t, res.Files, 1)
+	content := string(res.Files[0].Content)
+	// 自定义函数覆盖同名的内置函数
+	assert.Contains(t, content, "// user_list USER\n")
+	assert.Contains(t, content, "// Id id 0 gorm:\"column:id;primaryKey;autoIncrement\" json:\"id,omitempty\"\n")
+	assert.Contains(t, content, "// CreatedAt createdAt time.Time{} gorm:\"column:created_at\" json:\"createdAt,omitempty\"\n")
+}
@@ -47,7 +47,6 @@ func (impl *generatorImpl) checkModuleCfg(cfg *ModuleCfg) error {
 	requiredFields := map[string]string{
 		"packageName": cfg.PackageName,
 		"tableName":   cfg.TableName,
-		"tplDir":      cfg.TplDir,
 		"rootDir":     cfg.RootDir,
 	}
 
@@ -56,6 +55,9 @@ func (impl *generatorImpl) checkModuleCfg(cfg *ModuleCfg) error {
 			return fmt.Errorf("%s is required", field)
 		}
 	}
+	if cfg.TplDir == "" && cfg.TplFS == nil {
+		return fmt.Errorf("tplDir or tplFS is required")
+	}
 	return nil
 }
 
@@ -84,7 +86,6 @@ func (impl *generatorImpl) checkControllerCfg(cfg *ApiCfg) error {
 	requiredFields := map[string]string{
 		"packageName":    cfg.PackageName,
 		"targetFilename": cfg.TargetFilename,
-		"tplDir":         cfg.TplDir,
 		"rootDir":        cfg.RootDir,
 	}
 
@@ -93,6 +94,9 @@ func (impl *generatorImpl) checkControllerCfg(cfg *ApiCfg) error {
 			return fmt.Errorf("%s is required", field)
 		}
 	}
+	if cfg.TplDir == "" && cfg.TplFS == nil {
+		return fmt.Errorf("tplDir or tplFS is required")
+	}
 	if !strings.HasSuffix(cfg.TargetFilename, goFileExtension) {
 		return fmt.Errorf("targetFilename should end with %s", goFileExtension)
 	}
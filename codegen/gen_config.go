@@ -1,8 +1,10 @@
 package codegen
 
 import (
+	"io/fs"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/morehao/golib/gutil"
 )
@@ -10,17 +12,48 @@ import (
 type CommonConfig struct {
 	PackageName       string                    // 包名
 	TplDir            string                    // 模板目录
+	TplFS             fs.FS                     // 模板文件系统，模板文件位于其根目录，设置后优先于 TplDir，如 DefaultModuleTplFS()
 	RootDir           string                    // 生成文件的根目录
 	LayerParentDirMap map[LayerName]string      // 各层级父目录，如果为空则使用默认规则
 	LayerNameMap      map[LayerName]LayerName   // 各层级名称，如果为空则使用默认规则
 	LayerPrefixMap    map[LayerName]LayerPrefix // 各层级前缀，如果为空则使用默认规则
-	TplFuncMap        template.FuncMap          // 模板函数
+	TplFuncMap        template.FuncMap          // 自定义模板函数，与内置函数（见 DefaultTplFuncMap）同名时覆盖内置函数
+	OutputPathTplMap  map[LayerName]string      // 各层级输出路径模板，参数见 OutputPathTplParams，相对路径基于 RootDir；以 .go 结尾时同时指定文件名，否则只指定目录。设置后忽略该层级的默认目录规则
+	MigrationVersion  string                    // 迁移版本号，为空时使用当前时间，如20060102150405；批量生成多张表时追加序号，如20060102150405001
+}
+
+// OutputPathTplParams 输出路径模板的参数，如 internal/{{.PackageName}}/dao/{{.TableName}}.go
+type OutputPathTplParams struct {
+	PackageName      string      // 包名
+	TableName        string      // 表名，api 代码生成时为目标文件名（不含扩展名）
+	LayerName        LayerName   // 层级名称，如 dao
+	LayerPrefix      LayerPrefix // 层级前缀，如 dao
+	TargetFilename   string      // 默认规则下的目标文件名，如 user.go
+	MigrationVersion string      // 迁移版本号
 }
 
 type ModuleCfg struct {
 	CommonConfig
 	TableName     string            `validate:"required"` // 表名
+	TableNames    []string          // 批量生成的表名，仅 GenerateModules、GenerateModulesFromDDL 使用，支持 glob、re: 前缀的正则和 ! 前缀的排除，规则见 MatchTableNames
 	ColumnTypeMap map[string]string // 表字段类型映射，入股为空则使用默认规则
+	NoPKStrategy  NoPKStrategy      // 无主键表的处理策略，默认 NoPKStrategyUniqueIndex
+	SchemaName    string            // 读取表结构的 schema，postgresql 默认 public，mysql 为库名，默认当前连接的库
+	Dialect       string            // DDL 方言，mysql 或 postgres，仅 GenerateFromDDL 使用，为空时根据语句特征识别
+	ImportPath    string            // RootDir 对应的 Go 导入路径，如 github.com/foo/bar/internal，GenerateModule 据此生成跨层级的 import
+	GenClient     bool              // GenerateModule 是否同时生成供其他服务调用的 ghttp 客户端，使用内置的 client 模板，生成到 client/client{包名} 目录
+	DaoTest       DaoTestMode       // GenerateModule 同时生成的 dao 层单元测试，默认不生成，生成到 dao 层目录的 {表名}_test.go
+	GenProto      bool              // GenerateModule 是否同时生成 proto 文件，包含各接口的消息和 CRUD service，生成到 proto/{包名}/{表名}.proto，重新生成时保持字段编号不变
+	// ErrorCodeBase 大于 0 时 GenerateModule 同时生成模块错误码，从 ErrorCodeBase 起分配 code 层目录中未被占用的号段，
+	// 生成到 code/{表名}.go 并在 init 中注册到 gerror，不同模块的错误码冲突时启动即 panic
+	ErrorCodeBase      int
+	ErrorCodeRangeSize int  // 每个模块的错误码号段大小，默认 100
+	DryRun             bool // 只渲染不写入文件，渲染结果见 GenerateModuleRes.Files，可用于预览或与已有文件对比
+	// NamingStrategy 结构体名和字段名的命名规则，如去除表名前缀、缩写词大写，为空时直接转为大驼峰
+	NamingStrategy *NamingStrategy
+	// TypeOverrides 按列覆盖字段类型，key 为列名或 表名.列名（优先），value 为 Go 类型，如 gorm.DeletedAt、decimal.Decimal、
+	// *github.com/foo/bar/money.Amount，常用包以外的类型需写完整导入路径。覆盖的类型原样使用，可空列不再转为指针，也不识别枚举
+	TypeOverrides map[string]string
 }
 
 type ApiCfg struct {
@@ -28,8 +61,12 @@ type ApiCfg struct {
 	TargetFilename string // 目标文件名
 }
 
+// format 规范化配置并填充默认值，各生成入口在分析模板前调用
 func (cfg *CommonConfig) format() {
 	cfg.PackageName = strings.ToLower(gutil.SnakeToPascal(cfg.PackageName))
+	if cfg.MigrationVersion == "" {
+		cfg.MigrationVersion = time.Now().Format(migrationVersionLayout)
+	}
 }
 
 type LayerName string
@@ -51,6 +88,8 @@ const (
 	LayerNameDao        LayerName = "dao"
 	LayerNameCode       LayerName = "code"
 	LayerNameObject     LayerName = "object"
+	LayerNameMigration  LayerName = "migration"
+	LayerNameClient     LayerName = "client"
 
 	defaultLayerNameRequest  LayerName = "dto"
 	defaultLayerNameResponse LayerName = "dto"
@@ -60,6 +99,9 @@ const (
 	defaultLayerPrefixDto        LayerPrefix = "dto"
 	defaultLayerPrefixDao        LayerPrefix = "dao"
 	defaultLayerPrefixObject     LayerPrefix = "obj"
+	defaultLayerPrefixClient     LayerPrefix = "client"
+
+	migrationVersionLayout = "20060102150405"
 )
 
 var defaultLayerPrefixMap = map[LayerName]LayerPrefix{
@@ -70,6 +112,7 @@ var defaultLayerPrefixMap = map[LayerName]LayerPrefix{
 	LayerNameRequest:    defaultLayerPrefixDto,
 	LayerNameResponse:   defaultLayerPrefixDto,
 	LayerNameObject:     defaultLayerPrefixObject,
+	LayerNameClient:     defaultLayerPrefixClient,
 }
 
 var defaultLayerSpecialNameMap = map[LayerName]LayerName{
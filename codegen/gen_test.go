@@ -3,6 +3,7 @@ package codegen
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -343,3 +344,113 @@ func TestGenModelCodeWithPostgreSQL(t *testing.T) {
 	})
 	assert.Nil(t, err)
 }
+
+func TestGenMigrationCode(t *testing.T) {
+	// 获取当前的运行路径
+	workDir, getErr := os.Getwd()
+	assert.Nil(t, getErr)
+	tplDir := fmt.Sprintf("%s/example/tplExample/migration", workDir)
+	rootDir := t.TempDir()
+	cfg := CommonConfig{
+		PackageName:      "user",
+		TplDir:           tplDir,
+		RootDir:          rootDir,
+		MigrationVersion: "20260101000000",
+	}
+	tplAnalysisList, analysisErr := analysisTplFiles(cfg, "user")
+	assert.Nil(t, analysisErr)
+	assert.Len(t, tplAnalysisList, 1)
+	assert.Equal(t, filepath.Join(rootDir, "migration"), tplAnalysisList[0].TargetDir)
+	assert.Equal(t, "20260101000000_user.go", tplAnalysisList[0].TargetFilename)
+
+	type Param struct {
+		Dialect          string
+		MigrationVersion string
+		TableName        string
+		PrimaryKeys      []string
+		ModelFields      []ModelField
+	}
+	tplItem := tplAnalysisList[0]
+	// 标识符按方言引用，user 在两种数据库中都是保留字
+	for dialect, wantSQL := range map[string]string{
+		dbTypeMysql:      "\"CREATE TABLE IF NOT EXISTS `user` (\\n\\t`id` bigint unsigned NOT NULL,\\n\\t`name` varchar(64),\\n\\tPRIMARY KEY (`id`)\\n)\"",
+		dbTypePostgresql: `"CREATE TABLE IF NOT EXISTS \"user\" (\n\t\"id\" bigint NOT NULL,\n\t\"name\" varchar(64),\n\tPRIMARY KEY (\"id\")\n)"`,
+	} {
+		columnType := "bigint unsigned"
+		if dialect == dbTypePostgresql {
+			columnType = "bigint"
+		}
+		targetDir := filepath.Join(t.TempDir(), "migration")
+		err := NewGenerator().Gen(&GenParams{
+			ParamsList: []GenParamsItem{
+				{
+					TargetDir:      targetDir,
+					TargetFileName: tplItem.TargetFilename,
+					Template:       tplItem.Template,
+					ExtraParams: &Param{
+						Dialect:          dialect,
+						MigrationVersion: cfg.MigrationVersion,
+						TableName:        "user",
+						PrimaryKeys:      []string{"id"},
+						ModelFields: []ModelField{
+							{ColumnName: "id", ColumnType: columnType, ColumnKey: ColumnKeyPRI},
+							{ColumnName: "name", ColumnType: "varchar(64)", IsNullable: true},
+						},
+					},
+				},
+			},
+		})
+		assert.Nil(t, err)
+		content, readErr := os.ReadFile(filepath.Join(targetDir, tplItem.TargetFilename))
+		assert.Nil(t, readErr)
+		assert.Contains(t, string(content), `Version: "20260101000000"`)
+		assert.Contains(t, string(content), "tx.Exec("+wantSQL+").Error", dialect)
+	}
+}
+
+func TestTplQuoteIdent(t *testing.T) {
+	assert.Equal(t, "`user`", tplQuoteIdent(dbTypeMysql, "user"))
+	assert.Equal(t, "`a``b`", tplQuoteIdent("", "a`b"))
+	assert.Equal(t, `"user"`, tplQuoteIdent(dbTypePostgresql, "user"))
+	assert.Equal(t, `"a""b"`, tplQuoteIdent(dbTypePostgresql, `a"b`))
+}
+
+func TestOutputPathTpl(t *testing.T) {
+	workDir, getErr := os.Getwd()
+	assert.Nil(t, getErr)
+	rootDir := t.TempDir()
+	cfg := CommonConfig{
+		PackageName: "user",
+		TplDir:      fmt.Sprintf("%s/example/tplExample/api", workDir),
+		RootDir:     rootDir,
+		OutputPathTplMap: map[LayerName]string{
+			// 只指定目录，文件名沿用默认规则
+			LayerNameService: "apps/{{.PackageName}}/internal/{{.LayerPrefix}}{{.PackageName}}",
+			// 同时指定目录和文件名
+			LayerNameController: "apps/{{.PackageName}}/internal/handler/{{.TableName}}_handler.go",
+		},
+	}
+	tplAnalysisList, analysisErr := analysisTplFiles(cfg, "profile.go")
+	assert.Nil(t, analysisErr)
+
+	items := make(map[LayerName]TplAnalysisItem)
+	for _, item := range tplAnalysisList {
+		items[item.OriginLayerName] = item
+	}
+	service := items[LayerNameService]
+	assert.Equal(t, filepath.Join(rootDir, "apps/user/internal/svcuser"), service.TargetDir)
+	assert.Equal(t, "profile.go", service.TargetFilename)
+	assert.Equal(t, "svcuser", service.TargetPackage)
+
+	controller := items[LayerNameController]
+	assert.Equal(t, filepath.Join(rootDir, "apps/user/internal/handler"), controller.TargetDir)
+	assert.Equal(t, "profile_handler.go", controller.TargetFilename)
+	assert.Equal(t, "handler", controller.TargetPackage)
+
+	// 未配置模板的层级使用默认规则
+	assert.Equal(t, filepath.Join(rootDir, "code"), items[LayerNameCode].TargetDir)
+
+	cfg.OutputPathTplMap = map[LayerName]string{LayerNameService: "{{.Unknown}}"}
+	_, analysisErr = analysisTplFiles(cfg, "profile.go")
+	assert.NotNil(t, analysisErr)
+}
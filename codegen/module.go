@@ -0,0 +1,490 @@
+package codegen
+
+import (
+	"embed"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/morehao/golib/gutil"
+	"gorm.io/gorm"
+)
+
+//go:embed templates/module/*.tpl templates/client/*.tpl templates/daotest/*/*.tpl templates/code/*.tpl
+var defaultModuleTplFS embed.FS
+
+const (
+	defaultModuleTplDir = "templates/module"
+	defaultClientTplDir = "templates/client"
+)
+
+const (
+	columnCreatedAt = "created_at"
+	columnUpdatedAt = "updated_at"
+	columnDeletedAt = "deleted_at"
+)
+
+// DefaultModuleTplFS 返回内置的模块模板，包含 model、dao、dto、service、controller、router 六个层级，
+// 模板参数见 ModuleTplParams，可复制到本地目录修改后通过 TplDir 使用
+func DefaultModuleTplFS() fs.FS {
+	sub, _ := fs.Sub(defaultModuleTplFS, defaultModuleTplDir)
+	return sub
+}
+
+// DefaultClientTplFS 返回内置的客户端模板，根据生成的 CRUD 接口生成基于 ghttp 的客户端，模板参数同 ModuleTplParams
+func DefaultClientTplFS() fs.FS {
+	sub, _ := fs.Sub(defaultModuleTplFS, defaultClientTplDir)
+	return sub
+}
+
+// ModuleLayer 生成文件所在层级的包信息
+type ModuleLayer struct {
+	Package    string // 包名，如 daouser
+	ImportPath string // 导入路径，如 github.com/foo/bar/internal/dao/daouser
+}
+
+// ModuleTplField 模板使用的字段，FieldType 已按规则修正：未映射的类型为 string，可空列为指针，deleted_at 为 gorm.DeletedAt，
+// 通过 ModuleCfg.TypeOverrides 覆盖的类型原样使用
+type ModuleTplField struct {
+	ModelField
+	IsPK     bool   // 是否为行标识字段
+	JSONName string // json 标签名，如 userName
+	VarName  string // 作为函数参数时的变量名，已避开关键字
+	SwagTag  string // swaggo 文档需要的额外标签，如 extensions:"x-nullable"，无需时为空
+	// Enum 枚举定义，非枚举字段及行标识字段为 nil
+	Enum *FieldEnum
+	// ModelType model 层的字段类型，枚举字段为枚举类型，如 *UserRoleStatus，其余同 FieldType
+	ModelType string
+}
+
+// IsPointer 字段类型是否为指针
+func (f ModuleTplField) IsPointer() bool {
+	return strings.HasPrefix(f.FieldType, "*")
+}
+
+// ModuleTplFilterField 查询条件字段，用于生成 Filter 结构体
+type ModuleTplFilterField struct {
+	ModuleTplField
+	ValueType string // 条件值类型，不带指针，如 int64、time.Time
+	HasIn     bool   // 是否生成 IN 条件，整型和字符串字段
+	HasRange  bool   // 是否生成范围条件，数值和时间字段
+	HasLike   bool   // 是否生成 LIKE 条件，字符串字段
+}
+
+// ModuleTplParams GenerateModule 传给模板的参数
+type ModuleTplParams struct {
+	Dialect       string                 // 数据库方言，mysql 或 postgres
+	Package       string                 // 当前生成文件的包名
+	PackageName   string                 // 模块包名
+	TableName     string                 // 表名
+	StructName    string                 // 模型结构体名
+	ModelFields   []ModuleTplField       // 全部字段
+	PKFields      []ModuleTplField       // 行标识字段
+	CreateFields  []ModuleTplField       // 创建时可写入的字段，排除自增主键和 created_at、updated_at、deleted_at
+	UpdateFields  []ModuleTplField       // 更新时可写入的字段，排除行标识字段和 created_at、updated_at、deleted_at
+	ItemFields    []ModuleTplField       // 对外返回的字段，排除 deleted_at
+	IsCompositePK bool                   // 行标识是否由多列组成
+	HasPK         bool                   // 是否存在行标识字段，为 false 时不生成按主键查询、更新、删除的方法
+	HasUpdate     bool                   // 是否生成更新方法，需存在行标识字段和可更新字段
+	HasSoftDelete bool                   // 是否存在类型为 gorm.DeletedAt 的 deleted_at 字段，删除时为软删除
+	IsAutoIncrPK  bool                   // 主键是否为单列整型，gorm 将其视为自增主键，创建时由数据库生成
+	PKWhere       string                 // 按行标识查询的条件，如 id = ?
+	PKOrder       string                 // 按行标识倒序排列的子句，如 id DESC
+	ModelImports  []string               // model 层需要的额外导入
+	DtoImports    []string               // dto 层需要的额外导入
+	PKImports     []string               // 行标识字段类型需要的额外导入
+	FilterFields  []ModuleTplFilterField // 查询条件字段，排除 deleted_at 及 json、[]byte 等无法比较的类型
+	DaoImports    []string               // dao 层需要的额外导入，包含行标识字段和查询条件字段的类型
+	Enums         []*FieldEnum           // 枚举定义，按字段顺序排列
+	Indexes       []TableIndex           // 索引定义，不含主键
+	ForeignKeys   []ForeignKey           // 外键定义
+	Layers        map[string]ModuleLayer // 各层级的包信息，key 为模板文件对应的原始层级名称，如 dao
+	// ErrorCodes 模块错误码，ErrorCodeBase 大于 0 时按分配的号段生成，依次为 Create、Delete、Update、GetDetail、GetPageList、NotExist
+	ErrorCodes     []ModuleTplErrorCode
+	ErrorCodeStart int // 错误码号段起点
+	ErrorCodeEnd   int // 错误码号段终点（含）
+}
+
+// GenerateModuleRes 模块代码生成结果
+type GenerateModuleRes struct {
+	GeneratedFiles []string        // 生成的文件，DryRun 时为将要生成的文件
+	SkippedFiles   []string        // 已存在而跳过的文件
+	Files          []GeneratedFile // 各文件的渲染结果，仅 DryRun 时返回，包含已存在而跳过的文件
+}
+
+// GeneratedFile 单个文件的渲染结果
+type GeneratedFile struct {
+	Path    string // 目标文件路径
+	Content []byte // 格式化后的文件内容
+	Exist   bool   // 目标文件是否已存在，已存在的文件实际生成时会跳过，Overwrite 为 true 时除外
+	// Overwrite 已存在时是否覆盖，如 proto 文件合并已有字段编号后覆盖写入
+	Overwrite bool
+}
+
+// GenerateModule 读取表结构并生成模块的 CRUD 代码，未设置 TplDir 和 TplFS 时使用内置模板，
+// 此时 ImportPath 必填。为避免重复声明，目标文件已存在时跳过该文件
+func GenerateModule(db *gorm.DB, cfg *ModuleCfg) (*GenerateModuleRes, error) {
+	moduleCfg, prepareErr := prepareModuleCfg(cfg)
+	if prepareErr != nil {
+		return nil, prepareErr
+	}
+	generator := NewGenerator()
+	analysisRes, analysisErr := generator.AnalysisModuleTpl(db, moduleCfg)
+	if analysisErr != nil {
+		return nil, analysisErr
+	}
+	return genModule(generator, moduleCfg, analysisRes)
+}
+
+// prepareModuleCfg 复制配置，未设置模板时使用内置模板
+func prepareModuleCfg(cfg *ModuleCfg) (*ModuleCfg, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("cfg is nil")
+	}
+	moduleCfg := *cfg
+	if moduleCfg.TplDir == "" && moduleCfg.TplFS == nil {
+		if moduleCfg.ImportPath == "" {
+			return nil, fmt.Errorf("importPath is required when using default templates")
+		}
+		moduleCfg.TplFS = DefaultModuleTplFS()
+	}
+	return &moduleCfg, nil
+}
+
+func genModule(generator Generator, cfg *ModuleCfg, analysisRes *ModuleTplAnalysisRes) (*GenerateModuleRes, error) {
+	if cfg.GenClient && len(analysisRes.TplAnalysisList) > 0 {
+		clientCfg := cfg.CommonConfig
+		clientCfg.TplDir, clientCfg.TplFS = "", DefaultClientTplFS()
+		clientTplList, analysisErr := analysisTplFiles(clientCfg, cfg.TableName)
+		if analysisErr != nil {
+			return nil, analysisErr
+		}
+		for _, v := range clientTplList {
+			analysisRes.TplAnalysisList = append(analysisRes.TplAnalysisList, ModuleTplAnalysisItem{
+				TplAnalysisItem: v,
+				ModelFields:     analysisRes.TplAnalysisList[0].ModelFields,
+			})
+		}
+	}
+	if cfg.DaoTest != DaoTestNone && len(analysisRes.TplAnalysisList) > 0 {
+		daoTestTplList, analysisErr := analysisDaoTestTpl(cfg, analysisRes.TplAnalysisList)
+		if analysisErr != nil {
+			return nil, analysisErr
+		}
+		analysisRes.TplAnalysisList = append(analysisRes.TplAnalysisList, daoTestTplList...)
+	}
+	if cfg.ErrorCodeBase > 0 && len(analysisRes.TplAnalysisList) > 0 {
+		codeTplList, analysisErr := analysisErrorCodeTpl(cfg, analysisRes.TplAnalysisList)
+		if analysisErr != nil {
+			return nil, analysisErr
+		}
+		analysisRes.TplAnalysisList = append(analysisRes.TplAnalysisList, codeTplList...)
+	}
+	params, buildErr := buildModuleTplParams(cfg, analysisRes)
+	if buildErr != nil {
+		return nil, buildErr
+	}
+	res := &GenerateModuleRes{}
+	var paramsList []GenParamsItem
+	for _, item := range analysisRes.TplAnalysisList {
+		targetFilepath := filepath.Join(item.TargetDir, item.TargetFilename)
+		itemParams := *params
+		itemParams.Package = item.TargetPackage
+		if cfg.DryRun {
+			content, renderErr := renderTemplate(item.Template, &itemParams)
+			if renderErr != nil {
+				return nil, fmt.Errorf("render %s fail, error: %w", targetFilepath, renderErr)
+			}
+			res.Files = append(res.Files, GeneratedFile{Path: targetFilepath, Content: content, Exist: item.TargetFileExist})
+		}
+		if item.TargetFileExist {
+			res.SkippedFiles = append(res.SkippedFiles, targetFilepath)
+			continue
+		}
+		paramsList = append(paramsList, GenParamsItem{
+			Template:       item.Template,
+			TargetDir:      item.TargetDir,
+			TargetFileName: item.TargetFilename,
+			ExtraParams:    &itemParams,
+		})
+		res.GeneratedFiles = append(res.GeneratedFiles, targetFilepath)
+	}
+	var protoFile *GeneratedFile
+	if cfg.GenProto && len(analysisRes.TplAnalysisList) > 0 {
+		var protoErr error
+		if protoFile, protoErr = buildProtoFile(cfg, params); protoErr != nil {
+			return nil, protoErr
+		}
+		if cfg.DryRun {
+			res.Files = append(res.Files, *protoFile)
+		}
+		res.GeneratedFiles = append(res.GeneratedFiles, protoFile.Path)
+	}
+	if cfg.DryRun {
+		return res, nil
+	}
+	if len(paramsList) > 0 {
+		if err := generator.Gen(&GenParams{ParamsList: paramsList}); err != nil {
+			return nil, err
+		}
+	}
+	if protoFile != nil {
+		if err := writeProtoFile(protoFile); err != nil {
+			return nil, fmt.Errorf("write %s fail, error: %w", protoFile.Path, err)
+		}
+	}
+	return res, nil
+}
+
+// buildModuleTplParams 根据表结构分析结果构造模板参数
+func buildModuleTplParams(cfg *ModuleCfg, analysisRes *ModuleTplAnalysisRes) (*ModuleTplParams, error) {
+	params := &ModuleTplParams{
+		Dialect:       analysisRes.Dialect,
+		PackageName:   analysisRes.PackageName,
+		TableName:     analysisRes.TableName,
+		StructName:    analysisRes.StructName,
+		IsCompositePK: analysisRes.IsCompositePK,
+		HasPK:         analysisRes.HasPK,
+		Indexes:       analysisRes.Indexes,
+		ForeignKeys:   analysisRes.ForeignKeys,
+		Layers:        make(map[string]ModuleLayer, len(analysisRes.TplAnalysisList)),
+	}
+	for _, item := range analysisRes.TplAnalysisList {
+		importPath := cfg.ImportPath
+		if rel, err := filepath.Rel(cfg.RootDir, item.TargetDir); err == nil && rel != "." {
+			importPath = path.Join(importPath, filepath.ToSlash(rel))
+		}
+		params.Layers[string(item.OriginLayerName)] = ModuleLayer{
+			Package:    item.TargetPackage,
+			ImportPath: importPath,
+		}
+	}
+	if len(analysisRes.TplAnalysisList) == 0 {
+		return params, nil
+	}
+
+	pkColumns := make(map[string]bool, len(analysisRes.PKFields))
+	for _, field := range analysisRes.PKFields {
+		pkColumns[field.ColumnName] = true
+	}
+	// 单列整型主键视为自增主键，创建时不由请求写入
+	autoIncrementPK := len(analysisRes.PrimaryKeys) == 1 && isIntegerType(analysisRes.PKFields[0].FieldType)
+
+	fieldMap := make(map[string]ModuleTplField)
+	for _, field := range analysisRes.TplAnalysisList[0].ModelFields {
+		tplField := newModuleTplField(analysisRes.StructName, field, pkColumns[field.ColumnName])
+		if tplField.Enum != nil {
+			params.Enums = append(params.Enums, tplField.Enum)
+		}
+		fieldMap[field.ColumnName] = tplField
+		params.ModelFields = append(params.ModelFields, tplField)
+
+		isAutoColumn := field.ColumnName == columnCreatedAt || field.ColumnName == columnUpdatedAt || field.ColumnName == columnDeletedAt
+		if field.ColumnName != columnDeletedAt {
+			params.ItemFields = append(params.ItemFields, tplField)
+		} else if tplField.FieldType == "gorm.DeletedAt" {
+			params.HasSoftDelete = true
+		}
+		if !isAutoColumn && !(tplField.IsPK && autoIncrementPK) {
+			params.CreateFields = append(params.CreateFields, tplField)
+		}
+		if !isAutoColumn && !tplField.IsPK {
+			params.UpdateFields = append(params.UpdateFields, tplField)
+		}
+		if filterField, ok := newModuleTplFilterField(tplField); ok {
+			params.FilterFields = append(params.FilterFields, filterField)
+		}
+	}
+
+	var whereList, orderList []string
+	for _, field := range analysisRes.PKFields {
+		tplField, ok := fieldMap[field.ColumnName]
+		if !ok {
+			return nil, fmt.Errorf("pk column %s not found in table %s", field.ColumnName, analysisRes.TableName)
+		}
+		params.PKFields = append(params.PKFields, tplField)
+		whereList = append(whereList, fmt.Sprintf("%s = ?", field.ColumnName))
+		orderList = append(orderList, fmt.Sprintf("%s DESC", field.ColumnName))
+	}
+	params.PKWhere = strings.Join(whereList, " AND ")
+	params.PKOrder = strings.Join(orderList, ", ")
+	params.HasUpdate = params.HasPK && len(params.UpdateFields) > 0
+	params.IsAutoIncrPK = autoIncrementPK
+	params.ModelImports = append(fieldImports(params.ModelFields), enumImports(params.Enums)...)
+	sort.Strings(params.ModelImports)
+	params.DtoImports = fieldImports(params.ItemFields)
+	params.PKImports = fieldImports(params.PKFields)
+	params.DaoImports = daoImports(params.PKFields, params.FilterFields)
+	if cfg.ErrorCodeBase > 0 {
+		for _, item := range analysisRes.TplAnalysisList {
+			if item.OriginLayerName != LayerNameCode {
+				continue
+			}
+			var err error
+			params.ErrorCodeStart, params.ErrorCodeEnd, params.ErrorCodes, err = buildErrorCodes(cfg, item, params.StructName, params.TableName)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return params, nil
+}
+
+// newModuleTplFilterField 根据字段类型确定支持的查询条件，不支持过滤的字段返回 false
+func newModuleTplFilterField(field ModuleTplField) (ModuleTplFilterField, bool) {
+	valueType := strings.TrimPrefix(field.FieldType, "*")
+	if field.ColumnName == columnDeletedAt || isReferenceType(valueType) || strings.HasPrefix(valueType, "gorm.") {
+		return ModuleTplFilterField{}, false
+	}
+	isNumber := isIntegerType(valueType) || valueType == "float32" || valueType == "float64" || valueType == "time.Duration"
+	return ModuleTplFilterField{
+		ModuleTplField: field,
+		ValueType:      valueType,
+		HasIn:          isIntegerType(valueType) || valueType == "string",
+		HasRange:       isNumber || valueType == "time.Time",
+		HasLike:        valueType == "string",
+	}, true
+}
+
+// daoImports 合并行标识字段和查询条件字段需要的导入，LIKE 条件转义通配符需要 strings
+func daoImports(pkFields []ModuleTplField, filterFields []ModuleTplFilterField) []string {
+	fields := append([]ModuleTplField(nil), pkFields...)
+	var hasLike bool
+	for _, field := range filterFields {
+		fields = append(fields, field.ModuleTplField)
+		hasLike = hasLike || field.HasLike
+	}
+	imports := fieldImports(fields)
+	if hasLike {
+		imports = append(imports, "strings")
+		sort.Strings(imports)
+	}
+	return imports
+}
+
+// reservedVarNames 内置模板中已占用的标识符，字段变量名与之冲突时追加后缀
+var reservedVarNames = map[string]bool{
+	"ctx": true, "d": true, "dao": true, "entity": true, "err": true, "fields": true,
+	"context": true, "errors": true, "gorm": true,
+}
+
+func newModuleTplField(structName string, field ModelField, isPK bool) ModuleTplField {
+	if field.FieldType == "" {
+		field.FieldType = "string"
+	}
+	var enum *FieldEnum
+	if !isPK && !field.IsTypeOverride {
+		enum = parseFieldEnum(structName, field)
+	}
+	switch {
+	case field.IsTypeOverride:
+		// 覆盖的类型原样使用
+	case field.ColumnName == columnDeletedAt && field.FieldType == "time.Time":
+		field.FieldType = "gorm.DeletedAt"
+	case field.IsNullable && !isPK && !isReferenceType(field.FieldType):
+		field.FieldType = "*" + field.FieldType
+	}
+	// 注释写在行尾，去掉换行避免破坏生成的代码
+	field.Comment = strings.Join(strings.Fields(field.Comment), " ")
+
+	jsonName := gutil.SnakeToLowerCamel(field.ColumnName)
+	varName := jsonName
+	if token.IsKeyword(varName) || reservedVarNames[varName] {
+		varName += "Param"
+	}
+	tplField := ModuleTplField{
+		ModelField: field,
+		IsPK:       isPK,
+		JSONName:   jsonName,
+		VarName:    varName,
+		SwagTag:    swagTag(field.FieldType, field.IsNullable && !isPK),
+		Enum:       enum,
+		ModelType:  field.FieldType,
+	}
+	if enum != nil {
+		tplField.ModelType = strings.TrimSuffix(field.FieldType, enum.BaseType) + enum.TypeName
+		if enums := enum.SwagEnums(); enums != "" {
+			tplField.SwagTag = strings.TrimSpace(tplField.SwagTag + ` enums:"` + enums + `"`)
+		}
+	}
+	return tplField
+}
+
+// swagTag 为 swaggo 无法正确推断的类型指定文档类型，可空字段标记 x-nullable
+func swagTag(fieldType string, nullable bool) string {
+	var tags []string
+	if nullable {
+		tags = append(tags, `extensions:"x-nullable"`)
+	}
+	switch strings.TrimPrefix(fieldType, "*") {
+	case "json.RawMessage":
+		tags = append(tags, `swaggertype:"object"`)
+	case "time.Duration":
+		tags = append(tags, `swaggertype:"integer"`)
+	case "gorm.DeletedAt":
+		tags = append(tags, `swaggertype:"string" format:"date-time"`)
+	}
+	return strings.Join(tags, " ")
+}
+
+// isReferenceType 判断类型零值是否可表示 NULL，此类类型不需要转为指针
+func isReferenceType(fieldType string) bool {
+	return strings.HasPrefix(fieldType, "*") || strings.HasPrefix(fieldType, "[]") ||
+		strings.HasPrefix(fieldType, "map[") || fieldType == "json.RawMessage" ||
+		fieldType == "any" || fieldType == "interface{}"
+}
+
+func isIntegerType(fieldType string) bool {
+	switch strings.TrimPrefix(fieldType, "u") {
+	case "int", "int8", "int16", "int32", "int64":
+		return true
+	}
+	return false
+}
+
+// enumImports 枚举类型的方法需要的导入：整型枚举的 String 使用 fmt，set 使用 strings
+func enumImports(enums []*FieldEnum) []string {
+	var hasInteger, hasSet bool
+	for _, enum := range enums {
+		hasInteger = hasInteger || !enum.IsString()
+		hasSet = hasSet || enum.IsSet
+	}
+	var imports []string
+	if hasInteger {
+		imports = append(imports, "fmt")
+	}
+	if hasSet {
+		imports = append(imports, "strings")
+	}
+	return imports
+}
+
+// fieldImports 根据字段类型推断需要导入的标准库和 gorm 包
+func fieldImports(fields []ModuleTplField) []string {
+	importSet := make(map[string]bool)
+	for _, field := range fields {
+		if field.TypeImport != "" {
+			importSet[field.TypeImport] = true
+			continue
+		}
+		switch fieldType := strings.TrimLeft(field.FieldType, "*[]"); {
+		case strings.HasPrefix(fieldType, "time."):
+			importSet["time"] = true
+		case strings.HasPrefix(fieldType, "json."):
+			importSet["encoding/json"] = true
+		case strings.HasPrefix(fieldType, "gorm."):
+			importSet["gorm.io/gorm"] = true
+		}
+	}
+	imports := make([]string, 0, len(importSet))
+	for importPath := range importSet {
+		imports = append(imports, importPath)
+	}
+	sort.Strings(imports)
+	return imports
+}
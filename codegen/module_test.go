@@ -0,0 +1,252 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestModuleAnalysisRes 使用内置模板构造模块分析结果，模拟从数据库读取的表结构
+func newTestModuleAnalysisRes(t *testing.T, cfg *ModuleCfg, fields []ModelField, primaryKeys []string) *ModuleTplAnalysisRes {
+	t.Helper()
+	cfg.format()
+	tplAnalysisList, err := analysisTplFiles(cfg.CommonConfig, cfg.TableName)
+	require.Nil(t, err)
+	var pkFields []ModelField
+	for _, pk := range primaryKeys {
+		for _, field := range fields {
+			if field.ColumnName == pk {
+				pkFields = append(pkFields, field)
+			}
+		}
+	}
+	res := &ModuleTplAnalysisRes{
+		PackageName:   cfg.PackageName,
+		TableName:     cfg.TableName,
+		StructName:    "UserRole",
+		PrimaryKeys:   primaryKeys,
+		PKFields:      pkFields,
+		IsCompositePK: len(pkFields) > 1,
+		HasPK:         len(pkFields) > 0,
+	}
+	for _, item := range tplAnalysisList {
+		res.TplAnalysisList = append(res.TplAnalysisList, ModuleTplAnalysisItem{TplAnalysisItem: item, ModelFields: fields})
+	}
+	return res
+}
+
+func TestGenModuleWithDefaultTpl(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			TplFS:       DefaultModuleTplFS(),
+			RootDir:     rootDir,
+		},
+		TableName:  "user_role",
+		ImportPath: "example.com/demo/internal",
+	}
+	fields := []ModelField{
+		{FieldName: "Id", FieldType: "uint", ColumnName: "id", ColumnKey: ColumnKeyPRI},
+		{FieldName: "RoleName", FieldType: "string", ColumnName: "role_name", Comment: "角色\n名称"},
+		{FieldName: "Type", FieldType: "int8", ColumnName: "type", IsNullable: true},
+		{FieldName: "Extra", FieldType: "json.RawMessage", ColumnName: "extra", IsNullable: true},
+		{FieldName: "CreatedAt", FieldType: "time.Time", ColumnName: "created_at"},
+		{FieldName: "DeletedAt", FieldType: "time.Time", ColumnName: "deleted_at", IsNullable: true},
+	}
+	analysisRes := newTestModuleAnalysisRes(t, cfg, fields, []string{"id"})
+
+	res, err := genModule(NewGenerator(), cfg, analysisRes)
+	require.Nil(t, err)
+	assert.Len(t, res.GeneratedFiles, 6)
+	assert.Empty(t, res.SkippedFiles)
+
+	readFile := func(elem ...string) string {
+		content, readErr := os.ReadFile(filepath.Join(append([]string{rootDir}, elem...)...))
+		require.Nil(t, readErr)
+		return string(content)
+	}
+	model := readFile("model", "user_role.go")
+	assert.Contains(t, model, "package model")
+	assert.Contains(t, model, "Id        uint            `gorm:\"column:id;primaryKey\"`")
+	assert.Contains(t, model, "Type      *int8")
+	assert.Contains(t, model, "Extra     json.RawMessage")
+	assert.Contains(t, model, "DeletedAt gorm.DeletedAt")
+	assert.Contains(t, model, "// 角色 名称")
+
+	dto := readFile("dto", "dtouser", "user_role.go")
+	assert.Contains(t, dto, "type UserRoleUpdateReq struct")
+	assert.NotContains(t, dto, "deletedAt")
+	assert.Contains(t, dto, "`json:\"type\" extensions:\"x-nullable\"`")
+	assert.Contains(t, dto, "`json:\"extra\" extensions:\"x-nullable\" swaggertype:\"object\"`")
+
+	dao := readFile("dao", "daouser", "user_role.go")
+	assert.Contains(t, dao, `"example.com/demo/internal/model"`)
+	assert.Contains(t, dao, `Where("id = ?", id)`)
+	assert.Contains(t, dao, `Order("id DESC")`)
+	assert.Contains(t, dao, `"github.com/morehao/golib/biz/gobject"`)
+	assert.Contains(t, dao, "func (d *UserRoleDao) ListByFilter(ctx context.Context, filter *UserRoleFilter) ([]model.UserRole, int64, error)")
+	assert.Contains(t, dao, "TypeIn       []int8")
+	assert.Contains(t, dao, "CreatedAtGte *time.Time")
+	assert.Contains(t, dao, `db = db.Where("role_name LIKE ?", "%"+likeReplacer.Replace(*f.RoleNameLike)+"%")`)
+	assert.NotContains(t, dao, "ExtraIn")
+	assert.NotContains(t, dao, "DeletedAt")
+	assert.Contains(t, dao, "return db.Offset((f.PageQuery.Page - 1) * f.PageQuery.PageSize).Limit(f.PageQuery.PageSize)")
+
+	service := readFile("service", "svcuser", "user_role.go")
+	assert.Contains(t, service, `"type":      req.Type`)
+	assert.NotContains(t, service, "Id: req.Id")
+
+	controller := readFile("controller", "ctruser", "user_role.go")
+	assert.Contains(t, controller, `"example.com/demo/internal/service/svcuser"`)
+	assert.Contains(t, controller, "// @Param req body dtouser.UserRoleUpdateReq true")
+	assert.Contains(t, controller, "// @Success 200 {object} gincontext.DtoRender{data=dtouser.UserRolePageListRes}")
+	assert.Contains(t, controller, "// @Router /user_role/detail [post]")
+
+	router := readFile("router", "user.go")
+	assert.Contains(t, router, "func RegisterUserRoleRouter(routerGroup *gin.RouterGroup, ctr *ctruser.UserRoleCtr)")
+	assert.Contains(t, router, `group.POST("/update", ctr.Update)`)
+
+	// 再次生成时跳过已存在的文件
+	analysisRes = newTestModuleAnalysisRes(t, cfg, fields, []string{"id"})
+	res, err = genModule(NewGenerator(), cfg, analysisRes)
+	require.Nil(t, err)
+	assert.Empty(t, res.GeneratedFiles)
+	assert.Len(t, res.SkippedFiles, 6)
+}
+
+func TestGenModuleWithClient(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			TplFS:       DefaultModuleTplFS(),
+			RootDir:     rootDir,
+		},
+		TableName:  "user_role",
+		ImportPath: "example.com/demo/internal",
+		GenClient:  true,
+	}
+	fields := []ModelField{
+		{FieldName: "Id", FieldType: "uint", ColumnName: "id", ColumnKey: ColumnKeyPRI},
+		{FieldName: "RoleName", FieldType: "string", ColumnName: "role_name"},
+		{FieldName: "CreatedAt", FieldType: "time.Time", ColumnName: "created_at"},
+	}
+	analysisRes := newTestModuleAnalysisRes(t, cfg, fields, []string{"id"})
+
+	res, err := genModule(NewGenerator(), cfg, analysisRes)
+	require.Nil(t, err)
+	assert.Len(t, res.GeneratedFiles, 7)
+
+	content, err := os.ReadFile(filepath.Join(rootDir, "client", "clientuser", "user_role.go"))
+	require.Nil(t, err)
+	client := string(content)
+	assert.Contains(t, client, "package clientuser")
+	assert.Contains(t, client, `"time"`)
+	assert.Contains(t, client, "func NewUserRoleClient(client *ghttp.Client, basePath string) *UserRoleClient")
+	assert.Contains(t, client, `basePath: basePath + "/user_role"`)
+	assert.Contains(t, client, `c.client.PostJSONData(ctx, c.basePath+"/update", nil, ghttp.RequestOption{RequestBody: req})`)
+	assert.Contains(t, client, "func (c *UserRoleClient) Detail(ctx context.Context, req *UserRoleDetailReq) (*UserRoleItem, error)")
+}
+
+func TestGenModuleWithEnum(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			TplFS:       DefaultModuleTplFS(),
+			RootDir:     rootDir,
+		},
+		TableName:  "user_role",
+		ImportPath: "example.com/demo/internal",
+	}
+	fields := []ModelField{
+		{FieldName: "Id", FieldType: "uint", ColumnName: "id", ColumnKey: ColumnKeyPRI},
+		{FieldName: "Status", FieldType: "int8", ColumnName: "status", Comment: "状态: 1-enabled 2-disabled"},
+		{FieldName: "Level", FieldType: "string", ColumnName: "level", ColumnType: "enum('low','high')", IsNullable: true},
+		{FieldName: "Tags", FieldType: "string", ColumnName: "tags", ColumnType: "set('a','b')"},
+	}
+	analysisRes := newTestModuleAnalysisRes(t, cfg, fields, []string{"id"})
+
+	_, err := genModule(NewGenerator(), cfg, analysisRes)
+	require.Nil(t, err)
+
+	readFile := func(elem ...string) string {
+		content, readErr := os.ReadFile(filepath.Join(append([]string{rootDir}, elem...)...))
+		require.Nil(t, readErr)
+		return string(content)
+	}
+	model := readFile("model", "user_role.go")
+	assert.Contains(t, model, "Status UserRoleStatus")
+	assert.Contains(t, model, "Level  *UserRoleLevel")
+	assert.Contains(t, model, "type UserRoleStatus int8")
+	assert.Contains(t, model, "UserRoleStatusEnabled  UserRoleStatus = 1 // enabled")
+	assert.Contains(t, model, `UserRoleLevelHigh UserRoleLevel = "high"`)
+	assert.Contains(t, model, "func (v UserRoleTags) Has(member UserRoleTags) bool")
+	assert.Contains(t, model, `"fmt"`)
+	assert.Contains(t, model, `"strings"`)
+
+	dto := readFile("dto", "dtouser", "user_role.go")
+	assert.Contains(t, dto, "Status int8    `json:\"status\" binding:\"omitempty,oneof=1 2\" enums:\"1,2\"`")
+	assert.Contains(t, dto, "`json:\"level\" binding:\"omitempty,oneof=low high\" extensions:\"x-nullable\" enums:\"low,high\"`")
+	assert.Contains(t, dto, "Tags   string  `json:\"tags\"`")
+
+	service := readFile("service", "svcuser", "user_role.go")
+	assert.Contains(t, service, "Level:  (*model.UserRoleLevel)(req.Level)")
+	assert.Contains(t, service, "Status: (int8)(entity.Status)")
+}
+
+func TestGenModuleWithDefaultTplCompositePK(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			TplFS:       DefaultModuleTplFS(),
+			RootDir:     rootDir,
+		},
+		TableName:  "user_role",
+		ImportPath: "example.com/demo",
+	}
+	fields := []ModelField{
+		{FieldName: "UserId", FieldType: "uint", ColumnName: "user_id", ColumnKey: ColumnKeyPRI},
+		{FieldName: "RoleId", FieldType: "uint", ColumnName: "role_id", ColumnKey: ColumnKeyPRI},
+	}
+	analysisRes := newTestModuleAnalysisRes(t, cfg, fields, []string{"user_id", "role_id"})
+
+	_, err := genModule(NewGenerator(), cfg, analysisRes)
+	require.Nil(t, err)
+
+	dao, readErr := os.ReadFile(filepath.Join(rootDir, "dao", "daouser", "user_role.go"))
+	require.Nil(t, readErr)
+	assert.Contains(t, string(dao), `Where("user_id = ? AND role_id = ?", userId, roleId)`)
+	// 联合主键由请求写入，且没有可更新字段时不生成更新方法
+	assert.NotContains(t, string(dao), "UpdateByPK")
+	service, readErr := os.ReadFile(filepath.Join(rootDir, "service", "svcuser", "user_role.go"))
+	require.Nil(t, readErr)
+	assert.Contains(t, string(service), "UserId: req.UserId")
+}
+
+func TestGenerateModuleCheckCfg(t *testing.T) {
+	_, err := GenerateModule(nil, nil)
+	assert.NotNil(t, err)
+	_, err = GenerateModule(nil, &ModuleCfg{CommonConfig: CommonConfig{PackageName: "user", RootDir: t.TempDir()}, TableName: "user"})
+	assert.ErrorContains(t, err, "importPath is required")
+}
+
+func TestGenerateModule(t *testing.T) {
+	db := openMySQLForTest(t)
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     t.TempDir(),
+		},
+		TableName:  "user",
+		ImportPath: "example.com/demo/internal",
+	}
+	res, err := GenerateModule(db, cfg)
+	assert.Nil(t, err)
+	assert.Len(t, res.GeneratedFiles, 6)
+}
@@ -2,16 +2,20 @@ package codegen
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/morehao/golib/gutil"
 	"gorm.io/gorm"
 )
 
+// mysqlPrimaryIndexName mysql 主键索引的固定名称
+const mysqlPrimaryIndexName = "PRIMARY"
+
 type mysqlImpl struct {
 }
 
 func (impl *mysqlImpl) GetModuleTemplateParam(db *gorm.DB, cfg *ModuleCfg) (*ModuleTplAnalysisRes, error) {
-	dbName, getDbNameErr := getDbName(db)
+	dbName, getDbNameErr := mysqlSchemaName(db, cfg.SchemaName)
 	if getDbNameErr != nil {
 		return nil, getDbNameErr
 	}
@@ -28,6 +32,20 @@ func (impl *mysqlImpl) GetModuleTemplateParam(db *gorm.DB, cfg *ModuleCfg) (*Mod
 	if getFieldErr != nil {
 		return nil, getFieldErr
 	}
+	if err := applyModelFieldRules(cfg, modelFieldList); err != nil {
+		return nil, err
+	}
+
+	indexes, foreignKeys, metaErr := impl.getTableMeta(db, dbName, cfg.TableName)
+	if metaErr != nil {
+		return nil, metaErr
+	}
+	fillFieldMeta(modelFieldList, indexes, foreignKeys)
+
+	pkRes, pkErr := impl.analysisPK(db, dbName, cfg, modelFieldList)
+	if pkErr != nil {
+		return nil, pkErr
+	}
 
 	// 获取模板文件
 	tplAnalysisList, analysisErr := analysisTplFiles(cfg.CommonConfig, cfg.TableName)
@@ -43,20 +61,28 @@ func (impl *mysqlImpl) GetModuleTemplateParam(db *gorm.DB, cfg *ModuleCfg) (*Mod
 			ModelFields:     modelFieldList,
 		})
 	}
-	structName := gutil.SnakeToPascal(cfg.TableName)
+	structName := cfg.NamingStrategy.StructName(cfg.TableName)
 	res := &ModuleTplAnalysisRes{
-		PackageName:     cfg.PackageName,
-		TableName:       cfg.TableName,
-		StructName:      structName,
-		TplAnalysisList: moduleAnalysisList,
+		Dialect:          dbTypeMysql,
+		PackageName:      cfg.PackageName,
+		TableName:        cfg.TableName,
+		StructName:       structName,
+		MigrationVersion: cfg.MigrationVersion,
+		PrimaryKeys:      pkRes.PrimaryKeys,
+		PKFields:         pkRes.PKFields,
+		IsCompositePK:    pkRes.IsCompositePK,
+		HasPK:            pkRes.HasPK,
+		Indexes:          indexes,
+		ForeignKeys:      foreignKeys,
+		TplAnalysisList:  moduleAnalysisList,
 	}
 	return res, nil
 }
 
 func (impl *mysqlImpl) getModelField(db *gorm.DB, dbName string, cfg *ModuleCfg) ([]ModelField, error) {
 	var entities []mysqlTableColumn
-	getColumnSql := fmt.Sprintf("SELECT * FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s';", dbName, cfg.TableName)
-	if err := db.Raw(getColumnSql).Scan(&entities).Error; err != nil {
+	getColumnSql := "SELECT * FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION;"
+	if err := db.Raw(getColumnSql, dbName, cfg.TableName).Scan(&entities).Error; err != nil {
 		return nil, err
 	}
 
@@ -72,14 +98,15 @@ func (impl *mysqlImpl) getModelField(db *gorm.DB, dbName string, cfg *ModuleCfg)
 	var modelFieldList []ModelField
 	for _, v := range entities {
 		item := ModelField{
-			FieldName:    gutil.SnakeToPascal(v.ColumnName),
-			FieldType:    columnTypeMap[v.DataType],
-			ColumnName:   v.ColumnName,
-			ColumnType:   v.ColumnType,
-			ColumnKey:    v.ColumnKey,
-			IsNullable:   v.IsNullable == "YES",
-			DefaultValue: v.ColumnDefault.String,
-			Comment:      v.ColumnComment,
+			FieldName:       gutil.SnakeToPascal(v.ColumnName),
+			FieldType:       columnTypeMap[v.DataType],
+			ColumnName:      v.ColumnName,
+			ColumnType:      v.ColumnType,
+			ColumnKey:       v.ColumnKey,
+			IsNullable:      v.IsNullable == "YES",
+			DefaultValue:    v.ColumnDefault.String,
+			Comment:         v.ColumnComment,
+			IsAutoIncrement: strings.Contains(strings.ToLower(v.Extra), "auto_increment"),
 		}
 		if colIndexInfo, ok := indexInfoMap[v.ColumnName]; ok {
 			item.IndexName = colIndexInfo.IndexName
@@ -90,15 +117,82 @@ func (impl *mysqlImpl) getModelField(db *gorm.DB, dbName string, cfg *ModuleCfg)
 	return modelFieldList, nil
 }
 
-func (impl *mysqlImpl) getIndexInfo(db *gorm.DB, dbName, tableName string) (map[string]mysqlIndexInfo, error) {
+// analysisPK 分析主键，主键列取自 PRIMARY 索引以保证联合主键的列顺序
+func (impl *mysqlImpl) analysisPK(db *gorm.DB, dbName string, cfg *ModuleCfg, fields []ModelField) (*pkAnalysisRes, error) {
+	entities, err := impl.getIndexList(db, dbName, cfg.TableName)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		primaryKeys []string
+		indexes     []indexColumn
+	)
+	for _, v := range entities {
+		if v.IndexName == mysqlPrimaryIndexName {
+			primaryKeys = append(primaryKeys, v.ColumnName)
+			continue
+		}
+		indexes = append(indexes, indexColumn{
+			IndexName:  v.IndexName,
+			ColumnName: v.ColumnName,
+			IsUnique:   v.NonUnique == 0,
+			SeqInIndex: v.SeqInIndex,
+		})
+	}
+	return analysisPK(cfg.TableName, fields, primaryKeys, indexes, cfg.NoPKStrategy)
+}
+
+func (impl *mysqlImpl) getIndexList(db *gorm.DB, dbName, tableName string) ([]mysqlIndexInfo, error) {
 	var entities []mysqlIndexInfo
-	getIndexSql := fmt.Sprintf(`
+	getIndexSql := `
 		SELECT INDEX_NAME, COLUMN_NAME, NON_UNIQUE, SEQ_IN_INDEX
 		FROM INFORMATION_SCHEMA.STATISTICS
-		WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s'
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 		ORDER BY INDEX_NAME, SEQ_IN_INDEX;
-	`, dbName, tableName)
-	if err := db.Raw(getIndexSql).Scan(&entities).Error; err != nil {
+	`
+	if err := db.Raw(getIndexSql, dbName, tableName).Scan(&entities).Error; err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// getTableMeta 获取主键以外的索引定义和外键定义
+func (impl *mysqlImpl) getTableMeta(db *gorm.DB, dbName, tableName string) ([]TableIndex, []ForeignKey, error) {
+	entities, err := impl.getIndexList(db, dbName, tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var indexes []indexColumn
+	for _, v := range entities {
+		if v.IndexName == mysqlPrimaryIndexName {
+			continue
+		}
+		indexes = append(indexes, indexColumn{
+			IndexName:  v.IndexName,
+			ColumnName: v.ColumnName,
+			IsUnique:   v.NonUnique == 0,
+			SeqInIndex: v.SeqInIndex,
+		})
+	}
+
+	var fkColumns []foreignKeyColumn
+	getForeignKeySql := `
+		SELECT CONSTRAINT_NAME AS constraint_name, COLUMN_NAME AS column_name,
+			REFERENCED_TABLE_NAME AS ref_table_name, REFERENCED_COLUMN_NAME AS ref_column_name,
+			ORDINAL_POSITION AS seq_in_key
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION;
+	`
+	if err := db.Raw(getForeignKeySql, dbName, tableName).Scan(&fkColumns).Error; err != nil {
+		return nil, nil, err
+	}
+	return buildTableIndexes(indexes), buildForeignKeys(fkColumns), nil
+}
+
+func (impl *mysqlImpl) getIndexInfo(db *gorm.DB, dbName, tableName string) (map[string]mysqlIndexInfo, error) {
+	entities, err := impl.getIndexList(db, dbName, tableName)
+	if err != nil {
 		return nil, err
 	}
 	indexMap := make(map[string]mysqlIndexInfo)
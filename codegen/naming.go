@@ -0,0 +1,142 @@
+package codegen
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/morehao/golib/gutil"
+)
+
+// NamingStrategy 命名规则，作用于表名转换的结构体名和列名转换的字段名，为 nil 时直接转为大驼峰
+type NamingStrategy struct {
+	TablePrefixes  []string                       // 转换结构体名前去除的表名前缀，如 t_、tbl_，按顺序去除第一个匹配的前缀
+	Acronyms       []string                       // 以全大写输出的缩写词，如 ID、URL、API，按下划线分隔的片段整体匹配，不区分大小写
+	StructNameFunc func(tableName string) string  // 自定义结构体命名，设置后忽略 TablePrefixes 和 Acronyms
+	FieldNameFunc  func(columnName string) string // 自定义字段命名，设置后忽略 Acronyms
+}
+
+// TrimTablePrefix 去除表名前缀，去除后为空时返回原表名
+func (s *NamingStrategy) TrimTablePrefix(tableName string) string {
+	if s == nil {
+		return tableName
+	}
+	for _, prefix := range s.TablePrefixes {
+		if trimmed, ok := strings.CutPrefix(tableName, prefix); ok && prefix != "" && trimmed != "" {
+			return trimmed
+		}
+	}
+	return tableName
+}
+
+// StructName 表名对应的结构体名，如 t_user_api 在去除 t_ 前缀并设置 API 缩写后为 UserAPI
+func (s *NamingStrategy) StructName(tableName string) string {
+	if s != nil && s.StructNameFunc != nil {
+		return s.StructNameFunc(tableName)
+	}
+	return s.pascal(s.TrimTablePrefix(tableName))
+}
+
+// FieldName 列名对应的字段名，如 user_id 在设置 ID 缩写后为 UserID
+func (s *NamingStrategy) FieldName(columnName string) string {
+	if s != nil && s.FieldNameFunc != nil {
+		return s.FieldNameFunc(columnName)
+	}
+	return s.pascal(columnName)
+}
+
+func (s *NamingStrategy) pascal(name string) string {
+	if s == nil || len(s.Acronyms) == 0 || name == "" {
+		return gutil.SnakeToPascal(name)
+	}
+	var sb strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if acronym, ok := s.matchAcronym(part); ok {
+			sb.WriteString(acronym)
+			continue
+		}
+		sb.WriteString(gutil.SnakeToPascal(part))
+	}
+	return sb.String()
+}
+
+func (s *NamingStrategy) matchAcronym(part string) (string, bool) {
+	for _, acronym := range s.Acronyms {
+		if strings.EqualFold(part, acronym) {
+			return strings.ToUpper(acronym), true
+		}
+	}
+	return "", false
+}
+
+// typeOverridePackages 类型覆盖中可省略导入路径的常用包
+var typeOverridePackages = map[string]string{
+	"time":      "time",
+	"json":      "encoding/json",
+	"sql":       "database/sql",
+	"gorm":      "gorm.io/gorm",
+	"datatypes": "gorm.io/datatypes",
+	"decimal":   "github.com/shopspring/decimal",
+}
+
+// majorVersionRegexp 匹配导入路径末尾的主版本号，如 /v2
+var majorVersionRegexp = regexp.MustCompile(`^v\d+$`)
+
+// parseTypeOverride 解析类型覆盖的取值，返回字段类型和需要的导入路径。
+// 取值可带 * 或 [] 前缀，包名不在 typeOverridePackages 中时需写完整导入路径，如 *github.com/shopspring/decimal.Decimal
+func parseTypeOverride(value string) (fieldType, importPath string, err error) {
+	value = strings.TrimSpace(value)
+	rest := strings.TrimLeft(value, "*[]")
+	modifier := value[:len(value)-len(rest)]
+	dot := strings.LastIndex(rest, ".")
+	if dot < 0 {
+		if rest == "" {
+			return "", "", fmt.Errorf("invalid type override %q", value)
+		}
+		return value, "", nil
+	}
+	pkgPath, typeName := rest[:dot], rest[dot+1:]
+	if pkgPath == "" || typeName == "" {
+		return "", "", fmt.Errorf("invalid type override %q", value)
+	}
+	if !strings.Contains(pkgPath, "/") {
+		knownPath, ok := typeOverridePackages[pkgPath]
+		if !ok {
+			return "", "", fmt.Errorf("unknown package of type override %q, use the full import path such as %s", value, "github.com/foo/bar."+typeName)
+		}
+		return value, knownPath, nil
+	}
+	pkgName := path.Base(pkgPath)
+	if majorVersionRegexp.MatchString(pkgName) {
+		pkgName = path.Base(path.Dir(pkgPath))
+	}
+	pkgName = strings.ReplaceAll(pkgName, "-", "")
+	return modifier + pkgName + "." + typeName, pkgPath, nil
+}
+
+// applyModelFieldRules 按命名规则设置字段名，并按 TypeOverrides 覆盖字段类型
+func applyModelFieldRules(cfg *ModuleCfg, fields []ModelField) error {
+	for i := range fields {
+		field := &fields[i]
+		field.FieldName = cfg.NamingStrategy.FieldName(field.ColumnName)
+		override, ok := cfg.TypeOverrides[cfg.TableName+"."+field.ColumnName]
+		if !ok {
+			override, ok = cfg.TypeOverrides[field.ColumnName]
+		}
+		if !ok {
+			continue
+		}
+		fieldType, importPath, err := parseTypeOverride(override)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", field.ColumnName, err)
+		}
+		field.FieldType = fieldType
+		field.TypeImport = importPath
+		field.IsTypeOverride = true
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingStrategy(t *testing.T) {
+	var nilStrategy *NamingStrategy
+	assert.Equal(t, "TUserApi", nilStrategy.StructName("t_user_api"))
+	assert.Equal(t, "UserId", nilStrategy.FieldName("user_id"))
+
+	strategy := &NamingStrategy{
+		TablePrefixes: []string{"tbl_", "t_"},
+		Acronyms:      []string{"id", "URL", "API"},
+	}
+	assert.Equal(t, "UserAPI", strategy.StructName("t_user_api"))
+	assert.Equal(t, "Order", strategy.StructName("tbl_order"))
+	assert.Equal(t, "T", strategy.StructName("t_"))
+	assert.Equal(t, "UserID", strategy.FieldName("user_id"))
+	assert.Equal(t, "AvatarURL", strategy.FieldName("avatar__url"))
+	assert.Equal(t, "Identity", strategy.FieldName("identity"))
+
+	strategy.StructNameFunc = strings.ToUpper
+	assert.Equal(t, "T_USER", strategy.StructName("t_user"))
+}
+
+func TestParseTypeOverride(t *testing.T) {
+	cases := []struct {
+		value, fieldType, importPath string
+	}{
+		{"int64", "int64", ""},
+		{"gorm.DeletedAt", "gorm.DeletedAt", "gorm.io/gorm"},
+		{"*decimal.Decimal", "*decimal.Decimal", "github.com/shopspring/decimal"},
+		{"[]github.com/foo/money-kit/v2.Amount", "[]moneykit.Amount", "github.com/foo/money-kit/v2"},
+	}
+	for _, c := range cases {
+		fieldType, importPath, err := parseTypeOverride(c.value)
+		require.Nil(t, err, c.value)
+		assert.Equal(t, c.fieldType, fieldType, c.value)
+		assert.Equal(t, c.importPath, importPath, c.value)
+	}
+
+	_, _, err := parseTypeOverride("money.Amount")
+	assert.ErrorContains(t, err, "unknown package")
+	_, _, err = parseTypeOverride("*")
+	assert.ErrorContains(t, err, "invalid type override")
+}
+
+func TestGenerateFromDDLWithNaming(t *testing.T) {
+	rootDir := t.TempDir()
+	ddl := strings.Replace(mysqlTestDDL, "`user`", "`t_user`", 1)
+	res, err := GenerateFromDDL(ddl, &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     rootDir,
+		},
+		TableName:      "t_user",
+		ImportPath:     "example.com/demo",
+		NamingStrategy: &NamingStrategy{TablePrefixes: []string{"t_"}, Acronyms: []string{"id"}},
+		TypeOverrides: map[string]string{
+			"t_user.amount": "decimal.Decimal",
+			"amount":        "float64",
+			"status":        "string",
+		},
+	})
+	require.Nil(t, err)
+	require.NotEmpty(t, res.GeneratedFiles)
+
+	model, err := os.ReadFile(filepath.Join(rootDir, "model", "t_user.go"))
+	require.Nil(t, err)
+	content := string(model)
+	assert.Contains(t, content, `"github.com/shopspring/decimal"`)
+	assert.Contains(t, content, "type User struct")
+	assert.Contains(t, content, "ID        uint ")
+	assert.Contains(t, content, "Amount    decimal.Decimal ")
+	assert.Contains(t, content, "DeletedAt gorm.DeletedAt ")
+	// 覆盖类型的字段不识别枚举
+	assert.NotContains(t, content, "UserStatus")
+	assert.Contains(t, content, "Status    string")
+}
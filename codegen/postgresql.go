@@ -2,6 +2,7 @@ package codegen
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/morehao/golib/gutil"
 	"gorm.io/gorm"
@@ -11,8 +12,8 @@ type postgresqlImpl struct {
 }
 
 func (impl *postgresqlImpl) GetModuleTemplateParam(db *gorm.DB, cfg *ModuleCfg) (*ModuleTplAnalysisRes, error) {
-	// PostgreSQL 默认使用 public schema
-	tableList, getTableErr := getPostgresqlTableList(db, "public")
+	schemaName := postgresqlSchemaName(cfg.SchemaName)
+	tableList, getTableErr := getPostgresqlTableList(db, schemaName)
 	if getTableErr != nil {
 		return nil, getTableErr
 	}
@@ -21,10 +22,24 @@ func (impl *postgresqlImpl) GetModuleTemplateParam(db *gorm.DB, cfg *ModuleCfg)
 		return nil, fmt.Errorf("table %s not exist", cfg.TableName)
 	}
 
-	modelFieldList, getFieldErr := impl.getModelField(db, "public", cfg)
+	modelFieldList, getFieldErr := impl.getModelField(db, schemaName, cfg)
 	if getFieldErr != nil {
 		return nil, getFieldErr
 	}
+	if err := applyModelFieldRules(cfg, modelFieldList); err != nil {
+		return nil, err
+	}
+
+	indexes, foreignKeys, metaErr := impl.getTableMeta(db, schemaName, cfg.TableName)
+	if metaErr != nil {
+		return nil, metaErr
+	}
+	fillFieldMeta(modelFieldList, indexes, foreignKeys)
+
+	pkRes, pkErr := impl.analysisPK(db, schemaName, cfg, modelFieldList)
+	if pkErr != nil {
+		return nil, pkErr
+	}
 
 	// 获取模板文件
 	tplAnalysisList, analysisErr := analysisTplFiles(cfg.CommonConfig, cfg.TableName)
@@ -40,12 +55,20 @@ func (impl *postgresqlImpl) GetModuleTemplateParam(db *gorm.DB, cfg *ModuleCfg)
 			ModelFields:     modelFieldList,
 		})
 	}
-	structName := gutil.SnakeToPascal(cfg.TableName)
+	structName := cfg.NamingStrategy.StructName(cfg.TableName)
 	res := &ModuleTplAnalysisRes{
-		PackageName:     cfg.PackageName,
-		TableName:       cfg.TableName,
-		StructName:      structName,
-		TplAnalysisList: moduleAnalysisList,
+		Dialect:          dbTypePostgresql,
+		PackageName:      cfg.PackageName,
+		TableName:        cfg.TableName,
+		StructName:       structName,
+		MigrationVersion: cfg.MigrationVersion,
+		PrimaryKeys:      pkRes.PrimaryKeys,
+		PKFields:         pkRes.PKFields,
+		IsCompositePK:    pkRes.IsCompositePK,
+		HasPK:            pkRes.HasPK,
+		Indexes:          indexes,
+		ForeignKeys:      foreignKeys,
+		TplAnalysisList:  moduleAnalysisList,
 	}
 	return res, nil
 }
@@ -53,7 +76,7 @@ func (impl *postgresqlImpl) GetModuleTemplateParam(db *gorm.DB, cfg *ModuleCfg)
 func (impl *postgresqlImpl) getModelField(db *gorm.DB, schemaName string, cfg *ModuleCfg) ([]ModelField, error) {
 	// 查询列信息，同时获取注释
 	// PostgreSQL 的注释存储在 pg_description 系统表中
-	getColumnSql := fmt.Sprintf(`
+	getColumnSql := `
 		SELECT
 			c.column_name,
 			c.data_type,
@@ -67,25 +90,27 @@ func (impl *postgresqlImpl) getModelField(db *gorm.DB, schemaName string, cfg *M
 			c.ordinal_position,
 			c.table_schema,
 			c.table_name,
+			c.is_identity,
 			COALESCE(pd.description, '') AS column_comment
 		FROM information_schema.columns c
-		LEFT JOIN pg_class pc ON pc.relname = c.table_name
-		LEFT JOIN pg_namespace pn ON pn.oid = pc.relnamespace AND pn.nspname = c.table_schema
+		LEFT JOIN pg_namespace pn ON pn.nspname = c.table_schema
+		LEFT JOIN pg_class pc ON pc.relname = c.table_name AND pc.relnamespace = pn.oid
 		LEFT JOIN pg_description pd ON pd.objoid = pc.oid AND pd.objsubid = c.ordinal_position
-		WHERE c.table_schema = '%s' AND c.table_name = '%s'
+		WHERE c.table_schema = ? AND c.table_name = ?
 		ORDER BY c.ordinal_position;
-	`, schemaName, cfg.TableName)
+	`
 
 	var entities []postgresqlTableColumn
-	if err := db.Raw(getColumnSql).Scan(&entities).Error; err != nil {
+	if err := db.Raw(getColumnSql, schemaName, cfg.TableName).Scan(&entities).Error; err != nil {
 		return nil, err
 	}
 
 	// 查询主键信息
-	primaryKeys, pkErr := impl.getPrimaryKeys(db, schemaName, cfg.TableName)
+	primaryKeyList, pkErr := impl.getPrimaryKeys(db, schemaName, cfg.TableName)
 	if pkErr != nil {
 		return nil, pkErr
 	}
+	primaryKeys := TableList(primaryKeyList).ToMap()
 
 	// 查询索引信息
 	indexInfoMap, indexErr := impl.getIndexInfo(db, schemaName, cfg.TableName)
@@ -118,6 +143,8 @@ func (impl *postgresqlImpl) getModelField(db *gorm.DB, schemaName string, cfg *M
 			IsNullable:   v.IsNullable == "YES",
 			DefaultValue: v.ColumnDefault.String,
 			Comment:      v.ColumnComment,
+			// serial 列的默认值为 nextval('seq'::regclass)
+			IsAutoIncrement: v.IsIdentity == "YES" || strings.HasPrefix(v.ColumnDefault.String, "nextval("),
 		}
 		// 如果类型映射中没有找到，使用 data_type 作为后备
 		if item.FieldType == "" {
@@ -137,33 +164,51 @@ func (impl *postgresqlImpl) getModelField(db *gorm.DB, schemaName string, cfg *M
 	return modelFieldList, nil
 }
 
-// getPrimaryKeys 获取表的主键列名
-func (impl *postgresqlImpl) getPrimaryKeys(db *gorm.DB, schemaName, tableName string) (map[string]struct{}, error) {
-	getPkSql := fmt.Sprintf(`
+// analysisPK 分析主键，无主键时从唯一索引中回退
+func (impl *postgresqlImpl) analysisPK(db *gorm.DB, schemaName string, cfg *ModuleCfg, fields []ModelField) (*pkAnalysisRes, error) {
+	primaryKeys, err := impl.getPrimaryKeys(db, schemaName, cfg.TableName)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := impl.getIndexList(db, schemaName, cfg.TableName)
+	if err != nil {
+		return nil, err
+	}
+	var indexes []indexColumn
+	for _, v := range entities {
+		indexes = append(indexes, indexColumn{
+			IndexName:  v.IndexName,
+			ColumnName: v.ColumnName,
+			IsUnique:   v.IsUnique,
+			SeqInIndex: v.SeqInIndex,
+		})
+	}
+	return analysisPK(cfg.TableName, fields, primaryKeys, indexes, cfg.NoPKStrategy)
+}
+
+// getPrimaryKeys 获取表的主键列名，按主键中的列顺序排列
+func (impl *postgresqlImpl) getPrimaryKeys(db *gorm.DB, schemaName, tableName string) ([]string, error) {
+	getPkSql := `
 		SELECT kcu.column_name
 		FROM information_schema.table_constraints tc
 		JOIN information_schema.key_column_usage kcu
 			ON tc.constraint_name = kcu.constraint_name
 			AND tc.table_schema = kcu.table_schema
 		WHERE tc.constraint_type = 'PRIMARY KEY'
-			AND tc.table_schema = '%s'
-			AND tc.table_name = '%s';
-	`, schemaName, tableName)
+			AND tc.table_schema = ?
+			AND tc.table_name = ?
+		ORDER BY kcu.ordinal_position;
+	`
 
 	var pkColumns []string
-	if err := db.Raw(getPkSql).Scan(&pkColumns).Error; err != nil {
+	if err := db.Raw(getPkSql, schemaName, tableName).Scan(&pkColumns).Error; err != nil {
 		return nil, err
 	}
-
-	pkMap := make(map[string]struct{})
-	for _, col := range pkColumns {
-		pkMap[col] = struct{}{}
-	}
-	return pkMap, nil
+	return pkColumns, nil
 }
 
-func (impl *postgresqlImpl) getIndexInfo(db *gorm.DB, schemaName, tableName string) (map[string]postgresqlIndexInfo, error) {
-	getIndexSql := fmt.Sprintf(`
+func (impl *postgresqlImpl) getIndexList(db *gorm.DB, schemaName, tableName string) ([]postgresqlIndexInfo, error) {
+	getIndexSql := `
 		SELECT
 			i.relname AS index_name,
 			a.attname AS column_name,
@@ -177,13 +222,66 @@ func (impl *postgresqlImpl) getIndexInfo(db *gorm.DB, schemaName, tableName stri
 		JOIN pg_namespace n ON n.oid = i.relnamespace
 		JOIN pg_class c ON c.oid = ix.indrelid
 		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = ANY(ix.indkey)
-		WHERE n.nspname = '%s' AND c.relname = '%s'
-			AND i.relname NOT LIKE '%%_pkey'
+		WHERE n.nspname = ? AND c.relname = ?
+			AND i.relname NOT LIKE '%_pkey'
 		ORDER BY i.relname, seq_in_index;
-	`, schemaName, tableName)
+	`
 
 	var entities []postgresqlIndexInfo
-	if err := db.Raw(getIndexSql).Scan(&entities).Error; err != nil {
+	if err := db.Raw(getIndexSql, schemaName, tableName).Scan(&entities).Error; err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// getTableMeta 获取主键以外的索引定义和外键定义
+func (impl *postgresqlImpl) getTableMeta(db *gorm.DB, schemaName, tableName string) ([]TableIndex, []ForeignKey, error) {
+	entities, err := impl.getIndexList(db, schemaName, tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var indexes []indexColumn
+	for _, v := range entities {
+		if v.IsPrimary {
+			continue
+		}
+		indexes = append(indexes, indexColumn{
+			IndexName:  v.IndexName,
+			ColumnName: v.ColumnName,
+			IsUnique:   v.IsUnique,
+			SeqInIndex: v.SeqInIndex,
+		})
+	}
+
+	// 引用列通过 position_in_unique_constraint 与本表的列对应，保证多列外键的顺序
+	var fkColumns []foreignKeyColumn
+	getForeignKeySql := `
+		SELECT
+			kcu.constraint_name,
+			kcu.column_name,
+			rku.table_name AS ref_table_name,
+			rku.column_name AS ref_column_name,
+			kcu.ordinal_position AS seq_in_key
+		FROM information_schema.referential_constraints rc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = rc.constraint_schema
+			AND kcu.constraint_name = rc.constraint_name
+		JOIN information_schema.key_column_usage rku
+			ON rku.constraint_schema = rc.unique_constraint_schema
+			AND rku.constraint_name = rc.unique_constraint_name
+			AND rku.ordinal_position = kcu.position_in_unique_constraint
+		WHERE kcu.table_schema = ? AND kcu.table_name = ?
+		ORDER BY kcu.constraint_name, kcu.ordinal_position;
+	`
+	if err := db.Raw(getForeignKeySql, schemaName, tableName).Scan(&fkColumns).Error; err != nil {
+		return nil, nil, err
+	}
+	return buildTableIndexes(indexes), buildForeignKeys(fkColumns), nil
+}
+
+func (impl *postgresqlImpl) getIndexInfo(db *gorm.DB, schemaName, tableName string) (map[string]postgresqlIndexInfo, error) {
+	entities, err := impl.getIndexList(db, schemaName, tableName)
+	if err != nil {
 		return nil, err
 	}
 	indexMap := make(map[string]postgresqlIndexInfo)
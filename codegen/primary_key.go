@@ -0,0 +1,101 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NoPKStrategy 无主键表的处理策略
+type NoPKStrategy string
+
+const (
+	// NoPKStrategyUniqueIndex 使用第一个列均非空的唯一索引作为行标识，没有可用唯一索引时按 NoPKStrategyNone 处理，默认策略
+	NoPKStrategyUniqueIndex NoPKStrategy = "unique_index"
+	// NoPKStrategyNone 不提供行标识，PKFields 为空，由模板根据 HasPK 跳过按主键操作的方法
+	NoPKStrategyNone NoPKStrategy = "none"
+	// NoPKStrategyError 直接返回错误
+	NoPKStrategyError NoPKStrategy = "error"
+)
+
+// indexColumn 索引中的一列，用于统一 mysql 和 postgresql 的索引信息
+type indexColumn struct {
+	IndexName  string
+	ColumnName string
+	IsUnique   bool
+	SeqInIndex int
+}
+
+// pkAnalysisRes 主键分析结果
+type pkAnalysisRes struct {
+	PrimaryKeys   []string
+	PKFields      []ModelField
+	IsCompositePK bool
+	HasPK         bool
+}
+
+// analysisPK 根据主键列（按主键中的顺序）和唯一索引确定行标识字段
+func analysisPK(tableName string, fields []ModelField, primaryKeys []string, indexes []indexColumn, strategy NoPKStrategy) (*pkAnalysisRes, error) {
+	fieldMap := make(map[string]ModelField, len(fields))
+	for _, field := range fields {
+		fieldMap[field.ColumnName] = field
+	}
+
+	res := &pkAnalysisRes{PrimaryKeys: primaryKeys}
+	keyColumns := primaryKeys
+	if len(keyColumns) == 0 {
+		switch strategy {
+		case NoPKStrategyError:
+			return nil, fmt.Errorf("table %s has no primary key", tableName)
+		case NoPKStrategyNone:
+		default:
+			keyColumns = pickUniqueIndex(indexes, fieldMap)
+		}
+	}
+
+	for _, column := range keyColumns {
+		field, ok := fieldMap[column]
+		if !ok {
+			return nil, fmt.Errorf("key column %s not found in table %s", column, tableName)
+		}
+		res.PKFields = append(res.PKFields, field)
+	}
+	res.HasPK = len(res.PKFields) > 0
+	res.IsCompositePK = len(res.PKFields) > 1
+	return res, nil
+}
+
+// pickUniqueIndex 按索引名排序选择第一个列均非空的唯一索引，返回其按索引顺序排列的列名
+func pickUniqueIndex(indexes []indexColumn, fieldMap map[string]ModelField) []string {
+	indexColumns := make(map[string][]indexColumn)
+	var indexNames []string
+	for _, v := range indexes {
+		if !v.IsUnique {
+			continue
+		}
+		if _, ok := indexColumns[v.IndexName]; !ok {
+			indexNames = append(indexNames, v.IndexName)
+		}
+		indexColumns[v.IndexName] = append(indexColumns[v.IndexName], v)
+	}
+	sort.Strings(indexNames)
+
+	for _, name := range indexNames {
+		columns := indexColumns[name]
+		sort.Slice(columns, func(i, j int) bool {
+			return columns[i].SeqInIndex < columns[j].SeqInIndex
+		})
+		var keyColumns []string
+		for _, column := range columns {
+			field, ok := fieldMap[column.ColumnName]
+			if !ok || field.IsNullable {
+				keyColumns = nil
+				break
+			}
+			keyColumns = append(keyColumns, column.ColumnName)
+		}
+		if len(keyColumns) > 0 {
+			return keyColumns
+		}
+	}
+	return nil
+}
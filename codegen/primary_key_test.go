@@ -0,0 +1,52 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalysisPK(t *testing.T) {
+	fields := []ModelField{
+		{ColumnName: "tenant_id", FieldName: "TenantID"},
+		{ColumnName: "user_id", FieldName: "UserID"},
+		{ColumnName: "email", FieldName: "Email"},
+		{ColumnName: "nickname", FieldName: "Nickname", IsNullable: true},
+	}
+
+	// 联合主键保持主键中的列顺序
+	res, err := analysisPK("user", fields, []string{"user_id", "tenant_id"}, nil, "")
+	assert.Nil(t, err)
+	assert.True(t, res.HasPK)
+	assert.True(t, res.IsCompositePK)
+	assert.Equal(t, []string{"user_id", "tenant_id"}, res.PrimaryKeys)
+	assert.Equal(t, "UserID", res.PKFields[0].FieldName)
+	assert.Equal(t, "TenantID", res.PKFields[1].FieldName)
+
+	// 无主键时回退到列均非空的唯一索引，跳过包含可空列的索引
+	indexes := []indexColumn{
+		{IndexName: "a_uk_nickname", ColumnName: "nickname", IsUnique: true, SeqInIndex: 1},
+		{IndexName: "b_uk_tenant_email", ColumnName: "email", IsUnique: true, SeqInIndex: 2},
+		{IndexName: "b_uk_tenant_email", ColumnName: "tenant_id", IsUnique: true, SeqInIndex: 1},
+		{IndexName: "idx_user", ColumnName: "user_id", SeqInIndex: 1},
+	}
+	res, err = analysisPK("user", fields, nil, indexes, NoPKStrategyUniqueIndex)
+	assert.Nil(t, err)
+	assert.Empty(t, res.PrimaryKeys)
+	assert.True(t, res.IsCompositePK)
+	assert.Equal(t, "TenantID", res.PKFields[0].FieldName)
+	assert.Equal(t, "Email", res.PKFields[1].FieldName)
+
+	// 没有可用唯一索引
+	res, err = analysisPK("user", fields, nil, indexes[3:], NoPKStrategyUniqueIndex)
+	assert.Nil(t, err)
+	assert.False(t, res.HasPK)
+
+	res, err = analysisPK("user", fields, nil, indexes, NoPKStrategyNone)
+	assert.Nil(t, err)
+	assert.False(t, res.HasPK)
+	assert.False(t, res.IsCompositePK)
+
+	_, err = analysisPK("user", fields, nil, indexes, NoPKStrategyError)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,282 @@
+package codegen
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/morehao/golib/gutil"
+)
+
+const (
+	protoDir           = "proto"
+	protoFileExtension = ".proto"
+	protoTimestamp     = "google.protobuf.Timestamp"
+	protoEmpty         = "google.protobuf.Empty"
+)
+
+// protoMessage proto 消息定义
+type protoMessage struct {
+	Name     string
+	Comment  string
+	Fields   []protoField
+	Reserved []int // 已删除字段的编号，不再复用
+}
+
+// protoField proto 字段定义，Number 由 assignProtoNumbers 分配
+type protoField struct {
+	Name    string
+	Type    string
+	Label   string // optional、repeated 或空
+	Comment string
+	Number  int
+}
+
+var (
+	protoMessageRegexp  = regexp.MustCompile(`(?s)message\s+(\w+)\s*\{(.*?)\n\}`)
+	protoFieldRegexp    = regexp.MustCompile(`(?m)^\s*(?:optional\s+|repeated\s+)?[\w.]+\s+(\w+)\s*=\s*(\d+)\s*;`)
+	protoReservedRegexp = regexp.MustCompile(`(?m)^\s*reserved\s+([\d\s,]+);`)
+)
+
+// protoExistingMessage 已有 proto 文件中单个消息的字段编号
+type protoExistingMessage struct {
+	numbers  map[string]int
+	reserved []int
+}
+
+// parseProtoNumbers 解析已有 proto 文件中各消息的字段编号和保留编号，用于重新生成时保持编号不变
+func parseProtoNumbers(content string) map[string]protoExistingMessage {
+	res := make(map[string]protoExistingMessage)
+	for _, match := range protoMessageRegexp.FindAllStringSubmatch(content, -1) {
+		msg := protoExistingMessage{numbers: make(map[string]int)}
+		for _, field := range protoFieldRegexp.FindAllStringSubmatch(match[2], -1) {
+			number, _ := strconv.Atoi(field[2])
+			msg.numbers[field[1]] = number
+		}
+		for _, reserved := range protoReservedRegexp.FindAllStringSubmatch(match[2], -1) {
+			for _, item := range strings.Split(reserved[1], ",") {
+				if number, err := strconv.Atoi(strings.TrimSpace(item)); err == nil {
+					msg.reserved = append(msg.reserved, number)
+				}
+			}
+		}
+		res[match[1]] = msg
+	}
+	return res
+}
+
+// assignProtoNumbers 分配字段编号：已有字段沿用原编号，新字段使用已用最大编号加一，已删除字段的编号加入 reserved
+func assignProtoNumbers(msg *protoMessage, existing protoExistingMessage) {
+	used := make(map[int]bool)
+	maxNumber := 0
+	for _, number := range existing.numbers {
+		maxNumber = max(maxNumber, number)
+	}
+	for _, number := range existing.reserved {
+		maxNumber = max(maxNumber, number)
+		used[number] = true
+	}
+	for i := range msg.Fields {
+		if number, ok := existing.numbers[msg.Fields[i].Name]; ok && !used[number] {
+			msg.Fields[i].Number = number
+			used[number] = true
+		}
+	}
+	for i := range msg.Fields {
+		if msg.Fields[i].Number == 0 {
+			maxNumber++
+			msg.Fields[i].Number = maxNumber
+			used[maxNumber] = true
+		}
+	}
+	msg.Reserved = append(msg.Reserved, existing.reserved...)
+	for _, number := range existing.numbers {
+		if !used[number] {
+			msg.Reserved = append(msg.Reserved, number)
+			used[number] = true
+		}
+	}
+	sort.Ints(msg.Reserved)
+}
+
+// protoType 根据字段的 Go 类型确定 proto 类型，无法对应的类型使用 string
+func protoType(fieldType string) (typ, label string) {
+	if fieldType == "[]byte" {
+		return "bytes", ""
+	}
+	if elem, ok := strings.CutPrefix(fieldType, "[]"); ok {
+		typ, _ = protoType(elem)
+		return typ, "repeated"
+	}
+	if elem, ok := strings.CutPrefix(fieldType, "*"); ok {
+		typ, label = protoType(elem)
+		if label == "" {
+			label = "optional"
+		}
+		return typ, label
+	}
+	switch fieldType {
+	case "int8", "int16", "int32":
+		return "int32", ""
+	case "int", "int64", "time.Duration":
+		return "int64", ""
+	case "uint8", "uint16", "uint32":
+		return "uint32", ""
+	case "uint", "uint64":
+		return "uint64", ""
+	case "float32":
+		return "float", ""
+	case "float64":
+		return "double", ""
+	case "bool":
+		return "bool", ""
+	case "time.Time", "gorm.DeletedAt":
+		return protoTimestamp, ""
+	}
+	return "string", ""
+}
+
+func newProtoFields(fields []ModuleTplField, forceRequired bool) []protoField {
+	res := make([]protoField, 0, len(fields))
+	for _, field := range fields {
+		typ, label := protoType(field.FieldType)
+		if forceRequired && label == "optional" {
+			label = ""
+		}
+		res = append(res, protoField{Name: field.ColumnName, Type: typ, Label: label, Comment: field.Comment})
+	}
+	return res
+}
+
+// buildProtoMessages 构造与 HTTP 接口对应的消息，命名同 dto 层，如 UserCreateReq、UserItem
+func buildProtoMessages(params *ModuleTplParams) []protoMessage {
+	structName := params.StructName
+	messages := []protoMessage{
+		{Name: structName + "Item", Comment: params.TableName + " 记录", Fields: newProtoFields(params.ItemFields, false)},
+		{Name: structName + "CreateReq", Comment: "创建 " + params.TableName + " 请求", Fields: newProtoFields(params.CreateFields, false)},
+		{Name: structName + "CreateRes", Comment: "创建 " + params.TableName + " 响应", Fields: newProtoFields(params.PKFields, true)},
+	}
+	if params.HasPK {
+		messages = append(messages,
+			protoMessage{Name: structName + "DeleteReq", Comment: "删除 " + params.TableName + " 请求", Fields: newProtoFields(params.PKFields, true)},
+		)
+		if params.HasUpdate {
+			updateFields := append(newProtoFields(params.PKFields, true), newProtoFields(params.UpdateFields, false)...)
+			messages = append(messages,
+				protoMessage{Name: structName + "UpdateReq", Comment: "更新 " + params.TableName + " 请求", Fields: updateFields},
+			)
+		}
+		messages = append(messages,
+			protoMessage{Name: structName + "DetailReq", Comment: "查询 " + params.TableName + " 详情请求", Fields: newProtoFields(params.PKFields, true)},
+		)
+	}
+	messages = append(messages,
+		protoMessage{Name: structName + "PageListReq", Comment: "分页查询 " + params.TableName + " 请求", Fields: []protoField{
+			{Name: "page", Type: "int32"},
+			{Name: "page_size", Type: "int32"},
+		}},
+		protoMessage{Name: structName + "PageListRes", Comment: "分页查询 " + params.TableName + " 响应", Fields: []protoField{
+			{Name: "list", Type: structName + "Item", Label: "repeated"},
+			{Name: "total", Type: "int64"},
+		}},
+	)
+	return messages
+}
+
+// buildProtoFile 渲染表对应的 proto 文件，生成到 RootDir/proto/{包名}/{表名}.proto。
+// 目标文件已存在时读取其中的字段编号，已有字段沿用原编号，已删除字段的编号标记为 reserved，保证多次生成间的兼容
+func buildProtoFile(cfg *ModuleCfg, params *ModuleTplParams) (*GeneratedFile, error) {
+	packageName := params.PackageName
+	targetFilepath := filepath.Join(cfg.RootDir, protoDir, packageName, params.TableName+protoFileExtension)
+	file := &GeneratedFile{Path: targetFilepath, Overwrite: true}
+
+	existing := make(map[string]protoExistingMessage)
+	if gutil.FileExists(targetFilepath) {
+		content, readErr := os.ReadFile(targetFilepath)
+		if readErr != nil {
+			return nil, fmt.Errorf("read %s fail, error: %w", targetFilepath, readErr)
+		}
+		file.Exist = true
+		existing = parseProtoNumbers(string(content))
+	}
+
+	messages := buildProtoMessages(params)
+	var hasTimestamp bool
+	for i := range messages {
+		assignProtoNumbers(&messages[i], existing[messages[i].Name])
+		for _, field := range messages[i].Fields {
+			hasTimestamp = hasTimestamp || field.Type == protoTimestamp
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Code generated by codegen. 字段编号在重新生成时保持不变，已删除字段的编号标记为 reserved。\n\n")
+	sb.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&sb, "package %s;\n\n", packageName)
+	if cfg.ImportPath != "" {
+		goPackage := path.Join(cfg.ImportPath, protoDir, packageName)
+		fmt.Fprintf(&sb, "option go_package = \"%s;%s\";\n\n", goPackage, packageName)
+	}
+	if params.HasPK {
+		sb.WriteString("import \"google/protobuf/empty.proto\";\n")
+	}
+	if hasTimestamp {
+		sb.WriteString("import \"google/protobuf/timestamp.proto\";\n")
+	}
+	if params.HasPK || hasTimestamp {
+		sb.WriteString("\n")
+	}
+
+	structName := params.StructName
+	fmt.Fprintf(&sb, "// %sService %s 的 CRUD 接口，与 HTTP 接口一一对应\n", structName, params.TableName)
+	fmt.Fprintf(&sb, "service %sService {\n", structName)
+	fmt.Fprintf(&sb, "  rpc Create(%sCreateReq) returns (%sCreateRes);\n", structName, structName)
+	if params.HasPK {
+		fmt.Fprintf(&sb, "  rpc Delete(%sDeleteReq) returns (%s);\n", structName, protoEmpty)
+		if params.HasUpdate {
+			fmt.Fprintf(&sb, "  rpc Update(%sUpdateReq) returns (%s);\n", structName, protoEmpty)
+		}
+		fmt.Fprintf(&sb, "  rpc Detail(%sDetailReq) returns (%sItem);\n", structName, structName)
+	}
+	fmt.Fprintf(&sb, "  rpc PageList(%sPageListReq) returns (%sPageListRes);\n", structName, structName)
+	sb.WriteString("}\n")
+
+	for _, msg := range messages {
+		fmt.Fprintf(&sb, "\n// %s %s\n", msg.Name, msg.Comment)
+		fmt.Fprintf(&sb, "message %s {\n", msg.Name)
+		if len(msg.Reserved) > 0 {
+			numbers := make([]string, 0, len(msg.Reserved))
+			for _, number := range msg.Reserved {
+				numbers = append(numbers, strconv.Itoa(number))
+			}
+			fmt.Fprintf(&sb, "  reserved %s;\n", strings.Join(numbers, ", "))
+		}
+		for _, field := range msg.Fields {
+			sb.WriteString("  ")
+			if field.Label != "" {
+				sb.WriteString(field.Label + " ")
+			}
+			fmt.Fprintf(&sb, "%s %s = %d;", field.Type, field.Name, field.Number)
+			if field.Comment != "" {
+				sb.WriteString(" // " + field.Comment)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("}\n")
+	}
+	file.Content = []byte(sb.String())
+	return file, nil
+}
+
+// writeProtoFile 写入 proto 文件，已存在时覆盖
+func writeProtoFile(file *GeneratedFile) error {
+	if err := gutil.CreateDir(filepath.Dir(file.Path)); err != nil {
+		return err
+	}
+	return os.WriteFile(file.Path, file.Content, 0666)
+}
@@ -0,0 +1,112 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoType(t *testing.T) {
+	cases := map[string][2]string{
+		"int32":           {"int32", ""},
+		"int":             {"int64", ""},
+		"uint":            {"uint64", ""},
+		"uint8":           {"uint32", ""},
+		"float64":         {"double", ""},
+		"bool":            {"bool", ""},
+		"[]byte":          {"bytes", ""},
+		"[]string":        {"string", "repeated"},
+		"*int32":          {"int32", "optional"},
+		"time.Time":       {protoTimestamp, ""},
+		"*time.Time":      {protoTimestamp, "optional"},
+		"json.RawMessage": {"string", ""},
+		"decimal.Decimal": {"string", ""},
+	}
+	for fieldType, want := range cases {
+		typ, label := protoType(fieldType)
+		assert.Equal(t, want, [2]string{typ, label}, fieldType)
+	}
+}
+
+func TestAssignProtoNumbers(t *testing.T) {
+	existing := parseProtoNumbers(`
+message UserItem {
+  reserved 4;
+  uint64 id = 1;
+  string name = 2;
+  optional int32 age = 3;
+  string email = 5;
+}
+`)
+	msg := protoMessage{Name: "UserItem", Fields: []protoField{
+		{Name: "id"}, {Name: "phone"}, {Name: "name"}, {Name: "email"},
+	}}
+	assignProtoNumbers(&msg, existing["UserItem"])
+	numbers := make(map[string]int)
+	for _, field := range msg.Fields {
+		numbers[field.Name] = field.Number
+	}
+	assert.Equal(t, map[string]int{"id": 1, "name": 2, "email": 5, "phone": 6}, numbers)
+	assert.Equal(t, []int{3, 4}, msg.Reserved)
+}
+
+func TestGenerateProtoFromDDL(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     rootDir,
+		},
+		TableName:  "user",
+		ImportPath: "example.com/demo",
+		GenProto:   true,
+	}
+	res, err := GenerateFromDDL(mysqlTestDDL, cfg)
+	require.Nil(t, err)
+	protoPath := filepath.Join(rootDir, "proto", "user", "user.proto")
+	assert.Contains(t, res.GeneratedFiles, protoPath)
+
+	content, err := os.ReadFile(protoPath)
+	require.Nil(t, err)
+	proto := string(content)
+	assert.Contains(t, proto, "package user;")
+	assert.Contains(t, proto, `option go_package = "example.com/demo/proto/user;user";`)
+	assert.Contains(t, proto, `import "google/protobuf/timestamp.proto";`)
+	assert.Contains(t, proto, "rpc Detail(UserDetailReq) returns (UserItem);")
+	assert.Contains(t, proto, "rpc Delete(UserDeleteReq) returns (google.protobuf.Empty);")
+	assert.Contains(t, proto, "uint64 id = 1; // 主键")
+	assert.Contains(t, proto, "optional int32 age = 3;")
+	assert.Contains(t, proto, "repeated UserItem list = 1;")
+	assert.NotContains(t, proto, "deleted_at")
+
+	// 删除 age 列、新增 email 列后重新生成，已有字段编号不变
+	ddl := strings.Replace(mysqlTestDDL, "`age` int(11) DEFAULT NULL,", "`email` varchar(128) NOT NULL DEFAULT '',", 1)
+	ddl = strings.Replace(ddl, "KEY `idx_age_status` (`age`, `status`)", "KEY `idx_status` (`status`)", 1)
+	cfg.DryRun = true
+	res, err = GenerateFromDDL(ddl, cfg)
+	require.Nil(t, err)
+	var protoFile *GeneratedFile
+	for i := range res.Files {
+		if res.Files[i].Path == protoPath {
+			protoFile = &res.Files[i]
+		}
+	}
+	require.NotNil(t, protoFile)
+	assert.True(t, protoFile.Exist)
+	assert.True(t, protoFile.Overwrite)
+	regenerated := string(protoFile.Content)
+	assert.Contains(t, regenerated, "uint64 id = 1; // 主键")
+	assert.Contains(t, regenerated, "string amount = 4;")
+	assert.Contains(t, regenerated, "reserved 3;")
+	assert.NotContains(t, regenerated, "int32 age")
+	assert.Contains(t, regenerated, "string email = 7;")
+
+	// DryRun 不修改已有文件
+	unchanged, err := os.ReadFile(protoPath)
+	require.Nil(t, err)
+	assert.Equal(t, proto, string(unchanged))
+}
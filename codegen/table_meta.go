@@ -0,0 +1,127 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TableIndex 表索引定义，不含主键
+type TableIndex struct {
+	Name     string   // 索引名称
+	Columns  []string // 索引列，按索引中的顺序排列
+	IsUnique bool     // 是否唯一索引
+}
+
+// IsComposite 是否为联合索引
+func (i TableIndex) IsComposite() bool {
+	return len(i.Columns) > 1
+}
+
+// ForeignKey 外键定义
+type ForeignKey struct {
+	Name       string   // 约束名称
+	Columns    []string // 本表的列，按约束中的顺序排列
+	RefTable   string   // 引用的表
+	RefColumns []string // 引用的列，与 Columns 一一对应
+}
+
+// IsComposite 是否为多列外键
+func (fk ForeignKey) IsComposite() bool {
+	return len(fk.Columns) > 1
+}
+
+// foreignKeyColumn 外键中的一列，用于统一 mysql 和 postgresql 的外键信息
+type foreignKeyColumn struct {
+	ConstraintName string `gorm:"column:constraint_name"`
+	ColumnName     string `gorm:"column:column_name"`
+	RefTableName   string `gorm:"column:ref_table_name"`
+	RefColumnName  string `gorm:"column:ref_column_name"`
+	SeqInKey       int    `gorm:"column:seq_in_key"`
+}
+
+// buildTableIndexes 将索引列按索引名聚合，结果按索引名排序
+func buildTableIndexes(indexes []indexColumn) []TableIndex {
+	columnMap := make(map[string][]indexColumn)
+	var names []string
+	for _, v := range indexes {
+		if _, ok := columnMap[v.IndexName]; !ok {
+			names = append(names, v.IndexName)
+		}
+		columnMap[v.IndexName] = append(columnMap[v.IndexName], v)
+	}
+	sort.Strings(names)
+
+	res := make([]TableIndex, 0, len(names))
+	for _, name := range names {
+		columns := columnMap[name]
+		sort.SliceStable(columns, func(i, j int) bool {
+			return columns[i].SeqInIndex < columns[j].SeqInIndex
+		})
+		index := TableIndex{Name: name, IsUnique: columns[0].IsUnique}
+		for _, column := range columns {
+			index.Columns = append(index.Columns, column.ColumnName)
+		}
+		res = append(res, index)
+	}
+	return res
+}
+
+// buildForeignKeys 将外键列按约束名聚合，结果按约束名排序
+func buildForeignKeys(columns []foreignKeyColumn) []ForeignKey {
+	columnMap := make(map[string][]foreignKeyColumn)
+	var names []string
+	for _, v := range columns {
+		if _, ok := columnMap[v.ConstraintName]; !ok {
+			names = append(names, v.ConstraintName)
+		}
+		columnMap[v.ConstraintName] = append(columnMap[v.ConstraintName], v)
+	}
+	sort.Strings(names)
+
+	res := make([]ForeignKey, 0, len(names))
+	for _, name := range names {
+		keyColumns := columnMap[name]
+		sort.SliceStable(keyColumns, func(i, j int) bool {
+			return keyColumns[i].SeqInKey < keyColumns[j].SeqInKey
+		})
+		fk := ForeignKey{Name: name, RefTable: keyColumns[0].RefTableName}
+		for _, column := range keyColumns {
+			fk.Columns = append(fk.Columns, column.ColumnName)
+			fk.RefColumns = append(fk.RefColumns, column.RefColumnName)
+		}
+		res = append(res, fk)
+	}
+	return res
+}
+
+// fillFieldMeta 根据索引和外键定义填充字段的 IndexTag 和 ForeignKey
+func fillFieldMeta(fields []ModelField, indexes []TableIndex, foreignKeys []ForeignKey) {
+	for i := range fields {
+		column := fields[i].ColumnName
+		var tags []string
+		for _, index := range indexes {
+			for seq, indexColumn := range index.Columns {
+				if indexColumn != column {
+					continue
+				}
+				tag := "index:" + index.Name
+				if index.IsUnique {
+					tag = "uniqueIndex:" + index.Name
+				}
+				if index.IsComposite() {
+					tag += fmt.Sprintf(",priority:%d", seq+1)
+				}
+				tags = append(tags, tag)
+			}
+		}
+		fields[i].IndexTag = strings.Join(tags, ";")
+		for j := range foreignKeys {
+			for _, fkColumn := range foreignKeys[j].Columns {
+				if fkColumn == column {
+					fields[i].ForeignKey = &foreignKeys[j]
+				}
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"go/format"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,10 +20,18 @@ const (
 )
 
 type ModuleTplAnalysisRes struct {
-	PackageName     string
-	TableName       string
-	StructName      string
-	TplAnalysisList []ModuleTplAnalysisItem
+	Dialect          string // 数据库方言，mysql 或 postgres
+	PackageName      string
+	TableName        string
+	StructName       string
+	MigrationVersion string
+	PrimaryKeys      []string     // 主键列名，按主键中的顺序排列，无主键时为空
+	PKFields         []ModelField // 行标识字段，有主键时为主键字段，无主键时按 NoPKStrategy 回退
+	IsCompositePK    bool         // 行标识是否由多列组成
+	HasPK            bool         // 是否存在行标识字段，为 false 时模板应跳过按主键查询、更新、删除的方法
+	Indexes          []TableIndex // 索引定义，不含主键，按索引名排序
+	ForeignKeys      []ForeignKey // 外键定义，按约束名排序
+	TplAnalysisList  []ModuleTplAnalysisItem
 }
 
 type TplAnalysisItem struct {
@@ -32,6 +41,7 @@ type TplAnalysisItem struct {
 	OriginFilename  string
 	TargetDir       string
 	TargetFilename  string
+	TargetPackage   string // 生成文件所在目录对应的 Go 包名，即目录名
 	TargetFileExist bool
 	OriginLayerName LayerName
 	LayerName       LayerName
@@ -61,21 +71,21 @@ type GenParamsItem struct {
 
 // 获取指定目录下所有的模板文件
 func analysisTplFiles(cfg CommonConfig, defaultTargetFilename string) ([]TplAnalysisItem, error) {
-	// 打开指定目录
-	file, openErr := os.Open(cfg.TplDir)
-	if openErr != nil {
-		return nil, openErr
+	// 优先使用模板文件系统，未设置时读取模板目录
+	tplFS, tplDir := cfg.TplFS, "."
+	if tplFS == nil {
+		tplFS, tplDir = os.DirFS(cfg.TplDir), cfg.TplDir
 	}
-	// 读取目录下所有文件
-	tplFilenameList, readErr := file.Readdirnames(-1)
+	entries, readErr := fs.ReadDir(tplFS, ".")
 	if readErr != nil {
 		return nil, readErr
 	}
 	var analysisList []TplAnalysisItem
 	rootDir := cfg.RootDir
-	for _, tplFilename := range tplFilenameList {
+	for _, entry := range entries {
+		tplFilename := entry.Name()
 		// 判断是否是模板文件
-		if gutil.GetFileExtension(tplFilename) != tplFileExtension {
+		if entry.IsDir() || gutil.GetFileExtension(tplFilename) != tplFileExtension {
 			continue
 		}
 
@@ -120,19 +130,45 @@ func analysisTplFiles(cfg CommonConfig, defaultTargetFilename string) ([]TplAnal
 			targetFilename = fmt.Sprintf("%s%s", originFilename, goFileExtension)
 		case LayerNameRouter, LayerNameCode:
 			targetFilename = fmt.Sprintf("%s%s", gutil.CamelToSnakeCase(cfg.PackageName), goFileExtension)
+		case LayerNameMigration:
+			// 迁移文件以版本号为前缀，保证按生成顺序执行
+			targetFilename = fmt.Sprintf("%s_%s%s", cfg.MigrationVersion, gutil.TrimFileExtension(defaultTargetFilename), goFileExtension)
 		default:
 			targetFilename = fmt.Sprintf("%s%s", gutil.TrimFileExtension(defaultTargetFilename), goFileExtension)
 		}
 
+		// 配置了输出路径模板时，按模板渲染结果覆盖默认的目录和文件名
+		outputPathTpl, ok := cfg.OutputPathTplMap[layerName]
+		if !ok {
+			outputPathTpl, ok = cfg.OutputPathTplMap[defaultLayerName]
+		}
+		if ok {
+			var renderErr error
+			targetDir, targetFilename, renderErr = renderOutputPath(cfg, outputPathTpl, OutputPathTplParams{
+				PackageName:      cfg.PackageName,
+				TableName:        gutil.TrimFileExtension(defaultTargetFilename),
+				LayerName:        layerName,
+				LayerPrefix:      layerPrefix,
+				TargetFilename:   targetFilename,
+				MigrationVersion: cfg.MigrationVersion,
+			})
+			if renderErr != nil {
+				return nil, fmt.Errorf("render output path of layer %s fail, error: %w", defaultLayerName, renderErr)
+			}
+		}
+
 		var targetFileExist bool
 		if gutil.FileExists(filepath.Join(targetDir, targetFilename)) {
 			targetFileExist = true
 		}
-		tplFilepath := filepath.Join(cfg.TplDir, tplFilename)
-		tplInst := template.New(tplFilename).Funcs(cfg.TplFuncMap)
-		fileTemplate, parseErr := tplInst.ParseFiles(tplFilepath)
+		tplFilepath := filepath.Join(tplDir, tplFilename)
+		tplContent, readTplErr := fs.ReadFile(tplFS, tplFilename)
+		if readTplErr != nil {
+			return nil, readTplErr
+		}
+		fileTemplate, parseErr := template.New(tplFilename).Funcs(tplFuncMap(cfg.TplFuncMap)).Parse(string(tplContent))
 		if parseErr != nil {
-			return nil, parseErr
+			return nil, fmt.Errorf("parse template %s fail, error: %w", tplFilepath, parseErr)
 		}
 
 		analysisList = append(analysisList, TplAnalysisItem{
@@ -145,6 +181,7 @@ func analysisTplFiles(cfg CommonConfig, defaultTargetFilename string) ([]TplAnal
 			OriginFilename:  originFilename,
 			TargetDir:       targetDir,
 			TargetFilename:  targetFilename,
+			TargetPackage:   filepath.Base(targetDir),
 			TargetFileExist: targetFileExist,
 		})
 
@@ -152,6 +189,29 @@ func analysisTplFiles(cfg CommonConfig, defaultTargetFilename string) ([]TplAnal
 	return analysisList, nil
 }
 
+// renderOutputPath 渲染输出路径模板，返回目标目录和文件名；模板结果不以 .go 结尾时视为目录，文件名沿用 params.TargetFilename
+func renderOutputPath(cfg CommonConfig, pathTpl string, params OutputPathTplParams) (string, string, error) {
+	tpl, parseErr := template.New("outputPath").Funcs(tplFuncMap(cfg.TplFuncMap)).Option("missingkey=error").Parse(pathTpl)
+	if parseErr != nil {
+		return "", "", parseErr
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, params); err != nil {
+		return "", "", err
+	}
+	outputPath := filepath.Clean(strings.TrimSpace(buf.String()))
+	if outputPath == "." {
+		return "", "", fmt.Errorf("output path is empty")
+	}
+	if !filepath.IsAbs(outputPath) {
+		outputPath = filepath.Join(cfg.RootDir, outputPath)
+	}
+	if strings.HasSuffix(outputPath, goFileExtension) {
+		return filepath.Dir(outputPath), filepath.Base(outputPath), nil
+	}
+	return outputPath, params.TargetFilename, nil
+}
+
 func createFile(targetDir, targetFilename string, tpl *template.Template, tplParam interface{}) error {
 	if err := gutil.CreateDir(targetDir); err != nil {
 		return err
@@ -220,13 +280,9 @@ func createFile(targetDir, targetFilename string, tpl *template.Template, tplPar
 		}
 	} else {
 		// 文件不存在，生成新文件
-		var buf bytes.Buffer
-		if err := tpl.Execute(&buf, tplParam); err != nil {
-			return err
-		}
-		formattedContent, formatErr := format.Source(buf.Bytes())
-		if formatErr != nil {
-			return fmt.Errorf("format fail, error: %w", formatErr)
+		formattedContent, renderErr := renderTemplate(tpl, tplParam)
+		if renderErr != nil {
+			return renderErr
 		}
 
 		f, openErr := os.OpenFile(codeFilepath, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0666)
@@ -242,3 +298,16 @@ func createFile(targetDir, targetFilename string, tpl *template.Template, tplPar
 
 	return nil
 }
+
+// renderTemplate 执行模板并格式化结果
+func renderTemplate(tpl *template.Template, tplParam interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, tplParam); err != nil {
+		return nil, err
+	}
+	formattedContent, formatErr := format.Source(buf.Bytes())
+	if formatErr != nil {
+		return nil, fmt.Errorf("format fail, error: %w", formatErr)
+	}
+	return formattedContent, nil
+}
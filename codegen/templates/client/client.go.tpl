@@ -0,0 +1,116 @@
+package {{.Package}}
+
+import (
+	"context"
+{{- range .DtoImports}}
+	"{{.}}"
+{{- end}}
+
+	"github.com/morehao/golib/protocol/ghttp"
+)
+
+// {{.StructName}}CreateReq 创建 {{.TableName}} 请求
+type {{.StructName}}CreateReq struct {
+{{- range .CreateFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"`{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+
+// {{.StructName}}CreateRes 创建 {{.TableName}} 响应
+type {{.StructName}}CreateRes struct {
+{{- range .PKFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"`
+{{- end}}
+}
+{{if .HasPK}}
+// {{.StructName}}DeleteReq 删除 {{.TableName}} 请求
+type {{.StructName}}DeleteReq struct {
+{{- range .PKFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"`
+{{- end}}
+}
+{{if .HasUpdate}}
+// {{.StructName}}UpdateReq 更新 {{.TableName}} 请求
+type {{.StructName}}UpdateReq struct {
+{{- range .PKFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"`
+{{- end}}
+{{- range .UpdateFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"`{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+{{end}}
+// {{.StructName}}DetailReq 查询 {{.TableName}} 详情请求
+type {{.StructName}}DetailReq struct {
+{{- range .PKFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"`
+{{- end}}
+}
+{{end}}
+// {{.StructName}}PageListReq 分页查询 {{.TableName}} 请求
+type {{.StructName}}PageListReq struct {
+	Page     int `json:"page"`
+	PageSize int `json:"pageSize"`
+}
+
+// {{.StructName}}Item {{.TableName}} 记录
+type {{.StructName}}Item struct {
+{{- range .ItemFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"`{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+
+// {{.StructName}}PageListRes 分页查询 {{.TableName}} 响应
+type {{.StructName}}PageListRes struct {
+	List  []{{.StructName}}Item `json:"list"`
+	Total int64 `json:"total"`
+}
+
+// {{.StructName}}Client {{.TableName}} 接口的客户端，供其他服务调用，响应按 {code,msg,data} 解析，
+// 业务码不等于 ghttp.Client.SuccessCode 时返回 gerror.Error
+type {{.StructName}}Client struct {
+	client   *ghttp.Client
+	basePath string
+}
+
+// New{{.StructName}}Client 创建 {{.TableName}} 接口的客户端，basePath 为注册路由时的分组路径，如 /api/v1
+func New{{.StructName}}Client(client *ghttp.Client, basePath string) *{{.StructName}}Client {
+	return &{{.StructName}}Client{client: client, basePath: basePath + "/{{.TableName}}"}
+}
+
+// Create 创建 {{.TableName}}
+func (c *{{.StructName}}Client) Create(ctx context.Context, req *{{.StructName}}CreateReq) (*{{.StructName}}CreateRes, error) {
+	var res {{.StructName}}CreateRes
+	if err := c.client.PostJSONData(ctx, c.basePath+"/create", &res, ghttp.RequestOption{RequestBody: req}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+{{if .HasPK}}
+// Delete 删除 {{.TableName}}
+func (c *{{.StructName}}Client) Delete(ctx context.Context, req *{{.StructName}}DeleteReq) error {
+	return c.client.PostJSONData(ctx, c.basePath+"/delete", nil, ghttp.RequestOption{RequestBody: req})
+}
+{{if .HasUpdate}}
+// Update 更新 {{.TableName}}
+func (c *{{.StructName}}Client) Update(ctx context.Context, req *{{.StructName}}UpdateReq) error {
+	return c.client.PostJSONData(ctx, c.basePath+"/update", nil, ghttp.RequestOption{RequestBody: req})
+}
+{{end}}
+// Detail 查询 {{.TableName}} 详情
+func (c *{{.StructName}}Client) Detail(ctx context.Context, req *{{.StructName}}DetailReq) (*{{.StructName}}Item, error) {
+	var res {{.StructName}}Item
+	if err := c.client.PostJSONData(ctx, c.basePath+"/detail", &res, ghttp.RequestOption{RequestBody: req}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+{{end}}
+// PageList 分页查询 {{.TableName}}
+func (c *{{.StructName}}Client) PageList(ctx context.Context, req *{{.StructName}}PageListReq) (*{{.StructName}}PageListRes, error) {
+	var res {{.StructName}}PageListRes
+	if err := c.client.PostJSONData(ctx, c.basePath+"/pageList", &res, ghttp.RequestOption{RequestBody: req}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,23 @@
+package {{.Package}}
+
+import "github.com/morehao/golib/gerror"
+
+// {{.TableName}} 模块错误码 ({{.ErrorCodeStart}}-{{.ErrorCodeEnd}})，号段由代码生成分配，新增错误码在号段内顺延
+const (
+{{- range .ErrorCodes}}
+	{{.Name}} = {{.Code}}
+{{- end}}
+)
+
+var {{.StructName}}ErrorMsgMap = gerror.CodeMsgMap{
+{{- range .ErrorCodes}}
+	{{.Name}}: "{{.Msg}}",
+{{- end}}
+}
+
+// {{.StructName}}ErrorMap {{.TableName}} 模块错误，如 {{.StructName}}ErrorMap.MustGet({{.StructName}}NotExistErr)
+var {{.StructName}}ErrorMap = {{.StructName}}ErrorMsgMap.ToErrorMap()
+
+func init() {
+	gerror.MustRegister("{{.TableName}}", {{.StructName}}ErrorMsgMap)
+}
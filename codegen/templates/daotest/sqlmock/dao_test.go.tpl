@@ -0,0 +1,149 @@
+{{- $model := index .Layers "model" -}}
+{{- $struct := .StructName -}}
+package {{.Package}}
+
+import (
+	"context"
+	"regexp"
+	"testing"
+{{- if .HasPK}}
+{{- range .PKImports}}
+	"{{.}}"
+{{- end}}
+{{- end}}
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"{{$model.ImportPath}}"
+{{- if eq .Dialect "postgres"}}
+	"gorm.io/driver/postgres"
+{{- else}}
+	"gorm.io/driver/mysql"
+{{- end}}
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newMock{{$struct}}Dao 创建基于 sqlmock 的 {{.TableName}} 表数据访问，关闭默认事务，期望只需声明每个方法执行的语句
+func newMock{{$struct}}Dao(t *testing.T) (*{{$struct}}Dao, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock fail, error: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+{{- if eq .Dialect "postgres"}}
+	dialector := postgres.New(postgres.Config{Conn: sqlDB})
+{{- else}}
+	dialector := mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true})
+{{- end}}
+	db, err := gorm.Open(dialector, &gorm.Config{SkipDefaultTransaction: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open gorm fail, error: %v", err)
+	}
+	return New{{$struct}}Dao(func(ctx context.Context) *gorm.DB { return db.WithContext(ctx) }), mock
+}
+
+func Test{{$struct}}Dao(t *testing.T) {
+	// 表名两侧为 mysql 的反引号或 postgres 的双引号
+	table := "[`\"]" + regexp.QuoteMeta({{$model.Package}}.TableName{{$struct}}) + "[`\"]"
+	columns := []string{ {{- range $i, $v := .ModelFields}}{{if $i}}, {{end}}"{{$v.ColumnName}}"{{end -}} }
+	tests := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+		call   func(ctx context.Context, dao *{{$struct}}Dao) error
+	}{
+		{
+			name: "Create",
+			expect: func(mock sqlmock.Sqlmock) {
+{{- if and (eq .Dialect "postgres") .IsAutoIncrPK}}
+				mock.ExpectQuery("^INSERT INTO " + table).WillReturnRows(sqlmock.NewRows([]string{"{{(index .PKFields 0).ColumnName}}"}).AddRow(1))
+{{- else}}
+				mock.ExpectExec("^INSERT INTO " + table).WillReturnResult(sqlmock.NewResult(1, 1))
+{{- end}}
+			},
+			call: func(ctx context.Context, dao *{{$struct}}Dao) error {
+				return dao.Create(ctx, &{{$model.Package}}.{{$struct}}{})
+			},
+		},
+{{- if .HasPK}}
+		{
+			name: "GetByPK",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT \\* FROM " + table).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			call: func(ctx context.Context, dao *{{$struct}}Dao) error {
+{{- range .PKFields}}
+				var {{.VarName}} {{.FieldType}}
+{{- end}}
+				_, err := dao.GetByPK(ctx{{range .PKFields}}, {{.VarName}}{{end}})
+				return err
+			},
+		},
+{{- if .HasUpdate}}
+		{
+			name: "UpdateByPK",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("^UPDATE " + table).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			call: func(ctx context.Context, dao *{{$struct}}Dao) error {
+{{- range .PKFields}}
+				var {{.VarName}} {{.FieldType}}
+{{- end}}
+				return dao.UpdateByPK(ctx{{range .PKFields}}, {{.VarName}}{{end}}, map[string]any{"{{(index .UpdateFields 0).ColumnName}}": nil})
+			},
+		},
+{{- end}}
+		{
+			name: "DeleteByPK",
+			expect: func(mock sqlmock.Sqlmock) {
+{{- if .HasSoftDelete}}
+				// deleted_at 为 gorm.DeletedAt，删除为软删除
+				mock.ExpectExec("^UPDATE " + table).WillReturnResult(sqlmock.NewResult(0, 1))
+{{- else}}
+				mock.ExpectExec("^DELETE FROM " + table).WillReturnResult(sqlmock.NewResult(0, 1))
+{{- end}}
+			},
+			call: func(ctx context.Context, dao *{{$struct}}Dao) error {
+{{- range .PKFields}}
+				var {{.VarName}} {{.FieldType}}
+{{- end}}
+				return dao.DeleteByPK(ctx{{range .PKFields}}, {{.VarName}}{{end}})
+			},
+		},
+{{- end}}
+		{
+			name: "PageList",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT count\\(\\*\\) FROM " + table).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("^SELECT \\* FROM " + table).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			call: func(ctx context.Context, dao *{{$struct}}Dao) error {
+				_, _, err := dao.PageList(ctx, 1, 10)
+				return err
+			},
+		},
+		{
+			name: "ListByFilter",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT count\\(\\*\\) FROM " + table).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("^SELECT \\* FROM " + table).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			call: func(ctx context.Context, dao *{{$struct}}Dao) error {
+				_, _, err := dao.ListByFilter(ctx, &{{$struct}}Filter{})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao, mock := newMock{{$struct}}Dao(t)
+			tt.expect(mock)
+			if err := tt.call(context.Background(), dao); err != nil {
+				t.Fatalf("call fail, error: %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
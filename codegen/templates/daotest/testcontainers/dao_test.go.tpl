@@ -0,0 +1,164 @@
+{{- $model := index .Layers "model" -}}
+{{- $struct := .StructName -}}
+package {{.Package}}
+
+import (
+	"context"
+	"testing"
+
+	"{{$model.ImportPath}}"
+	"github.com/testcontainers/testcontainers-go"
+{{- if eq .Dialect "postgres"}}
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"gorm.io/driver/postgres"
+{{- else}}
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
+	"gorm.io/driver/mysql"
+{{- end}}
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newContainer{{$struct}}Dao 启动数据库容器并按模型建表，返回 {{.TableName}} 表数据访问。
+// 表结构由 AutoMigrate 根据模型生成，依赖数据库特性的列可改为执行迁移文件
+func newContainer{{$struct}}Dao(t *testing.T) *{{$struct}}Dao {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skip container test in short mode")
+	}
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+{{- if eq .Dialect "postgres"}}
+	container, err := tcpostgres.Run(ctx, "postgres:16-alpine",
+		tcpostgres.WithDatabase("test"),
+		tcpostgres.WithUsername("test"),
+		tcpostgres.WithPassword("test"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start postgres container fail, error: %v", err)
+	}
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("get postgres dsn fail, error: %v", err)
+	}
+	dialector := postgres.Open(dsn)
+{{- else}}
+	container, err := tcmysql.Run(ctx, "mysql:8.0",
+		tcmysql.WithDatabase("test"),
+		tcmysql.WithUsername("test"),
+		tcmysql.WithPassword("test"),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start mysql container fail, error: %v", err)
+	}
+	dsn, err := container.ConnectionString(ctx, "parseTime=true", "loc=Local")
+	if err != nil {
+		t.Fatalf("get mysql dsn fail, error: %v", err)
+	}
+	// 未指定长度的字符串列使用 varchar(256)，便于建立索引
+	dialector := mysql.New(mysql.Config{DSN: dsn, DefaultStringSize: 256})
+{{- end}}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open gorm fail, error: %v", err)
+	}
+	if err := db.AutoMigrate(&{{$model.Package}}.{{$struct}}{}); err != nil {
+		t.Fatalf("migrate {{.TableName}} fail, error: %v", err)
+	}
+	return New{{$struct}}Dao(func(ctx context.Context) *gorm.DB { return db.WithContext(ctx) })
+}
+
+// Test{{$struct}}Dao 按顺序执行 CRUD，后续步骤依赖 Create 写入的记录
+func Test{{$struct}}Dao(t *testing.T) {
+	dao := newContainer{{$struct}}Dao(t)
+	ctx := context.Background()
+	entity := &{{$model.Package}}.{{$struct}}{}
+	steps := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{
+			name: "Create",
+			run: func(t *testing.T) {
+				if err := dao.Create(ctx, entity); err != nil {
+					t.Fatalf("create fail, error: %v", err)
+				}
+			},
+		},
+{{- if .HasPK}}
+		{
+			name: "GetByPK",
+			run: func(t *testing.T) {
+				got, err := dao.GetByPK(ctx{{range .PKFields}}, entity.{{.FieldName}}{{end}})
+				if err != nil {
+					t.Fatalf("get fail, error: %v", err)
+				}
+				if got == nil {
+					t.Fatal("get fail, record not found")
+				}
+			},
+		},
+{{- if .HasUpdate}}
+{{- $field := index .UpdateFields 0}}
+		{
+			name: "UpdateByPK",
+			run: func(t *testing.T) {
+				if err := dao.UpdateByPK(ctx{{range .PKFields}}, entity.{{.FieldName}}{{end}}, map[string]any{"{{$field.ColumnName}}": entity.{{$field.FieldName}}}); err != nil {
+					t.Fatalf("update fail, error: %v", err)
+				}
+			},
+		},
+{{- end}}
+{{- end}}
+		{
+			name: "PageList",
+			run: func(t *testing.T) {
+				list, total, err := dao.PageList(ctx, 1, 10)
+				if err != nil {
+					t.Fatalf("page list fail, error: %v", err)
+				}
+				if total != 1 || len(list) != 1 {
+					t.Fatalf("page list got %d records, total %d, want 1", len(list), total)
+				}
+			},
+		},
+		{
+			name: "ListByFilter",
+			run: func(t *testing.T) {
+				list, total, err := dao.ListByFilter(ctx, &{{$struct}}Filter{})
+				if err != nil {
+					t.Fatalf("list by filter fail, error: %v", err)
+				}
+				if total != 1 || len(list) != 1 {
+					t.Fatalf("list by filter got %d records, total %d, want 1", len(list), total)
+				}
+			},
+		},
+{{- if .HasPK}}
+		{
+			name: "DeleteByPK",
+			run: func(t *testing.T) {
+				if err := dao.DeleteByPK(ctx{{range .PKFields}}, entity.{{.FieldName}}{{end}}); err != nil {
+					t.Fatalf("delete fail, error: %v", err)
+				}
+				got, err := dao.GetByPK(ctx{{range .PKFields}}, entity.{{.FieldName}}{{end}})
+				if err != nil {
+					t.Fatalf("get after delete fail, error: %v", err)
+				}
+				if got != nil {
+					t.Fatal("record still exists after delete")
+				}
+			},
+		},
+{{- end}}
+	}
+	for _, step := range steps {
+		if !t.Run(step.name, step.run) {
+			return
+		}
+	}
+}
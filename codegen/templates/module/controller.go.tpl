@@ -0,0 +1,129 @@
+{{- $dto := index .Layers "dto" -}}
+{{- $service := index .Layers "service" -}}
+package {{.Package}}
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/morehao/golib/biz/gcontext/gincontext"
+
+	"{{$dto.ImportPath}}"
+	"{{$service.ImportPath}}"
+)
+
+// {{.StructName}}Ctr {{.TableName}} 接口
+type {{.StructName}}Ctr struct {
+	svc *{{$service.Package}}.{{.StructName}}Svc
+}
+
+// New{{.StructName}}Ctr 创建 {{.TableName}} 接口
+func New{{.StructName}}Ctr(svc *{{$service.Package}}.{{.StructName}}Svc) *{{.StructName}}Ctr {
+	return &{{.StructName}}Ctr{svc: svc}
+}
+
+// Create 创建 {{.TableName}}
+// @Tags {{.TableName}}
+// @Summary 创建 {{.TableName}}
+// @accept application/json
+// @Produce application/json
+// @Param req body {{$dto.Package}}.{{.StructName}}CreateReq true "创建请求"
+// @Success 200 {object} gincontext.DtoRender{data={{$dto.Package}}.{{.StructName}}CreateRes}
+// @Router /{{.TableName}}/create [post]
+func (ctr *{{.StructName}}Ctr) Create(ctx *gin.Context) {
+	var req {{$dto.Package}}.{{.StructName}}CreateReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	res, err := ctr.svc.Create(ctx, &req)
+	if err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	gincontext.Success(ctx, res)
+}
+{{if .HasPK}}
+// Delete 删除 {{.TableName}}
+// @Tags {{.TableName}}
+// @Summary 删除 {{.TableName}}
+// @accept application/json
+// @Produce application/json
+// @Param req body {{$dto.Package}}.{{.StructName}}DeleteReq true "删除请求"
+// @Success 200 {object} gincontext.DtoRender
+// @Router /{{.TableName}}/delete [post]
+func (ctr *{{.StructName}}Ctr) Delete(ctx *gin.Context) {
+	var req {{$dto.Package}}.{{.StructName}}DeleteReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	if err := ctr.svc.Delete(ctx, &req); err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	gincontext.Success(ctx, nil)
+}
+{{if .HasUpdate}}
+// Update 更新 {{.TableName}}
+// @Tags {{.TableName}}
+// @Summary 更新 {{.TableName}}
+// @accept application/json
+// @Produce application/json
+// @Param req body {{$dto.Package}}.{{.StructName}}UpdateReq true "更新请求"
+// @Success 200 {object} gincontext.DtoRender
+// @Router /{{.TableName}}/update [post]
+func (ctr *{{.StructName}}Ctr) Update(ctx *gin.Context) {
+	var req {{$dto.Package}}.{{.StructName}}UpdateReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	if err := ctr.svc.Update(ctx, &req); err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	gincontext.Success(ctx, nil)
+}
+{{end}}
+// Detail 查询 {{.TableName}} 详情
+// @Tags {{.TableName}}
+// @Summary 查询 {{.TableName}} 详情
+// @accept application/json
+// @Produce application/json
+// @Param req body {{$dto.Package}}.{{.StructName}}DetailReq true "查询请求"
+// @Success 200 {object} gincontext.DtoRender{data={{$dto.Package}}.{{.StructName}}Item}
+// @Router /{{.TableName}}/detail [post]
+func (ctr *{{.StructName}}Ctr) Detail(ctx *gin.Context) {
+	var req {{$dto.Package}}.{{.StructName}}DetailReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	res, err := ctr.svc.Detail(ctx, &req)
+	if err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	gincontext.Success(ctx, res)
+}
+{{end}}
+// PageList 分页查询 {{.TableName}}
+// @Tags {{.TableName}}
+// @Summary 分页查询 {{.TableName}}
+// @accept application/json
+// @Produce application/json
+// @Param req body {{$dto.Package}}.{{.StructName}}PageListReq true "分页查询请求"
+// @Success 200 {object} gincontext.DtoRender{data={{$dto.Package}}.{{.StructName}}PageListRes}
+// @Router /{{.TableName}}/pageList [post]
+func (ctr *{{.StructName}}Ctr) PageList(ctx *gin.Context) {
+	var req {{$dto.Package}}.{{.StructName}}PageListReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	res, err := ctr.svc.PageList(ctx, &req)
+	if err != nil {
+		gincontext.Fail(ctx, err)
+		return
+	}
+	gincontext.Success(ctx, res)
+}
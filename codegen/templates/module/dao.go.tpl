@@ -0,0 +1,150 @@
+{{- $model := index .Layers "model" -}}
+package {{.Package}}
+
+import (
+	"context"
+{{- if .HasPK}}
+	"errors"
+{{- end}}
+{{- range .DaoImports}}
+	"{{.}}"
+{{- end}}
+
+	"{{$model.ImportPath}}"
+	"github.com/morehao/golib/biz/gobject"
+	"gorm.io/gorm"
+)
+
+// {{.StructName}}Dao {{.TableName}} 表数据访问
+type {{.StructName}}Dao struct {
+	getDB func(ctx context.Context) *gorm.DB
+}
+
+// New{{.StructName}}Dao 创建 {{.TableName}} 表数据访问，getDB 返回携带 ctx 的数据库连接
+func New{{.StructName}}Dao(getDB func(ctx context.Context) *gorm.DB) *{{.StructName}}Dao {
+	return &{{.StructName}}Dao{getDB: getDB}
+}
+
+// DB 返回绑定了 {{.TableName}} 表模型的数据库连接
+func (d *{{.StructName}}Dao) DB(ctx context.Context) *gorm.DB {
+	return d.getDB(ctx).Model(&{{$model.Package}}.{{.StructName}}{})
+}
+
+// Create 创建记录
+func (d *{{.StructName}}Dao) Create(ctx context.Context, entity *{{$model.Package}}.{{.StructName}}) error {
+	return d.DB(ctx).Create(entity).Error
+}
+{{if .HasPK}}
+// GetByPK 按行标识查询记录，不存在时返回 nil
+func (d *{{.StructName}}Dao) GetByPK(ctx context.Context{{range .PKFields}}, {{.VarName}} {{.FieldType}}{{end}}) (*{{$model.Package}}.{{.StructName}}, error) {
+	var entity {{$model.Package}}.{{.StructName}}
+	err := d.DB(ctx).Where("{{.PKWhere}}"{{range .PKFields}}, {{.VarName}}{{end}}).Take(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+{{if .HasUpdate}}
+// UpdateByPK 按行标识更新指定列，fields 的 key 为列名
+func (d *{{.StructName}}Dao) UpdateByPK(ctx context.Context{{range .PKFields}}, {{.VarName}} {{.FieldType}}{{end}}, fields map[string]any) error {
+	return d.DB(ctx).Where("{{.PKWhere}}"{{range .PKFields}}, {{.VarName}}{{end}}).Updates(fields).Error
+}
+{{end}}
+// DeleteByPK 按行标识删除记录
+func (d *{{.StructName}}Dao) DeleteByPK(ctx context.Context{{range .PKFields}}, {{.VarName}} {{.FieldType}}{{end}}) error {
+	return d.DB(ctx).Where("{{.PKWhere}}"{{range .PKFields}}, {{.VarName}}{{end}}).Delete(&{{$model.Package}}.{{.StructName}}{}).Error
+}
+{{end}}
+// PageList 分页查询记录，page 从 1 开始
+func (d *{{.StructName}}Dao) PageList(ctx context.Context, page, pageSize int) ([]{{$model.Package}}.{{.StructName}}, int64, error) {
+	var total int64
+	if err := d.DB(ctx).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []{{$model.Package}}.{{.StructName}}
+	err := d.DB(ctx){{if .HasPK}}.Order("{{.PKOrder}}"){{end}}.Offset((page - 1) * pageSize).Limit(pageSize).Find(&list).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// ListByFilter 按条件分页查询记录，返回当前页记录和满足条件的总数
+func (d *{{.StructName}}Dao) ListByFilter(ctx context.Context, filter *{{.StructName}}Filter) ([]{{$model.Package}}.{{.StructName}}, int64, error) {
+	db := filter.Apply(d.DB(ctx))
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []{{$model.Package}}.{{.StructName}}
+	err := filter.Paginate(db){{if .HasPK}}.Order("{{.PKOrder}}"){{end}}.Find(&list).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// {{.StructName}}Filter {{.TableName}} 查询条件，值为 nil 或空切片的条件不生效
+type {{.StructName}}Filter struct {
+	gobject.PageQuery
+{{- range .FilterFields}}
+	{{.FieldName}} *{{.ValueType}}{{if .Comment}} // {{.Comment}}{{end}}
+{{- if .HasIn}}
+	{{.FieldName}}In []{{.ValueType}}
+{{- end}}
+{{- if .HasRange}}
+	{{.FieldName}}Gte *{{.ValueType}}
+	{{.FieldName}}Lte *{{.ValueType}}
+{{- end}}
+{{- if .HasLike}}
+	{{.FieldName}}Like *string // 模糊匹配，通配符按普通字符处理
+{{- end}}
+{{- end}}
+}
+
+// Apply 将查询条件应用到 db，不包含分页
+func (f *{{.StructName}}Filter) Apply(db *gorm.DB) *gorm.DB {
+	if f == nil {
+		return db
+	}
+{{- $hasLike := false}}
+{{- range .FilterFields}}{{if .HasLike}}{{$hasLike = true}}{{end}}{{end}}
+{{- if $hasLike}}
+	likeReplacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+{{- end}}
+{{- range .FilterFields}}
+	if f.{{.FieldName}} != nil {
+		db = db.Where("{{.ColumnName}} = ?", *f.{{.FieldName}})
+	}
+{{- if .HasIn}}
+	if len(f.{{.FieldName}}In) > 0 {
+		db = db.Where("{{.ColumnName}} IN ?", f.{{.FieldName}}In)
+	}
+{{- end}}
+{{- if .HasRange}}
+	if f.{{.FieldName}}Gte != nil {
+		db = db.Where("{{.ColumnName}} >= ?", *f.{{.FieldName}}Gte)
+	}
+	if f.{{.FieldName}}Lte != nil {
+		db = db.Where("{{.ColumnName}} <= ?", *f.{{.FieldName}}Lte)
+	}
+{{- end}}
+{{- if .HasLike}}
+	if f.{{.FieldName}}Like != nil {
+		db = db.Where("{{.ColumnName}} LIKE ?", "%"+likeReplacer.Replace(*f.{{.FieldName}}Like)+"%")
+	}
+{{- end}}
+{{- end}}
+	return db
+}
+
+// Paginate 按 PageQuery 分页，Page 或 PageSize <= 0 时不分页
+func (f *{{.StructName}}Filter) Paginate(db *gorm.DB) *gorm.DB {
+	if f == nil || f.PageQuery.Page <= 0 || f.PageQuery.PageSize <= 0 {
+		return db
+	}
+	return db.Offset((f.PageQuery.Page - 1) * f.PageQuery.PageSize).Limit(f.PageQuery.PageSize)
+}
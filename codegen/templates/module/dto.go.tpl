@@ -0,0 +1,64 @@
+package {{.Package}}
+{{if .DtoImports}}
+import (
+{{- range .DtoImports}}
+	"{{.}}"
+{{- end}}
+)
+{{end}}
+// {{.StructName}}CreateReq 创建 {{.TableName}} 请求
+type {{.StructName}}CreateReq struct {
+{{- range .CreateFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"{{if .Enum}}{{with .Enum.OneOf}} binding:"omitempty,oneof={{.}}"{{end}}{{end}}{{if .SwagTag}} {{.SwagTag}}{{end}}`{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+
+// {{.StructName}}CreateRes 创建 {{.TableName}} 响应
+type {{.StructName}}CreateRes struct {
+{{- range .PKFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"`
+{{- end}}
+}
+{{if .HasPK}}
+// {{.StructName}}DeleteReq 删除 {{.TableName}} 请求
+type {{.StructName}}DeleteReq struct {
+{{- range .PKFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}" binding:"required"`
+{{- end}}
+}
+{{if .HasUpdate}}
+// {{.StructName}}UpdateReq 更新 {{.TableName}} 请求
+type {{.StructName}}UpdateReq struct {
+{{- range .PKFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}" binding:"required"`
+{{- end}}
+{{- range .UpdateFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"{{if .Enum}}{{with .Enum.OneOf}} binding:"omitempty,oneof={{.}}"{{end}}{{end}}{{if .SwagTag}} {{.SwagTag}}{{end}}`{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+{{end}}
+// {{.StructName}}DetailReq 查询 {{.TableName}} 详情请求
+type {{.StructName}}DetailReq struct {
+{{- range .PKFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}" binding:"required"`
+{{- end}}
+}
+{{end}}
+// {{.StructName}}PageListReq 分页查询 {{.TableName}} 请求
+type {{.StructName}}PageListReq struct {
+	Page     int `json:"page"`
+	PageSize int `json:"pageSize"`
+}
+
+// {{.StructName}}Item {{.TableName}} 记录
+type {{.StructName}}Item struct {
+{{- range .ItemFields}}
+	{{.FieldName}} {{.FieldType}} `json:"{{.JSONName}}"{{if .SwagTag}} {{.SwagTag}}{{end}}`{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+
+// {{.StructName}}PageListRes 分页查询 {{.TableName}} 响应
+type {{.StructName}}PageListRes struct {
+	List  []{{.StructName}}Item `json:"list"`
+	Total int64 `json:"total"`
+}
@@ -0,0 +1,86 @@
+package {{.Package}}
+{{if .ModelImports}}
+import (
+{{- range .ModelImports}}
+	"{{.}}"
+{{- end}}
+)
+{{end}}
+// TableName{{.StructName}} {{.TableName}} 表名
+const TableName{{.StructName}} = "{{.TableName}}"
+
+// {{.StructName}} {{.TableName}} 表模型
+type {{.StructName}} struct {
+{{- range .ModelFields}}
+	{{.FieldName}} {{.ModelType}} `{{gormTag .}}`{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+}
+
+func ({{.StructName}}) TableName() string {
+	return TableName{{.StructName}}
+}
+{{- range .Enums}}
+{{- $enum := .}}
+
+// {{.TypeName}} {{if .IsSet}}集合{{else}}枚举{{end}}
+type {{.TypeName}} {{.BaseType}}
+
+const (
+{{- range .Values}}
+	{{.ConstName}} {{$enum.TypeName}} = {{.Value}}{{if not $enum.IsString}} // {{.Label}}{{end}}
+{{- end}}
+)
+{{if .IsSet}}
+// IsValid 判断各成员是否均为定义的值，空集合视为合法
+func (v {{.TypeName}}) IsValid() bool {
+	if v == "" {
+		return true
+	}
+	for _, member := range strings.Split(string(v), ",") {
+		switch {{.TypeName}}(member) {
+		case {{range $i, $v := .Values}}{{if $i}}, {{end}}{{$v.ConstName}}{{end}}:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Has 判断集合是否包含 member
+func (v {{.TypeName}}) Has(member {{.TypeName}}) bool {
+	for _, item := range strings.Split(string(v), ",") {
+		if item == string(member) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v {{.TypeName}}) String() string {
+	return string(v)
+}
+{{else}}
+// IsValid 判断是否为定义的枚举值
+func (v {{.TypeName}}) IsValid() bool {
+	switch v {
+	case {{range $i, $v := .Values}}{{if $i}}, {{end}}{{$v.ConstName}}{{end}}:
+		return true
+	}
+	return false
+}
+
+func (v {{.TypeName}}) String() string {
+{{- if .IsString}}
+	return string(v)
+{{- else}}
+	switch v {
+{{- range .Values}}
+	case {{.ConstName}}:
+		return {{printf "%q" .Label}}
+{{- end}}
+	}
+	return fmt.Sprint({{.BaseType}}(v))
+{{- end}}
+}
+{{end}}
+{{- end}}
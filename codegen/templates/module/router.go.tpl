@@ -0,0 +1,22 @@
+{{- $controller := index .Layers "controller" -}}
+package {{.Package}}
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"{{$controller.ImportPath}}"
+)
+
+// Register{{.StructName}}Router 注册 {{.TableName}} 的 CRUD 路由
+func Register{{.StructName}}Router(routerGroup *gin.RouterGroup, ctr *{{$controller.Package}}.{{.StructName}}Ctr) {
+	group := routerGroup.Group("/{{.TableName}}")
+	group.POST("/create", ctr.Create)
+{{- if .HasPK}}
+	group.POST("/delete", ctr.Delete)
+{{- if .HasUpdate}}
+	group.POST("/update", ctr.Update)
+{{- end}}
+	group.POST("/detail", ctr.Detail)
+{{- end}}
+	group.POST("/pageList", ctr.PageList)
+}
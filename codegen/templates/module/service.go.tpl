@@ -0,0 +1,107 @@
+{{- $model := index .Layers "model" -}}
+{{- $dao := index .Layers "dao" -}}
+{{- $dto := index .Layers "dto" -}}
+{{- $code := index .Layers "code" -}}
+package {{.Package}}
+
+import (
+	"context"
+{{- if and .HasPK (not .ErrorCodes)}}
+	"errors"
+{{- end}}
+
+{{if and .HasPK .ErrorCodes}}	"{{$code.ImportPath}}"
+{{end}}	"{{$dao.ImportPath}}"
+	"{{$dto.ImportPath}}"
+	"{{$model.ImportPath}}"
+)
+
+// {{.StructName}}Svc {{.TableName}} 业务逻辑
+type {{.StructName}}Svc struct {
+	dao *{{$dao.Package}}.{{.StructName}}Dao
+}
+
+// New{{.StructName}}Svc 创建 {{.TableName}} 业务逻辑
+func New{{.StructName}}Svc(dao *{{$dao.Package}}.{{.StructName}}Dao) *{{.StructName}}Svc {
+	return &{{.StructName}}Svc{dao: dao}
+}
+
+// Create 创建 {{.TableName}}
+func (svc *{{.StructName}}Svc) Create(ctx context.Context, req *{{$dto.Package}}.{{.StructName}}CreateReq) (*{{$dto.Package}}.{{.StructName}}CreateRes, error) {
+	entity := &{{$model.Package}}.{{.StructName}}{
+{{- range .CreateFields}}
+		{{.FieldName}}: {{if .Enum}}({{if .IsPointer}}*{{end}}{{$model.Package}}.{{.Enum.TypeName}})(req.{{.FieldName}}){{else}}req.{{.FieldName}}{{end}},
+{{- end}}
+	}
+	if err := svc.dao.Create(ctx, entity); err != nil {
+		return nil, err
+	}
+	return &{{$dto.Package}}.{{.StructName}}CreateRes{
+{{- range .PKFields}}
+		{{.FieldName}}: entity.{{.FieldName}},
+{{- end}}
+	}, nil
+}
+{{if .HasPK}}
+// Delete 删除 {{.TableName}}
+func (svc *{{.StructName}}Svc) Delete(ctx context.Context, req *{{$dto.Package}}.{{.StructName}}DeleteReq) error {
+	return svc.dao.DeleteByPK(ctx{{range .PKFields}}, req.{{.FieldName}}{{end}})
+}
+{{if .HasUpdate}}
+// Update 更新 {{.TableName}}
+func (svc *{{.StructName}}Svc) Update(ctx context.Context, req *{{$dto.Package}}.{{.StructName}}UpdateReq) error {
+	fields := map[string]any{
+{{- range .UpdateFields}}
+		"{{.ColumnName}}": req.{{.FieldName}},
+{{- end}}
+	}
+	return svc.dao.UpdateByPK(ctx{{range .PKFields}}, req.{{.FieldName}}{{end}}, fields)
+}
+{{end}}
+// Detail 查询 {{.TableName}} 详情
+func (svc *{{.StructName}}Svc) Detail(ctx context.Context, req *{{$dto.Package}}.{{.StructName}}DetailReq) (*{{$dto.Package}}.{{.StructName}}Item, error) {
+	entity, err := svc.dao.GetByPK(ctx{{range .PKFields}}, req.{{.FieldName}}{{end}})
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+{{- if .ErrorCodes}}
+		return nil, {{$code.Package}}.{{.StructName}}ErrorMap.MustGet({{$code.Package}}.{{.StructName}}NotExistErr)
+{{- else}}
+		return nil, errors.New("{{.TableName}} not found")
+{{- end}}
+	}
+	item := to{{.StructName}}Item(entity)
+	return &item, nil
+}
+{{end}}
+// PageList 分页查询 {{.TableName}}
+func (svc *{{.StructName}}Svc) PageList(ctx context.Context, req *{{$dto.Package}}.{{.StructName}}PageListReq) (*{{$dto.Package}}.{{.StructName}}PageListRes, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	list, total, err := svc.dao.PageList(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	res := &{{$dto.Package}}.{{.StructName}}PageListRes{
+		List:  make([]{{$dto.Package}}.{{.StructName}}Item, 0, len(list)),
+		Total: total,
+	}
+	for i := range list {
+		res.List = append(res.List, to{{.StructName}}Item(&list[i]))
+	}
+	return res, nil
+}
+
+func to{{.StructName}}Item(entity *{{$model.Package}}.{{.StructName}}) {{$dto.Package}}.{{.StructName}}Item {
+	return {{$dto.Package}}.{{.StructName}}Item{
+{{- range .ItemFields}}
+		{{.FieldName}}: {{if .Enum}}({{.FieldType}})(entity.{{.FieldName}}){{else}}entity.{{.FieldName}}{{end}},
+{{- end}}
+	}
+}
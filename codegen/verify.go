@@ -0,0 +1,292 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DriftKind 模型与表结构的差异类型
+type DriftKind string
+
+const (
+	DriftModelMissing  DriftKind = "model_missing"  // 模型文件或结构体不存在
+	DriftColumnAdded   DriftKind = "column_added"   // 表中新增的列，模型中缺少对应字段
+	DriftColumnDropped DriftKind = "column_dropped" // 表中已删除的列，模型中仍有对应字段
+	DriftTypeChanged   DriftKind = "type_changed"   // 字段类型与按当前表结构生成的类型不一致
+)
+
+// Drift 单个差异
+type Drift struct {
+	Kind       DriftKind `json:"kind"`
+	Column     string    `json:"column,omitempty"`
+	Field      string    `json:"field,omitempty"`      // 模型字段名，column_added 时为按命名规则生成的字段名
+	ModelType  string    `json:"modelType,omitempty"`  // 模型中的字段类型
+	SchemaType string    `json:"schemaType,omitempty"` // 按当前表结构生成的字段类型
+}
+
+// String 返回差异描述，如 column_added: email (Email string)
+func (d Drift) String() string {
+	switch d.Kind {
+	case DriftModelMissing:
+		return string(d.Kind)
+	case DriftTypeChanged:
+		return fmt.Sprintf("%s: %s (%s %s -> %s)", d.Kind, d.Column, d.Field, d.ModelType, d.SchemaType)
+	case DriftColumnDropped:
+		return fmt.Sprintf("%s: %s (%s %s)", d.Kind, d.Column, d.Field, d.ModelType)
+	default:
+		return fmt.Sprintf("%s: %s (%s %s)", d.Kind, d.Column, d.Field, d.SchemaType)
+	}
+}
+
+// TableVerifyRes 单张表的校验结果
+type TableVerifyRes struct {
+	TableName  string  `json:"table"`
+	StructName string  `json:"struct"`
+	ModelFile  string  `json:"modelFile"`
+	Drifts     []Drift `json:"drifts"`
+	Error      string  `json:"error,omitempty"` // 校验失败的原因，如表结构读取失败
+}
+
+// VerifyRes 批量校验结果，按表名排序，可直接序列化为 JSON 供 CI 使用
+type VerifyRes struct {
+	Tables []TableVerifyRes `json:"tables"`
+}
+
+// HasDrift 是否存在差异或校验失败的表，可作为 CI 的失败条件
+func (r *VerifyRes) HasDrift() bool {
+	for _, v := range r.Tables {
+		if len(v.Drifts) > 0 || v.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Report 返回校验结果汇总，每个差异一行，末尾为合计
+func (r *VerifyRes) Report() string {
+	var (
+		sb             strings.Builder
+		drifts, failed int
+	)
+	for _, v := range r.Tables {
+		if v.Error != "" {
+			failed++
+			fmt.Fprintf(&sb, "%s: failed, %s\n", v.TableName, v.Error)
+			continue
+		}
+		drifts += len(v.Drifts)
+		for _, drift := range v.Drifts {
+			fmt.Fprintf(&sb, "%s: %s\n", v.TableName, drift)
+		}
+	}
+	fmt.Fprintf(&sb, "total: %d tables, %d failed, %d drifts", len(r.Tables), failed, drifts)
+	return sb.String()
+}
+
+// Verify 按 cfg.TableNames（未设置时为 cfg.TableName）比较已生成的模型结构体与当前表结构，报告新增列、删除列和类型变化。
+// 模型文件路径、结构体名和期望的字段类型与 GenerateModule 的规则一致，因此应使用生成时的配置；
+// 字段按 gorm 标签中的 column 对应到列，未设置时按 gorm 默认规则由字段名推断
+func Verify(db *gorm.DB, cfg *ModuleCfg) (*VerifyRes, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	tableList, listErr := listTables(db, cfg.SchemaName)
+	if listErr != nil {
+		return nil, listErr
+	}
+	return verifyModules(cfg, tableList, func(tableCfg *ModuleCfg) (*ModuleTplAnalysisRes, error) {
+		return NewGenerator().AnalysisModuleTpl(db, tableCfg)
+	})
+}
+
+// VerifyFromDDL 解析 DDL 并比较已生成的模型结构体与其中的表结构，规则同 Verify，适用于以迁移文件为准的 CI
+func VerifyFromDDL(sqlText string, cfg *ModuleCfg) (*VerifyRes, error) {
+	tables, parseErr := parseDDL(sqlText)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	tableList := make([]string, 0, len(tables))
+	for _, v := range tables {
+		tableList = append(tableList, v.name)
+	}
+	return verifyModules(cfg, tableList, func(tableCfg *ModuleCfg) (*ModuleTplAnalysisRes, error) {
+		return AnalysisModuleDDL(sqlText, tableCfg)
+	})
+}
+
+func verifyModules(cfg *ModuleCfg, tableList []string, analysisFunc func(tableCfg *ModuleCfg) (*ModuleTplAnalysisRes, error)) (*VerifyRes, error) {
+	tableCfgs, matchErr := batchTableCfgs(cfg, tableList)
+	if matchErr != nil {
+		return nil, matchErr
+	}
+	res := &VerifyRes{}
+	for _, tableCfg := range tableCfgs {
+		// 只读取模板确定模型文件位置，未设置模板时使用内置模板
+		if tableCfg.TplDir == "" && tableCfg.TplFS == nil {
+			tableCfg.TplFS = DefaultModuleTplFS()
+		}
+		tableRes, verifyErr := verifyModule(tableCfg, analysisFunc)
+		if verifyErr != nil {
+			tableRes.Error = verifyErr.Error()
+		}
+		res.Tables = append(res.Tables, *tableRes)
+	}
+	return res, nil
+}
+
+func verifyModule(cfg *ModuleCfg, analysisFunc func(tableCfg *ModuleCfg) (*ModuleTplAnalysisRes, error)) (*TableVerifyRes, error) {
+	res := &TableVerifyRes{TableName: cfg.TableName, Drifts: []Drift{}}
+	analysisRes, analysisErr := analysisFunc(cfg)
+	if analysisErr != nil {
+		return res, analysisErr
+	}
+	res.StructName = analysisRes.StructName
+	var modelItem *ModuleTplAnalysisItem
+	for i, item := range analysisRes.TplAnalysisList {
+		if item.OriginLayerName == LayerNameModel {
+			modelItem = &analysisRes.TplAnalysisList[i]
+			break
+		}
+	}
+	if modelItem == nil {
+		return res, fmt.Errorf("model template not found")
+	}
+	res.ModelFile = filepath.Join(modelItem.TargetDir, modelItem.TargetFilename)
+	params, buildErr := buildModuleTplParams(cfg, analysisRes)
+	if buildErr != nil {
+		return res, buildErr
+	}
+
+	if !modelItem.TargetFileExist {
+		res.Drifts = append(res.Drifts, Drift{Kind: DriftModelMissing})
+		return res, nil
+	}
+	modelFieldList, found, parseErr := parseModelStruct(res.ModelFile, params.StructName)
+	if parseErr != nil {
+		return res, parseErr
+	}
+	if !found {
+		res.Drifts = append(res.Drifts, Drift{Kind: DriftModelMissing})
+		return res, nil
+	}
+	modelFields := make(map[string]modelStructField, len(modelFieldList))
+	for _, field := range modelFieldList {
+		modelFields[field.columnName] = field
+	}
+
+	schemaColumns := make(map[string]bool, len(params.ModelFields))
+	for _, field := range params.ModelFields {
+		schemaColumns[field.ColumnName] = true
+		modelField, ok := modelFields[field.ColumnName]
+		switch {
+		case !ok:
+			res.Drifts = append(res.Drifts, Drift{Kind: DriftColumnAdded, Column: field.ColumnName, Field: field.FieldName, SchemaType: field.ModelType})
+		case normalizeTypeExpr(modelField.fieldType) != normalizeTypeExpr(field.ModelType):
+			res.Drifts = append(res.Drifts, Drift{
+				Kind:       DriftTypeChanged,
+				Column:     field.ColumnName,
+				Field:      modelField.fieldName,
+				ModelType:  modelField.fieldType,
+				SchemaType: field.ModelType,
+			})
+		}
+	}
+	// 已删除的列排在最后，按模型中的字段顺序排列
+	for _, modelField := range modelFieldList {
+		if !schemaColumns[modelField.columnName] {
+			res.Drifts = append(res.Drifts, Drift{Kind: DriftColumnDropped, Column: modelField.columnName, Field: modelField.fieldName, ModelType: modelField.fieldType})
+		}
+	}
+	return res, nil
+}
+
+// modelStructField 模型结构体中与列对应的字段
+type modelStructField struct {
+	fieldName  string
+	fieldType  string
+	columnName string
+}
+
+// gormModelFields 嵌入 gorm.Model 时对应的列
+var gormModelFields = []modelStructField{
+	{fieldName: "ID", fieldType: "uint", columnName: "id"},
+	{fieldName: "CreatedAt", fieldType: "time.Time", columnName: columnCreatedAt},
+	{fieldName: "UpdatedAt", fieldType: "time.Time", columnName: columnUpdatedAt},
+	{fieldName: "DeletedAt", fieldType: "gorm.DeletedAt", columnName: columnDeletedAt},
+}
+
+// parseModelStruct 解析模型文件中的结构体，按字段顺序返回与列对应的字段，结构体不存在时 found 为 false。
+// gorm 标签为 - 或 -:all 的字段以及未导出字段不参与比较
+func parseModelStruct(filename, structName string) (fields []modelStructField, found bool, err error) {
+	file, parseErr := parser.ParseFile(token.NewFileSet(), filename, nil, parser.SkipObjectResolution)
+	if parseErr != nil {
+		return nil, false, fmt.Errorf("parse model file %s: %w", filename, parseErr)
+	}
+	var structType *ast.StructType
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Name == structName {
+			structType, _ = spec.Type.(*ast.StructType)
+			return false
+		}
+		return structType == nil
+	})
+	if structType == nil {
+		return nil, false, nil
+	}
+
+	namingStrategy := schema.NamingStrategy{}
+	for _, field := range structType.Fields.List {
+		fieldType := types.ExprString(field.Type)
+		var tag string
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("gorm")
+		}
+		if tag == "-" || tag == "-:all" {
+			continue
+		}
+		if len(field.Names) == 0 {
+			if fieldType == "gorm.Model" {
+				fields = append(fields, gormModelFields...)
+			}
+			continue
+		}
+		column := gormTagColumn(tag)
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			columnName := column
+			if columnName == "" {
+				columnName = namingStrategy.ColumnName("", name.Name)
+			}
+			fields = append(fields, modelStructField{fieldName: name.Name, fieldType: fieldType, columnName: columnName})
+		}
+	}
+	return fields, true, nil
+}
+
+// gormTagColumn 返回 gorm 标签中的列名，如 column:user_id;primaryKey 返回 user_id
+func gormTagColumn(tag string) string {
+	for _, item := range strings.Split(tag, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if ok && strings.EqualFold(key, "column") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// normalizeTypeExpr 统一等价的类型写法，如 interface{} 与 any
+func normalizeTypeExpr(typeExpr string) string {
+	typeExpr = strings.ReplaceAll(typeExpr, " ", "")
+	return strings.ReplaceAll(typeExpr, "interface{}", "any")
+}
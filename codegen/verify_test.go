@@ -0,0 +1,91 @@
+package codegen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyFromDDL(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := &ModuleCfg{
+		CommonConfig: CommonConfig{
+			PackageName: "user",
+			RootDir:     rootDir,
+		},
+		TableName:  "user",
+		ImportPath: "example.com/demo",
+	}
+	_, err := GenerateFromDDL(mysqlTestDDL, cfg)
+	require.Nil(t, err)
+
+	res, err := VerifyFromDDL(mysqlTestDDL, cfg)
+	require.Nil(t, err)
+	require.Len(t, res.Tables, 1)
+	assert.False(t, res.HasDrift(), res.Report())
+	assert.Equal(t, "User", res.Tables[0].StructName)
+	assert.Equal(t, filepath.Join(rootDir, "model", "user.go"), res.Tables[0].ModelFile)
+
+	// age 改为 varchar，删除 amount，新增 email
+	ddl := strings.Replace(mysqlTestDDL, "`age` int(11) DEFAULT NULL,", "`age` varchar(16) DEFAULT NULL,", 1)
+	ddl = strings.Replace(ddl, "`amount` decimal(10,2) NOT NULL DEFAULT '0.00',", "`email` varchar(128) NOT NULL DEFAULT '',", 1)
+	res, err = VerifyFromDDL(ddl, cfg)
+	require.Nil(t, err)
+	assert.True(t, res.HasDrift())
+	assert.Equal(t, []Drift{
+		{Kind: DriftTypeChanged, Column: "age", Field: "Age", ModelType: "*int32", SchemaType: "*string"},
+		{Kind: DriftColumnAdded, Column: "email", Field: "Email", SchemaType: "string"},
+		{Kind: DriftColumnDropped, Column: "amount", Field: "Amount", ModelType: "string"},
+	}, res.Tables[0].Drifts)
+	assert.Contains(t, res.Report(), "user: type_changed: age (Age *int32 -> *string)")
+	assert.Contains(t, res.Report(), "total: 1 tables, 0 failed, 3 drifts")
+
+	data, err := json.Marshal(res)
+	require.Nil(t, err)
+	assert.Contains(t, string(data), `{"kind":"column_added","column":"email","field":"Email","schemaType":"string"}`)
+
+	// 未生成模型的表
+	res, err = VerifyFromDDL(mysqlTestDDL, &ModuleCfg{CommonConfig: CommonConfig{RootDir: rootDir}, TableNames: []string{"order"}})
+	require.Nil(t, err)
+	require.Len(t, res.Tables, 1)
+	assert.Equal(t, []Drift{{Kind: DriftModelMissing}}, res.Tables[0].Drifts)
+}
+
+func TestParseModelStruct(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "user.go")
+	require.Nil(t, os.WriteFile(filename, []byte(`package model
+
+import "gorm.io/gorm"
+
+type User struct {
+	gorm.Model
+	UserID   int64
+	Name     string `+"`gorm:\"column:user_name;size:64\"`"+`
+	Extra    string `+"`gorm:\"-\"`"+`
+	Meta     map[string]interface{}
+	internal string
+}
+`), 0644))
+
+	fields, found, err := parseModelStruct(filename, "User")
+	require.Nil(t, err)
+	require.True(t, found)
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, field.columnName+" "+field.fieldType)
+	}
+	assert.Equal(t, []string{
+		"id uint", "created_at time.Time", "updated_at time.Time", "deleted_at gorm.DeletedAt",
+		"user_id int64", "user_name string", "meta map[string]interface{}",
+	}, columns)
+	assert.Equal(t, normalizeTypeExpr("map[string]any"), normalizeTypeExpr(fields[6].fieldType))
+
+	_, found, err = parseModelStruct(filename, "Role")
+	require.Nil(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,153 @@
+package dbes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NoSubAggs 桶内没有子聚合时作为类型参数使用，如 TermsAgg[NoSubAggs]
+type NoSubAggs = struct{}
+
+// TermsAgg terms 聚合结果，S 为桶内子聚合结构体，字段通过 json 标签对应子聚合名称
+type TermsAgg[S any] struct {
+	DocCountErrorUpperBound int64            `json:"doc_count_error_upper_bound"`
+	SumOtherDocCount        int64            `json:"sum_other_doc_count"`
+	Buckets                 []TermsBucket[S] `json:"buckets"`
+}
+
+// TermsBucket terms 聚合桶
+type TermsBucket[S any] struct {
+	// Key 桶的键，字符串字段为 string，数值字段为 json.Number，避免 long 类型精度丢失
+	Key any `json:"key"`
+	// KeyAsString 数值、日期、布尔字段的格式化键
+	KeyAsString string `json:"key_as_string,omitempty"`
+	DocCount    int64  `json:"doc_count"`
+	// Aggs 桶内子聚合
+	Aggs S `json:"-"`
+}
+
+// KeyString 返回字符串形式的键，优先使用 KeyAsString
+func (b TermsBucket[S]) KeyString() string {
+	if b.KeyAsString != "" {
+		return b.KeyAsString
+	}
+	return fmt.Sprint(b.Key)
+}
+
+func (b *TermsBucket[S]) UnmarshalJSON(data []byte) error {
+	var base struct {
+		Key         any    `json:"key"`
+		KeyAsString string `json:"key_as_string"`
+		DocCount    int64  `json:"doc_count"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&base); err != nil {
+		return err
+	}
+	b.Key, b.KeyAsString, b.DocCount = base.Key, base.KeyAsString, base.DocCount
+	return unmarshalSubAggs(data, &b.Aggs)
+}
+
+// DateHistogramAgg date_histogram 聚合结果，S 为桶内子聚合结构体
+type DateHistogramAgg[S any] struct {
+	Buckets []DateHistogramBucket[S] `json:"buckets"`
+}
+
+// DateHistogramBucket date_histogram 聚合桶
+type DateHistogramBucket[S any] struct {
+	// Key 桶起始时间的毫秒时间戳
+	Key int64 `json:"key"`
+	// KeyAsString 按聚合 format 格式化的桶起始时间
+	KeyAsString string `json:"key_as_string,omitempty"`
+	DocCount    int64  `json:"doc_count"`
+	// Aggs 桶内子聚合
+	Aggs S `json:"-"`
+}
+
+// Time 返回桶起始时间
+func (b DateHistogramBucket[S]) Time() time.Time {
+	return time.UnixMilli(b.Key)
+}
+
+func (b *DateHistogramBucket[S]) UnmarshalJSON(data []byte) error {
+	var base struct {
+		Key         int64  `json:"key"`
+		KeyAsString string `json:"key_as_string"`
+		DocCount    int64  `json:"doc_count"`
+	}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return err
+	}
+	b.Key, b.KeyAsString, b.DocCount = base.Key, base.KeyAsString, base.DocCount
+	return unmarshalSubAggs(data, &b.Aggs)
+}
+
+// CardinalityAgg cardinality 聚合结果，Value 为去重计数的近似值
+type CardinalityAgg struct {
+	Value int64 `json:"value"`
+}
+
+// MetricAgg avg、sum、min、max 等单值指标聚合结果，没有文档参与计算时 Value 为 nil
+type MetricAgg struct {
+	Value         *float64 `json:"value"`
+	ValueAsString string   `json:"value_as_string,omitempty"`
+}
+
+// unmarshalSubAggs 将桶对象解析为子聚合结构体，NoSubAggs 时跳过
+func unmarshalSubAggs[S any](data []byte, sub *S) error {
+	if _, ok := any(sub).(*NoSubAggs); ok {
+		return nil
+	}
+	return json.Unmarshal(data, sub)
+}
+
+// ParseAggs 将搜索响应的 aggregations 部分解析为 T，T 的字段通过 json 标签对应聚合名称，如：
+//
+//	type StatusAggs struct {
+//		ByStatus dbes.TermsAgg[struct {
+//			Users dbes.CardinalityAgg `json:"users"`
+//		}] `json:"by_status"`
+//	}
+//
+// aggs 可以是 []byte、json.RawMessage，或已解码的值，如 map[string]any、TypedClient 响应的 Aggregations
+func ParseAggs[T any](aggs any) (T, error) {
+	var result T
+	var data []byte
+	switch v := aggs.(type) {
+	case nil:
+		return result, errors.New("aggregations is empty")
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return result, fmt.Errorf("failed to marshal aggregations: %w", err)
+		}
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("failed to parse aggregations: %w", err)
+	}
+	return result, nil
+}
+
+// ParseResponseAggs 从完整的搜索响应体中解析 aggregations 部分，响应中没有聚合时返回错误
+func ParseResponseAggs[T any](body []byte) (T, error) {
+	var resp struct {
+		Aggregations json.RawMessage `json:"aggregations"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		var result T
+		return result, fmt.Errorf("failed to parse search response: %w", err)
+	}
+	if len(resp.Aggregations) == 0 || string(resp.Aggregations) == "null" {
+		var result T
+		return result, errors.New("aggregations not found in search response")
+	}
+	return ParseAggs[T](resp.Aggregations)
+}
@@ -0,0 +1,91 @@
+package dbes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const aggsSearchResponse = `{
+	"took": 3,
+	"hits": {"total": {"value": 3, "relation": "eq"}, "hits": []},
+	"aggregations": {
+		"by_status": {
+			"doc_count_error_upper_bound": 0,
+			"sum_other_doc_count": 1,
+			"buckets": [
+				{"key": "active", "doc_count": 2, "users": {"value": 2}, "avg_age": {"value": 30.5}},
+				{"key": "disabled", "doc_count": 1, "users": {"value": 1}, "avg_age": {"value": null}}
+			]
+		},
+		"by_org": {
+			"buckets": [
+				{"key": 9007199254740993, "doc_count": 3}
+			]
+		},
+		"per_day": {
+			"buckets": [
+				{"key_as_string": "2024-01-01", "key": 1704067200000, "doc_count": 2, "users": {"value": 2}}
+			]
+		},
+		"total_users": {"value": 3}
+	}
+}`
+
+type testStatusSubAggs struct {
+	Users  CardinalityAgg `json:"users"`
+	AvgAge MetricAgg      `json:"avg_age"`
+}
+
+type testAggs struct {
+	ByStatus TermsAgg[testStatusSubAggs] `json:"by_status"`
+	ByOrg    TermsAgg[NoSubAggs]         `json:"by_org"`
+	PerDay   DateHistogramAgg[struct {
+		Users CardinalityAgg `json:"users"`
+	}] `json:"per_day"`
+	TotalUsers CardinalityAgg `json:"total_users"`
+}
+
+func TestParseResponseAggs(t *testing.T) {
+	aggs, err := ParseResponseAggs[testAggs]([]byte(aggsSearchResponse))
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(1), aggs.ByStatus.SumOtherDocCount)
+	assert.Len(t, aggs.ByStatus.Buckets, 2)
+	active := aggs.ByStatus.Buckets[0]
+	assert.Equal(t, "active", active.KeyString())
+	assert.Equal(t, int64(2), active.DocCount)
+	assert.Equal(t, int64(2), active.Aggs.Users.Value)
+	assert.Equal(t, 30.5, *active.Aggs.AvgAge.Value)
+	assert.Nil(t, aggs.ByStatus.Buckets[1].Aggs.AvgAge.Value)
+
+	// 数值键保留 long 精度
+	assert.Equal(t, json.Number("9007199254740993"), aggs.ByOrg.Buckets[0].Key)
+	assert.Equal(t, "9007199254740993", aggs.ByOrg.Buckets[0].KeyString())
+
+	day := aggs.PerDay.Buckets[0]
+	assert.Equal(t, "2024-01-01", day.KeyAsString)
+	assert.True(t, day.Time().Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int64(2), day.Aggs.Users.Value)
+
+	assert.Equal(t, int64(3), aggs.TotalUsers.Value)
+}
+
+func TestParseAggs(t *testing.T) {
+	var resp map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(aggsSearchResponse), &resp))
+
+	aggs, err := ParseAggs[testAggs](resp["aggregations"])
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), aggs.TotalUsers.Value)
+	assert.Len(t, aggs.ByStatus.Buckets, 2)
+
+	_, err = ParseAggs[testAggs](nil)
+	assert.NotNil(t, err)
+	_, err = ParseAggs[testAggs]([]byte(`{"by_status": {"buckets": "bad"}}`))
+	assert.NotNil(t, err)
+	_, err = ParseResponseAggs[testAggs]([]byte(`{"hits": {"hits": []}}`))
+	assert.NotNil(t, err)
+}
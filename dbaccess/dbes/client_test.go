@@ -7,6 +7,7 @@ import (
 
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/gutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,8 +41,8 @@ func TestNewTypedES(t *testing.T) {
 			MatchAll: types.NewMatchAllQuery(),
 		}).Do(ctx)
 	assert.Nil(t, searchErr)
-	glog.Infof(ctx, "search result: %s", glog.ToJsonString(res))
-	t.Log(glog.ToJsonString(res))
+	glog.Infof(ctx, "search result: %s", gutil.ToJsonString(res))
+	t.Log(gutil.ToJsonString(res))
 }
 
 func TestNewSimpleES(t *testing.T) {
@@ -73,6 +74,6 @@ func TestNewSimpleES(t *testing.T) {
 		simpleClient.Search.WithBody(strings.NewReader(`{"query":{"match_all":{}}}`)),
 	)
 	assert.Nil(t, searchErr)
-	glog.Infof(ctx, "search result: %s", glog.ToJsonString(res))
-	t.Log(glog.ToJsonString(res))
+	glog.Infof(ctx, "search result: %s", gutil.ToJsonString(res))
+	t.Log(gutil.ToJsonString(res))
 }
@@ -0,0 +1,213 @@
+package dbes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/morehao/golib/glog"
+)
+
+// ParamType DSL 模板参数类型
+type ParamType int
+
+const (
+	ParamAny     ParamType = iota // 不校验类型
+	ParamString                   // 字符串
+	ParamInt                      // 整数
+	ParamFloat                    // 浮点数，整数也可通过校验
+	ParamBool                     // 布尔值
+	ParamStrings                  // 字符串切片
+	ParamList                     // 任意切片
+	ParamMap                      // map 或结构体
+)
+
+func (t ParamType) String() string {
+	switch t {
+	case ParamAny:
+		return "any"
+	case ParamString:
+		return "string"
+	case ParamInt:
+		return "int"
+	case ParamFloat:
+		return "float"
+	case ParamBool:
+		return "bool"
+	case ParamStrings:
+		return "[]string"
+	case ParamList:
+		return "list"
+	case ParamMap:
+		return "map"
+	default:
+		return "unknown"
+	}
+}
+
+// ParamSpec DSL 模板参数定义
+type ParamSpec struct {
+	Name     string    // 参数名，模板中通过 .Name 引用
+	Type     ParamType // 参数类型
+	Required bool      // 是否必填
+	Default  any       // 未传入时的默认值
+}
+
+// TemplateRegistry DSL 模板注册表，模板基于 text/template 渲染。
+// 参数需通过 json 函数输出，例如 {"size": {{ json .Size }}}，
+// 值会被 JSON 编码，避免字符串参数破坏 DSL 结构；渲染结果会校验是否为合法 JSON。
+type TemplateRegistry struct {
+	mu        sync.RWMutex
+	templates map[string]*dslTemplate
+}
+
+type dslTemplate struct {
+	tmpl   *template.Template
+	params []ParamSpec
+}
+
+// NewTemplateRegistry 创建 DSL 模板注册表
+func NewTemplateRegistry() *TemplateRegistry {
+	return &TemplateRegistry{
+		templates: make(map[string]*dslTemplate),
+	}
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	},
+}
+
+// Register 注册 DSL 模板，同名模板会被覆盖
+func (r *TemplateRegistry) Register(name, text string, params ...ParamSpec) error {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parse dsl template %s: %w", name, err)
+	}
+	r.mu.Lock()
+	r.templates[name] = &dslTemplate{tmpl: tmpl, params: params}
+	r.mu.Unlock()
+	return nil
+}
+
+// RegisterFS 从文件系统加载匹配 pattern 的模板文件，模板名为去掉扩展名的文件名
+// params 按模板名指定参数定义，未指定的模板不做参数校验
+func (r *TemplateRegistry) RegisterFS(fsys fs.FS, pattern string, params map[string][]ParamSpec) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		base := path.Base(file)
+		name := strings.TrimSuffix(base, path.Ext(base))
+		if err := r.Register(name, string(data), params[name]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render 校验参数并渲染 DSL 模板，返回 JSON 字节
+func (r *TemplateRegistry) Render(ctx context.Context, name string, params Map) ([]byte, error) {
+	r.mu.RLock()
+	t, ok := r.templates[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("dsl template %s not found", name)
+	}
+
+	data, err := bindParams(t.params, params)
+	if err != nil {
+		return nil, fmt.Errorf("dsl template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("render dsl template %s: %w", name, err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("dsl template %s rendered invalid json: %s", name, buf.String())
+	}
+
+	glog.Debugw(ctx, "es dsl template rendered",
+		glog.KeyNetworkProtocolName, glog.ValueNetworkProtoElasticsearch,
+		glog.KeyDbOperation, name,
+		glog.KeyDbStatement, buf.String(),
+	)
+	return buf.Bytes(), nil
+}
+
+// RenderReader 渲染 DSL 模板并返回 io.Reader，可直接作为 ES 请求体
+func (r *TemplateRegistry) RenderReader(ctx context.Context, name string, params Map) (io.Reader, error) {
+	data, err := r.Render(ctx, name, params)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// bindParams 按参数定义填充默认值并校验类型，未定义参数时原样返回
+func bindParams(specs []ParamSpec, params Map) (Map, error) {
+	data := make(Map, len(params)+len(specs))
+	for k, v := range params {
+		data[k] = v
+	}
+	for _, spec := range specs {
+		v, ok := data[spec.Name]
+		if !ok || v == nil {
+			if spec.Default != nil {
+				data[spec.Name] = spec.Default
+				continue
+			}
+			if spec.Required {
+				return nil, fmt.Errorf("param %s is required", spec.Name)
+			}
+			data[spec.Name] = nil
+			continue
+		}
+		if !matchParamType(spec.Type, v) {
+			return nil, fmt.Errorf("param %s must be %s, got %T", spec.Name, spec.Type, v)
+		}
+	}
+	return data, nil
+}
+
+func matchParamType(t ParamType, v any) bool {
+	rv := reflect.ValueOf(v)
+	kind := rv.Kind()
+	switch t {
+	case ParamString:
+		return kind == reflect.String
+	case ParamInt:
+		return kind >= reflect.Int && kind <= reflect.Uint64
+	case ParamFloat:
+		return (kind >= reflect.Int && kind <= reflect.Uint64) || kind == reflect.Float32 || kind == reflect.Float64
+	case ParamBool:
+		return kind == reflect.Bool
+	case ParamStrings:
+		return (kind == reflect.Slice || kind == reflect.Array) && rv.Type().Elem().Kind() == reflect.String
+	case ParamList:
+		return kind == reflect.Slice || kind == reflect.Array
+	case ParamMap:
+		return kind == reflect.Map || kind == reflect.Struct
+	default:
+		return true
+	}
+}
@@ -0,0 +1,77 @@
+package dbes
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const searchByCityTemplate = `{
+	"query": {
+		"bool": {
+			"filter": [
+				{"term": {"city": {{ json .City }}}}
+				{{- if .Tags }},
+				{"terms": {"tags": {{ json .Tags }}}}
+				{{- end }}
+			]
+		}
+	},
+	"size": {{ json .Size }}
+}`
+
+func TestTemplateRegistry_Render(t *testing.T) {
+	registry := NewTemplateRegistry()
+	err := registry.Register("search_by_city", searchByCityTemplate,
+		ParamSpec{Name: "City", Type: ParamString, Required: true},
+		ParamSpec{Name: "Tags", Type: ParamStrings},
+		ParamSpec{Name: "Size", Type: ParamInt, Default: 10},
+	)
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	data, err := registry.Render(ctx, "search_by_city", Map{
+		"City": `Shanghai"}}`,
+		"Tags": []string{"a", "b"},
+	})
+	assert.Nil(t, err)
+
+	var body Map
+	assert.Nil(t, json.Unmarshal(data, &body))
+	assert.Equal(t, float64(10), body["size"])
+	filter := body["query"].(Map)["bool"].(Map)["filter"].([]any)
+	assert.Len(t, filter, 2)
+	assert.Equal(t, `Shanghai"}}`, filter[0].(Map)["term"].(Map)["city"])
+
+	_, err = registry.Render(ctx, "search_by_city", Map{"Size": 10})
+	assert.ErrorContains(t, err, "param City is required")
+
+	_, err = registry.Render(ctx, "search_by_city", Map{"City": "Beijing", "Size": "10"})
+	assert.ErrorContains(t, err, "param Size must be int")
+
+	_, err = registry.Render(ctx, "missing", nil)
+	assert.NotNil(t, err)
+}
+
+func TestTemplateRegistry_RegisterFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dsl/count_by_status.tmpl": {Data: []byte(`{"query": {"term": {"status": {{ .Status }}}}}`)},
+	}
+	registry := NewTemplateRegistry()
+	err := registry.RegisterFS(fsys, "dsl/*.tmpl", map[string][]ParamSpec{
+		"count_by_status": {{Name: "Status", Type: ParamInt, Required: true}},
+	})
+	assert.Nil(t, err)
+
+	data, err := registry.Render(context.Background(), "count_by_status", Map{"Status": 1})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"query": {"term": {"status": 1}}}`, string(data))
+
+	// 未使用 json 函数输出字符串时渲染结果不是合法 JSON
+	assert.Nil(t, registry.Register("raw", `{"query": {"term": {"status": {{ .Status }}}}}`))
+	_, err = registry.Render(context.Background(), "raw", Map{"Status": "x"})
+	assert.ErrorContains(t, err, "invalid json")
+}
@@ -0,0 +1,174 @@
+package dbgorm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultMigrationTable = "schema_migrations"
+
+// Migration 描述一次版本化的表结构变更，Version 按字典序执行，建议使用 20060102150405 格式
+type Migration struct {
+	Version string                  // 版本号
+	Name    string                  // 迁移名称，如 create_user
+	Up      func(tx *gorm.DB) error // 升级
+	Down    func(tx *gorm.DB) error // 回滚
+}
+
+type migrationRecord struct {
+	Version   string    `gorm:"column:version;primaryKey;size:64"`
+	Name      string    `gorm:"column:name;size:255"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []*Migration
+)
+
+// RegisterMigration 注册迁移，供生成的迁移文件在 init 中调用
+func RegisterMigration(migrations ...*Migration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, migrations...)
+}
+
+// RegisteredMigrations 返回已注册的迁移
+func RegisteredMigrations() []*Migration {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]*Migration(nil), registry...)
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	tableName  string
+	migrations []*Migration
+}
+
+type MigratorOption func(*Migrator)
+
+// WithMigrationTable 指定记录迁移版本的表名，默认 schema_migrations
+func WithMigrationTable(tableName string) MigratorOption {
+	return func(m *Migrator) {
+		m.tableName = tableName
+	}
+}
+
+func NewMigrator(db *gorm.DB, migrations []*Migration, opts ...MigratorOption) (*Migrator, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	m := &Migrator{
+		db:        db,
+		tableName: defaultMigrationTable,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	versionSet := make(map[string]struct{}, len(migrations))
+	for _, v := range migrations {
+		if v == nil || v.Version == "" {
+			return nil, fmt.Errorf("migration version is required")
+		}
+		if v.Up == nil {
+			return nil, fmt.Errorf("migration %s up is required", v.Version)
+		}
+		if _, ok := versionSet[v.Version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %s", v.Version)
+		}
+		versionSet[v.Version] = struct{}{}
+		m.migrations = append(m.migrations, v)
+	}
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	return m, nil
+}
+
+// Up 按版本顺序执行所有未执行的迁移，每个迁移在独立事务中执行
+func (m *Migrator) Up(ctx context.Context) error {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	for _, v := range pending {
+		if err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := v.Up(tx); err != nil {
+				return err
+			}
+			return tx.Table(m.tableName).Create(&migrationRecord{
+				Version:   v.Version,
+				Name:      v.Name,
+				AppliedAt: time.Now(),
+			}).Error
+		}); err != nil {
+			return fmt.Errorf("migrate up %s failed: %w", v.Version, err)
+		}
+	}
+	return nil
+}
+
+// Down 回滚最近一次执行的迁移，没有已执行的迁移时直接返回
+func (m *Migrator) Down(ctx context.Context) error {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		v := m.migrations[i]
+		if _, ok := applied[v.Version]; !ok {
+			continue
+		}
+		if v.Down == nil {
+			return fmt.Errorf("migration %s down is required", v.Version)
+		}
+		if err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := v.Down(tx); err != nil {
+				return err
+			}
+			return tx.Table(m.tableName).Where("version = ?", v.Version).Delete(&migrationRecord{}).Error
+		}); err != nil {
+			return fmt.Errorf("migrate down %s failed: %w", v.Version, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// Pending 返回尚未执行的迁移
+func (m *Migrator) Pending(ctx context.Context) ([]*Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Migration
+	for _, v := range m.migrations {
+		if _, ok := applied[v.Version]; !ok {
+			pending = append(pending, v)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[string]struct{}, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.Table(m.tableName).AutoMigrate(&migrationRecord{}); err != nil {
+		return nil, fmt.Errorf("create migration table failed: %w", err)
+	}
+	var records []migrationRecord
+	if err := db.Table(m.tableName).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query migration table failed: %w", err)
+	}
+	applied := make(map[string]struct{}, len(records))
+	for _, v := range records {
+		applied[v.Version] = struct{}{}
+	}
+	return applied, nil
+}
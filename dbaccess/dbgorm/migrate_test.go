@@ -0,0 +1,75 @@
+package dbgorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMigrator(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	migrations := []*Migration{
+		{
+			Version: "20260102000000",
+			Name:    "create_order",
+			Up: func(tx *gorm.DB) error {
+				return tx.Exec("CREATE TABLE `order` (id INTEGER PRIMARY KEY)").Error
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("order")
+			},
+		},
+		{
+			Version: "20260101000000",
+			Name:    "create_user",
+			Up: func(tx *gorm.DB) error {
+				return tx.Exec("CREATE TABLE user (id INTEGER PRIMARY KEY)").Error
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("user")
+			},
+		},
+	}
+	m, err := NewMigrator(db, migrations)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	pending, err := m.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "20260101000000", pending[0].Version)
+
+	require.NoError(t, m.Up(ctx))
+	assert.True(t, db.Migrator().HasTable("user"))
+	assert.True(t, db.Migrator().HasTable("order"))
+
+	// 重复执行不会重复迁移
+	require.NoError(t, m.Up(ctx))
+
+	require.NoError(t, m.Down(ctx))
+	assert.False(t, db.Migrator().HasTable("order"))
+	assert.True(t, db.Migrator().HasTable("user"))
+
+	pending, err = m.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "20260102000000", pending[0].Version)
+}
+
+func TestNewMigratorDuplicateVersion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	up := func(tx *gorm.DB) error { return nil }
+	_, err = NewMigrator(db, []*Migration{
+		{Version: "1", Up: up},
+		{Version: "1", Up: up},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,175 @@
+package dbgorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/gerror"
+	"gorm.io/gorm"
+)
+
+// DBGetter 按请求上下文获取 db 实例
+type DBGetter func(ctx context.Context) *gorm.DB
+
+// Scope 查询条件，与 gorm 的 Scopes 签名一致
+type Scope func(db *gorm.DB) *gorm.DB
+
+// PageQuery 分页查询参数，Page 从 1 开始，Page 或 PageSize <= 0 时不分页
+type PageQuery struct {
+	Page        int
+	PageSize    int
+	OrderBy     string // 排序语句，如 "id DESC"
+	WithDeleted bool   // 是否包含已软删除的记录
+}
+
+// Repo 泛型仓储，封装单表常用的增删改查。
+// 查询通过 WithContext 传递 ctx，SQL 日志由 dbgorm 的 ormLogger 按请求上下文记录；
+// 错误统一包装为 gconstant 中的 DB 错误码，可通过 gerror.GetCode 获取或 gerror.IsCode 判断。
+// 实体包含 gorm.DeletedAt 字段时，查询自动过滤已软删除的记录
+type Repo[T any] struct {
+	getDB DBGetter
+	tx    *gorm.DB
+	name  string
+}
+
+// NewRepo 创建泛型仓储
+func NewRepo[T any](getDB DBGetter) *Repo[T] {
+	return &Repo[T]{
+		getDB: getDB,
+		name:  reflect.TypeOf((*T)(nil)).Elem().Name(),
+	}
+}
+
+// WithTx 返回使用指定事务的仓储副本
+func (r *Repo[T]) WithTx(tx *gorm.DB) *Repo[T] {
+	return &Repo[T]{getDB: r.getDB, tx: tx, name: r.name}
+}
+
+// DB 返回绑定 ctx 的 db 实例，事务优先
+func (r *Repo[T]) DB(ctx context.Context) *gorm.DB {
+	if r.tx != nil {
+		return r.tx.WithContext(ctx)
+	}
+	return r.getDB(ctx).WithContext(ctx)
+}
+
+// Create 插入一条记录
+func (r *Repo[T]) Create(ctx context.Context, entity *T) error {
+	if err := r.DB(ctx).Create(entity).Error; err != nil {
+		return dbError(gconstant.DBInsertErr).Wrapf(err, "[%s] Create fail", r.name)
+	}
+	return nil
+}
+
+// GetByID 按主键查询，记录不存在时返回 nil, nil
+func (r *Repo[T]) GetByID(ctx context.Context, id any) (*T, error) {
+	var entities []T
+	if err := r.DB(ctx).Where(r.pkCondition(ctx), id).Limit(1).Find(&entities).Error; err != nil {
+		return nil, dbError(gconstant.DBFindErr).Wrapf(err, "[%s] GetByID fail, id:%v", r.name, id)
+	}
+	if len(entities) == 0 {
+		return nil, nil
+	}
+	return &entities[0], nil
+}
+
+// List 按条件分页查询，返回当前页记录和满足条件的总数
+func (r *Repo[T]) List(ctx context.Context, query PageQuery, scopes ...Scope) ([]T, int64, error) {
+	db := r.DB(ctx).Model(new(T)).Scopes(toGormScopes(scopes)...)
+	if query.WithDeleted {
+		db = db.Unscoped()
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, dbError(gconstant.DBFindErr).Wrapf(err, "[%s] List count fail", r.name)
+	}
+	if total == 0 {
+		return []T{}, 0, nil
+	}
+
+	if query.OrderBy != "" {
+		db = db.Order(query.OrderBy)
+	}
+	if query.Page > 0 && query.PageSize > 0 {
+		db = db.Offset((query.Page - 1) * query.PageSize).Limit(query.PageSize)
+	}
+	var entities []T
+	if err := db.Find(&entities).Error; err != nil {
+		return nil, 0, dbError(gconstant.DBFindErr).Wrapf(err, "[%s] List find fail, page:%d, pageSize:%d", r.name, query.Page, query.PageSize)
+	}
+	return entities, total, nil
+}
+
+// UpdateFields 按主键更新指定字段，fields 的 key 为列名
+func (r *Repo[T]) UpdateFields(ctx context.Context, id any, fields map[string]any) error {
+	if len(fields) == 0 {
+		return dbError(gconstant.DBUpdateErr).New(fmt.Sprintf("[%s] UpdateFields fail, fields is empty", r.name))
+	}
+	if err := r.DB(ctx).Model(new(T)).Where(r.pkCondition(ctx), id).Updates(fields).Error; err != nil {
+		return dbError(gconstant.DBUpdateErr).Wrapf(err, "[%s] UpdateFields fail, id:%v", r.name, id)
+	}
+	return nil
+}
+
+// SoftDelete 按主键软删除，实体必须包含 gorm.DeletedAt 字段，避免误用为物理删除
+func (r *Repo[T]) SoftDelete(ctx context.Context, id any) error {
+	db := r.DB(ctx)
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return dbError(gconstant.DBDeleteErr).Wrapf(err, "[%s] SoftDelete parse model fail", r.name)
+	}
+	if !hasSoftDeleteField(stmt) {
+		return dbError(gconstant.DBDeleteErr).New(fmt.Sprintf("[%s] SoftDelete fail, model has no gorm.DeletedAt field", r.name))
+	}
+	if err := db.Where(r.pkCondition(ctx), id).Delete(new(T)).Error; err != nil {
+		return dbError(gconstant.DBDeleteErr).Wrapf(err, "[%s] SoftDelete fail, id:%v", r.name, id)
+	}
+	return nil
+}
+
+// Exists 判断是否存在满足条件的记录，已软删除的记录不计入
+func (r *Repo[T]) Exists(ctx context.Context, scopes ...Scope) (bool, error) {
+	var found []map[string]any
+	db := r.DB(ctx).Model(new(T)).Scopes(toGormScopes(scopes)...)
+	if err := db.Select("1 AS found").Limit(1).Find(&found).Error; err != nil {
+		return false, dbError(gconstant.DBFindErr).Wrapf(err, "[%s] Exists fail", r.name)
+	}
+	return len(found) > 0, nil
+}
+
+// pkCondition 返回主键查询条件，解析失败时回退到 id 列
+func (r *Repo[T]) pkCondition(ctx context.Context) string {
+	stmt := &gorm.Statement{DB: r.DB(ctx)}
+	if err := stmt.Parse(new(T)); err == nil && stmt.Schema.PrioritizedPrimaryField != nil {
+		return fmt.Sprintf("%s = ?", stmt.Quote(stmt.Schema.PrioritizedPrimaryField.DBName))
+	}
+	return "id = ?"
+}
+
+func hasSoftDeleteField(stmt *gorm.Statement) bool {
+	deletedAtType := reflect.TypeOf(gorm.DeletedAt{})
+	for _, field := range stmt.Schema.Fields {
+		if field.FieldType == deletedAtType {
+			return true
+		}
+	}
+	return false
+}
+
+func toGormScopes(scopes []Scope) []func(*gorm.DB) *gorm.DB {
+	gormScopes := make([]func(*gorm.DB) *gorm.DB, 0, len(scopes))
+	for _, scope := range scopes {
+		gormScopes = append(gormScopes, scope)
+	}
+	return gormScopes
+}
+
+func dbError(code int) gerror.Error {
+	return gerror.Error{
+		Code: code,
+		Msg:  gconstant.DBErrorMsgMap[code],
+	}
+}
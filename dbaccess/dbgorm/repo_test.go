@@ -0,0 +1,84 @@
+package dbgorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/gerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type repoUser struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	Age       int
+	DeletedAt gorm.DeletedAt
+}
+
+type repoLog struct {
+	ID      uint `gorm:"primaryKey"`
+	Content string
+}
+
+func TestRepo(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&repoUser{}, &repoLog{}))
+
+	ctx := context.Background()
+	repo := NewRepo[repoUser](func(ctx context.Context) *gorm.DB { return db })
+	for _, name := range []string{"alice", "bob", "carol"} {
+		require.NoError(t, repo.Create(ctx, &repoUser{Name: name, Age: 20}))
+	}
+
+	user, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.Name)
+	user, err = repo.GetByID(ctx, 100)
+	require.NoError(t, err)
+	assert.Nil(t, user)
+
+	require.NoError(t, repo.UpdateFields(ctx, 2, map[string]any{"age": 30}))
+	user, err = repo.GetByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 30, user.Age)
+	assert.True(t, gerror.IsCode(repo.UpdateFields(ctx, 2, nil), gconstant.DBUpdateErr))
+
+	require.NoError(t, repo.SoftDelete(ctx, 1))
+	user, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, user)
+
+	users, total, err := repo.List(ctx, PageQuery{Page: 1, PageSize: 1, OrderBy: "id DESC"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "carol", users[0].Name)
+	_, total, err = repo.List(ctx, PageQuery{WithDeleted: true})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, total)
+
+	byName := func(name string) Scope {
+		return func(db *gorm.DB) *gorm.DB { return db.Where("name = ?", name) }
+	}
+	exists, err := repo.Exists(ctx, byName("bob"))
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = repo.Exists(ctx, byName("alice"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// 无 DeletedAt 字段的实体不允许软删除
+	logRepo := NewRepo[repoLog](func(ctx context.Context) *gorm.DB { return db })
+	require.NoError(t, logRepo.Create(ctx, &repoLog{Content: "x"}))
+	assert.Equal(t, gconstant.DBDeleteErr, gerror.GetCode(logRepo.SoftDelete(ctx, 1)))
+	exists, err = logRepo.Exists(ctx)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.Equal(t, gconstant.DBInsertErr, gerror.GetCode(repo.Create(ctx, &repoUser{ID: 2, Name: "dup"})))
+}
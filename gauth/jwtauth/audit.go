@@ -0,0 +1,93 @@
+package jwtauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/morehao/golib/glog"
+)
+
+// AuditAction 审计事件对应的操作
+type AuditAction string
+
+const (
+	AuditActionIssue AuditAction = "issue"
+	AuditActionParse AuditAction = "parse"
+)
+
+// AuditEvent 一次签发或解析 token 的审计信息
+type AuditEvent struct {
+	Action   AuditAction
+	Subject  string
+	Issuer   string
+	ID       string // jti
+	SourceIP string // 来源 IP，从 ctx 中获取，见 WithSourceIP
+	Success  bool
+	Err      error
+	Time     time.Time
+}
+
+// AuditHook 审计钩子，在签发和解析 token 后同步调用，实现中不应执行耗时操作
+type AuditHook func(ctx context.Context, event *AuditEvent)
+
+type sourceIPKey struct{}
+
+// WithSourceIP 将来源 IP 写入 ctx，供审计钩子读取
+func WithSourceIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, sourceIPKey{}, ip)
+}
+
+// SourceIPFromContext 从 ctx 中获取来源 IP。
+// 依次尝试 WithSourceIP 写入的值、glog.KeyClientAddress 对应的值，
+// 以及 ctx 自身实现的 ClientIP() 方法（如 *gin.Context）。
+func SourceIPFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if ip, ok := ctx.Value(sourceIPKey{}).(string); ok && ip != "" {
+		return ip
+	}
+	if ip, ok := ctx.Value(glog.KeyClientAddress).(string); ok && ip != "" {
+		return ip
+	}
+	if c, ok := ctx.(interface{ ClientIP() string }); ok {
+		return c.ClientIP()
+	}
+	return ""
+}
+
+// GlogAuditHook 将审计事件写入 glog，成功为 Info 级别，失败为 Warn 级别
+func GlogAuditHook(ctx context.Context, event *AuditEvent) {
+	kvs := []any{
+		"jwt.action", string(event.Action),
+		"jwt.subject", event.Subject,
+		"jwt.issuer", event.Issuer,
+		"jwt.id", event.ID,
+		glog.KeyClientAddress, event.SourceIP,
+	}
+	if event.Success {
+		glog.Infow(ctx, "jwt audit", kvs...)
+		return
+	}
+	kvs = append(kvs, glog.KeyAppErrorMessage, event.Err.Error())
+	glog.Warnw(ctx, "jwt audit", kvs...)
+}
+
+func (a *Auth[T]) audit(ctx context.Context, action AuditAction, subject, issuer, id string, err error) {
+	if len(a.auditHooks) == 0 {
+		return
+	}
+	event := &AuditEvent{
+		Action:   action,
+		Subject:  subject,
+		Issuer:   issuer,
+		ID:       id,
+		SourceIP: SourceIPFromContext(ctx),
+		Success:  err == nil,
+		Err:      err,
+		Time:     time.Now(),
+	}
+	for _, hook := range a.auditHooks {
+		hook(ctx, event)
+	}
+}
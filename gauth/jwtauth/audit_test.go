@@ -0,0 +1,58 @@
+package jwtauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/glog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHook(t *testing.T) {
+	type CustomData struct {
+		Role string `json:"role"`
+	}
+
+	var events []AuditEvent
+	auth, err := New[CustomData]("secret", WithAuditHook[CustomData](func(_ context.Context, event *AuditEvent) {
+		events = append(events, *event)
+	}))
+	require.NoError(t, err)
+
+	ctx := WithSourceIP(context.Background(), "10.0.0.1")
+	token, err := auth.IssueContext(ctx, "user123", "example.com", time.Now().Add(time.Hour), CustomData{Role: "admin"},
+		WithID[CustomData]("jti-1"))
+	require.NoError(t, err)
+
+	_, err = auth.ParseContext(ctx, token)
+	require.NoError(t, err)
+
+	_, err = auth.Issue("", "example.com", time.Now().Add(time.Hour), CustomData{})
+	assert.True(t, errors.Is(err, ErrEmptySubject))
+
+	otherAuth, err := New[CustomData]("other")
+	require.NoError(t, err)
+	otherToken, err := otherAuth.Issue("user456", "example.com", time.Now().Add(time.Hour), CustomData{})
+	require.NoError(t, err)
+	_, err = auth.Parse(otherToken)
+	assert.Error(t, err)
+
+	require.Len(t, events, 4)
+	assert.Equal(t, AuditEvent{Action: AuditActionIssue, Subject: "user123", Issuer: "example.com", ID: "jti-1", SourceIP: "10.0.0.1", Success: true, Time: events[0].Time}, events[0])
+	assert.Equal(t, AuditActionParse, events[1].Action)
+	assert.Equal(t, "jti-1", events[1].ID)
+	assert.True(t, events[1].Success)
+	assert.False(t, events[2].Success)
+	assert.Equal(t, "", events[2].SourceIP)
+	assert.False(t, events[3].Success)
+	assert.Equal(t, "user456", events[3].Subject)
+}
+
+func TestSourceIPFromContext(t *testing.T) {
+	assert.Equal(t, "", SourceIPFromContext(context.Background()))
+	assert.Equal(t, "10.0.0.2", SourceIPFromContext(context.WithValue(context.Background(), glog.KeyClientAddress, "10.0.0.2")))
+	assert.Equal(t, "10.0.0.3", SourceIPFromContext(WithSourceIP(context.Background(), "10.0.0.3")))
+}
@@ -23,3 +23,13 @@ func WithID[T any](id string) IssueOption[T] {
 		cfg.id = &id
 	}
 }
+
+// Option Auth 构造选项
+type Option[T any] func(*Auth[T])
+
+// WithAuditHook 注册审计钩子，每次签发和解析 token 后按注册顺序调用
+func WithAuditHook[T any](hooks ...AuditHook) Option[T] {
+	return func(a *Auth[T]) {
+		a.auditHooks = append(a.auditHooks, hooks...)
+	}
+}
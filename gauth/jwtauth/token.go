@@ -1,6 +1,7 @@
 package jwtauth
 
 import (
+	"context"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -21,13 +22,14 @@ type issueConfig struct {
 // 当前实现固定使用 HS256，签名密钥在内部以 []byte 持有，
 // 便于直接参与 HMAC 签名与验签。
 type Auth[T any] struct {
-	signKey []byte
+	signKey    []byte
+	auditHooks []AuditHook
 }
 
 // New 使用给定的签名密钥构造 Auth 实例。
 // signKey 在内部转换为 []byte 并做防御性复制，
 // 防止调用方后续修改影响内部状态。
-func New[T any](signKey string) (*Auth[T], error) {
+func New[T any](signKey string, opts ...Option[T]) (*Auth[T], error) {
 	if signKey == "" {
 		return nil, ErrEmptySignKey
 	}
@@ -37,7 +39,11 @@ func New[T any](signKey string) (*Auth[T], error) {
 	keyCopy := make([]byte, len(key))
 	copy(keyCopy, key)
 
-	return &Auth[T]{signKey: keyCopy}, nil
+	auth := &Auth[T]{signKey: keyCopy}
+	for _, opt := range opts {
+		opt(auth)
+	}
+	return auth, nil
 }
 
 // Issue 签发一枚新 JWT。
@@ -45,6 +51,26 @@ func New[T any](signKey string) (*Auth[T], error) {
 // subject 与 issuer 不可为空；expiresAt 必须至少比当前时间晚 1 秒，
 // 以保证签出的 token 在秒级精度下不会立即失效。
 func (a *Auth[T]) Issue(subject, issuer string, expiresAt time.Time, customData T, opts ...IssueOption[T]) (string, error) {
+	return a.IssueContext(context.Background(), subject, issuer, expiresAt, customData, opts...)
+}
+
+// IssueContext 与 Issue 相同，ctx 会传递给审计钩子，用于获取来源 IP 等请求信息。
+func (a *Auth[T]) IssueContext(ctx context.Context, subject, issuer string, expiresAt time.Time, customData T, opts ...IssueOption[T]) (string, error) {
+	cfg := issueConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var jti string
+	if cfg.id != nil {
+		jti = *cfg.id
+	}
+
+	token, err := a.issue(subject, issuer, expiresAt, customData, cfg)
+	a.audit(ctx, AuditActionIssue, subject, issuer, jti, err)
+	return token, err
+}
+
+func (a *Auth[T]) issue(subject, issuer string, expiresAt time.Time, customData T, cfg issueConfig) (string, error) {
 	if subject == "" {
 		return "", ErrEmptySubject
 	}
@@ -57,11 +83,6 @@ func (a *Auth[T]) Issue(subject, issuer string, expiresAt time.Time, customData
 		return "", ErrInvalidExpiry
 	}
 
-	cfg := issueConfig{}
-	for _, opt := range opts {
-		opt(&cfg)
-	}
-
 	claims := &Claims[T]{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   subject,
@@ -90,6 +111,25 @@ func (a *Auth[T]) Issue(subject, issuer string, expiresAt time.Time, customData
 //
 // 验签采用类型断言而非字符串比较，可防止算法混淆攻击（algorithm confusion attack）。
 func (a *Auth[T]) Parse(tokenStr string) (*Claims[T], error) {
+	return a.ParseContext(context.Background(), tokenStr)
+}
+
+// ParseContext 与 Parse 相同，ctx 会传递给审计钩子，用于获取来源 IP 等请求信息。
+// 解析失败时审计事件中的 subject、jti 为 token 中可读取到的值，未经验签，仅供排查参考。
+func (a *Auth[T]) ParseContext(ctx context.Context, tokenStr string) (*Claims[T], error) {
+	claims, err := a.parse(tokenStr)
+	if claims != nil {
+		a.audit(ctx, AuditActionParse, claims.Subject, claims.Issuer, claims.ID, err)
+	} else {
+		a.audit(ctx, AuditActionParse, "", "", "", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (a *Auth[T]) parse(tokenStr string) (*Claims[T], error) {
 	if tokenStr == "" {
 		return nil, ErrEmptyToken
 	}
@@ -103,11 +143,11 @@ func (a *Auth[T]) Parse(tokenStr string) (*Claims[T], error) {
 		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
 	)
 	if err != nil {
-		return nil, err
+		return claims, err
 	}
 
 	if !token.Valid {
-		return nil, ErrInvalidToken
+		return claims, ErrInvalidToken
 	}
 
 	return claims, nil
@@ -0,0 +1,38 @@
+package loginguard
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/gerror"
+)
+
+var ErrNilStore = errors.New("store cannot be nil")
+
+// Scope 计数维度
+type Scope string
+
+const (
+	ScopeAccount Scope = "account"
+	ScopeIP      Scope = "ip"
+)
+
+// LockedError 账号或 IP 处于锁定期，可通过 errors.As 获取锁定维度和剩余时间，
+// 通过 errors.As 获取 gerror.Error 时错误码为 gconstant.LoginLockedErr
+type LockedError struct {
+	Scope      Scope
+	RetryAfter time.Duration // 剩余锁定时间
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("login locked by %s, retry after %s", e.Scope, e.RetryAfter)
+}
+
+func (e *LockedError) Unwrap() error {
+	return gerror.Error{
+		Code: gconstant.LoginLockedErr,
+		Msg:  gconstant.AuthErrorMsgMap[gconstant.LoginLockedErr],
+	}
+}
@@ -0,0 +1,199 @@
+package loginguard
+
+import (
+	"context"
+	"time"
+
+	"github.com/morehao/golib/glog"
+)
+
+type config struct {
+	keyPrefix          string
+	maxAccountAttempts int64
+	maxIPAttempts      int64
+	window             time.Duration
+	baseLockout        time.Duration
+	maxLockout         time.Duration
+	resetAfter         time.Duration
+}
+
+// Option Guard 构造选项
+type Option func(*config)
+
+// WithKeyPrefix 设置存储 key 前缀，默认 loginguard:
+func WithKeyPrefix(prefix string) Option {
+	return func(c *config) {
+		c.keyPrefix = prefix
+	}
+}
+
+// WithMaxAttempts 设置窗口期内单个账号、单个 IP 允许的失败次数，达到后锁定，默认分别为 5 和 20，<= 0 时不限制该维度
+func WithMaxAttempts(account, ip int) Option {
+	return func(c *config) {
+		c.maxAccountAttempts = int64(account)
+		c.maxIPAttempts = int64(ip)
+	}
+}
+
+// WithWindow 设置失败计数窗口，从窗口内首次失败开始计算，默认 15 分钟
+func WithWindow(window time.Duration) Option {
+	return func(c *config) {
+		c.window = window
+	}
+}
+
+// WithLockout 设置锁定时长，第 n 次锁定时长为 base * 2^(n-1)，不超过 max，默认 1 分钟和 1 小时
+func WithLockout(base, max time.Duration) Option {
+	return func(c *config) {
+		c.baseLockout = base
+		c.maxLockout = max
+	}
+}
+
+// WithResetAfter 设置锁定次数的保留时长，超过该时长未再被锁定时锁定时长回到 base，默认 24 小时
+func WithResetAfter(d time.Duration) Option {
+	return func(c *config) {
+		c.resetAfter = d
+	}
+}
+
+// Guard 登录防爆破，按账号和 IP 两个维度统计失败次数，达到阈值后按指数退避锁定
+type Guard struct {
+	store Store
+	cfg   config
+}
+
+// New 创建 Guard
+func New(store Store, opts ...Option) (*Guard, error) {
+	if store == nil {
+		return nil, ErrNilStore
+	}
+	cfg := config{
+		keyPrefix:          "loginguard:",
+		maxAccountAttempts: 5,
+		maxIPAttempts:      20,
+		window:             15 * time.Minute,
+		baseLockout:        time.Minute,
+		maxLockout:         time.Hour,
+		resetAfter:         24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Guard{store: store, cfg: cfg}, nil
+}
+
+type target struct {
+	scope       Scope
+	id          string
+	maxAttempts int64
+}
+
+// targets 返回需要统计的维度，id 为空或未限制次数的维度跳过
+func (g *Guard) targets(account, ip string) []target {
+	var list []target
+	if account != "" && g.cfg.maxAccountAttempts > 0 {
+		list = append(list, target{scope: ScopeAccount, id: account, maxAttempts: g.cfg.maxAccountAttempts})
+	}
+	if ip != "" && g.cfg.maxIPAttempts > 0 {
+		list = append(list, target{scope: ScopeIP, id: ip, maxAttempts: g.cfg.maxIPAttempts})
+	}
+	return list
+}
+
+func (g *Guard) key(kind string, t target) string {
+	return g.cfg.keyPrefix + kind + ":" + string(t.scope) + ":" + t.id
+}
+
+// Check 登录前检查账号和 IP 是否处于锁定期，锁定时返回 *LockedError，两个维度均锁定时返回剩余时间较长的一个
+func (g *Guard) Check(ctx context.Context, account, ip string) error {
+	var lockedErr *LockedError
+	for _, t := range g.targets(account, ip) {
+		ttl, err := g.store.TTL(ctx, g.key("lock", t))
+		if err != nil {
+			return err
+		}
+		if ttl > 0 && (lockedErr == nil || ttl > lockedErr.RetryAfter) {
+			lockedErr = &LockedError{Scope: t.scope, RetryAfter: ttl}
+		}
+	}
+	if lockedErr != nil {
+		return lockedErr
+	}
+	return nil
+}
+
+// Fail 记录一次登录失败，本次失败触发锁定时返回 *LockedError
+func (g *Guard) Fail(ctx context.Context, account, ip string) error {
+	var lockedErr *LockedError
+	for _, t := range g.targets(account, ip) {
+		lockout, err := g.fail(ctx, t)
+		if err != nil {
+			return err
+		}
+		if lockout > 0 && (lockedErr == nil || lockout > lockedErr.RetryAfter) {
+			lockedErr = &LockedError{Scope: t.scope, RetryAfter: lockout}
+		}
+	}
+	if lockedErr != nil {
+		return lockedErr
+	}
+	return nil
+}
+
+func (g *Guard) fail(ctx context.Context, t target) (time.Duration, error) {
+	failKey := g.key("fail", t)
+	count, err := g.store.Incr(ctx, failKey, g.cfg.window)
+	if err != nil {
+		return 0, err
+	}
+	if count < t.maxAttempts {
+		return 0, nil
+	}
+
+	level, err := g.store.Incr(ctx, g.key("level", t), g.cfg.resetAfter)
+	if err != nil {
+		return 0, err
+	}
+	lockout := g.lockoutDuration(level)
+	if err := g.store.Set(ctx, g.key("lock", t), lockout); err != nil {
+		return 0, err
+	}
+	// 锁定后重新计数，解锁后再次达到阈值才会再次锁定
+	if err := g.store.Del(ctx, failKey); err != nil {
+		return 0, err
+	}
+	glog.Warnw(ctx, "login locked",
+		"loginguard.scope", string(t.scope),
+		"loginguard.id", t.id,
+		"loginguard.level", level,
+		"loginguard.lockout", lockout.String())
+	return lockout, nil
+}
+
+// lockoutDuration 第 level 次锁定的时长
+func (g *Guard) lockoutDuration(level int64) time.Duration {
+	lockout := g.cfg.baseLockout
+	for i := int64(1); i < level && lockout < g.cfg.maxLockout; i++ {
+		lockout *= 2
+	}
+	if g.cfg.maxLockout > 0 && lockout > g.cfg.maxLockout {
+		lockout = g.cfg.maxLockout
+	}
+	return lockout
+}
+
+// Succeed 登录成功后清除账号的失败计数和锁定次数，IP 维度的计数保留，避免同一 IP 轮换账号绕过限制
+func (g *Guard) Succeed(ctx context.Context, account string) error {
+	if account == "" {
+		return nil
+	}
+	t := target{scope: ScopeAccount, id: account}
+	return g.store.Del(ctx, g.key("fail", t), g.key("level", t))
+}
+
+// Unlock 解除账号或 IP 的锁定并清除计数，用于管理后台手动解锁
+func (g *Guard) Unlock(ctx context.Context, scope Scope, id string) error {
+	t := target{scope: scope, id: id}
+	return g.store.Del(ctx, g.key("fail", t), g.key("level", t), g.key("lock", t))
+}
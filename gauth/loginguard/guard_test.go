@@ -0,0 +1,119 @@
+package loginguard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/biz/gconstant"
+	"github.com/morehao/golib/gerror"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestGuard(t *testing.T, opts ...Option) (*Guard, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	guard, err := New(store, opts...)
+	require.NoError(t, err)
+	return guard, clock
+}
+
+func TestGuardAccountLockout(t *testing.T) {
+	ctx := context.Background()
+	guard, clock := newTestGuard(t, WithMaxAttempts(3, 0), WithLockout(time.Minute, 3*time.Minute))
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, guard.Fail(ctx, "alice", "10.0.0.1"))
+	}
+	require.NoError(t, guard.Check(ctx, "alice", "10.0.0.1"))
+
+	err := guard.Fail(ctx, "alice", "10.0.0.1")
+	var lockedErr *LockedError
+	require.True(t, errors.As(err, &lockedErr))
+	assert.Equal(t, ScopeAccount, lockedErr.Scope)
+	assert.Equal(t, time.Minute, lockedErr.RetryAfter)
+
+	var gErr gerror.Error
+	require.True(t, errors.As(guard.Check(ctx, "alice", ""), &gErr))
+	assert.Equal(t, gconstant.LoginLockedErr, gErr.Code)
+	assert.NoError(t, guard.Check(ctx, "bob", "10.0.0.1"))
+
+	// 锁定时长按指数增长并以最大值封顶
+	for _, want := range []time.Duration{2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		clock.now = clock.now.Add(lockedErr.RetryAfter)
+		require.NoError(t, guard.Check(ctx, "alice", ""))
+		for i := 0; i < 2; i++ {
+			require.NoError(t, guard.Fail(ctx, "alice", ""))
+		}
+		require.True(t, errors.As(guard.Fail(ctx, "alice", ""), &lockedErr))
+		assert.Equal(t, want, lockedErr.RetryAfter)
+	}
+}
+
+func TestGuardWindowAndSucceed(t *testing.T) {
+	ctx := context.Background()
+	guard, clock := newTestGuard(t, WithMaxAttempts(2, 0), WithWindow(time.Minute))
+
+	require.NoError(t, guard.Fail(ctx, "alice", ""))
+	clock.now = clock.now.Add(time.Minute)
+	require.NoError(t, guard.Fail(ctx, "alice", ""), "计数窗口过期后重新计数")
+
+	require.NoError(t, guard.Succeed(ctx, "alice"))
+	require.NoError(t, guard.Fail(ctx, "alice", ""), "登录成功后重新计数")
+	assert.Error(t, guard.Fail(ctx, "alice", ""))
+
+	require.NoError(t, guard.Unlock(ctx, ScopeAccount, "alice"))
+	assert.NoError(t, guard.Check(ctx, "alice", ""))
+}
+
+func TestGuardIPLockout(t *testing.T) {
+	ctx := context.Background()
+	guard, _ := newTestGuard(t, WithMaxAttempts(5, 3), WithLockout(time.Minute, time.Hour))
+
+	// 同一 IP 轮换账号
+	require.NoError(t, guard.Fail(ctx, "a", "10.0.0.1"))
+	require.NoError(t, guard.Fail(ctx, "b", "10.0.0.1"))
+	require.NoError(t, guard.Succeed(ctx, "b"))
+	err := guard.Fail(ctx, "c", "10.0.0.1")
+	var lockedErr *LockedError
+	require.True(t, errors.As(err, &lockedErr))
+	assert.Equal(t, ScopeIP, lockedErr.Scope)
+
+	require.True(t, errors.As(guard.Check(ctx, "d", "10.0.0.1"), &lockedErr))
+	assert.Equal(t, ScopeIP, lockedErr.Scope)
+	assert.NoError(t, guard.Check(ctx, "d", "10.0.0.2"))
+}
+
+func TestNewGuard(t *testing.T) {
+	_, err := New(nil)
+	assert.ErrorIs(t, err, ErrNilStore)
+}
+
+func TestRedisStore(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:6379"})
+	defer client.Close()
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("redis not available: %v", err)
+	}
+
+	guard, err := New(NewRedisStore(client), WithKeyPrefix("loginguard_test:"), WithMaxAttempts(2, 0))
+	require.NoError(t, err)
+	defer guard.Unlock(ctx, ScopeAccount, "alice")
+
+	require.NoError(t, guard.Fail(ctx, "alice", ""))
+	var lockedErr *LockedError
+	require.True(t, errors.As(guard.Fail(ctx, "alice", ""), &lockedErr))
+	require.True(t, errors.As(guard.Check(ctx, "alice", ""), &lockedErr))
+	assert.LessOrEqual(t, lockedErr.RetryAfter, time.Minute)
+}
@@ -0,0 +1,135 @@
+package loginguard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store 计数与锁定状态的存储，实现需保证并发安全
+type Store interface {
+	// Incr 计数加一并返回新值，key 新建时设置过期时间 ttl
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Set 写入 key 并设置过期时间
+	Set(ctx context.Context, key string, ttl time.Duration) error
+	// TTL 返回 key 的剩余过期时间，key 不存在时返回 0
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Del 删除 key，key 不存在时不报错
+	Del(ctx context.Context, keys ...string) error
+}
+
+// incrScript 自增并仅在 key 新建时设置过期时间，保证计数窗口从首次失败开始
+var incrScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// RedisStore 基于 Redis 的 Store，多实例部署时共享计数
+type RedisStore struct {
+	client redis.UniversalClient
+}
+
+// NewRedisStore 创建 RedisStore
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, s.client, []string{key}, ttl.Milliseconds()).Int64()
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, ttl time.Duration) error {
+	return s.client.Set(ctx, key, 1, ttl).Err()
+}
+
+func (s *RedisStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// key 不存在时为 -2，未设置过期时间时为 -1
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s *RedisStore) Del(ctx context.Context, keys ...string) error {
+	return s.client.Del(ctx, keys...).Err()
+}
+
+type memoryEntry struct {
+	value    int64
+	expireAt time.Time
+}
+
+// MemoryStore 基于内存的 Store，仅适用于单实例部署和测试，过期的 key 在访问时清理
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryStore 创建 MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.get(key)
+	if entry == nil {
+		entry = &memoryEntry{expireAt: s.now().Add(ttl)}
+		s.entries[key] = entry
+	}
+	entry.value++
+	return entry.value, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &memoryEntry{value: 1, expireAt: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) TTL(_ context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.get(key)
+	if entry == nil {
+		return 0, nil
+	}
+	return entry.expireAt.Sub(s.now()), nil
+}
+
+func (s *MemoryStore) Del(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// get 返回未过期的 key，已过期的 key 直接删除，调用方需持有锁
+func (s *MemoryStore) get(key string) *memoryEntry {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if !s.now().Before(entry.expireAt) {
+		delete(s.entries, key)
+		return nil
+	}
+	return entry
+}
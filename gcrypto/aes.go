@@ -7,6 +7,8 @@ import (
 	"encoding/base64"
 	"errors"
 	"io"
+	"os"
+	"strings"
 )
 
 // AES密钥环境变量名
@@ -56,6 +58,19 @@ func NewAES(key string) (*AES, error) {
 	return &AES{key: keyBytes}, nil
 }
 
+// NewAESFromFile 从文件读取密钥创建AES加密器，会去除首尾空白字符
+func NewAESFromFile(keyFile string) (*AES, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return nil, errors.New("key file is empty")
+	}
+	return NewAES(key)
+}
+
 // Encrypt 加密数据（使用GCM模式）
 func (a *AES) Encrypt(plaintext []byte) ([]byte, error) {
 	block, err := aes.NewCipher(a.key)
@@ -16,53 +16,108 @@ const (
 	argon2idM       = 65536
 	argon2idT       = 1
 	argon2idP       = 4
+
+	// 解析已存储哈希时允许的参数范围，超出范围的哈希视为无效，避免 IDKey panic 或耗尽资源
+	argon2idMaxMemory  = 4 << 20 // 4 GiB
+	argon2idMaxTime    = 1 << 10
+	argon2idMinSaltLen = 8
+	argon2idMinKeyLen  = 4
 )
 
+// argon2idParams argon2id 哈希参数
+type argon2idParams struct {
+	memory  uint32 // 内存开销，单位 KiB
+	time    uint32 // 迭代次数
+	threads uint8  // 并行度
+	saltLen uint32
+	keyLen  uint32
+}
+
+var defaultArgon2idParams = argon2idParams{
+	memory:  argon2idM,
+	time:    argon2idT,
+	threads: argon2idP,
+	saltLen: argon2idSaltLen,
+	keyLen:  argon2idKeyLen,
+}
+
 func GenerateArgon2idHash(password string) (string, error) {
-	salt := make([]byte, argon2idSaltLen)
+	return generateArgon2idHash(password, defaultArgon2idParams)
+}
+
+func CompareArgon2idHash(hashedPassword, password string) error {
+	params, salt, expectedHash, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	actualHash := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(expectedHash)))
+
+	if subtle.ConstantTimeCompare(expectedHash, actualHash) != 1 {
+		return fmt.Errorf("password mismatch")
+	}
+
+	return nil
+}
+
+func generateArgon2idHash(password string, params argon2idParams) (string, error) {
+	salt := make([]byte, params.saltLen)
 	if _, err := rand.Read(salt); err != nil {
 		return "", fmt.Errorf("failed to generate salt: %w", err)
 	}
 
-	hash := argon2.IDKey([]byte(password), salt, argon2idT, argon2idM, argon2idP, argon2idKeyLen)
+	hash := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, params.keyLen)
 
 	saltB64 := base64.RawStdEncoding.EncodeToString(salt)
 	hashB64 := base64.RawStdEncoding.EncodeToString(hash)
 
-	return fmt.Sprintf("$argon2id$v=19$m=%d,t=%d,p=%d$%s$%s", argon2idM, argon2idT, argon2idP, saltB64, hashB64), nil
+	return fmt.Sprintf("$argon2id$v=19$m=%d,t=%d,p=%d$%s$%s", params.memory, params.time, params.threads, saltB64, hashB64), nil
 }
 
-func CompareArgon2idHash(hashedPassword, password string) error {
+// decodeArgon2idHash 解析 PHC 格式的 argon2id 哈希，返回参数、盐和哈希值
+func decodeArgon2idHash(hashedPassword string) (argon2idParams, []byte, []byte, error) {
+	var params argon2idParams
 	parts := strings.Split(hashedPassword, "$")
 	if len(parts) != 6 {
-		return fmt.Errorf("invalid hash format")
+		return params, nil, nil, fmt.Errorf("invalid hash format")
 	}
 
 	if parts[1] != "argon2id" || parts[2] != "v=19" {
-		return fmt.Errorf("unsupported argon2id version")
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version")
 	}
 
-	var m, t, p int
-	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &m, &t, &p)
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads)
 	if err != nil {
-		return fmt.Errorf("invalid parameters: %w", err)
+		return params, nil, nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+	if params.time < 1 || params.time > argon2idMaxTime {
+		return params, nil, nil, fmt.Errorf("invalid parameters: t=%d out of range [1, %d]", params.time, argon2idMaxTime)
+	}
+	if params.threads < 1 {
+		return params, nil, nil, fmt.Errorf("invalid parameters: p=%d must be at least 1", params.threads)
+	}
+	// RFC 9106 要求内存不小于 8*p KiB
+	if params.memory < 8*uint32(params.threads) || params.memory > argon2idMaxMemory {
+		return params, nil, nil, fmt.Errorf("invalid parameters: m=%d out of range [%d, %d]", params.memory, 8*uint32(params.threads), argon2idMaxMemory)
 	}
 
 	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
 	if err != nil {
-		return fmt.Errorf("invalid salt: %w", err)
+		return params, nil, nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	if len(salt) < argon2idMinSaltLen {
+		return params, nil, nil, fmt.Errorf("invalid salt: length %d is less than %d", len(salt), argon2idMinSaltLen)
 	}
 
-	expectedHash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
 	if err != nil {
-		return fmt.Errorf("invalid hash: %w", err)
+		return params, nil, nil, fmt.Errorf("invalid hash: %w", err)
 	}
-
-	actualHash := argon2.IDKey([]byte(password), salt, uint32(t), argon2idM, uint8(p), uint32(len(expectedHash)))
-
-	if subtle.ConstantTimeCompare(expectedHash, actualHash) != 1 {
-		return fmt.Errorf("password mismatch")
+	if len(hash) < argon2idMinKeyLen {
+		return params, nil, nil, fmt.Errorf("invalid hash: length %d is less than %d", len(hash), argon2idMinKeyLen)
 	}
+	params.saltLen = uint32(len(salt))
+	params.keyLen = uint32(len(hash))
 
-	return nil
-}
\ No newline at end of file
+	return params, salt, hash, nil
+}
@@ -64,4 +64,38 @@ func TestGenerateArgon2idHash_SpecialChars(t *testing.T) {
 			t.Errorf("CompareArgon2idHash failed for %s: %v", pwd, err)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestCompareArgon2idHash_Malformed(t *testing.T) {
+	const salt, hash = "c29tZXNhbHRzb21lc2FsdA", "aGFzaGhhc2hoYXNoaGFzaGhhc2hoYXNoaGFzaGhhc2g"
+	hashes := map[string]string{
+		"empty":         "",
+		"missing parts": "$argon2id$v=19$m=65536,t=1,p=4$" + salt,
+		"wrong algo":    "$argon2i$v=19$m=65536,t=1,p=4$" + salt + "$" + hash,
+		"wrong version": "$argon2id$v=16$m=65536,t=1,p=4$" + salt + "$" + hash,
+		"bad params":    "$argon2id$v=19$m=x,t=1,p=4$" + salt + "$" + hash,
+		"zero time":     "$argon2id$v=19$m=65536,t=0,p=4$" + salt + "$" + hash,
+		"zero threads":  "$argon2id$v=19$m=65536,t=1,p=0$" + salt + "$" + hash,
+		"threads range": "$argon2id$v=19$m=65536,t=1,p=256$" + salt + "$" + hash,
+		"low memory":    "$argon2id$v=19$m=16,t=1,p=4$" + salt + "$" + hash,
+		"huge memory":   "$argon2id$v=19$m=4294967295,t=1,p=4$" + salt + "$" + hash,
+		"huge time":     "$argon2id$v=19$m=65536,t=4294967295,p=4$" + salt + "$" + hash,
+		"bad salt":      "$argon2id$v=19$m=65536,t=1,p=4$!!!$" + hash,
+		"short salt":    "$argon2id$v=19$m=65536,t=1,p=4$c2FsdA$" + hash,
+		"bad hash":      "$argon2id$v=19$m=65536,t=1,p=4$" + salt + "$!!!",
+		"empty hash":    "$argon2id$v=19$m=65536,t=1,p=4$" + salt + "$",
+	}
+	for name, hashed := range hashes {
+		t.Run(name, func(t *testing.T) {
+			if err := CompareArgon2idHash(hashed, "password"); err == nil {
+				t.Errorf("CompareArgon2idHash should fail for %q", hashed)
+			}
+			if _, err := DefaultPasswordPolicy.Verify(hashed, "password"); err == nil {
+				t.Errorf("PasswordPolicy.Verify should fail for %q", hashed)
+			}
+			if !DefaultPasswordPolicy.NeedsRehash(hashed) {
+				t.Errorf("NeedsRehash should be true for %q", hashed)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package gcrypto
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+)
+
+// 证书默认有效期
+const defaultCertValidFor = 365 * 24 * time.Hour
+
+// CertOptions 证书和证书请求的主体信息
+type CertOptions struct {
+	CommonName   string        // 通用名称
+	Organization []string      // 组织
+	DNSNames     []string      // DNS 备用名称
+	IPAddresses  []net.IP      // IP 备用名称
+	ValidFor     time.Duration // 有效期，默认 365 天，仅自签名证书使用
+	IsCA         bool          // 是否为 CA 证书，仅自签名证书使用
+}
+
+func (o *CertOptions) subject() pkix.Name {
+	return pkix.Name{
+		CommonName:   o.CommonName,
+		Organization: o.Organization,
+	}
+}
+
+// GenerateSelfSignedCert 生成自签名证书，返回 PEM 格式
+// key: 签名私钥，支持 *rsa.PrivateKey、*ecdsa.PrivateKey、ed25519.PrivateKey
+func GenerateSelfSignedCert(key crypto.Signer, opts CertOptions) ([]byte, error) {
+	if key == nil {
+		return nil, errors.New("private key is required")
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	validFor := opts.ValidFor
+	if validFor <= 0 {
+		validFor = defaultCertValidFor
+	}
+	notBefore := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               opts.subject(),
+		DNSNames:              opts.DNSNames,
+		IPAddresses:           opts.IPAddresses,
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validFor),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  opts.IsCA,
+	}
+	if opts.IsCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: certBytes,
+	}), nil
+}
+
+// GenerateCSR 生成证书签名请求，返回 PEM 格式
+func GenerateCSR(key crypto.Signer, opts CertOptions) ([]byte, error) {
+	if key == nil {
+		return nil, errors.New("private key is required")
+	}
+
+	template := &x509.CertificateRequest{
+		Subject:     opts.subject(),
+		DNSNames:    opts.DNSNames,
+		IPAddresses: opts.IPAddresses,
+	}
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE REQUEST",
+		Bytes: csrBytes,
+	}), nil
+}
+
+// ParseCertificatePEM 解析PEM格式的证书
+func ParseCertificatePEM(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block")
+	}
+	if block.Type != "CERTIFICATE" {
+		return nil, errors.New("not a certificate PEM block")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// ParseCSRPEM 解析PEM格式的证书签名请求并校验签名
+func ParseCSRPEM(csrPEM []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block")
+	}
+	if block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("not a certificate request PEM block")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+	return csr, nil
+}
+
+// CertExpiresIn 返回证书距离过期的剩余时间，已过期时返回负值
+func CertExpiresIn(cert *x509.Certificate) time.Duration {
+	return time.Until(cert.NotAfter)
+}
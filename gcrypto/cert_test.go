@@ -0,0 +1,100 @@
+package gcrypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestGenerateSelfSignedCert_RSA(t *testing.T) {
+	privateKey, _, err := GenerateRSAKeyPair(2048)
+	if err != nil {
+		t.Fatalf("GenerateRSAKeyPair failed: %v", err)
+	}
+
+	certPEM, err := GenerateSelfSignedCert(privateKey, CertOptions{
+		CommonName:  "golib.local",
+		DNSNames:    []string{"golib.local"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ValidFor:    24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("GenerateSelfSignedCert failed: %v", err)
+	}
+
+	cert, err := ParseCertificatePEM(certPEM)
+	if err != nil {
+		t.Fatalf("ParseCertificatePEM failed: %v", err)
+	}
+	if cert.Subject.CommonName != "golib.local" {
+		t.Fatalf("CommonName mismatch: %s", cert.Subject.CommonName)
+	}
+	if err := cert.VerifyHostname("golib.local"); err != nil {
+		t.Fatalf("VerifyHostname failed: %v", err)
+	}
+	if err := cert.VerifyHostname("127.0.0.1"); err != nil {
+		t.Fatalf("VerifyHostname failed: %v", err)
+	}
+
+	expiresIn := CertExpiresIn(cert)
+	if expiresIn <= 23*time.Hour || expiresIn > 24*time.Hour {
+		t.Fatalf("unexpected CertExpiresIn: %v", expiresIn)
+	}
+}
+
+func TestGenerateSelfSignedCert_ECDSA(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey failed: %v", err)
+	}
+
+	certPEM, err := GenerateSelfSignedCert(privateKey, CertOptions{CommonName: "golib-ca", IsCA: true})
+	if err != nil {
+		t.Fatalf("GenerateSelfSignedCert failed: %v", err)
+	}
+	cert, err := ParseCertificatePEM(certPEM)
+	if err != nil {
+		t.Fatalf("ParseCertificatePEM failed: %v", err)
+	}
+	if !cert.IsCA || cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		t.Fatal("expected CA certificate")
+	}
+	if err := cert.CheckSignatureFrom(cert); err != nil {
+		t.Fatalf("CheckSignatureFrom failed: %v", err)
+	}
+	if expiresIn := CertExpiresIn(cert); expiresIn <= 364*24*time.Hour {
+		t.Fatalf("unexpected default validity: %v", expiresIn)
+	}
+}
+
+func TestGenerateCSR(t *testing.T) {
+	privateKey, _, err := GenerateRSAKeyPair(2048)
+	if err != nil {
+		t.Fatalf("GenerateRSAKeyPair failed: %v", err)
+	}
+
+	csrPEM, err := GenerateCSR(privateKey, CertOptions{
+		CommonName:   "svc.golib.local",
+		Organization: []string{"golib"},
+		DNSNames:     []string{"svc.golib.local"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateCSR failed: %v", err)
+	}
+
+	csr, err := ParseCSRPEM(csrPEM)
+	if err != nil {
+		t.Fatalf("ParseCSRPEM failed: %v", err)
+	}
+	if csr.Subject.CommonName != "svc.golib.local" || len(csr.DNSNames) != 1 {
+		t.Fatalf("unexpected CSR subject: %+v", csr.Subject)
+	}
+
+	if _, err := ParseCertificatePEM(csrPEM); err == nil {
+		t.Fatal("ParseCertificatePEM should reject CSR PEM")
+	}
+}
@@ -0,0 +1,286 @@
+package gcrypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 配置文件加密值格式，如 password: ENC(base64密文)
+const (
+	ConfigValuePrefix = "ENC("
+	ConfigValueSuffix = ")"
+)
+
+// StringCipher 字符串加解密，AES、SM4、RSA、SM2 均已实现
+type StringCipher interface {
+	EncryptString(plaintext string) (string, error)
+	DecryptString(ciphertext string) (string, error)
+}
+
+// IsEncryptedConfigValue 判断是否为 ENC(...) 格式的加密值
+func IsEncryptedConfigValue(value string) bool {
+	return strings.HasPrefix(value, ConfigValuePrefix) && strings.HasSuffix(value, ConfigValueSuffix) &&
+		len(value) > len(ConfigValuePrefix)+len(ConfigValueSuffix)
+}
+
+// EncryptConfigValue 加密配置值，返回 ENC(...) 格式，已加密的值原样返回
+func EncryptConfigValue(c StringCipher, plaintext string) (string, error) {
+	if IsEncryptedConfigValue(plaintext) {
+		return plaintext, nil
+	}
+	ciphertext, err := c.EncryptString(plaintext)
+	if err != nil {
+		return "", err
+	}
+	return ConfigValuePrefix + ciphertext + ConfigValueSuffix, nil
+}
+
+// DecryptConfigValue 解密 ENC(...) 格式的配置值，非加密值原样返回
+func DecryptConfigValue(c StringCipher, value string) (string, error) {
+	if !IsEncryptedConfigValue(value) {
+		return value, nil
+	}
+	return c.DecryptString(value[len(ConfigValuePrefix) : len(value)-len(ConfigValueSuffix)])
+}
+
+// EncryptYAML 加密 YAML 文档中的字符串值，保留注释和键的顺序。
+// paths 为以 . 分隔的路径，* 匹配任意一级键或数组下标，路径匹配值本身或其上级节点时加密，
+// 如 mysql.password、clients.*.secret、servers.0；不传 paths 时加密全部字符串值。
+// 空字符串和已加密的值不做处理，支持多文档
+func EncryptYAML(data []byte, c StringCipher, paths ...string) ([]byte, error) {
+	return transformYAML(data, configEncryptFunc(c), paths)
+}
+
+// DecryptYAML 解密 YAML 文档中 ENC(...) 格式的值，paths 规则同 EncryptYAML，不传时解密全部加密值，
+// 用于运维工具查看或修改明文后重新加密
+func DecryptYAML(data []byte, c StringCipher, paths ...string) ([]byte, error) {
+	return transformYAML(data, configDecryptFunc(c), paths)
+}
+
+// EncryptJSON 加密 JSON 文档中的字符串值，保留键的顺序，输出以两个空格缩进，paths 规则同 EncryptYAML
+func EncryptJSON(data []byte, c StringCipher, paths ...string) ([]byte, error) {
+	return transformJSON(data, configEncryptFunc(c), paths)
+}
+
+// DecryptJSON 解密 JSON 文档中 ENC(...) 格式的值，paths 规则同 EncryptYAML
+func DecryptJSON(data []byte, c StringCipher, paths ...string) ([]byte, error) {
+	return transformJSON(data, configDecryptFunc(c), paths)
+}
+
+// configValueFunc 转换字符串值，返回 false 表示不修改
+type configValueFunc func(value string) (string, bool, error)
+
+func configEncryptFunc(c StringCipher) configValueFunc {
+	return func(value string) (string, bool, error) {
+		if value == "" || IsEncryptedConfigValue(value) {
+			return "", false, nil
+		}
+		res, err := EncryptConfigValue(c, value)
+		return res, err == nil, err
+	}
+}
+
+func configDecryptFunc(c StringCipher) configValueFunc {
+	return func(value string) (string, bool, error) {
+		if !IsEncryptedConfigValue(value) {
+			return "", false, nil
+		}
+		res, err := DecryptConfigValue(c, value)
+		return res, err == nil, err
+	}
+}
+
+func transformYAML(data []byte, fn configValueFunc, paths []string) ([]byte, error) {
+	patterns := splitConfigPaths(paths)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("parse yaml: %w", err)
+		}
+		if err := walkConfigNode(&doc, nil, patterns, fn); err != nil {
+			return nil, err
+		}
+		if err := encoder.Encode(&doc); err != nil {
+			return nil, fmt.Errorf("encode yaml: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encode yaml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// transformJSON 借助 YAML 节点解析 JSON 以保留键的顺序，再按节点输出 JSON
+func transformJSON(data []byte, fn configValueFunc, paths []string) ([]byte, error) {
+	if !json.Valid(data) {
+		return nil, errors.New("parse json: invalid json document")
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse json: %w", err)
+	}
+	if err := walkConfigNode(&doc, nil, splitConfigPaths(paths), fn); err != nil {
+		return nil, err
+	}
+	var compact bytes.Buffer
+	if err := writeJSONNode(&compact, &doc); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, compact.Bytes(), "", "  "); err != nil {
+		return nil, fmt.Errorf("encode json: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func splitConfigPaths(paths []string) [][]string {
+	patterns := make([][]string, 0, len(paths))
+	for _, p := range paths {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, strings.Split(p, "."))
+		}
+	}
+	return patterns
+}
+
+// matchConfigPath 判断路径是否被选中，未指定 patterns 时全部选中
+func matchConfigPath(path []string, patterns [][]string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if len(pattern) > len(path) {
+			continue
+		}
+		matched := true
+		for i, seg := range pattern {
+			if seg != "*" && seg != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// walkConfigNode 遍历节点并转换被选中的字符串值，别名节点指向的锚点已在定义处处理
+func walkConfigNode(node *yaml.Node, path []string, patterns [][]string, fn configValueFunc) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := walkConfigNode(child, path, patterns, fn); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childPath := append(path[:len(path):len(path)], node.Content[i].Value)
+			if err := walkConfigNode(node.Content[i+1], childPath, patterns, fn); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			childPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if err := walkConfigNode(child, childPath, patterns, fn); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() != "!!str" || !matchConfigPath(path, patterns) {
+			return nil
+		}
+		value, changed, err := fn(node.Value)
+		if err != nil {
+			return fmt.Errorf("config value %s: %w", strings.Join(path, "."), err)
+		}
+		if changed {
+			node.Value = value
+			node.Tag = "!!str"
+			// 块样式会给值追加换行，转换后统一由编码器选择样式
+			if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+				node.Style = 0
+			}
+		}
+	}
+	return nil
+}
+
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(buf, node.Content[0])
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONString(buf, node.Content[i].Value); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, child); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!str":
+			return writeJSONString(buf, node.Value)
+		case "!!null":
+			buf.WriteString("null")
+		default:
+			// 输入为合法 JSON，数字和布尔值原样输出
+			buf.WriteString(node.Value)
+		}
+	default:
+		return fmt.Errorf("encode json: unsupported node kind %d", node.Kind)
+	}
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	// Encode 会追加换行
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
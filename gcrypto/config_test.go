@@ -0,0 +1,167 @@
+package gcrypto
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testConfigYAML = `# 数据库配置
+mysql:
+  host: 127.0.0.1
+  port: 3306
+  password: "123456" # 密码
+clients:
+  - name: order
+    secret: s1
+  - name: user
+    secret: s2
+empty: ""
+`
+
+func TestConfigValue(t *testing.T) {
+	aes, err := NewAES("")
+	if err != nil {
+		t.Fatalf("NewAES failed: %v", err)
+	}
+	encrypted, err := EncryptConfigValue(aes, "secret")
+	if err != nil {
+		t.Fatalf("EncryptConfigValue failed: %v", err)
+	}
+	if !IsEncryptedConfigValue(encrypted) {
+		t.Fatalf("Expected ENC(...) value, got %s", encrypted)
+	}
+	again, _ := EncryptConfigValue(aes, encrypted)
+	if again != encrypted {
+		t.Fatalf("Encrypted value should not be encrypted twice")
+	}
+	decrypted, err := DecryptConfigValue(aes, encrypted)
+	if err != nil || decrypted != "secret" {
+		t.Fatalf("DecryptConfigValue failed: %v, got %s", err, decrypted)
+	}
+	plain, err := DecryptConfigValue(aes, "plain")
+	if err != nil || plain != "plain" {
+		t.Fatalf("Plain value should be returned as is, got %s", plain)
+	}
+	if IsEncryptedConfigValue("ENC()") {
+		t.Fatalf("ENC() should not be treated as encrypted value")
+	}
+}
+
+func TestEncryptYAML_Paths(t *testing.T) {
+	aes, _ := NewAES("")
+	encrypted, err := EncryptYAML([]byte(testConfigYAML), aes, "mysql.password", "clients.*.secret")
+	if err != nil {
+		t.Fatalf("EncryptYAML failed: %v", err)
+	}
+	var cfg struct {
+		MySQL struct {
+			Host     string `yaml:"host"`
+			Port     int    `yaml:"port"`
+			Password string `yaml:"password"`
+		} `yaml:"mysql"`
+		Clients []struct {
+			Name   string `yaml:"name"`
+			Secret string `yaml:"secret"`
+		} `yaml:"clients"`
+	}
+	if err := yaml.Unmarshal(encrypted, &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if cfg.MySQL.Host != "127.0.0.1" || cfg.MySQL.Port != 3306 || cfg.Clients[0].Name != "order" {
+		t.Fatalf("Unselected values should not change: %s", encrypted)
+	}
+	if !IsEncryptedConfigValue(cfg.MySQL.Password) || !IsEncryptedConfigValue(cfg.Clients[1].Secret) {
+		t.Fatalf("Selected values should be encrypted: %s", encrypted)
+	}
+	if !strings.Contains(string(encrypted), "# 数据库配置") || !strings.Contains(string(encrypted), "# 密码") {
+		t.Fatalf("Comments should be kept: %s", encrypted)
+	}
+
+	decrypted, err := DecryptYAML(encrypted, aes)
+	if err != nil {
+		t.Fatalf("DecryptYAML failed: %v", err)
+	}
+	if string(decrypted) != testConfigYAML {
+		t.Fatalf("Expected %q, got %q", testConfigYAML, decrypted)
+	}
+}
+
+func TestEncryptYAML_All(t *testing.T) {
+	aes, _ := NewAES("")
+	encrypted, err := EncryptYAML([]byte(testConfigYAML), aes)
+	if err != nil {
+		t.Fatalf("EncryptYAML failed: %v", err)
+	}
+	var cfg map[string]any
+	if err := yaml.Unmarshal(encrypted, &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	mysql := cfg["mysql"].(map[string]any)
+	if !IsEncryptedConfigValue(mysql["host"].(string)) || mysql["port"] != 3306 {
+		t.Fatalf("Only string values should be encrypted: %s", encrypted)
+	}
+	if cfg["empty"] != "" {
+		t.Fatalf("Empty value should not be encrypted: %s", encrypted)
+	}
+
+	// 解密时可以只解密指定路径
+	decrypted, err := DecryptYAML(encrypted, aes, "mysql")
+	if err != nil {
+		t.Fatalf("DecryptYAML failed: %v", err)
+	}
+	if !strings.Contains(string(decrypted), "host: 127.0.0.1") || strings.Contains(string(decrypted), "name: order") {
+		t.Fatalf("Only mysql should be decrypted: %s", decrypted)
+	}
+
+	other, _ := NewAES("0123456789abcdef")
+	if _, err := DecryptYAML(encrypted, other); err == nil || !strings.Contains(err.Error(), "config value mysql.host") {
+		t.Fatalf("DecryptYAML with wrong key should fail with path, got %v", err)
+	}
+}
+
+func TestEncryptJSON(t *testing.T) {
+	sm4, err := NewSM4("")
+	if err != nil {
+		t.Fatalf("NewSM4 failed: %v", err)
+	}
+	input := `{"redis":{"addr":"127.0.0.1:6379","password":"p<w>d","db":0,"tls":false,"extra":null},"tokens":["a","b"]}`
+	encrypted, err := EncryptJSON([]byte(input), sm4, "redis.password", "tokens.1")
+	if err != nil {
+		t.Fatalf("EncryptJSON failed: %v", err)
+	}
+	s := string(encrypted)
+	if strings.Index(s, `"addr"`) > strings.Index(s, `"password"`) || !strings.Contains(s, `"addr": "127.0.0.1:6379"`) {
+		t.Fatalf("Key order should be kept: %s", s)
+	}
+	if !strings.Contains(s, `"password": "ENC(`) || !strings.Contains(s, `"a",`) {
+		t.Fatalf("Selected values should be encrypted: %s", s)
+	}
+
+	decrypted, err := DecryptJSON(encrypted, sm4)
+	if err != nil {
+		t.Fatalf("DecryptJSON failed: %v", err)
+	}
+	expected := `{
+  "redis": {
+    "addr": "127.0.0.1:6379",
+    "password": "p<w>d",
+    "db": 0,
+    "tls": false,
+    "extra": null
+  },
+  "tokens": [
+    "a",
+    "b"
+  ]
+}
+`
+	if string(decrypted) != expected {
+		t.Fatalf("Expected %s, got %s", expected, decrypted)
+	}
+
+	if _, err := EncryptJSON([]byte("a: 1"), sm4); err == nil {
+		t.Fatalf("EncryptJSON should fail with non-json input")
+	}
+}
@@ -1,13 +1,30 @@
 package gcrypto
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
 )
 
+// 常用字符集
+const (
+	CharsetDigits       = "0123456789"
+	CharsetLowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	CharsetUpperLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	CharsetAlphanumeric = CharsetDigits + CharsetLowerLetters + CharsetUpperLetters
+)
+
+// 默认长度
+const (
+	apiKeyBytes = 32 // API Key 随机部分字节数（256位）
+	nonceBytes  = 12 // nonce 字节数，与 AES-GCM 一致
+)
+
 // GenerateRandomBytes 生成指定长度的随机字节
 func GenerateRandomBytes(length int) ([]byte, error) {
 	if length <= 0 {
@@ -20,6 +37,54 @@ func GenerateRandomBytes(length int) ([]byte, error) {
 	return bytes, nil
 }
 
+// GenerateRandomString 从 charset 中均匀随机选取字符生成指定长度的字符串，charset 为空时使用 CharsetAlphanumeric
+func GenerateRandomString(charset string, length int) (string, error) {
+	if length <= 0 {
+		return "", errors.New("length must be greater than 0")
+	}
+	if charset == "" {
+		charset = CharsetAlphanumeric
+	}
+	chars := []rune(charset)
+	max := big.NewInt(int64(len(chars)))
+	result := make([]rune, length)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		result[i] = chars[n.Int64()]
+	}
+	return string(result), nil
+}
+
+// GenerateAPIKey 生成 API Key，格式为 prefix_<随机部分>，随机部分为 256 位的 URL 安全 base64 编码
+// prefix 为空时只返回随机部分，建议使用业务前缀（如 sk_live）便于识别和密钥扫描
+func GenerateAPIKey(prefix string) (string, error) {
+	id, err := GenerateURLSafeID(apiKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	if prefix == "" {
+		return id, nil
+	}
+	return prefix + "_" + id, nil
+}
+
+// GenerateNonce 生成 12 字节随机 nonce
+func GenerateNonce() ([]byte, error) {
+	return GenerateRandomBytes(nonceBytes)
+}
+
+// GenerateURLSafeID 生成 n 字节随机数并以无填充的 URL 安全 base64 编码返回，可用于 URL、Cookie、文件名
+func GenerateURLSafeID(n int) (string, error) {
+	b, err := GenerateRandomBytes(n)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 // getKeyFromEnvOrDefault 从环境变量获取密钥，如果不存在则使用默认值
 // envKey: 环境变量名
 // defaultKey: 默认密钥
@@ -34,3 +99,15 @@ func SHA256Hash(data string) string {
 	h := sha256.Sum256([]byte(data))
 	return fmt.Sprintf("%x", h)
 }
+
+// HMACSHA256 计算 data 的 HMAC-SHA256
+func HMACSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// VerifyHMACSHA256 以常量时间校验 data 的 HMAC-SHA256 是否等于 sum
+func VerifyHMACSHA256(key, data, sum []byte) bool {
+	return hmac.Equal(HMACSHA256(key, data), sum)
+}
@@ -1,6 +1,8 @@
 package gcrypto
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -33,3 +35,69 @@ func TestSHA256Hash(t *testing.T) {
 		t.Fatalf("Expected hash length 64 for empty string, got %d", len(emptyResult))
 	}
 }
+
+func TestHMACSHA256(t *testing.T) {
+	key, data := []byte("Jefe"), []byte("what do ya want for nothing?")
+	sum := HMACSHA256(key, data)
+	expected := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got := fmt.Sprintf("%x", sum); got != expected {
+		t.Fatalf("Expected %s, got %s", expected, got)
+	}
+	if !VerifyHMACSHA256(key, data, sum) {
+		t.Fatalf("VerifyHMACSHA256 should succeed")
+	}
+	if VerifyHMACSHA256([]byte("other"), data, sum) {
+		t.Fatalf("VerifyHMACSHA256 should fail with wrong key")
+	}
+	if VerifyHMACSHA256(key, data, sum[:16]) {
+		t.Fatalf("VerifyHMACSHA256 should fail with truncated sum")
+	}
+}
+
+func TestGenerateRandomString(t *testing.T) {
+	s, err := GenerateRandomString(CharsetDigits, 6)
+	if err != nil {
+		t.Fatalf("GenerateRandomString failed: %v", err)
+	}
+	if len(s) != 6 || strings.Trim(s, CharsetDigits) != "" {
+		t.Fatalf("unexpected random string: %s", s)
+	}
+
+	s, err = GenerateRandomString("", 32)
+	if err != nil || len(s) != 32 {
+		t.Fatalf("GenerateRandomString default charset failed: %v, %s", err, s)
+	}
+
+	if _, err := GenerateRandomString(CharsetDigits, 0); err == nil {
+		t.Fatal("GenerateRandomString should fail for zero length")
+	}
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, err := GenerateAPIKey("sk_live")
+	if err != nil {
+		t.Fatalf("GenerateAPIKey failed: %v", err)
+	}
+	if !strings.HasPrefix(key, "sk_live_") || len(key) != len("sk_live_")+43 {
+		t.Fatalf("unexpected api key: %s", key)
+	}
+	other, _ := GenerateAPIKey("sk_live")
+	if key == other {
+		t.Fatal("api keys should be unique")
+	}
+}
+
+func TestGenerateNonceAndURLSafeID(t *testing.T) {
+	nonce, err := GenerateNonce()
+	if err != nil || len(nonce) != 12 {
+		t.Fatalf("GenerateNonce failed: %v, %d", err, len(nonce))
+	}
+
+	id, err := GenerateURLSafeID(16)
+	if err != nil {
+		t.Fatalf("GenerateURLSafeID failed: %v", err)
+	}
+	if len(id) != 22 || strings.ContainsAny(id, "+/=") {
+		t.Fatalf("unexpected url safe id: %s", id)
+	}
+}
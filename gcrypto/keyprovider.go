@@ -0,0 +1,156 @@
+package gcrypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// KeyProvider 密钥提供者，用于从文件、环境变量或密钥管理服务（Vault、KMS 等）获取密钥
+type KeyProvider interface {
+	// GetKey 获取名称为 name 的密钥内容，PEM 密钥返回完整的 PEM 数据
+	GetKey(ctx context.Context, name string) ([]byte, error)
+}
+
+// KeyProviderFunc 函数形式的 KeyProvider
+type KeyProviderFunc func(ctx context.Context, name string) ([]byte, error)
+
+// GetKey 实现 KeyProvider 接口
+func (f KeyProviderFunc) GetKey(ctx context.Context, name string) ([]byte, error) {
+	return f(ctx, name)
+}
+
+// EnvKeyProvider 从环境变量获取密钥，name 为环境变量名
+type EnvKeyProvider struct{}
+
+// GetKey 实现 KeyProvider 接口
+func (EnvKeyProvider) GetKey(_ context.Context, name string) ([]byte, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil, fmt.Errorf("environment variable %s is empty", name)
+	}
+	return []byte(value), nil
+}
+
+// FileKeyProvider 从目录中读取密钥文件，name 为相对 Dir 的文件名
+type FileKeyProvider struct {
+	Dir string
+}
+
+// GetKey 实现 KeyProvider 接口
+func (p FileKeyProvider) GetKey(_ context.Context, name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(p.Dir, name))
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("key file %s is empty", name)
+	}
+	return data, nil
+}
+
+// CachedKeyProvider 带缓存的 KeyProvider，缓存过期后重新从底层 provider 获取，实现密钥定期刷新
+// 刷新失败时继续使用旧密钥，避免密钥服务短暂不可用影响加解密。
+// 获取底层密钥时不持有锁，其他已缓存的密钥不受影响，同一密钥的并发获取只请求一次底层 provider
+type CachedKeyProvider struct {
+	provider KeyProvider
+	ttl      time.Duration
+	group    singleflight.Group
+	mu       sync.RWMutex
+	entries  map[string]cachedKey
+}
+
+type cachedKey struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewCachedKeyProvider 创建带缓存的 KeyProvider，ttl 为缓存有效期
+func NewCachedKeyProvider(provider KeyProvider, ttl time.Duration) *CachedKeyProvider {
+	return &CachedKeyProvider{
+		provider: provider,
+		ttl:      ttl,
+		entries:  make(map[string]cachedKey),
+	}
+}
+
+// GetKey 实现 KeyProvider 接口
+func (p *CachedKeyProvider) GetKey(ctx context.Context, name string) ([]byte, error) {
+	p.mu.RLock()
+	entry, ok := p.entries[name]
+	p.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	// 底层请求不随单个调用方的 ctx 取消，避免影响复用同一请求的其他调用方
+	ch := p.group.DoChan(name, func() (any, error) {
+		value, err := p.provider.GetKey(context.WithoutCancel(ctx), name)
+		if err != nil {
+			return nil, err
+		}
+		p.mu.Lock()
+		p.entries[name] = cachedKey{value: value, expiresAt: time.Now().Add(p.ttl)}
+		p.mu.Unlock()
+		return value, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			if ok {
+				return entry.value, nil
+			}
+			return nil, res.Err
+		}
+		return res.Val.([]byte), nil
+	case <-ctx.Done():
+		if ok {
+			return entry.value, nil
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Invalidate 清除指定密钥的缓存，下次获取时强制刷新
+func (p *CachedKeyProvider) Invalidate(name string) {
+	p.mu.Lock()
+	delete(p.entries, name)
+	p.mu.Unlock()
+}
+
+// NewAESFromProvider 从 KeyProvider 获取密钥创建AES加密器
+func NewAESFromProvider(ctx context.Context, provider KeyProvider, keyName string) (*AES, error) {
+	key, err := provider.GetKey(ctx, keyName)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return nil, errors.New("key is empty")
+	}
+	return NewAES(string(key))
+}
+
+// NewRSAFromProvider 从 KeyProvider 获取PEM密钥创建RSA加密器，密钥名为空时忽略对应密钥
+func NewRSAFromProvider(ctx context.Context, provider KeyProvider, privateKeyName, publicKeyName string) (*RSA, error) {
+	var privateKeyPEM, publicKeyPEM []byte
+	var err error
+	if privateKeyName != "" {
+		if privateKeyPEM, err = provider.GetKey(ctx, privateKeyName); err != nil {
+			return nil, err
+		}
+	}
+	if publicKeyName != "" {
+		if publicKeyPEM, err = provider.GetKey(ctx, publicKeyName); err != nil {
+			return nil, err
+		}
+	}
+	return newRSAFromPEM(privateKeyPEM, publicKeyPEM)
+}
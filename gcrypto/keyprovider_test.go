@@ -0,0 +1,167 @@
+package gcrypto
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewAESFromFile(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "aes.key")
+	if err := os.WriteFile(keyFile, []byte("12345678901234567890123456789012\n"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	fromFile, err := NewAESFromFile(keyFile)
+	if err != nil {
+		t.Fatalf("NewAESFromFile failed: %v", err)
+	}
+	inline, _ := NewAES("12345678901234567890123456789012")
+
+	ciphertext, err := fromFile.EncryptString("hello")
+	if err != nil {
+		t.Fatalf("EncryptString failed: %v", err)
+	}
+	plaintext, err := inline.DecryptString(ciphertext)
+	if err != nil || plaintext != "hello" {
+		t.Fatalf("DecryptString failed: %v, %s", err, plaintext)
+	}
+
+	if _, err := NewAESFromFile(filepath.Join(dir, "missing.key")); err == nil {
+		t.Fatal("NewAESFromFile should fail for missing file")
+	}
+}
+
+func TestNewRSAFromFile(t *testing.T) {
+	privateKey, publicKey, err := GenerateRSAKeyPair(2048)
+	if err != nil {
+		t.Fatalf("GenerateRSAKeyPair failed: %v", err)
+	}
+	publicKeyPEM, _ := PublicKeyToPEM(publicKey)
+
+	dir := t.TempDir()
+	privateKeyFile := filepath.Join(dir, "private.pem")
+	publicKeyFile := filepath.Join(dir, "public.pem")
+	_ = os.WriteFile(privateKeyFile, PrivateKeyToPEM(privateKey), 0600)
+	_ = os.WriteFile(publicKeyFile, publicKeyPEM, 0600)
+
+	encryptor, err := NewRSAFromFile("", publicKeyFile)
+	if err != nil {
+		t.Fatalf("NewRSAFromFile failed: %v", err)
+	}
+	decryptor, err := NewRSAFromFile(privateKeyFile, "")
+	if err != nil {
+		t.Fatalf("NewRSAFromFile failed: %v", err)
+	}
+
+	ciphertext, err := encryptor.EncryptString("hello")
+	if err != nil {
+		t.Fatalf("EncryptString failed: %v", err)
+	}
+	plaintext, err := decryptor.DecryptString(ciphertext)
+	if err != nil || plaintext != "hello" {
+		t.Fatalf("DecryptString failed: %v, %s", err, plaintext)
+	}
+
+	if _, err := NewRSAFromFile("", ""); err == nil {
+		t.Fatal("NewRSAFromFile should fail without keys")
+	}
+}
+
+func TestCachedKeyProvider(t *testing.T) {
+	calls := 0
+	fail := false
+	provider := NewCachedKeyProvider(KeyProviderFunc(func(_ context.Context, name string) ([]byte, error) {
+		calls++
+		if fail {
+			return nil, errors.New("provider unavailable")
+		}
+		return []byte(name + "-v" + string(rune('0'+calls))), nil
+	}), 50*time.Millisecond)
+
+	ctx := context.Background()
+	key, _ := provider.GetKey(ctx, "aes")
+	if string(key) != "aes-v1" {
+		t.Fatalf("unexpected key: %s", key)
+	}
+	key, _ = provider.GetKey(ctx, "aes")
+	if string(key) != "aes-v1" || calls != 1 {
+		t.Fatalf("key should be cached, got %s after %d calls", key, calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	key, _ = provider.GetKey(ctx, "aes")
+	if string(key) != "aes-v2" {
+		t.Fatalf("key should be refreshed, got %s", key)
+	}
+
+	fail = true
+	provider.Invalidate("other")
+	time.Sleep(60 * time.Millisecond)
+	key, err := provider.GetKey(ctx, "aes")
+	if err != nil || string(key) != "aes-v2" {
+		t.Fatalf("stale key should be kept on refresh failure, got %s, %v", key, err)
+	}
+	if _, err := provider.GetKey(ctx, "other"); err == nil {
+		t.Fatal("GetKey should fail when provider fails without cache")
+	}
+}
+
+func TestCachedKeyProviderConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	provider := NewCachedKeyProvider(KeyProviderFunc(func(_ context.Context, name string) ([]byte, error) {
+		calls.Add(1)
+		if name == "slow" {
+			<-release
+		}
+		return []byte(name), nil
+	}), time.Minute)
+
+	ctx := context.Background()
+	if _, err := provider.GetKey(ctx, "cached"); err != nil {
+		t.Fatalf("GetKey failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if key, err := provider.GetKey(ctx, "slow"); err != nil || string(key) != "slow" {
+				t.Errorf("GetKey failed: %v, %s", err, key)
+			}
+		}()
+	}
+
+	// 慢请求进行中不影响已缓存的密钥
+	done := make(chan struct{})
+	go func() {
+		provider.GetKey(ctx, "cached")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cached key should not wait for a slow fetch")
+	}
+
+	// 调用方 ctx 取消时立即返回
+	cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := provider.GetKey(cancelCtx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetKey should return ctx error, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+	if calls.Load() != 2 {
+		t.Fatalf("concurrent fetches of the same key should be merged, calls = %d", calls.Load())
+	}
+}
@@ -0,0 +1,145 @@
+package gcrypto
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordAlgorithm 密码哈希算法
+type PasswordAlgorithm string
+
+const (
+	PasswordAlgorithmArgon2id PasswordAlgorithm = "argon2id"
+	PasswordAlgorithmBcrypt   PasswordAlgorithm = "bcrypt"
+)
+
+// PasswordPolicy 密码哈希策略，零值字段使用默认参数
+type PasswordPolicy struct {
+	Algorithm     PasswordAlgorithm // 新哈希使用的算法，默认 argon2id
+	BcryptCost    int               // bcrypt 成本，默认 bcrypt.DefaultCost
+	Argon2Memory  uint32            // argon2id 内存开销（KiB），默认 65536
+	Argon2Time    uint32            // argon2id 迭代次数，默认 1
+	Argon2Threads uint8             // argon2id 并行度，默认 4
+}
+
+// DefaultPasswordPolicy 默认密码哈希策略：argon2id，m=65536,t=1,p=4
+var DefaultPasswordPolicy = PasswordPolicy{Algorithm: PasswordAlgorithmArgon2id}
+
+// HashPassword 使用默认策略生成密码哈希
+func HashPassword(password string) (string, error) {
+	return DefaultPasswordPolicy.Hash(password)
+}
+
+// VerifyPassword 使用默认策略校验密码，校验通过且哈希参数弱于当前策略时返回重新生成的哈希，
+// 调用方应将非空的 newHash 持久化以完成哈希升级
+func VerifyPassword(hashedPassword, password string) (newHash string, err error) {
+	return DefaultPasswordPolicy.Verify(hashedPassword, password)
+}
+
+// Hash 按策略生成密码哈希
+func (p PasswordPolicy) Hash(password string) (string, error) {
+	if password == "" {
+		return "", errors.New("password is empty")
+	}
+	switch p.algorithm() {
+	case PasswordAlgorithmBcrypt:
+		return GeneratePasswordHashWithCost(password, p.bcryptCost())
+	case PasswordAlgorithmArgon2id:
+		return generateArgon2idHash(password, p.argon2idParams())
+	default:
+		return "", errors.New("unsupported password algorithm: " + string(p.Algorithm))
+	}
+}
+
+// Verify 校验密码，根据哈希前缀自动识别 bcrypt 或 argon2id。
+// 校验通过且 NeedsRehash 为 true 时按当前策略重新生成哈希并通过 newHash 返回，否则 newHash 为空。
+func (p PasswordPolicy) Verify(hashedPassword, password string) (newHash string, err error) {
+	if password == "" {
+		return "", errors.New("password is empty")
+	}
+	switch detectPasswordAlgorithm(hashedPassword) {
+	case PasswordAlgorithmBcrypt:
+		err = ComparePasswordHash(hashedPassword, password)
+	case PasswordAlgorithmArgon2id:
+		err = CompareArgon2idHash(hashedPassword, password)
+	default:
+		err = errors.New("unsupported password hash format")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if !p.NeedsRehash(hashedPassword) {
+		return "", nil
+	}
+	return p.Hash(password)
+}
+
+// NeedsRehash 判断哈希是否需要按当前策略重新生成：算法不同或参数弱于当前策略时返回 true
+func (p PasswordPolicy) NeedsRehash(hashedPassword string) bool {
+	algorithm := detectPasswordAlgorithm(hashedPassword)
+	if algorithm != p.algorithm() {
+		return true
+	}
+	switch algorithm {
+	case PasswordAlgorithmBcrypt:
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost < p.bcryptCost()
+	case PasswordAlgorithmArgon2id:
+		current, _, _, err := decodeArgon2idHash(hashedPassword)
+		if err != nil {
+			return true
+		}
+		want := p.argon2idParams()
+		return current.memory < want.memory ||
+			current.time < want.time ||
+			current.threads < want.threads ||
+			current.keyLen < want.keyLen
+	default:
+		return true
+	}
+}
+
+func (p PasswordPolicy) algorithm() PasswordAlgorithm {
+	if p.Algorithm == "" {
+		return PasswordAlgorithmArgon2id
+	}
+	return p.Algorithm
+}
+
+func (p PasswordPolicy) bcryptCost() int {
+	if p.BcryptCost == 0 {
+		return bcrypt.DefaultCost
+	}
+	return p.BcryptCost
+}
+
+func (p PasswordPolicy) argon2idParams() argon2idParams {
+	params := defaultArgon2idParams
+	if p.Argon2Memory > 0 {
+		params.memory = p.Argon2Memory
+	}
+	if p.Argon2Time > 0 {
+		params.time = p.Argon2Time
+	}
+	if p.Argon2Threads > 0 {
+		params.threads = p.Argon2Threads
+	}
+	return params
+}
+
+// detectPasswordAlgorithm 根据哈希前缀识别算法
+func detectPasswordAlgorithm(hashedPassword string) PasswordAlgorithm {
+	switch {
+	case strings.HasPrefix(hashedPassword, "$argon2id$"):
+		return PasswordAlgorithmArgon2id
+	case strings.HasPrefix(hashedPassword, "$2a$"),
+		strings.HasPrefix(hashedPassword, "$2b$"),
+		strings.HasPrefix(hashedPassword, "$2y$"):
+		return PasswordAlgorithmBcrypt
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,83 @@
+package gcrypto
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPasswordAndVerify(t *testing.T) {
+	hash, err := HashPassword("password")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=1,p=4$") {
+		t.Fatalf("unexpected hash format: %s", hash)
+	}
+
+	newHash, err := VerifyPassword(hash, "password")
+	if err != nil {
+		t.Fatalf("VerifyPassword failed: %v", err)
+	}
+	if newHash != "" {
+		t.Fatal("hash with current parameters should not be upgraded")
+	}
+
+	if _, err := VerifyPassword(hash, "wrong"); err == nil {
+		t.Fatal("VerifyPassword should fail for wrong password")
+	}
+	if _, err := HashPassword(""); err == nil {
+		t.Fatal("HashPassword should fail for empty password")
+	}
+}
+
+func TestPasswordPolicy_UpgradeOnVerify(t *testing.T) {
+	// bcrypt 哈希升级为 argon2id
+	bcryptHash, err := GeneratePasswordHashWithCost("password", bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GeneratePasswordHashWithCost failed: %v", err)
+	}
+	newHash, err := VerifyPassword(bcryptHash, "password")
+	if err != nil {
+		t.Fatalf("VerifyPassword failed: %v", err)
+	}
+	if !strings.HasPrefix(newHash, "$argon2id$") {
+		t.Fatalf("bcrypt hash should be upgraded to argon2id, got %s", newHash)
+	}
+
+	// bcrypt 成本提升
+	bcryptPolicy := PasswordPolicy{Algorithm: PasswordAlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1}
+	if !bcryptPolicy.NeedsRehash(bcryptHash) {
+		t.Fatal("lower bcrypt cost should need rehash")
+	}
+	newHash, err = bcryptPolicy.Verify(bcryptHash, "password")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if cost, _ := bcrypt.Cost([]byte(newHash)); cost != bcrypt.MinCost+1 {
+		t.Fatalf("unexpected upgraded cost: %d", cost)
+	}
+
+	// argon2id 参数增强
+	strongPolicy := PasswordPolicy{Argon2Time: 2, Argon2Memory: 32 * 1024}
+	argonHash, _ := HashPassword("password")
+	if !strongPolicy.NeedsRehash(argonHash) {
+		t.Fatal("higher argon2id time should need rehash")
+	}
+	newHash, err = strongPolicy.Verify(argonHash, "password")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !strings.HasPrefix(newHash, "$argon2id$v=19$m=32768,t=2,p=4$") {
+		t.Fatalf("unexpected upgraded hash: %s", newHash)
+	}
+
+	// 新参数生成的哈希可被校验
+	if _, err := strongPolicy.Verify(newHash, "password"); err != nil {
+		t.Fatalf("Verify upgraded hash failed: %v", err)
+	}
+	if _, err := strongPolicy.Verify("plain-text", "password"); err == nil {
+		t.Fatal("Verify should fail for unsupported hash format")
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"os"
 )
 
@@ -18,10 +20,30 @@ const (
 	RSAPublicKeyEnv  = "GOLIB_RSA_PUBLIC_KEY"
 )
 
+// RSAFormat RSA密文格式
+type RSAFormat int
+
+const (
+	// RSAFormatLegacy 默认格式：OAEP分块密文直接拼接，与旧版本互通
+	RSAFormatLegacy RSAFormat = iota
+	// RSAFormatFramed 带帧头的密文格式：magic(4) + 版本(1) + 密钥字节数(2) + 分块数(4) + 分块密文，
+	// 密文自描述，解密时可校验密钥长度和完整性。旧版本无法解密该格式，需通过 WithFormat 显式启用，
+	// 应在所有解密方升级后再切换
+	RSAFormatFramed
+)
+
+// 帧格式常量
+const (
+	rsaFrameMagic      = "GRSA"
+	rsaFrameVersion    = 1
+	rsaFrameHeaderSize = len(rsaFrameMagic) + 1 + 2 + 4
+)
+
 // RSA RSA加密器
 type RSA struct {
 	privateKey *rsa.PrivateKey
 	publicKey  *rsa.PublicKey
+	format     RSAFormat
 }
 
 // NewRSA 从私钥和公钥创建RSA加密器
@@ -74,6 +96,48 @@ func NewRSA(privateKeyPEM, publicKeyPEM string) (*RSA, error) {
 	}, nil
 }
 
+// NewRSAFromFile 从PEM文件创建RSA加密器，文件路径为空时忽略对应密钥
+// 与 NewRSA 不同，不会回退到环境变量
+func NewRSAFromFile(privateKeyFile, publicKeyFile string) (*RSA, error) {
+	var privateKeyPEM, publicKeyPEM []byte
+	var err error
+	if privateKeyFile != "" {
+		if privateKeyPEM, err = os.ReadFile(privateKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	if publicKeyFile != "" {
+		if publicKeyPEM, err = os.ReadFile(publicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	return newRSAFromPEM(privateKeyPEM, publicKeyPEM)
+}
+
+// newRSAFromPEM 从PEM数据创建RSA加密器，为空的密钥会被忽略
+func newRSAFromPEM(privateKeyPEM, publicKeyPEM []byte) (*RSA, error) {
+	r := &RSA{}
+	if len(privateKeyPEM) > 0 {
+		privateKey, err := parsePrivateKeyPEM(privateKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		r.privateKey = privateKey
+		r.publicKey = &privateKey.PublicKey
+	}
+	if len(publicKeyPEM) > 0 {
+		publicKey, err := parsePublicKeyPEM(publicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		r.publicKey = publicKey
+	}
+	if r.privateKey == nil && r.publicKey == nil {
+		return nil, errors.New("at least one key must be provided")
+	}
+	return r, nil
+}
+
 // NewRSAFromPrivateKey 从私钥创建RSA加密器（私钥包含公钥信息）
 func NewRSAFromPrivateKey(privateKey *rsa.PrivateKey) *RSA {
 	return &RSA{
@@ -118,7 +182,16 @@ func PublicKeyToPEM(publicKey *rsa.PublicKey) ([]byte, error) {
 	return publicKeyPEM, nil
 }
 
+// WithFormat 返回 Encrypt 使用指定密文格式的RSA加密器副本，默认为 RSAFormatLegacy。
+// Decrypt 不受格式影响，始终同时支持两种格式，因此可以先升级解密方，再切换加密方的格式。
+func (r *RSA) WithFormat(format RSAFormat) *RSA {
+	c := *r
+	c.format = format
+	return &c
+}
+
 // Encrypt 使用公钥加密数据（使用OAEP填充）
+// 默认输出旧格式密文，通过 WithFormat(RSAFormatFramed) 输出带帧头的密文
 func (r *RSA) Encrypt(plaintext []byte) ([]byte, error) {
 	if r.publicKey == nil {
 		return nil, errors.New("public key is required for encryption")
@@ -150,10 +223,19 @@ func (r *RSA) Encrypt(plaintext []byte) ([]byte, error) {
 		ciphertext = append(ciphertext, encryptedChunk...)
 	}
 
-	return ciphertext, nil
+	if r.format != RSAFormatFramed {
+		return ciphertext, nil
+	}
+	keySize := r.publicKey.Size()
+	header := make([]byte, rsaFrameHeaderSize, rsaFrameHeaderSize+len(ciphertext))
+	copy(header, rsaFrameMagic)
+	header[4] = rsaFrameVersion
+	binary.BigEndian.PutUint16(header[5:7], uint16(keySize))
+	binary.BigEndian.PutUint32(header[7:11], uint32(len(ciphertext)/keySize))
+	return append(header, ciphertext...), nil
 }
 
-// Decrypt 使用私钥解密数据
+// Decrypt 使用私钥解密数据，自动识别带帧头的密文和旧格式密文
 func (r *RSA) Decrypt(ciphertext []byte) ([]byte, error) {
 	if r.privateKey == nil {
 		return nil, errors.New("private key is required for decryption")
@@ -163,6 +245,13 @@ func (r *RSA) Decrypt(ciphertext []byte) ([]byte, error) {
 	if len(ciphertext) == 0 {
 		return nil, errors.New("ciphertext is empty")
 	}
+	body, framed, err := parseRSAFrame(ciphertext, blockSize)
+	if err != nil {
+		return nil, err
+	}
+	if framed {
+		ciphertext = body
+	}
 	if len(ciphertext)%blockSize != 0 {
 		return nil, errors.New("ciphertext length must be a multiple of key size")
 	}
@@ -230,6 +319,28 @@ func (r *RSA) Verify(data []byte, signature []byte) error {
 	return rsa.VerifyPKCS1v15(r.publicKey, crypto.SHA256, hashed[:], signature)
 }
 
+// parseRSAFrame 解析带帧头的密文，返回分块密文部分。
+// 不以 magic 开头或长度与帧头不一致时视为旧格式密文，framed 返回 false；
+// 帧头完整但密钥长度或版本不匹配时返回错误。
+func parseRSAFrame(ciphertext []byte, keySize int) (body []byte, framed bool, err error) {
+	if len(ciphertext) < rsaFrameHeaderSize || string(ciphertext[:len(rsaFrameMagic)]) != rsaFrameMagic {
+		return nil, false, nil
+	}
+	frameKeySize := int(binary.BigEndian.Uint16(ciphertext[5:7]))
+	chunkCount := int(binary.BigEndian.Uint32(ciphertext[7:11]))
+	body = ciphertext[rsaFrameHeaderSize:]
+	if frameKeySize == 0 || chunkCount == 0 || len(body) != frameKeySize*chunkCount {
+		return nil, false, nil
+	}
+	if ciphertext[4] != rsaFrameVersion {
+		return nil, false, fmt.Errorf("unsupported RSA ciphertext version: %d", ciphertext[4])
+	}
+	if frameKeySize != keySize {
+		return nil, false, fmt.Errorf("ciphertext was encrypted with a %d-bit key, but the private key is %d-bit", frameKeySize*8, keySize*8)
+	}
+	return body, true, nil
+}
+
 // parsePrivateKeyPEM 解析PEM格式的私钥
 func parsePrivateKeyPEM(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode(privateKeyPEM)
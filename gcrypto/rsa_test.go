@@ -2,6 +2,7 @@ package gcrypto
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -310,3 +311,49 @@ func TestRSA_DecryptWithoutPrivateKey(t *testing.T) {
 		t.Fatal("Expected error when decrypting without private key")
 	}
 }
+
+func TestRSA_FramedFormat(t *testing.T) {
+	privateKey, _, err := GenerateRSAKeyPair(2048)
+	if err != nil {
+		t.Fatalf("GenerateRSAKeyPair failed: %v", err)
+	}
+	r := NewRSAFromPrivateKey(privateKey)
+	plaintext := []byte(strings.Repeat("framed", 80))
+
+	framed, err := r.WithFormat(RSAFormatFramed).Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt framed failed: %v", err)
+	}
+	if string(framed[:4]) != "GRSA" || len(framed) != rsaFrameHeaderSize+3*256 {
+		t.Fatalf("unexpected framed ciphertext header or length: %d", len(framed))
+	}
+
+	// 默认输出旧格式，保证未升级的对端可以解密
+	legacy, err := r.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if len(legacy) != 3*256 {
+		t.Fatalf("unexpected legacy ciphertext length: %d", len(legacy))
+	}
+
+	// 解密不受格式影响，两种格式都可解密
+	for _, decrypter := range []*RSA{r, r.WithFormat(RSAFormatFramed)} {
+		for _, ciphertext := range [][]byte{framed, legacy} {
+			decrypted, err := decrypter.Decrypt(ciphertext)
+			if err != nil {
+				t.Fatalf("Decrypt failed: %v", err)
+			}
+			if string(decrypted) != string(plaintext) {
+				t.Fatal("Decrypted text doesn't match")
+			}
+		}
+	}
+
+	// 密钥长度不一致时给出明确错误
+	otherKey, _, _ := GenerateRSAKeyPair(1024)
+	_, err = NewRSAFromPrivateKey(otherKey).Decrypt(framed)
+	if err == nil || !strings.Contains(err.Error(), "2048-bit") {
+		t.Fatalf("expected key size mismatch error, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package gcrypto
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secret 从密钥管理服务获取的密钥
+type Secret struct {
+	Path    string            // 密钥路径
+	Data    map[string]string // 密钥内容，字段名 → 值
+	Version int               // 密钥版本，后端不支持时为 0
+	TTL     time.Duration     // 后端建议的缓存时长（如 Vault lease_duration），0 表示使用客户端配置
+}
+
+// Get 获取字段值，字段不存在时返回空字符串
+func (s *Secret) Get(field string) string {
+	if s == nil {
+		return ""
+	}
+	return s.Data[field]
+}
+
+// SecretsClient 密钥客户端，按路径获取密钥，配置组件和 KeyProvider 通过它统一从 Vault 兼容的后端解析密钥，
+// Vault KV v2 的 HTTP 实现见 gcrypto/secrets 包
+type SecretsClient interface {
+	GetSecret(ctx context.Context, path string) (*Secret, error)
+}
+
+// SecretsClientFunc 函数形式的 SecretsClient
+type SecretsClientFunc func(ctx context.Context, path string) (*Secret, error)
+
+// GetSecret 实现 SecretsClient 接口
+func (f SecretsClientFunc) GetSecret(ctx context.Context, path string) (*Secret, error) {
+	return f(ctx, path)
+}
+
+// ---------------------------------------------------------------------------
+// CachedSecretsClient —— TTL 缓存与提前刷新
+// ---------------------------------------------------------------------------
+
+const (
+	defaultSecretTTL          = 5 * time.Minute
+	defaultSecretRefreshAhead = 0.2
+)
+
+// CachedSecretsOption CachedSecretsClient 选项
+type CachedSecretsOption func(*CachedSecretsClient)
+
+// WithSecretTTL 设置缓存有效期，后端返回的 TTL 更短时以后端为准，默认 5 分钟
+func WithSecretTTL(ttl time.Duration) CachedSecretsOption {
+	return func(c *CachedSecretsClient) {
+		c.ttl = ttl
+	}
+}
+
+// WithSecretRefreshAhead 设置提前刷新比例，缓存剩余有效期低于 ratio*TTL 时返回缓存并在后台刷新，
+// 避免过期瞬间的请求阻塞在密钥服务上；0 表示不提前刷新，默认 0.2
+func WithSecretRefreshAhead(ratio float64) CachedSecretsOption {
+	return func(c *CachedSecretsClient) {
+		c.refreshAhead = ratio
+	}
+}
+
+// CachedSecretsClient 带缓存的 SecretsClient。
+// 缓存有效时直接返回；进入提前刷新窗口时返回缓存并在后台刷新；过期后同步获取。
+// 获取失败时若有旧值则继续使用旧值，避免密钥服务短暂不可用影响业务。同一路径的并发获取只请求一次后端。
+type CachedSecretsClient struct {
+	backend      SecretsClient
+	ttl          time.Duration
+	refreshAhead float64
+
+	mu       sync.Mutex
+	entries  map[string]*cachedSecret
+	inflight map[string]*secretCall
+}
+
+type cachedSecret struct {
+	secret    *Secret
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+type secretCall struct {
+	done   chan struct{}
+	secret *Secret
+	err    error
+}
+
+// NewCachedSecretsClient 创建带缓存的 SecretsClient
+func NewCachedSecretsClient(backend SecretsClient, opts ...CachedSecretsOption) *CachedSecretsClient {
+	c := &CachedSecretsClient{
+		backend:      backend,
+		ttl:          defaultSecretTTL,
+		refreshAhead: defaultSecretRefreshAhead,
+		entries:      make(map[string]*cachedSecret),
+		inflight:     make(map[string]*secretCall),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.ttl <= 0 {
+		c.ttl = defaultSecretTTL
+	}
+	if c.refreshAhead < 0 || c.refreshAhead >= 1 {
+		c.refreshAhead = 0
+	}
+	return c
+}
+
+// GetSecret 实现 SecretsClient 接口
+func (c *CachedSecretsClient) GetSecret(ctx context.Context, path string) (*Secret, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	if ok && now.Before(entry.expiresAt) {
+		if c.inRefreshWindow(entry, now) {
+			c.startCallLocked(context.WithoutCancel(ctx), path)
+		}
+		c.mu.Unlock()
+		return entry.secret, nil
+	}
+	call := c.startCallLocked(ctx, path)
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		if ok {
+			return entry.secret, nil
+		}
+		return nil, ctx.Err()
+	}
+	if call.err != nil {
+		if ok {
+			return entry.secret, nil
+		}
+		return nil, call.err
+	}
+	return call.secret, nil
+}
+
+// Invalidate 清除指定路径的缓存，下次获取时强制刷新
+func (c *CachedSecretsClient) Invalidate(path string) {
+	c.mu.Lock()
+	delete(c.entries, path)
+	c.mu.Unlock()
+}
+
+// inRefreshWindow 判断缓存是否进入提前刷新窗口
+func (c *CachedSecretsClient) inRefreshWindow(entry *cachedSecret, now time.Time) bool {
+	if c.refreshAhead <= 0 {
+		return false
+	}
+	window := time.Duration(float64(entry.expiresAt.Sub(entry.fetchedAt)) * c.refreshAhead)
+	return entry.expiresAt.Sub(now) <= window
+}
+
+// startCallLocked 发起后端请求，同一路径已有请求进行中时复用，调用方需持有锁
+func (c *CachedSecretsClient) startCallLocked(ctx context.Context, path string) *secretCall {
+	if call, ok := c.inflight[path]; ok {
+		return call
+	}
+	call := &secretCall{done: make(chan struct{})}
+	c.inflight[path] = call
+	go func() {
+		// 后端请求不随单个调用方的 ctx 取消，避免影响复用同一请求的其他调用方
+		secret, err := c.backend.GetSecret(context.WithoutCancel(ctx), path)
+		if err == nil && secret == nil {
+			err = fmt.Errorf("secret %s: backend returned nil secret", path)
+		}
+		c.mu.Lock()
+		if err == nil {
+			ttl := c.ttl
+			if secret.TTL > 0 && secret.TTL < ttl {
+				ttl = secret.TTL
+			}
+			now := time.Now()
+			c.entries[path] = &cachedSecret{secret: secret, fetchedAt: now, expiresAt: now.Add(ttl)}
+		}
+		delete(c.inflight, path)
+		c.mu.Unlock()
+		call.secret, call.err = secret, err
+		close(call.done)
+	}()
+	return call
+}
+
+// ---------------------------------------------------------------------------
+// SecretKeyProvider —— 将 SecretsClient 适配为 KeyProvider
+// ---------------------------------------------------------------------------
+
+// SecretKeyProvider 从 SecretsClient 获取密钥的 KeyProvider，密钥名格式为 "<path>#<field>"，省略 field 时使用 DefaultField
+type SecretKeyProvider struct {
+	Client       SecretsClient
+	DefaultField string // 默认字段名，默认 key
+}
+
+// GetKey 实现 KeyProvider 接口
+func (p *SecretKeyProvider) GetKey(ctx context.Context, name string) ([]byte, error) {
+	path, field, _ := strings.Cut(name, "#")
+	if field == "" {
+		field = p.DefaultField
+	}
+	if field == "" {
+		field = "key"
+	}
+	secret, err := p.Client.GetSecret(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	value := secret.Get(field)
+	if value == "" {
+		return nil, fmt.Errorf("secret %s has no field %s", path, field)
+	}
+	return []byte(value), nil
+}
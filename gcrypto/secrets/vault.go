@@ -0,0 +1,115 @@
+// Package secrets 提供 gcrypto.SecretsClient 的 HTTP 实现，依赖 ghttp，与 gcrypto 的加解密能力分开以免引入网络依赖
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/morehao/golib/gcrypto"
+	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/protocol"
+	"github.com/morehao/golib/protocol/ghttp"
+)
+
+// VaultConfig VaultClient 配置
+type VaultConfig struct {
+	Address   string        `yaml:"address"`   // 服务地址，例如 https://vault.example.com:8200
+	Token     string        `yaml:"token"`     // 访问令牌
+	Mount     string        `yaml:"mount"`     // KV v2 引擎挂载路径，默认 secret
+	Namespace string        `yaml:"namespace"` // Vault 企业版命名空间，可选
+	Timeout   time.Duration `yaml:"timeout"`   // 请求超时时间，默认 5 秒
+	MaxRetry  int           `yaml:"max_retry"` // 请求失败重试次数
+}
+
+// VaultClient 基于 ghttp 的 gcrypto.SecretsClient 实现，读取 Vault KV v2 兼容接口：
+// GET {Address}/v1/{Mount}/data/{path}，请求头携带 X-Vault-Token。
+// 请求不输出 ghttp 的请求/响应日志，避免密钥内容落入日志。
+type VaultClient struct {
+	cfg    VaultConfig
+	client *ghttp.Client
+}
+
+type vaultSecretResponse struct {
+	LeaseDuration int `json:"lease_duration"`
+	Data          struct {
+		Data     map[string]any `json:"data"`
+		Metadata struct {
+			Version int `json:"version"`
+		} `json:"metadata"`
+	} `json:"data"`
+}
+
+// NewVaultClient 创建 Vault KV v2 密钥客户端
+func NewVaultClient(cfg VaultConfig) (*VaultClient, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("secrets address is required")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	client := ghttp.NewClient(&protocol.HttpClientConfig{
+		Module:   "secrets",
+		Host:     strings.TrimRight(cfg.Address, "/"),
+		Timeout:  cfg.Timeout,
+		MaxRetry: cfg.MaxRetry,
+	})
+	return &VaultClient{cfg: cfg, client: client}, nil
+}
+
+// GetSecret 实现 gcrypto.SecretsClient 接口，非字符串字段按 JSON 文本格式化为字符串
+func (c *VaultClient) GetSecret(ctx context.Context, path string) (*gcrypto.Secret, error) {
+	path = strings.Trim(path, "/")
+	headers := map[string]string{"X-Vault-Token": c.cfg.Token}
+	if c.cfg.Namespace != "" {
+		headers["X-Vault-Namespace"] = c.cfg.Namespace
+	}
+	reqPath := fmt.Sprintf("/v1/%s/data/%s", strings.Trim(c.cfg.Mount, "/"), path)
+
+	var resp vaultSecretResponse
+	result, err := c.client.Get(context.WithValue(ctx, glog.KeySkipLog, true), reqPath, ghttp.RequestOption{Headers: headers})
+	if err != nil {
+		if result != nil && result.HttpCode == http.StatusNotFound {
+			return nil, fmt.Errorf("secret %s not found", path)
+		}
+		return nil, fmt.Errorf("get secret %s: %w", path, err)
+	}
+	if err := result.JSON(&resp); err != nil {
+		return nil, fmt.Errorf("decode secret %s: %w", path, err)
+	}
+
+	secret := &gcrypto.Secret{
+		Path:    path,
+		Data:    make(map[string]string, len(resp.Data.Data)),
+		Version: resp.Data.Metadata.Version,
+		TTL:     time.Duration(resp.LeaseDuration) * time.Second,
+	}
+	for field, value := range resp.Data.Data {
+		switch v := value.(type) {
+		case string:
+			secret.Data[field] = v
+		case nil:
+			secret.Data[field] = ""
+		default:
+			b, _ := json.Marshal(v)
+			secret.Data[field] = string(b)
+		}
+	}
+	return secret, nil
+}
+
+// NewVaultKeyProvider 创建从 Vault KV v2 获取密钥的 gcrypto.KeyProvider，密钥名格式为 "<path>#<field>"，
+// 省略 field 时使用 defaultField，为空时为 key。需要缓存时使用 gcrypto.NewCachedKeyProvider 包装
+func NewVaultKeyProvider(cfg VaultConfig, defaultField string) (*gcrypto.SecretKeyProvider, error) {
+	client, err := NewVaultClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &gcrypto.SecretKeyProvider{Client: client, DefaultField: defaultField}, nil
+}
@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/gcrypto"
+)
+
+func newVaultServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/app/db":
+			w.Write([]byte(`{"lease_duration":60,"data":{"data":{"password":"p@ss","port":3306},"metadata":{"version":3}}}`))
+		case "/v1/secret/data/app/crypto":
+			w.Write([]byte(`{"data":{"data":{"key":"12345678901234567890123456789012","other":"x"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestVaultClient(t *testing.T) {
+	srv := newVaultServer()
+	defer srv.Close()
+
+	client, err := NewVaultClient(VaultConfig{Address: srv.URL, Token: "token", Mount: "kv"})
+	if err != nil {
+		t.Fatalf("NewVaultClient failed: %v", err)
+	}
+	secret, err := client.GetSecret(context.Background(), "/app/db")
+	if err != nil {
+		t.Fatalf("GetSecret failed: %v", err)
+	}
+	if secret.Get("password") != "p@ss" || secret.Get("port") != "3306" || secret.Version != 3 || secret.TTL != time.Minute {
+		t.Fatalf("unexpected secret: %+v", secret)
+	}
+
+	if _, err := client.GetSecret(context.Background(), "app/missing"); err == nil {
+		t.Fatal("GetSecret should fail for missing secret")
+	}
+
+	provider := &gcrypto.SecretKeyProvider{Client: client}
+	key, err := provider.GetKey(context.Background(), "app/db#password")
+	if err != nil || string(key) != "p@ss" {
+		t.Fatalf("SecretKeyProvider.GetKey failed: %v, %s", err, key)
+	}
+	if _, err := provider.GetKey(context.Background(), "app/db"); err == nil {
+		t.Fatal("GetKey should fail for missing default field")
+	}
+
+	if _, err := NewVaultClient(VaultConfig{}); err == nil {
+		t.Fatal("NewVaultClient should fail without address")
+	}
+}
+
+func TestVaultKeyProvider(t *testing.T) {
+	srv := newVaultServer()
+	defer srv.Close()
+
+	provider, err := NewVaultKeyProvider(VaultConfig{Address: srv.URL, Token: "token"}, "")
+	if err != nil {
+		t.Fatalf("NewVaultKeyProvider failed: %v", err)
+	}
+	aesCrypto, err := gcrypto.NewAESFromProvider(context.Background(), provider, "app/crypto")
+	if err != nil {
+		t.Fatalf("NewAESFromProvider failed: %v", err)
+	}
+	ciphertext, err := aesCrypto.EncryptString("hello")
+	if err != nil {
+		t.Fatalf("EncryptString failed: %v", err)
+	}
+	if plaintext, err := aesCrypto.DecryptString(ciphertext); err != nil || plaintext != "hello" {
+		t.Fatalf("DecryptString failed: %v, %s", err, plaintext)
+	}
+
+	value, err := provider.GetKey(context.Background(), "app/crypto#other")
+	if err != nil || string(value) != "x" {
+		t.Fatalf("GetKey failed: %v, %s", err, value)
+	}
+	if _, err := provider.GetKey(context.Background(), "app/missing"); err == nil {
+		t.Fatal("GetKey should fail for missing secret")
+	}
+}
@@ -0,0 +1,91 @@
+package gcrypto
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachedSecretsClient(t *testing.T) {
+	var calls atomic.Int32
+	var fail atomic.Bool
+	backend := SecretsClientFunc(func(ctx context.Context, path string) (*Secret, error) {
+		n := calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		if fail.Load() {
+			return nil, errors.New("backend unavailable")
+		}
+		return &Secret{Path: path, Data: map[string]string{"key": path}, Version: int(n)}, nil
+	})
+	client := NewCachedSecretsClient(backend, WithSecretTTL(100*time.Millisecond), WithSecretRefreshAhead(0.5))
+
+	// 并发获取同一路径只请求一次后端
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetSecret(context.Background(), "a"); err != nil {
+				t.Errorf("GetSecret failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("backend calls = %d, want 1", calls.Load())
+	}
+
+	// 进入提前刷新窗口：返回缓存并在后台刷新
+	time.Sleep(60 * time.Millisecond)
+	secret, _ := client.GetSecret(context.Background(), "a")
+	if secret.Version != 1 {
+		t.Fatalf("refresh-ahead should return cached secret, got version %d", secret.Version)
+	}
+	time.Sleep(30 * time.Millisecond)
+	secret, _ = client.GetSecret(context.Background(), "a")
+	if secret.Version != 2 {
+		t.Fatalf("background refresh should update secret, got version %d", secret.Version)
+	}
+
+	// 过期后后端失败时继续使用旧值
+	fail.Store(true)
+	time.Sleep(120 * time.Millisecond)
+	secret, err := client.GetSecret(context.Background(), "a")
+	if err != nil || secret.Version != 2 {
+		t.Fatalf("stale secret should be returned on failure: %v, %+v", err, secret)
+	}
+
+	// 无缓存时返回错误
+	client.Invalidate("a")
+	if _, err := client.GetSecret(context.Background(), "a"); err == nil {
+		t.Fatal("GetSecret should fail without cache")
+	}
+}
+
+func TestCachedSecretsClientBackendTTL(t *testing.T) {
+	var calls atomic.Int32
+	backend := SecretsClientFunc(func(ctx context.Context, path string) (*Secret, error) {
+		calls.Add(1)
+		return &Secret{Path: path, TTL: 20 * time.Millisecond}, nil
+	})
+	client := NewCachedSecretsClient(backend, WithSecretRefreshAhead(0))
+	client.GetSecret(context.Background(), "a")
+	time.Sleep(30 * time.Millisecond)
+	client.GetSecret(context.Background(), "a")
+	if calls.Load() != 2 {
+		t.Fatalf("backend TTL should shorten cache, calls = %d", calls.Load())
+	}
+}
+
+func TestCachedSecretsClientNilSecret(t *testing.T) {
+	backend := SecretsClientFunc(func(ctx context.Context, path string) (*Secret, error) {
+		return nil, nil
+	})
+	client := NewCachedSecretsClient(backend)
+	if _, err := client.GetSecret(context.Background(), "a"); err == nil {
+		t.Fatal("GetSecret should fail when backend returns nil secret")
+	}
+}
@@ -0,0 +1,131 @@
+package gcrypto
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestURLSigner(t *testing.T, opts ...URLSignerOption) *URLSigner {
+	t.Helper()
+	signer, err := NewURLSigner([]byte("0123456789abcdef0123456789abcdef"), opts...)
+	if err != nil {
+		t.Fatalf("NewURLSigner failed: %v", err)
+	}
+	return signer
+}
+
+func TestNewURLSignerInvalid(t *testing.T) {
+	if _, err := NewURLSigner([]byte("short")); err == nil {
+		t.Fatal("expected error for short key")
+	}
+	if _, err := NewURLSigner([]byte("0123456789abcdef"), WithSignedURLParams("sig", "sig")); err == nil {
+		t.Fatal("expected error for same param names")
+	}
+}
+
+func TestURLSignerSignVerify(t *testing.T) {
+	signer := newTestURLSigner(t)
+	signed, err := signer.Sign("https://example.com/files/report%20v1.pdf?b=2&a=1", time.Minute)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if err := signer.Verify(signed); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	// 不同域名、参数顺序不影响校验
+	u, _ := url.Parse(signed)
+	reordered := "/files/report%20v1.pdf?" + reverseQuery(u.RawQuery)
+	if err := signer.Verify(reordered); err != nil {
+		t.Fatalf("Verify reordered failed: %v", err)
+	}
+
+	// 重新签名替换已有参数
+	resigned, err := signer.Sign(signed, time.Hour)
+	if err != nil {
+		t.Fatalf("Sign again failed: %v", err)
+	}
+	ru, _ := url.Parse(resigned)
+	if got := len(ru.Query()[DefaultSignedURLSignatureParam]); got != 1 {
+		t.Fatalf("expected 1 signature param, got %d", got)
+	}
+	if err := signer.Verify(resigned); err != nil {
+		t.Fatalf("Verify resigned failed: %v", err)
+	}
+}
+
+func TestURLSignerVerifyErrors(t *testing.T) {
+	signer := newTestURLSigner(t)
+	signed, err := signer.Sign("/download?id=1", time.Minute)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	expired, err := signer.SignUntil("/download?id=1", time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("SignUntil failed: %v", err)
+	}
+	other := newTestURLSigner(t)
+	other.key = []byte("fedcba9876543210fedcba9876543210")
+
+	tests := []struct {
+		name    string
+		signer  *URLSigner
+		rawURL  string
+		wantErr error
+	}{
+		{name: "missing", signer: signer, rawURL: "/download?id=1", wantErr: ErrSignedURLMissing},
+		{name: "tampered path", signer: signer, rawURL: strings.Replace(signed, "/download", "/upload", 1), wantErr: ErrSignedURLInvalid},
+		{name: "tampered query", signer: signer, rawURL: strings.Replace(signed, "id=1", "id=2", 1), wantErr: ErrSignedURLInvalid},
+		{name: "added query", signer: signer, rawURL: signed + "&admin=1", wantErr: ErrSignedURLInvalid},
+		{name: "tampered expires", signer: signer, rawURL: replaceQuery(t, expired, DefaultSignedURLExpiresParam, "9999999999"), wantErr: ErrSignedURLInvalid},
+		{name: "bad signature", signer: signer, rawURL: replaceQuery(t, signed, DefaultSignedURLSignatureParam, "!!"), wantErr: ErrSignedURLInvalid},
+		{name: "other key", signer: other, rawURL: signed, wantErr: ErrSignedURLInvalid},
+		{name: "expired", signer: signer, rawURL: expired, wantErr: ErrSignedURLExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.signer.Verify(tt.rawURL); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestURLSignerCustomParams(t *testing.T) {
+	signer := newTestURLSigner(t, WithSignedURLParams("x-exp", "x-sig"))
+	signed, err := signer.Sign("/download?expires=keep", time.Minute)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	u, _ := url.Parse(signed)
+	query := u.Query()
+	if query.Get("expires") != "keep" || query.Get("x-exp") == "" || query.Get("x-sig") == "" {
+		t.Fatalf("unexpected query: %s", u.RawQuery)
+	}
+	if err := signer.VerifyURL(u); err != nil {
+		t.Fatalf("VerifyURL failed: %v", err)
+	}
+}
+
+func reverseQuery(rawQuery string) string {
+	parts := strings.Split(rawQuery, "&")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, "&")
+}
+
+func replaceQuery(t *testing.T, rawURL, key, value string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("parse url failed: %v", err)
+	}
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,202 @@
+package gcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+)
+
+// SIV密钥派生标签，用于从AES密钥派生出 S2V 和 CTR 两把子密钥
+const (
+	sivMACKeyLabel = "gcrypto-aes-siv-mac"
+	sivCTRKeyLabel = "gcrypto-aes-siv-ctr"
+)
+
+// EncryptDeterministic 使用 AES-SIV（RFC 5297）进行确定性加密，密文 = SIV(16字节) + CTR密文
+//
+// 警告：相同密钥、相同明文和关联数据总是得到相同密文，攻击者可以据此判断两条密文的明文是否相等，
+// 并可能通过频率分析推断低熵字段（如性别、状态）的取值。仅用于必须按密文检索或关联的字段
+// （如作为查询条件的手机号、身份证号），其他场景请使用 Encrypt。
+// associatedData 参与认证但不加密，可传入表名、字段名等实现不同字段间密文隔离。
+func (a *AES) EncryptDeterministic(plaintext []byte, associatedData ...[]byte) ([]byte, error) {
+	macKey, ctrKey := a.sivKeys()
+	return sivEncrypt(macKey, ctrKey, plaintext, associatedData)
+}
+
+// DecryptDeterministic 解密 EncryptDeterministic 生成的密文，associatedData 必须与加密时一致
+func (a *AES) DecryptDeterministic(ciphertext []byte, associatedData ...[]byte) ([]byte, error) {
+	macKey, ctrKey := a.sivKeys()
+	return sivDecrypt(macKey, ctrKey, ciphertext, associatedData)
+}
+
+// EncryptDeterministicString 确定性加密字符串，返回base64编码，相同明文总是得到相同结果
+func (a *AES) EncryptDeterministicString(plaintext string, associatedData ...[]byte) (string, error) {
+	ciphertext, err := a.EncryptDeterministic([]byte(plaintext), associatedData...)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptDeterministicString 解密base64编码的确定性密文
+func (a *AES) DecryptDeterministicString(ciphertext string, associatedData ...[]byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := a.DecryptDeterministic(data, associatedData...)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// sivKeys 从AES密钥派生 S2V 和 CTR 子密钥，避免同一密钥同时用于 GCM 和 SIV
+func (a *AES) sivKeys() ([]byte, []byte) {
+	derive := func(label string) []byte {
+		mac := hmac.New(sha256.New, a.key)
+		mac.Write([]byte(label))
+		return mac.Sum(nil)
+	}
+	return derive(sivMACKeyLabel), derive(sivCTRKeyLabel)
+}
+
+// sivEncrypt AES-SIV 加密，macKey 和 ctrKey 长度须相同（16、24或32字节）
+func sivEncrypt(macKey, ctrKey, plaintext []byte, associatedData [][]byte) ([]byte, error) {
+	v, err := s2v(macKey, associatedData, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, aes.BlockSize+len(plaintext))
+	copy(ciphertext, v)
+	if err := sivCTR(ctrKey, v, ciphertext[aes.BlockSize:], plaintext); err != nil {
+		return nil, err
+	}
+	return ciphertext, nil
+}
+
+// sivDecrypt AES-SIV 解密并校验 SIV
+func sivDecrypt(macKey, ctrKey, ciphertext []byte, associatedData [][]byte) ([]byte, error) {
+	if len(ciphertext) < aes.BlockSize {
+		return nil, errors.New("ciphertext too short: missing SIV")
+	}
+	v := ciphertext[:aes.BlockSize]
+	plaintext := make([]byte, len(ciphertext)-aes.BlockSize)
+	if err := sivCTR(ctrKey, v, plaintext, ciphertext[aes.BlockSize:]); err != nil {
+		return nil, err
+	}
+	expected, err := s2v(macKey, associatedData, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(v, expected) != 1 {
+		return nil, errors.New("message authentication failed")
+	}
+	return plaintext, nil
+}
+
+// sivCTR 以 SIV 清除第31、63位后作为初始计数器进行CTR加解密
+func sivCTR(key, v, dst, src []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	iv := make([]byte, aes.BlockSize)
+	copy(iv, v)
+	iv[8] &= 0x7f
+	iv[12] &= 0x7f
+	cipher.NewCTR(block, iv).XORKeyStream(dst, src)
+	return nil
+}
+
+// s2v RFC 5297 中基于 CMAC 的伪随机函数
+func s2v(key []byte, associatedData [][]byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	k1, k2 := cmacSubkeys(block)
+
+	d := cmac(block, k1, k2, make([]byte, aes.BlockSize))
+	for _, ad := range associatedData {
+		d = cmacDouble(d)
+		xorBytes(d, cmac(block, k1, k2, ad))
+	}
+
+	var t []byte
+	if len(plaintext) >= aes.BlockSize {
+		t = make([]byte, len(plaintext))
+		copy(t, plaintext)
+		xorBytes(t[len(t)-aes.BlockSize:], d)
+	} else {
+		t = cmacDouble(d)
+		padded := make([]byte, aes.BlockSize)
+		copy(padded, plaintext)
+		padded[len(plaintext)] = 0x80
+		xorBytes(t, padded)
+	}
+	return cmac(block, k1, k2, t), nil
+}
+
+// cmacSubkeys 生成 CMAC 子密钥（RFC 4493）
+func cmacSubkeys(block cipher.Block) ([]byte, []byte) {
+	l := make([]byte, aes.BlockSize)
+	block.Encrypt(l, l)
+	k1 := cmacDouble(l)
+	k2 := cmacDouble(k1)
+	return k1, k2
+}
+
+// cmac 计算 AES-CMAC（RFC 4493）
+func cmac(block cipher.Block, k1, k2, msg []byte) []byte {
+	n := (len(msg) + aes.BlockSize - 1) / aes.BlockSize
+	complete := n > 0 && len(msg)%aes.BlockSize == 0
+	if n == 0 {
+		n = 1
+	}
+
+	last := make([]byte, aes.BlockSize)
+	if complete {
+		copy(last, msg[(n-1)*aes.BlockSize:])
+		xorBytes(last, k1)
+	} else {
+		rest := msg[(n-1)*aes.BlockSize:]
+		copy(last, rest)
+		last[len(rest)] = 0x80
+		xorBytes(last, k2)
+	}
+
+	x := make([]byte, aes.BlockSize)
+	for i := 0; i < n-1; i++ {
+		xorBytes(x, msg[i*aes.BlockSize:(i+1)*aes.BlockSize])
+		block.Encrypt(x, x)
+	}
+	xorBytes(x, last)
+	block.Encrypt(x, x)
+	return x
+}
+
+// cmacDouble GF(2^128) 上乘以 x
+func cmacDouble(b []byte) []byte {
+	out := make([]byte, len(b))
+	var carry byte
+	for i := len(b) - 1; i >= 0; i-- {
+		out[i] = b[i]<<1 | carry
+		carry = b[i] >> 7
+	}
+	if carry != 0 {
+		out[len(out)-1] ^= 0x87
+	}
+	return out
+}
+
+// xorBytes dst ^= src，按 dst 长度处理
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
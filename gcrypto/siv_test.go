@@ -0,0 +1,71 @@
+package gcrypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSIV_RFC5297Vector(t *testing.T) {
+	key, _ := hex.DecodeString("fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+	ad, _ := hex.DecodeString("101112131415161718191a1b1c1d1e1f2021222324252627")
+	plaintext, _ := hex.DecodeString("112233445566778899aabbccddee")
+	expected, _ := hex.DecodeString("85632d07c6e8f37f950acd320a2ecc9340c02b9690c4dc04daef7f6afe5c")
+
+	ciphertext, err := sivEncrypt(key[:16], key[16:], plaintext, [][]byte{ad})
+	if err != nil {
+		t.Fatalf("sivEncrypt failed: %v", err)
+	}
+	if !bytes.Equal(ciphertext, expected) {
+		t.Fatalf("ciphertext mismatch: %x", ciphertext)
+	}
+
+	decrypted, err := sivDecrypt(key[:16], key[16:], ciphertext, [][]byte{ad})
+	if err != nil {
+		t.Fatalf("sivDecrypt failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("plaintext mismatch: %x", decrypted)
+	}
+}
+
+func TestAES_EncryptDeterministic(t *testing.T) {
+	aesCrypto, _ := NewAES("12345678901234567890123456789012")
+	phone := "13812345678"
+
+	c1, err := aesCrypto.EncryptDeterministicString(phone, []byte("user.phone"))
+	if err != nil {
+		t.Fatalf("EncryptDeterministicString failed: %v", err)
+	}
+	c2, _ := aesCrypto.EncryptDeterministicString(phone, []byte("user.phone"))
+	if c1 != c2 {
+		t.Fatal("deterministic encryption should produce identical ciphertext")
+	}
+	c3, _ := aesCrypto.EncryptDeterministicString(phone, []byte("order.phone"))
+	if c1 == c3 {
+		t.Fatal("different associated data should produce different ciphertext")
+	}
+
+	decrypted, err := aesCrypto.DecryptDeterministicString(c1, []byte("user.phone"))
+	if err != nil || decrypted != phone {
+		t.Fatalf("DecryptDeterministicString failed: %v, %s", err, decrypted)
+	}
+	if _, err := aesCrypto.DecryptDeterministicString(c1, []byte("order.phone")); err == nil {
+		t.Fatal("decryption with wrong associated data should fail")
+	}
+
+	long := bytes.Repeat([]byte("a"), 100)
+	ciphertext, _ := aesCrypto.EncryptDeterministic(long)
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err := aesCrypto.DecryptDeterministic(ciphertext); err == nil {
+		t.Fatal("tampered ciphertext should fail authentication")
+	}
+
+	empty, err := aesCrypto.EncryptDeterministic(nil)
+	if err != nil {
+		t.Fatalf("EncryptDeterministic empty failed: %v", err)
+	}
+	if plaintext, err := aesCrypto.DecryptDeterministic(empty); err != nil || len(plaintext) != 0 {
+		t.Fatalf("DecryptDeterministic empty failed: %v", err)
+	}
+}
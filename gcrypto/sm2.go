@@ -0,0 +1,541 @@
+package gcrypto
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+)
+
+// SM2密钥环境变量名
+const (
+	SM2PrivateKeyEnv = "GOLIB_SM2_PRIVATE_KEY"
+	SM2PublicKeyEnv  = "GOLIB_SM2_PUBLIC_KEY"
+)
+
+// SM2DefaultUID 签名时默认使用的用户标识（GM/T 0009-2012）
+var SM2DefaultUID = []byte("1234567812345678")
+
+var (
+	oidPublicKeyEC = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSM2Curve    = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+)
+
+var (
+	sm2CurveOnce   sync.Once
+	sm2CurveParams *elliptic.CurveParams
+	sm2CurveA      *big.Int
+)
+
+// SM2Curve 返回SM2推荐曲线（GB/T 32918.5-2017），运算不是常量时间的，见 SM2 的说明
+func SM2Curve() elliptic.Curve {
+	sm2CurveOnce.Do(initSM2Curve)
+	return sm2CurveParams
+}
+
+func initSM2Curve() {
+	sm2CurveParams = &elliptic.CurveParams{Name: "SM2-P-256", BitSize: 256}
+	sm2CurveParams.P, _ = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF", 16)
+	sm2CurveParams.N, _ = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123", 16)
+	sm2CurveParams.B, _ = new(big.Int).SetString("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93", 16)
+	sm2CurveParams.Gx, _ = new(big.Int).SetString("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7", 16)
+	sm2CurveParams.Gy, _ = new(big.Int).SetString("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0", 16)
+	// SM2 曲线 a = p - 3，可以直接使用 elliptic.CurveParams 的通用实现
+	sm2CurveA = new(big.Int).Sub(sm2CurveParams.P, big.NewInt(3))
+}
+
+// SM2PublicKey SM2公钥
+type SM2PublicKey struct {
+	X, Y *big.Int
+}
+
+// SM2PrivateKey SM2私钥
+type SM2PrivateKey struct {
+	SM2PublicKey
+	D *big.Int
+}
+
+// SM2 SM2加密器
+//
+// 注意：点乘基于 elliptic.CurveParams 的通用 math/big 实现，不是常量时间的，
+// 签名、解密时私钥 d 和随机数 k 可能通过时间侧信道泄露。仅适用于与国密系统对接、测试等场景，
+// 不要在攻击者可以测量耗时的环境中（如对外提供签名/解密服务）使用生产私钥，
+// 此类场景请使用常量时间实现（如 github.com/emmansun/gmsm）
+type SM2 struct {
+	privateKey *SM2PrivateKey
+	publicKey  *SM2PublicKey
+}
+
+type sm2Signature struct {
+	R, S *big.Int
+}
+
+type sm2ECPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+type sm2PublicKeyAlgorithm struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.ObjectIdentifier
+}
+
+type sm2PublicKeyInfo struct {
+	Algorithm sm2PublicKeyAlgorithm
+	PublicKey asn1.BitString
+}
+
+// NewSM2 从私钥和公钥创建SM2加密器
+// 如果只需要加密或验签，可以只提供公钥；如果只需要解密或签名，可以只提供私钥
+// privateKeyPEM: PEM格式的私钥字符串，如果为空则从环境变量 GOLIB_SM2_PRIVATE_KEY 获取
+// publicKeyPEM: PEM格式的公钥字符串，如果为空则从环境变量 GOLIB_SM2_PUBLIC_KEY 获取
+func NewSM2(privateKeyPEM, publicKeyPEM string) (*SM2, error) {
+	var privateKey *SM2PrivateKey
+	var publicKey *SM2PublicKey
+	var err error
+
+	if privateKeyPEM == "" {
+		privateKeyPEM = os.Getenv(SM2PrivateKeyEnv)
+	}
+	if privateKeyPEM != "" {
+		privateKey, err = parseSM2PrivateKeyPEM([]byte(privateKeyPEM))
+		if err != nil {
+			return nil, err
+		}
+		publicKey = &privateKey.SM2PublicKey
+	}
+
+	// 私钥已包含公钥信息，未明确指定公钥参数时不再从环境变量获取
+	if publicKeyPEM == "" && publicKey == nil {
+		publicKeyPEM = os.Getenv(SM2PublicKeyEnv)
+	}
+	if publicKeyPEM != "" {
+		publicKey, err = parseSM2PublicKeyPEM([]byte(publicKeyPEM))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if privateKey == nil && publicKey == nil {
+		return nil, errors.New("at least one key must be provided (via parameters or environment variables)")
+	}
+
+	return &SM2{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+	}, nil
+}
+
+// NewSM2FromPrivateKey 从私钥创建SM2加密器（私钥包含公钥信息）
+func NewSM2FromPrivateKey(privateKey *SM2PrivateKey) *SM2 {
+	return &SM2{
+		privateKey: privateKey,
+		publicKey:  &privateKey.SM2PublicKey,
+	}
+}
+
+// GenerateSM2KeyPair 生成SM2密钥对
+func GenerateSM2KeyPair() (*SM2PrivateKey, *SM2PublicKey, error) {
+	curve := SM2Curve()
+	d, err := sm2RandScalar(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	x, y := curve.ScalarBaseMult(sm2ScalarBytes(d))
+	privateKey := &SM2PrivateKey{
+		SM2PublicKey: SM2PublicKey{X: x, Y: y},
+		D:            d,
+	}
+	return privateKey, &privateKey.SM2PublicKey, nil
+}
+
+// SM2PrivateKeyToPEM 将私钥转换为PEM格式（SEC1 EC PRIVATE KEY，兼容 OpenSSL）
+func SM2PrivateKeyToPEM(privateKey *SM2PrivateKey) ([]byte, error) {
+	point := sm2MarshalPoint(privateKey.X, privateKey.Y)
+	der, err := asn1.Marshal(sm2ECPrivateKey{
+		Version:       1,
+		PrivateKey:    sm2ScalarBytes(privateKey.D),
+		NamedCurveOID: oidSM2Curve,
+		PublicKey:     asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "EC PRIVATE KEY",
+		Bytes: der,
+	}), nil
+}
+
+// SM2PublicKeyToPEM 将公钥转换为PEM格式（PKIX PUBLIC KEY，兼容 OpenSSL）
+func SM2PublicKeyToPEM(publicKey *SM2PublicKey) ([]byte, error) {
+	point := sm2MarshalPoint(publicKey.X, publicKey.Y)
+	der, err := asn1.Marshal(sm2PublicKeyInfo{
+		Algorithm: sm2PublicKeyAlgorithm{
+			Algorithm:  oidPublicKeyEC,
+			Parameters: oidSM2Curve,
+		},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: der,
+	}), nil
+}
+
+// Encrypt 使用公钥加密数据，密文格式为 C1C3C2
+func (s *SM2) Encrypt(plaintext []byte) ([]byte, error) {
+	if s.publicKey == nil {
+		return nil, errors.New("public key is required for encryption")
+	}
+	if len(plaintext) == 0 {
+		return nil, errors.New("plaintext is empty")
+	}
+
+	for {
+		k, err := sm2RandScalar(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if ciphertext := sm2EncryptWithK(s.publicKey, plaintext, k); ciphertext != nil {
+			return ciphertext, nil
+		}
+	}
+}
+
+// sm2EncryptWithK 使用指定随机数 k 加密，KDF 输出全零时返回 nil，需要更换 k 重试
+func sm2EncryptWithK(publicKey *SM2PublicKey, plaintext []byte, k *big.Int) []byte {
+	curve := SM2Curve()
+	kBytes := sm2ScalarBytes(k)
+	x1, y1 := curve.ScalarBaseMult(kBytes)
+	x2, y2 := curve.ScalarMult(publicKey.X, publicKey.Y, kBytes)
+
+	x2Bytes, y2Bytes := sm2ScalarBytes(x2), sm2ScalarBytes(y2)
+	t := sm2KDF(len(plaintext), x2Bytes, y2Bytes)
+	if sm2AllZero(t) {
+		return nil
+	}
+
+	c2 := make([]byte, len(plaintext))
+	subtle.XORBytes(c2, plaintext, t)
+	c3 := sm2C3(x2Bytes, plaintext, y2Bytes)
+
+	ciphertext := sm2MarshalPoint(x1, y1)
+	ciphertext = append(ciphertext, c3...)
+	return append(ciphertext, c2...)
+}
+
+// Decrypt 使用私钥解密 C1C3C2 格式的密文
+func (s *SM2) Decrypt(ciphertext []byte) ([]byte, error) {
+	if s.privateKey == nil {
+		return nil, errors.New("private key is required for decryption")
+	}
+
+	pointSize := 1 + 2*32
+	if len(ciphertext) <= pointSize+SM3Size {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	curve := SM2Curve()
+	x1, y1, err := sm2UnmarshalPoint(ciphertext[:pointSize])
+	if err != nil {
+		return nil, err
+	}
+	c3 := ciphertext[pointSize : pointSize+SM3Size]
+	c2 := ciphertext[pointSize+SM3Size:]
+
+	x2, y2 := curve.ScalarMult(x1, y1, sm2ScalarBytes(s.privateKey.D))
+	x2Bytes, y2Bytes := sm2ScalarBytes(x2), sm2ScalarBytes(y2)
+	t := sm2KDF(len(c2), x2Bytes, y2Bytes)
+	if sm2AllZero(t) {
+		return nil, errors.New("sm2: decryption error")
+	}
+
+	plaintext := make([]byte, len(c2))
+	subtle.XORBytes(plaintext, c2, t)
+	if subtle.ConstantTimeCompare(c3, sm2C3(x2Bytes, plaintext, y2Bytes)) != 1 {
+		return nil, errors.New("sm2: decryption error")
+	}
+	return plaintext, nil
+}
+
+// EncryptString 加密字符串，返回base64编码
+func (s *SM2) EncryptString(plaintext string) (string, error) {
+	ciphertext, err := s.Encrypt([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptString 解密base64编码的字符串
+func (s *SM2) DecryptString(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := s.Decrypt(data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Sign 使用私钥签名数据（SM3摘要，默认用户标识），返回ASN.1编码的签名
+func (s *SM2) Sign(data []byte) ([]byte, error) {
+	if s.privateKey == nil {
+		return nil, errors.New("private key is required for signing")
+	}
+
+	e := new(big.Int).SetBytes(sm2Digest(&s.privateKey.SM2PublicKey, data))
+	for {
+		k, err := sm2RandScalar(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if r, sv := sm2SignWithK(s.privateKey, e, k); r != nil {
+			return asn1.Marshal(sm2Signature{R: r, S: sv})
+		}
+	}
+}
+
+// sm2SignWithK 使用指定随机数 k 对摘要 e 签名，r 或 s 不满足要求时返回 nil，需要更换 k 重试
+func sm2SignWithK(privateKey *SM2PrivateKey, e, k *big.Int) (*big.Int, *big.Int) {
+	curve := SM2Curve()
+	n := curve.Params().N
+	d := privateKey.D
+
+	x1, _ := curve.ScalarBaseMult(sm2ScalarBytes(k))
+	r := new(big.Int).Add(e, x1)
+	r.Mod(r, n)
+	if r.Sign() == 0 || new(big.Int).Add(r, k).Cmp(n) == 0 {
+		return nil, nil
+	}
+
+	// s = (1 + d)^-1 * (k - r*d) mod n
+	dInv := new(big.Int).Add(d, big.NewInt(1))
+	dInv.ModInverse(dInv, n)
+	sv := new(big.Int).Mul(r, d)
+	sv.Sub(k, sv)
+	sv.Mul(sv, dInv)
+	sv.Mod(sv, n)
+	if sv.Sign() == 0 {
+		return nil, nil
+	}
+	return r, sv
+}
+
+// Verify 使用公钥验证签名
+func (s *SM2) Verify(data []byte, signature []byte) error {
+	if s.publicKey == nil {
+		return errors.New("public key is required for verification")
+	}
+
+	var sig sm2Signature
+	rest, err := asn1.Unmarshal(signature, &sig)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("sm2: trailing data after signature")
+	}
+
+	curve := SM2Curve()
+	n := curve.Params().N
+	one := big.NewInt(1)
+	if sig.R.Cmp(one) < 0 || sig.R.Cmp(n) >= 0 || sig.S.Cmp(one) < 0 || sig.S.Cmp(n) >= 0 {
+		return errors.New("sm2: verification error")
+	}
+
+	t := new(big.Int).Add(sig.R, sig.S)
+	t.Mod(t, n)
+	if t.Sign() == 0 {
+		return errors.New("sm2: verification error")
+	}
+
+	x1, y1 := curve.ScalarBaseMult(sm2ScalarBytes(sig.S))
+	x2, y2 := curve.ScalarMult(s.publicKey.X, s.publicKey.Y, sm2ScalarBytes(t))
+	x, _ := curve.Add(x1, y1, x2, y2)
+
+	e := new(big.Int).SetBytes(sm2Digest(s.publicKey, data))
+	r := e.Add(e, x)
+	r.Mod(r, n)
+	if r.Cmp(sig.R) != 0 {
+		return errors.New("sm2: verification error")
+	}
+	return nil
+}
+
+// sm2Digest 计算 e = SM3(Z || M)，Z = SM3(ENTL || ID || a || b || Gx || Gy || Px || Py)
+func sm2Digest(publicKey *SM2PublicKey, data []byte) []byte {
+	params := SM2Curve().Params()
+	h := NewSM3()
+
+	var entl [2]byte
+	binary.BigEndian.PutUint16(entl[:], uint16(len(SM2DefaultUID)*8))
+	h.Write(entl[:])
+	h.Write(SM2DefaultUID)
+	h.Write(sm2ScalarBytes(sm2CurveA))
+	h.Write(sm2ScalarBytes(params.B))
+	h.Write(sm2ScalarBytes(params.Gx))
+	h.Write(sm2ScalarBytes(params.Gy))
+	h.Write(sm2ScalarBytes(publicKey.X))
+	h.Write(sm2ScalarBytes(publicKey.Y))
+	z := h.Sum(nil)
+
+	h.Reset()
+	h.Write(z)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// sm2KDF 基于SM3的密钥派生函数
+func sm2KDF(length int, z ...[]byte) []byte {
+	h := NewSM3()
+	out := make([]byte, 0, length+SM3Size)
+	var counter [4]byte
+	for ct := uint32(1); len(out) < length; ct++ {
+		binary.BigEndian.PutUint32(counter[:], ct)
+		h.Reset()
+		for _, v := range z {
+			h.Write(v)
+		}
+		h.Write(counter[:])
+		out = h.Sum(out)
+	}
+	return out[:length]
+}
+
+func sm2C3(x2, msg, y2 []byte) []byte {
+	h := NewSM3()
+	h.Write(x2)
+	h.Write(msg)
+	h.Write(y2)
+	return h.Sum(nil)
+}
+
+func sm2AllZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sm2RandScalar 生成 [1, n-2] 范围内的随机数
+func sm2RandScalar(random io.Reader) (*big.Int, error) {
+	n := SM2Curve().Params().N
+	max := new(big.Int).Sub(n, big.NewInt(2))
+	k, err := rand.Int(random, max)
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// sm2ScalarBytes 将大整数编码为32字节大端序
+func sm2ScalarBytes(v *big.Int) []byte {
+	b := make([]byte, 32)
+	return v.FillBytes(b)
+}
+
+func sm2MarshalPoint(x, y *big.Int) []byte {
+	point := make([]byte, 1, 65)
+	point[0] = 0x04
+	point = append(point, sm2ScalarBytes(x)...)
+	return append(point, sm2ScalarBytes(y)...)
+}
+
+func sm2UnmarshalPoint(data []byte) (*big.Int, *big.Int, error) {
+	if len(data) != 65 || data[0] != 0x04 {
+		return nil, nil, errors.New("sm2: invalid point encoding")
+	}
+	x := new(big.Int).SetBytes(data[1:33])
+	y := new(big.Int).SetBytes(data[33:])
+	if !SM2Curve().IsOnCurve(x, y) {
+		return nil, nil, errors.New("sm2: point is not on curve")
+	}
+	return x, y, nil
+}
+
+// parseSM2PrivateKeyPEM 解析PEM格式的私钥（SEC1 或 PKCS8）
+func parseSM2PrivateKeyPEM(privateKeyPEM []byte) (*SM2PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block")
+	}
+
+	der := block.Bytes
+	if block.Type == "PRIVATE KEY" {
+		var pkcs8 struct {
+			Version    int
+			Algorithm  sm2PublicKeyAlgorithm
+			PrivateKey []byte
+		}
+		if _, err := asn1.Unmarshal(der, &pkcs8); err != nil {
+			return nil, err
+		}
+		if !pkcs8.Algorithm.Parameters.Equal(oidSM2Curve) {
+			return nil, errors.New("not an SM2 private key")
+		}
+		der = pkcs8.PrivateKey
+	}
+
+	var key sm2ECPrivateKey
+	if _, err := asn1.Unmarshal(der, &key); err != nil {
+		return nil, err
+	}
+	if len(key.NamedCurveOID) > 0 && !key.NamedCurveOID.Equal(oidSM2Curve) {
+		return nil, errors.New("not an SM2 private key")
+	}
+
+	d := new(big.Int).SetBytes(key.PrivateKey)
+	n := SM2Curve().Params().N
+	if d.Sign() <= 0 || d.Cmp(new(big.Int).Sub(n, big.NewInt(1))) >= 0 {
+		return nil, errors.New("sm2: invalid private key")
+	}
+	x, y := SM2Curve().ScalarBaseMult(sm2ScalarBytes(d))
+	if len(key.PublicKey.Bytes) > 0 && !bytes.Equal(key.PublicKey.Bytes, sm2MarshalPoint(x, y)) {
+		return nil, errors.New("sm2: public key does not match private key")
+	}
+	return &SM2PrivateKey{
+		SM2PublicKey: SM2PublicKey{X: x, Y: y},
+		D:            d,
+	}, nil
+}
+
+// parseSM2PublicKeyPEM 解析PEM格式的公钥
+func parseSM2PublicKeyPEM(publicKeyPEM []byte) (*SM2PublicKey, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, errors.New("failed to parse PEM block")
+	}
+
+	var info sm2PublicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, err
+	}
+	if !info.Algorithm.Parameters.Equal(oidSM2Curve) {
+		return nil, errors.New("not an SM2 public key")
+	}
+	x, y, err := sm2UnmarshalPoint(info.PublicKey.RightAlign())
+	if err != nil {
+		return nil, err
+	}
+	return &SM2PublicKey{X: x, Y: y}, nil
+}
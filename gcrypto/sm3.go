@@ -0,0 +1,158 @@
+package gcrypto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math/bits"
+)
+
+// SM3Size SM3摘要长度（字节）
+const SM3Size = 32
+
+// SM3BlockSize SM3分组长度（字节）
+const SM3BlockSize = 64
+
+var sm3IV = [8]uint32{
+	0x7380166f, 0x4914b2b9, 0x172442d7, 0xda8a0600,
+	0xa96f30bc, 0x163138aa, 0xe38dee4d, 0xb0fb0e4e,
+}
+
+type sm3Digest struct {
+	h   [8]uint32
+	x   [SM3BlockSize]byte
+	nx  int
+	len uint64
+}
+
+// NewSM3 创建SM3哈希（GB/T 32905-2016）
+func NewSM3() hash.Hash {
+	d := &sm3Digest{}
+	d.Reset()
+	return d
+}
+
+// SM3Sum 计算数据的SM3摘要
+func SM3Sum(data []byte) [SM3Size]byte {
+	d := &sm3Digest{}
+	d.Reset()
+	_, _ = d.Write(data)
+	var sum [SM3Size]byte
+	copy(sum[:], d.Sum(nil))
+	return sum
+}
+
+// SM3Hash 计算字符串的SM3摘要，返回十六进制字符串
+func SM3Hash(data string) string {
+	h := SM3Sum([]byte(data))
+	return fmt.Sprintf("%x", h)
+}
+
+func (d *sm3Digest) Reset() {
+	d.h = sm3IV
+	d.nx = 0
+	d.len = 0
+}
+
+func (d *sm3Digest) Size() int { return SM3Size }
+
+func (d *sm3Digest) BlockSize() int { return SM3BlockSize }
+
+func (d *sm3Digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	if d.nx > 0 {
+		c := copy(d.x[d.nx:], p)
+		d.nx += c
+		if d.nx == SM3BlockSize {
+			d.block(d.x[:])
+			d.nx = 0
+		}
+		p = p[c:]
+	}
+	for len(p) >= SM3BlockSize {
+		d.block(p[:SM3BlockSize])
+		p = p[SM3BlockSize:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.x[:], p)
+	}
+	return n, nil
+}
+
+func (d *sm3Digest) Sum(in []byte) []byte {
+	// 复制一份，保证调用 Sum 后仍可继续写入
+	d0 := *d
+	length := d0.len << 3
+
+	var tmp [SM3BlockSize + 8]byte
+	tmp[0] = 0x80
+	padLen := 56 - int(d0.len%SM3BlockSize)
+	if padLen <= 0 {
+		padLen += SM3BlockSize
+	}
+	binary.BigEndian.PutUint64(tmp[padLen:], length)
+	_, _ = d0.Write(tmp[:padLen+8])
+
+	var digest [SM3Size]byte
+	for i, v := range d0.h {
+		binary.BigEndian.PutUint32(digest[i*4:], v)
+	}
+	return append(in, digest[:]...)
+}
+
+func sm3P0(x uint32) uint32 {
+	return x ^ bits.RotateLeft32(x, 9) ^ bits.RotateLeft32(x, 17)
+}
+
+func sm3P1(x uint32) uint32 {
+	return x ^ bits.RotateLeft32(x, 15) ^ bits.RotateLeft32(x, 23)
+}
+
+// block 压缩函数
+func (d *sm3Digest) block(p []byte) {
+	var w [68]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(p[i*4:])
+	}
+	for j := 16; j < 68; j++ {
+		w[j] = sm3P1(w[j-16]^w[j-9]^bits.RotateLeft32(w[j-3], 15)) ^ bits.RotateLeft32(w[j-13], 7) ^ w[j-6]
+	}
+
+	a, b, c, e, f, g, hh := d.h[0], d.h[1], d.h[2], d.h[4], d.h[5], d.h[6], d.h[7]
+	dd := d.h[3]
+	for j := 0; j < 64; j++ {
+		var t, ff, gg uint32
+		if j < 16 {
+			t = 0x79cc4519
+			ff = a ^ b ^ c
+			gg = e ^ f ^ g
+		} else {
+			t = 0x7a879d8a
+			ff = (a & b) | (a & c) | (b & c)
+			gg = (e & f) | (^e & g)
+		}
+		a12 := bits.RotateLeft32(a, 12)
+		ss1 := bits.RotateLeft32(a12+e+bits.RotateLeft32(t, j%32), 7)
+		ss2 := ss1 ^ a12
+		tt1 := ff + dd + ss2 + (w[j] ^ w[j+4])
+		tt2 := gg + hh + ss1 + w[j]
+		dd = c
+		c = bits.RotateLeft32(b, 9)
+		b = a
+		a = tt1
+		hh = g
+		g = bits.RotateLeft32(f, 19)
+		f = e
+		e = sm3P0(tt2)
+	}
+
+	d.h[0] ^= a
+	d.h[1] ^= b
+	d.h[2] ^= c
+	d.h[3] ^= dd
+	d.h[4] ^= e
+	d.h[5] ^= f
+	d.h[6] ^= g
+	d.h[7] ^= hh
+}
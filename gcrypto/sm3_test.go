@@ -0,0 +1,43 @@
+package gcrypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSM3Hash(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "abc",
+			expected: "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0",
+		},
+		{
+			input:    strings.Repeat("abcd", 16),
+			expected: "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732",
+		},
+	}
+	for _, tt := range tests {
+		if result := SM3Hash(tt.input); result != tt.expected {
+			t.Fatalf("SM3Hash(%q) expected %s, got %s", tt.input, tt.expected, result)
+		}
+	}
+}
+
+func TestSM3_StreamWrite(t *testing.T) {
+	data := []byte(strings.Repeat("golib", 100))
+	h := NewSM3()
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		h.Write(data[i:end])
+	}
+	sum := SM3Sum(data)
+	if string(h.Sum(nil)) != string(sum[:]) {
+		t.Fatalf("stream write digest doesn't match")
+	}
+}
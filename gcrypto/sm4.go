@@ -0,0 +1,257 @@
+package gcrypto
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// SM4密钥环境变量名
+const (
+	SM4KeyEnv = "GOLIB_SM4_KEY"
+)
+
+// 默认SM4密钥（16字节）
+const defaultSM4Key = "Gq3Vd8sLk2Xw9PzR"
+
+// SM4KeySize SM4密钥长度（字节）
+const SM4KeySize = 16
+
+// SM4BlockSize SM4分组长度（字节）
+const SM4BlockSize = 16
+
+var sm4FK = [4]uint32{0xa3b1bac6, 0x56aa3350, 0x677d9197, 0xb27022dc}
+
+var sm4CK = func() [32]uint32 {
+	var ck [32]uint32
+	for i := range ck {
+		var b [4]byte
+		for j := range b {
+			b[j] = byte((4*i + j) * 7)
+		}
+		ck[i] = binary.BigEndian.Uint32(b[:])
+	}
+	return ck
+}()
+
+var sm4SBox = [256]byte{
+	0xd6, 0x90, 0xe9, 0xfe, 0xcc, 0xe1, 0x3d, 0xb7, 0x16, 0xb6, 0x14, 0xc2, 0x28, 0xfb, 0x2c, 0x05,
+	0x2b, 0x67, 0x9a, 0x76, 0x2a, 0xbe, 0x04, 0xc3, 0xaa, 0x44, 0x13, 0x26, 0x49, 0x86, 0x06, 0x99,
+	0x9c, 0x42, 0x50, 0xf4, 0x91, 0xef, 0x98, 0x7a, 0x33, 0x54, 0x0b, 0x43, 0xed, 0xcf, 0xac, 0x62,
+	0xe4, 0xb3, 0x1c, 0xa9, 0xc9, 0x08, 0xe8, 0x95, 0x80, 0xdf, 0x94, 0xfa, 0x75, 0x8f, 0x3f, 0xa6,
+	0x47, 0x07, 0xa7, 0xfc, 0xf3, 0x73, 0x17, 0xba, 0x83, 0x59, 0x3c, 0x19, 0xe6, 0x85, 0x4f, 0xa8,
+	0x68, 0x6b, 0x81, 0xb2, 0x71, 0x64, 0xda, 0x8b, 0xf8, 0xeb, 0x0f, 0x4b, 0x70, 0x56, 0x9d, 0x35,
+	0x1e, 0x24, 0x0e, 0x5e, 0x63, 0x58, 0xd1, 0xa2, 0x25, 0x22, 0x7c, 0x3b, 0x01, 0x21, 0x78, 0x87,
+	0xd4, 0x00, 0x46, 0x57, 0x9f, 0xd3, 0x27, 0x52, 0x4c, 0x36, 0x02, 0xe7, 0xa0, 0xc4, 0xc8, 0x9e,
+	0xea, 0xbf, 0x8a, 0xd2, 0x40, 0xc7, 0x38, 0xb5, 0xa3, 0xf7, 0xf2, 0xce, 0xf9, 0x61, 0x15, 0xa1,
+	0xe0, 0xae, 0x5d, 0xa4, 0x9b, 0x34, 0x1a, 0x55, 0xad, 0x93, 0x32, 0x30, 0xf5, 0x8c, 0xb1, 0xe3,
+	0x1d, 0xf6, 0xe2, 0x2e, 0x82, 0x66, 0xca, 0x60, 0xc0, 0x29, 0x23, 0xab, 0x0d, 0x53, 0x4e, 0x6f,
+	0xd5, 0xdb, 0x37, 0x45, 0xde, 0xfd, 0x8e, 0x2f, 0x03, 0xff, 0x6a, 0x72, 0x6d, 0x6c, 0x5b, 0x51,
+	0x8d, 0x1b, 0xaf, 0x92, 0xbb, 0xdd, 0xbc, 0x7f, 0x11, 0xd9, 0x5c, 0x41, 0x1f, 0x10, 0x5a, 0xd8,
+	0x0a, 0xc1, 0x31, 0x88, 0xa5, 0xcd, 0x7b, 0xbd, 0x2d, 0x74, 0xd0, 0x12, 0xb8, 0xe5, 0xb4, 0xb0,
+	0x89, 0x69, 0x97, 0x4a, 0x0c, 0x96, 0x77, 0x7e, 0x65, 0xb9, 0xf1, 0x09, 0xc5, 0x6e, 0xc6, 0x84,
+	0x18, 0xf0, 0x7d, 0xec, 0x3a, 0xdc, 0x4d, 0x20, 0x79, 0xee, 0x5f, 0x3e, 0xd7, 0xcb, 0x39, 0x48,
+}
+
+// SM4 SM4加密器
+type SM4 struct {
+	key []byte
+}
+
+// NewSM4 创建SM4加密器
+// key: 密钥字符串，如果为空则从环境变量 GOLIB_SM4_KEY 获取，如果环境变量也不存在则使用默认密钥
+// 密钥长度必须是16字节，不足时使用0填充，超过时截取前16字节
+func NewSM4(key string) (*SM4, error) {
+	if key == "" {
+		key = getKeyFromEnvOrDefault(SM4KeyEnv, defaultSM4Key)
+	}
+
+	keyBytes := []byte(key)
+	if len(keyBytes) < SM4KeySize {
+		padding := make([]byte, SM4KeySize-len(keyBytes))
+		keyBytes = append(keyBytes, padding...)
+	} else {
+		keyBytes = keyBytes[:SM4KeySize]
+	}
+
+	return &SM4{key: keyBytes}, nil
+}
+
+// NewSM4Cipher 创建SM4分组密码，可与 crypto/cipher 中的工作模式组合使用
+func NewSM4Cipher(key []byte) (cipher.Block, error) {
+	if len(key) != SM4KeySize {
+		return nil, errors.New("sm4 key size must be 16 bytes")
+	}
+	c := &sm4Cipher{}
+	c.expandKey(key)
+	return c, nil
+}
+
+// Encrypt 加密数据（使用GCM模式）
+func (s *SM4) Encrypt(plaintext []byte) ([]byte, error) {
+	block, err := NewSM4Cipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	return ciphertext, nil
+}
+
+// Decrypt 解密数据
+func (s *SM4) Decrypt(ciphertext []byte) ([]byte, error) {
+	block, err := NewSM4Cipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("ciphertext too short: missing nonce")
+	}
+
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// EncryptString 加密字符串，返回base64编码
+func (s *SM4) EncryptString(plaintext string) (string, error) {
+	ciphertext, err := s.Encrypt([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptString 解密base64编码的字符串
+func (s *SM4) DecryptString(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := s.Decrypt(data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// EncryptCBC 使用CBC模式加密
+func (s *SM4) EncryptCBC(plaintext []byte) ([]byte, error) {
+	block, err := NewSM4Cipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext = pkcs7Padding(plaintext, SM4BlockSize)
+
+	iv := make([]byte, SM4BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	mode := cipher.NewCBCEncrypter(block, iv)
+	ciphertext := make([]byte, len(plaintext))
+	mode.CryptBlocks(ciphertext, plaintext)
+
+	// 将IV附加到密文前面
+	return append(iv, ciphertext...), nil
+}
+
+// DecryptCBC 使用CBC模式解密
+func (s *SM4) DecryptCBC(ciphertext []byte) ([]byte, error) {
+	block, err := NewSM4Cipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < SM4BlockSize {
+		return nil, errors.New("ciphertext too short: missing IV")
+	}
+	iv := ciphertext[:SM4BlockSize]
+	ciphertext = ciphertext[SM4BlockSize:]
+	if len(ciphertext)%SM4BlockSize != 0 {
+		return nil, errors.New("ciphertext is not a multiple of the block size")
+	}
+
+	mode := cipher.NewCBCDecrypter(block, iv)
+	plaintext := make([]byte, len(ciphertext))
+	mode.CryptBlocks(plaintext, ciphertext)
+
+	return pkcs7UnPadding(plaintext)
+}
+
+// sm4Cipher 实现 cipher.Block（GB/T 32907-2016）
+type sm4Cipher struct {
+	rk [32]uint32
+}
+
+func (c *sm4Cipher) BlockSize() int { return SM4BlockSize }
+
+func (c *sm4Cipher) Encrypt(dst, src []byte) {
+	c.crypt(dst, src, false)
+}
+
+func (c *sm4Cipher) Decrypt(dst, src []byte) {
+	c.crypt(dst, src, true)
+}
+
+func (c *sm4Cipher) expandKey(key []byte) {
+	var k [36]uint32
+	for i := 0; i < 4; i++ {
+		k[i] = binary.BigEndian.Uint32(key[i*4:]) ^ sm4FK[i]
+	}
+	for i := 0; i < 32; i++ {
+		b := sm4Tau(k[i+1] ^ k[i+2] ^ k[i+3] ^ sm4CK[i])
+		k[i+4] = k[i] ^ b ^ bits.RotateLeft32(b, 13) ^ bits.RotateLeft32(b, 23)
+		c.rk[i] = k[i+4]
+	}
+}
+
+func (c *sm4Cipher) crypt(dst, src []byte, decrypt bool) {
+	if len(src) < SM4BlockSize || len(dst) < SM4BlockSize {
+		panic("gcrypto: sm4 input not full block")
+	}
+	var x [36]uint32
+	for i := 0; i < 4; i++ {
+		x[i] = binary.BigEndian.Uint32(src[i*4:])
+	}
+	for i := 0; i < 32; i++ {
+		rk := c.rk[i]
+		if decrypt {
+			rk = c.rk[31-i]
+		}
+		b := sm4Tau(x[i+1] ^ x[i+2] ^ x[i+3] ^ rk)
+		x[i+4] = x[i] ^ b ^ bits.RotateLeft32(b, 2) ^ bits.RotateLeft32(b, 10) ^ bits.RotateLeft32(b, 18) ^ bits.RotateLeft32(b, 24)
+	}
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint32(dst[i*4:], x[35-i])
+	}
+}
+
+// sm4Tau 非线性变换
+func sm4Tau(a uint32) uint32 {
+	return uint32(sm4SBox[a>>24])<<24 |
+		uint32(sm4SBox[(a>>16)&0xff])<<16 |
+		uint32(sm4SBox[(a>>8)&0xff])<<8 |
+		uint32(sm4SBox[a&0xff])
+}
@@ -0,0 +1,94 @@
+package gcrypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestSM4Cipher_StandardVector(t *testing.T) {
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	expected := "681edf34d206965e86b3e94f536e4246"
+
+	block, err := NewSM4Cipher(key)
+	if err != nil {
+		t.Fatalf("NewSM4Cipher failed: %v", err)
+	}
+	dst := make([]byte, SM4BlockSize)
+	block.Encrypt(dst, key)
+	if hex.EncodeToString(dst) != expected {
+		t.Fatalf("Expected %s, got %x", expected, dst)
+	}
+
+	plain := make([]byte, SM4BlockSize)
+	block.Decrypt(plain, dst)
+	if hex.EncodeToString(plain) != hex.EncodeToString(key) {
+		t.Fatalf("Decrypted block doesn't match, got %x", plain)
+	}
+}
+
+func TestSM4_EncryptDecryptString(t *testing.T) {
+	sm4, err := NewSM4("")
+	if err != nil {
+		t.Fatalf("NewSM4 failed: %v", err)
+	}
+
+	plaintext := "测试国密SM4加密解密"
+	encrypted, err := sm4.EncryptString(plaintext)
+	if err != nil {
+		t.Fatalf("EncryptString failed: %v", err)
+	}
+
+	decrypted, err := sm4.DecryptString(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptString failed: %v", err)
+	}
+	if decrypted != plaintext {
+		t.Fatalf("Decrypted text doesn't match. Expected: %s, Got: %s", plaintext, decrypted)
+	}
+}
+
+func TestSM4_EncryptDecryptCBC(t *testing.T) {
+	sm4, err := NewSM4("my-sm4-key")
+	if err != nil {
+		t.Fatalf("NewSM4 failed: %v", err)
+	}
+
+	plaintext := []byte("Hello, SM4 CBC mode!")
+	ciphertext, err := sm4.EncryptCBC(plaintext)
+	if err != nil {
+		t.Fatalf("EncryptCBC failed: %v", err)
+	}
+
+	decrypted, err := sm4.DecryptCBC(ciphertext)
+	if err != nil {
+		t.Fatalf("DecryptCBC failed: %v", err)
+	}
+	if string(decrypted) != string(plaintext) {
+		t.Fatalf("Decrypted text doesn't match. Expected: %s, Got: %s", plaintext, decrypted)
+	}
+}
+
+func TestSM4_FromEnv(t *testing.T) {
+	t.Setenv(SM4KeyEnv, "env-sm4-key-1234")
+
+	encryptor, err := NewSM4("")
+	if err != nil {
+		t.Fatalf("NewSM4 failed: %v", err)
+	}
+	decryptor, err := NewSM4("env-sm4-key-1234")
+	if err != nil {
+		t.Fatalf("NewSM4 failed: %v", err)
+	}
+
+	encrypted, err := encryptor.EncryptString("hello")
+	if err != nil {
+		t.Fatalf("EncryptString failed: %v", err)
+	}
+	decrypted, err := decryptor.DecryptString(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptString failed: %v", err)
+	}
+	if decrypted != "hello" {
+		t.Fatalf("Expected hello, got %s", decrypted)
+	}
+}
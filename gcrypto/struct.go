@@ -0,0 +1,197 @@
+package gcrypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// 结构体字段加密标签
+const (
+	StructTagName      = "gcrypto"
+	StructTagEncrypt   = "encrypt"
+	structTagValueSkip = "-"
+)
+
+// EncryptStruct 使用默认AES密钥加密结构体中标记了 `gcrypto:"encrypt"` 的字段
+// 密钥获取规则与 NewAES("") 一致：环境变量 GOLIB_AES_KEY > 默认密钥
+func EncryptStruct(v any) error {
+	a, err := NewAES("")
+	if err != nil {
+		return err
+	}
+	return a.EncryptStruct(v)
+}
+
+// DecryptStruct 使用默认AES密钥解密结构体中标记了 `gcrypto:"encrypt"` 的字段
+func DecryptStruct(v any) error {
+	a, err := NewAES("")
+	if err != nil {
+		return err
+	}
+	return a.DecryptStruct(v)
+}
+
+// EncryptStruct 加密结构体中标记了 `gcrypto:"encrypt"` 的字段，v 必须是结构体指针
+// string 字段加密后使用base64编码，[]byte 字段直接写入密文，空值不做处理
+// 未标记的嵌套结构体、结构体指针、结构体切片会递归处理，同一指针只处理一次，支持自引用结构体
+func (a *AES) EncryptStruct(v any) error {
+	return walkStruct(v, func(value []byte) ([]byte, error) {
+		return a.Encrypt(value)
+	}, true)
+}
+
+// DecryptStruct 解密结构体中标记了 `gcrypto:"encrypt"` 的字段，v 必须是结构体指针
+func (a *AES) DecryptStruct(v any) error {
+	return walkStruct(v, func(value []byte) ([]byte, error) {
+		return a.Decrypt(value)
+	}, false)
+}
+
+type cryptFunc func(value []byte) ([]byte, error)
+
+func walkStruct(v any, fn cryptFunc, encrypt bool) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("value must be a non-nil pointer to struct")
+	}
+	if rv.Elem().Kind() != reflect.Struct {
+		return errors.New("value must be a non-nil pointer to struct")
+	}
+	w := &structWalker{fn: fn, encrypt: encrypt, visited: make(map[visitKey]struct{})}
+	return w.walk(rv)
+}
+
+// visitKey 已处理的指针，结构体与其首个字段地址相同，需要同时比较类型
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// structWalker 遍历结构体字段，记录已处理的指针，避免自引用结构体无限递归，
+// 也避免多个字段共享同一指针时被重复加解密
+type structWalker struct {
+	fn      cryptFunc
+	encrypt bool
+	visited map[visitKey]struct{}
+}
+
+func (w *structWalker) walk(rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		key := visitKey{ptr: rv.Pointer(), typ: rv.Type()}
+		if _, ok := w.visited[key]; ok {
+			return nil
+		}
+		w.visited[key] = struct{}{}
+		return w.walk(rv.Elem())
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return w.walk(rv.Elem())
+	case reflect.Slice, reflect.Array:
+		// []byte、[]string 等元素不可能包含结构体的切片无需逐个遍历
+		if !mayContainStruct(rv.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := w.walk(rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+	default:
+		return nil
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get(StructTagName)
+		if tag == structTagValueSkip {
+			continue
+		}
+		fv := rv.Field(i)
+		if tag != StructTagEncrypt {
+			if err := w.walk(fv); err != nil {
+				return fmt.Errorf("%s.%w", field.Name, err)
+			}
+			continue
+		}
+		if !fv.CanSet() {
+			return fmt.Errorf("field %s cannot be set", field.Name)
+		}
+		if err := cryptField(fv, w.fn, w.encrypt); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// mayContainStruct 判断类型的值是否可能包含需要遍历的结构体
+func mayContainStruct(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return mayContainStruct(rt.Elem())
+	default:
+		return false
+	}
+}
+
+func cryptField(fv reflect.Value, fn cryptFunc, encrypt bool) error {
+	// 支持 *string、*[]byte
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+
+	switch {
+	case fv.Kind() == reflect.String:
+		if fv.Len() == 0 {
+			return nil
+		}
+		if encrypt {
+			ciphertext, err := fn([]byte(fv.String()))
+			if err != nil {
+				return err
+			}
+			fv.SetString(base64.StdEncoding.EncodeToString(ciphertext))
+			return nil
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(fv.String())
+		if err != nil {
+			return err
+		}
+		plaintext, err := fn(ciphertext)
+		if err != nil {
+			return err
+		}
+		fv.SetString(string(plaintext))
+		return nil
+	case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Uint8:
+		if fv.Len() == 0 {
+			return nil
+		}
+		result, err := fn(fv.Bytes())
+		if err != nil {
+			return err
+		}
+		fv.SetBytes(result)
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %s, only string and []byte are supported", fv.Type())
+	}
+}
@@ -0,0 +1,126 @@
+package gcrypto
+
+import (
+	"testing"
+)
+
+type testProfile struct {
+	Address string `gcrypto:"encrypt"`
+}
+
+type testUser struct {
+	Name     string
+	Phone    string  `gcrypto:"encrypt"`
+	IDCard   *string `gcrypto:"encrypt"`
+	Secret   []byte  `gcrypto:"encrypt"`
+	Email    string  `gcrypto:"encrypt"`
+	Profile  testProfile
+	Profiles []*testProfile
+	Ignored  testProfile `gcrypto:"-"`
+}
+
+func TestAES_EncryptDecryptStruct(t *testing.T) {
+	aes, err := NewAES("my-secret-key-1234567890123456")
+	if err != nil {
+		t.Fatalf("NewAES failed: %v", err)
+	}
+
+	idCard := "110101199001011234"
+	user := &testUser{
+		Name:     "alice",
+		Phone:    "13812345678",
+		IDCard:   &idCard,
+		Secret:   []byte("secret"),
+		Profile:  testProfile{Address: "beijing"},
+		Profiles: []*testProfile{{Address: "shanghai"}, nil},
+		Ignored:  testProfile{Address: "plain"},
+	}
+
+	if err := aes.EncryptStruct(user); err != nil {
+		t.Fatalf("EncryptStruct failed: %v", err)
+	}
+	if user.Name != "alice" {
+		t.Fatalf("Untagged field should not be encrypted, got %s", user.Name)
+	}
+	if user.Phone == "13812345678" || *user.IDCard == "110101199001011234" || string(user.Secret) == "secret" {
+		t.Fatalf("Tagged fields should be encrypted")
+	}
+	if user.Profile.Address == "beijing" || user.Profiles[0].Address == "shanghai" {
+		t.Fatalf("Nested tagged fields should be encrypted")
+	}
+	if user.Email != "" {
+		t.Fatalf("Empty field should stay empty, got %s", user.Email)
+	}
+	if user.Ignored.Address != "plain" {
+		t.Fatalf("Skipped field should not be encrypted")
+	}
+
+	if err := aes.DecryptStruct(user); err != nil {
+		t.Fatalf("DecryptStruct failed: %v", err)
+	}
+	if user.Phone != "13812345678" || *user.IDCard != "110101199001011234" || string(user.Secret) != "secret" {
+		t.Fatalf("Decrypted fields don't match: %+v", user)
+	}
+	if user.Profile.Address != "beijing" || user.Profiles[0].Address != "shanghai" {
+		t.Fatalf("Decrypted nested fields don't match: %+v", user)
+	}
+}
+
+func TestEncryptDecryptStruct_DefaultKey(t *testing.T) {
+	user := &testUser{Phone: "13812345678"}
+	if err := EncryptStruct(user); err != nil {
+		t.Fatalf("EncryptStruct failed: %v", err)
+	}
+	if err := DecryptStruct(user); err != nil {
+		t.Fatalf("DecryptStruct failed: %v", err)
+	}
+	if user.Phone != "13812345678" {
+		t.Fatalf("Expected 13812345678, got %s", user.Phone)
+	}
+}
+
+func TestEncryptStruct_InvalidInput(t *testing.T) {
+	if err := EncryptStruct(testUser{}); err == nil {
+		t.Fatalf("EncryptStruct should fail with non-pointer value")
+	}
+
+	type invalid struct {
+		Age int `gcrypto:"encrypt"`
+	}
+	if err := EncryptStruct(&invalid{Age: 1}); err == nil {
+		t.Fatalf("EncryptStruct should fail with unsupported field type")
+	}
+}
+
+type testNode struct {
+	Value    string `gcrypto:"encrypt"`
+	Next     *testNode
+	Children []*testNode
+	Raw      []byte
+}
+
+func TestEncryptStruct_SelfReference(t *testing.T) {
+	a, err := NewAES("")
+	if err != nil {
+		t.Fatalf("NewAES failed: %v", err)
+	}
+
+	// 环形链表和共享指针只处理一次
+	shared := &testNode{Value: "shared"}
+	root := &testNode{Value: "root", Children: []*testNode{shared, shared}, Raw: make([]byte, 1<<20)}
+	root.Next = root
+	shared.Next = root
+
+	if err := a.EncryptStruct(root); err != nil {
+		t.Fatalf("EncryptStruct failed: %v", err)
+	}
+	if root.Value == "root" || shared.Value == "shared" {
+		t.Fatalf("fields should be encrypted")
+	}
+	if err := a.DecryptStruct(root); err != nil {
+		t.Fatalf("DecryptStruct failed: %v", err)
+	}
+	if root.Value != "root" || shared.Value != "shared" {
+		t.Fatalf("unexpected decrypted values: %s, %s", root.Value, shared.Value)
+	}
+}
@@ -184,28 +184,43 @@ func (m CodeMsgMap) ToErrorMap() ErrorMap {
 // 全局工具函数
 // ═══════════════════════════════════════════════════════════════
 
-// GetCode 从任意 error 中提取业务错误码，找不到返回 -1
+// GetCode 沿 error 链查找业务错误码，支持 fmt.Errorf("%w") 多层包装、errors.Join 和自定义 As 方法，
+// 链上有多个业务错误时返回最外层的错误码，找不到（含 err 为 nil）返回 -1。
+//
+// 包装约定：dao/service 层用哨兵的 Wrap/Wrapf 或 fmt.Errorf("...: %w", err) 追加上下文，
+// 不要用 %v 或 err.Error() 拼接，否则错误链断开，handler 层无法再通过 GetCode/IsCode 按业务码分支
 func GetCode(err error) int {
-	var e Error
-	if errors.As(err, &e) {
+	if e, ok := findError(err); ok {
 		return e.Code
 	}
 	return -1
 }
 
+// findError 通过 errors.As 查找链上第一个业务错误，兼容 Error 值、*Error 指针和包装错误
+func findError(err error) (Error, bool) {
+	var e Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	var p *Error
+	if errors.As(err, &p) && p != nil {
+		return *p, true
+	}
+	return Error{}, false
+}
+
 // GetMsg 从任意 error 中提取业务错误信息，找不到则返回 err.Error()
 func GetMsg(err error) string {
 	if err == nil {
 		return ""
 	}
-	var e Error
-	if errors.As(err, &e) {
+	if e, ok := findError(err); ok {
 		return e.Msg
 	}
 	return err.Error()
 }
 
-// IsCode 直接用 code 整数判断，无需构造哨兵
+// IsCode 直接用 code 整数判断，无需构造哨兵，与 GetCode 一样沿 error 链查找
 func IsCode(err error, code int) bool {
 	return GetCode(err) == code
 }
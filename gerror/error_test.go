@@ -2,6 +2,7 @@ package gerror
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -107,6 +108,55 @@ func TestIsCode(t *testing.T) {
 	}
 }
 
+// customCodeError 通过自定义 As 方法暴露业务错误
+type customCodeError struct{ code int }
+
+func (e customCodeError) Error() string { return "custom" }
+
+func (e customCodeError) As(target any) bool {
+	if t, ok := target.(*Error); ok {
+		*t = Error{Code: e.code, Msg: "custom"}
+		return true
+	}
+	return false
+}
+
+func TestGetCodeChain(t *testing.T) {
+	notFound, forbidden, _ := newTestSentinels()
+
+	cases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"nil", nil, -1},
+		{"plain", errors.New("plain"), -1},
+		{"sentinel", notFound, 10404},
+		{"pointer", &forbidden, 10403},
+		{"fmt wrap", fmt.Errorf("dao: query user: %w", fmt.Errorf("scan: %w", notFound)), 10404},
+		{"outermost wins", fmt.Errorf("service: %w", forbidden.Wrap(notFound)), 10403},
+		{"wrapped plain", fmt.Errorf("service: %w", notFound.Wrap(errors.New("db error"))), 10404},
+		{"join", errors.Join(errors.New("plain"), fmt.Errorf("dao: %w", forbidden)), 10403},
+		{"custom As", fmt.Errorf("dao: %w", customCodeError{code: 10500}), 10500},
+		{"%v breaks chain", fmt.Errorf("dao: %v", notFound), -1},
+	}
+	for _, c := range cases {
+		if got := GetCode(c.err); got != c.code {
+			t.Fatalf("%s: expected code %d, got %d", c.name, c.code, got)
+		}
+		if c.code > 0 && !IsCode(c.err, c.code) {
+			t.Fatalf("%s: expected IsCode(err, %d) to return true", c.name, c.code)
+		}
+	}
+
+	if IsCode(fmt.Errorf("dao: %w", notFound), 10403) {
+		t.Fatal("expected IsCode to return false for a different code")
+	}
+	if GetMsg(fmt.Errorf("dao: %w", customCodeError{code: 10500})) != "custom" {
+		t.Fatal("expected GetMsg to honor custom As method")
+	}
+}
+
 func TestCause(t *testing.T) {
 	notFound, _, _ := newTestSentinels()
 	root := errors.New("root cause")
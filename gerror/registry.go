@@ -0,0 +1,81 @@
+package gerror
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ═══════════════════════════════════════════════════════════════
+// 错误码注册表
+// ═══════════════════════════════════════════════════════════════
+
+// registry 全局错误码注册表，记录各错误码的所属模块，用于在启动时发现不同模块间的错误码冲突
+var registry = struct {
+	sync.RWMutex
+	codes map[int]registeredCode
+}{codes: make(map[int]registeredCode)}
+
+type registeredCode struct {
+	owner string
+	msg   string
+}
+
+// Register 注册 owner（通常为模块名）的错误码。任一错误码已被其他 owner 注册时返回错误，且本次不注册任何错误码；
+// 同一 owner 重复注册时覆盖错误信息
+func Register(owner string, m CodeMsgMap) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	codes := make([]int, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if exist, ok := registry.codes[code]; ok && exist.owner != owner {
+			return fmt.Errorf("gerror: code %d of %s already registered by %s", code, owner, exist.owner)
+		}
+	}
+	for code, msg := range m {
+		registry.codes[code] = registeredCode{owner: owner, msg: msg}
+	}
+	return nil
+}
+
+// MustRegister 同 Register，冲突时 panic，用于在包的 init 中注册
+func MustRegister(owner string, m CodeMsgMap) {
+	if err := Register(owner, m); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup 查询已注册的错误码
+func Lookup(code int) (Error, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	r, ok := registry.codes[code]
+	if !ok {
+		return Error{}, false
+	}
+	return Error{Code: code, Msg: r.msg}, true
+}
+
+// CodeOwner 返回错误码的所属模块
+func CodeOwner(code int) (string, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	r, ok := registry.codes[code]
+	return r.owner, ok
+}
+
+// Registered 返回全部已注册的错误码
+func Registered() ErrorMap {
+	registry.RLock()
+	defer registry.RUnlock()
+	em := make(ErrorMap, len(registry.codes))
+	for code, r := range registry.codes {
+		em[code] = Error{Code: code, Msg: r.msg}
+	}
+	return em
+}
@@ -0,0 +1,42 @@
+package gerror
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	if err := Register("order", CodeMsgMap{900100: "order not exist", 900101: "create order error"}); err != nil {
+		t.Fatalf("register order fail: %v", err)
+	}
+	// 同一模块重复注册覆盖错误信息
+	if err := Register("order", CodeMsgMap{900100: "订单不存在"}); err != nil {
+		t.Fatalf("re-register order fail: %v", err)
+	}
+	e, ok := Lookup(900100)
+	if !ok || e.Msg != "订单不存在" {
+		t.Fatalf("expected 订单不存在, got %v %v", e, ok)
+	}
+	if owner, _ := CodeOwner(900101); owner != "order" {
+		t.Fatalf("expected owner order, got %s", owner)
+	}
+
+	// 冲突时返回错误且不注册任何错误码
+	err := Register("payment", CodeMsgMap{900101: "payment error", 900200: "payment timeout"})
+	if err == nil || !strings.Contains(err.Error(), "already registered by order") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if _, ok := Lookup(900200); ok {
+		t.Fatal("code 900200 should not be registered after conflict")
+	}
+	if _, ok := Registered()[900101]; !ok {
+		t.Fatal("code 900101 should be in Registered")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustRegister should panic on conflict")
+		}
+	}()
+	MustRegister("payment", CodeMsgMap{900100: "payment error"})
+}
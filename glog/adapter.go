@@ -0,0 +1,280 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 适配器将第三方库通过标准库 log 或 log/slog 输出的日志统一接入 glog，
+// 按模块名创建独立的 logger，日志级别受 SetLevel 动态控制。
+
+// adapterCallerSkip 适配器相对直接调用 Logger 多出的调用栈层数，用于将 caller 定位到第三方库的调用处，
+// log：Print → output → Write；slog：Info → log → Handle
+const adapterCallerSkip = 3
+
+// newModuleLogger 基于默认 logger 的配置创建指定模块的 logger，extraSkip 为额外跳过的调用栈层数
+func newModuleLogger(module string, extraSkip int, opts ...Option) (Logger, error) {
+	cfg := *GetDefaultLogConfig()
+	if defaultLoggerInstance != nil {
+		cfg = *defaultLoggerInstance.GetConfig()
+	}
+	if module != "" {
+		cfg.Module = module
+	}
+	opts = append([]Option{WithCallerSkip(defaultLogCallerSkip + extraSkip)}, opts...)
+	return NewLogger(&cfg, opts...)
+}
+
+// logw 按级别输出日志，Panic/Fatal 降级为 Error，避免第三方库的日志触发 panic 或退出进程
+func logw(logger Logger, ctx context.Context, level Level, msg string, kvs ...any) {
+	switch level {
+	case DebugLevel:
+		logger.Debugw(ctx, msg, kvs...)
+	case InfoLevel:
+		logger.Infow(ctx, msg, kvs...)
+	case WarnLevel:
+		logger.Warnw(ctx, msg, kvs...)
+	default:
+		logger.Errorw(ctx, msg, kvs...)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// 标准库 log 适配
+// ---------------------------------------------------------------------------
+
+// stdLogPrefixes 常见的日志级别前缀，匹配时忽略大小写并从消息中去除
+var stdLogPrefixes = []struct {
+	prefix string
+	level  Level
+}{
+	{"[debug]", DebugLevel},
+	{"[info]", InfoLevel},
+	{"[warn]", WarnLevel},
+	{"[warning]", WarnLevel},
+	{"[error]", ErrorLevel},
+	{"debug:", DebugLevel},
+	{"info:", InfoLevel},
+	{"warn:", WarnLevel},
+	{"warning:", WarnLevel},
+	{"error:", ErrorLevel},
+}
+
+type stdLogWriter struct {
+	logger Logger
+	level  Level
+}
+
+// Write 每次调用对应一条标准库日志，消息带有级别前缀时按前缀确定级别，否则使用默认级别
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, "\n"))
+	level := w.level
+	lower := strings.ToLower(msg)
+	for _, v := range stdLogPrefixes {
+		if strings.HasPrefix(lower, v.prefix) {
+			level = v.level
+			msg = strings.TrimSpace(msg[len(v.prefix):])
+			break
+		}
+	}
+	logw(w.logger, context.Background(), level, msg)
+	return len(p), nil
+}
+
+// NewStdLogger 创建输出到 glog 的标准库 *log.Logger，用于接收只支持 *log.Logger 的第三方库日志，
+// 如 http.Server.ErrorLog。level 为默认级别，消息以 "[WARN]"、"error:" 等前缀开头时按前缀确定级别。
+func NewStdLogger(module string, level Level, opts ...Option) (*log.Logger, error) {
+	logger, err := newModuleLogger(module, adapterCallerSkip, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return log.New(&stdLogWriter{logger: logger, level: level}, "", 0), nil
+}
+
+// RedirectStdLog 将标准库 log 包的全局输出重定向到 glog，返回的函数用于恢复原有输出
+func RedirectStdLog(module string, level Level, opts ...Option) (restore func(), err error) {
+	logger, err := newModuleLogger(module, adapterCallerSkip, opts...)
+	if err != nil {
+		return nil, err
+	}
+	prevWriter, prevFlags, prevPrefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(&stdLogWriter{logger: logger, level: level})
+	log.SetFlags(0)
+	log.SetPrefix("")
+	return func() {
+		log.SetOutput(prevWriter)
+		log.SetFlags(prevFlags)
+		log.SetPrefix(prevPrefix)
+	}, nil
+}
+
+// ---------------------------------------------------------------------------
+// log/slog 适配
+// ---------------------------------------------------------------------------
+
+// slogAdapter 实现 slog.Handler，将 slog 记录转为 glog 日志，group 以 "." 拼接为字段名前缀
+type slogAdapter struct {
+	logger Logger
+	module string
+	attrs  []any
+	prefix string
+}
+
+// NewSlogHandler 创建输出到 glog 的 slog.Handler，用于接入使用 log/slog 的第三方库
+func NewSlogHandler(module string, opts ...Option) (slog.Handler, error) {
+	logger, err := newModuleLogger(module, adapterCallerSkip, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if module == "" {
+		module = logger.GetConfig().Module
+	}
+	return &slogAdapter{logger: logger, module: module}, nil
+}
+
+// RedirectSlog 将 slog 默认 logger 设置为输出到 glog，返回的函数用于恢复原有默认 logger
+func RedirectSlog(module string, opts ...Option) (restore func(), err error) {
+	handler, err := NewSlogHandler(module, opts...)
+	if err != nil {
+		return nil, err
+	}
+	prev := slog.Default()
+	slog.SetDefault(slog.New(handler))
+	return func() {
+		slog.SetDefault(prev)
+	}, nil
+}
+
+// AsSlogHandler 将已创建的 Logger 包装为 slog.Handler，供接受 *slog.Logger 的第三方库使用，
+// 日志经过 logger 的格式化、ctx 字段提取（request_id、CtxWith 绑定的字段等）和各类 hook
+func AsSlogHandler(logger Logger) slog.Handler {
+	if s, ok := logger.(interface{ addCallerSkip(skip int) Logger }); ok {
+		logger = s.addCallerSkip(adapterCallerSkip)
+	}
+	module := logger.GetConfig().Module
+	if module == "" {
+		module = defaultModuleName
+	}
+	return &slogAdapter{logger: logger, module: module}
+}
+
+// Enabled 根据模块当前的日志级别判断，SetLevel 修改后立即生效
+func (h *slogAdapter) Enabled(_ context.Context, level slog.Level) bool {
+	current, ok := GetLevel(h.module)
+	if !ok {
+		return true
+	}
+	return level >= logLevelToSlog(current)
+}
+
+func (h *slogAdapter) Handle(ctx context.Context, r slog.Record) error {
+	kvs := make([]any, 0, len(h.attrs)+r.NumAttrs()*2)
+	kvs = append(kvs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		kvs = appendSlogAttr(kvs, h.prefix, a)
+		return true
+	})
+	logw(h.logger, ctx, slogLevelToLevel(r.Level), r.Message, kvs...)
+	return nil
+}
+
+func (h *slogAdapter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	next.attrs = append([]any(nil), h.attrs...)
+	for _, a := range attrs {
+		next.attrs = appendSlogAttr(next.attrs, h.prefix, a)
+	}
+	return &next
+}
+
+func (h *slogAdapter) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := *h
+	next.prefix = h.prefix + name + "."
+	return &next
+}
+
+// appendSlogAttr 将 slog.Attr 展开为 kv，group 类型递归展开
+func appendSlogAttr(kvs []any, prefix string, a slog.Attr) []any {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return kvs
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			kvs = appendSlogAttr(kvs, groupPrefix, ga)
+		}
+		return kvs
+	}
+	return append(kvs, prefix+a.Key, a.Value.Any())
+}
+
+// slogLevelToLevel 将 slog 级别映射为 glog 级别，自定义级别按所在区间归类
+func slogLevelToLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+// ---------------------------------------------------------------------------
+// *zap.Logger 适配
+// ---------------------------------------------------------------------------
+
+// slogZapCore 将 zap 日志转发到 slog.Handler，用于 slog 类型 Logger 的 Unwrap
+type slogZapCore struct {
+	handler slog.Handler
+}
+
+func (c *slogZapCore) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), logLevelToSlog(zapLevelToLevel(level)))
+}
+
+func (c *slogZapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slogZapCore{handler: c.handler.WithAttrs(zapFieldsToSlogAttrs(fields))}
+}
+
+func (c *slogZapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *slogZapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	r := slog.NewRecord(ent.Time, logLevelToSlog(zapLevelToLevel(ent.Level)), ent.Message, ent.Caller.PC)
+	r.AddAttrs(zapFieldsToSlogAttrs(fields)...)
+	return c.handler.Handle(context.Background(), r)
+}
+
+func (c *slogZapCore) Sync() error { return nil }
+
+// zapFieldsToSlogAttrs 按顺序将 zap.Field 转为 slog.Attr
+func zapFieldsToSlogAttrs(fields []zapcore.Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		for k, v := range enc.Fields {
+			attrs = append(attrs, slog.Any(k, v))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,120 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStdLogger(t *testing.T) {
+	stdLogger, err := NewStdLogger("std-test", InfoLevel)
+	require.Nil(t, err)
+	assert.NotNil(t, stdLogger)
+
+	var buf bytes.Buffer
+	logger, err := NewLogger(&LogConfig{Module: "std-test", Level: InfoLevel, Writer: WriterCustom}, WithSinks(NewWriterSink(&buf)))
+	require.Nil(t, err)
+	stdLogger = log.New(&stdLogWriter{logger: logger, level: InfoLevel}, "", 0)
+	stdLogger.Print("plain message")
+	stdLogger.Print("[WARN] warn message")
+	stdLogger.Print("debug: filtered message")
+
+	out := buf.String()
+	assert.Contains(t, out, `"level":"info"`)
+	assert.Contains(t, out, `"msg":"plain message"`)
+	assert.Contains(t, out, `"level":"warn"`)
+	assert.Contains(t, out, `"msg":"warn message"`)
+	assert.NotContains(t, out, "filtered message")
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	restore, err := RedirectStdLog("std-redirect", InfoLevel)
+	require.Nil(t, err)
+	log.Print("redirected message")
+	restore()
+	assert.Equal(t, log.LstdFlags, log.Flags())
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(&LogConfig{Module: "slog-test", Level: InfoLevel, Writer: WriterCustom}, WithSinks(NewWriterSink(&buf)))
+	require.Nil(t, err)
+	handler := &slogAdapter{logger: logger, module: "slog-test"}
+
+	sl := slog.New(handler).With("component", "lib").WithGroup("req")
+	sl.Info("slog message", "id", 1, slog.Group("user", "name", "tom"))
+	sl.Debug("filtered message")
+	sl.Log(context.Background(), slog.LevelError+4, "critical message")
+
+	out := buf.String()
+	assert.Contains(t, out, `"msg":"slog message"`)
+	assert.Contains(t, out, `"component":"lib"`)
+	assert.Contains(t, out, `"req.id":1`)
+	assert.Contains(t, out, `"req.user.name":"tom"`)
+	assert.Contains(t, out, `"module":"app.slog-test"`)
+	assert.NotContains(t, out, "filtered message")
+	assert.Contains(t, out, `"level":"error","ts"`)
+
+	// 模块级别动态调整后生效
+	require.Nil(t, SetLevel("slog-test", DebugLevel))
+	sl.Debug("debug after set level")
+	assert.Contains(t, buf.String(), "debug after set level")
+
+	restore, err := RedirectSlog("slog-redirect")
+	require.Nil(t, err)
+	_, ok := slog.Default().Handler().(*slogAdapter)
+	assert.True(t, ok)
+	restore()
+}
+
+func TestAsSlogHandler(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: fmt.Sprintf("as-slog-%d", loggerType), Level: InfoLevel, Writer: WriterCustom, ExtraKeys: []string{KeyAppRequestID}}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		sl := slog.New(AsSlogHandler(logger)).With("component", "kafka")
+		ctx := context.WithValue(context.Background(), KeyAppRequestID, "req-1")
+		sl.InfoContext(ctx, "consumer started", "topic", "orders")
+		sl.Debug("filtered message")
+
+		out := buf.String()
+		assert.Contains(t, out, "consumer started", loggerType)
+		assert.Contains(t, out, `"component":"kafka"`, loggerType)
+		assert.Contains(t, out, `"topic":"orders"`, loggerType)
+		assert.Contains(t, out, `"req-1"`, loggerType)
+		assert.NotContains(t, out, "filtered message", loggerType)
+		if loggerType == LoggerTypeZap {
+			assert.Contains(t, out, "adapter_test.go", "caller should point to the slog call site")
+		}
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: fmt.Sprintf("unwrap-%d", loggerType), Level: InfoLevel, Writer: WriterCustom}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		zl := logger.With("component", "gorm").Unwrap()
+		zl.With(zap.String("db", "demo")).Warn("slow query", zap.Int("rows", 3))
+		zl.Debug("filtered message")
+
+		out := buf.String()
+		assert.Contains(t, out, "slow query", loggerType)
+		assert.Contains(t, out, `"component":"gorm"`, loggerType)
+		assert.Contains(t, out, `"db":"demo"`, loggerType)
+		assert.Contains(t, out, `"rows":3`, loggerType)
+		assert.Contains(t, out, "adapter_test.go", loggerType)
+		assert.NotContains(t, out, "filtered message", loggerType)
+	}
+}
@@ -0,0 +1,188 @@
+package glog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy 异步模式下缓冲区满时的处理策略
+type OverflowPolicy string
+
+const (
+	// OverflowBlock 阻塞写入方直到缓冲区有空位，不丢日志，默认值
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDropOldest 丢弃缓冲区中最旧的日志，保留最新的日志
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowDropNew 丢弃当前写入的日志
+	OverflowDropNew OverflowPolicy = "drop_new"
+)
+
+const defaultAsyncBufferSize = 8192
+
+// AsyncConfig 异步写入配置，日志先写入有界环形缓冲区，由后台 goroutine 写入文件或 Sink，
+// 避免突发流量下磁盘 IO 阻塞请求路径。Close/Sync 时会等待缓冲区写完。
+type AsyncConfig struct {
+	// BufferSize 缓冲区可容纳的日志条数，默认 8192
+	BufferSize int `json:"buffer_size" yaml:"buffer_size"`
+	// Overflow 缓冲区满时的处理策略：block、drop_oldest、drop_new，默认 block
+	Overflow OverflowPolicy `json:"overflow" yaml:"overflow"`
+}
+
+// droppedEntries 所有异步 writer 因缓冲区满丢弃的日志条数
+var droppedEntries atomic.Uint64
+
+// DroppedEntries 返回异步模式下因缓冲区满被丢弃的日志总条数，可用于监控告警
+func DroppedEntries() uint64 {
+	return droppedEntries.Load()
+}
+
+// asyncWriter 基于环形缓冲区的异步 writer，Write 只做一次内存拷贝，由后台 goroutine 按批写入下游。
+// 单个 writer 按写入顺序输出，同一请求的日志不会乱序；需要跨输出还原顺序时开启 LogConfig.RequestSequence。
+type asyncWriter struct {
+	w      io.Writer
+	policy OverflowPolicy
+
+	mu      sync.Mutex
+	cond    *sync.Cond // 缓冲区状态变化时广播：有新日志、有空位、写完一批
+	buf     [][]byte
+	head    int
+	count   int
+	writing bool // 后台 goroutine 正在写入已取出的一批日志
+	closed  bool
+	done    chan struct{}
+}
+
+// newAsyncWriter 创建异步 writer 并启动后台写入 goroutine，cfg 为 nil 时使用默认配置
+func newAsyncWriter(w io.Writer, cfg *AsyncConfig) (*asyncWriter, error) {
+	size := defaultAsyncBufferSize
+	policy := OverflowBlock
+	if cfg != nil {
+		if cfg.BufferSize > 0 {
+			size = cfg.BufferSize
+		}
+		if cfg.Overflow != "" {
+			policy = cfg.Overflow
+		}
+	}
+	switch policy {
+	case OverflowBlock, OverflowDropOldest, OverflowDropNew:
+	default:
+		return nil, fmt.Errorf("glog: unknown async overflow policy %q", policy)
+	}
+
+	aw := &asyncWriter{
+		w:      w,
+		policy: policy,
+		buf:    make([][]byte, size),
+		done:   make(chan struct{}),
+	}
+	aw.cond = sync.NewCond(&aw.mu)
+	go aw.run()
+	return aw, nil
+}
+
+// Write 将日志拷贝到缓冲区后立即返回；关闭后直接同步写入下游
+func (aw *asyncWriter) Write(p []byte) (int, error) {
+	aw.mu.Lock()
+	if aw.closed {
+		aw.mu.Unlock()
+		return aw.w.Write(p)
+	}
+	for aw.count == len(aw.buf) {
+		switch aw.policy {
+		case OverflowDropNew:
+			aw.mu.Unlock()
+			droppedEntries.Add(1)
+			return len(p), nil
+		case OverflowDropOldest:
+			aw.buf[aw.head] = nil
+			aw.head = (aw.head + 1) % len(aw.buf)
+			aw.count--
+			droppedEntries.Add(1)
+		default:
+			aw.cond.Wait()
+			if aw.closed {
+				aw.mu.Unlock()
+				return aw.w.Write(p)
+			}
+		}
+	}
+	// 调用方（如 zap）会复用 p，必须拷贝
+	aw.buf[(aw.head+aw.count)%len(aw.buf)] = append([]byte(nil), p...)
+	aw.count++
+	aw.cond.Broadcast()
+	aw.mu.Unlock()
+	return len(p), nil
+}
+
+// Sync 等待缓冲区中的日志全部写入下游，再调用下游的 Sync
+func (aw *asyncWriter) Sync() error {
+	aw.mu.Lock()
+	for aw.count > 0 || aw.writing {
+		aw.cond.Wait()
+	}
+	aw.mu.Unlock()
+	return syncWriter(aw.w)
+}
+
+// Close 写完缓冲区中的日志并停止后台 goroutine，不关闭下游 writer
+func (aw *asyncWriter) Close() error {
+	aw.mu.Lock()
+	if aw.closed {
+		aw.mu.Unlock()
+		return nil
+	}
+	aw.closed = true
+	aw.cond.Broadcast()
+	aw.mu.Unlock()
+	<-aw.done
+	return syncWriter(aw.w)
+}
+
+// run 后台写入循环，每次取出缓冲区中的全部日志批量写入
+func (aw *asyncWriter) run() {
+	defer close(aw.done)
+	var batch [][]byte
+	for {
+		aw.mu.Lock()
+		for aw.count == 0 && !aw.closed {
+			aw.cond.Wait()
+		}
+		if aw.count == 0 && aw.closed {
+			aw.mu.Unlock()
+			return
+		}
+		batch = batch[:0]
+		for aw.count > 0 {
+			batch = append(batch, aw.buf[aw.head])
+			aw.buf[aw.head] = nil
+			aw.head = (aw.head + 1) % len(aw.buf)
+			aw.count--
+		}
+		aw.writing = true
+		aw.cond.Broadcast()
+		aw.mu.Unlock()
+
+		for _, entry := range batch {
+			if _, err := aw.w.Write(entry); err != nil {
+				fmt.Fprintf(os.Stderr, "glog: async write failed: %v\n", err)
+			}
+		}
+
+		aw.mu.Lock()
+		aw.writing = false
+		aw.cond.Broadcast()
+		aw.mu.Unlock()
+	}
+}
+
+// syncWriter 下游支持 Sync 时调用
+func syncWriter(w io.Writer) error {
+	if s, ok := w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedWriter 在 gate 关闭前阻塞写入，用于模拟慢速磁盘
+type gatedWriter struct {
+	gate    chan struct{}
+	started chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	entries []string
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (g *gatedWriter) Write(p []byte) (int, error) {
+	g.once.Do(func() { close(g.started) })
+	<-g.gate
+	g.mu.Lock()
+	g.entries = append(g.entries, string(p))
+	g.mu.Unlock()
+	return len(p), nil
+}
+
+func (g *gatedWriter) written() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.entries...)
+}
+
+func TestAsyncWriterBlock(t *testing.T) {
+	var buf bytes.Buffer
+	aw, err := newAsyncWriter(&buf, &AsyncConfig{BufferSize: 4})
+	require.Nil(t, err)
+
+	p := []byte("entry\n")
+	for i := 0; i < 100; i++ {
+		_, err := aw.Write(p)
+		require.Nil(t, err)
+	}
+	// 调用方复用 p 不影响已写入的日志
+	copy(p, "xxxxx\n")
+	require.Nil(t, aw.Close())
+	assert.Equal(t, strings.Repeat("entry\n", 100), buf.String())
+}
+
+func TestAsyncWriterDropPolicies(t *testing.T) {
+	for _, tc := range []struct {
+		policy OverflowPolicy
+		want   []string
+	}{
+		{OverflowDropNew, []string{"0", "1", "2", "3"}},
+		{OverflowDropOldest, []string{"0", "3", "4", "5"}},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			gw := newGatedWriter()
+			aw, err := newAsyncWriter(gw, &AsyncConfig{BufferSize: 3, Overflow: tc.policy})
+			require.Nil(t, err)
+
+			// 第一条被后台 goroutine 取出并阻塞在写入，缓冲区容纳后续 3 条，其余 2 条溢出
+			_, _ = aw.Write([]byte("0"))
+			<-gw.started
+			before := DroppedEntries()
+			for _, entry := range []string{"1", "2", "3", "4", "5"} {
+				_, err := aw.Write([]byte(entry))
+				require.Nil(t, err)
+			}
+			assert.Equal(t, uint64(2), DroppedEntries()-before)
+
+			close(gw.gate)
+			require.Nil(t, aw.Close())
+			assert.Equal(t, tc.want, gw.written())
+		})
+	}
+}
+
+func TestAsyncWriterSync(t *testing.T) {
+	gw := newGatedWriter()
+	aw, err := newAsyncWriter(gw, nil)
+	require.Nil(t, err)
+	_, _ = aw.Write([]byte("a"))
+	_, _ = aw.Write([]byte("b"))
+
+	synced := make(chan struct{})
+	go func() {
+		_ = aw.Sync()
+		close(synced)
+	}()
+	select {
+	case <-synced:
+		t.Fatal("Sync returned before entries were written")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(gw.gate)
+	<-synced
+	assert.Equal(t, []string{"a", "b"}, gw.written())
+	require.Nil(t, aw.Close())
+
+	// 关闭后同步写入
+	_, err = aw.Write([]byte("c"))
+	require.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, gw.written())
+}
+
+func TestAsyncWriterInvalidPolicy(t *testing.T) {
+	_, err := newAsyncWriter(&bytes.Buffer{}, &AsyncConfig{Overflow: "discard"})
+	assert.NotNil(t, err)
+}
+
+func TestAsyncLogger(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		dir := t.TempDir()
+		cfg := &LogConfig{
+			Service: "async",
+			Module:  "async",
+			Level:   InfoLevel,
+			Writer:  WriterFile,
+			Dir:     dir,
+			Async:   &AsyncConfig{BufferSize: 16},
+		}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType))
+		require.Nil(t, err)
+
+		for i := 0; i < 50; i++ {
+			logger.Infow(context.Background(), "async message", "i", i)
+		}
+		// zap file 模式同时输出到 stdout，测试环境下 stdout Sync 可能报错，这里只关心文件内容
+		_ = logger.Close()
+
+		content, err := os.ReadFile(filepath.Join(dir, time.Now().Format("20060102"), "async_full.log"))
+		require.Nil(t, err)
+		assert.Equal(t, 50, strings.Count(string(content), "async message"), loggerType)
+	}
+}
@@ -8,6 +8,8 @@
  */
 package glog
 
+import "fmt"
+
 // LogConfig 模块级别的日志配置
 type LogConfig struct {
 	// Service 服务名
@@ -18,20 +20,80 @@ type LogConfig struct {
 	Level Level `json:"level" yaml:"level"`
 	// Writer 日志输出类型
 	Writer WriterType `json:"writer" yaml:"writer"`
+	// Sinks Writer 为 custom 时引用的 Sink 名称，需先通过 RegisterSink 注册
+	Sinks []string `json:"sinks" yaml:"sinks"`
+	// Outputs 多输出配置，非空时忽略 Writer 和 Sinks，同一条日志按各输出的级别分别写入
+	Outputs []OutputConfig `json:"outputs" yaml:"outputs"`
+	// Encoding 日志编码格式，json 或 console，默认 json
+	Encoding EncodingType `json:"encoding" yaml:"encoding"`
+	// ConsoleEncoding 标准输出的编码格式，为空时使用 Encoding，可实现终端输出 console、文件输出 json
+	ConsoleEncoding EncodingType `json:"console_encoding" yaml:"console_encoding"`
+	// Encoder JSON 编码的时间格式、时区和标准字段名配置，为空时使用默认值
+	Encoder *EncoderConfig `json:"encoder" yaml:"encoder"`
 	// Dir 日志文件目录
 	Dir string `json:"dir" yaml:"dir"`
+	// WFLevel file 模式下额外写入 wf 文件的最低级别，默认 warn，设为 error 时 wf 文件只包含错误日志
+	WFLevel Level `json:"wf_level" yaml:"wf_level"`
+	// WFSuffix wf 文件的文件名后缀，文件名为 {service}_{suffix}.log，默认 wf，如设为 error 时输出 {service}_error.log
+	WFSuffix string `json:"wf_suffix" yaml:"wf_suffix"`
+	// DisableWF 为 true 时 file 模式只写 full 文件，不单独输出 wf 文件
+	DisableWF bool `json:"disable_wf" yaml:"disable_wf"`
 	// ExtraKeys 需要从上下文中提取的额外字段
 	ExtraKeys []string `json:"extra_keys" yaml:"extra_keys"`
 	// MaxSize 单个日志文件的最大大小（MB），超过则切割，默认 100
 	MaxSize int `json:"max_size" yaml:"max_size"`
-	// MaxBackups 保留的旧日志文件数量，默认 10
+	// Rotation 按时间切割的周期，daily 或 hourly，默认 daily；与 MaxSize 同时生效
+	Rotation RotationType `json:"rotation" yaml:"rotation"`
+	// MaxBackups 保留的旧日志文件数量（跨日期目录统计），默认 10
 	MaxBackups int `json:"max_backups" yaml:"max_backups"`
-	// MaxAge 保留日志文件的最大天数，默认 7
+	// MaxAge 保留日志文件的最大天数，默认 7，过期文件及清空后的日期目录会被删除
 	MaxAge int `json:"max_age" yaml:"max_age"`
-	// Compress 是否压缩旧日志文件，默认 false
+	// MaxTotalSize 单类日志文件（full 或 wf）占用磁盘的总大小上限（MB），超出时从最旧的文件开始删除，0 表示不限制
+	MaxTotalSize int `json:"max_total_size" yaml:"max_total_size"`
+	// FlatLayout 为 true 时不按日期分目录，日志文件直接写入 Dir，归档文件以时间戳后缀区分
+	FlatLayout bool `json:"flat_layout" yaml:"flat_layout"`
+	// CurrentLink 为 true 时在 Dir 下维护指向当前日期目录的软链接 current，
+	// 日志采集可配置固定路径 {Dir}/current/{Service}_full.log，跨天后无需修改；FlatLayout 时不生效
+	CurrentLink bool `json:"current_link" yaml:"current_link"`
+	// Compress 是否 gzip 压缩已完成切割的日志文件，默认 false
 	Compress bool `json:"compress" yaml:"compress"`
 	// EnableOTELTrace 是否自动注入 OpenTelemetry trace 关联字段
 	EnableOTELTrace bool `json:"enable_otel_trace" yaml:"enable_otel_trace"`
+	// MaxFieldSize 单个字段值的最大字节数，超出部分截断并追加 `...[truncated N bytes]` 标记，0 表示不限制
+	MaxFieldSize int `json:"max_field_size" yaml:"max_field_size"`
+	// FieldSizeLimits 按字段名单独设置的最大字节数，优先级高于 MaxFieldSize，<= 0 表示该字段不限制
+	FieldSizeLimits map[string]int `json:"field_size_limits" yaml:"field_size_limits"`
+	// Redaction 敏感数据脱敏配置，为空表示不脱敏
+	Redaction *RedactionConfig `json:"redaction" yaml:"redaction"`
+	// Async 异步写入配置，对 file 和 custom 输出生效，为空表示同步写入
+	Async *AsyncConfig `json:"async" yaml:"async"`
+	// RequestSequence 为 true 时为携带 request id 的日志追加 app.log.seq 字段，同一请求内从 1 递增，
+	// Sink 批量发送或多个输出交错写入导致同一请求的日志乱序时，可按 request id + 序号还原顺序
+	RequestSequence bool `json:"request_sequence" yaml:"request_sequence"`
+	// Metadata 写入每条日志的部署元数据（环境、区域、版本、代码提交等），为空表示不输出
+	Metadata *MetadataConfig `json:"metadata" yaml:"metadata"`
+	// CrashDump 崩溃现场配置，输出 Panic/Fatal 日志时将最近 N 条日志（含 Debug）写入崩溃文件，为空表示不开启
+	CrashDump *CrashDumpConfig `json:"crash_dump" yaml:"crash_dump"`
+	// StacktraceLevel 附加调用栈（stacktrace 字段）的最低级别，默认 panic；设为 none 时不附加，
+	// 设为 error 或 warn 时对应级别的日志也附加调用栈，日志量会明显增加
+	StacktraceLevel Level `json:"stacktrace_level" yaml:"stacktrace_level"`
+	// ModuleStacktraceLevels 按模块名设置附加调用栈的最低级别，优先级高于 StacktraceLevel，
+	// 如 {"gorm": "none", "payment": "warn"}
+	ModuleStacktraceLevels map[string]Level `json:"module_stacktrace_levels" yaml:"module_stacktrace_levels"`
+}
+
+// OutputConfig 单个输出目标的配置，如全部级别写文件、Warn 及以上写终端、Error 及以上写告警 Sink
+type OutputConfig struct {
+	// Writer 输出类型：console、file 或 custom
+	Writer WriterType `json:"writer" yaml:"writer"`
+	// Level 该输出的最低级别，为空时不额外过滤；实际生效的级别取模块级别与该级别中较高者
+	Level Level `json:"level" yaml:"level"`
+	// Encoding 编码格式，为空时 console 输出使用 ConsoleEncoding，其他输出使用 Encoding
+	Encoding EncodingType `json:"encoding" yaml:"encoding"`
+	// FileSuffix file 输出的文件名后缀，文件名为 {service}_{suffix}.log，默认 full，多个 file 输出的后缀不能重复
+	FileSuffix string `json:"file_suffix" yaml:"file_suffix"`
+	// Sinks custom 输出引用的 Sink 名称，为空时使用 WithSinks 传入的 Sink
+	Sinks []string `json:"sinks" yaml:"sinks"`
 }
 
 func AppendExtraKeys(cfg *LogConfig, keys ...string) {
@@ -49,6 +111,43 @@ func AppendExtraKeys(cfg *LogConfig, keys ...string) {
 	}
 }
 
+// encoding 返回非标准输出的编码格式
+func (cfg *LogConfig) encoding() EncodingType {
+	if cfg.Encoding == "" {
+		return EncodingJSON
+	}
+	return cfg.Encoding
+}
+
+// consoleEncoding 返回标准输出的编码格式
+func (cfg *LogConfig) consoleEncoding() EncodingType {
+	if cfg.ConsoleEncoding == "" {
+		return cfg.encoding()
+	}
+	return cfg.ConsoleEncoding
+}
+
+// wfFile 返回 wf 文件的后缀和最低级别，enabled 为 false 表示不输出 wf 文件
+func (cfg *LogConfig) wfFile() (suffix string, level Level, enabled bool, err error) {
+	if cfg.DisableWF {
+		return "", "", false, nil
+	}
+	suffix, level = cfg.WFSuffix, cfg.WFLevel
+	if suffix == "" {
+		suffix = "wf"
+	}
+	if suffix == "full" {
+		return "", "", false, fmt.Errorf("glog: wf suffix must not be %q", suffix)
+	}
+	if level == "" {
+		level = WarnLevel
+	}
+	if _, ok := logLevelMap[level]; !ok {
+		return "", "", false, fmt.Errorf("glog: invalid wf level %q", level)
+	}
+	return suffix, level, true, nil
+}
+
 func GetDefaultLogConfig() *LogConfig {
 	return &LogConfig{
 		Service:         defaultServiceName,
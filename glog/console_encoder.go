@@ -0,0 +1,103 @@
+package glog
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultConsoleMaxFieldSize console 编码下未配置 MaxFieldSize 时单个字段的最大字节数，避免大字段刷屏
+const defaultConsoleMaxFieldSize = 1024
+
+// consoleTimeLayout console 编码的时间格式，与 JSON 编码保持一致
+const consoleTimeLayout = "2006-01-02 15:04:05.000000"
+
+// ANSI 颜色码
+const (
+	colorRed     = 31
+	colorYellow  = 33
+	colorBlue    = 34
+	colorMagenta = 35
+)
+
+var consoleLevelColors = map[Level]int{
+	DebugLevel: colorMagenta,
+	InfoLevel:  colorBlue,
+	WarnLevel:  colorYellow,
+	ErrorLevel: colorRed,
+	PanicLevel: colorRed,
+	FatalLevel: colorRed,
+}
+
+// formatConsoleLevel 返回大写、按 5 个字符左对齐的级别文本
+func formatConsoleLevel(level Level) string {
+	return fmt.Sprintf("%-5s", strings.ToUpper(string(level)))
+}
+
+func colorize(s string, level Level) string {
+	code, ok := consoleLevelColors[level]
+	if !ok {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", code, s)
+}
+
+// newConsoleFieldSizeLimiter console 编码的字段大小限制，未配置 MaxFieldSize 时使用 defaultConsoleMaxFieldSize
+func newConsoleFieldSizeLimiter(cfg *LogConfig) *fieldSizeLimiter {
+	maxSize := defaultConsoleMaxFieldSize
+	var limits map[string]int
+	if cfg != nil {
+		if cfg.MaxFieldSize > 0 {
+			maxSize = cfg.MaxFieldSize
+		}
+		limits = cfg.FieldSizeLimits
+	}
+	return &fieldSizeLimiter{maxSize: maxSize, limits: limits}
+}
+
+// getZapConsoleEncoder 返回 zap console 编码器：级别对齐，color 为 true 时级别着色
+func getZapConsoleEncoder(cfg *zapLoggerConfig, color bool) zapcore.Encoder {
+	encoderCfg := zap.NewDevelopmentEncoderConfig()
+	encoderCfg.NameKey = "module"
+	encoderCfg.ConsoleSeparator = "  "
+	encoderCfg.EncodeTime = zapcore.TimeEncoderOfLayout(consoleTimeLayout)
+	encoderCfg.EncodeLevel = func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		level := zapLevelToLevel(l)
+		text := formatConsoleLevel(level)
+		if color {
+			text = colorize(text, level)
+		}
+		enc.AppendString(text)
+	}
+
+	customEncoder := &gZapEncoder{
+		Encoder: zapcore.NewConsoleEncoder(encoderCfg),
+	}
+	if cfg != nil {
+		customEncoder.messageHookFunc = cfg.messageHookFunc
+		customEncoder.redactor = cfg.redactor
+		customEncoder.sizeLimiter = cfg.consoleSizeLimiter
+	}
+	return customEncoder
+}
+
+func zapLevelToLevel(l zapcore.Level) Level {
+	switch l {
+	case zapcore.DebugLevel:
+		return DebugLevel
+	case zapcore.InfoLevel:
+		return InfoLevel
+	case zapcore.WarnLevel:
+		return WarnLevel
+	case zapcore.ErrorLevel:
+		return ErrorLevel
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return PanicLevel
+	case zapcore.FatalLevel:
+		return FatalLevel
+	default:
+		return Level(l.String())
+	}
+}
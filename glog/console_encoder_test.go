@@ -0,0 +1,66 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleEncoding(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: "console-test", Level: DebugLevel, Writer: WriterCustom, Encoding: EncodingConsole, MaxFieldSize: 8}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		logger.Infow(ctx, "console message", "key", strings.Repeat("v", 20))
+		logger.Warn(ctx, "warn message")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.False(t, strings.HasPrefix(lines[0], "{"), lines[0])
+		assert.Contains(t, lines[0], "INFO ")
+		assert.Contains(t, lines[0], "console message")
+		assert.Contains(t, lines[0], "...[truncated 12 bytes]")
+		assert.Contains(t, lines[1], "WARN ")
+		// 非终端输出不着色
+		assert.NotContains(t, buf.String(), "\x1b[")
+	}
+}
+
+func TestSlogConsoleHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newSlogConsoleHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}, true)
+	logger := slog.New(handler).With("service", "demo").WithGroup("req")
+	logger.Error("failed", "path", "/a b", slog.Group("user", "id", 1))
+	logger.Log(context.Background(), slogLevelPanic, "panic message")
+
+	out := buf.String()
+	assert.Contains(t, out, "\x1b[31mERROR\x1b[0m")
+	assert.Contains(t, out, `service=demo req.path="/a b" req.user.id=1`)
+	assert.Contains(t, out, "PANIC")
+}
+
+func TestSlogConsoleFileWf(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &LogConfig{Service: "console-file", Module: "console-file", Level: InfoLevel, Writer: WriterFile, Dir: dir, Encoding: EncodingConsole}
+	logger, err := NewLogger(cfg, WithLoggerType(LoggerTypeSlog))
+	require.Nil(t, err)
+	logger.Info(context.Background(), "info message")
+	logger.Warn(context.Background(), "warn message")
+	require.Nil(t, logger.Close())
+
+	data, err := os.ReadFile(filepath.Join(dir, time.Now().Format("20060102"), "console-file_wf.log"))
+	require.Nil(t, err)
+	assert.Contains(t, string(data), "warn message")
+	assert.NotContains(t, string(data), "info message")
+}
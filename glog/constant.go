@@ -29,7 +29,13 @@ const (
 	MsgEventNotice = "notice"
 
 	KeySkipLog                = "app.log.skip"
+	KeyAppLogSeq              = "app.log.seq"
 	KeyService                = "service"
+	KeyServiceVersion         = "service.version"
+	KeyDeploymentEnvironment  = "deployment.environment"
+	KeyCloudRegion            = "cloud.region"
+	KeyVcsRevision            = "vcs.revision"
+	KeyLogIndexDate           = "log.index_date"
 	KeyServerAddress          = "server.address"
 	KeyClientAddress          = "client.address"
 	KeyAppHandler             = "app.handler"
@@ -49,6 +55,13 @@ const (
 	KeyHttpResponseBody       = "http.response.body"
 	KeyHttpResponseBodySize   = "http.response.body.size"
 	KeyHttpRoute              = "http.route"
+	KeyHttpSlow               = "http.slow"
+	KeyHttpSLOBudgetMs        = "http.slo.budget_ms"
+	KeyHttpApiVersion         = "http.api.version"
+	KeyHttpDeprecated         = "http.deprecated"
+	KeyHttpDeprecatedAt       = "http.deprecated_at"
+	KeyHttpSunset             = "http.sunset"
+	KeyHttpDeprecationLink    = "http.deprecation.link"
 	KeyAppRequestStartTime    = "app.request.start_time"
 	KeyAppRequestEndTime      = "app.request.end_time"
 	KeyAppRequestDurationMs   = "app.request.duration_ms"
@@ -66,6 +79,7 @@ const (
 	KeyLogFilePath            = "log.file.path"
 	KeyErrorType              = "error.type"
 	KeyErrorMessage           = "error.message"
+	KeyPanic                  = "panic"
 
 	KeyEventName = "event.name"
 
@@ -101,6 +115,28 @@ type WriterType string
 const (
 	WriterConsole WriterType = "console"
 	WriterFile    WriterType = "file"
+	// WriterCustom 输出到自定义 Sink，通过 LogConfig.Sinks 引用已注册的 Sink 或通过 WithSinks 传入
+	WriterCustom WriterType = "custom"
+)
+
+// EncodingType 日志编码格式
+type EncodingType string
+
+const (
+	// EncodingJSON JSON 格式，默认值，便于日志采集和检索
+	EncodingJSON EncodingType = "json"
+	// EncodingConsole 人类可读的文本格式，级别对齐，输出到终端时着色，适用于本地开发
+	EncodingConsole EncodingType = "console"
+)
+
+// RotationType 日志文件按时间切割的周期
+type RotationType string
+
+const (
+	// RotationDaily 按天切割，默认值，每天一个日期目录
+	RotationDaily RotationType = "daily"
+	// RotationHourly 按小时切割，日期目录内每小时生成一个归档文件
+	RotationHourly RotationType = "hourly"
 )
 
 const (
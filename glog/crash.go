@@ -0,0 +1,177 @@
+package glog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/morehao/golib/gutil"
+	"go.uber.org/zap/zapcore"
+)
+
+const defaultCrashDumpSize = 200
+
+// CrashDumpConfig 崩溃现场配置，在内存中保留最近 N 条日志（不受日志级别限制，包含 Debug），
+// 输出 Panic/Fatal 日志时追加写入独立的崩溃文件，即使未开启 Debug 也能看到崩溃前的上下文。
+// 开启后低于模块级别的日志也会完成编码并写入内存，有一定的额外开销
+type CrashDumpConfig struct {
+	// Size 保留的最近日志条数，默认 200
+	Size int `json:"size" yaml:"size"`
+	// File 崩溃文件路径，默认 {Dir}/{Service}_crash.log；同一路径的多个模块共用一个缓冲区，按时间顺序交错记录
+	File string `json:"file" yaml:"file"`
+}
+
+// crashFile 返回崩溃文件路径
+func (cfg *LogConfig) crashFile() string {
+	if cfg.CrashDump.File != "" {
+		return cfg.CrashDump.File
+	}
+	dir, service := strings.TrimSuffix(cfg.Dir, "/"), cfg.Service
+	if dir == "" {
+		dir = defaultLogDir
+	}
+	if service == "" {
+		service = defaultServiceName
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s_crash.log", service))
+}
+
+// crashRing 最近日志的环形缓冲区，每次 Write 为一条完整的日志
+type crashRing struct {
+	mu      sync.Mutex
+	file    string
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+var crashRings gutil.SyncMap[string, *crashRing] // key 为崩溃文件路径
+
+// registerCrashRing 返回崩溃文件对应的缓冲区，同一文件以首次登记的容量为准
+func registerCrashRing(cfg *LogConfig) *crashRing {
+	size := cfg.CrashDump.Size
+	if size <= 0 {
+		size = defaultCrashDumpSize
+	}
+	file := cfg.crashFile()
+	ring, _ := crashRings.LoadOrStore(file, &crashRing{file: file, entries: make([][]byte, size)})
+	return ring
+}
+
+func (r *crashRing) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+	r.mu.Lock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+	return len(p), nil
+}
+
+func (r *crashRing) Sync() error { return nil }
+
+// snapshot 按写入顺序返回缓冲区中的日志
+func (r *crashRing) snapshot() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([][]byte(nil), r.entries[:r.next]...)
+	}
+	return append(append([][]byte(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// dump 将缓冲区中的日志追加写入崩溃文件并刷盘，失败时输出到标准错误
+func (r *crashRing) dump() {
+	if err := r.dumpTo(r.file); err != nil {
+		fmt.Fprintf(os.Stderr, "glog: dump crash log to %s failed: %v\n", r.file, err)
+	}
+}
+
+func (r *crashRing) dumpTo(file string) error {
+	entries := r.snapshot()
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, err = f.Write(entry); err != nil {
+			break
+		}
+	}
+	if syncErr := f.Sync(); err == nil {
+		err = syncErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// ---------------------------------------------------------------------------
+// zap
+// ---------------------------------------------------------------------------
+
+// crashCore 将全部级别的日志写入 crashRing，写入 Panic/Fatal 日志后转储到崩溃文件
+type crashCore struct {
+	zapcore.Core
+	ring *crashRing
+}
+
+func newCrashCore(encoder zapcore.Encoder, ring *crashRing) zapcore.Core {
+	return &crashCore{Core: zapcore.NewCore(encoder, ring, zapcore.DebugLevel), ring: ring}
+}
+
+func (c *crashCore) With(fields []zapcore.Field) zapcore.Core {
+	return &crashCore{Core: c.Core.With(fields), ring: c.ring}
+}
+
+func (c *crashCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *crashCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+	if ent.Level >= zapcore.DPanicLevel {
+		c.ring.dump()
+	}
+	return err
+}
+
+// ---------------------------------------------------------------------------
+// slog
+// ---------------------------------------------------------------------------
+
+// slogCrashHandler 将全部级别的日志写入 crashRing，处理 Panic/Fatal 日志后转储到崩溃文件
+type slogCrashHandler struct {
+	slog.Handler
+	ring *crashRing
+}
+
+func (h *slogCrashHandler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.Handler.Handle(ctx, r)
+	if r.Level >= slogLevelPanic {
+		h.ring.dump()
+	}
+	return err
+}
+
+func (h *slogCrashHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogCrashHandler{Handler: h.Handler.WithAttrs(attrs), ring: h.ring}
+}
+
+func (h *slogCrashHandler) WithGroup(name string) slog.Handler {
+	return &slogCrashHandler{Handler: h.Handler.WithGroup(name), ring: h.ring}
+}
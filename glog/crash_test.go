@@ -0,0 +1,57 @@
+package glog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrashDump(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		file := filepath.Join(t.TempDir(), "crash", "app_crash.log")
+		cfg := GetDefaultLogConfig()
+		cfg.Module = "crash_dump_test"
+		cfg.Level = ErrorLevel
+		cfg.CrashDump = &CrashDumpConfig{Size: 3, File: file}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		logger.Debug(ctx, "step 1")
+		logger.Infow(ctx, "step 2", "order_id", 1)
+		logger.Debugf(ctx, "step %d", 3)
+		_, statErr := os.Stat(file)
+		assert.True(t, os.IsNotExist(statErr))
+
+		func() {
+			defer func() {
+				assert.NotNil(t, recover())
+			}()
+			logger.Panicw(ctx, "boom", "reason", "nil map")
+		}()
+
+		// 只保留最近 3 条，低于模块级别的日志也会记录
+		content, err := os.ReadFile(file)
+		require.Nil(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[0], `"order_id":1`)
+		assert.Contains(t, lines[1], "step 3")
+		assert.Contains(t, lines[2], "boom")
+		assert.Contains(t, lines[2], `"reason":"nil map"`)
+	}
+}
+
+func TestCrashRingSnapshot(t *testing.T) {
+	ring := &crashRing{entries: make([][]byte, 2)}
+	assert.Empty(t, ring.snapshot())
+	for _, entry := range []string{"a", "b", "c"} {
+		ring.Write([]byte(entry))
+	}
+	assert.Equal(t, [][]byte{[]byte("b"), []byte("c")}, ring.snapshot())
+}
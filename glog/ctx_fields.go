@@ -0,0 +1,81 @@
+package glog
+
+import (
+	"context"
+	"fmt"
+)
+
+// ctxFieldsKey CtxWith 绑定的字段在 context 中的 key
+const ctxFieldsKey = "app.log.fields"
+
+// CtxWith 返回绑定了日志字段的子 context，之后所有使用该 ctx 的日志都会附加这些字段，
+// 场景示例：在 handler 入口绑定 user_id、order_id，后续调用无需重复传入。
+// kvs 为 key-value 交替的参数，也可直接传入 Field；多次调用时字段累加，同名字段以后绑定的为准。
+func CtxWith(ctx context.Context, kvs ...any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	fields := kvsToFields(kvs)
+	if len(fields) == 0 {
+		return ctx
+	}
+	parent := CtxFields(ctx)
+	merged := make([]Field, 0, len(parent)+len(fields))
+	for _, f := range parent {
+		if !containsFieldKey(fields, f.Key) {
+			merged = append(merged, f)
+		}
+	}
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, ctxFieldsKey, merged)
+}
+
+// CtxFields 返回 ctx 上通过 CtxWith 绑定的字段，返回值不可修改
+func CtxFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(ctxFieldsKey).([]Field)
+	return fields
+}
+
+// With 返回默认 logger 携带固定字段的子 Logger
+func With(kvs ...any) Logger {
+	return defaultLoggerInstance.With(kvs...)
+}
+
+// kvsToFields 将 key-value 交替的参数转为 []Field，与 zap 的处理方式保持一致：
+// 末尾孤立的值记为 "!extra"，非 string 的 key 记为 "!badKey{i}"
+func kvsToFields(kvs []any) []Field {
+	if len(kvs) == 0 {
+		return nil
+	}
+	fields := make([]Field, 0, (len(kvs)+1)/2)
+	for i := 0; i < len(kvs); i++ {
+		if f, ok := kvs[i].(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		if i == len(kvs)-1 {
+			fields = append(fields, Field{Key: "!extra", Value: kvs[i]})
+			break
+		}
+		key, ok := kvs[i].(string)
+		if !ok {
+			fields = append(fields, Field{Key: fmt.Sprintf("!badKey%d", i), Value: kvs[i]})
+			continue
+		}
+		fields = append(fields, Field{Key: key, Value: kvs[i+1]})
+		i++
+	}
+	return fields
+}
+
+func containsFieldKey(fields []Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCtxWith(t *testing.T) {
+	ctx := CtxWith(context.Background(), "user_id", 1, "order_id", "o-1")
+	ctx = CtxWith(ctx, KV("order_id", "o-2"), "odd")
+
+	fields := CtxFields(ctx)
+	require.Len(t, fields, 3)
+	assert.Equal(t, Field{Key: "user_id", Value: 1}, fields[0])
+	assert.Equal(t, Field{Key: "order_id", Value: "o-2"}, fields[1])
+	assert.Equal(t, Field{Key: "!extra", Value: "odd"}, fields[2])
+
+	// 无字段时返回原 ctx
+	base := context.Background()
+	assert.Equal(t, base, CtxWith(base))
+	assert.Nil(t, CtxFields(base))
+}
+
+func TestCtxWithFieldsLogged(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: "ctx-fields", Level: DebugLevel, Writer: WriterCustom}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		ctx := CtxWith(context.Background(), "user_id", 42)
+		logger.With("component", "order").Infow(ctx, "create order", "amount", 100)
+		logger.Info(context.Background(), "without ctx fields")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+
+		var entry map[string]any
+		require.Nil(t, json.Unmarshal([]byte(lines[0]), &entry), lines[0])
+		assert.EqualValues(t, 42, entry["user_id"])
+		assert.Equal(t, "order", entry["component"])
+		assert.EqualValues(t, 100, entry["amount"])
+		assert.NotContains(t, lines[1], "user_id")
+	}
+}
@@ -0,0 +1,107 @@
+package glog
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelContextKey 请求级日志级别在 context 中的 key，gin 等框架可通过 Set(LevelContextKey, level) 让框架上下文也生效
+const LevelContextKey = "app.log.level"
+
+// CtxWithLevel 返回提升了日志级别的子 context，使用该 ctx 的日志按 level 输出，不受模块级别限制，
+// 场景示例：trace 被采样或携带调试请求头的请求强制输出 Debug 日志，按需抓取特定链路的详细日志。
+// 只会放宽模块级别，Outputs 中单个输出配置的最低级别仍然生效
+func CtxWithLevel(ctx context.Context, level Level) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := logLevelMap[level]; !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, LevelContextKey, level)
+}
+
+// CtxLevel 返回 ctx 上通过 CtxWithLevel 设置的日志级别
+func CtxLevel(ctx context.Context) (Level, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	level, ok := ctx.Value(LevelContextKey).(Level)
+	if !ok {
+		return "", false
+	}
+	_, ok = logLevelMap[level]
+	return level, ok
+}
+
+// ---------------------------------------------------------------------------
+// zap
+// ---------------------------------------------------------------------------
+
+// forcedLevelKey 携带请求级日志级别的内部字段名，字段类型为 SkipType，不会被编码输出
+const forcedLevelKey = "glog.forced_level"
+
+// withForcedLevel 返回按 level 放宽模块级别的 logger，通过 With 一个内部字段通知各 ctxLevelCore
+func withForcedLevel(logger *zap.Logger, level Level) *zap.Logger {
+	return logger.With(zap.Field{Key: forcedLevelKey, Type: zapcore.SkipType, Interface: levelToZapLevel(level)})
+}
+
+// ctxLevelCore 在模块级别之外支持请求级日志级别：内层 core 只按输出自身的最低级别过滤，
+// 模块级别在本层判断，With 到 forcedLevelKey 字段后放宽为该字段的级别
+type ctxLevelCore struct {
+	zapcore.Core
+	module *moduleLevel
+	forced *zapcore.Level // nil 表示未放宽
+}
+
+// newCtxLevelCore 创建受模块级别和请求级日志级别控制的 core，min 为输出自身的最低级别，为空时不额外过滤
+func newCtxLevelCore(encoder zapcore.Encoder, writer zapcore.WriteSyncer, module *moduleLevel, min Level) zapcore.Core {
+	minLevel := zapcore.DebugLevel
+	if min != "" {
+		minLevel = levelToZapLevel(min)
+	}
+	return &ctxLevelCore{Core: zapcore.NewCore(encoder, writer, minLevel), module: module}
+}
+
+func (c *ctxLevelCore) Enabled(level zapcore.Level) bool {
+	if !c.Core.Enabled(level) {
+		return false
+	}
+	return c.module.Enabled(level) || (c.forced != nil && level >= *c.forced)
+}
+
+func (c *ctxLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	next := &ctxLevelCore{module: c.module, forced: c.forced}
+	rest := fields[:0:0]
+	for _, f := range fields {
+		if f.Key == forcedLevelKey && f.Type == zapcore.SkipType {
+			if level, ok := f.Interface.(zapcore.Level); ok {
+				next.forced = &level
+			}
+			continue
+		}
+		rest = append(rest, f)
+	}
+	next.Core = c.Core.With(rest)
+	return next
+}
+
+func (c *ctxLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// ---------------------------------------------------------------------------
+// slog
+// ---------------------------------------------------------------------------
+
+// ctxLevelEnabled 判断 ctx 上的请求级日志级别是否放行 level，min 为输出自身的最低级别
+func ctxLevelEnabled(ctx context.Context, level, min slog.Level) bool {
+	forced, ok := CtxLevel(ctx)
+	return ok && level >= min && level >= logLevelToSlog(forced)
+}
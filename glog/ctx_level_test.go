@@ -0,0 +1,55 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCtxWithLevel(t *testing.T) {
+	var alert bytes.Buffer
+	require.Nil(t, RegisterSink("ctx-level-alert", NewWriterSink(&alert)))
+
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		alert.Reset()
+		var all bytes.Buffer
+		cfg := &LogConfig{
+			Module: fmt.Sprintf("ctx-level-%d", loggerType),
+			Level:  WarnLevel,
+			Outputs: []OutputConfig{
+				{Writer: WriterCustom},
+				{Writer: WriterCustom, Level: ErrorLevel, Sinks: []string{"ctx-level-alert"}},
+			},
+		}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&all)))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		debugCtx := CtxWithLevel(ctx, DebugLevel)
+		level, ok := CtxLevel(debugCtx)
+		assert.True(t, ok)
+		assert.Equal(t, DebugLevel, level)
+
+		logger.Debug(ctx, "normal debug")
+		logger.Debug(debugCtx, "forced debug")
+		logger.With("component", "order").Infow(CtxWith(debugCtx, "order_id", "o-1"), "forced info")
+		logger.Error(debugCtx, "forced error")
+		require.Nil(t, logger.Close())
+
+		assert.NotContains(t, all.String(), "normal debug", loggerType)
+		assert.Contains(t, all.String(), "forced debug", loggerType)
+		assert.Contains(t, all.String(), "forced info", loggerType)
+		assert.Contains(t, all.String(), "o-1", loggerType)
+		assert.NotContains(t, all.String(), forcedLevelKey, loggerType)
+		// 输出自身的最低级别仍然生效
+		assert.NotContains(t, alert.String(), "forced debug", loggerType)
+		assert.Contains(t, alert.String(), "forced error", loggerType)
+	}
+
+	_, ok := CtxLevel(CtxWithLevel(context.Background(), "verbose"))
+	assert.False(t, ok)
+}
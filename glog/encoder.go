@@ -0,0 +1,120 @@
+package glog
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// EncoderConfig JSON 编码配置，用于让日志直接匹配已有的 ELK 等索引模板，无需额外的清洗流程。
+// 仅对 json 编码生效，字段为空时保持默认值
+type EncoderConfig struct {
+	// TimeLayout 时间格式，如 time.RFC3339Nano，zap 默认 "2006-01-02 15:04:05.000000"，slog 默认 RFC3339 毫秒精度
+	TimeLayout string `json:"time_layout" yaml:"time_layout"`
+	// TimeZone 时区：local、UTC 或 IANA 时区名（如 Asia/Shanghai），默认使用本地时区
+	TimeZone string `json:"time_zone" yaml:"time_zone"`
+	// TimeKey 时间字段名，zap 默认 ts，slog 默认 time
+	TimeKey string `json:"time_key" yaml:"time_key"`
+	// LevelKey 级别字段名，默认 level
+	LevelKey string `json:"level_key" yaml:"level_key"`
+	// MessageKey 消息字段名，默认 msg
+	MessageKey string `json:"message_key" yaml:"message_key"`
+	// ModuleKey 模块字段名，默认 module
+	ModuleKey string `json:"module_key" yaml:"module_key"`
+	// CallerKey 调用位置字段名，zap 默认 caller，slog 默认 source
+	CallerKey string `json:"caller_key" yaml:"caller_key"`
+}
+
+// defaultTimeLayout zap JSON 编码的默认时间格式
+const defaultTimeLayout = "2006-01-02 15:04:05.000000"
+
+// encoderSettings 解析后的 EncoderConfig
+type encoderSettings struct {
+	EncoderConfig
+	location *time.Location // nil 表示不转换时区
+}
+
+// newEncoderSettings 解析 cfg.Encoder，未配置时返回 nil
+func newEncoderSettings(cfg *LogConfig) (*encoderSettings, error) {
+	if cfg == nil || cfg.Encoder == nil {
+		return nil, nil
+	}
+	s := &encoderSettings{EncoderConfig: *cfg.Encoder}
+	switch strings.ToLower(s.TimeZone) {
+	case "":
+	case "local":
+		s.location = time.Local
+	case "utc":
+		s.location = time.UTC
+	default:
+		loc, err := time.LoadLocation(s.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("glog: invalid time zone %q: %w", s.TimeZone, err)
+		}
+		s.location = loc
+	}
+	return s, nil
+}
+
+// applyZap 将配置应用到 zap 的 JSON 编码配置
+func (s *encoderSettings) applyZap(encoderCfg *zapcore.EncoderConfig) {
+	if s == nil {
+		return
+	}
+	setKey(&encoderCfg.TimeKey, s.TimeKey)
+	setKey(&encoderCfg.LevelKey, s.LevelKey)
+	setKey(&encoderCfg.MessageKey, s.MessageKey)
+	setKey(&encoderCfg.NameKey, s.ModuleKey)
+	setKey(&encoderCfg.CallerKey, s.CallerKey)
+
+	layout := s.TimeLayout
+	if layout == "" {
+		layout = defaultTimeLayout
+	}
+	location := s.location
+	encoderCfg.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		if location != nil {
+			t = t.In(location)
+		}
+		enc.AppendString(t.Format(layout))
+	}
+}
+
+// replaceSlogAttr 格式化 slog 的时间字段并重命名标准字段，需在脱敏、截断之后执行，
+// 这两步按 slog 的标准字段名跳过内置字段
+func (s *encoderSettings) replaceSlogAttr(groups []string, a slog.Attr) slog.Attr {
+	if s == nil || len(groups) != 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.TimeKey:
+		if t, ok := a.Value.Any().(time.Time); ok {
+			if s.location != nil {
+				t = t.In(s.location)
+			}
+			a.Value = slog.TimeValue(t)
+			if s.TimeLayout != "" {
+				a.Value = slog.StringValue(t.Format(s.TimeLayout))
+			}
+		}
+		setKey(&a.Key, s.TimeKey)
+	case slog.LevelKey:
+		setKey(&a.Key, s.LevelKey)
+	case slog.MessageKey:
+		setKey(&a.Key, s.MessageKey)
+	case slog.SourceKey:
+		setKey(&a.Key, s.CallerKey)
+	case "module":
+		setKey(&a.Key, s.ModuleKey)
+	}
+	return a
+}
+
+func setKey(dst *string, key string) {
+	if key != "" {
+		*dst = key
+	}
+}
@@ -0,0 +1,63 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoderConfig(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{
+			Module: fmt.Sprintf("encoder-%d", loggerType),
+			Writer: WriterCustom,
+			Encoder: &EncoderConfig{
+				TimeLayout: time.RFC3339,
+				TimeZone:   "UTC",
+				TimeKey:    "@timestamp",
+				LevelKey:   "log.level",
+				MessageKey: "message",
+				ModuleKey:  "log.logger",
+				CallerKey:  "log.origin",
+			},
+		}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+		logger.Infow(context.Background(), "hello", "order_id", "o-1")
+		require.Nil(t, logger.Close())
+
+		var entry map[string]any
+		require.Nil(t, json.Unmarshal(buf.Bytes(), &entry), buf.String())
+		assert.Equal(t, "hello", entry["message"], loggerType)
+		assert.Contains(t, entry["log.logger"], cfg.Module, loggerType)
+		assert.NotNil(t, entry["log.level"], loggerType)
+		assert.NotNil(t, entry["log.origin"], loggerType)
+		assert.Equal(t, "o-1", entry["order_id"], loggerType)
+		for _, key := range []string{"msg", "level", "ts", "time", "caller", "source", "module"} {
+			assert.NotContains(t, entry, key, loggerType)
+		}
+
+		ts, ok := entry["@timestamp"].(string)
+		require.True(t, ok, loggerType)
+		parsed, err := time.Parse(time.RFC3339, ts)
+		require.Nil(t, err, ts)
+		_, offset := parsed.Zone()
+		assert.Equal(t, 0, offset, ts)
+		assert.WithinDuration(t, time.Now(), parsed, time.Minute)
+	}
+}
+
+func TestEncoderConfigInvalidTimeZone(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		cfg := &LogConfig{Module: "encoder-invalid", Writer: WriterConsole, Encoder: &EncoderConfig{TimeZone: "Mars/Olympus"}}
+		_, err := NewLogger(cfg, WithLoggerType(loggerType))
+		assert.NotNil(t, err, loggerType)
+	}
+}
@@ -2,6 +2,7 @@ package glog
 
 import (
 	"context"
+	"errors"
 )
 
 type loggerInstance struct {
@@ -69,6 +70,10 @@ func Debugw(ctx context.Context, msg string, kvs ...any) {
 	defaultLoggerInstance.Debugw(ctx, msg, kvs...)
 }
 
+func Debugt(ctx context.Context, tmpl string, args ...any) {
+	defaultLoggerInstance.Debugt(ctx, tmpl, args...)
+}
+
 func Info(ctx context.Context, args ...any) {
 	defaultLoggerInstance.Info(ctx, args...)
 }
@@ -81,6 +86,10 @@ func Infow(ctx context.Context, msg string, kvs ...any) {
 	defaultLoggerInstance.Infow(ctx, msg, kvs...)
 }
 
+func Infot(ctx context.Context, tmpl string, args ...any) {
+	defaultLoggerInstance.Infot(ctx, tmpl, args...)
+}
+
 func Warn(ctx context.Context, args ...any) {
 	defaultLoggerInstance.Warn(ctx, args...)
 }
@@ -93,6 +102,10 @@ func Warnw(ctx context.Context, msg string, kvs ...any) {
 	defaultLoggerInstance.Warnw(ctx, msg, kvs...)
 }
 
+func Warnt(ctx context.Context, tmpl string, args ...any) {
+	defaultLoggerInstance.Warnt(ctx, tmpl, args...)
+}
+
 func Error(ctx context.Context, args ...any) {
 	defaultLoggerInstance.Error(ctx, args...)
 }
@@ -105,6 +118,10 @@ func Errorw(ctx context.Context, msg string, kvs ...any) {
 	defaultLoggerInstance.Errorw(ctx, msg, kvs...)
 }
 
+func Errort(ctx context.Context, tmpl string, args ...any) {
+	defaultLoggerInstance.Errort(ctx, tmpl, args...)
+}
+
 func Panic(ctx context.Context, args ...any) {
 	defaultLoggerInstance.Panic(ctx, args...)
 }
@@ -117,6 +134,10 @@ func Panicw(ctx context.Context, msg string, kvs ...any) {
 	defaultLoggerInstance.Panicw(ctx, msg, kvs...)
 }
 
+func Panict(ctx context.Context, tmpl string, args ...any) {
+	defaultLoggerInstance.Panict(ctx, tmpl, args...)
+}
+
 func Fatal(ctx context.Context, args ...any) {
 	defaultLoggerInstance.Fatal(ctx, args...)
 }
@@ -129,6 +150,12 @@ func Fatalw(ctx context.Context, msg string, kvs ...any) {
 	defaultLoggerInstance.Fatalw(ctx, msg, kvs...)
 }
 
+func Fatalt(ctx context.Context, tmpl string, args ...any) {
+	defaultLoggerInstance.Fatalt(ctx, tmpl, args...)
+}
+
+// Close 按注册顺序执行关闭回调，再关闭默认 logger
 func Close() error {
-	return defaultLoggerInstance.Logger.Close()
+	hookErr := runCloseHooks()
+	return errors.Join(hookErr, defaultLoggerInstance.Logger.Close())
 }
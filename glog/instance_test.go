@@ -2,9 +2,14 @@ package glog
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultLogger(t *testing.T) {
@@ -66,4 +71,70 @@ func TestAppendExtraKeys(t *testing.T) {
 	cfg = &LogConfig{ExtraKeys: []string{KeyAppRequestID}}
 	AppendExtraKeys(cfg, KeyAppRequestID)
 	assert.Equal(t, []string{KeyAppRequestID}, cfg.ExtraKeys)
-}
\ No newline at end of file
+}
+
+func TestCloseHooks(t *testing.T) {
+	var order []string
+	RegisterCloseHook("first", func() error {
+		order = append(order, "first")
+		return nil
+	})
+	RegisterCloseHook("second", func() error {
+		order = append(order, "second")
+		return errors.New("flush failed")
+	})
+	RegisterCloseHook("third", func() error {
+		order = append(order, "third")
+		return nil
+	})
+	RegisterCloseHook("nil", nil)
+
+	err := Close()
+	assert.ErrorContains(t, err, "close hook second: flush failed")
+	assert.Equal(t, []string{"first", "second", "third"}, order)
+
+	// 回调执行后清空，再次 Close 不会重复执行
+	order = nil
+	assert.Nil(t, runCloseHooks())
+	assert.Empty(t, order)
+	InitLogger(GetDefaultLogConfig())
+}
+
+func TestFlush(t *testing.T) {
+	defer InitLogger(GetDefaultLogConfig())
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		gw := newGatedWriter()
+		cfg := &LogConfig{Module: fmt.Sprintf("flush-%d", loggerType), Writer: WriterCustom, Async: &AsyncConfig{BufferSize: 16}}
+		require.Nil(t, InitLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(gw))))
+
+		ctx := context.Background()
+		Info(ctx, "before flush")
+		<-gw.started
+
+		// 下游阻塞时 Flush 超时返回
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		assert.ErrorIs(t, Flush(timeoutCtx), context.DeadlineExceeded, loggerType)
+		cancel()
+
+		close(gw.gate)
+		require.Nil(t, Flush(ctx))
+		assert.Contains(t, strings.Join(gw.written(), ""), "before flush", loggerType)
+
+		// FlushOnPanic 记录 panic 并刷新日志后重新抛出
+		func() {
+			defer func() {
+				assert.Equal(t, "boom", recover(), loggerType)
+			}()
+			func() {
+				defer FlushOnPanic()
+				panic("boom")
+			}()
+		}()
+		assert.Contains(t, strings.Join(gw.written(), ""), "panic recovered", loggerType)
+
+		// Flush 不释放资源，之后仍可继续写日志
+		Info(ctx, "after flush")
+		require.Nil(t, Flush(ctx))
+		assert.Contains(t, strings.Join(gw.written(), ""), "after flush", loggerType)
+	}
+}
@@ -0,0 +1,166 @@
+package glog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+
+	"github.com/morehao/golib/gutil"
+	"go.uber.org/zap/zapcore"
+)
+
+// moduleLevel 模块级别的动态日志级别，同一模块的所有 logger 共享，
+// 同时实现 zapcore.LevelEnabler 和 slog.Leveler，修改后立即对已创建的 logger 生效
+type moduleLevel struct {
+	level atomic.Value // Level
+	base  atomic.Value // Level，InitLogger 时配置的级别，用于信号切换后恢复
+}
+
+func (l *moduleLevel) get() Level {
+	return l.level.Load().(Level)
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (l *moduleLevel) Enabled(level zapcore.Level) bool {
+	return level >= levelToZapLevel(l.get())
+}
+
+// Level 实现 slog.Leveler
+func (l *moduleLevel) Level() slog.Level {
+	return logLevelToSlog(l.get())
+}
+
+var moduleLevels gutil.SyncMap[string, *moduleLevel]
+
+// registerModuleLevel 创建 logger 时登记模块级别，同名模块以最近一次配置为准
+func registerModuleLevel(module string, level Level) *moduleLevel {
+	if module == "" {
+		module = defaultModuleName
+	}
+	if _, ok := logLevelMap[level]; !ok {
+		level = InfoLevel
+	}
+	ml := &moduleLevel{}
+	ml.level.Store(level)
+	ml.base.Store(level)
+	if v, loaded := moduleLevels.LoadOrStore(module, ml); loaded {
+		ml = v
+		ml.level.Store(level)
+		ml.base.Store(level)
+	}
+	return ml
+}
+
+// SetLevel 运行时修改模块的日志级别，无需重启即可在线上开启 debug 日志
+func SetLevel(module string, level Level) error {
+	if _, ok := logLevelMap[level]; !ok {
+		return fmt.Errorf("invalid log level: %s", level)
+	}
+	v, ok := moduleLevels.Load(module)
+	if !ok {
+		return fmt.Errorf("log module not found: %s", module)
+	}
+	v.level.Store(level)
+	return nil
+}
+
+// GetLevel 获取模块当前的日志级别
+func GetLevel(module string) (Level, bool) {
+	v, ok := moduleLevels.Load(module)
+	if !ok {
+		return "", false
+	}
+	return v.get(), true
+}
+
+// GetModuleLevels 获取所有模块当前的日志级别
+func GetModuleLevels() map[string]Level {
+	levels := make(map[string]Level)
+	moduleLevels.Range(func(module string, ml *moduleLevel) bool {
+		levels[module] = ml.get()
+		return true
+	})
+	return levels
+}
+
+// levelRequest LevelHandler 修改级别的请求体
+type levelRequest struct {
+	Module string `json:"module"`
+	Level  Level  `json:"level"`
+}
+
+// LevelHandler 返回查看和修改日志级别的 HTTP handler：
+// GET 返回所有模块的级别；PUT/POST 请求体为 {"module":"es","level":"debug"}，修改指定模块的级别。
+// 该接口可修改线上日志行为，应注册在内部端口或鉴权路由下。
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := SetLevel(req.Module, req.Level); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(GetModuleLevels())
+	})
+}
+
+// ToggleDebugOnSignal 收到信号时在 debug 级别和配置级别之间切换指定模块，未指定模块时切换所有模块。
+// 常用于 SIGUSR1：kill -USR1 <pid> 开启 debug，再次发送恢复。返回的函数用于停止监听。
+func ToggleDebugOnSignal(sig os.Signal, modules ...string) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sig)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				toggleDebug(modules)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+func toggleDebug(modules []string) {
+	if len(modules) == 0 {
+		for module := range GetModuleLevels() {
+			modules = append(modules, module)
+		}
+	}
+	for _, module := range modules {
+		ml, ok := moduleLevels.Load(module)
+		if !ok {
+			continue
+		}
+		if ml.get() == DebugLevel {
+			ml.level.Store(ml.base.Load().(Level))
+		} else {
+			ml.level.Store(DebugLevel)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package glog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLevel(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		dir := t.TempDir()
+		module := "level-test-" + string(rune('0'+loggerType))
+		cfg := &LogConfig{Service: "level-service", Module: module, Level: InfoLevel, Writer: WriterFile, Dir: dir}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		logger.Debug(ctx, "debug before")
+		assert.Nil(t, SetLevel(module, DebugLevel))
+		level, ok := GetLevel(module)
+		assert.True(t, ok)
+		assert.Equal(t, DebugLevel, level)
+		logger.Debug(ctx, "debug after")
+		_ = logger.Close()
+
+		data, err := os.ReadFile(filepath.Join(dir, time.Now().Format("20060102"), "level-service_full.log"))
+		require.Nil(t, err)
+		assert.NotContains(t, string(data), "debug before")
+		assert.Contains(t, string(data), "debug after")
+	}
+
+	assert.NotNil(t, SetLevel("not-exist", DebugLevel))
+	assert.NotNil(t, SetLevel(defaultModuleName, Level("verbose")))
+}
+
+func TestLevelHandler(t *testing.T) {
+	_, err := NewLogger(&LogConfig{Module: "handler-test", Level: WarnLevel, Writer: WriterConsole})
+	require.Nil(t, err)
+	handler := LevelHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"module":"handler-test","level":"debug"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"handler-test":"debug"`)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"module":"handler-test","level":"verbose"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/log/level", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestToggleDebug(t *testing.T) {
+	_, err := NewLogger(&LogConfig{Module: "signal-test", Level: ErrorLevel, Writer: WriterConsole})
+	require.Nil(t, err)
+
+	toggleDebug([]string{"signal-test"})
+	level, _ := GetLevel("signal-test")
+	assert.Equal(t, DebugLevel, level)
+
+	toggleDebug([]string{"signal-test"})
+	level, _ = GetLevel("signal-test")
+	assert.Equal(t, ErrorLevel, level)
+}
@@ -2,6 +2,8 @@ package glog
 
 import (
 	"context"
+
+	"go.uber.org/zap"
 )
 
 type Logger interface {
@@ -23,9 +25,23 @@ type Logger interface {
 	Fatal(ctx context.Context, args ...any)
 	Fatalf(ctx context.Context, format string, args ...any)
 	Fatalw(ctx context.Context, msg string, kvs ...any)
+	// Debugt、Infot 等为消息模板风格，如 Infot(ctx, "user {user_id} purchased {sku}", 42, "A-1")：
+	// 占位符按顺序绑定参数，渲染为可读消息的同时以占位符名为 key 输出结构化字段，剩余参数按 kvs 处理。
+	Debugt(ctx context.Context, tmpl string, args ...any)
+	Infot(ctx context.Context, tmpl string, args ...any)
+	Warnt(ctx context.Context, tmpl string, args ...any)
+	Errort(ctx context.Context, tmpl string, args ...any)
+	Panict(ctx context.Context, tmpl string, args ...any)
+	Fatalt(ctx context.Context, tmpl string, args ...any)
 	// With 返回一个携带固定 kv 字段的子 Logger，场景示例：用于在请求链路中绑定 request_id 等字段。
 	With(kvs ...any) Logger
+	// Sync 将缓冲中的日志写入下游并刷盘，不释放资源，之后仍可继续写日志。
+	Sync() error
 	// Close 确保所有缓冲日志写入完毕并释放底层文件资源。
 	Close() error
 	GetConfig() *LogConfig
+	// Unwrap 返回写入同一输出的 *zap.Logger，供只接受 *zap.Logger 的第三方库使用，caller 定位到第三方库的调用处。
+	// zap 类型返回共享同一 core 的 logger，slog 类型返回桥接到 slog handler 的 logger；
+	// 没有 ctx，不经过 ctx 字段提取、FieldHook 和 MetricsHook，需要这些能力时使用 AsSlogHandler。
+	Unwrap() *zap.Logger
 }
@@ -0,0 +1,92 @@
+package glog
+
+import (
+	"os"
+	"runtime/debug"
+	"sort"
+	"time"
+)
+
+// 部署元数据对应的环境变量，MetadataConfig 中对应字段为空时读取
+const (
+	EnvAppEnv     = "APP_ENV"
+	EnvAppRegion  = "APP_REGION"
+	EnvAppVersion = "APP_VERSION"
+	EnvGitCommit  = "GIT_COMMIT"
+)
+
+// MetadataConfig 部署元数据配置，字段在创建 logger 时解析一次并写入每条日志，
+// 日志管道可直接按环境、版本路由和设置保留策略，无需额外的 enrich 步骤
+type MetadataConfig struct {
+	// Env 部署环境，如 prod，输出为 deployment.environment，为空时读取环境变量 APP_ENV
+	Env string `json:"env" yaml:"env"`
+	// Region 部署区域，输出为 cloud.region，为空时读取环境变量 APP_REGION
+	Region string `json:"region" yaml:"region"`
+	// Version 服务版本，输出为 service.version，为空时读取环境变量 APP_VERSION，仍为空时使用构建信息中的主模块版本
+	Version string `json:"version" yaml:"version"`
+	// GitCommit 代码提交，输出为 vcs.revision，为空时读取环境变量 GIT_COMMIT，仍为空时使用构建信息中的 vcs.revision
+	GitCommit string `json:"git_commit" yaml:"git_commit"`
+	// Fields 其他静态字段，如 {"cluster": "bj-1"}
+	Fields map[string]string `json:"fields" yaml:"fields"`
+	// EnvFields 从环境变量读取的字段，key 为字段名，value 为环境变量名，如 {"k8s.pod.name": "POD_NAME"}，环境变量为空时不输出
+	EnvFields map[string]string `json:"env_fields" yaml:"env_fields"`
+	// IndexDateLayout 非空时为每条日志追加 log.index_date 字段，值为写入时的 UTC 日期，按该格式（Go 时间格式，如 2006.01.02）输出，
+	// 可直接用于 ES 按天写入的索引名，如 logs-demo-%{[log.index_date]}
+	IndexDateLayout string `json:"index_date_layout" yaml:"index_date_layout"`
+}
+
+// staticFields 返回解析后的静态字段，值为空的字段不输出，自定义字段按字段名排序
+func (cfg *MetadataConfig) staticFields() []Field {
+	if cfg == nil {
+		return nil
+	}
+	var buildVersion, buildRevision string
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Version != "(devel)" {
+			buildVersion = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				buildRevision = setting.Value
+			}
+		}
+	}
+
+	var fields []Field
+	add := func(key string, values ...string) {
+		for _, v := range values {
+			if v != "" {
+				fields = append(fields, Field{Key: key, Value: v})
+				return
+			}
+		}
+	}
+	add(KeyDeploymentEnvironment, cfg.Env, os.Getenv(EnvAppEnv))
+	add(KeyCloudRegion, cfg.Region, os.Getenv(EnvAppRegion))
+	add(KeyServiceVersion, cfg.Version, os.Getenv(EnvAppVersion), buildVersion)
+	add(KeyVcsRevision, cfg.GitCommit, os.Getenv(EnvGitCommit), buildRevision)
+	for _, key := range sortedKeys(cfg.Fields) {
+		add(key, cfg.Fields[key])
+	}
+	for _, key := range sortedKeys(cfg.EnvFields) {
+		add(key, os.Getenv(cfg.EnvFields[key]))
+	}
+	return fields
+}
+
+// indexDate 返回 log.index_date 字段的值，未配置时返回 false
+func (cfg *MetadataConfig) indexDate() (string, bool) {
+	if cfg == nil || cfg.IndexDateLayout == "" {
+		return "", false
+	}
+	return time.Now().UTC().Format(cfg.IndexDateLayout), true
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,69 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataLogged(t *testing.T) {
+	t.Setenv(EnvAppEnv, "staging")
+	t.Setenv(EnvAppRegion, "")
+	t.Setenv("TEST_POD_NAME", "demo-0")
+	metadata := &MetadataConfig{
+		Env:             "prod",
+		Version:         "v1.2.3",
+		GitCommit:       "abc123",
+		Fields:          map[string]string{"cluster": "bj-1", "empty": ""},
+		EnvFields:       map[string]string{"k8s.pod.name": "TEST_POD_NAME", "k8s.node.name": "TEST_NODE_NAME"},
+		IndexDateLayout: "2006.01.02",
+	}
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: "metadata", Level: DebugLevel, Writer: WriterCustom, Metadata: metadata}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+		logger.Info(context.Background(), "first")
+		logger.With("k", "v").Warnw(context.Background(), "second")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		for _, line := range lines {
+			var entry map[string]any
+			require.Nil(t, json.Unmarshal([]byte(line), &entry), line)
+			assert.Equal(t, "prod", entry[KeyDeploymentEnvironment], loggerType)
+			assert.Equal(t, "v1.2.3", entry[KeyServiceVersion], loggerType)
+			assert.Equal(t, "abc123", entry[KeyVcsRevision], loggerType)
+			assert.Equal(t, "bj-1", entry["cluster"], loggerType)
+			assert.Equal(t, "demo-0", entry["k8s.pod.name"], loggerType)
+			assert.Equal(t, time.Now().UTC().Format("2006.01.02"), entry[KeyLogIndexDate], loggerType)
+			for _, key := range []string{KeyCloudRegion, "empty", "k8s.node.name"} {
+				assert.NotContains(t, entry, key, loggerType)
+			}
+		}
+	}
+}
+
+func TestMetadataStaticFieldsFromEnv(t *testing.T) {
+	t.Setenv(EnvAppEnv, "staging")
+	t.Setenv(EnvAppRegion, "cn-north-1")
+	t.Setenv(EnvAppVersion, "v2.0.0")
+	t.Setenv(EnvGitCommit, "def456")
+	assert.Equal(t, []Field{
+		{Key: KeyDeploymentEnvironment, Value: "staging"},
+		{Key: KeyCloudRegion, Value: "cn-north-1"},
+		{Key: KeyServiceVersion, Value: "v2.0.0"},
+		{Key: KeyVcsRevision, Value: "def456"},
+	}, (&MetadataConfig{}).staticFields())
+
+	var cfg *MetadataConfig
+	assert.Nil(t, cfg.staticFields())
+	_, ok := cfg.indexDate()
+	assert.False(t, ok)
+}
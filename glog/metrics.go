@@ -0,0 +1,159 @@
+package glog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MetricsEntry 一条日志的计数维度
+type MetricsEntry struct {
+	Level  Level
+	Module string
+	// ErrorCode 日志字段或 CtxWith 绑定字段中 app.error.code 的值，没有时为空
+	ErrorCode string
+}
+
+// MetricsHook 日志计数钩子，每条实际输出的日志（通过级别过滤后）调用一次 OnEntry，
+// 用于按级别、模块、错误码统计日志条数，对错误日志突增告警而无需解析日志文件。
+// OnEntry 在日志调用方协程中同步执行，实现需保证并发安全且足够轻量。
+type MetricsHook interface {
+	OnEntry(entry MetricsEntry)
+}
+
+// MetricsHookFunc 函数形式的 MetricsHook
+type MetricsHookFunc func(entry MetricsEntry)
+
+// OnEntry 实现 MetricsHook 接口
+func (f MetricsHookFunc) OnEntry(entry MetricsEntry) {
+	f(entry)
+}
+
+// newMetricsEntry 构造计数维度，错误码优先取本次日志的 kvs，其次取 ctx 绑定的字段
+func newMetricsEntry(ctx context.Context, level Level, module string, kvs []any) MetricsEntry {
+	if module == "" {
+		module = defaultModuleName
+	}
+	entry := MetricsEntry{Level: level, Module: module}
+	if code, ok := errorCodeFromKVs(kvs); ok {
+		entry.ErrorCode = code
+		return entry
+	}
+	for _, f := range CtxFields(ctx) {
+		if f.Key == KeyAppErrorCode {
+			entry.ErrorCode = fmt.Sprint(f.Value)
+		}
+	}
+	return entry
+}
+
+// errorCodeFromKVs 从 kvs 中查找 app.error.code，kvs 中可以混用 key-value 和 Field
+func errorCodeFromKVs(kvs []any) (string, bool) {
+	for i := 0; i < len(kvs); i++ {
+		switch v := kvs[i].(type) {
+		case Field:
+			if v.Key == KeyAppErrorCode {
+				return fmt.Sprint(v.Value), true
+			}
+		case string:
+			if i+1 < len(kvs) {
+				if v == KeyAppErrorCode {
+					return fmt.Sprint(kvs[i+1]), true
+				}
+				i++
+			}
+		}
+	}
+	return "", false
+}
+
+// PrometheusMetrics 内置的 MetricsHook 实现，在内存中按 level、module、code 累计日志条数，
+// 并以 Prometheus 文本格式暴露为 counter，可直接挂载到 /metrics 路由供 Prometheus 抓取：
+//
+//	metrics := glog.NewPrometheusMetrics("myapp")
+//	glog.InitLogger(cfg, glog.WithMetricsHook(metrics))
+//	http.Handle("/metrics/log", metrics)
+//
+// 输出形如：myapp_log_entries_total{level="error",module="order",code="1001"} 3
+type PrometheusMetrics struct {
+	name     string
+	mu       sync.RWMutex
+	counters map[MetricsEntry]*atomic.Uint64
+}
+
+// NewPrometheusMetrics 创建 PrometheusMetrics，namespace 作为指标名前缀，为空时指标名为 log_entries_total
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	name := "log_entries_total"
+	if namespace != "" {
+		name = namespace + "_" + name
+	}
+	return &PrometheusMetrics{
+		name:     name,
+		counters: make(map[MetricsEntry]*atomic.Uint64),
+	}
+}
+
+// OnEntry 实现 MetricsHook 接口
+func (m *PrometheusMetrics) OnEntry(entry MetricsEntry) {
+	m.mu.RLock()
+	counter, ok := m.counters[entry]
+	m.mu.RUnlock()
+	if !ok {
+		m.mu.Lock()
+		if counter, ok = m.counters[entry]; !ok {
+			counter = new(atomic.Uint64)
+			m.counters[entry] = counter
+		}
+		m.mu.Unlock()
+	}
+	counter.Add(1)
+}
+
+// Count 返回指定维度的日志条数
+func (m *PrometheusMetrics) Count(level Level, module, code string) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if counter, ok := m.counters[MetricsEntry{Level: level, Module: module, ErrorCode: code}]; ok {
+		return counter.Load()
+	}
+	return 0
+}
+
+// WriteTo 以 Prometheus 文本格式输出全部计数，按标签排序保证输出稳定
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.RLock()
+	lines := make([]string, 0, len(m.counters))
+	for entry, counter := range m.counters {
+		lines = append(lines, fmt.Sprintf("%s{level=\"%s\",module=\"%s\",code=\"%s\"} %d\n",
+			m.name, escapeLabel(string(entry.Level)), escapeLabel(entry.Module), escapeLabel(entry.ErrorCode), counter.Load()))
+	}
+	m.mu.RUnlock()
+	sort.Strings(lines)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s Total number of log entries by level, module and error code.\n", m.name)
+	fmt.Fprintf(&b, "# TYPE %s counter\n", m.name)
+	for _, line := range lines {
+		b.WriteString(line)
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP 实现 http.Handler，输出 Prometheus 文本格式
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel 按 Prometheus 文本格式转义标签值中的反斜杠、双引号和换行
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,56 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHook(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		metrics := NewPrometheusMetrics("app")
+		cfg := &LogConfig{Module: fmt.Sprintf("metrics-%d", loggerType), Level: InfoLevel, Writer: WriterCustom}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)), WithMetricsHook(metrics))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		logger.Debug(ctx, "filtered by level")
+		logger.Info(ctx, "info")
+		logger.Errorw(ctx, "pay failed", KeyAppErrorCode, 1001)
+		logger.Errorw(ctx, "pay failed", KV(KeyAppErrorCode, 1001))
+		logger.With("component", "order").Errorf(CtxWith(ctx, KeyAppErrorCode, "E2"), "create failed: %s", "timeout")
+		logger.Errort(ctx, "user {user_id} failed", 42)
+
+		module := cfg.Module
+		assert.EqualValues(t, 0, metrics.Count(DebugLevel, module, ""), loggerType)
+		assert.EqualValues(t, 1, metrics.Count(InfoLevel, module, ""), loggerType)
+		assert.EqualValues(t, 2, metrics.Count(ErrorLevel, module, "1001"), loggerType)
+		assert.EqualValues(t, 1, metrics.Count(ErrorLevel, module, "E2"), loggerType)
+		assert.EqualValues(t, 1, metrics.Count(ErrorLevel, module, ""), loggerType)
+	}
+}
+
+func TestPrometheusMetricsExposition(t *testing.T) {
+	metrics := NewPrometheusMetrics("")
+	metrics.OnEntry(MetricsEntry{Level: ErrorLevel, Module: "order", ErrorCode: "1001"})
+	metrics.OnEntry(MetricsEntry{Level: ErrorLevel, Module: "order", ErrorCode: "1001"})
+	metrics.OnEntry(MetricsEntry{Level: WarnLevel, Module: `a"b`})
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+	assert.Equal(t, strings.Join([]string{
+		"# HELP log_entries_total Total number of log entries by level, module and error code.",
+		"# TYPE log_entries_total counter",
+		`log_entries_total{level="error",module="order",code="1001"} 2`,
+		`log_entries_total{level="warn",module="a\"b",code=""} 1`,
+		"",
+	}, "\n"), rec.Body.String())
+}
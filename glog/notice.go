@@ -0,0 +1,95 @@
+package glog
+
+import (
+	"context"
+	"sync"
+)
+
+// NoticeContextKey Notice 在 context 中的 key，gin 等框架可通过 Set(NoticeContextKey, notice) 让框架上下文也能取到同一个 Notice
+const NoticeContextKey = "app.log.notice"
+
+// Notice 请求级日志聚合器：请求处理过程中各层通过 AddNotice 追加字段，请求结束时合并为一条汇总日志输出，
+// 如 gin 的 AccessLog 中间件会把 Notice 字段追加到访问日志。并发安全，同名字段以后追加的为准。
+type Notice struct {
+	mu     sync.Mutex
+	fields []Field
+}
+
+// WithNotice 返回绑定了新 Notice 的子 context，通常在请求入口调用一次
+func WithNotice(ctx context.Context) (context.Context, *Notice) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	notice := &Notice{}
+	return context.WithValue(ctx, NoticeContextKey, notice), notice
+}
+
+// NoticeFrom 返回 ctx 上绑定的 Notice，未绑定时返回 nil
+func NoticeFrom(ctx context.Context) *Notice {
+	if ctx == nil {
+		return nil
+	}
+	notice, _ := ctx.Value(NoticeContextKey).(*Notice)
+	return notice
+}
+
+// AddNotice 向 ctx 上绑定的 Notice 追加字段，kvs 为 key-value 交替的参数，也可直接传入 Field；未绑定 Notice 时忽略
+func AddNotice(ctx context.Context, kvs ...any) {
+	NoticeFrom(ctx).Add(kvs...)
+}
+
+// NoticeKVs 返回 ctx 上绑定的 Notice 的字段，格式为 key-value 交替，可直接追加到 Infow 等方法的 kvs 中
+func NoticeKVs(ctx context.Context) []any {
+	return NoticeFrom(ctx).KVs()
+}
+
+// EmitNotice 以 MsgEventNotice 为消息输出一条包含 Notice 全部字段的汇总日志，适用于非 HTTP 请求的场景，如定时任务、消息消费
+func EmitNotice(ctx context.Context, kvs ...any) {
+	Infow(ctx, MsgEventNotice, append(NoticeKVs(ctx), kvs...)...)
+}
+
+// Add 追加字段，同名字段覆盖原值并保持首次出现的位置
+func (n *Notice) Add(kvs ...any) {
+	if n == nil {
+		return
+	}
+	fields := kvsToFields(kvs)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, f := range fields {
+		replaced := false
+		for i := range n.fields {
+			if n.fields[i].Key == f.Key {
+				n.fields[i].Value = f.Value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			n.fields = append(n.fields, f)
+		}
+	}
+}
+
+// Fields 返回已追加字段的副本
+func (n *Notice) Fields() []Field {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Field(nil), n.fields...)
+}
+
+// KVs 返回 key-value 交替的字段
+func (n *Notice) KVs() []any {
+	fields := n.Fields()
+	if len(fields) == 0 {
+		return nil
+	}
+	kvs := make([]any, 0, len(fields)*2)
+	for _, f := range fields {
+		kvs = append(kvs, f.Key, f.Value)
+	}
+	return kvs
+}
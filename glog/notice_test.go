@@ -0,0 +1,53 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotice(t *testing.T) {
+	ctx := context.Background()
+	// 未绑定 Notice 时忽略
+	AddNotice(ctx, "ignored", 1)
+	assert.Nil(t, NoticeKVs(ctx))
+
+	ctx, notice := WithNotice(ctx)
+	assert.Same(t, notice, NoticeFrom(ctx))
+	AddNotice(ctx, "tenant", "t-1", "gray", false)
+	AddNotice(CtxWith(ctx, "user_id", 1), KV("ab_bucket", "B"))
+	AddNotice(ctx, "gray", true)
+	assert.Equal(t, []any{"tenant", "t-1", "gray", true, "ab_bucket", "B"}, NoticeKVs(ctx))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			AddNotice(ctx, "db_calls", i)
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, notice.Fields(), 4)
+}
+
+func TestEmitNotice(t *testing.T) {
+	defer InitLogger(GetDefaultLogConfig())
+	var buf bytes.Buffer
+	require.Nil(t, InitLogger(&LogConfig{Module: "notice", Writer: WriterCustom}, WithSinks(NewWriterSink(&buf))))
+
+	ctx, _ := WithNotice(context.Background())
+	AddNotice(ctx, "tenant", "t-1")
+	EmitNotice(ctx, "job", "sync_order")
+
+	var entry map[string]any
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &entry), buf.String())
+	assert.Equal(t, MsgEventNotice, entry["msg"])
+	assert.Equal(t, "t-1", entry["tenant"])
+	assert.Equal(t, "sync_order", entry["job"])
+}
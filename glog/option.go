@@ -29,6 +29,8 @@ type optConfig struct {
 	messageHookFunc MessageHookFunc
 	enableOTELTrace *bool
 	loggerType      LoggerType
+	sinks           []Sink
+	metricsHook     MetricsHook
 }
 
 type option func(cfg *optConfig)
@@ -72,6 +74,20 @@ func WithLoggerType(t LoggerType) Option {
 	})
 }
 
+// WithSinks 设置自定义输出 Sink，Writer 为 custom 时生效，与 LogConfig.Sinks 引用的 Sink 合并
+func WithSinks(sinks ...Sink) Option {
+	return option(func(cfg *optConfig) {
+		cfg.sinks = append(cfg.sinks, sinks...)
+	})
+}
+
+// WithMetricsHook 设置日志计数钩子，每条实际输出的日志按级别、模块、错误码计数一次
+func WithMetricsHook(hook MetricsHook) Option {
+	return option(func(cfg *optConfig) {
+		cfg.metricsHook = hook
+	})
+}
+
 func getOptConfig(opts ...Option) *optConfig {
 	cfg := &optConfig{}
 	for _, opt := range opts {
@@ -0,0 +1,242 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// outputLevel slog 单个输出的级别过滤，需同时满足模块的动态级别和输出的最低级别，
+// 通过 SetLevel 修改模块级别后对各输出仍然生效；zap 由 ctxLevelCore 实现同样的过滤
+type outputLevel struct {
+	module *moduleLevel
+	min    Level
+}
+
+// Level 实现 slog.Leveler
+func (l *outputLevel) Level() slog.Level {
+	level := l.module.Level()
+	if l.min != "" {
+		level = max(level, logLevelToSlog(l.min))
+	}
+	return level
+}
+
+// fileSuffix 返回 file 输出的文件名后缀
+func (out *OutputConfig) fileSuffix() string {
+	if out.FileSuffix == "" {
+		return "full"
+	}
+	return out.FileSuffix
+}
+
+// outputEncoding 返回输出的编码格式
+func (cfg *LogConfig) outputEncoding(out *OutputConfig) EncodingType {
+	if out.Encoding != "" {
+		return out.Encoding
+	}
+	if out.Writer == WriterConsole {
+		return cfg.consoleEncoding()
+	}
+	return cfg.encoding()
+}
+
+// validateOutputs 校验多输出配置：输出类型、级别合法，file 输出的文件名后缀不重复
+func validateOutputs(outputs []OutputConfig) error {
+	suffixes := make(map[string]bool)
+	for i := range outputs {
+		out := &outputs[i]
+		if out.Level != "" {
+			if _, ok := logLevelMap[out.Level]; !ok {
+				return fmt.Errorf("glog: invalid level %q for output %d", out.Level, i)
+			}
+		}
+		switch out.Writer {
+		case WriterConsole, WriterCustom:
+		case WriterFile:
+			suffix := out.fileSuffix()
+			if suffixes[suffix] {
+				return fmt.Errorf("glog: duplicate file output suffix %q", suffix)
+			}
+			suffixes[suffix] = true
+		default:
+			return fmt.Errorf("glog: unknown output writer %q", out.Writer)
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// zap
+// ---------------------------------------------------------------------------
+
+// getZapOutputCores 为 Outputs 中的每个输出创建独立级别过滤的 core，由调用方合并为 Tee
+func getZapOutputCores(cfg *LogConfig, zapCfg *zapLoggerConfig, optCfg *optConfig, ml *moduleLevel) ([]zapcore.Core, error) {
+	if err := validateOutputs(cfg.Outputs); err != nil {
+		return nil, err
+	}
+	var cores []zapcore.Core
+	for i := range cfg.Outputs {
+		out := &cfg.Outputs[i]
+		encoder := getZapEncoder(zapCfg)
+		if cfg.outputEncoding(out) == EncodingConsole {
+			encoder = getZapConsoleEncoder(zapCfg, out.Writer == WriterConsole)
+		}
+
+		var writers []zapcore.WriteSyncer
+		switch out.Writer {
+		case WriterConsole:
+			writers = append(writers, getZapStandoutWriter())
+		case WriterFile:
+			writer, err := getZapFileWriter(cfg, out.fileSuffix())
+			if err != nil {
+				return nil, err
+			}
+			writers = append(writers, writer)
+		case WriterCustom:
+			sinks, err := resolveOutputSinks(out, optCfg)
+			if err != nil {
+				return nil, err
+			}
+			for _, sink := range sinks {
+				writers = append(writers, sink)
+			}
+		}
+		for _, writer := range writers {
+			if out.Writer != WriterConsole {
+				var err error
+				if writer, err = wrapZapAsync(cfg, writer); err != nil {
+					return nil, err
+				}
+			}
+			cores = append(cores, newCtxLevelCore(encoder, writer, ml, out.Level))
+		}
+	}
+	return cores, nil
+}
+
+// ---------------------------------------------------------------------------
+// slog
+// ---------------------------------------------------------------------------
+
+// slogOutputs 多输出模式下 slogLogger 持有的 writer，Close 时按创建顺序关闭（异步 writer 先于其下游）
+type slogOutputs struct {
+	closers []func() error
+	syncers []func() error
+}
+
+func (o *slogOutputs) Sync() error {
+	var errs []error
+	for _, syncer := range o.syncers {
+		errs = append(errs, syncer())
+	}
+	return errors.Join(errs...)
+}
+
+func (o *slogOutputs) Close() error {
+	var errs []error
+	for _, closer := range o.closers {
+		errs = append(errs, closer())
+	}
+	return errors.Join(errs...)
+}
+
+// newSlogOutputHandler 为 Outputs 中的每个输出创建独立级别过滤的 handler，合并为一个 slogTeeHandler
+func newSlogOutputHandler(cfg *LogConfig, optCfg *optConfig, redactor *redactor) (slog.Handler, *slogOutputs, error) {
+	if err := validateOutputs(cfg.Outputs); err != nil {
+		return nil, nil, err
+	}
+	ml := registerModuleLevel(cfg.Module, cfg.Level)
+	outputs := &slogOutputs{}
+	handlers := make(slogTeeHandler, 0, len(cfg.Outputs))
+	for i := range cfg.Outputs {
+		out := &cfg.Outputs[i]
+		var (
+			writer io.Writer
+			closer func() error
+			syncer func() error
+		)
+		switch out.Writer {
+		case WriterConsole:
+			writer = os.Stdout
+		case WriterFile:
+			rw, err := newRotateWriter(cfg, out.fileSuffix())
+			if err != nil {
+				_ = outputs.Close()
+				return nil, nil, err
+			}
+			writer, closer, syncer = rw, rw.Close, rw.Sync
+		case WriterCustom:
+			sinks, err := resolveOutputSinks(out, optCfg)
+			if err != nil {
+				_ = outputs.Close()
+				return nil, nil, err
+			}
+			writer, closer, syncer = multiSink(sinks), multiSink(sinks).Sync, multiSink(sinks).Sync
+		}
+		if out.Writer != WriterConsole && cfg.Async != nil {
+			aw, err := newAsyncWriter(writer, cfg.Async)
+			if err != nil {
+				_ = closer()
+				_ = outputs.Close()
+				return nil, nil, err
+			}
+			writer = aw
+			outputs.closers = append(outputs.closers, aw.Close)
+			// 异步 writer 的 Sync 会等待缓冲写完并同步下游
+			syncer = aw.Sync
+		}
+		if syncer != nil {
+			outputs.syncers = append(outputs.syncers, syncer)
+		}
+		if closer != nil {
+			outputs.closers = append(outputs.closers, closer)
+		}
+		level := &outputLevel{module: ml, min: out.Level}
+		handlers = append(handlers, newSlogLevelHandler(cfg, optCfg, writer, cfg.outputEncoding(out), out.Writer == WriterConsole, redactor, level))
+	}
+	return handlers, outputs, nil
+}
+
+// slogTeeHandler 将日志分发给多个 handler，每个 handler 按自身级别过滤
+type slogTeeHandler []slog.Handler
+
+func (t slogTeeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t slogTeeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t slogTeeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(slogTeeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t slogTeeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(slogTeeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
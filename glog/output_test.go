@@ -0,0 +1,125 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputs(t *testing.T) {
+	var alert bytes.Buffer
+	require.Nil(t, RegisterSink("outputs-alert", NewWriterSink(&alert)))
+
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		alert.Reset()
+		var all bytes.Buffer
+		dir := t.TempDir()
+		cfg := &LogConfig{
+			Service: "outputs",
+			Module:  fmt.Sprintf("outputs-%d", loggerType),
+			Level:   DebugLevel,
+			Dir:     dir,
+			Outputs: []OutputConfig{
+				{Writer: WriterCustom},
+				{Writer: WriterFile, Level: WarnLevel, FileSuffix: "wf"},
+				{Writer: WriterCustom, Level: ErrorLevel, Sinks: []string{"outputs-alert"}, Encoding: EncodingConsole},
+			},
+		}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&all)))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		logger.Debug(ctx, "debug message")
+		logger.Warn(ctx, "warn message")
+		logger.Errorw(ctx, "error message", "order_id", "o-1")
+
+		// 模块级别高于输出级别时以模块级别为准
+		require.Nil(t, SetLevel(cfg.Module, ErrorLevel))
+		logger.Warn(ctx, "filtered warn")
+		require.Nil(t, logger.Close())
+
+		assert.Equal(t, 3, strings.Count(all.String(), "\n"), loggerType)
+		assert.Contains(t, all.String(), "debug message")
+		assert.NotContains(t, all.String(), "filtered warn")
+
+		content, err := os.ReadFile(filepath.Join(dir, time.Now().Format("20060102"), "outputs_wf.log"))
+		require.Nil(t, err)
+		assert.NotContains(t, string(content), "debug message", loggerType)
+		assert.Contains(t, string(content), "warn message", loggerType)
+		assert.Contains(t, string(content), "error message", loggerType)
+
+		assert.Equal(t, 1, strings.Count(alert.String(), "\n"), loggerType)
+		assert.Contains(t, alert.String(), "error message")
+		assert.Contains(t, alert.String(), "o-1", loggerType)
+	}
+}
+
+func TestOutputsInvalid(t *testing.T) {
+	cases := [][]OutputConfig{
+		{{Writer: "kafka"}},
+		{{Writer: WriterConsole, Level: "verbose"}},
+		{{Writer: WriterFile}, {Writer: WriterFile, FileSuffix: "full"}},
+		{{Writer: WriterCustom, Sinks: []string{"outputs-missing"}}},
+	}
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		for _, outputs := range cases {
+			cfg := &LogConfig{Service: "outputs", Module: "outputs-invalid", Dir: t.TempDir(), Outputs: outputs}
+			_, err := NewLogger(cfg, WithLoggerType(loggerType))
+			assert.NotNil(t, err, "%s %+v", loggerType, outputs)
+		}
+	}
+}
+
+func TestWFFile(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		dir := t.TempDir()
+		cfg := &LogConfig{
+			Service:  "wf",
+			Module:   fmt.Sprintf("wf-%d", loggerType),
+			Level:    DebugLevel,
+			Writer:   WriterFile,
+			Dir:      dir,
+			WFLevel:  ErrorLevel,
+			WFSuffix: "error",
+		}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		logger.Info(ctx, "info message")
+		logger.Warn(ctx, "warn message")
+		logger.Error(ctx, "error message")
+		// zap file 模式同时输出到 stdout，测试环境下 stdout Sync 可能报错，这里只关心文件内容
+		_ = logger.Close()
+
+		dateDir := filepath.Join(dir, time.Now().Format("20060102"))
+		content, err := os.ReadFile(filepath.Join(dateDir, "wf_error.log"))
+		require.Nil(t, err)
+		assert.NotContains(t, string(content), "warn message", loggerType)
+		assert.Contains(t, string(content), "error message", loggerType)
+		_, err = os.Stat(filepath.Join(dateDir, "wf_wf.log"))
+		assert.True(t, os.IsNotExist(err))
+
+		// 关闭 wf 文件后只输出 full 文件
+		disabledDir := t.TempDir()
+		logger, err = NewLogger(&LogConfig{Service: "wf", Module: cfg.Module, Writer: WriterFile, Dir: disabledDir, DisableWF: true}, WithLoggerType(loggerType))
+		require.Nil(t, err)
+		logger.Error(ctx, "error message")
+		_ = logger.Close()
+		entries, err := os.ReadDir(filepath.Join(disabledDir, time.Now().Format("20060102")))
+		require.Nil(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "wf_full.log", entries[0].Name())
+
+		_, err = NewLogger(&LogConfig{Service: "wf", Module: cfg.Module, Writer: WriterFile, Dir: t.TempDir(), WFLevel: "verbose"}, WithLoggerType(loggerType))
+		assert.NotNil(t, err)
+	}
+}
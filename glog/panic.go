@@ -0,0 +1,66 @@
+package glog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// PanicValue 结构化的 panic 值，用于记录 recover 得到的任意类型的值，
+// 相比 fmt.Sprint 保留了动态类型和结构体、map 等值的字段结构
+type PanicValue struct {
+	Type    string `json:"type"`            // 动态类型，如 *errors.errorString、main.OrderState
+	Message string `json:"message"`         // error 为 Error()，其余为 fmt.Sprint 的结果
+	Value   any    `json:"value,omitempty"` // 结构化的值，string、error 及无法 JSON 序列化的值为空
+}
+
+// NewPanicValue 将 recover 得到的值转为 PanicValue。结构体、map、切片等按 JSON 结构记录，
+// 只有未导出字段的结构体记录为 %+v 格式的字符串，无法 JSON 序列化的值（如 chan、func）只记录 Type 和 Message：
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			glog.Errorw(ctx, "task panic", glog.KeyPanic, glog.NewPanicValue(r), "stack", string(debug.Stack()))
+//		}
+//	}()
+func NewPanicValue(r any) PanicValue {
+	if r == nil {
+		return PanicValue{Type: "<nil>"}
+	}
+	res := PanicValue{Type: reflect.TypeOf(r).String()}
+	switch v := r.(type) {
+	case error:
+		res.Message = v.Error()
+		return res
+	case string:
+		res.Message = v
+		return res
+	}
+	res.Message = fmt.Sprint(r)
+	res.Value = structuredPanicValue(r)
+	return res
+}
+
+// structuredPanicValue 经 JSON 往返转为 map、切片等通用类型，避免下游编码器再次序列化时失败
+func structuredPanicValue(r any) any {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	// 只有未导出字段的结构体序列化结果为 {}，改用 %+v 保留字段内容
+	if m, ok := value.(map[string]any); ok && len(m) == 0 && isStructValue(r) {
+		return fmt.Sprintf("%+v", r)
+	}
+	return value
+}
+
+func isStructValue(r any) bool {
+	t := reflect.TypeOf(r)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t.NumField() > 0
+}
@@ -0,0 +1,89 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicOrder struct {
+	ID    int               `json:"id"`
+	State string            `json:"state"`
+	Tags  map[string]string `json:"tags"`
+}
+
+type panicState struct {
+	code   int
+	reason string
+}
+
+func TestNewPanicValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  PanicValue
+	}{
+		{name: "nil", value: nil, want: PanicValue{Type: "<nil>"}},
+		{name: "string", value: "boom", want: PanicValue{Type: "string", Message: "boom"}},
+		{name: "error", value: errors.New("boom"), want: PanicValue{Type: "*errors.errorString", Message: "boom"}},
+		{name: "int", value: 42, want: PanicValue{Type: "int", Message: "42", Value: float64(42)}},
+		{
+			name:  "struct",
+			value: &panicOrder{ID: 1, State: "paid", Tags: map[string]string{"k": "v"}},
+			want: PanicValue{
+				Type:    "*glog.panicOrder",
+				Message: "&{1 paid map[k:v]}",
+				Value:   map[string]any{"id": float64(1), "state": "paid", "tags": map[string]any{"k": "v"}},
+			},
+		},
+		{
+			name:  "unexported struct",
+			value: panicState{code: 3, reason: "closed"},
+			want:  PanicValue{Type: "glog.panicState", Message: "{3 closed}", Value: "{code:3 reason:closed}"},
+		},
+		{name: "map", value: map[string]int{"a": 1}, want: PanicValue{Type: "map[string]int", Message: "map[a:1]", Value: map[string]any{"a": float64(1)}}},
+		{name: "unsupported", value: make(chan int), want: PanicValue{Type: "chan int"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPanicValue(tt.value)
+			if tt.name == "unsupported" {
+				// chan 的 Message 为地址，不做比较
+				got.Message = ""
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPanicValueLogged(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: "panic-value", Level: DebugLevel, Writer: WriterCustom}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Errorw(context.Background(), "task panic", KeyPanic, NewPanicValue(r))
+				}
+			}()
+			panic(panicOrder{ID: 7, State: "refunding"})
+		}()
+
+		var entry map[string]any
+		line := strings.TrimSpace(buf.String())
+		require.Nil(t, json.Unmarshal([]byte(line), &entry), line)
+		panicEntry, ok := entry[KeyPanic].(map[string]any)
+		require.True(t, ok, line)
+		assert.Equal(t, "glog.panicOrder", panicEntry["type"])
+		assert.Equal(t, map[string]any{"id": float64(7), "state": "refunding", "tags": nil}, panicEntry["value"])
+	}
+}
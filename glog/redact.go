@@ -0,0 +1,337 @@
+package glog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 内置脱敏规则名称，在 RedactionConfig.Builtins 中引用
+const (
+	RedactPhone       = "phone"        // 手机号，保留前 3 位和后 4 位
+	RedactEmail       = "email"        // 邮箱，保留首字符和域名
+	RedactIDCard      = "id_card"      // 18 位身份证号，保留前 6 位和后 4 位
+	RedactBearerToken = "bearer_token" // Bearer 令牌，整体替换
+)
+
+const defaultRedactMask = "***"
+
+// RedactionConfig 敏感数据脱敏配置，在编码前统一生效，对所有输出（console/file/sink）一致。
+type RedactionConfig struct {
+	// Fields 需要整体脱敏的字段名，不区分大小写，同时匹配 "a.b.password" 这类带前缀字段的最后一段
+	Fields []string `json:"fields" yaml:"fields"`
+	// Builtins 启用的内置规则：phone、email、id_card、bearer_token，作用于字符串字段值和日志消息
+	Builtins []string `json:"builtins" yaml:"builtins"`
+	// Rules 自定义正则规则，作用于字符串字段值和日志消息
+	Rules []RedactionRule `json:"rules" yaml:"rules"`
+	// JSONPaths 字符串字段值为 JSON（如请求体）时按路径脱敏，路径以 "." 分隔，"*" 匹配任意 key 或数组元素，
+	// 如 "password"、"user.id_card"、"items.*.card_no"，可带 "$." 前缀
+	JSONPaths []string `json:"json_paths" yaml:"json_paths"`
+	// Mask 脱敏后的替换文本，默认 "***"
+	Mask string `json:"mask" yaml:"mask"`
+}
+
+// RedactionRule 自定义正则脱敏规则
+type RedactionRule struct {
+	// Name 规则名称，仅用于错误提示
+	Name string `json:"name" yaml:"name"`
+	// Pattern 正则表达式
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// Replacement 替换文本，支持 $1 引用分组，为空时使用 Mask
+	Replacement string `json:"replacement" yaml:"replacement"`
+}
+
+type redactPattern struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+var builtinRedactPatterns = map[string]redactPattern{
+	RedactPhone: {
+		re:          regexp.MustCompile(`\b(1[3-9]\d)\d{4}(\d{4})\b`),
+		replacement: "${1}****${2}",
+	},
+	RedactEmail: {
+		re:          regexp.MustCompile(`\b([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})\b`),
+		replacement: "${1}***@${2}",
+	},
+	RedactIDCard: {
+		re:          regexp.MustCompile(`\b(\d{6})\d{8}(\d{3}[\dXx])\b`),
+		replacement: "${1}********${2}",
+	},
+	RedactBearerToken: {
+		re:          regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		replacement: "${1}",
+	},
+}
+
+// redactor 编译后的脱敏规则，与 fieldSizeLimiter 一样在 encoder/handler 层生效，先脱敏再截断。
+type redactor struct {
+	mask      string
+	fields    map[string]struct{}
+	patterns  []redactPattern
+	jsonPaths [][]string
+}
+
+// newRedactor 编译脱敏配置，未配置时返回 nil，调用方据此跳过脱敏逻辑。
+func newRedactor(cfg *LogConfig) (*redactor, error) {
+	if cfg == nil || cfg.Redaction == nil {
+		return nil, nil
+	}
+	rc := cfg.Redaction
+	r := &redactor{
+		mask:   rc.Mask,
+		fields: make(map[string]struct{}, len(rc.Fields)),
+	}
+	if r.mask == "" {
+		r.mask = defaultRedactMask
+	}
+	for _, field := range rc.Fields {
+		r.fields[strings.ToLower(field)] = struct{}{}
+	}
+	enabled := make(map[string]bool, len(rc.Builtins))
+	for _, builtin := range rc.Builtins {
+		if _, ok := builtinRedactPatterns[builtin]; !ok {
+			return nil, fmt.Errorf("glog: unknown builtin redaction rule %q", builtin)
+		}
+		enabled[builtin] = true
+	}
+	// 身份证号先于手机号匹配，避免 18 位号码中的片段被误判
+	for _, name := range []string{RedactIDCard, RedactPhone, RedactEmail, RedactBearerToken} {
+		if !enabled[name] {
+			continue
+		}
+		pattern := builtinRedactPatterns[name]
+		if name == RedactBearerToken {
+			pattern.replacement = "${1}" + r.mask
+		}
+		r.patterns = append(r.patterns, pattern)
+	}
+	for _, rule := range rc.Rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("glog: compile redaction rule %q: %w", rule.Name, err)
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = r.mask
+		}
+		r.patterns = append(r.patterns, redactPattern{re: re, replacement: replacement})
+	}
+	for _, p := range rc.JSONPaths {
+		p = strings.TrimPrefix(strings.TrimPrefix(p, "$"), ".")
+		if p != "" {
+			r.jsonPaths = append(r.jsonPaths, strings.Split(p, "."))
+		}
+	}
+	return r, nil
+}
+
+// sensitiveField 判断字段名是否需要整体脱敏
+func (r *redactor) sensitiveField(key string) bool {
+	if len(r.fields) == 0 {
+		return false
+	}
+	key = strings.ToLower(key)
+	if _, ok := r.fields[key]; ok {
+		return true
+	}
+	if idx := strings.LastIndex(key, "."); idx >= 0 {
+		_, ok := r.fields[key[idx+1:]]
+		return ok
+	}
+	return false
+}
+
+// redactMessage 对日志消息应用正则规则
+func (r *redactor) redactMessage(msg string) string {
+	if r == nil {
+		return msg
+	}
+	return r.applyPatterns(msg)
+}
+
+func (r *redactor) applyPatterns(s string) string {
+	for _, p := range r.patterns {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// redactString 依次按字段名、JSON 路径、正则规则脱敏字符串值，返回值是否发生变化
+func (r *redactor) redactString(key, value string) (string, bool) {
+	if r == nil {
+		return value, false
+	}
+	if r.sensitiveField(key) {
+		return r.mask, true
+	}
+	redacted := r.redactJSON(value)
+	redacted = r.applyPatterns(redacted)
+	return redacted, redacted != value
+}
+
+// redactAny 脱敏非字符串值：字段名命中时整体替换，字符串类值应用规则，其余类型保持原样
+func (r *redactor) redactAny(key string, value any) (string, bool) {
+	if r == nil || value == nil {
+		return "", false
+	}
+	if r.sensitiveField(key) {
+		return r.mask, true
+	}
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		return "", false
+	}
+	return r.redactString(key, s)
+}
+
+// redactJSON 字符串为 JSON 对象或数组时按 JSONPaths 脱敏，未命中任何路径时原样返回
+func (r *redactor) redactJSON(s string) string {
+	if len(r.jsonPaths) == 0 {
+		return s
+	}
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return s
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return s
+	}
+	changed := false
+	for _, path := range r.jsonPaths {
+		if r.maskJSONPath(doc, path) {
+			changed = true
+		}
+	}
+	if !changed {
+		return s
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return s
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// maskJSONPath 将 path 指向的值替换为 mask，返回是否命中
+func (r *redactor) maskJSONPath(node any, path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	seg, rest := path[0], path[1:]
+	hit := false
+	switch v := node.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if seg != "*" && key != seg {
+				continue
+			}
+			if len(rest) == 0 {
+				v[key] = r.mask
+				hit = true
+			} else if r.maskJSONPath(child, rest) {
+				hit = true
+			}
+		}
+	case []any:
+		// 数组可由 "*" 显式匹配，也可省略，如 "items.card_no" 作用于每个元素
+		if seg != "*" {
+			rest = path
+		}
+		for i, child := range v {
+			if len(rest) == 0 {
+				v[i] = r.mask
+				hit = true
+			} else if r.maskJSONPath(child, rest) {
+				hit = true
+			}
+		}
+	}
+	return hit
+}
+
+// redactZapFields 脱敏 zap 字段，只有发生变化时才复制切片，不修改调用方数据。
+func (r *redactor) redactZapFields(fields []zapcore.Field) []zapcore.Field {
+	if r == nil {
+		return fields
+	}
+	copied := false
+	for i, f := range fields {
+		var (
+			redacted string
+			ok       bool
+		)
+		switch f.Type {
+		case zapcore.StringType:
+			redacted, ok = r.redactString(f.Key, f.String)
+		case zapcore.ByteStringType, zapcore.BinaryType, zapcore.ReflectType, zapcore.StringerType, zapcore.ErrorType:
+			redacted, ok = r.redactAny(f.Key, f.Interface)
+		default:
+			if r.sensitiveField(f.Key) {
+				redacted, ok = r.mask, true
+			}
+		}
+		if !ok {
+			continue
+		}
+		if !copied {
+			fields = append([]zapcore.Field(nil), fields...)
+			copied = true
+		}
+		fields[i] = zap.String(f.Key, redacted)
+	}
+	return fields
+}
+
+// redactSlogAttr 脱敏 slog 属性值，作为 ReplaceAttr 的一部分在截断前执行。
+func (r *redactor) redactSlogAttr(groups []string, a slog.Attr) slog.Attr {
+	if r == nil {
+		return a
+	}
+	if len(groups) == 0 {
+		switch a.Key {
+		case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+			return a
+		}
+	}
+	value := a.Value.Resolve()
+	var (
+		redacted string
+		ok       bool
+	)
+	switch value.Kind() {
+	case slog.KindString:
+		redacted, ok = r.redactString(a.Key, value.String())
+	case slog.KindAny:
+		redacted, ok = r.redactAny(a.Key, value.Any())
+	default:
+		if r.sensitiveField(a.Key) {
+			redacted, ok = r.mask, true
+		}
+	}
+	if !ok {
+		return a
+	}
+	return slog.String(a.Key, redacted)
+}
@@ -0,0 +1,104 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactorBuiltins(t *testing.T) {
+	r, err := newRedactor(&LogConfig{Redaction: &RedactionConfig{
+		Builtins: []string{RedactPhone, RedactEmail, RedactIDCard, RedactBearerToken},
+	}})
+	require.Nil(t, err)
+
+	cases := map[string]string{
+		"phone 13812345678":                   "phone 138****5678",
+		"mail alice.w@example.com":            "mail a***@example.com",
+		"id 11010519491231002X":               "id 110105********002X",
+		"Authorization: Bearer eyJhbGci.abc=": "Authorization: Bearer ***",
+		"order 202401010001":                  "order 202401010001",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, r.redactMessage(in))
+	}
+}
+
+func TestRedactorFieldsAndJSONPaths(t *testing.T) {
+	r, err := newRedactor(&LogConfig{Redaction: &RedactionConfig{
+		Fields:    []string{"Password"},
+		JSONPaths: []string{"$.user.token", "items.card_no"},
+		Rules:     []RedactionRule{{Name: "secret", Pattern: `secret=\w+`, Replacement: "secret=<hidden>"}},
+		Mask:      "<hidden>",
+	}})
+	require.Nil(t, err)
+
+	v, ok := r.redactString("password", "123456")
+	assert.True(t, ok)
+	assert.Equal(t, "<hidden>", v)
+	// 字段名命中时任意类型的值都整体替换，同时匹配带前缀字段的最后一段
+	v, ok = r.redactAny("req.password", 123456)
+	assert.True(t, ok)
+	assert.Equal(t, "<hidden>", v)
+	_, ok = r.redactAny("count", 123456)
+	assert.False(t, ok)
+
+	v, ok = r.redactString("body", `{"user":{"name":"a","token":"t1"},"items":[{"card_no":"6222"},{"card_no":"6223"}],"n":1.50}`)
+	assert.True(t, ok)
+	assert.Equal(t, `{"items":[{"card_no":"<hidden>"},{"card_no":"<hidden>"}],"n":1.50,"user":{"name":"a","token":"<hidden>"}}`, v)
+
+	_, ok = r.redactString("body", `{"user":{"name":"a"}}`)
+	assert.False(t, ok)
+
+	v, ok = r.redactAny("err", errors.New("bad secret=abc"))
+	assert.True(t, ok)
+	assert.Equal(t, "bad secret=<hidden>", v)
+}
+
+func TestRedactorInvalidConfig(t *testing.T) {
+	_, err := newRedactor(&LogConfig{Redaction: &RedactionConfig{Builtins: []string{"unknown"}}})
+	assert.NotNil(t, err)
+	_, err = newRedactor(&LogConfig{Redaction: &RedactionConfig{Rules: []RedactionRule{{Name: "bad", Pattern: "("}}}})
+	assert.NotNil(t, err)
+
+	r, err := newRedactor(&LogConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, r)
+	assert.Equal(t, "13812345678", r.redactMessage("13812345678"))
+}
+
+func TestRedactionLogger(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{
+			Module: "redact-test",
+			Level:  DebugLevel,
+			Writer: WriterCustom,
+			Redaction: &RedactionConfig{
+				Fields:   []string{"password"},
+				Builtins: []string{RedactPhone},
+			},
+		}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		logger.With("password", "bound-secret").Infow(ctx, "call 13812345678", "password", "p@ss", "mobile", "13912345678", "count", 3)
+
+		out := buf.String()
+		assert.NotContains(t, out, "bound-secret")
+		assert.NotContains(t, out, "p@ss")
+		assert.NotContains(t, out, "13812345678")
+		assert.NotContains(t, out, "13912345678")
+		assert.Contains(t, out, "call 138****5678")
+		assert.Contains(t, out, `"mobile":"139****5678"`)
+		assert.Contains(t, out, `"count":3`)
+	}
+
+	_, err := NewLogger(&LogConfig{Writer: WriterConsole, Redaction: &RedactionConfig{Builtins: []string{"unknown"}}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,470 @@
+package glog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// rotateWriter —— 统一的日志文件切割引擎
+// ---------------------------------------------------------------------------
+
+const (
+	defaultRotateMaxSize    = 100 // MB
+	defaultRotateMaxBackups = 10
+	defaultRotateMaxAge     = 7 // 天
+
+	rotateDirLayout    = "20060102"
+	rotateBackupLayout = "2006-01-02T15-04-05.000"
+	compressSuffix     = ".gz"
+	currentLinkName    = "current"
+)
+
+// rotateWriter 按时间和大小切割日志文件，文件布局：
+//
+//	{Dir}/{YYYYMMDD}/{Service}_{suffix}.log                      当前写入的文件
+//	{Dir}/{YYYYMMDD}/{Service}_{suffix}-{2006-01-02T15-04-05.000}.log  同一天内按大小或按小时切割出的归档文件
+//
+// 跨天时切换到新的日期目录，旧目录中的文件保留原名作为归档文件。
+// 配置 CurrentLink 时维护 {Dir}/current -> {YYYYMMDD} 软链接；配置 FlatLayout 时不创建日期目录，
+// 文件直接位于 {Dir} 下，按天、按小时或按大小切割时当前文件均重命名为带时间戳的归档文件。
+// 每次切割后在后台清理：压缩归档文件，按 MaxBackups、MaxAge、MaxTotalSize 跨日期目录删除旧文件，并删除空的日期目录。
+type rotateWriter struct {
+	dir          string // 日志根目录
+	base         string // 文件名前缀，如 app_full
+	rotation     RotationType
+	maxSize      int64 // 字节
+	maxBackups   int
+	maxAge       time.Duration
+	maxTotalSize int64 // 字节，0 表示不限制
+	compress     bool
+	flat         bool // 不按日期分目录
+	currentLink  bool // 维护 current 软链接
+	now          func() time.Time
+
+	mu           sync.Mutex
+	file         *os.File // 切割时打开新文件失败为 nil，下次写入时重试
+	closed       bool
+	filename     string
+	size         int64
+	nextRotateAt time.Time
+
+	cleanupMu sync.Mutex // 串行化清理，避免同一文件被重复压缩
+	cleanupCh chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newRotateWriter 创建切割 writer，fileSuffix 为 full、wf 或 Outputs/WFSuffix 指定的后缀
+func newRotateWriter(cfg *LogConfig, fileSuffix string) (*rotateWriter, error) {
+	w := &rotateWriter{
+		dir:         strings.TrimSuffix(cfg.Dir, "/"),
+		base:        fmt.Sprintf("%s_%s", cfg.Service, fileSuffix),
+		rotation:    cfg.Rotation,
+		maxSize:     int64(cfg.MaxSize) * 1024 * 1024,
+		maxBackups:  cfg.MaxBackups,
+		maxAge:      time.Duration(cfg.MaxAge) * 24 * time.Hour,
+		compress:    cfg.Compress,
+		flat:        cfg.FlatLayout,
+		currentLink: cfg.CurrentLink && !cfg.FlatLayout,
+		now:         time.Now,
+		cleanupCh:   make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	if w.rotation == "" {
+		w.rotation = RotationDaily
+	}
+	if w.rotation != RotationDaily && w.rotation != RotationHourly {
+		return nil, fmt.Errorf("glog: unknown rotation %q", cfg.Rotation)
+	}
+	if w.maxSize <= 0 {
+		w.maxSize = defaultRotateMaxSize * 1024 * 1024
+	}
+	if w.maxBackups <= 0 {
+		w.maxBackups = defaultRotateMaxBackups
+	}
+	if w.maxAge <= 0 {
+		w.maxAge = defaultRotateMaxAge * 24 * time.Hour
+	}
+	if cfg.MaxTotalSize > 0 {
+		w.maxTotalSize = int64(cfg.MaxTotalSize) * 1024 * 1024
+	}
+
+	if err := w.open(w.now()); err != nil {
+		return nil, err
+	}
+	go w.cleanupLoop()
+	w.triggerCleanup()
+	return w, nil
+}
+
+// Write 实现 io.Writer，写入前检查是否到达切割时间点或超过大小上限
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	now := w.now()
+	if w.file == nil {
+		// 上次切割时打开文件失败（如磁盘满、目录权限变化），重新打开
+		if err := w.open(now); err != nil {
+			return 0, err
+		}
+	} else if !now.Before(w.nextRotateAt) || (w.size > 0 && w.size+int64(len(p)) > w.maxSize) {
+		if err := w.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Sync 实现 zapcore.WriteSyncer
+func (w *rotateWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close 刷盘并关闭当前文件，停止后台清理
+func (w *rotateWriter) Close() error {
+	w.mu.Lock()
+	var err error
+	w.closed = true
+	if w.file != nil {
+		err = w.file.Sync()
+		if closeErr := w.file.Close(); err == nil {
+			err = closeErr
+		}
+		w.file = nil
+	}
+	w.mu.Unlock()
+	w.closeOnce.Do(func() { close(w.done) })
+	return err
+}
+
+// open 打开 now 所在日期目录中的当前文件并计算下一个切割时间点，调用方需持有锁或处于初始化阶段。
+// 按小时切割时，若已有文件最后修改于上一个周期（如进程重启），先将其归档。
+func (w *rotateWriter) open(now time.Time) error {
+	dir := w.fileDir(now)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("glog: mkdir %s: %w", dir, err)
+	}
+	if w.currentLink {
+		w.linkCurrent(dir)
+	}
+	filename := filepath.Join(dir, w.base+".log")
+	start := w.periodStart(now)
+	if info, err := os.Stat(filename); err == nil && info.Size() > 0 && info.ModTime().Before(start) {
+		_ = os.Rename(filename, w.backupName(dir, info.ModTime()))
+	}
+
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("glog: open %s: %w", filename, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("glog: stat %s: %w", filename, err)
+	}
+	w.file = f
+	w.filename = filename
+	w.size = info.Size()
+	w.nextRotateAt = w.nextPeriodStart(start)
+	return nil
+}
+
+// rotate 关闭当前文件并打开新文件，调用方需持有锁。
+// 仍在同一日期目录时当前文件重命名为带时间戳的归档文件；跨天时旧文件保留原名留在旧目录。
+// 打开新文件失败时 w.file 为 nil，下次写入时重试打开
+func (w *rotateWriter) rotate(now time.Time) error {
+	closeErr := w.file.Close()
+	w.file = nil
+	if closeErr != nil {
+		return fmt.Errorf("glog: close %s: %w", w.filename, closeErr)
+	}
+	dir := filepath.Dir(w.filename)
+	if dir == w.fileDir(now) {
+		// 重命名失败时继续追加写入原文件，不中断日志输出
+		_ = os.Rename(w.filename, w.backupName(dir, now))
+	}
+	if err := w.open(now); err != nil {
+		return err
+	}
+	w.triggerCleanup()
+	return nil
+}
+
+// fileDir 返回 now 时刻当前文件所在的目录
+func (w *rotateWriter) fileDir(now time.Time) string {
+	if w.flat {
+		return w.dir
+	}
+	return filepath.Join(w.dir, now.Format(rotateDirLayout))
+}
+
+// linkCurrent 将 current 软链接指向 dir，先创建临时链接再原子替换，避免采集端读到不存在的路径。
+// 软链接不可用（如 Windows 无权限）或 current 已是普通目录时忽略，不影响日志写入
+func (w *rotateWriter) linkCurrent(dir string) {
+	link := filepath.Join(w.dir, currentLinkName)
+	target := filepath.Base(dir)
+	if cur, err := os.Readlink(link); err == nil && cur == target {
+		return
+	}
+	// full、wf 等多个 writer 共用 current，临时链接按文件名区分
+	tmp := fmt.Sprintf("%s.%s.tmp", link, w.base)
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
+
+// periodStart 返回 now 所在切割周期的起始时间
+func (w *rotateWriter) periodStart(now time.Time) time.Time {
+	if w.rotation == RotationHourly {
+		return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// nextPeriodStart 返回下一个切割周期的起始时间
+func (w *rotateWriter) nextPeriodStart(start time.Time) time.Time {
+	if w.rotation == RotationHourly {
+		return time.Date(start.Year(), start.Month(), start.Day(), start.Hour()+1, 0, 0, 0, start.Location())
+	}
+	return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+}
+
+// backupName 返回归档文件名，同一毫秒内多次切割时顺延时间戳避免覆盖
+func (w *rotateWriter) backupName(dir string, t time.Time) string {
+	for {
+		name := filepath.Join(dir, fmt.Sprintf("%s-%s.log", w.base, t.Format(rotateBackupLayout)))
+		_, err := os.Stat(name)
+		_, gzErr := os.Stat(name + compressSuffix)
+		if os.IsNotExist(err) && os.IsNotExist(gzErr) {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// 清理：压缩与保留策略
+// ---------------------------------------------------------------------------
+
+// triggerCleanup 通知后台执行清理，清理进行中时合并为一次
+func (w *rotateWriter) triggerCleanup() {
+	select {
+	case w.cleanupCh <- struct{}{}:
+	default:
+	}
+}
+
+func (w *rotateWriter) cleanupLoop() {
+	for {
+		select {
+		case <-w.cleanupCh:
+			w.cleanup()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// rotatedFile 已完成切割的归档文件
+type rotatedFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// cleanup 压缩归档文件，按保留策略删除旧文件，并删除空的历史日期目录
+func (w *rotateWriter) cleanup() {
+	w.cleanupMu.Lock()
+	defer w.cleanupMu.Unlock()
+
+	w.mu.Lock()
+	active := w.filename
+	now := w.now()
+	w.mu.Unlock()
+	activeDir := filepath.Dir(active)
+
+	files, dirs, activeSize := w.listRotatedFiles(active)
+
+	if w.compress {
+		for i, f := range files {
+			if strings.HasSuffix(f.path, compressSuffix) {
+				continue
+			}
+			size, err := compressLogFile(f.path)
+			if err != nil {
+				continue
+			}
+			files[i].path = f.path + compressSuffix
+			files[i].size = size
+		}
+	}
+
+	// 由新到旧排序，超出 MaxBackups 或 MaxAge 的文件删除
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	cutoff := now.Add(-w.maxAge)
+	kept := files[:0]
+	for i, f := range files {
+		if i >= w.maxBackups || f.modTime.Before(cutoff) {
+			_ = os.Remove(f.path)
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	// 总大小超限时从最旧的文件开始删除，当前文件计入总大小但不会被删除
+	if w.maxTotalSize > 0 {
+		total := activeSize
+		for _, f := range kept {
+			total += f.size
+		}
+		for i := len(kept) - 1; i >= 0 && total > w.maxTotalSize; i-- {
+			if err := os.Remove(kept[i].path); err == nil {
+				total -= kept[i].size
+			}
+		}
+	}
+
+	// 删除空的历史日期目录，os.Remove 对非空目录不生效，不会误删其他日志
+	for _, dir := range dirs {
+		if dir == activeDir {
+			continue
+		}
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) == 0 {
+			_ = os.Remove(dir)
+		}
+	}
+}
+
+// listRotatedFiles 列出 Dir 及所有日期目录中属于当前 writer 的归档文件，不包含当前文件，同时返回日期目录和当前文件大小
+func (w *rotateWriter) listRotatedFiles(active string) ([]rotatedFile, []string, int64) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, nil, 0
+	}
+	var (
+		files      []rotatedFile
+		dirs       []string
+		activeSize int64
+	)
+	collect := func(dir string, entries []os.DirEntry) {
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || !w.isLogFile(entry.Name()) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			filePath := filepath.Join(dir, entry.Name())
+			if filePath == active {
+				activeSize = info.Size()
+				continue
+			}
+			files = append(files, rotatedFile{path: filePath, modTime: info.ModTime(), size: info.Size()})
+		}
+	}
+	// FlatLayout 的文件直接位于 Dir 下
+	collect(w.dir, entries)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(rotateDirLayout, entry.Name()); err != nil || len(entry.Name()) != len(rotateDirLayout) {
+			continue
+		}
+		dir := filepath.Join(w.dir, entry.Name())
+		dirs = append(dirs, dir)
+		subEntries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		collect(dir, subEntries)
+	}
+	return files, dirs, activeSize
+}
+
+// isLogFile 判断文件名是否属于当前 writer：{base}.log、{base}-{时间戳}.log 及其 .gz 压缩文件
+func (w *rotateWriter) isLogFile(name string) bool {
+	name = strings.TrimSuffix(name, compressSuffix)
+	if name == w.base+".log" {
+		return true
+	}
+	ts, ok := strings.CutPrefix(name, w.base+"-")
+	if !ok {
+		return false
+	}
+	ts, ok = strings.CutSuffix(ts, ".log")
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(rotateBackupLayout, ts)
+	return err == nil
+}
+
+// compressLogFile 将文件 gzip 压缩为 {path}.gz 并删除原文件，保留原文件的修改时间以便按时间清理，返回压缩后的大小
+func compressLogFile(src string) (int64, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return 0, err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	dst := src + compressSuffix
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	_ = os.Chtimes(dst, info.ModTime(), info.ModTime())
+	_ = os.Remove(src)
+
+	dstInfo, err := os.Stat(dst)
+	if err != nil {
+		return 0, err
+	}
+	return dstInfo.Size(), nil
+}
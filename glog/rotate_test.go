@@ -0,0 +1,274 @@
+package glog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRotateWriter 创建使用可控时钟的 rotateWriter
+func newTestRotateWriter(t *testing.T, cfg *LogConfig, now *time.Time) *rotateWriter {
+	t.Helper()
+	if cfg.Service == "" {
+		cfg.Service = "rotate"
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = t.TempDir()
+	}
+	w, err := newRotateWriter(cfg, "full")
+	require.NoError(t, err)
+	// 以可控时钟重新打开当前文件
+	w.mu.Lock()
+	require.NoError(t, w.file.Close())
+	_ = os.Remove(w.filename)
+	_ = os.Remove(filepath.Dir(w.filename))
+	w.now = func() time.Time { return *now }
+	require.NoError(t, w.open(*now))
+	w.mu.Unlock()
+	t.Cleanup(func() { _ = w.Close() })
+	return w
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeOldFile 在日期目录中写入指定修改时间的文件
+func writeOldFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestRotateWriterSize(t *testing.T) {
+	now := time.Now()
+	w := newTestRotateWriter(t, &LogConfig{MaxSize: 1}, &now)
+
+	line := []byte(strings.Repeat("a", 300*1024) + "\n")
+	for i := 0; i < 4; i++ {
+		_, err := w.Write(line)
+		require.NoError(t, err)
+	}
+
+	names := listDir(t, filepath.Join(w.dir, now.Format(rotateDirLayout)))
+	assert.Contains(t, names, "rotate_full.log")
+	backups := 0
+	for _, name := range names {
+		if strings.HasPrefix(name, "rotate_full-") && strings.HasSuffix(name, ".log") {
+			backups++
+		}
+	}
+	assert.Equal(t, 1, backups)
+	assert.Equal(t, int64(len(line)), w.size)
+}
+
+func TestRotateWriterHourly(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 30, 0, 0, time.Local)
+	w := newTestRotateWriter(t, &LogConfig{Rotation: RotationHourly}, &now)
+
+	_, err := w.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	now = time.Date(2026, 3, 10, 11, 0, 1, 0, time.Local)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	dir := filepath.Join(w.dir, "20260310")
+	assert.Equal(t, []string{"rotate_full-2026-03-10T11-00-01.000.log", "rotate_full.log"}, listDir(t, dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "rotate_full.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(content))
+	content, err = os.ReadFile(filepath.Join(dir, "rotate_full-2026-03-10T11-00-01.000.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(content))
+}
+
+func TestRotateWriterDaily(t *testing.T) {
+	now := time.Date(2026, 3, 10, 23, 59, 0, 0, time.Local)
+	w := newTestRotateWriter(t, &LogConfig{}, &now)
+
+	_, err := w.Write([]byte("day1\n"))
+	require.NoError(t, err)
+
+	now = time.Date(2026, 3, 11, 0, 0, 1, 0, time.Local)
+	_, err = w.Write([]byte("day2\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"rotate_full.log"}, listDir(t, filepath.Join(w.dir, "20260310")))
+	assert.Equal(t, []string{"rotate_full.log"}, listDir(t, filepath.Join(w.dir, "20260311")))
+	assert.Equal(t, filepath.Join(w.dir, "20260311", "rotate_full.log"), w.filename)
+}
+
+func TestRotateWriterReopenAfterOpenFailure(t *testing.T) {
+	now := time.Date(2026, 3, 10, 23, 59, 0, 0, time.Local)
+	w := newTestRotateWriter(t, &LogConfig{}, &now)
+
+	// 新日期目录的位置被普通文件占用，切割时打开新文件失败
+	blocker := filepath.Join(w.dir, "20260311")
+	require.NoError(t, os.WriteFile(blocker, nil, 0644))
+	now = time.Date(2026, 3, 11, 0, 0, 1, 0, time.Local)
+	_, err := w.Write([]byte("lost\n"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, os.ErrClosed)
+
+	// 故障恢复后下次写入重新打开文件
+	require.NoError(t, os.Remove(blocker))
+	_, err = w.Write([]byte("day2\n"))
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(blocker, "rotate_full.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "day2\n", string(content))
+
+	require.NoError(t, w.Close())
+	_, err = w.Write([]byte("closed\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestRotateWriterCurrentLink(t *testing.T) {
+	now := time.Date(2026, 3, 10, 23, 59, 0, 0, time.Local)
+	w := newTestRotateWriter(t, &LogConfig{CurrentLink: true}, &now)
+
+	_, err := w.Write([]byte("day1\n"))
+	require.NoError(t, err)
+	target, err := os.Readlink(filepath.Join(w.dir, currentLinkName))
+	require.NoError(t, err)
+	assert.Equal(t, "20260310", target)
+
+	now = time.Date(2026, 3, 11, 0, 0, 1, 0, time.Local)
+	_, err = w.Write([]byte("day2\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Sync())
+
+	// 固定路径跨天后指向新的日期目录
+	content, err := os.ReadFile(filepath.Join(w.dir, currentLinkName, "rotate_full.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "day2\n", string(content))
+	assert.Equal(t, []string{"20260310", "20260311", currentLinkName}, listDir(t, w.dir))
+}
+
+func TestRotateWriterFlatLayout(t *testing.T) {
+	now := time.Date(2026, 3, 10, 23, 59, 0, 0, time.Local)
+	w := newTestRotateWriter(t, &LogConfig{FlatLayout: true, CurrentLink: true, MaxBackups: 1}, &now)
+
+	_, err := w.Write([]byte("day1\n"))
+	require.NoError(t, err)
+	now = time.Date(2026, 3, 11, 0, 0, 1, 0, time.Local)
+	_, err = w.Write([]byte("day2\n"))
+	require.NoError(t, err)
+	now = time.Date(2026, 3, 12, 0, 0, 1, 0, time.Local)
+	_, err = w.Write([]byte("day3\n"))
+	require.NoError(t, err)
+	w.cleanup()
+
+	assert.Equal(t, filepath.Join(w.dir, "rotate_full.log"), w.filename)
+	assert.Equal(t, []string{"rotate_full-2026-03-12T00-00-01.000.log", "rotate_full.log"}, listDir(t, w.dir))
+}
+
+func TestRotateWriterCleanupMaxAge(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	old := now.AddDate(0, 0, -10)
+	oldDir := filepath.Join(dir, old.Format(rotateDirLayout))
+	writeOldFile(t, filepath.Join(oldDir, "rotate_full.log"), 10, old)
+	// 其他服务的文件不受影响
+	writeOldFile(t, filepath.Join(dir, now.AddDate(0, 0, -9).Format(rotateDirLayout), "other_full.log"), 10, old)
+
+	w := newTestRotateWriter(t, &LogConfig{Dir: dir, MaxAge: 7}, &now)
+	w.cleanup()
+
+	assert.NoDirExists(t, oldDir)
+	assert.FileExists(t, filepath.Join(dir, now.AddDate(0, 0, -9).Format(rotateDirLayout), "other_full.log"))
+	assert.FileExists(t, w.filename)
+}
+
+func TestRotateWriterCleanupMaxBackups(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	for i := 1; i <= 4; i++ {
+		day := now.AddDate(0, 0, -i)
+		writeOldFile(t, filepath.Join(dir, day.Format(rotateDirLayout), "rotate_full.log"), 10, day)
+	}
+
+	w := newTestRotateWriter(t, &LogConfig{Dir: dir, MaxBackups: 2}, &now)
+	w.cleanup()
+
+	assert.DirExists(t, filepath.Join(dir, now.AddDate(0, 0, -1).Format(rotateDirLayout)))
+	assert.DirExists(t, filepath.Join(dir, now.AddDate(0, 0, -2).Format(rotateDirLayout)))
+	assert.NoDirExists(t, filepath.Join(dir, now.AddDate(0, 0, -3).Format(rotateDirLayout)))
+	assert.NoDirExists(t, filepath.Join(dir, now.AddDate(0, 0, -4).Format(rotateDirLayout)))
+}
+
+func TestRotateWriterCleanupMaxTotalSize(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	for i := 1; i <= 3; i++ {
+		day := now.AddDate(0, 0, -i)
+		writeOldFile(t, filepath.Join(dir, day.Format(rotateDirLayout), "rotate_full.log"), 400*1024, day)
+	}
+
+	w := newTestRotateWriter(t, &LogConfig{Dir: dir, MaxTotalSize: 1}, &now)
+	_, err := w.Write([]byte(strings.Repeat("b", 100*1024)))
+	require.NoError(t, err)
+	w.cleanup()
+
+	// 当前文件 100KB + 最近两天 800KB 未超过 1MB，最旧的一天被删除
+	assert.DirExists(t, filepath.Join(dir, now.AddDate(0, 0, -1).Format(rotateDirLayout)))
+	assert.DirExists(t, filepath.Join(dir, now.AddDate(0, 0, -2).Format(rotateDirLayout)))
+	assert.NoDirExists(t, filepath.Join(dir, now.AddDate(0, 0, -3).Format(rotateDirLayout)))
+}
+
+func TestRotateWriterCompress(t *testing.T) {
+	now := time.Now()
+	w := newTestRotateWriter(t, &LogConfig{MaxSize: 1, Compress: true}, &now)
+
+	_, err := w.Write([]byte(strings.Repeat("c", 800*1024)))
+	require.NoError(t, err)
+	_, err = w.Write([]byte(strings.Repeat("d", 800*1024)))
+	require.NoError(t, err)
+	w.cleanup()
+
+	dir := filepath.Join(w.dir, now.Format(rotateDirLayout))
+	var gzName string
+	for _, name := range listDir(t, dir) {
+		assert.False(t, strings.HasPrefix(name, "rotate_full-") && strings.HasSuffix(name, ".log"), "uncompressed backup %s", name)
+		if strings.HasSuffix(name, ".log.gz") {
+			gzName = name
+		}
+	}
+	require.NotEmpty(t, gzName)
+
+	f, err := os.Open(filepath.Join(dir, gzName))
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("c", 800*1024), string(content))
+}
+
+func TestRotateWriterInvalidRotation(t *testing.T) {
+	_, err := newRotateWriter(&LogConfig{Service: "rotate", Dir: t.TempDir(), Rotation: "weekly"}, "full")
+	assert.Error(t, err)
+}
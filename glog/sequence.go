@@ -0,0 +1,59 @@
+package glog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestSeqIdleTTL 请求序号的保留时长，超过该时长没有新日志的请求会被清理，之后的日志重新从 1 开始计数
+const requestSeqIdleTTL = 10 * time.Minute
+
+type requestSeq struct {
+	seq      uint64
+	lastUsed time.Time
+}
+
+// requestSeqRegistry 按 request id 维护日志序号，写入时顺带清理长时间未使用的请求
+type requestSeqRegistry struct {
+	mu        sync.Mutex
+	seqs      map[string]*requestSeq
+	lastSweep time.Time
+}
+
+var requestSeqs = &requestSeqRegistry{seqs: make(map[string]*requestSeq)}
+
+// next 返回请求的下一个日志序号，从 1 开始
+func (r *requestSeqRegistry) next(requestID string) uint64 {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now.Sub(r.lastSweep) > requestSeqIdleTTL {
+		for id, s := range r.seqs {
+			if now.Sub(s.lastUsed) > requestSeqIdleTTL {
+				delete(r.seqs, id)
+			}
+		}
+		r.lastSweep = now
+	}
+	s, ok := r.seqs[requestID]
+	if !ok {
+		s = &requestSeq{}
+		r.seqs[requestID] = s
+	}
+	s.seq++
+	s.lastUsed = now
+	return s.seq
+}
+
+// requestSequence 返回 ctx 中请求的下一个日志序号，ctx 未携带 request id 时返回 false
+func requestSequence(ctx context.Context) (uint64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	requestID := GetRequestID(ctx)
+	if requestID == "" {
+		return 0, false
+	}
+	return requestSeqs.next(requestID), true
+}
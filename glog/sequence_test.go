@@ -0,0 +1,55 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestSequenceLogged(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: "request-seq", Level: DebugLevel, Writer: WriterCustom, RequestSequence: true}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		requestID := GenRequestID()
+		ctx := context.WithValue(context.Background(), KeyAppRequestID, requestID)
+		other := context.WithValue(context.Background(), KeyAppRequestID, GenRequestID())
+		logger.Info(ctx, "first")
+		logger.Info(other, "other request")
+		logger.Debug(ctx, "second")
+		logger.Info(context.Background(), "without request id")
+		logger.Warnw(ctx, "third")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 5)
+		var seqs []any
+		for _, line := range lines {
+			var entry map[string]any
+			require.Nil(t, json.Unmarshal([]byte(line), &entry), line)
+			seqs = append(seqs, entry[KeyAppLogSeq])
+		}
+		assert.Equal(t, []any{1.0, 1.0, 2.0, nil, 3.0}, seqs, loggerType)
+	}
+}
+
+func TestRequestSeqRegistrySweep(t *testing.T) {
+	r := &requestSeqRegistry{seqs: make(map[string]*requestSeq)}
+	assert.EqualValues(t, 1, r.next("a"))
+	assert.EqualValues(t, 2, r.next("a"))
+	assert.EqualValues(t, 1, r.next("b"))
+
+	// 长时间未使用的请求被清理，之后重新计数
+	r.seqs["a"].lastUsed = time.Now().Add(-2 * requestSeqIdleTTL)
+	r.lastSweep = time.Now().Add(-2 * requestSeqIdleTTL)
+	assert.EqualValues(t, 2, r.next("b"))
+	assert.NotContains(t, r.seqs, "a")
+	assert.EqualValues(t, 1, r.next("a"))
+}
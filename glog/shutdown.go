@@ -0,0 +1,130 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultFlushTimeout Flush、FlushOnPanic 和退出信号刷新日志的默认超时时间，
+// 避免下游阻塞（如远端 sink 不可用）导致进程无法退出
+const DefaultFlushTimeout = 5 * time.Second
+
+// CloseHook 关闭回调，用于缓冲 sink、Kafka sink、异步 writer 等在 Close 时刷新并释放资源
+type CloseHook func() error
+
+type namedCloseHook struct {
+	name string
+	fn   CloseHook
+}
+
+var (
+	closeHooksMu sync.Mutex
+	closeHooks   []namedCloseHook
+
+	flushOnExitOnce sync.Once
+)
+
+// RegisterCloseHook 注册关闭回调，Close 时按注册顺序执行，执行完毕后清空，
+// 回调先于默认 logger 关闭执行，回调中仍可正常输出日志
+func RegisterCloseHook(name string, fn CloseHook) {
+	if fn == nil {
+		return
+	}
+	closeHooksMu.Lock()
+	defer closeHooksMu.Unlock()
+	closeHooks = append(closeHooks, namedCloseHook{name: name, fn: fn})
+}
+
+// runCloseHooks 按注册顺序执行并清空关闭回调，单个回调失败不影响后续回调执行
+func runCloseHooks() error {
+	closeHooksMu.Lock()
+	hooks := closeHooks
+	closeHooks = nil
+	closeHooksMu.Unlock()
+
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook.fn(); err != nil {
+			errs = append(errs, fmt.Errorf("close hook %s: %w", hook.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RegisterFlushOnExit 监听退出信号，收到信号后执行 Close 刷新日志再退出进程，避免发布时丢失尾部日志。
+// 未指定信号时监听 SIGINT 和 SIGTERM，重复调用只生效一次；Close 超过 DefaultFlushTimeout 未完成时直接退出。
+func RegisterFlushOnExit(signals ...os.Signal) {
+	flushOnExitOnce.Do(func() {
+		if len(signals) == 0 {
+			signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+		}
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, signals...)
+		go func() {
+			sig := <-ch
+			signal.Stop(ch)
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultFlushTimeout)
+			if err := runWithContext(ctx, Close); err != nil {
+				fmt.Fprintf(os.Stderr, "glog close on exit failed, signal: %s, error: %v\n", sig, err)
+			}
+			cancel()
+			os.Exit(exitCode(sig))
+		}()
+	})
+}
+
+// exitCode 按 shell 惯例返回 128+信号值
+func exitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
+
+// Flush 将默认 logger 缓冲中的日志写入下游并刷盘，不释放资源，之后仍可继续写日志。
+// ctx 未设置 deadline 时使用 DefaultFlushTimeout，超时后返回错误，缓冲中的日志仍会在后台继续写入
+func Flush(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultFlushTimeout)
+		defer cancel()
+	}
+	return runWithContext(ctx, defaultLoggerInstance.Sync)
+}
+
+// FlushOnPanic 捕获 panic 后记录 panic 信息和调用栈并刷新日志，再重新抛出 panic，
+// 避免进程崩溃前的日志滞留在缓冲中丢失。需在 main 或 goroutine 入口处直接 defer 调用：
+//
+//	defer glog.FlushOnPanic()
+func FlushOnPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	Errorw(context.Background(), "panic recovered, flushing logs", KeyPanic, NewPanicValue(r), "stack", string(debug.Stack()))
+	if err := Flush(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "glog flush on panic failed, error: %v\n", err)
+	}
+	panic(r)
+}
+
+// runWithContext 执行 fn，ctx 结束时 fn 仍未完成则返回错误，fn 在后台继续执行
+func runWithContext(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("glog: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,381 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink 自定义日志输出目标，Writer 为 WriterCustom 时使用。
+// 每次 Write 传入一条完整的日志（按 Encoding 编码的一行），调用返回后 p 可能被复用，需要异步处理的实现应自行拷贝；
+// Write 会被并发调用，实现需保证并发安全。
+type Sink interface {
+	io.Writer
+	// Sync 刷新缓冲的日志，logger Close 时调用
+	Sync() error
+	// Close 刷新并释放资源，glog.Close 时按注册顺序调用
+	Close() error
+}
+
+var (
+	sinkMu sync.RWMutex
+	sinks  = make(map[string]Sink)
+)
+
+// RegisterSink 按名称注册 Sink，LogConfig.Sinks 中按名称引用。
+// 注册时同时通过 RegisterCloseHook 登记关闭回调，glog.Close 时自动刷新，避免丢失尾部日志。
+func RegisterSink(name string, sink Sink) error {
+	if name == "" || sink == nil {
+		return errors.New("sink name and sink are required")
+	}
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+	if _, ok := sinks[name]; ok {
+		return fmt.Errorf("sink %s already registered", name)
+	}
+	sinks[name] = sink
+	RegisterCloseHook("sink:"+name, sink.Close)
+	return nil
+}
+
+// GetSink 获取已注册的 Sink
+func GetSink(name string) (Sink, bool) {
+	sinkMu.RLock()
+	defer sinkMu.RUnlock()
+	sink, ok := sinks[name]
+	return sink, ok
+}
+
+// resolveSinks 合并 LogConfig.Sinks 引用的已注册 Sink 和 WithSinks 传入的 Sink
+func resolveSinks(cfg *LogConfig, optCfg *optConfig) ([]Sink, error) {
+	return lookupSinks(cfg.Sinks, optCfg.sinks)
+}
+
+// resolveOutputSinks 返回 custom 输出使用的 Sink：引用了 Sink 名称时只使用引用的 Sink，否则使用 WithSinks 传入的 Sink
+func resolveOutputSinks(out *OutputConfig, optCfg *optConfig) ([]Sink, error) {
+	if len(out.Sinks) > 0 {
+		return lookupSinks(out.Sinks, nil)
+	}
+	return lookupSinks(nil, optCfg.sinks)
+}
+
+// lookupSinks 按名称查找已注册的 Sink，并追加 extra
+func lookupSinks(names []string, extra []Sink) ([]Sink, error) {
+	var result []Sink
+	for _, name := range names {
+		sink, ok := GetSink(name)
+		if !ok {
+			return nil, fmt.Errorf("sink %s not registered", name)
+		}
+		result = append(result, sink)
+	}
+	result = append(result, extra...)
+	if len(result) == 0 {
+		return nil, errors.New("no sink configured for custom writer")
+	}
+	return result, nil
+}
+
+// multiSink 将日志写入多个 Sink，单个 Sink 失败不影响其他 Sink
+type multiSink []Sink
+
+func (m multiSink) Write(p []byte) (int, error) {
+	var errs []error
+	for _, sink := range m {
+		if _, err := sink.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+func (m multiSink) Sync() error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Sync())
+	}
+	return errors.Join(errs...)
+}
+
+// ---------------------------------------------------------------------------
+// WriterSink —— 包装任意 io.Writer
+// ---------------------------------------------------------------------------
+
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink 将 io.Writer 包装为 Sink，写入加锁保证并发安全；
+// w 实现了 Sync() error 或 io.Closer 时在 Sync/Close 中调用
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+func (s *writerSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if syncer, ok := s.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+func (s *writerSink) Close() error {
+	if err := s.Sync(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// BatchSink —— 批量异步发送与重试
+// ---------------------------------------------------------------------------
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	defaultBufferSize    = 10000
+	defaultMaxRetries    = 3
+	defaultRetryBackoff  = 200 * time.Millisecond
+)
+
+// BatchSender 批量发送日志，由 Kafka、Loki 等远程 Sink 实现
+type BatchSender interface {
+	Send(ctx context.Context, lines [][]byte) error
+}
+
+// BatchSenderFunc 函数形式的 BatchSender
+type BatchSenderFunc func(ctx context.Context, lines [][]byte) error
+
+// Send 实现 BatchSender 接口
+func (f BatchSenderFunc) Send(ctx context.Context, lines [][]byte) error {
+	return f(ctx, lines)
+}
+
+// BatchOption BatchSink 选项
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	batchSize     int
+	flushInterval time.Duration
+	bufferSize    int
+	maxRetries    int
+	retryBackoff  time.Duration
+	sendTimeout   time.Duration
+}
+
+// WithBatchSize 设置单批最大条数，默认 100
+func WithBatchSize(size int) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.batchSize = size
+	}
+}
+
+// WithFlushInterval 设置定时发送间隔，默认 1s
+func WithFlushInterval(interval time.Duration) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.flushInterval = interval
+	}
+}
+
+// WithBufferSize 设置待发送缓冲区条数，缓冲区满时丢弃新日志，默认 10000
+func WithBufferSize(size int) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.bufferSize = size
+	}
+}
+
+// WithRetry 设置发送失败的最大重试次数和初始退避时间，退避时间按次数翻倍，默认 3 次、200ms
+func WithRetry(maxRetries int, backoff time.Duration) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.maxRetries = maxRetries
+		cfg.retryBackoff = backoff
+	}
+}
+
+// WithSendTimeout 设置单次发送的超时时间，默认不限制
+func WithSendTimeout(timeout time.Duration) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.sendTimeout = timeout
+	}
+}
+
+// BatchSink 将日志缓冲后按条数或时间间隔批量发送，发送失败按退避重试，重试耗尽后丢弃该批并输出到 stderr。
+// 发送在后台协程中进行，不阻塞日志写入。
+type BatchSink struct {
+	sender  BatchSender
+	cfg     batchConfig
+	lines   chan []byte
+	flushCh chan chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	closeOnce sync.Once
+	mu        sync.RWMutex
+	closed    bool
+}
+
+// NewBatchSink 创建 BatchSink 并启动后台发送协程
+func NewBatchSink(sender BatchSender, opts ...BatchOption) *BatchSink {
+	cfg := batchConfig{
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		bufferSize:    defaultBufferSize,
+		maxRetries:    defaultMaxRetries,
+		retryBackoff:  defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.batchSize <= 0 {
+		cfg.batchSize = defaultBatchSize
+	}
+	if cfg.flushInterval <= 0 {
+		cfg.flushInterval = defaultFlushInterval
+	}
+	if cfg.bufferSize <= 0 {
+		cfg.bufferSize = defaultBufferSize
+	}
+
+	s := &BatchSink{
+		sender:  sender,
+		cfg:     cfg,
+		lines:   make(chan []byte, cfg.bufferSize),
+		flushCh: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// Write 拷贝日志放入缓冲区，缓冲区已满或已关闭时丢弃
+func (s *BatchSink) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return 0, errors.New("batch sink closed")
+	}
+	line := make([]byte, len(p))
+	copy(line, p)
+	select {
+	case s.lines <- line:
+		return len(p), nil
+	default:
+		return 0, errors.New("batch sink buffer full, log dropped")
+	}
+}
+
+// Sync 发送缓冲区中已写入的日志并等待完成
+func (s *BatchSink) Sync() error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil
+	}
+	ack := make(chan struct{})
+	s.flushCh <- ack
+	s.mu.RUnlock()
+	<-ack
+	return nil
+}
+
+// Close 停止接收日志，发送剩余日志后退出后台协程
+func (s *BatchSink) Close() error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		close(s.done)
+		s.mu.Unlock()
+		s.wg.Wait()
+	})
+	return nil
+}
+
+func (s *BatchSink) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.cfg.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.cfg.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		s.send(batch)
+		batch = make([][]byte, 0, s.cfg.batchSize)
+	}
+	// drain 取出缓冲区中当前已有的日志
+	drain := func() {
+		for {
+			select {
+			case line := <-s.lines:
+				batch = append(batch, line)
+				if len(batch) >= s.cfg.batchSize {
+					flush()
+				}
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case line := <-s.lines:
+			batch = append(batch, line)
+			if len(batch) >= s.cfg.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case ack := <-s.flushCh:
+			drain()
+			flush()
+			close(ack)
+		case <-s.done:
+			drain()
+			flush()
+			return
+		}
+	}
+}
+
+// send 发送一批日志，失败时按指数退避重试
+func (s *BatchSink) send(batch [][]byte) {
+	backoff := s.cfg.retryBackoff
+	var err error
+	for attempt := 0; attempt <= s.cfg.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if s.cfg.sendTimeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, s.cfg.sendTimeout)
+		}
+		err = s.sender.Send(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+	}
+	// 日志系统自身的错误不能再写日志，直接输出到 stderr
+	fmt.Fprintf(os.Stderr, "glog batch sink send failed, %d logs dropped, error: %v\n", len(batch), err)
+}
@@ -0,0 +1,48 @@
+package glog
+
+import (
+	"context"
+	"errors"
+)
+
+// KafkaMessage 发往 Kafka 的一条消息
+type KafkaMessage struct {
+	Topic string
+	Key   []byte
+	Value []byte
+}
+
+// KafkaProducer Kafka 生产者，由业务方基于 sarama、kafka-go 等客户端适配，glog 不直接依赖具体客户端
+type KafkaProducer interface {
+	SendMessages(ctx context.Context, msgs []KafkaMessage) error
+}
+
+// KafkaProducerFunc 函数形式的 KafkaProducer
+type KafkaProducerFunc func(ctx context.Context, msgs []KafkaMessage) error
+
+// SendMessages 实现 KafkaProducer 接口
+func (f KafkaProducerFunc) SendMessages(ctx context.Context, msgs []KafkaMessage) error {
+	return f(ctx, msgs)
+}
+
+// KafkaSinkConfig Kafka Sink 配置
+type KafkaSinkConfig struct {
+	Producer KafkaProducer // 生产者，必填
+	Topic    string        // 日志 topic，必填
+	Key      []byte        // 消息 key，为空时由生产者决定分区
+}
+
+// NewKafkaSink 创建批量发送到 Kafka 的 Sink，每条日志一条消息
+func NewKafkaSink(cfg KafkaSinkConfig, opts ...BatchOption) (*BatchSink, error) {
+	if cfg.Producer == nil || cfg.Topic == "" {
+		return nil, errors.New("kafka sink producer and topic are required")
+	}
+	sender := BatchSenderFunc(func(ctx context.Context, lines [][]byte) error {
+		msgs := make([]KafkaMessage, 0, len(lines))
+		for _, line := range lines {
+			msgs = append(msgs, KafkaMessage{Topic: cfg.Topic, Key: cfg.Key, Value: line})
+		}
+		return cfg.Producer.SendMessages(ctx, msgs)
+	})
+	return NewBatchSink(sender, opts...), nil
+}
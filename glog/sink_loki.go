@@ -0,0 +1,90 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultLokiTimeout = 5 * time.Second
+
+// LokiSinkConfig Loki Sink 配置
+type LokiSinkConfig struct {
+	URL      string            // push 接口地址，如 http://loki:3100/loki/api/v1/push，必填
+	Labels   map[string]string // stream 标签，如 {"service": "demo", "env": "prod"}
+	TenantID string            // 多租户时的 X-Scope-OrgID
+	Username string            // basic auth 用户名
+	Password string            // basic auth 密码
+	Client   *http.Client      // HTTP 客户端，默认超时 5s
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// NewLokiSink 创建批量推送到 Loki 的 Sink。
+// 日志时间戳取发送时间，同一批内按写入顺序递增 1ns，保证同一 stream 内有序。
+func NewLokiSink(cfg LokiSinkConfig, opts ...BatchOption) (*BatchSink, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("loki sink url is required")
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultLokiTimeout}
+	}
+	labels := cfg.Labels
+	if len(labels) == 0 {
+		labels = map[string]string{"job": defaultServiceName}
+	}
+
+	sender := BatchSenderFunc(func(ctx context.Context, lines [][]byte) error {
+		now := time.Now().UnixNano()
+		values := make([][2]string, 0, len(lines))
+		for i, line := range lines {
+			values = append(values, [2]string{
+				strconv.FormatInt(now+int64(i), 10),
+				strings.TrimRight(string(line), "\n"),
+			})
+		}
+		body, err := json.Marshal(lokiPushRequest{Streams: []lokiStream{{Stream: labels, Values: values}}})
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if cfg.TenantID != "" {
+			req.Header.Set("X-Scope-OrgID", cfg.TenantID)
+		}
+		if cfg.Username != "" {
+			req.SetBasicAuth(cfg.Username, cfg.Password)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return fmt.Errorf("loki push failed, status: %d, body: %s", resp.StatusCode, msg)
+		}
+		return nil
+	})
+	return NewBatchSink(sender, opts...), nil
+}
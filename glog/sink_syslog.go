@@ -0,0 +1,165 @@
+package glog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslog facility，取值见 RFC 5424
+const (
+	SyslogFacilityUser   = 1
+	SyslogFacilityLocal0 = 16
+)
+
+// syslog severity，取值见 RFC 5424
+const (
+	syslogSeverityEmerg   = 0
+	syslogSeverityCrit    = 2
+	syslogSeverityErr     = 3
+	syslogSeverityWarning = 4
+	syslogSeverityInfo    = 6
+	syslogSeverityDebug   = 7
+)
+
+// 本地 syslog 的 unix socket 路径
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogSinkConfig syslog Sink 配置
+type SyslogSinkConfig struct {
+	Network  string // 网络类型 udp、tcp，为空时连接本地 syslog 的 unix socket
+	Addr     string // 远程地址，如 127.0.0.1:514
+	Tag      string // APP-NAME，默认使用进程名
+	Facility int    // facility，默认 SyslogFacilityUser
+}
+
+// SyslogSink 按 RFC 5424 格式发送日志到 syslog，severity 根据日志中的 level 字段确定。
+// tcp 连接按 RFC 6587 octet counting 分帧，写入失败时重连一次。
+type SyslogSink struct {
+	cfg      SyslogSinkConfig
+	hostname string
+	mu       sync.Mutex
+	conn     net.Conn
+	stream   bool
+}
+
+// NewSyslogSink 创建 syslog Sink 并建立连接
+func NewSyslogSink(cfg SyslogSinkConfig) (*SyslogSink, error) {
+	if cfg.Tag == "" {
+		cfg.Tag = defaultServiceName
+		if len(os.Args) > 0 {
+			cfg.Tag = strings.TrimSuffix(os.Args[0][strings.LastIndexAny(os.Args[0], `/\`)+1:], ".exe")
+		}
+	}
+	if cfg.Facility == 0 {
+		cfg.Facility = SyslogFacilityUser
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	s := &SyslogSink{cfg: cfg, hostname: hostname}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SyslogSink) connect() error {
+	if s.cfg.Network != "" {
+		conn, err := net.Dial(s.cfg.Network, s.cfg.Addr)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		s.stream = strings.HasPrefix(s.cfg.Network, "tcp")
+		return nil
+	}
+	for _, path := range syslogLocalPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				s.conn = conn
+				s.stream = network == "unix"
+				return nil
+			}
+		}
+	}
+	return errors.New("local syslog socket not found")
+}
+
+// Write 发送一条日志
+func (s *SyslogSink) Write(p []byte) (int, error) {
+	msg := s.format(p)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		if _, err := s.conn.Write(msg); err == nil {
+			return len(p), nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	if err := s.connect(); err != nil {
+		return 0, err
+	}
+	if _, err := s.conn.Write(msg); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync syslog 逐条发送，无需刷新
+func (s *SyslogSink) Sync() error {
+	return nil
+}
+
+// Close 关闭连接
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format 按 RFC 5424 组装消息：<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+func (s *SyslogSink) format(p []byte) []byte {
+	line := strings.TrimRight(string(p), "\n")
+	pri := s.cfg.Facility*8 + syslogSeverity(p)
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		pri, time.Now().Format(time.RFC3339Nano), s.hostname, s.cfg.Tag, os.Getpid(), line)
+	if s.stream {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	return []byte(msg)
+}
+
+// syslogSeverity 根据 JSON 日志中的 level 字段确定 severity，兼容 zap（小写）和 slog（大写）
+func syslogSeverity(p []byte) int {
+	var entry struct {
+		Level string `json:"level"`
+	}
+	_ = json.Unmarshal(p, &entry)
+	switch Level(strings.ToLower(entry.Level)) {
+	case DebugLevel:
+		return syslogSeverityDebug
+	case WarnLevel:
+		return syslogSeverityWarning
+	case ErrorLevel:
+		return syslogSeverityErr
+	case PanicLevel:
+		return syslogSeverityCrit
+	case FatalLevel:
+		return syslogSeverityEmerg
+	default:
+		return syslogSeverityInfo
+	}
+}
@@ -0,0 +1,154 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterCustom(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: "sink-test", Level: InfoLevel, Writer: WriterCustom}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		logger.Infow(context.Background(), "custom sink message", "key", "value")
+		logger.Debug(context.Background(), "filtered message")
+		assert.Nil(t, logger.Close())
+		assert.Contains(t, buf.String(), "custom sink message")
+		assert.NotContains(t, buf.String(), "filtered message")
+	}
+
+	_, err := NewLogger(&LogConfig{Writer: WriterCustom})
+	assert.NotNil(t, err)
+	_, err = NewLogger(&LogConfig{Writer: WriterCustom, Sinks: []string{"not-exist"}})
+	assert.NotNil(t, err)
+}
+
+func TestRegisterSink(t *testing.T) {
+	var buf bytes.Buffer
+	require.Nil(t, RegisterSink("register-test", NewWriterSink(&buf)))
+	assert.NotNil(t, RegisterSink("register-test", NewWriterSink(&buf)))
+
+	logger, err := NewLogger(&LogConfig{Level: InfoLevel, Writer: WriterCustom, Sinks: []string{"register-test"}})
+	require.Nil(t, err)
+	logger.Info(context.Background(), "registered sink message")
+	assert.Contains(t, buf.String(), "registered sink message")
+}
+
+func TestBatchSink(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]string
+		fails   = 1
+	)
+	sender := BatchSenderFunc(func(ctx context.Context, lines [][]byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if fails > 0 {
+			fails--
+			return errors.New("temporary error")
+		}
+		var batch []string
+		for _, line := range lines {
+			batch = append(batch, string(line))
+		}
+		batches = append(batches, batch)
+		return nil
+	})
+	sink := NewBatchSink(sender, WithBatchSize(2), WithFlushInterval(time.Hour), WithRetry(2, time.Millisecond))
+
+	// 写入后复用缓冲区，验证 Sink 已拷贝数据
+	buf := []byte("a")
+	_, _ = sink.Write(buf)
+	buf[0] = 'b'
+	_, _ = sink.Write(buf)
+	_, _ = sink.Write([]byte("c"))
+	assert.Nil(t, sink.Sync())
+
+	mu.Lock()
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, batches)
+	mu.Unlock()
+
+	assert.Nil(t, sink.Close())
+	_, err := sink.Write([]byte("d"))
+	assert.NotNil(t, err)
+}
+
+func TestKafkaSink(t *testing.T) {
+	var msgs []KafkaMessage
+	producer := KafkaProducerFunc(func(ctx context.Context, batch []KafkaMessage) error {
+		msgs = append(msgs, batch...)
+		return nil
+	})
+	_, err := NewKafkaSink(KafkaSinkConfig{Producer: producer})
+	assert.NotNil(t, err)
+
+	sink, err := NewKafkaSink(KafkaSinkConfig{Producer: producer, Topic: "app-log"})
+	require.Nil(t, err)
+	_, _ = sink.Write([]byte(`{"msg":"hello"}`))
+	assert.Nil(t, sink.Close())
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "app-log", msgs[0].Topic)
+	assert.Equal(t, `{"msg":"hello"}`, string(msgs[0].Value))
+}
+
+func TestLokiSink(t *testing.T) {
+	var (
+		req      lokiPushRequest
+		tenantID string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID = r.Header.Get("X-Scope-OrgID")
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := NewLokiSink(LokiSinkConfig{URL: server.URL, Labels: map[string]string{"service": "demo"}, TenantID: "t1"})
+	require.Nil(t, err)
+	_, _ = sink.Write([]byte("line1\n"))
+	_, _ = sink.Write([]byte("line2\n"))
+	assert.Nil(t, sink.Close())
+
+	assert.Equal(t, "t1", tenantID)
+	require.Len(t, req.Streams, 1)
+	assert.Equal(t, "demo", req.Streams[0].Stream["service"])
+	require.Len(t, req.Streams[0].Values, 2)
+	assert.Equal(t, "line1", req.Streams[0].Values[0][1])
+	assert.Less(t, req.Streams[0].Values[0][0], req.Streams[0].Values[1][0])
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.Nil(t, err)
+	defer conn.Close()
+
+	sink, err := NewSyslogSink(SyslogSinkConfig{Network: "udp", Addr: conn.LocalAddr().String(), Tag: "demo"})
+	require.Nil(t, err)
+	defer sink.Close()
+	_, err = sink.Write([]byte(`{"level":"error","msg":"boom"}` + "\n"))
+	require.Nil(t, err)
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.Nil(t, err)
+	msg := string(buf[:n])
+	// facility user(1)*8 + severity err(3)
+	assert.True(t, strings.HasPrefix(msg, "<11>1 "), msg)
+	assert.Contains(t, msg, " demo ")
+	assert.True(t, strings.HasSuffix(msg, `{"level":"error","msg":"boom"}`), msg)
+}
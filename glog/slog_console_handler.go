@@ -0,0 +1,148 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// slogConsoleHandler 人类可读的 slog.Handler，输出格式：
+// 2006-01-02 15:04:05.000000  INFO   file.go:12  message  key=value key2="value with space"
+// 级别按 5 个字符对齐，color 为 true 时着色；ReplaceAttr 对每个字段生效（用于字段截断）。
+type slogConsoleHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	opts   slog.HandlerOptions
+	color  bool
+	attrs  []byte // WithAttrs 预先格式化的字段
+	prefix string // WithGroup 形成的字段名前缀
+}
+
+func newSlogConsoleHandler(w io.Writer, opts *slog.HandlerOptions, color bool) *slogConsoleHandler {
+	h := &slogConsoleHandler{mu: &sync.Mutex{}, w: w, color: color}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func (h *slogConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *slogConsoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	if !r.Time.IsZero() {
+		buf.WriteString(r.Time.Format(consoleTimeLayout))
+		buf.WriteString("  ")
+	}
+
+	level := slogLevelName(r.Level)
+	levelText := formatConsoleLevel(level)
+	if h.color {
+		levelText = colorize(levelText, level)
+	}
+	buf.WriteString(levelText)
+
+	if h.opts.AddSource && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		frame, _ := frames.Next()
+		fmt.Fprintf(&buf, "  %s:%d", filepath.Base(frame.File), frame.Line)
+	}
+
+	buf.WriteString("  ")
+	buf.WriteString(r.Message)
+
+	if len(h.attrs) > 0 || r.NumAttrs() > 0 {
+		buf.WriteString(" ")
+		buf.Write(h.attrs)
+		r.Attrs(func(a slog.Attr) bool {
+			h.appendAttr(&buf, h.prefix, a)
+			return true
+		})
+	}
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+func (h *slogConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	var buf bytes.Buffer
+	buf.Write(h.attrs)
+	for _, a := range attrs {
+		h.appendAttr(&buf, h.prefix, a)
+	}
+	next.attrs = buf.Bytes()
+	return &next
+}
+
+func (h *slogConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := *h
+	next.prefix = h.prefix + name + "."
+	return &next
+}
+
+// appendAttr 以 key=value 形式追加字段，group 类型递归展开，字段名以 "." 拼接
+func (h *slogConsoleHandler) appendAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(nil, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			h.appendAttr(buf, groupPrefix, ga)
+		}
+		return
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(prefix)
+	buf.WriteString(a.Key)
+	buf.WriteByte('=')
+	buf.WriteString(quoteConsoleValue(a.Value.String()))
+}
+
+// quoteConsoleValue 值包含空白、引号或等号时加引号，避免与相邻字段混淆
+func quoteConsoleValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\r\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// slogLevelName 将 slog 级别转为 glog 级别，包含自定义的 Panic/Fatal 级别
+func slogLevelName(level slog.Level) Level {
+	switch {
+	case level >= slogLevelFatal:
+		return FatalLevel
+	case level >= slogLevelPanic:
+		return PanicLevel
+	default:
+		return slogLevelToLevel(level)
+	}
+}
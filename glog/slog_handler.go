@@ -3,17 +3,11 @@ package glog
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"io"
 	"log/slog"
-	"os"
-	"path"
 	"sync"
-	"sync/atomic"
-	"time"
 
 	"go.opentelemetry.io/otel/trace"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // ---------------------------------------------------------------------------
@@ -27,15 +21,31 @@ type gSlogHandler struct {
 	handler         slog.Handler
 	fieldHookFunc   FieldHookFunc
 	messageHookFunc MessageHookFunc
+	redactor        *redactor
 	enableOTELTrace bool
-	cfg             *LogConfig // 只读，构造后不修改
+	minLevel        slog.Level       // 输出自身的最低级别，请求级日志级别放宽模块级别时仍需满足
+	stack           *stacktraceLevel // 附加调用栈的最低级别
+	cfg             *LogConfig       // 只读，构造后不修改
 }
 
-func newSlogHandler(cfg *LogConfig, optCfg *optConfig, writer io.Writer) *gSlogHandler {
+// newSlogHandler 创建 handler，encoding 为 console 时使用 slogConsoleHandler，color 控制是否对级别着色，
+// redactor 为 nil 时不脱敏
+func newSlogHandler(cfg *LogConfig, optCfg *optConfig, writer io.Writer, encoding EncodingType, color bool, redactor *redactor) *gSlogHandler {
+	return newSlogLevelHandler(cfg, optCfg, writer, encoding, color, redactor, registerModuleLevel(cfg.Module, cfg.Level))
+}
+
+// newSlogLevelHandler 与 newSlogHandler 相同，级别过滤使用 level
+func newSlogLevelHandler(cfg *LogConfig, optCfg *optConfig, writer io.Writer, encoding EncodingType, color bool, redactor *redactor, level slog.Leveler) *gSlogHandler {
 	h := &gSlogHandler{
+		redactor:        redactor,
 		enableOTELTrace: cfg.EnableOTELTrace,
+		minLevel:        slog.LevelDebug,
+		stack:           registerStacktraceLevel(cfg),
 		cfg:             cfg,
 	}
+	if ol, ok := level.(*outputLevel); ok && ol.min != "" {
+		h.minLevel = logLevelToSlog(ol.min)
+	}
 
 	if optCfg != nil {
 		h.fieldHookFunc = optCfg.fieldHookFunc
@@ -45,13 +55,28 @@ func newSlogHandler(cfg *LogConfig, optCfg *optConfig, writer io.Writer) *gSlogH
 		}
 	}
 
+	sizeLimiter := newFieldSizeLimiter(cfg)
+	var encoder *encoderSettings
+	if encoding == EncodingConsole {
+		sizeLimiter = newConsoleFieldSizeLimiter(cfg)
+	} else {
+		// 构造 logger 时已校验过配置，这里不会出错
+		encoder, _ = newEncoderSettings(cfg)
+	}
 	handlerOpts := &slog.HandlerOptions{
 		AddSource: true,
-		Level:     logLevelToSlog(cfg.Level),
-		// 将自定义 Level 常量（PanicLevel / FatalLevel）映射为可读字符串
-		ReplaceAttr: replaceLevel,
+		Level:     level,
+		// 将自定义 Level 常量（PanicLevel / FatalLevel）映射为可读字符串，脱敏后截断超长字段，最后按 Encoder 配置重命名标准字段
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			a = redactor.redactSlogAttr(groups, replaceLevel(groups, a))
+			return encoder.replaceSlogAttr(groups, sizeLimiter.truncateSlogAttr(groups, a))
+		},
+	}
+	if encoding == EncodingConsole {
+		h.handler = newSlogConsoleHandler(writer, handlerOpts, color)
+	} else {
+		h.handler = slog.NewJSONHandler(writer, handlerOpts)
 	}
-	h.handler = slog.NewJSONHandler(writer, handlerOpts)
 	return h
 }
 
@@ -74,7 +99,7 @@ func replaceLevel(groups []string, a slog.Attr) slog.Attr {
 }
 
 func (h *gSlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return h.handler.Enabled(ctx, level)
+	return h.handler.Enabled(ctx, level) || ctxLevelEnabled(ctx, level, h.minLevel)
 }
 
 func (h *gSlogHandler) Handle(ctx context.Context, r slog.Record) error {
@@ -89,6 +114,7 @@ func (h *gSlogHandler) Handle(ctx context.Context, r slog.Record) error {
 	if h.messageHookFunc != nil {
 		r.Message = h.messageHookFunc(r.Message)
 	}
+	r.Message = h.redactor.redactMessage(r.Message)
 
 	// 提取横切字段（OTEL trace + ctx extra keys），使用 pool 减少 GC 压力
 	fields := acquireFields()
@@ -104,6 +130,9 @@ func (h *gSlogHandler) Handle(ctx context.Context, r slog.Record) error {
 	for _, f := range fields {
 		r.AddAttrs(slog.Any(f.Key, f.Value))
 	}
+	if h.stack != nil && h.stack.enabledSlog(r.Level) {
+		r.AddAttrs(slog.String(stacktraceKey, slogStacktrace()))
+	}
 
 	return h.handler.Handle(ctx, r)
 }
@@ -113,7 +142,10 @@ func (h *gSlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		handler:         h.handler.WithAttrs(attrs),
 		fieldHookFunc:   h.fieldHookFunc,
 		messageHookFunc: h.messageHookFunc,
+		redactor:        h.redactor,
 		enableOTELTrace: h.enableOTELTrace,
+		minLevel:        h.minLevel,
+		stack:           h.stack,
 		cfg:             h.cfg, // cfg 构造后只读，共享指针安全
 	}
 }
@@ -123,7 +155,10 @@ func (h *gSlogHandler) WithGroup(name string) slog.Handler {
 		handler:         h.handler.WithGroup(name),
 		fieldHookFunc:   h.fieldHookFunc,
 		messageHookFunc: h.messageHookFunc,
+		redactor:        h.redactor,
 		enableOTELTrace: h.enableOTELTrace,
+		minLevel:        h.minLevel,
+		stack:           h.stack,
 		cfg:             h.cfg,
 	}
 }
@@ -154,6 +189,21 @@ func (h *gSlogHandler) extractFields(ctx context.Context, dst []Field) []Field {
 		}
 	}
 
+	// CtxWith 绑定的字段
+	dst = append(dst, CtxFields(ctx)...)
+
+	if h.cfg != nil && h.cfg.RequestSequence {
+		if seq, ok := requestSequence(ctx); ok {
+			dst = append(dst, Field{Key: KeyAppLogSeq, Value: seq})
+		}
+	}
+
+	if h.cfg != nil {
+		if date, ok := h.cfg.Metadata.indexDate(); ok {
+			dst = append(dst, Field{Key: KeyLogIndexDate, Value: date})
+		}
+	}
+
 	return dst
 }
 
@@ -243,6 +293,10 @@ func (lw *levelWriter) shouldWrite(p []byte) bool {
 	needle := []byte(`"level":"`)
 	idx := bytes.Index(scanRange, needle)
 	if idx < 0 {
+		// console 编码：日期、时间之后的第三个字段为级别
+		if fields := bytes.Fields(scanRange); len(fields) >= 3 {
+			return lw.levelEnabled(string(fields[2]))
+		}
 		return true // 解析不到 level 字段时放行
 	}
 	rest := scanRange[idx+len(needle):]
@@ -250,9 +304,12 @@ func (lw *levelWriter) shouldWrite(p []byte) bool {
 	if end < 0 {
 		return true
 	}
-	levelBytes := rest[:end]
+	return lw.levelEnabled(string(rest[:end]))
+}
 
-	switch string(levelBytes) {
+// levelEnabled 判断级别文本是否 >= minLevel
+func (lw *levelWriter) levelEnabled(levelText string) bool {
+	switch levelText {
 	case "DEBUG":
 		return slog.LevelDebug >= lw.minLevel
 	case "INFO":
@@ -271,229 +328,71 @@ func (lw *levelWriter) shouldWrite(p []byte) bool {
 }
 
 // ---------------------------------------------------------------------------
-// gSlogFileWriter —— 带日期轮转的文件 writer
+// gSlogFileWriter —— 按时间和大小切割的文件 writer
 // ---------------------------------------------------------------------------
 
-// writerPair 持有一对日志文件 writer，作为原子替换的整体单元。
-type writerPair struct {
-	full     *lumberjack.Logger
-	wf       *levelWriter
-	fullFile *os.File // 持有底层 *os.File，用于 Close 时 Sync 刷盘
-	wfFile   *os.File
-}
-
 type gSlogFileWriter struct {
-	cfg          *LogConfig
-	rotateMu     sync.Mutex // 仅保护 rotate 操作，不参与常规写入
-	current      atomic.Pointer[writerPair]
-	currentDate  atomic.Value // string，存当前日期 "20060102"
-	nextRotateAt atomic.Int64 // Unix seconds，下一次需要 rotate 的时间点
+	full     *rotateWriter
+	wfRotate *rotateWriter // 配置 DisableWF 时为 nil
+	wf       *levelWriter  // 包装 wfRotate，仅写入 WFLevel 及以上级别
 }
 
 func newSlogFileWriter(cfg *LogConfig) (*gSlogFileWriter, error) {
-	w := &gSlogFileWriter{cfg: cfg}
-	pair, dateStr, nextAt, err := w.buildWriterPair(time.Now())
+	wfSuffix, wfLevel, wfEnabled, err := cfg.wfFile()
 	if err != nil {
 		return nil, err
 	}
-	w.current.Store(pair)
-	w.currentDate.Store(dateStr)
-	w.nextRotateAt.Store(nextAt)
-	return w, nil
-}
-
-// needsRotate 通过 atomic 快速判断是否需要切换日期目录，无需加锁。
-func (w *gSlogFileWriter) needsRotate(now time.Time) bool {
-	return now.Unix() >= w.nextRotateAt.Load()
-}
-
-// buildWriterPair 构造新的 writerPair，不持有任何锁。
-func (w *gSlogFileWriter) buildWriterPair(now time.Time) (*writerPair, string, int64, error) {
-	dateStr := now.Format("20060102")
-	dir := w.cfg.Dir + "/" + dateStr
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return nil, "", 0, fmt.Errorf("glog: mkdir %s: %w", dir, err)
-	}
-
-	maxSize, maxBackups, maxAge := w.resolvedRotateConfig()
-
-	fullPath := path.Join(dir, fmt.Sprintf("%s_full.log", w.cfg.Service))
-	wfPath := path.Join(dir, fmt.Sprintf("%s_wf.log", w.cfg.Service))
-
-	fullLJ := &lumberjack.Logger{
-		Filename:   fullPath,
-		MaxSize:    maxSize,
-		MaxBackups: maxBackups,
-		MaxAge:     maxAge,
-		Compress:   w.cfg.Compress,
-		LocalTime:  true,
-	}
-	wfLJ := &lumberjack.Logger{
-		Filename:   wfPath,
-		MaxSize:    maxSize,
-		MaxBackups: maxBackups,
-		MaxAge:     maxAge,
-		Compress:   w.cfg.Compress,
-		LocalTime:  true,
-	}
-
-	// 打开底层 *os.File，仅用于 Close 时 Sync 刷盘，不参与常规写入
-	fullFile, err := openLogFile(fullPath)
-	if err != nil {
-		return nil, "", 0, err
-	}
-	wfFile, err := openLogFile(wfPath)
-	if err != nil {
-		_ = fullFile.Close()
-		return nil, "", 0, err
-	}
-
-	pair := &writerPair{
-		full:     fullLJ,
-		wf:       &levelWriter{w: wfLJ, minLevel: slog.LevelWarn},
-		fullFile: fullFile,
-		wfFile:   wfFile,
-	}
-
-	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
-	return pair, dateStr, tomorrow.Unix(), nil
-}
-
-// openLogFile 以 append 模式打开日志文件，仅持有 fd 用于 Sync，不做日常写入。
-func openLogFile(filePath string) (*os.File, error) {
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	full, err := newRotateWriter(cfg, "full")
 	if err != nil {
-		return nil, fmt.Errorf("glog: open %s: %w", filePath, err)
-	}
-	return f, nil
-}
-
-// rotate 在持有 rotateMu 的情况下完成日期切换，采用 double-check 防止重复 rotate。
-func (w *gSlogFileWriter) rotate() error {
-	w.rotateMu.Lock()
-	defer w.rotateMu.Unlock()
-
-	// 重新取时间，避免加锁前后跨天导致 dateStr 不一致
-	now := time.Now()
-
-	// double-check：可能在等锁期间已被其他 goroutine rotate 完毕
-	if !w.needsRotate(now) {
-		return nil
-	}
-
-	newDateStr := now.Format("20060102")
-	if cur, ok := w.currentDate.Load().(string); ok && cur == newDateStr {
-		// 日期未变（极罕见的时钟抖动场景），仅更新 nextRotateAt 防止无限循环
-		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
-		w.nextRotateAt.Store(tomorrow.Unix())
-		return nil
-	}
-
-	pair, dateStr, nextAt, err := w.buildWriterPair(now)
-	if err != nil {
-		return err
-	}
-
-	// 原子替换，新的 goroutine 立即可见新 pair
-	old := w.current.Swap(pair)
-	w.currentDate.Store(dateStr)
-	w.nextRotateAt.Store(nextAt)
-
-	// 异步关闭旧资源，不阻塞写入路径
-	go closeWriterPair(old)
-
-	return nil
-}
-
-func closeWriterPair(pair *writerPair) {
-	if pair == nil {
-		return
-	}
-	if pair.fullFile != nil {
-		_ = pair.fullFile.Sync()
-		_ = pair.fullFile.Close()
-	}
-	if pair.wfFile != nil {
-		_ = pair.wfFile.Sync()
-		_ = pair.wfFile.Close()
-	}
-	if pair.full != nil {
-		_ = pair.full.Close()
+		return nil, err
 	}
-	if pair.wf != nil {
-		if lj, ok := pair.wf.w.(*lumberjack.Logger); ok {
-			_ = lj.Close()
+	w := &gSlogFileWriter{full: full}
+	if wfEnabled {
+		wf, err := newRotateWriter(cfg, wfSuffix)
+		if err != nil {
+			_ = full.Close()
+			return nil, err
 		}
+		w.wfRotate = wf
+		w.wf = &levelWriter{w: wf, minLevel: logLevelToSlog(wfLevel)}
 	}
-}
-
-func (w *gSlogFileWriter) resolvedRotateConfig() (maxSize, maxBackups, maxAge int) {
-	maxSize = w.cfg.MaxSize
-	if maxSize <= 0 {
-		maxSize = 100
-	}
-	maxBackups = w.cfg.MaxBackups
-	if maxBackups <= 0 {
-		maxBackups = 10
-	}
-	maxAge = w.cfg.MaxAge
-	if maxAge <= 0 {
-		maxAge = 7
-	}
-	return
+	return w, nil
 }
 
 func (w *gSlogFileWriter) Write(p []byte) (int, error) {
-	// 快速路径：atomic 读，无锁判断是否需要 rotate
-	if w.needsRotate(time.Now()) {
-		if err := w.rotate(); err != nil {
-			return 0, err
-		}
-	}
-
-	// 无锁读取当前 pair，lumberjack 内部自带锁保证并发安全
-	pair := w.current.Load()
-
-	n, err := pair.full.Write(p)
+	n, err := w.full.Write(p)
 	if err != nil {
 		return n, err
 	}
 
 	// wf 内置 levelWriter 过滤，直接写；忽略 wf 写入错误，不影响 full 路径
-	_, _ = pair.wf.Write(p)
+	if w.wf != nil {
+		_, _ = w.wf.Write(p)
+	}
 
 	return n, nil
 }
 
-// Close 刷盘并释放当前所有文件资源。应在服务退出时调用。
-func (w *gSlogFileWriter) Close() error {
-	pair := w.current.Load()
-	if pair == nil {
-		return nil
-	}
-
-	var firstErr error
-
-	if pair.fullFile != nil {
-		if err := pair.fullFile.Sync(); err != nil && firstErr == nil {
-			firstErr = err
-		}
-		_ = pair.fullFile.Close()
+// Sync 刷盘但不释放文件资源
+func (w *gSlogFileWriter) Sync() error {
+	err := w.full.Sync()
+	if w.wfRotate == nil {
+		return err
 	}
-	if pair.wfFile != nil {
-		if err := pair.wfFile.Sync(); err != nil && firstErr == nil {
-			firstErr = err
-		}
-		_ = pair.wfFile.Close()
+	if wfErr := w.wfRotate.Sync(); err == nil {
+		err = wfErr
 	}
-	if pair.full != nil {
-		_ = pair.full.Close()
+	return err
+}
+
+// Close 刷盘并释放当前所有文件资源。应在服务退出时调用。
+func (w *gSlogFileWriter) Close() error {
+	err := w.full.Close()
+	if w.wfRotate == nil {
+		return err
 	}
-	if pair.wf != nil {
-		if lj, ok := pair.wf.w.(*lumberjack.Logger); ok {
-			_ = lj.Close()
-		}
+	if wfErr := w.wfRotate.Close(); err == nil {
+		err = wfErr
 	}
-
-	return firstErr
+	return err
 }
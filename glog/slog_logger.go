@@ -3,8 +3,11 @@ package glog
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+
+	"go.uber.org/zap"
 )
 
 // slogLogger 是对外暴露的 Logger 实现。
@@ -13,7 +16,11 @@ import (
 type slogLogger struct {
 	logger     *slog.Logger
 	cfg        *LogConfig
-	fileWriter *gSlogFileWriter // nil 表示 console 模式
+	fileWriter *gSlogFileWriter // nil 表示 console 或 custom 模式
+	sinks      multiSink        // custom 模式的输出 Sink
+	async      *asyncWriter     // 配置 Async 时包装 fileWriter 或 sinks
+	outputs    *slogOutputs     // 配置 Outputs 时各输出的 writer
+	metrics    MetricsHook      // 日志计数钩子，为 nil 时不计数
 }
 
 func newSlogLogger(cfg *LogConfig, opts ...Option) (Logger, error) {
@@ -26,22 +33,74 @@ func newSlogLogger(cfg *LogConfig, opts ...Option) (Logger, error) {
 		opt.apply(optCfg)
 	}
 
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := newEncoderSettings(cfg); err != nil {
+		return nil, err
+	}
+	if _, err := cfg.stacktraceLevel(); err != nil {
+		return nil, err
+	}
+
 	var (
 		logger     *slog.Logger
 		fileWriter *gSlogFileWriter
+		sinks      multiSink
+		async      *asyncWriter
+		outputs    *slogOutputs
 	)
 
-	if cfg.Writer == WriterConsole {
-		handler := newSlogHandler(cfg, optCfg, os.Stdout)
-		logger = slog.New(handler)
-	} else {
-		fw, err := newSlogFileWriter(cfg)
+	switch {
+	case len(cfg.Outputs) > 0:
+		handler, o, err := newSlogOutputHandler(cfg, optCfg, redactor)
 		if err != nil {
 			return nil, err
 		}
-		fileWriter = fw
-		handler := newSlogHandler(cfg, optCfg, fw)
+		outputs = o
+		logger = slog.New(handler)
+	case cfg.Writer == WriterConsole:
+		handler := newSlogHandler(cfg, optCfg, os.Stdout, cfg.consoleEncoding(), true, redactor)
 		logger = slog.New(handler)
+	default:
+		var output io.Writer
+		if cfg.Writer == WriterCustom {
+			resolved, err := resolveSinks(cfg, optCfg)
+			if err != nil {
+				return nil, err
+			}
+			sinks = resolved
+			output = sinks
+		} else {
+			fw, err := newSlogFileWriter(cfg)
+			if err != nil {
+				return nil, err
+			}
+			fileWriter = fw
+			output = fw
+		}
+		if cfg.Async != nil {
+			async, err = newAsyncWriter(output, cfg.Async)
+			if err != nil {
+				if fileWriter != nil {
+					_ = fileWriter.Close()
+				}
+				return nil, err
+			}
+			output = async
+		}
+		handler := newSlogHandler(cfg, optCfg, output, cfg.encoding(), false, redactor)
+		logger = slog.New(handler)
+	}
+
+	if cfg.CrashDump != nil {
+		ring := registerCrashRing(cfg)
+		crash := &slogCrashHandler{
+			Handler: newSlogLevelHandler(cfg, optCfg, ring, EncodingJSON, false, redactor, slog.LevelDebug),
+			ring:    ring,
+		}
+		logger = slog.New(slogTeeHandler{logger.Handler(), crash})
 	}
 
 	serviceName := cfg.Service
@@ -58,11 +117,18 @@ func newSlogLogger(cfg *LogConfig, opts ...Option) (Logger, error) {
 		slog.String("service", serviceName),
 		slog.String("module", moduleName),
 	)
+	for _, f := range cfg.Metadata.staticFields() {
+		logger = logger.With(f.Key, f.Value)
+	}
 
 	return &slogLogger{
 		logger:     logger,
 		cfg:        cfg,
 		fileWriter: fileWriter,
+		sinks:      sinks,
+		async:      async,
+		outputs:    outputs,
+		metrics:    optCfg.metricsHook,
 	}, nil
 }
 
@@ -70,6 +136,10 @@ func (l *slogLogger) GetConfig() *LogConfig {
 	return l.cfg
 }
 
+func (l *slogLogger) Unwrap() *zap.Logger {
+	return zap.New(&slogZapCore{handler: l.logger.Handler()}, zap.AddCaller())
+}
+
 // ---------------------------------------------------------------------------
 // With —— 返回携带固定 kv 字段的子 Logger
 // ---------------------------------------------------------------------------
@@ -85,6 +155,10 @@ func (l *slogLogger) With(kvs ...any) Logger {
 		logger:     l.logger.With(kvs...),
 		cfg:        l.cfg,
 		fileWriter: l.fileWriter,
+		sinks:      l.sinks,
+		async:      l.async,
+		outputs:    l.outputs,
+		metrics:    l.metrics,
 	}
 }
 
@@ -104,6 +178,11 @@ func (l *slogLogger) Debugw(ctx context.Context, msg string, kvs ...any) {
 	l.log(ctx, DebugLevel, msg, kvs...)
 }
 
+func (l *slogLogger) Debugt(ctx context.Context, tmpl string, args ...any) {
+	msg, kvs := renderTemplate(tmpl, args)
+	l.log(ctx, DebugLevel, msg, kvs...)
+}
+
 // ---------------------------------------------------------------------------
 // Info
 // ---------------------------------------------------------------------------
@@ -120,6 +199,11 @@ func (l *slogLogger) Infow(ctx context.Context, msg string, kvs ...any) {
 	l.log(ctx, InfoLevel, msg, kvs...)
 }
 
+func (l *slogLogger) Infot(ctx context.Context, tmpl string, args ...any) {
+	msg, kvs := renderTemplate(tmpl, args)
+	l.log(ctx, InfoLevel, msg, kvs...)
+}
+
 // ---------------------------------------------------------------------------
 // Warn
 // ---------------------------------------------------------------------------
@@ -136,6 +220,11 @@ func (l *slogLogger) Warnw(ctx context.Context, msg string, kvs ...any) {
 	l.log(ctx, WarnLevel, msg, kvs...)
 }
 
+func (l *slogLogger) Warnt(ctx context.Context, tmpl string, args ...any) {
+	msg, kvs := renderTemplate(tmpl, args)
+	l.log(ctx, WarnLevel, msg, kvs...)
+}
+
 // ---------------------------------------------------------------------------
 // Error
 // ---------------------------------------------------------------------------
@@ -152,24 +241,39 @@ func (l *slogLogger) Errorw(ctx context.Context, msg string, kvs ...any) {
 	l.log(ctx, ErrorLevel, msg, kvs...)
 }
 
+func (l *slogLogger) Errort(ctx context.Context, tmpl string, args ...any) {
+	msg, kvs := renderTemplate(tmpl, args)
+	l.log(ctx, ErrorLevel, msg, kvs...)
+}
+
 // ---------------------------------------------------------------------------
-// Panic —— 写日志后 panic
+// Panic —— 写日志并刷盘后 panic，recover 后 logger 仍可继续使用
 // ---------------------------------------------------------------------------
 
 func (l *slogLogger) Panic(ctx context.Context, args ...any) {
 	msg := fmt.Sprint(args...)
 	l.log(ctx, PanicLevel, msg)
+	_ = l.Sync()
 	panic(msg)
 }
 
 func (l *slogLogger) Panicf(ctx context.Context, format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
 	l.log(ctx, PanicLevel, msg)
+	_ = l.Sync()
 	panic(msg)
 }
 
 func (l *slogLogger) Panicw(ctx context.Context, msg string, kvs ...any) {
 	l.log(ctx, PanicLevel, msg, kvs...)
+	_ = l.Sync()
+	panic(msg)
+}
+
+func (l *slogLogger) Panict(ctx context.Context, tmpl string, args ...any) {
+	msg, kvs := renderTemplate(tmpl, args)
+	l.log(ctx, PanicLevel, msg, kvs...)
+	_ = l.Sync()
 	panic(msg)
 }
 
@@ -197,15 +301,49 @@ func (l *slogLogger) Fatalw(ctx context.Context, msg string, kvs ...any) {
 	os.Exit(1)
 }
 
+func (l *slogLogger) Fatalt(ctx context.Context, tmpl string, args ...any) {
+	msg, kvs := renderTemplate(tmpl, args)
+	l.log(ctx, FatalLevel, msg, kvs...)
+	_ = l.Close()
+	os.Exit(1)
+}
+
 // ---------------------------------------------------------------------------
 // Close
 // ---------------------------------------------------------------------------
 
+// Sync 将缓冲中的日志写入下游并刷盘，不释放资源，之后仍可继续写日志
+func (l *slogLogger) Sync() error {
+	if l.outputs != nil {
+		return l.outputs.Sync()
+	}
+	if l.async != nil {
+		// 异步 writer 的 Sync 会等待缓冲写完并同步下游
+		return l.async.Sync()
+	}
+	if l.fileWriter != nil {
+		return l.fileWriter.Sync()
+	}
+	if len(l.sinks) > 0 {
+		return l.sinks.Sync()
+	}
+	return nil
+}
+
 // Close 刷盘并释放底层文件资源，应在服务退出时调用。
 func (l *slogLogger) Close() error {
+	if l.outputs != nil {
+		return l.outputs.Close()
+	}
+	if l.async != nil {
+		_ = l.async.Close()
+	}
 	if l.fileWriter != nil {
 		return l.fileWriter.Close()
 	}
+	if len(l.sinks) > 0 {
+		return l.sinks.Sync()
+	}
 	return nil
 }
 
@@ -221,6 +359,10 @@ func (l *slogLogger) log(ctx context.Context, level Level, msg string, kvs ...an
 		return
 	}
 
+	if l.metrics != nil && l.logger.Enabled(ctx, logLevelToSlog(level)) {
+		l.metrics.OnEntry(newMetricsEntry(ctx, level, l.cfg.Module, kvs))
+	}
+
 	kvs = normalizeKVs(kvs)
 
 	switch level {
@@ -0,0 +1,147 @@
+package glog
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/morehao/golib/gutil"
+	"go.uber.org/zap/zapcore"
+)
+
+// StacktraceNone 用于 StacktraceLevel，表示不附加调用栈
+const StacktraceNone Level = "none"
+
+// defaultStacktraceLevel 未配置时附加调用栈的最低级别
+const defaultStacktraceLevel = PanicLevel
+
+// stacktraceKey 调用栈字段名，与 zap 默认的 StacktraceKey 一致
+const stacktraceKey = "stacktrace"
+
+// stacktraceLevel 模块附加调用栈的最低级别，同一模块的所有 logger 共享，修改后立即生效
+type stacktraceLevel struct {
+	level atomic.Value // Level
+}
+
+func (l *stacktraceLevel) get() Level {
+	return l.level.Load().(Level)
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (l *stacktraceLevel) Enabled(level zapcore.Level) bool {
+	min := l.get()
+	return min != StacktraceNone && level >= levelToZapLevel(min)
+}
+
+func (l *stacktraceLevel) enabledSlog(level slog.Level) bool {
+	min := l.get()
+	return min != StacktraceNone && level >= logLevelToSlog(min)
+}
+
+var stacktraceLevels gutil.SyncMap[string, *stacktraceLevel]
+
+// stacktraceLevel 返回模块附加调用栈的最低级别：ModuleStacktraceLevels 中的配置优先，其次为 StacktraceLevel，默认 panic
+func (cfg *LogConfig) stacktraceLevel() (Level, error) {
+	level, ok := cfg.ModuleStacktraceLevels[cfg.moduleName()]
+	if !ok {
+		level = cfg.StacktraceLevel
+	}
+	if level == "" {
+		return defaultStacktraceLevel, nil
+	}
+	if err := validateStacktraceLevel(level); err != nil {
+		return "", err
+	}
+	return level, nil
+}
+
+func (cfg *LogConfig) moduleName() string {
+	if cfg.Module == "" {
+		return defaultModuleName
+	}
+	return cfg.Module
+}
+
+func validateStacktraceLevel(level Level) error {
+	if _, ok := logLevelMap[level]; !ok && level != StacktraceNone {
+		return fmt.Errorf("invalid stacktrace level: %s", level)
+	}
+	return nil
+}
+
+// registerStacktraceLevel 创建 logger 时登记模块附加调用栈的级别，同名模块以最近一次配置为准，配置无效时使用默认级别
+func registerStacktraceLevel(cfg *LogConfig) *stacktraceLevel {
+	level, err := cfg.stacktraceLevel()
+	if err != nil {
+		level = defaultStacktraceLevel
+	}
+	sl := &stacktraceLevel{}
+	sl.level.Store(level)
+	if v, loaded := stacktraceLevels.LoadOrStore(cfg.moduleName(), sl); loaded {
+		sl = v
+		sl.level.Store(level)
+	}
+	return sl
+}
+
+// SetStacktraceLevel 运行时修改模块附加调用栈的最低级别，level 为 StacktraceNone 时不附加，
+// 用于排查问题时临时为 Warn 开启调用栈，或在高频错误路径上关闭调用栈减少日志量
+func SetStacktraceLevel(module string, level Level) error {
+	if err := validateStacktraceLevel(level); err != nil {
+		return err
+	}
+	v, ok := stacktraceLevels.Load(module)
+	if !ok {
+		return fmt.Errorf("log module not found: %s", module)
+	}
+	v.level.Store(level)
+	return nil
+}
+
+// GetStacktraceLevel 获取模块当前附加调用栈的最低级别
+func GetStacktraceLevel(module string) (Level, bool) {
+	v, ok := stacktraceLevels.Load(module)
+	if !ok {
+		return "", false
+	}
+	return v.get(), true
+}
+
+// glogFuncPrefix glog 包内函数名的前缀，slog 调用栈跳过 glog 和 log/slog 内部的调用
+const glogFuncPrefix = "github.com/morehao/golib/glog."
+
+// slogStacktrace 返回从业务调用位置开始的调用栈，格式与 zap 一致。
+// slog.Record.PC 固定指向 slogLogger.log，因此按包名跳过 glog 和 log/slog 内部的调用
+func slogStacktrace() string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	found := false
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		if !found && !isLogFrame(frame) {
+			found = true
+		}
+		if found {
+			if b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+func isLogFrame(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "log/slog.") {
+		return true
+	}
+	return strings.HasPrefix(frame.Function, glogFuncPrefix) && !strings.HasSuffix(frame.File, "_test.go")
+}
@@ -0,0 +1,66 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStacktraceLevel(t *testing.T) {
+	ctx := context.Background()
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		module := fmt.Sprintf("stacktrace_test_%d", loggerType)
+		cfg := &LogConfig{
+			Module:                 module,
+			Level:                  InfoLevel,
+			Writer:                 WriterCustom,
+			StacktraceLevel:        ErrorLevel,
+			ModuleStacktraceLevels: map[string]Level{module: WarnLevel},
+		}
+		// 默认的 callerSkip 按包级函数调用设置，直接使用 logger 时少一层
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)), WithCallerSkip(defaultLogCallerSkip-1))
+		require.Nil(t, err)
+		lines := func() []map[string]any {
+			var entries []map[string]any
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var entry map[string]any
+				require.Nil(t, json.Unmarshal([]byte(line), &entry), line)
+				entries = append(entries, entry)
+			}
+			buf.Reset()
+			return entries
+		}
+
+		// 模块配置优先，Warn 及以上附加调用栈，调用栈从日志调用位置开始
+		logger.Info(ctx, "info")
+		logger.Warn(ctx, "warn")
+		entries := lines()
+		require.Len(t, entries, 2)
+		assert.NotContains(t, entries[0], "stacktrace", loggerType)
+		stack, _ := entries[1]["stacktrace"].(string)
+		assert.True(t, strings.HasPrefix(stack, "github.com/morehao/golib/glog.TestStacktraceLevel"), "%d: %s", loggerType, stack)
+
+		// 运行时关闭
+		level, ok := GetStacktraceLevel(module)
+		assert.True(t, ok)
+		assert.Equal(t, WarnLevel, level)
+		require.Nil(t, SetStacktraceLevel(module, StacktraceNone))
+		logger.Error(ctx, "error")
+		entries = lines()
+		require.Len(t, entries, 1)
+		assert.NotContains(t, entries[0], "stacktrace", loggerType)
+		assert.Nil(t, logger.Close())
+	}
+
+	assert.NotNil(t, SetStacktraceLevel("not-exist", ErrorLevel))
+	assert.NotNil(t, SetStacktraceLevel("stacktrace_test_1", "trace"))
+	_, err := NewLogger(&LogConfig{Module: "stacktrace_invalid", Writer: WriterConsole, StacktraceLevel: "trace"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,78 @@
+package glog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderTemplate 渲染消息模板，返回渲染后的消息和结构化字段 kvs。
+//
+// 模板中的 {name} 为占位符，按出现顺序依次绑定 args 中的值：渲染到消息中，同时以 name 为 key 输出为字段；
+// 同名占位符多次出现时只绑定一次。绑定完占位符后剩余的 args 按 key-value 交替解析为附加字段。
+// "{{" 和 "}}" 输出字面量花括号；缺少对应参数或名称为空、包含空白的占位符原样保留。
+//
+//	renderTemplate("user {user_id} purchased {sku}", []any{42, "A-1", "channel", "app"})
+//	// => "user 42 purchased A-1", ["user_id", 42, "sku", "A-1", "channel", "app"]
+func renderTemplate(tmpl string, args []any) (string, []any) {
+	if !strings.ContainsAny(tmpl, "{}") {
+		return tmpl, args
+	}
+
+	var (
+		b     strings.Builder
+		kvs   []any
+		bound map[string]any
+		next  int
+	)
+	b.Grow(len(tmpl))
+	for i := 0; i < len(tmpl); i++ {
+		c := tmpl[i]
+		if c == '}' && i+1 < len(tmpl) && tmpl[i+1] == '}' {
+			b.WriteByte('}')
+			i++
+			continue
+		}
+		if c != '{' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+1 < len(tmpl) && tmpl[i+1] == '{' {
+			b.WriteByte('{')
+			i++
+			continue
+		}
+		end := strings.IndexByte(tmpl[i+1:], '}')
+		if end < 0 {
+			b.WriteString(tmpl[i:])
+			break
+		}
+		name := tmpl[i+1 : i+1+end]
+		placeholder := tmpl[i : i+2+end]
+		i += end + 1
+
+		if name == "" || strings.ContainsAny(name, " \t\n{") {
+			b.WriteString(placeholder)
+			continue
+		}
+		value, ok := bound[name]
+		if !ok {
+			if next >= len(args) {
+				b.WriteString(placeholder)
+				continue
+			}
+			value = args[next]
+			next++
+			if bound == nil {
+				bound = make(map[string]any)
+			}
+			bound[name] = value
+			kvs = append(kvs, name, value)
+		}
+		b.WriteString(fmt.Sprint(value))
+	}
+
+	if next < len(args) {
+		kvs = append(kvs, args[next:]...)
+	}
+	return b.String(), kvs
+}
@@ -0,0 +1,61 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		args    []any
+		wantMsg string
+		wantKVs []any
+	}{
+		{"plain", "no placeholder", []any{"k", "v"}, "no placeholder", []any{"k", "v"}},
+		{"bind", "user {user_id} purchased {sku}", []any{42, "A-1"}, "user 42 purchased A-1", []any{"user_id", 42, "sku", "A-1"}},
+		{"extra kvs", "user {user_id} login", []any{42, "ip", "1.1.1.1"}, "user 42 login", []any{"user_id", 42, "ip", "1.1.1.1"}},
+		{"repeat", "{id} -> {id}", []any{7}, "7 -> 7", []any{"id", 7}},
+		{"missing arg", "order {order_id} of {user_id}", []any{"o-1"}, "order o-1 of {user_id}", []any{"order_id", "o-1"}},
+		{"escape", "{{literal}} {name}", []any{"x"}, "{literal} x", []any{"name", "x"}},
+		{"invalid", "{} {a b} {name}", []any{"x"}, "{} {a b} x", []any{"name", "x"}},
+		{"unclosed", "value {name", []any{"x"}, "value {name", []any{"x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, kvs := renderTemplate(tt.tmpl, tt.args)
+			assert.Equal(t, tt.wantMsg, msg)
+			assert.Equal(t, tt.wantKVs, kvs)
+		})
+	}
+}
+
+func TestLoggerTemplate(t *testing.T) {
+	for _, loggerType := range []LoggerType{LoggerTypeZap, LoggerTypeSlog} {
+		var buf bytes.Buffer
+		cfg := &LogConfig{Module: "template", Level: InfoLevel, Writer: WriterCustom}
+		logger, err := NewLogger(cfg, WithLoggerType(loggerType), WithSinks(NewWriterSink(&buf)))
+		require.Nil(t, err)
+
+		ctx := context.Background()
+		logger.Infot(ctx, "user {user_id} purchased {sku}", 42, "A-1", "channel", "app")
+		logger.Debugt(ctx, "filtered {value}", 1)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 1, loggerType)
+
+		var entry map[string]any
+		require.Nil(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assert.Equal(t, "user 42 purchased A-1", entry["msg"])
+		assert.EqualValues(t, 42, entry["user_id"])
+		assert.Equal(t, "A-1", entry["sku"])
+		assert.Equal(t, "app", entry["channel"])
+	}
+}
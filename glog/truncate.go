@@ -0,0 +1,258 @@
+package glog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldSizeLimiter 限制字段值的最大字节数，超出部分截断并追加 `...[truncated N bytes]` 标记。
+// 在 encoder/handler 层统一生效，调用方无需关心字段大小。
+type fieldSizeLimiter struct {
+	maxSize int
+	limits  map[string]int
+}
+
+// newFieldSizeLimiter 未配置任何限制时返回 nil，调用方据此跳过截断逻辑。
+func newFieldSizeLimiter(cfg *LogConfig) *fieldSizeLimiter {
+	if cfg == nil || (cfg.MaxFieldSize <= 0 && len(cfg.FieldSizeLimits) == 0) {
+		return nil
+	}
+	return &fieldSizeLimiter{
+		maxSize: cfg.MaxFieldSize,
+		limits:  cfg.FieldSizeLimits,
+	}
+}
+
+// limit 返回字段的最大字节数，字段级配置优先于全局配置，<= 0 表示不限制。
+func (l *fieldSizeLimiter) limit(key string) int {
+	if l == nil {
+		return 0
+	}
+	if size, ok := l.limits[key]; ok {
+		return size
+	}
+	return l.maxSize
+}
+
+// truncate 截断超长字符串，保证不会切断 UTF-8 字符。
+func (l *fieldSizeLimiter) truncate(key, value string) (string, bool) {
+	limit := l.limit(key)
+	if limit <= 0 || len(value) <= limit {
+		return value, false
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...[truncated %d bytes]", value[:cut], len(value)-cut), true
+}
+
+// truncateAny 将非字符串值转成字符串后判断是否超长，超长时返回截断后的字符串。
+func (l *fieldSizeLimiter) truncateAny(key string, value any) (string, bool) {
+	if l.limit(key) <= 0 {
+		return "", false
+	}
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		// 每行日志都会经过这里，只序列化可能超长的类型，序列化结果有上限且不超过限制的类型直接跳过
+		if size := maxJSONSize(reflect.TypeOf(v)); size >= 0 && size <= l.limit(key) {
+			return "", false
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		s = string(b)
+	}
+	return l.truncate(key, s)
+}
+
+// truncateZapFields 截断超长的 zap 字段，只有发生截断时才复制切片，不修改调用方数据。
+func (l *fieldSizeLimiter) truncateZapFields(fields []zapcore.Field) []zapcore.Field {
+	if l == nil {
+		return fields
+	}
+	copied := false
+	for i, f := range fields {
+		var (
+			replaced zapcore.Field
+			ok       bool
+		)
+		switch f.Type {
+		case zapcore.StringType:
+			var truncated string
+			if truncated, ok = l.truncate(f.Key, f.String); ok {
+				replaced = zap.String(f.Key, truncated)
+			}
+		case zapcore.ByteStringType, zapcore.BinaryType, zapcore.ReflectType, zapcore.StringerType:
+			var truncated string
+			if truncated, ok = l.truncateAny(f.Key, f.Interface); ok {
+				replaced = zap.String(f.Key, truncated)
+			}
+		case zapcore.ErrorType:
+			if err, isErr := f.Interface.(error); isErr {
+				var truncated error
+				if truncated, ok = l.truncateError(f.Key, err); ok {
+					replaced = zap.NamedError(f.Key, truncated)
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if !copied {
+			fields = append([]zapcore.Field(nil), fields...)
+			copied = true
+		}
+		fields[i] = replaced
+	}
+	return fields
+}
+
+// truncateError 截断错误信息超长的错误，未超长时保留原错误（包括 zap 输出的 {key}Verbose）。
+// 超长时返回 truncatedError，仍按错误字段输出，原错误实现 fmt.Formatter 时同时截断 %+v 的详细信息
+func (l *fieldSizeLimiter) truncateError(key string, err error) (error, bool) {
+	msg, ok := l.truncate(key, err.Error())
+	if !ok {
+		return nil, false
+	}
+	truncated := &truncatedError{msg: msg}
+	if _, isFormatter := err.(fmt.Formatter); isFormatter {
+		truncated.verbose, _ = l.truncate(key, fmt.Sprintf("%+v", err))
+	}
+	return truncated, true
+}
+
+// truncatedError 截断后的错误
+type truncatedError struct {
+	msg     string
+	verbose string
+}
+
+func (e *truncatedError) Error() string {
+	return e.msg
+}
+
+// Format 按 %+v 输出截断后的详细信息，没有详细信息时与 Error 相同，zap 不再输出 {key}Verbose
+func (e *truncatedError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') && e.verbose != "" {
+		_, _ = io.WriteString(s, e.verbose)
+		return
+	}
+	_, _ = io.WriteString(s, e.msg)
+}
+
+// jsonSizes 缓存 maxJSONSize 的结果
+var jsonSizes sync.Map
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// maxJSONSize 返回类型的值 JSON 序列化后的最大字节数，由数值、布尔及其数组、结构体、指针组成的类型有上限，
+// 字符串、切片、映射、接口、自引用类型以及自定义了序列化方法的类型没有上限，返回 -1
+func maxJSONSize(t reflect.Type) int {
+	if v, ok := jsonSizes.Load(t); ok {
+		return v.(int)
+	}
+	size := typeMaxJSONSize(t, make(map[reflect.Type]bool))
+	jsonSizes.Store(t, size)
+	return size
+}
+
+func typeMaxJSONSize(t reflect.Type, visiting map[reflect.Type]bool) int {
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) || visiting[t] {
+		return -1
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return len("false")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return len("-9223372036854775808")
+	case reflect.Float32, reflect.Float64:
+		return len("-1.7976931348623157e+308")
+	case reflect.Array:
+		elem := typeMaxJSONSize(t.Elem(), visiting)
+		if elem < 0 {
+			return -1
+		}
+		return 2 + t.Len()*(elem+1)
+	case reflect.Pointer:
+		elem := typeMaxJSONSize(t.Elem(), visiting)
+		if elem < 0 {
+			return -1
+		}
+		return max(elem, len("null"))
+	case reflect.Struct:
+		// 按每个导出字段都输出、字段名最长估算，内嵌结构体按嵌套对象估算，结果不小于实际大小
+		size := 2
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() && !f.Anonymous {
+				continue
+			}
+			fieldSize := typeMaxJSONSize(f.Type, visiting)
+			if fieldSize < 0 {
+				return -1
+			}
+			// 字段名、引号、冒号、逗号以及 string 选项的引号
+			size += max(len(f.Name), len(f.Tag.Get("json"))) + 6 + fieldSize
+		}
+		return size
+	default:
+		return -1
+	}
+}
+
+// truncateSlogAttr 截断超长的 slog 属性值，作为 ReplaceAttr 的一部分执行。
+func (l *fieldSizeLimiter) truncateSlogAttr(groups []string, a slog.Attr) slog.Attr {
+	if l == nil {
+		return a
+	}
+	if len(groups) == 0 {
+		switch a.Key {
+		case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+			return a
+		}
+	}
+	value := a.Value.Resolve()
+	var (
+		truncated string
+		ok        bool
+	)
+	switch value.Kind() {
+	case slog.KindString:
+		truncated, ok = l.truncate(a.Key, value.String())
+	case slog.KindAny:
+		truncated, ok = l.truncateAny(a.Key, value.Any())
+	}
+	if !ok {
+		return a
+	}
+	return slog.String(a.Key, truncated)
+}
@@ -0,0 +1,169 @@
+package glog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFieldSizeLimiterTruncate(t *testing.T) {
+	limiter := newFieldSizeLimiter(&LogConfig{
+		MaxFieldSize:    8,
+		FieldSizeLimits: map[string]int{KeyHttpResponseBody: 4, "dsl": 0},
+	})
+
+	v, ok := limiter.truncate("short", "abc")
+	assert.False(t, ok)
+	assert.Equal(t, "abc", v)
+
+	v, ok = limiter.truncate("long", "0123456789")
+	assert.True(t, ok)
+	assert.Equal(t, "01234567...[truncated 2 bytes]", v)
+
+	v, ok = limiter.truncate(KeyHttpResponseBody, "0123456789")
+	assert.True(t, ok)
+	assert.Equal(t, "0123...[truncated 6 bytes]", v)
+
+	// 字段级配置为 0 表示不限制
+	_, ok = limiter.truncate("dsl", strings.Repeat("x", 100))
+	assert.False(t, ok)
+
+	// 不切断多字节字符
+	v, ok = limiter.truncate("cn", "中文字符串")
+	assert.True(t, ok)
+	assert.Equal(t, "中文...[truncated 9 bytes]", v)
+
+	v, ok = limiter.truncateAny("obj", map[string]string{"key": "value"})
+	assert.True(t, ok)
+	assert.True(t, strings.HasPrefix(v, `{"key":"`))
+
+	v, ok = limiter.truncateAny("err", errors.New("something went wrong"))
+	assert.True(t, ok)
+	assert.Equal(t, "somethin...[truncated 12 bytes]", v)
+
+	assert.Nil(t, newFieldSizeLimiter(&LogConfig{}))
+}
+
+func TestZapEncoderTruncateFields(t *testing.T) {
+	encoder := getZapEncoder(&zapLoggerConfig{
+		sizeLimiter: newFieldSizeLimiter(&LogConfig{MaxFieldSize: 16}),
+	})
+	encoder.AddString("bound", strings.Repeat("b", 20))
+
+	fields := []zapcore.Field{
+		zap.String("body", strings.Repeat("a", 20)),
+		zap.Any("payload", map[string]string{"key": strings.Repeat("c", 20)}),
+		zap.Int("count", 1),
+	}
+	buf, err := encoder.EncodeEntry(zapcore.Entry{Message: "msg"}, fields)
+	assert.Nil(t, err)
+	content := buf.String()
+
+	assert.Contains(t, content, `"bound":"bbbbbbbbbbbbbbbb...[truncated 4 bytes]"`)
+	assert.Contains(t, content, `"body":"aaaaaaaaaaaaaaaa...[truncated 4 bytes]"`)
+	assert.Contains(t, content, `"payload":"{\"key\":\"cccccccc...[truncated 14 bytes]"`)
+	assert.Contains(t, content, `"count":1`)
+	// 调用方的字段不被修改
+	assert.Equal(t, strings.Repeat("a", 20), fields[0].String)
+}
+
+func TestSlogHandlerTruncateFields(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := &LogConfig{
+		Level:           InfoLevel,
+		MaxFieldSize:    16,
+		FieldSizeLimits: map[string]int{"dsl": 4},
+	}
+	logger := slog.New(newSlogHandler(cfg, nil, &buf, EncodingJSON, false, nil)).With("bound", strings.Repeat("b", 20))
+	logger.InfoContext(context.Background(), strings.Repeat("m", 20),
+		"body", strings.Repeat("a", 20),
+		"dsl", `{"query":{}}`,
+		"count", 1,
+	)
+	content := buf.String()
+
+	assert.Contains(t, content, `"msg":"mmmmmmmmmmmmmmmmmmmm"`)
+	assert.Contains(t, content, `"bound":"bbbbbbbbbbbbbbbb...[truncated 4 bytes]"`)
+	assert.Contains(t, content, `"body":"aaaaaaaaaaaaaaaa...[truncated 4 bytes]"`)
+	assert.Contains(t, content, `"dsl":"{\"qu...[truncated 8 bytes]"`)
+	assert.Contains(t, content, `"count":1`)
+}
+
+// verboseError 模拟 pkg/errors 等实现 fmt.Formatter 的错误，%+v 输出调用栈
+type verboseError struct{ msg string }
+
+func (e verboseError) Error() string { return e.msg }
+
+func (e verboseError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%s\nstack: main.go:10", e.msg)
+		return
+	}
+	fmt.Fprint(s, e.msg)
+}
+
+func TestZapEncoderTruncateError(t *testing.T) {
+	encoder := getZapEncoder(&zapLoggerConfig{
+		sizeLimiter: newFieldSizeLimiter(&LogConfig{MaxFieldSize: 32}),
+	})
+
+	// 未超长时保留错误字段，包括 errorVerbose
+	buf, err := encoder.EncodeEntry(zapcore.Entry{Message: "msg"}, []zapcore.Field{zap.Error(verboseError{msg: "short"})})
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"error":"short"`)
+	assert.Contains(t, buf.String(), `"errorVerbose":"short\nstack: main.go:10"`)
+
+	// 超长时错误信息和详细信息都截断
+	buf, err = encoder.EncodeEntry(zapcore.Entry{Message: "msg"}, []zapcore.Field{zap.Error(verboseError{msg: strings.Repeat("e", 40)})})
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"error":"`+strings.Repeat("e", 32)+`...[truncated 8 bytes]"`)
+	assert.Contains(t, buf.String(), `"errorVerbose":"`+strings.Repeat("e", 32)+`...[truncated 26 bytes]"`)
+
+	buf, err = encoder.EncodeEntry(zapcore.Entry{Message: "msg"}, []zapcore.Field{zap.Error(errors.New(strings.Repeat("e", 40)))})
+	assert.Nil(t, err)
+	assert.NotContains(t, buf.String(), "errorVerbose")
+}
+
+func TestMaxJSONSize(t *testing.T) {
+	type point struct {
+		X, Y int64
+		Ok   bool `json:"ok"`
+	}
+	type node struct {
+		Value int
+		Next  *node
+	}
+	assert.Equal(t, -1, maxJSONSize(reflect.TypeOf("")))
+	assert.Equal(t, -1, maxJSONSize(reflect.TypeOf([]int{})))
+	assert.Equal(t, -1, maxJSONSize(reflect.TypeOf(map[string]int{})))
+	assert.Equal(t, -1, maxJSONSize(reflect.TypeOf(node{})))
+	assert.Equal(t, -1, maxJSONSize(reflect.TypeOf(time.Time{})))
+
+	p := point{X: math.MinInt64, Y: math.MinInt64, Ok: false}
+	b, _ := json.Marshal(&p)
+	size := maxJSONSize(reflect.TypeOf(&p))
+	assert.GreaterOrEqual(t, size, len(b))
+	b, _ = json.Marshal([3]float64{-math.MaxFloat64, math.SmallestNonzeroFloat64, 1})
+	assert.GreaterOrEqual(t, maxJSONSize(reflect.TypeOf([3]float64{})), len(b))
+
+	// 序列化结果不超过限制的类型不截断，可能超过时仍按实际大小截断
+	limiter := newFieldSizeLimiter(&LogConfig{MaxFieldSize: size})
+	_, ok := limiter.truncateAny("point", p)
+	assert.False(t, ok)
+	limiter = newFieldSizeLimiter(&LogConfig{MaxFieldSize: 16})
+	v, ok := limiter.truncateAny("point", p)
+	assert.True(t, ok)
+	assert.True(t, strings.HasPrefix(v, `{"X":-9223372036`))
+}
@@ -2,11 +2,11 @@ package glog
 
 import (
 	"context"
-	"encoding/json"
 	"os"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/morehao/golib/gutil"
 )
 
 func GenRequestID() string {
@@ -49,7 +49,9 @@ func skipLog(ctx context.Context) bool {
 	return ctx.Value(KeySkipLog) != nil
 }
 
+// ToJsonString 序列化为 JSON 字符串，失败时返回空字符串
+//
+// Deprecated: 使用 gutil.ToJsonString，工具函数统一放在 gutil 包中，本函数将在后续版本移除
 func ToJsonString(v any) string {
-	b, _ := json.Marshal(v)
-	return string(b)
+	return gutil.ToJsonString(v)
 }
@@ -17,13 +17,19 @@ type zapLogger struct {
 	cfg             *LogConfig
 	enableOTELTrace bool
 	fieldHookFunc   FieldHookFunc
+	metricsHook     MetricsHook
+	callerSkip      int // logger 上生效的 caller skip，Unwrap 时抵消
 }
 
 type zapLoggerConfig struct {
-	callerSkip      int
-	fieldHookFunc   FieldHookFunc
-	messageHookFunc MessageHookFunc
-	enableOTELTrace bool
+	callerSkip         int
+	fieldHookFunc      FieldHookFunc
+	messageHookFunc    MessageHookFunc
+	enableOTELTrace    bool
+	redactor           *redactor
+	sizeLimiter        *fieldSizeLimiter
+	consoleSizeLimiter *fieldSizeLimiter // console 编码使用的字段大小限制
+	encoder            *encoderSettings  // JSON 编码配置，为 nil 时使用默认值
 }
 
 // newZapLogger 初始化 zapLogger。
@@ -46,55 +52,116 @@ func newZapLogger(cfg *LogConfig, opts ...Option) (Logger, error) {
 		enableOTELTrace = *optCfg.enableOTELTrace
 	}
 
+	callerSkip := defaultLogCallerSkip
+	if optCfg.callerSkip > 0 {
+		callerSkip = optCfg.callerSkip
+	}
+
 	return &zapLogger{
 		logger:          logger,
 		cfg:             cfg,
 		enableOTELTrace: enableOTELTrace,
 		fieldHookFunc:   optCfg.fieldHookFunc,
+		metricsHook:     optCfg.metricsHook,
+		callerSkip:      callerSkip,
 	}, nil
 }
 
 func getZapLogger(cfg *LogConfig, optCfg *optConfig) (*zap.Logger, error) {
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	encoderSettings, err := newEncoderSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cfg.stacktraceLevel(); err != nil {
+		return nil, err
+	}
 	zapCfg := &zapLoggerConfig{
-		callerSkip:      optCfg.callerSkip,
-		fieldHookFunc:   optCfg.fieldHookFunc,
-		messageHookFunc: optCfg.messageHookFunc,
-		enableOTELTrace: cfg.EnableOTELTrace,
+		callerSkip:         optCfg.callerSkip,
+		fieldHookFunc:      optCfg.fieldHookFunc,
+		messageHookFunc:    optCfg.messageHookFunc,
+		enableOTELTrace:    cfg.EnableOTELTrace,
+		redactor:           redactor,
+		sizeLimiter:        newFieldSizeLimiter(cfg),
+		consoleSizeLimiter: newConsoleFieldSizeLimiter(cfg),
+		encoder:            encoderSettings,
 	}
 	if optCfg.enableOTELTrace != nil {
 		zapCfg.enableOTELTrace = *optCfg.enableOTELTrace
 	}
 
 	encoder := getZapEncoder(zapCfg)
+	if cfg.encoding() == EncodingConsole {
+		encoder = getZapConsoleEncoder(zapCfg, false)
+	}
+	consoleEncoder := getZapEncoder(zapCfg)
+	if cfg.consoleEncoding() == EncodingConsole {
+		consoleEncoder = getZapConsoleEncoder(zapCfg, true)
+	}
+	level := registerModuleLevel(cfg.Module, cfg.Level)
 
-	consoleCore := zapcore.NewCore(
-		encoder,
-		getZapStandoutWriter(),
-		logLevelMap[cfg.Level],
-	)
+	consoleCore := newCtxLevelCore(consoleEncoder, getZapStandoutWriter(), level, "")
 
 	var cores []zapcore.Core
 
-	switch cfg.Writer {
-	case WriterConsole:
+	switch {
+	case len(cfg.Outputs) > 0:
+		outputCores, err := getZapOutputCores(cfg, zapCfg, optCfg, level)
+		if err != nil {
+			return nil, err
+		}
+		cores = outputCores
+	case cfg.Writer == WriterConsole:
 		cores = append(cores, consoleCore)
-	case WriterFile:
+	case cfg.Writer == WriterFile:
 		defaultWriter, err := getZapFileWriter(cfg, "full")
 		if err != nil {
 			return nil, err
 		}
-		wfWriter, err := getZapFileWriter(cfg, "wf")
-		if err != nil {
+		if defaultWriter, err = wrapZapAsync(cfg, defaultWriter); err != nil {
 			return nil, err
 		}
-		defaultCore := zapcore.NewCore(encoder, defaultWriter, logLevelMap[cfg.Level])
-		wfCore := zapcore.NewCore(encoder, wfWriter, zapcore.WarnLevel)
+		defaultCore := newCtxLevelCore(encoder, defaultWriter, level, "")
 		// 保持原有行为：file 模式同时输出到 console
-		cores = append(cores, consoleCore, defaultCore, wfCore)
+		cores = append(cores, consoleCore, defaultCore)
+
+		wfSuffix, wfLevel, wfEnabled, err := cfg.wfFile()
+		if err != nil {
+			return nil, err
+		}
+		if wfEnabled {
+			wfWriter, err := getZapFileWriter(cfg, wfSuffix)
+			if err != nil {
+				return nil, err
+			}
+			if wfWriter, err = wrapZapAsync(cfg, wfWriter); err != nil {
+				return nil, err
+			}
+			cores = append(cores, zapcore.NewCore(encoder, wfWriter, levelToZapLevel(wfLevel)))
+		}
+	case cfg.Writer == WriterCustom:
+		sinks, err := resolveSinks(cfg, optCfg)
+		if err != nil {
+			return nil, err
+		}
+		for _, sink := range sinks {
+			writer, err := wrapZapAsync(cfg, sink)
+			if err != nil {
+				return nil, err
+			}
+			cores = append(cores, newCtxLevelCore(encoder, writer, level, ""))
+		}
+	}
+
+	if cfg.CrashDump != nil {
+		cores = append(cores, newCrashCore(getZapEncoder(zapCfg), registerCrashRing(cfg)))
 	}
 
 	core := zapcore.NewTee(cores...)
-	logger := zap.New(core, zap.Development(), zap.AddCaller(), zap.AddStacktrace(zapcore.PanicLevel))
+	logger := zap.New(core, zap.Development(), zap.AddCaller(), zap.AddStacktrace(registerStacktraceLevel(cfg)))
 
 	serviceName := cfg.Service
 	if serviceName == "" {
@@ -105,6 +172,13 @@ func getZapLogger(cfg *LogConfig, optCfg *optConfig) (*zap.Logger, error) {
 		moduleName = defaultModuleName
 	}
 	logger = logger.Named(serviceName).Named(moduleName)
+	if metadata := cfg.Metadata.staticFields(); len(metadata) > 0 {
+		fields := make([]zap.Field, 0, len(metadata))
+		for _, f := range metadata {
+			fields = append(fields, zap.Any(f.Key, f.Value))
+		}
+		logger = logger.With(fields...)
+	}
 
 	callerSkip := defaultLogCallerSkip
 	if optCfg.callerSkip > 0 {
@@ -172,23 +246,57 @@ func (l *zapLogger) With(kvs ...any) Logger {
 		cfg:             l.cfg,                    // 共享配置，不复制
 		enableOTELTrace: l.enableOTELTrace,
 		fieldHookFunc:   l.fieldHookFunc, // 共享 hook
+		metricsHook:     l.metricsHook,
+		callerSkip:      l.callerSkip,
 	}
 }
 
+func (l *zapLogger) Sync() error { return l.logger.Sync() }
+
 func (l *zapLogger) Close() error { return l.logger.Sync() }
 
+func (l *zapLogger) Unwrap() *zap.Logger {
+	return l.logger.WithOptions(zap.AddCallerSkip(-l.callerSkip))
+}
+
+// addCallerSkip 返回额外跳过 skip 层调用栈的 logger，用于适配器将 caller 定位到第三方库的调用处
+func (l *zapLogger) addCallerSkip(skip int) Logger {
+	next := *l
+	next.logger = l.logger.WithOptions(zap.AddCallerSkip(skip))
+	next.callerSkip = l.callerSkip + skip
+	return &next
+}
+
 // ---------------------------------------------------------------------------
 // 内部核心：dispatch 统一处理前置检查
 // ---------------------------------------------------------------------------
+func (l *zapLogger) Debugt(ctx context.Context, tmpl string, args ...any) {
+	l.ctxLogt(DebugLevel, ctx, tmpl, args...)
+}
+func (l *zapLogger) Infot(ctx context.Context, tmpl string, args ...any) {
+	l.ctxLogt(InfoLevel, ctx, tmpl, args...)
+}
+func (l *zapLogger) Warnt(ctx context.Context, tmpl string, args ...any) {
+	l.ctxLogt(WarnLevel, ctx, tmpl, args...)
+}
+func (l *zapLogger) Errort(ctx context.Context, tmpl string, args ...any) {
+	l.ctxLogt(ErrorLevel, ctx, tmpl, args...)
+}
+func (l *zapLogger) Panict(ctx context.Context, tmpl string, args ...any) {
+	l.ctxLogt(PanicLevel, ctx, tmpl, args...)
+}
+func (l *zapLogger) Fatalt(ctx context.Context, tmpl string, args ...any) {
+	l.ctxLogt(FatalLevel, ctx, tmpl, args...)
+}
 
 // loggerWithCtx 将 ctx 动态字段附加到 logger 上。
 // 无额外字段时直接返回原 logger，避免不必要的 With 调用（With 内部是 copy-on-write）。
-func (l *zapLogger) loggerWithCtx(ctx context.Context) *zap.Logger {
+func (l *zapLogger) loggerWithCtx(log *zap.Logger, ctx context.Context) *zap.Logger {
 	fields := l.extraFields(ctx)
 	if len(fields) == 0 {
-		return l.logger
+		return log
 	}
-	return l.logger.With(fields...)
+	return log.With(fields...)
 }
 
 // dispatch 统一处理前置检查（nil ctx、skipLog、level 过滤），
@@ -197,21 +305,27 @@ func (l *zapLogger) dispatch(level Level, ctx context.Context, fn func(*zap.Logg
 	if nilCtx(ctx) || skipLog(ctx) {
 		return
 	}
+	log := l.logger
+	// ctx 上设置了请求级日志级别时放宽模块级别
+	if forced, ok := CtxLevel(ctx); ok {
+		log = withForcedLevel(log, forced)
+	}
 	// 先做 level 检查，避免 extraFields 的无效计算（对 Debug 在生产环境尤其重要）
-	if !l.logger.Core().Enabled(levelToZapLevel(level)) {
+	if !log.Core().Enabled(levelToZapLevel(level)) {
 		return
 	}
-	fn(l.loggerWithCtx(ctx))
+	fn(l.loggerWithCtx(log, ctx))
 }
 
 // ---------------------------------------------------------------------------
-// 三种调用风格的内部实现
+// 四种调用风格的内部实现
 // ---------------------------------------------------------------------------
 
 // ctxLog 对应 Info(ctx, args...) 风格：多个任意值拼接成消息字符串。
 // 与 Sugar.Info 行为一致：fmt.Sprint(args...)。
 func (l *zapLogger) ctxLog(level Level, ctx context.Context, args ...any) {
 	l.dispatch(level, ctx, func(log *zap.Logger) {
+		l.countEntry(ctx, level, nil)
 		logWithLevel(log, level, fmt.Sprint(args...))
 	})
 }
@@ -219,6 +333,7 @@ func (l *zapLogger) ctxLog(level Level, ctx context.Context, args ...any) {
 // ctxLogf 对应 Infof(ctx, format, args...) 风格：printf 格式化消息。
 func (l *zapLogger) ctxLogf(level Level, ctx context.Context, format string, args ...any) {
 	l.dispatch(level, ctx, func(log *zap.Logger) {
+		l.countEntry(ctx, level, nil)
 		logWithLevel(log, level, fmt.Sprintf(format, args...))
 	})
 }
@@ -227,6 +342,19 @@ func (l *zapLogger) ctxLogf(level Level, ctx context.Context, format string, arg
 // FieldHookFunc 在此处介入，操作的是 []zap.Field（完整类型信息），无原始实现的类型丢失问题。
 func (l *zapLogger) ctxLogw(level Level, ctx context.Context, msg string, kvs ...any) {
 	l.dispatch(level, ctx, func(log *zap.Logger) {
+		l.countEntry(ctx, level, kvs)
+		fields := sweetenFields(kvs)
+		fields = l.applyFieldHook(fields)
+		logWithLevel(log, level, msg, fields...)
+	})
+}
+
+// ctxLogt 对应 Infot(ctx, tmpl, args...) 风格：渲染消息模板，占位符绑定的参数同时输出为字段。
+// 模板在 level 检查通过后才渲染。
+func (l *zapLogger) ctxLogt(level Level, ctx context.Context, tmpl string, args ...any) {
+	l.dispatch(level, ctx, func(log *zap.Logger) {
+		msg, kvs := renderTemplate(tmpl, args)
+		l.countEntry(ctx, level, kvs)
 		fields := sweetenFields(kvs)
 		fields = l.applyFieldHook(fields)
 		logWithLevel(log, level, msg, fields...)
@@ -282,6 +410,14 @@ func sweetenFields(kvs []any) []zap.Field {
 	return fields
 }
 
+// countEntry 调用 MetricsHook 计数，在写日志前调用，保证 Panic/Fatal 日志也能计入
+func (l *zapLogger) countEntry(ctx context.Context, level Level, kvs []any) {
+	if l.metricsHook == nil {
+		return
+	}
+	l.metricsHook.OnEntry(newMetricsEntry(ctx, level, l.cfg.Module, kvs))
+}
+
 // applyFieldHook 对 []zap.Field 执行 FieldHookFunc。
 // 直接操作 zap.Field，类型信息完整，不存在原实现中强转 ReflectType 导致的类型丢失。
 // 注意：hook 只能修改已有字段的 Key/Value，不支持增减字段数量。
@@ -309,7 +445,7 @@ func (l *zapLogger) applyFieldHook(fields []zap.Field) []zap.Field {
 // Context 字段提取
 // ---------------------------------------------------------------------------
 
-// extraFields 从 ctx 中提取 OTEL trace 字段和自定义 ExtraKeys 字段。
+// extraFields 从 ctx 中提取 OTEL trace 字段、自定义 ExtraKeys 字段和 CtxWith 绑定的字段。
 // 直接返回 []zap.Field，不再经过 []any 中转，减少一次类型转换。
 func (l *zapLogger) extraFields(ctx context.Context) []zap.Field {
 	var fields []zap.Field
@@ -339,6 +475,21 @@ func (l *zapLogger) extraFields(ctx context.Context) []zap.Field {
 		}
 	}
 
+	// CtxWith 绑定的字段
+	for _, f := range CtxFields(ctx) {
+		fields = append(fields, zap.Any(f.Key, f.Value))
+	}
+
+	if l.cfg.RequestSequence {
+		if seq, ok := requestSequence(ctx); ok {
+			fields = append(fields, zap.Uint64(KeyAppLogSeq, seq))
+		}
+	}
+
+	if date, ok := l.cfg.Metadata.indexDate(); ok {
+		fields = append(fields, zap.String(KeyLogIndexDate, date))
+	}
+
 	return fields
 }
 
@@ -1,34 +1,34 @@
 package glog
 
 import (
-	"fmt"
 	"os"
-	"path"
-	"strings"
-	"sync"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // ---------------------------------------------------------------------------
 // Encoder
 // ---------------------------------------------------------------------------
 
-// gZapEncoder 只负责 messageHook，fieldHook 已上移到 ctxLogw 层处理。
+// gZapEncoder 负责 messageHook、脱敏和字段大小限制，fieldHook 已上移到 ctxLogw 层处理。
 type gZapEncoder struct {
 	zapcore.Encoder
 	messageHookFunc MessageHookFunc
+	redactor        *redactor
+	sizeLimiter     *fieldSizeLimiter
 }
 
 func getZapEncoder(cfg *zapLoggerConfig) zapcore.Encoder {
 	encoderCfg := zap.NewProductionEncoderConfig()
 	encoderCfg.NameKey = "module"
 	encoderCfg.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString(t.Format("2006-01-02 15:04:05.000000"))
+		enc.AppendString(t.Format(defaultTimeLayout))
+	}
+	if cfg != nil {
+		cfg.encoder.applyZap(&encoderCfg)
 	}
 
 	encoder := zapcore.NewJSONEncoder(encoderCfg)
@@ -37,6 +37,8 @@ func getZapEncoder(cfg *zapLoggerConfig) zapcore.Encoder {
 	}
 	if cfg != nil {
 		customEncoder.messageHookFunc = cfg.messageHookFunc
+		customEncoder.redactor = cfg.redactor
+		customEncoder.sizeLimiter = cfg.sizeLimiter
 	}
 	return customEncoder
 }
@@ -45,6 +47,8 @@ func (enc *gZapEncoder) Clone() zapcore.Encoder {
 	return &gZapEncoder{
 		Encoder:         enc.Encoder.Clone(),
 		messageHookFunc: enc.messageHookFunc,
+		redactor:        enc.redactor,
+		sizeLimiter:     enc.sizeLimiter,
 	}
 }
 
@@ -52,123 +56,76 @@ func (enc *gZapEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (
 	if enc.messageHookFunc != nil {
 		ent.Message = enc.messageHookFunc(ent.Message)
 	}
+	ent.Message = enc.redactor.redactMessage(ent.Message)
+	fields = enc.redactor.redactZapFields(fields)
+	fields = enc.sizeLimiter.truncateZapFields(fields)
 	return enc.Encoder.EncodeEntry(ent, fields)
 }
 
-// ---------------------------------------------------------------------------
-// Console writer
-// ---------------------------------------------------------------------------
-
-func getZapStandoutWriter() zapcore.WriteSyncer {
-	return os.Stdout
-}
-
-// ---------------------------------------------------------------------------
-// Daily-rotate file writer
-// ---------------------------------------------------------------------------
-
-// dailyRotateWriter 在每次 Write 时检测日期，跨天后自动切换到新目录/文件。
-// 同时内置 256KB 缓冲，每 5 秒强制刷盘，与原实现保持一致。
-type dailyRotateWriter struct {
-	mu         sync.Mutex
-	cfg        *LogConfig
-	fileSuffix string // "full" or "wf"
-
-	// 当前活跃的 lumberjack logger 及其对应的日期字符串
-	current *lumberjack.Logger
-	today   string
-
-	// 带缓冲的包装层，跨天时需要一并替换
-	buffered *zapcore.BufferedWriteSyncer
-}
-
-func newDailyRotateWriter(cfg *LogConfig, fileSuffix string) (*dailyRotateWriter, error) {
-	w := &dailyRotateWriter{
-		cfg:        cfg,
-		fileSuffix: fileSuffix,
+// AddString、AddByteString、AddReflected 覆盖 With 绑定字段的写入路径，保证固定字段同样脱敏并受大小限制。
+func (enc *gZapEncoder) AddString(key, value string) {
+	if redacted, ok := enc.redactor.redactString(key, value); ok {
+		value = redacted
 	}
-	// 初始化当天的 writer
-	if err := w.rotate(time.Now().Format("20060102")); err != nil {
-		return nil, err
+	if truncated, ok := enc.sizeLimiter.truncate(key, value); ok {
+		value = truncated
 	}
-	return w, nil
+	enc.Encoder.AddString(key, value)
 }
 
-// rotate 切换到新的日期目录，必须在持有 mu 或初始化阶段调用。
-func (w *dailyRotateWriter) rotate(today string) error {
-	// 关闭旧的缓冲 writer（刷盘）
-	if w.buffered != nil {
-		_ = w.buffered.Stop()
+func (enc *gZapEncoder) AddByteString(key string, value []byte) {
+	if redacted, ok := enc.redactor.redactAny(key, value); ok {
+		enc.AddString(key, redacted)
+		return
 	}
-
-	dir := strings.TrimSuffix(w.cfg.Dir, "/") + "/" + today
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return fmt.Errorf("glog: mkdir %s: %w", dir, err)
+	if truncated, ok := enc.sizeLimiter.truncateAny(key, value); ok {
+		enc.Encoder.AddString(key, truncated)
+		return
 	}
+	enc.Encoder.AddByteString(key, value)
+}
 
-	logFilepath := path.Join(dir, fmt.Sprintf("%s_%s.log", w.cfg.Service, w.fileSuffix))
-
-	maxSize := w.cfg.MaxSize
-	if maxSize <= 0 {
-		maxSize = 100
+func (enc *gZapEncoder) AddReflected(key string, value interface{}) error {
+	if redacted, ok := enc.redactor.redactAny(key, value); ok {
+		enc.AddString(key, redacted)
+		return nil
 	}
-	maxBackups := w.cfg.MaxBackups
-	if maxBackups <= 0 {
-		maxBackups = 10
-	}
-	maxAge := w.cfg.MaxAge
-	if maxAge <= 0 {
-		maxAge = 7
+	if truncated, ok := enc.sizeLimiter.truncateAny(key, value); ok {
+		enc.Encoder.AddString(key, truncated)
+		return nil
 	}
+	return enc.Encoder.AddReflected(key, value)
+}
 
-	lj := &lumberjack.Logger{
-		Filename:   logFilepath,
-		MaxSize:    maxSize,
-		MaxBackups: maxBackups,
-		MaxAge:     maxAge,
-		Compress:   w.cfg.Compress,
-		LocalTime:  true,
-	}
+// ---------------------------------------------------------------------------
+// Console writer
+// ---------------------------------------------------------------------------
 
-	w.current = lj
-	w.today = today
-	w.buffered = &zapcore.BufferedWriteSyncer{
-		WS:            zapcore.AddSync(lj),
-		Size:          256 * 1024,
-		FlushInterval: 5 * time.Second,
-	}
-	return nil
+func getZapStandoutWriter() zapcore.WriteSyncer {
+	return os.Stdout
 }
 
-// Write 实现 io.Writer，跨天时自动切换。
-func (w *dailyRotateWriter) Write(p []byte) (n int, err error) {
-	today := time.Now().Format("20060102")
+// ---------------------------------------------------------------------------
+// Rotate file writer
+// ---------------------------------------------------------------------------
 
-	w.mu.Lock()
-	if today != w.today {
-		if rotateErr := w.rotate(today); rotateErr != nil {
-			w.mu.Unlock()
-			return 0, rotateErr
-		}
+// getZapFileWriter 返回按时间和大小切割的 WriteSyncer，内置 256KB 缓冲，每 5 秒强制刷盘。
+func getZapFileWriter(cfg *LogConfig, fileSuffix string) (zapcore.WriteSyncer, error) {
+	rw, err := newRotateWriter(cfg, fileSuffix)
+	if err != nil {
+		return nil, err
 	}
-	buf := w.buffered
-	w.mu.Unlock()
-
-	return buf.Write(p)
+	return &zapcore.BufferedWriteSyncer{
+		WS:            rw,
+		Size:          256 * 1024,
+		FlushInterval: 5 * time.Second,
+	}, nil
 }
 
-// Sync 实现 zapcore.WriteSyncer。
-func (w *dailyRotateWriter) Sync() error {
-	w.mu.Lock()
-	buf := w.buffered
-	w.mu.Unlock()
-	if buf != nil {
-		return buf.Sync()
+// wrapZapAsync 配置了 Async 时将 writer 包装为异步 writer
+func wrapZapAsync(cfg *LogConfig, ws zapcore.WriteSyncer) (zapcore.WriteSyncer, error) {
+	if cfg.Async == nil {
+		return ws, nil
 	}
-	return nil
-}
-
-// getZapFileWriter 返回支持跨天切换的 WriteSyncer。
-func getZapFileWriter(cfg *LogConfig, fileSuffix string) (zapcore.WriteSyncer, error) {
-	return newDailyRotateWriter(cfg, fileSuffix)
+	return newAsyncWriter(ws, cfg.Async)
 }
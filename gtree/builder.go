@@ -51,10 +51,12 @@ const (
 type ErrorKind int
 
 const (
-	ErrDuplicateKey ErrorKind = iota // 重复的 key
-	ErrOrphanNode                    // 孤儿节点
-	ErrCyclicGraph                   // 存在循环引用
-	ErrContextDone                   // context 已取消
+	ErrDuplicateKey  ErrorKind = iota // 重复的 key
+	ErrOrphanNode                     // 孤儿节点
+	ErrCyclicGraph                    // 存在循环引用
+	ErrContextDone                    // context 已取消
+	ErrSelfParent                     // 父节点为自身
+	ErrDepthExceeded                  // 超过最大深度
 )
 
 func (e ErrorKind) String() string {
@@ -67,6 +69,10 @@ func (e ErrorKind) String() string {
 		return "cyclic graph"
 	case ErrContextDone:
 		return "context done"
+	case ErrSelfParent:
+		return "self parent"
+	case ErrDepthExceeded:
+		return "depth exceeded"
 	default:
 		return "unknown"
 	}
@@ -74,10 +80,12 @@ func (e ErrorKind) String() string {
 
 // 哨兵错误，支持 errors.Is / errors.As 判断
 var (
-	ErrKindDuplicateKey = errors.New("duplicate key")
-	ErrKindOrphanNode   = errors.New("orphan node")
-	ErrKindCyclicGraph  = errors.New("cyclic graph")
-	ErrKindContextDone  = errors.New("context done")
+	ErrKindDuplicateKey  = errors.New("duplicate key")
+	ErrKindOrphanNode    = errors.New("orphan node")
+	ErrKindCyclicGraph   = errors.New("cyclic graph")
+	ErrKindContextDone   = errors.New("context done")
+	ErrKindSelfParent    = errors.New("self parent")
+	ErrKindDepthExceeded = errors.New("depth exceeded")
 )
 
 func sentinelFor(k ErrorKind) error {
@@ -90,6 +98,10 @@ func sentinelFor(k ErrorKind) error {
 		return ErrKindCyclicGraph
 	case ErrContextDone:
 		return ErrKindContextDone
+	case ErrSelfParent:
+		return ErrKindSelfParent
+	case ErrDepthExceeded:
+		return ErrKindDepthExceeded
 	default:
 		return fmt.Errorf("unknown error kind %d", k)
 	}
@@ -20,12 +20,13 @@ type testNode struct {
 	sortOrder int
 }
 
-func (n *testNode) GetKey() int       { return n.nodeKey }
-func (n *testNode) GetParentKey() int { return n.parentKey }
-func (n *testNode) IsRoot() bool      { return n.isRoot }
-func (n *testNode) GetID() uint       { return n.sortID }
-func (n *testNode) GetName() string   { return n.sortName }
-func (n *testNode) GetOrder() int     { return n.sortOrder }
+func (n *testNode) GetKey() int        { return n.nodeKey }
+func (n *testNode) GetParentKey() int  { return n.parentKey }
+func (n *testNode) IsRoot() bool       { return n.isRoot }
+func (n *testNode) GetID() uint        { return n.sortID }
+func (n *testNode) GetName() string    { return n.sortName }
+func (n *testNode) GetOrder() int      { return n.sortOrder }
+func (n *testNode) SetOrder(order int) { n.sortOrder = order }
 
 func node(key, parent int, root bool) *testNode {
 	return &testNode{
@@ -0,0 +1,45 @@
+package gtree
+
+import (
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// DBScope 查询作用域，同 gorm Scopes 的参数，如追加租户条件、限定状态
+type DBScope = func(db *gorm.DB) *gorm.DB
+
+var dbScopes struct {
+	sync.RWMutex
+	scopes []DBScope
+}
+
+// RegisterDBScope 注册全局查询作用域，BuildFromDB 每次查询时按注册顺序应用，
+// 用于统一追加租户等过滤条件，应在初始化阶段调用
+func RegisterDBScope(scopes ...DBScope) {
+	dbScopes.Lock()
+	defer dbScopes.Unlock()
+	dbScopes.scopes = append(dbScopes.scopes, scopes...)
+}
+
+// BuildFromDB 查询节点并构建树，省去业务中先 Find 再 Build 的重复代码。
+// query 用于追加查询条件、排序，或通过 Select、Preload 加载额外的列和关联，可为 nil，在全局作用域之后应用；
+// 查询使用 WithContext 传入的 context，便于租户插件等从 context 中取值。
+// N 须为结构体指针，gorm 按其指向的结构体解析表名；未指定比较器时同级节点保持查询结果的顺序
+func BuildFromDB[K comparable, N TreeNode[K]](db *gorm.DB, query DBScope, opts ...Option[K, N]) (*Tree[K, N], error) {
+	builder := NewTreeBuilder(opts...)
+
+	dbScopes.RLock()
+	scopes := make([]DBScope, 0, len(dbScopes.scopes)+1)
+	scopes = append(scopes, dbScopes.scopes...)
+	dbScopes.RUnlock()
+	if query != nil {
+		scopes = append(scopes, query)
+	}
+
+	var nodes []N
+	if err := db.WithContext(builder.ctx).Scopes(scopes...).Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+	return builder.Build(nodes), nil
+}
@@ -0,0 +1,65 @@
+package gtree
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type deptNode struct {
+	ID       uint `gorm:"primaryKey"`
+	ParentID uint
+	TenantID uint
+	Name     string
+	Sort     int
+	Leader   string `gorm:"-:migration;->"` // 只读的额外列，通过 Select 加载
+}
+
+func (d *deptNode) GetKey() uint       { return d.ID }
+func (d *deptNode) GetParentKey() uint { return d.ParentID }
+func (d *deptNode) IsRoot() bool       { return d.ParentID == 0 }
+
+type tenantCtxKey struct{}
+
+func TestBuildFromDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.Nil(t, err)
+	require.Nil(t, db.AutoMigrate(&deptNode{}))
+	require.Nil(t, db.Create([]*deptNode{
+		{ID: 1, TenantID: 1, Name: "root", Sort: 1},
+		{ID: 2, ParentID: 1, TenantID: 1, Name: "b", Sort: 2},
+		{ID: 3, ParentID: 1, TenantID: 1, Name: "a", Sort: 1},
+		{ID: 4, TenantID: 2, Name: "other"},
+	}).Error)
+
+	t.Cleanup(func() { dbScopes.scopes = nil })
+	RegisterDBScope(func(db *gorm.DB) *gorm.DB {
+		tenantID, _ := db.Statement.Context.Value(tenantCtxKey{}).(uint)
+		return db.Where("tenant_id = ?", tenantID)
+	})
+
+	ctx := context.WithValue(context.Background(), tenantCtxKey{}, uint(1))
+	tree, err := BuildFromDB(db, func(db *gorm.DB) *gorm.DB {
+		return db.Select("*, upper(name) AS leader").Order("sort")
+	}, WithContext[uint, *deptNode](ctx))
+	require.Nil(t, err)
+	require.Empty(t, tree.BuildErrors)
+	require.Len(t, tree.Roots, 1)
+	assert.Equal(t, "ROOT", tree.Roots[0].Leader)
+	children, ok := tree.Children(1)
+	require.True(t, ok)
+	require.Len(t, children, 2)
+	assert.Equal(t, "a", children[0].Name)
+	assert.Equal(t, "b", children[1].Name)
+	assert.NotContains(t, tree.NodeMap, uint(4))
+
+	// 查询出错时返回错误
+	_, err = BuildFromDB[uint, *deptNode](db, func(db *gorm.DB) *gorm.DB {
+		return db.Where("missing_column = 1")
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,27 @@
+package gtree
+
+// OrderSetter 可写回排序值的节点，通常与 OrderComparator 使用的 GetOrder 成对实现
+type OrderSetter interface {
+	SetOrder(order int)
+}
+
+// AssignOrder 按树中当前的兄弟顺序（Build 排序后的顺序）为每组兄弟节点依次写回 step、2*step、3*step... 的排序值，
+// 根节点为一组。step <= 0 时按 1 处理，step > 1 时留出的间隔便于之后插入节点而不必重排。
+// 写回后遍历 NodeMap 即可一次性持久化计算出的顺序
+func AssignOrder[K comparable, N interface {
+	TreeNode[K]
+	OrderSetter
+}](tree *Tree[K, N], step int) {
+	if step <= 0 {
+		step = 1
+	}
+	assign := func(nodes []N) {
+		for i, node := range nodes {
+			node.SetOrder((i + 1) * step)
+		}
+	}
+	assign(tree.Roots)
+	for _, children := range tree.childrenMap {
+		assign(children)
+	}
+}
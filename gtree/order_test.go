@@ -0,0 +1,39 @@
+package gtree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignOrder(t *testing.T) {
+	nodes := []*testNode{
+		node(1, 0, true),
+		node(2, 1, false),
+		node(3, 1, false),
+		node(4, 2, false),
+		node(5, 2, false),
+		node(7, 0, true),
+	}
+	// 按名称倒序构建后写回排序值
+	tree := NewTreeBuilder[int, *testNode](WithComparator[int, *testNode](ComparatorFunc[*testNode](func(a, b *testNode) int {
+		return -NameComparator[*testNode, int]{}.Compare(a, b)
+	}))).Build(nodes)
+	AssignOrder(tree, 10)
+
+	got := make(map[int]int, len(tree.NodeMap))
+	for key, n := range tree.NodeMap {
+		got[key] = n.GetOrder()
+	}
+	assert.Equal(t, map[int]int{7: 10, 1: 20, 3: 10, 2: 20, 5: 10, 4: 20}, got)
+
+	// 按写回的排序值重新构建，顺序不变
+	rebuilt := NewTreeBuilder[int, *testNode](WithComparator[int, *testNode](OrderComparator[*testNode, int]{})).Build(nodes)
+	assert.Equal(t, keysOf(tree.Roots), keysOf(rebuilt.Roots))
+	children, _ := rebuilt.Children(2)
+	assert.Equal(t, []int{5, 4}, keysOf(children))
+
+	AssignOrder(tree, 0)
+	assert.Equal(t, 1, tree.NodeMap[7].GetOrder())
+	assert.Equal(t, 2, tree.NodeMap[4].GetOrder())
+}
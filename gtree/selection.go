@@ -0,0 +1,158 @@
+package gtree
+
+// =============================================================================
+// 勾选状态：用于权限分配等树形多选场景
+// =============================================================================
+
+// CheckState 节点勾选状态
+type CheckState int
+
+const (
+	Unchecked     CheckState = iota // 未选中
+	Indeterminate                   // 半选：部分后代被选中
+	Checked                         // 全选
+)
+
+func (s CheckState) String() string {
+	switch s {
+	case Unchecked:
+		return "unchecked"
+	case Indeterminate:
+		return "indeterminate"
+	case Checked:
+		return "checked"
+	default:
+		return "unknown"
+	}
+}
+
+// SelectionPolicy 选中集合的扩展策略
+type SelectionPolicy int
+
+const (
+	SelectExact       SelectionPolicy = iota // 不扩展，仅保留树中存在的 key
+	SelectDescendants                        // 向下扩展：选中节点的所有后代均视为选中
+	SelectAncestors                          // 向上扩展：选中节点的所有祖先均视为选中（如子菜单依赖父菜单）
+	SelectCascade                            // 级联：向下扩展后，子节点全部选中的父节点也视为选中
+)
+
+// CheckStates 计算树中所有节点的勾选状态。
+// 选中节点及其后代为 Checked；非叶子节点的子节点全部 Checked 时为 Checked，
+// 部分 Checked 或存在 Indeterminate 子节点时为 Indeterminate，否则为 Unchecked。
+// 不在树中的 key 会被忽略。
+func (t *Tree[K, N]) CheckStates(selected []K) map[K]CheckState {
+	selectedSet := make(map[K]bool, len(selected))
+	for _, key := range selected {
+		selectedSet[key] = true
+	}
+
+	// 前序遍历，父节点先于子节点访问，可一次完成向下扩展
+	order := make([]K, 0, len(t.NodeMap))
+	states := make(map[K]CheckState, len(t.NodeMap))
+	t.Walk(func(node N, _ int) bool {
+		key := node.GetKey()
+		order = append(order, key)
+		if selectedSet[key] {
+			states[key] = Checked
+		}
+		if states[key] == Checked {
+			for _, child := range t.childrenMap[key] {
+				states[child.GetKey()] = Checked
+			}
+		}
+		return true
+	})
+
+	// 逆前序即子节点先于父节点，自底向上汇总
+	for i := len(order) - 1; i >= 0; i-- {
+		key := order[i]
+		children := t.childrenMap[key]
+		if states[key] == Checked || len(children) == 0 {
+			continue
+		}
+		checked, partial := 0, false
+		for _, child := range children {
+			switch states[child.GetKey()] {
+			case Checked:
+				checked++
+			case Indeterminate:
+				partial = true
+			}
+		}
+		switch {
+		case checked == len(children):
+			states[key] = Checked
+		case checked > 0 || partial:
+			states[key] = Indeterminate
+		}
+	}
+
+	result := make(map[K]CheckState, len(order))
+	for _, key := range order {
+		result[key] = states[key]
+	}
+	return result
+}
+
+// ExpandSelection 按策略扩展选中集合，结果按前序遍历顺序返回。
+// 不在树中的 key 会被忽略。
+func (t *Tree[K, N]) ExpandSelection(selected []K, policy SelectionPolicy) []K {
+	selectedSet := make(map[K]bool, len(selected))
+	for _, key := range selected {
+		if _, ok := t.NodeMap[key]; ok {
+			selectedSet[key] = true
+		}
+	}
+
+	switch policy {
+	case SelectDescendants:
+		t.Walk(func(node N, _ int) bool {
+			if selectedSet[node.GetKey()] {
+				for _, child := range t.childrenMap[node.GetKey()] {
+					selectedSet[child.GetKey()] = true
+				}
+			}
+			return true
+		})
+	case SelectAncestors:
+		parents := t.parentMap()
+		for key := range selectedSet {
+			for parent, ok := parents[key]; ok && !selectedSet[parent]; parent, ok = parents[parent] {
+				selectedSet[parent] = true
+			}
+		}
+	case SelectCascade:
+		for key, state := range t.CheckStates(selected) {
+			if state == Checked {
+				selectedSet[key] = true
+			}
+		}
+	case SelectExact:
+	}
+
+	return t.collectInOrder(selectedSet)
+}
+
+// parentMap 根据构建后的父子关系生成 childKey → parentKey 映射，
+// 已被移除的环边和被提升的孤儿节点不会出现在映射中。
+func (t *Tree[K, N]) parentMap() map[K]K {
+	parents := make(map[K]K, len(t.NodeMap))
+	for parentKey, children := range t.childrenMap {
+		for _, child := range children {
+			parents[child.GetKey()] = parentKey
+		}
+	}
+	return parents
+}
+
+// collectInOrder 按前序遍历顺序收集集合中的 key
+func (t *Tree[K, N]) collectInOrder(set map[K]bool) []K {
+	result := make([]K, 0, len(set))
+	t.Walk(func(node N, _ int) bool {
+		if set[node.GetKey()] {
+			result = append(result, node.GetKey())
+		}
+		return true
+	})
+	return result
+}
@@ -0,0 +1,60 @@
+package gtree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// selectionTree 构建测试树：
+//
+//	1
+//	├─ 2
+//	│  ├─ 4
+//	│  └─ 5
+//	└─ 3
+//	   └─ 6
+func selectionTree() *Tree[int, *testNode] {
+	nodes := []*testNode{
+		node(1, 0, true),
+		node(2, 1, false),
+		node(3, 1, false),
+		node(4, 2, false),
+		node(5, 2, false),
+		node(6, 3, false),
+	}
+	return NewTreeBuilder[int, *testNode]().Build(nodes)
+}
+
+func TestCheckStates(t *testing.T) {
+	tree := selectionTree()
+
+	states := tree.CheckStates([]int{4, 5})
+	assert.Equal(t, map[int]CheckState{
+		1: Indeterminate,
+		2: Checked,
+		3: Unchecked,
+		4: Checked,
+		5: Checked,
+		6: Unchecked,
+	}, states)
+
+	states = tree.CheckStates([]int{3, 4})
+	assert.Equal(t, Indeterminate, states[1])
+	assert.Equal(t, Indeterminate, states[2])
+	assert.Equal(t, Checked, states[6])
+
+	states = tree.CheckStates([]int{2, 6, 99})
+	assert.Equal(t, Checked, states[1])
+	assert.Len(t, states, 6)
+}
+
+func TestExpandSelection(t *testing.T) {
+	tree := selectionTree()
+
+	assert.Equal(t, []int{4, 6}, tree.ExpandSelection([]int{6, 4, 99}, SelectExact))
+	assert.Equal(t, []int{2, 4, 5}, tree.ExpandSelection([]int{2}, SelectDescendants))
+	assert.Equal(t, []int{1, 2, 4, 3, 6}, tree.ExpandSelection([]int{4, 6}, SelectAncestors))
+	assert.Equal(t, []int{2, 4, 5}, tree.ExpandSelection([]int{4, 5}, SelectCascade))
+	assert.Equal(t, []int{1, 2, 4, 5, 3, 6}, tree.ExpandSelection([]int{2, 6}, SelectCascade))
+}
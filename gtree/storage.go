@@ -0,0 +1,234 @@
+package gtree
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// 存储模型转换：邻接表、路径枚举、嵌套集与 Tree 互转
+// =============================================================================
+
+// ErrInvalidRecord 存储行不合法，如路径为空、左右值交叉等
+var ErrInvalidRecord = errors.New("invalid storage record")
+
+// AdjacencyRecord 邻接表存储行：记录父节点和同级排序，对应 parent_id、sort 列
+type AdjacencyRecord[K comparable] struct {
+	Key       K
+	ParentKey K // 根节点为零值
+	IsRoot    bool
+	Sort      int // 同级排序序号，从 0 开始
+}
+
+// PathRecord 路径枚举存储行：Path 为从根到该节点（含自身）的 key 序列，可通过 JoinPath 编码为 path 列
+type PathRecord[K comparable] struct {
+	Key   K
+	Path  []K
+	Depth int // 根节点为 0
+	Sort  int // 同级排序序号，从 0 开始
+}
+
+// NestedSetRecord 嵌套集存储行：后代的左右值位于祖先的左右值之间，对应 lft、rgt 列
+type NestedSetRecord[K comparable] struct {
+	Key   K
+	Left  int
+	Right int
+	Depth int // 根节点为 0
+}
+
+// recordFrame 存储行导出时的遍历帧
+type recordFrame[K comparable, N TreeNode[K]] struct {
+	node     N
+	path     []K // 从根到该节点（含自身）
+	sort     int
+	childIdx int
+}
+
+// walkRecords 前序遍历整棵树，进入节点时调用 enter，其全部后代处理完毕后调用 exit
+func (t *Tree[K, N]) walkRecords(enter func(f *recordFrame[K, N]), exit func(f *recordFrame[K, N])) {
+	visited := make(map[K]bool, len(t.NodeMap))
+	for i, root := range t.Roots {
+		rootKey := root.GetKey()
+		if visited[rootKey] {
+			continue
+		}
+		visited[rootKey] = true
+		stack := []*recordFrame[K, N]{{node: root, path: []K{rootKey}, sort: i}}
+		enter(stack[0])
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			children := t.childrenMap[top.node.GetKey()]
+			if top.childIdx >= len(children) {
+				exit(top)
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			child := children[top.childIdx]
+			sortIdx := top.childIdx
+			top.childIdx++
+			ck := child.GetKey()
+			if visited[ck] {
+				continue
+			}
+			visited[ck] = true
+			path := make([]K, len(top.path)+1)
+			copy(path, top.path)
+			path[len(top.path)] = ck
+			frame := &recordFrame[K, N]{node: child, path: path, sort: sortIdx}
+			enter(frame)
+			stack = append(stack, frame)
+		}
+	}
+}
+
+// ToAdjacency 按前序遍历导出邻接表存储行，Sort 为节点在同级中的位置
+func (t *Tree[K, N]) ToAdjacency() []AdjacencyRecord[K] {
+	records := make([]AdjacencyRecord[K], 0, len(t.NodeMap))
+	t.walkRecords(func(f *recordFrame[K, N]) {
+		record := AdjacencyRecord[K]{Key: f.node.GetKey(), IsRoot: len(f.path) == 1, Sort: f.sort}
+		if !record.IsRoot {
+			record.ParentKey = f.path[len(f.path)-2]
+		}
+		records = append(records, record)
+	}, func(*recordFrame[K, N]) {})
+	return records
+}
+
+// ToPaths 按前序遍历导出路径枚举存储行
+func (t *Tree[K, N]) ToPaths() []PathRecord[K] {
+	records := make([]PathRecord[K], 0, len(t.NodeMap))
+	t.walkRecords(func(f *recordFrame[K, N]) {
+		records = append(records, PathRecord[K]{Key: f.node.GetKey(), Path: f.path, Depth: len(f.path) - 1, Sort: f.sort})
+	}, func(*recordFrame[K, N]) {})
+	return records
+}
+
+// ToNestedSet 按前序遍历导出嵌套集存储行，左右值从 1 开始编号，多个根节点依次排列
+func (t *Tree[K, N]) ToNestedSet() []NestedSetRecord[K] {
+	records := make([]NestedSetRecord[K], 0, len(t.NodeMap))
+	index := make(map[K]int, len(t.NodeMap))
+	counter := 0
+	t.walkRecords(func(f *recordFrame[K, N]) {
+		counter++
+		key := f.node.GetKey()
+		index[key] = len(records)
+		records = append(records, NestedSetRecord[K]{Key: key, Left: counter, Depth: len(f.path) - 1})
+	}, func(f *recordFrame[K, N]) {
+		counter++
+		records[index[f.node.GetKey()]].Right = counter
+	})
+	return records
+}
+
+// AdjacencyFromPaths 将路径枚举存储行转换为邻接表存储行，父节点取路径中的倒数第二个 key
+func AdjacencyFromPaths[K comparable](records []PathRecord[K]) ([]AdjacencyRecord[K], error) {
+	result := make([]AdjacencyRecord[K], 0, len(records))
+	for _, record := range records {
+		if len(record.Path) == 0 || record.Path[len(record.Path)-1] != record.Key {
+			return nil, fmt.Errorf("%w: node=%v path=%v, path must end with the node key", ErrInvalidRecord, record.Key, record.Path)
+		}
+		adjacency := AdjacencyRecord[K]{Key: record.Key, IsRoot: len(record.Path) == 1, Sort: record.Sort}
+		if !adjacency.IsRoot {
+			adjacency.ParentKey = record.Path[len(record.Path)-2]
+		}
+		result = append(result, adjacency)
+	}
+	return result, nil
+}
+
+// AdjacencyFromNestedSet 将嵌套集存储行转换为邻接表存储行，按左值顺序确定父节点和同级排序
+func AdjacencyFromNestedSet[K comparable](records []NestedSetRecord[K]) ([]AdjacencyRecord[K], error) {
+	sorted := make([]NestedSetRecord[K], len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Left < sorted[j].Left })
+
+	result := make([]AdjacencyRecord[K], 0, len(sorted))
+	var (
+		stack     []NestedSetRecord[K] // 当前节点的祖先链
+		rootCount int
+	)
+	childCount := make(map[K]int)
+	for _, record := range sorted {
+		if record.Left >= record.Right {
+			return nil, fmt.Errorf("%w: node=%v left=%d right=%d, left must be less than right", ErrInvalidRecord, record.Key, record.Left, record.Right)
+		}
+		for len(stack) > 0 && stack[len(stack)-1].Right < record.Left {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			result = append(result, AdjacencyRecord[K]{Key: record.Key, IsRoot: true, Sort: rootCount})
+			rootCount++
+		} else {
+			parent := stack[len(stack)-1]
+			if record.Right > parent.Right {
+				return nil, fmt.Errorf("%w: node=%v [%d,%d] overlaps parent=%v [%d,%d]", ErrInvalidRecord, record.Key, record.Left, record.Right, parent.Key, parent.Left, parent.Right)
+			}
+			result = append(result, AdjacencyRecord[K]{Key: record.Key, ParentKey: parent.Key, Sort: childCount[parent.Key]})
+			childCount[parent.Key]++
+		}
+		stack = append(stack, record)
+	}
+	return result, nil
+}
+
+// FromAdjacency 由邻接表存储行构建树，newNode 根据存储行创建节点（如按 key 查出业务数据并设置父节点），
+// 未通过 opts 指定比较器时同级节点按 Sort 排序
+func FromAdjacency[K comparable, N TreeNode[K]](records []AdjacencyRecord[K], newNode func(AdjacencyRecord[K]) N, opts ...Option[K, N]) *Tree[K, N] {
+	sorted := make([]AdjacencyRecord[K], len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Sort < sorted[j].Sort })
+
+	nodes := make([]N, 0, len(sorted))
+	for _, record := range sorted {
+		nodes = append(nodes, newNode(record))
+	}
+	return NewTreeBuilder(opts...).Build(nodes)
+}
+
+// FromPaths 由路径枚举存储行构建树，newNode 和排序规则同 FromAdjacency
+func FromPaths[K comparable, N TreeNode[K]](records []PathRecord[K], newNode func(AdjacencyRecord[K]) N, opts ...Option[K, N]) (*Tree[K, N], error) {
+	adjacency, err := AdjacencyFromPaths(records)
+	if err != nil {
+		return nil, err
+	}
+	return FromAdjacency(adjacency, newNode, opts...), nil
+}
+
+// FromNestedSet 由嵌套集存储行构建树，newNode 和排序规则同 FromAdjacency
+func FromNestedSet[K comparable, N TreeNode[K]](records []NestedSetRecord[K], newNode func(AdjacencyRecord[K]) N, opts ...Option[K, N]) (*Tree[K, N], error) {
+	adjacency, err := AdjacencyFromNestedSet(records)
+	if err != nil {
+		return nil, err
+	}
+	return FromAdjacency(adjacency, newNode, opts...), nil
+}
+
+// JoinPath 将路径编码为 "{sep}k1{sep}k2{sep}" 形式，首尾带分隔符便于 LIKE '/1/3/%' 前缀查询后代
+func JoinPath[K comparable](path []K, sep string) string {
+	var sb strings.Builder
+	sb.WriteString(sep)
+	for _, key := range path {
+		sb.WriteString(fmt.Sprint(key))
+		sb.WriteString(sep)
+	}
+	return sb.String()
+}
+
+// SplitPath 解析 JoinPath 编码的路径，parse 将单个片段转换为 key，如 strconv.Atoi
+func SplitPath[K comparable](s, sep string, parse func(string) (K, error)) ([]K, error) {
+	parts := strings.Split(strings.Trim(s, sep), sep)
+	path := make([]K, 0, len(parts))
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		key, err := parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: path=%q: %v", ErrInvalidRecord, s, err)
+		}
+		path = append(path, key)
+	}
+	return path, nil
+}
@@ -0,0 +1,107 @@
+package gtree
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storageTree 构建测试树：
+//
+//	1
+//	├── 3
+//	│   └── 4
+//	└── 2
+//	5
+func storageTree() *Tree[int, *testNode] {
+	return NewTreeBuilder[int, *testNode]().Build([]*testNode{
+		node(1, 0, true),
+		node(3, 1, false),
+		node(2, 1, false),
+		node(4, 3, false),
+		node(5, 0, true),
+	})
+}
+
+func newStorageNode(r AdjacencyRecord[int]) *testNode {
+	return node(r.Key, r.ParentKey, r.IsRoot)
+}
+
+func TestStorageExport(t *testing.T) {
+	tree := storageTree()
+
+	assert.Equal(t, []AdjacencyRecord[int]{
+		{Key: 1, IsRoot: true, Sort: 0},
+		{Key: 3, ParentKey: 1, Sort: 0},
+		{Key: 4, ParentKey: 3, Sort: 0},
+		{Key: 2, ParentKey: 1, Sort: 1},
+		{Key: 5, IsRoot: true, Sort: 1},
+	}, tree.ToAdjacency())
+
+	assert.Equal(t, []PathRecord[int]{
+		{Key: 1, Path: []int{1}, Depth: 0, Sort: 0},
+		{Key: 3, Path: []int{1, 3}, Depth: 1, Sort: 0},
+		{Key: 4, Path: []int{1, 3, 4}, Depth: 2, Sort: 0},
+		{Key: 2, Path: []int{1, 2}, Depth: 1, Sort: 1},
+		{Key: 5, Path: []int{5}, Depth: 0, Sort: 1},
+	}, tree.ToPaths())
+
+	assert.Equal(t, []NestedSetRecord[int]{
+		{Key: 1, Left: 1, Right: 8, Depth: 0},
+		{Key: 3, Left: 2, Right: 5, Depth: 1},
+		{Key: 4, Left: 3, Right: 4, Depth: 2},
+		{Key: 2, Left: 6, Right: 7, Depth: 1},
+		{Key: 5, Left: 9, Right: 10, Depth: 0},
+	}, tree.ToNestedSet())
+}
+
+func TestStorageRoundTrip(t *testing.T) {
+	tree := storageTree()
+
+	// 打乱存储行顺序，重建后同级顺序仍由 Sort 或左值决定
+	adjacency := tree.ToAdjacency()
+	adjacency[0], adjacency[4] = adjacency[4], adjacency[0]
+	assert.Equal(t, storageTree().ToAdjacency(), FromAdjacency(adjacency, newStorageNode).ToAdjacency())
+
+	paths := tree.ToPaths()
+	paths[1], paths[3] = paths[3], paths[1]
+	fromPaths, err := FromPaths(paths, newStorageNode)
+	require.NoError(t, err)
+	assert.Equal(t, storageTree().ToAdjacency(), fromPaths.ToAdjacency())
+
+	nested := tree.ToNestedSet()
+	nested[0], nested[2] = nested[2], nested[0]
+	fromNested, err := FromNestedSet(nested, newStorageNode)
+	require.NoError(t, err)
+	assert.Equal(t, storageTree().ToAdjacency(), fromNested.ToAdjacency())
+}
+
+func TestStorageInvalidRecords(t *testing.T) {
+	_, err := AdjacencyFromPaths([]PathRecord[int]{{Key: 1, Path: []int{2}}})
+	assert.True(t, errors.Is(err, ErrInvalidRecord))
+
+	_, err = AdjacencyFromNestedSet([]NestedSetRecord[int]{{Key: 1, Left: 2, Right: 1}})
+	assert.True(t, errors.Is(err, ErrInvalidRecord))
+
+	// 左右值交叉
+	_, err = AdjacencyFromNestedSet([]NestedSetRecord[int]{
+		{Key: 1, Left: 1, Right: 4},
+		{Key: 2, Left: 3, Right: 6},
+	})
+	assert.True(t, errors.Is(err, ErrInvalidRecord))
+}
+
+func TestJoinSplitPath(t *testing.T) {
+	s := JoinPath([]int{1, 3, 4}, "/")
+	assert.Equal(t, "/1/3/4/", s)
+
+	path, err := SplitPath(s, "/", strconv.Atoi)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 4}, path)
+
+	_, err = SplitPath("/1/x/", "/", strconv.Atoi)
+	assert.True(t, errors.Is(err, ErrInvalidRecord))
+}
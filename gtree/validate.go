@@ -0,0 +1,182 @@
+package gtree
+
+import (
+	"fmt"
+	"sort"
+)
+
+// =============================================================================
+// 校验：不构建树，仅报告结构问题
+// =============================================================================
+
+// Issue 校验发现的一个结构问题
+type Issue[K comparable] struct {
+	Kind ErrorKind
+	// Index 问题节点在输入切片中的下标
+	Index     int
+	NodeKey   K
+	ParentKey K
+	// Path 环上的节点 key，从 NodeKey 开始沿父节点方向排列，仅 ErrCyclicGraph 时有值
+	Path []K
+	// Depth 节点深度（根节点为 0），仅 ErrDepthExceeded 时有值
+	Depth int
+	Err   error // 始终为对应的哨兵错误，支持 errors.Is
+}
+
+func (i Issue[K]) Error() string {
+	switch i.Kind {
+	case ErrCyclicGraph:
+		return fmt.Sprintf("[%s] index=%d node=%v path=%v: %v", i.Kind, i.Index, i.NodeKey, i.Path, i.Err)
+	case ErrDepthExceeded:
+		return fmt.Sprintf("[%s] index=%d node=%v depth=%d: %v", i.Kind, i.Index, i.NodeKey, i.Depth, i.Err)
+	default:
+		return fmt.Sprintf("[%s] index=%d node=%v parent=%v: %v", i.Kind, i.Index, i.NodeKey, i.ParentKey, i.Err)
+	}
+}
+
+func (i Issue[K]) Unwrap() error { return i.Err }
+
+// ValidateOption 校验选项
+type ValidateOption func(*validateConfig)
+
+type validateConfig struct {
+	maxDepth int
+}
+
+// WithMaxDepth 设置允许的最大深度（根节点为 0），深度超过 n 的节点报告 ErrDepthExceeded；n <= 0 时不限制，默认不限制
+func WithMaxDepth(n int) ValidateOption {
+	return func(cfg *validateConfig) { cfg.maxDepth = n }
+}
+
+// Validate 校验节点列表的结构，不构建树，返回按输入下标排序的问题列表，无问题时返回 nil。
+//
+// 检查项：
+//   - ErrDuplicateKey：重复的 key，保留先出现的节点，后续重复节点各报告一次
+//   - ErrSelfParent：非根节点的父节点为自身
+//   - ErrOrphanNode：非根节点的父节点不存在
+//   - ErrCyclicGraph：父节点链形成环，每个环报告一次，Path 为环上的全部节点
+//   - ErrDepthExceeded：配置 WithMaxDepth 时，可由根节点到达且深度超限的节点
+//
+// 位于环、孤儿或自引用节点之下的节点无法确定深度，不参与深度检查。
+func Validate[K comparable, N TreeNode[K]](nodes []N, opts ...ValidateOption) []Issue[K] {
+	cfg := &validateConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var issues []Issue[K]
+	newIssue := func(kind ErrorKind, index int, nodeKey, parentKey K) Issue[K] {
+		return Issue[K]{Kind: kind, Index: index, NodeKey: nodeKey, ParentKey: parentKey, Err: sentinelFor(kind)}
+	}
+
+	// 1. 建立索引，检测重复 key
+	indexMap := make(map[K]int, len(nodes))
+	for i, node := range nodes {
+		key := node.GetKey()
+		if _, exists := indexMap[key]; exists {
+			issues = append(issues, newIssue(ErrDuplicateKey, i, key, node.GetParentKey()))
+			continue
+		}
+		indexMap[key] = i
+	}
+
+	// 2. 检测自引用和孤儿节点，重复节点已报告，不再检查
+	for i, node := range nodes {
+		key := node.GetKey()
+		if indexMap[key] != i || node.IsRoot() {
+			continue
+		}
+		parentKey := node.GetParentKey()
+		if parentKey == key {
+			issues = append(issues, newIssue(ErrSelfParent, i, key, parentKey))
+		} else if _, exists := indexMap[parentKey]; !exists {
+			issues = append(issues, newIssue(ErrOrphanNode, i, key, parentKey))
+		}
+	}
+
+	// 3. 沿父节点链向上计算深度，同时检测环。
+	// 每个节点只有一个父节点，沿链回到当前路径上的节点即为环；
+	// 深度为 -1 表示无法到达根节点（环、孤儿或自引用）。
+	const (
+		stateUnvisited uint8 = 0
+		stateOnPath    uint8 = 1
+		stateDone      uint8 = 2
+	)
+	state := make(map[K]uint8, len(indexMap))
+	depth := make(map[K]int, len(indexMap))
+	pathPos := make(map[K]int)
+
+	for i, node := range nodes {
+		startKey := node.GetKey()
+		if indexMap[startKey] != i || state[startKey] != stateUnvisited {
+			continue
+		}
+
+		var path []K
+		base := -1 // path 末尾节点的父节点深度
+		cur := startKey
+		for {
+			if state[cur] == stateDone {
+				base = depth[cur]
+				break
+			}
+			if state[cur] == stateOnPath {
+				cycle := append([]K(nil), path[pathPos[cur]:]...)
+				parentKey := nodes[indexMap[cur]].GetParentKey()
+				e := newIssue(ErrCyclicGraph, indexMap[cur], cur, parentKey)
+				e.Path = cycle
+				issues = append(issues, e)
+				break
+			}
+
+			n := nodes[indexMap[cur]]
+			if n.IsRoot() {
+				depth[cur] = 0
+				state[cur] = stateDone
+				base = 0
+				break
+			}
+			state[cur] = stateOnPath
+			pathPos[cur] = len(path)
+			path = append(path, cur)
+
+			// 孤儿或自引用节点已在第 2 步报告，其本身及子孙均无法确定深度
+			parentKey := n.GetParentKey()
+			if _, exists := indexMap[parentKey]; !exists || parentKey == cur {
+				break
+			}
+			cur = parentKey
+		}
+
+		// 自顶向下回填深度，无法到达根节点时整条路径均为 -1
+		for j := len(path) - 1; j >= 0; j-- {
+			key := path[j]
+			if base >= 0 {
+				base++
+			}
+			depth[key] = base
+			state[key] = stateDone
+			delete(pathPos, key)
+		}
+	}
+
+	// 4. 深度检查
+	if cfg.maxDepth > 0 {
+		for i, node := range nodes {
+			key := node.GetKey()
+			if indexMap[key] != i {
+				continue
+			}
+			if d := depth[key]; d > cfg.maxDepth {
+				e := newIssue(ErrDepthExceeded, i, key, node.GetParentKey())
+				e.Depth = d
+				issues = append(issues, e)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool {
+		return issues[a].Index < issues[b].Index
+	})
+	return issues
+}
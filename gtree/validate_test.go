@@ -0,0 +1,74 @@
+package gtree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValidTree(t *testing.T) {
+	nodes := []*testNode{
+		node(1, 0, true),
+		node(2, 1, false),
+		node(3, 2, false),
+		node(4, 1, false),
+	}
+	assert.Nil(t, Validate[int](nodes))
+	assert.Nil(t, Validate[int](nodes, WithMaxDepth(2)))
+}
+
+func TestValidateIssues(t *testing.T) {
+	nodes := []*testNode{
+		node(1, 0, true),   // 0
+		node(2, 1, false),  // 1
+		node(2, 1, false),  // 2 重复
+		node(3, 99, false), // 3 孤儿
+		node(4, 4, false),  // 4 自引用
+		node(5, 6, false),  // 5 环 5 -> 6 -> 7 -> 5
+		node(6, 7, false),  // 6
+		node(7, 5, false),  // 7
+		node(8, 5, false),  // 8 挂在环下，不重复报告
+		node(9, 3, false),  // 9 挂在孤儿下
+	}
+	issues := Validate[int](nodes)
+
+	kinds := make([]ErrorKind, 0, len(issues))
+	for _, issue := range issues {
+		kinds = append(kinds, issue.Kind)
+	}
+	assert.Equal(t, []ErrorKind{ErrDuplicateKey, ErrOrphanNode, ErrSelfParent, ErrCyclicGraph}, kinds)
+
+	assert.Equal(t, 2, issues[0].Index)
+	assert.Equal(t, 3, issues[1].NodeKey)
+	assert.Equal(t, 99, issues[1].ParentKey)
+	assert.Equal(t, 4, issues[2].NodeKey)
+	assert.Equal(t, 5, issues[3].NodeKey)
+	assert.Equal(t, []int{5, 6, 7}, issues[3].Path)
+
+	assert.True(t, errors.Is(issues[3], ErrKindCyclicGraph))
+	assert.Contains(t, issues[3].Error(), "path=[5 6 7]")
+}
+
+func TestValidateMaxDepth(t *testing.T) {
+	nodes := []*testNode{
+		node(4, 3, false),
+		node(1, 0, true),
+		node(2, 1, false),
+		node(3, 2, false),
+		node(5, 9, false), // 孤儿，深度未知，不参与深度检查
+		node(6, 5, false),
+		node(7, 5, false),
+	}
+	issues := Validate[int](nodes, WithMaxDepth(1))
+	assert.Len(t, issues, 3)
+
+	assert.Equal(t, ErrDepthExceeded, issues[0].Kind)
+	assert.Equal(t, 4, issues[0].NodeKey)
+	assert.Equal(t, 3, issues[0].Depth)
+	assert.Equal(t, ErrDepthExceeded, issues[1].Kind)
+	assert.Equal(t, 3, issues[1].NodeKey)
+	assert.Equal(t, 2, issues[1].Depth)
+	assert.Equal(t, ErrOrphanNode, issues[2].Kind)
+	assert.True(t, errors.Is(issues[0], ErrKindDepthExceeded))
+}
@@ -0,0 +1,135 @@
+package gutil
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// LevenshteinDistance 计算两个字符串的编辑距离，按 rune 计算，中文字符计为 1
+func LevenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+	// 只保留上一行，空间复杂度 O(len(b))
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// LCSSimilarity 基于最长公共子序列的相似度，取值 [0, 1]，计算方式为 2*LCS/(len(a)+len(b))，两个空字符串相似度为 1
+func LCSSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra)+len(rb) == 0 {
+		return 1
+	}
+	return float64(2*lcsLength(ra, rb)) / float64(len(ra)+len(rb))
+}
+
+func lcsLength(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else {
+				curr[j] = max(prev[j], curr[j-1])
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// pinyinInitialBoundaries GB2312 一级汉字按拼音排序，各声母首字的 GBK 编码，无 i、u、v 开头的拼音
+var pinyinInitialBoundaries = []struct {
+	code    int
+	initial byte
+}{
+	{0xB0A1, 'a'}, {0xB0C5, 'b'}, {0xB2C1, 'c'}, {0xB4EE, 'd'}, {0xB6EA, 'e'}, {0xB7A2, 'f'},
+	{0xB8C1, 'g'}, {0xB9FE, 'h'}, {0xBBF7, 'j'}, {0xBFA6, 'k'}, {0xC0AC, 'l'}, {0xC2E8, 'm'},
+	{0xC4C3, 'n'}, {0xC5B6, 'o'}, {0xC5BE, 'p'}, {0xC6DA, 'q'}, {0xC8BB, 'r'}, {0xC8F6, 's'},
+	{0xCBFA, 't'}, {0xCDDA, 'w'}, {0xCEF4, 'x'}, {0xD1B9, 'y'}, {0xD4D1, 'z'},
+}
+
+// pinyinLevel1End GB2312 一级汉字的最后一个编码，之后的二级汉字按部首排序，无法通过编码区间确定拼音
+const pinyinLevel1End = 0xD7F9
+
+// PinyinInitial 返回汉字拼音的首字母（小写），仅支持 GB2312 一级汉字（3755 个常用字），
+// 多音字按 GB2312 中的读音，不支持的字符返回 false
+func PinyinInitial(r rune) (byte, bool) {
+	encoded, err := simplifiedchinese.GBK.NewEncoder().String(string(r))
+	if err != nil || len(encoded) != 2 {
+		return 0, false
+	}
+	code := int(encoded[0])<<8 | int(encoded[1])
+	if code < pinyinInitialBoundaries[0].code || code > pinyinLevel1End {
+		return 0, false
+	}
+	initial := pinyinInitialBoundaries[0].initial
+	for _, boundary := range pinyinInitialBoundaries {
+		if code < boundary.code {
+			break
+		}
+		initial = boundary.initial
+	}
+	return initial, true
+}
+
+// PinyinInitials 将字符串中的汉字转为拼音首字母，如 "用户管理" 转为 "yhgl"，
+// 字母转为小写，其他字符及不支持的汉字原样保留
+func PinyinInitials(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			if initial, ok := PinyinInitial(r); ok {
+				sb.WriteByte(initial)
+				continue
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// FuzzyMatch 判断 query 是否按顺序出现在 target 或 target 的拼音首字母中，不区分大小写，
+// 如 "usr" 匹配 "user_role"，"yhgl" 匹配 "用户管理"，空 query 匹配任意 target
+func FuzzyMatch(query, target string) bool {
+	query = strings.ToLower(query)
+	return isSubsequence(query, strings.ToLower(target)) || isSubsequence(query, PinyinInitials(target))
+}
+
+func isSubsequence(sub, s string) bool {
+	subRunes := []rune(sub)
+	i := 0
+	for _, r := range s {
+		if i == len(subRunes) {
+			break
+		}
+		if subRunes[i] == r {
+			i++
+		}
+	}
+	return i == len(subRunes)
+}
@@ -0,0 +1,46 @@
+package gutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	assert.Equal(t, 0, LevenshteinDistance("", ""))
+	assert.Equal(t, 3, LevenshteinDistance("", "abc"))
+	assert.Equal(t, 3, LevenshteinDistance("kitten", "sitting"))
+	assert.Equal(t, 1, LevenshteinDistance("用户管理", "用户管里"))
+	assert.Equal(t, 2, LevenshteinDistance("user_role", "user_rule_"))
+}
+
+func TestLCSSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, LCSSimilarity("", ""))
+	assert.Equal(t, 0.0, LCSSimilarity("abc", ""))
+	assert.Equal(t, 1.0, LCSSimilarity("订单", "订单"))
+	// LCS("user_role", "user") = 4
+	assert.InDelta(t, 8.0/13, LCSSimilarity("user_role", "user"), 1e-9)
+}
+
+func TestPinyinInitials(t *testing.T) {
+	assert.Equal(t, "yhgl", PinyinInitials("用户管理"))
+	assert.Equal(t, "bj", PinyinInitials("北京"))
+	assert.Equal(t, "sh-2024", PinyinInitials("上海-2024"))
+	assert.Equal(t, "ab", PinyinInitials("阿B"))
+	assert.Equal(t, "z", PinyinInitials("座"))
+
+	_, ok := PinyinInitial('a')
+	assert.False(t, ok)
+	// 二级汉字不支持，原样保留
+	assert.Equal(t, "亍", PinyinInitials("亍"))
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	assert.True(t, FuzzyMatch("", "anything"))
+	assert.True(t, FuzzyMatch("usr", "user_role"))
+	assert.True(t, FuzzyMatch("UR", "user_role"))
+	assert.True(t, FuzzyMatch("yhgl", "用户管理"))
+	assert.True(t, FuzzyMatch("用管", "用户管理"))
+	assert.False(t, FuzzyMatch("ru", "user_order"))
+	assert.False(t, FuzzyMatch("ddgl", "用户管理"))
+}
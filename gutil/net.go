@@ -0,0 +1,159 @@
+package gutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+)
+
+// GetLocalIP 获取本机首选出口 IPv4 地址。
+// 通过 UDP "连接" 公网地址获取路由选择的本地地址（不会真正发送数据），失败时回退到第一个非回环的 IPv4 网卡地址。
+func GetLocalIP() (string, error) {
+	if conn, err := net.Dial("udp4", "8.8.8.8:80"); err == nil {
+		defer conn.Close()
+		if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok && !addr.IP.IsUnspecified() {
+			return addr.IP.String(), nil
+		}
+	}
+	ips, err := GetLocalIPs()
+	if err != nil {
+		return "", err
+	}
+	if len(ips) == 0 {
+		return "", errors.New("no available local ipv4 address")
+	}
+	return ips[0], nil
+}
+
+// GetLocalIPs 获取本机所有已启用网卡上的非回环 IPv4 地址
+func GetLocalIPs() ([]string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var ips []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ip, err := interfaceIPv4(iface)
+		if err != nil {
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// GetInterfaceIP 获取指定网卡的 IPv4 地址，如 "eth0"
+func GetInterfaceIP(name string) (string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", err
+	}
+	return interfaceIPv4(*iface)
+}
+
+func interfaceIPv4(iface net.Interface) (string, error) {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			return ip4.String(), nil
+		}
+	}
+	return "", fmt.Errorf("interface %s has no ipv4 address", iface.Name)
+}
+
+// CIDRContains 判断 IP 是否在 CIDR 网段内，如 CIDRContains("10.0.0.0/8", "10.1.2.3")
+func CIDRContains(cidr, ip string) (bool, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, err
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false, fmt.Errorf("invalid ip: %s", ip)
+	}
+	return ipNet.Contains(parsed), nil
+}
+
+// IPInList 判断 IP 是否匹配列表中的任一项，列表项可以是单个 IP 或 CIDR 网段，
+// 无效的 IP 或列表项视为不匹配，适用于白名单校验
+func IPInList(ip string, list []string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, item := range list {
+		if _, ipNet, err := net.ParseCIDR(item); err == nil {
+			if ipNet.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+		if target := net.ParseIP(item); target != nil && target.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// IPv4ToUint32 将 IPv4 地址转换为 uint32
+func IPv4ToUint32(ip string) (uint32, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return 0, fmt.Errorf("invalid ip: %s", ip)
+	}
+	ip4 := parsed.To4()
+	if ip4 == nil {
+		return 0, fmt.Errorf("not an ipv4 address: %s", ip)
+	}
+	return binary.BigEndian.Uint32(ip4), nil
+}
+
+// Uint32ToIPv4 将 uint32 转换为 IPv4 地址
+func Uint32ToIPv4(n uint32) string {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip.String()
+}
+
+// IPToBigInt 将 IPv4 或 IPv6 地址转换为整数，IPv4 按 4 字节计算
+func IPToBigInt(ip string) (*big.Int, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid ip: %s", ip)
+	}
+	if ip4 := parsed.To4(); ip4 != nil {
+		parsed = ip4
+	}
+	return new(big.Int).SetBytes(parsed), nil
+}
+
+// IsPrivateIP 判断是否为内网地址：RFC 1918 / RFC 4193 私有地址、回环地址和链路本地地址
+func IsPrivateIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	return parsed.IsPrivate() || parsed.IsLoopback() ||
+		parsed.IsLinkLocalUnicast() || parsed.IsLinkLocalMulticast()
+}
+
+// IsPublicIP 判断是否为公网地址
+func IsPublicIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsUnspecified() || parsed.IsMulticast() {
+		return false
+	}
+	return !IsPrivateIP(ip)
+}
@@ -0,0 +1,70 @@
+package gutil
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLocalIP(t *testing.T) {
+	ip, err := GetLocalIP()
+	if err != nil {
+		t.Skipf("no local ip available: %v", err)
+	}
+	assert.NotNil(t, net.ParseIP(ip).To4())
+}
+
+func TestCIDRContains(t *testing.T) {
+	ok, err := CIDRContains("10.0.0.0/8", "10.1.2.3")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = CIDRContains("10.0.0.0/8", "192.168.1.1")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, err = CIDRContains("10.0.0.0", "10.1.2.3")
+	assert.NotNil(t, err)
+	_, err = CIDRContains("10.0.0.0/8", "invalid")
+	assert.NotNil(t, err)
+}
+
+func TestIPInList(t *testing.T) {
+	list := []string{"127.0.0.1", "192.168.0.0/16", "invalid", "::1"}
+	assert.True(t, IPInList("127.0.0.1", list))
+	assert.True(t, IPInList("192.168.10.20", list))
+	assert.True(t, IPInList("::1", list))
+	assert.False(t, IPInList("10.0.0.1", list))
+	assert.False(t, IPInList("invalid", list))
+}
+
+func TestIPv4Uint32(t *testing.T) {
+	n, err := IPv4ToUint32("192.168.1.1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3232235777), n)
+	assert.Equal(t, "192.168.1.1", Uint32ToIPv4(n))
+
+	_, err = IPv4ToUint32("::1")
+	assert.NotNil(t, err)
+
+	v, err := IPToBigInt("::ffff")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(65535), v.Int64())
+	v, err = IPToBigInt("0.0.1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(256), v.Int64())
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	for _, ip := range []string{"10.0.0.1", "172.16.5.4", "192.168.1.1", "127.0.0.1", "169.254.1.1", "fd00::1", "::1"} {
+		assert.True(t, IsPrivateIP(ip), ip)
+		assert.False(t, IsPublicIP(ip), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "2001:4860:4860::8888"} {
+		assert.False(t, IsPrivateIP(ip), ip)
+		assert.True(t, IsPublicIP(ip), ip)
+	}
+	assert.False(t, IsPublicIP("0.0.0.0"))
+	assert.False(t, IsPublicIP("invalid"))
+}
@@ -0,0 +1,252 @@
+package gutil
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// SyncMap 是 sync.Map 的泛型封装，适用于读多写少或 key 集合相对稳定的场景。
+// V 为接口类型时可以存入 nil，读取时返回零值
+type SyncMap[K comparable, V any] struct {
+	m sync.Map
+}
+
+// Load 获取 key 对应的值
+func (m *SyncMap[K, V]) Load(key K) (value V, ok bool) {
+	v, ok := m.m.Load(key)
+	if !ok {
+		return value, false
+	}
+	value, _ = v.(V)
+	return value, true
+}
+
+// Store 设置 key 对应的值
+func (m *SyncMap[K, V]) Store(key K, value V) {
+	m.m.Store(key, value)
+}
+
+// LoadOrStore key 存在时返回已有值，loaded 为 true；否则写入 value 并返回
+func (m *SyncMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	v, loaded := m.m.LoadOrStore(key, value)
+	actual, _ = v.(V)
+	return actual, loaded
+}
+
+// LoadAndDelete 删除 key 并返回删除前的值
+func (m *SyncMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	v, loaded := m.m.LoadAndDelete(key)
+	if !loaded {
+		return value, false
+	}
+	value, _ = v.(V)
+	return value, true
+}
+
+// Swap 写入新值并返回旧值
+func (m *SyncMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	v, loaded := m.m.Swap(key, value)
+	if !loaded {
+		return previous, false
+	}
+	previous, _ = v.(V)
+	return previous, true
+}
+
+// Delete 删除 key
+func (m *SyncMap[K, V]) Delete(key K) {
+	m.m.Delete(key)
+}
+
+// Range 遍历所有元素，fn 返回 false 时停止遍历
+func (m *SyncMap[K, V]) Range(fn func(key K, value V) bool) {
+	m.m.Range(func(k, v any) bool {
+		key, _ := k.(K)
+		value, _ := v.(V)
+		return fn(key, value)
+	})
+}
+
+// Len 返回元素数量，需要遍历整个 map，不适合在热点路径调用
+func (m *SyncMap[K, V]) Len() int {
+	var n int
+	m.m.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Keys 返回所有 key，顺序不固定
+func (m *SyncMap[K, V]) Keys() []K {
+	var keys []K
+	m.m.Range(func(k, _ any) bool {
+		key, _ := k.(K)
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Clear 删除所有元素
+func (m *SyncMap[K, V]) Clear() {
+	m.m.Clear()
+}
+
+const defaultShardCount = 32
+
+// ShardedMap 分片加锁的并发安全 map，写并发高的场景下锁竞争明显低于单锁 map 和 sync.Map
+type ShardedMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*mapShard[K, V]
+}
+
+type mapShard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// NewShardedMap 创建分片 map，shardCount <= 0 时使用默认分片数 32
+func NewShardedMap[K comparable, V any](shardCount int) *ShardedMap[K, V] {
+	if shardCount <= 0 {
+		shardCount = defaultShardCount
+	}
+	shards := make([]*mapShard[K, V], shardCount)
+	for i := range shards {
+		shards[i] = &mapShard[K, V]{m: make(map[K]V)}
+	}
+	return &ShardedMap[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: shards,
+	}
+}
+
+func (m *ShardedMap[K, V]) shard(key K) *mapShard[K, V] {
+	h := maphash.Comparable(m.seed, key)
+	return m.shards[h%uint64(len(m.shards))]
+}
+
+// Get 获取 key 对应的值
+func (m *ShardedMap[K, V]) Get(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.RLock()
+	v, ok := s.m[key]
+	s.mu.RUnlock()
+	return v, ok
+}
+
+// Set 设置 key 对应的值
+func (m *ShardedMap[K, V]) Set(key K, value V) {
+	s := m.shard(key)
+	s.mu.Lock()
+	s.m[key] = value
+	s.mu.Unlock()
+}
+
+// GetOrSet key 存在时返回已有值，loaded 为 true；否则写入 value 并返回
+func (m *ShardedMap[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// GetOrCompute key 不存在时调用 fn 生成值并写入，fn 在分片锁内执行，同一 key 只会计算一次
+func (m *ShardedMap[K, V]) GetOrCompute(key K, fn func() V) V {
+	s := m.shard(key)
+	s.mu.RLock()
+	v, ok := s.m[key]
+	s.mu.RUnlock()
+	if ok {
+		return v
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v
+	}
+	v = fn()
+	s.m[key] = v
+	return v
+}
+
+// Update 在分片锁内基于旧值计算新值，exists 表示旧值是否存在
+func (m *ShardedMap[K, V]) Update(key K, fn func(old V, exists bool) V) V {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, exists := s.m[key]
+	v := fn(old, exists)
+	s.m[key] = v
+	return v
+}
+
+// Delete 删除 key
+func (m *ShardedMap[K, V]) Delete(key K) {
+	s := m.shard(key)
+	s.mu.Lock()
+	delete(s.m, key)
+	s.mu.Unlock()
+}
+
+// GetAndDelete 删除 key 并返回删除前的值
+func (m *ShardedMap[K, V]) GetAndDelete(key K) (V, bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[key]
+	if ok {
+		delete(s.m, key)
+	}
+	return v, ok
+}
+
+// Len 返回元素数量
+func (m *ShardedMap[K, V]) Len() int {
+	var n int
+	for _, s := range m.shards {
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Range 遍历所有元素，fn 返回 false 时停止遍历
+// 遍历时按分片持有读锁，fn 中不能对同一 map 进行写操作
+func (m *ShardedMap[K, V]) Range(fn func(key K, value V) bool) {
+	for _, s := range m.shards {
+		s.mu.RLock()
+		for k, v := range s.m {
+			if !fn(k, v) {
+				s.mu.RUnlock()
+				return
+			}
+		}
+		s.mu.RUnlock()
+	}
+}
+
+// Keys 返回所有 key，顺序不固定
+func (m *ShardedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Clear 删除所有元素
+func (m *ShardedMap[K, V]) Clear() {
+	for _, s := range m.shards {
+		s.mu.Lock()
+		clear(s.m)
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,131 @@
+package gutil
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMap(t *testing.T) {
+	var m SyncMap[string, int]
+
+	_, ok := m.Load("a")
+	assert.False(t, ok)
+
+	m.Store("a", 1)
+	v, ok := m.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	actual, loaded := m.LoadOrStore("a", 2)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, actual)
+
+	actual, loaded = m.LoadOrStore("b", 2)
+	assert.False(t, loaded)
+	assert.Equal(t, 2, actual)
+
+	previous, loaded := m.Swap("b", 3)
+	assert.True(t, loaded)
+	assert.Equal(t, 2, previous)
+
+	keys := m.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+	assert.Equal(t, 2, m.Len())
+
+	v, loaded = m.LoadAndDelete("a")
+	assert.True(t, loaded)
+	assert.Equal(t, 1, v)
+
+	m.Delete("b")
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestSyncMapNilInterface(t *testing.T) {
+	var m SyncMap[string, error]
+	m.Store("a", nil)
+
+	v, ok := m.Load("a")
+	assert.True(t, ok)
+	assert.Nil(t, v)
+
+	actual, loaded := m.LoadOrStore("a", nil)
+	assert.True(t, loaded)
+	assert.Nil(t, actual)
+
+	previous, loaded := m.Swap("a", nil)
+	assert.True(t, loaded)
+	assert.Nil(t, previous)
+
+	var n int
+	m.Range(func(key string, value error) bool {
+		assert.Equal(t, "a", key)
+		assert.Nil(t, value)
+		n++
+		return true
+	})
+	assert.Equal(t, 1, n)
+
+	v, loaded = m.LoadAndDelete("a")
+	assert.True(t, loaded)
+	assert.Nil(t, v)
+}
+
+func TestShardedMap(t *testing.T) {
+	m := NewShardedMap[string, int](0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m.Set(strconv.Itoa(i), i)
+			m.Update("counter", func(old int, _ bool) int {
+				return old + 1
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 101, m.Len())
+	counter, ok := m.Get("counter")
+	assert.True(t, ok)
+	assert.Equal(t, 100, counter)
+
+	actual, loaded := m.GetOrSet("1", 100)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, actual)
+
+	calls := 0
+	for i := 0; i < 3; i++ {
+		v := m.GetOrCompute("computed", func() int {
+			calls++
+			return 42
+		})
+		assert.Equal(t, 42, v)
+	}
+	assert.Equal(t, 1, calls)
+
+	v, ok := m.GetAndDelete("computed")
+	assert.True(t, ok)
+	assert.Equal(t, 42, v)
+	_, ok = m.Get("computed")
+	assert.False(t, ok)
+
+	var sum int
+	m.Range(func(key string, value int) bool {
+		if key != "counter" {
+			sum += value
+		}
+		return true
+	})
+	assert.Equal(t, 4950, sum)
+	assert.Len(t, m.Keys(), 101)
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+}
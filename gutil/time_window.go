@@ -0,0 +1,66 @@
+package gutil
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TimeBatch 同一时间窗口内的元素，窗口为左闭右开区间 [Start, End)
+type TimeBatch[T any] struct {
+	Start time.Time
+	End   time.Time
+	Items []T
+}
+
+// IterateTimeWindows 将 [start, end) 按 step 切分为连续的时间窗口并依次回调 fn，最后一个窗口截断到 end，
+// 常用于报表、导出任务按天或按小时分段查询数据。fn 返回 error 时停止迭代并返回该 error
+func IterateTimeWindows(start, end time.Time, step time.Duration, fn func(windowStart, windowEnd time.Time) error) error {
+	if step <= 0 {
+		return fmt.Errorf("invalid time window step: %s", step)
+	}
+	for windowStart := start; windowStart.Before(end); windowStart = windowStart.Add(step) {
+		windowEnd := windowStart.Add(step)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		if err := fn(windowStart, windowEnd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchByTime 按 getTime 返回的时间将元素划分到长度为 window 的时间窗口中，返回按窗口起始时间升序排列的非空窗口，
+// 窗口内保持元素的原始顺序。窗口按时间所在时区对齐，如 24h 窗口对齐到当地零点、1h 窗口对齐到整点；window <= 0 时返回 nil
+func BatchByTime[T any](items []T, getTime func(T) time.Time, window time.Duration) []TimeBatch[T] {
+	if window <= 0 {
+		return nil
+	}
+
+	batchIndex := make(map[int64]int)
+	var batches []TimeBatch[T]
+	for _, item := range items {
+		start := alignTimeWindow(getTime(item), window)
+		key := start.UnixNano()
+		idx, ok := batchIndex[key]
+		if !ok {
+			idx = len(batches)
+			batchIndex[key] = idx
+			batches = append(batches, TimeBatch[T]{Start: start, End: start.Add(window)})
+		}
+		batches[idx].Items = append(batches[idx].Items, item)
+	}
+
+	sort.SliceStable(batches, func(i, j int) bool {
+		return batches[i].Start.Before(batches[j].Start)
+	})
+	return batches
+}
+
+// alignTimeWindow 返回 t 所在窗口的起始时间，按 t 所在时区的 UTC 偏移对齐
+func alignTimeWindow(t time.Time, window time.Duration) time.Time {
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(window).Add(-shift)
+}
@@ -0,0 +1,66 @@
+package gutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterateTimeWindows(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC)
+
+	var windows [][2]time.Time
+	err := IterateTimeWindows(start, end, DayDuration, func(windowStart, windowEnd time.Time) error {
+		windows = append(windows, [2]time.Time{windowStart, windowEnd})
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, [][2]time.Time{
+		{start, start.Add(DayDuration)},
+		{start.Add(DayDuration), start.Add(2 * DayDuration)},
+		{start.Add(2 * DayDuration), end},
+	}, windows)
+
+	stopErr := errors.New("stop")
+	calls := 0
+	err = IterateTimeWindows(start, end, DayDuration, func(_, _ time.Time) error {
+		calls++
+		return stopErr
+	})
+	assert.Equal(t, stopErr, err)
+	assert.Equal(t, 1, calls)
+
+	assert.NotNil(t, IterateTimeWindows(start, end, 0, func(_, _ time.Time) error { return nil }))
+	assert.Nil(t, IterateTimeWindows(end, start, DayDuration, func(_, _ time.Time) error {
+		t.Fatal("unexpected window")
+		return nil
+	}))
+}
+
+func TestBatchByTime(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	type order struct {
+		ID        int
+		CreatedAt time.Time
+	}
+	orders := []order{
+		{1, time.Date(2024, 3, 2, 9, 0, 0, 0, loc)},
+		{2, time.Date(2024, 3, 1, 0, 30, 0, 0, loc)},
+		{3, time.Date(2024, 3, 2, 23, 59, 0, 0, loc)},
+		{4, time.Date(2024, 3, 1, 23, 0, 0, 0, loc)},
+	}
+
+	batches := BatchByTime(orders, func(o order) time.Time { return o.CreatedAt }, DayDuration)
+	assert.Len(t, batches, 2)
+	// 按当地零点对齐
+	assert.True(t, batches[0].Start.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, loc)))
+	assert.True(t, batches[0].End.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, loc)))
+	assert.Equal(t, []order{orders[1], orders[3]}, batches[0].Items)
+	assert.Equal(t, []order{orders[0], orders[2]}, batches[1].Items)
+
+	assert.Nil(t, BatchByTime(orders, func(o order) time.Time { return o.CreatedAt }, 0))
+	assert.Empty(t, BatchByTime(nil, func(o order) time.Time { return o.CreatedAt }, time.Hour))
+}
@@ -0,0 +1,264 @@
+package ghttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/morehao/golib/glog"
+)
+
+const (
+	defaultAuditBufferSize = 1024
+	defaultAuditWorkers    = 1
+	auditRedactedValue     = "***"
+)
+
+// 默认脱敏的 JSON 字段名和请求头，字段名比较时忽略大小写
+var (
+	defaultAuditRedactKeys = []string{
+		"password", "passwd", "secret", "token", "access_token", "refresh_token",
+		"card_no", "card_number", "cvv", "id_card",
+	}
+	defaultAuditRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+)
+
+// AuditRecord 出站请求审计记录，写入存储前已完成脱敏
+type AuditRecord struct {
+	Service        string            `json:"service"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Path           string            `json:"path"`
+	RequestID      string            `json:"requestId"`
+	RequestHeader  map[string]string `json:"requestHeader"`
+	RequestBody    string            `json:"requestBody"`
+	StatusCode     int               `json:"statusCode"`
+	ResponseHeader map[string]string `json:"responseHeader"`
+	ResponseBody   string            `json:"responseBody"`
+	Error          string            `json:"error"`
+	StartTime      time.Time         `json:"startTime"`
+	Duration       time.Duration     `json:"duration"`
+}
+
+// AuditStore 审计记录存储，如数据库、消息队列，由 Auditor 异步调用
+type AuditStore interface {
+	Save(ctx context.Context, record *AuditRecord) error
+}
+
+// AuditStoreFunc 函数形式的 AuditStore
+type AuditStoreFunc func(ctx context.Context, record *AuditRecord) error
+
+// Save 实现 AuditStore 接口
+func (f AuditStoreFunc) Save(ctx context.Context, record *AuditRecord) error {
+	return f(ctx, record)
+}
+
+// Auditor 出站请求审计器，对匹配的关键接口（支付、回调等）生成脱敏后的请求/响应摘要并异步持久化，
+// 用于对账和追溯。缓冲区满时丢弃记录并输出告警日志，不阻塞请求。
+type Auditor struct {
+	store         AuditStore
+	paths         []string
+	redactKeys    map[string]bool
+	redactHeaders map[string]bool
+	maxBodySize   int
+	bufferSize    int
+	workers       int
+	records       chan auditTask
+	wg            sync.WaitGroup
+	closeOnce     sync.Once
+	mu            sync.RWMutex
+	closed        bool
+}
+
+type auditTask struct {
+	ctx    context.Context
+	record *AuditRecord
+}
+
+// AuditOption 审计器选项
+type AuditOption func(*Auditor)
+
+// WithAuditPaths 设置需要审计的路径前缀，未设置时审计所有请求
+func WithAuditPaths(paths ...string) AuditOption {
+	return func(a *Auditor) {
+		a.paths = append(a.paths, paths...)
+	}
+}
+
+// WithAuditRedactKeys 追加需要脱敏的 JSON 字段名
+func WithAuditRedactKeys(keys ...string) AuditOption {
+	return func(a *Auditor) {
+		for _, key := range keys {
+			a.redactKeys[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// WithAuditRedactHeaders 追加需要脱敏的请求头和响应头
+func WithAuditRedactHeaders(headers ...string) AuditOption {
+	return func(a *Auditor) {
+		for _, header := range headers {
+			a.redactHeaders[http.CanonicalHeaderKey(header)] = true
+		}
+	}
+}
+
+// WithAuditMaxBodySize 设置请求体和响应体记录的最大字节数，<= 0 时不截断
+func WithAuditMaxBodySize(size int) AuditOption {
+	return func(a *Auditor) {
+		a.maxBodySize = size
+	}
+}
+
+// WithAuditBuffer 设置异步缓冲区大小和写入协程数
+func WithAuditBuffer(bufferSize, workers int) AuditOption {
+	return func(a *Auditor) {
+		a.bufferSize = bufferSize
+		a.workers = workers
+	}
+}
+
+// NewAuditor 创建审计器并启动异步写入协程，使用完毕需调用 Close 刷新缓冲区
+func NewAuditor(store AuditStore, opts ...AuditOption) *Auditor {
+	a := &Auditor{
+		store:         store,
+		redactKeys:    make(map[string]bool),
+		redactHeaders: make(map[string]bool),
+		bufferSize:    defaultAuditBufferSize,
+		workers:       defaultAuditWorkers,
+	}
+	WithAuditRedactKeys(defaultAuditRedactKeys...)(a)
+	WithAuditRedactHeaders(defaultAuditRedactHeaders...)(a)
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.bufferSize <= 0 {
+		a.bufferSize = defaultAuditBufferSize
+	}
+	if a.workers <= 0 {
+		a.workers = defaultAuditWorkers
+	}
+
+	a.records = make(chan auditTask, a.bufferSize)
+	for i := 0; i < a.workers; i++ {
+		a.wg.Add(1)
+		go a.run()
+	}
+	return a
+}
+
+// Close 停止接收新记录，等待缓冲区中的记录写入完成
+func (a *Auditor) Close() {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		a.closed = true
+		close(a.records)
+		a.mu.Unlock()
+		a.wg.Wait()
+	})
+}
+
+func (a *Auditor) run() {
+	defer a.wg.Done()
+	for task := range a.records {
+		a.redact(task.record)
+		if err := a.store.Save(task.ctx, task.record); err != nil {
+			glog.Warnf(task.ctx, "http audit save failed, url: %s, error: %v", task.record.URL, err)
+		}
+	}
+}
+
+// match 判断路径是否需要审计
+func (a *Auditor) match(path string) bool {
+	if len(a.paths) == 0 {
+		return true
+	}
+	for _, p := range a.paths {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// submit 放入缓冲区，脱敏和写入均在异步协程中完成；缓冲区已满或审计器已关闭时丢弃
+func (a *Auditor) submit(ctx context.Context, record *AuditRecord) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return
+	}
+	select {
+	case a.records <- auditTask{ctx: context.WithoutCancel(ctx), record: record}:
+	default:
+		glog.Warnf(ctx, "http audit buffer full, record dropped, url: %s", record.URL)
+	}
+}
+
+// redact 对记录中的请求/响应体和请求/响应头脱敏
+func (a *Auditor) redact(record *AuditRecord) {
+	record.RequestBody = a.redactBody(record.RequestBody)
+	record.ResponseBody = a.redactBody(record.ResponseBody)
+	a.redactHeaderMap(record.RequestHeader)
+	a.redactHeaderMap(record.ResponseHeader)
+}
+
+// redactBody 对 JSON 请求体中的敏感字段脱敏，非 JSON 内容原样保留，超过最大长度时截断
+func (a *Auditor) redactBody(body string) string {
+	if body != "" {
+		var v any
+		if err := json.Unmarshal([]byte(body), &v); err == nil {
+			if data, err := json.Marshal(a.redactValue(v)); err == nil {
+				body = string(data)
+			}
+		}
+	}
+	if a.maxBodySize > 0 && len(body) > a.maxBodySize {
+		body = body[:a.maxBodySize]
+	}
+	return body
+}
+
+func (a *Auditor) redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if a.redactKeys[strings.ToLower(k)] {
+				val[k] = auditRedactedValue
+				continue
+			}
+			val[k] = a.redactValue(item)
+		}
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = a.redactValue(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func (a *Auditor) redactHeaderMap(header map[string]string) {
+	for k := range header {
+		if a.redactHeaders[http.CanonicalHeaderKey(k)] {
+			header[k] = auditRedactedValue
+		}
+	}
+}
+
+// flattenHeader 将 http.Header 转为单值 map，多值以逗号连接
+func flattenHeader(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(header))
+	for k, v := range header {
+		result[k] = strings.Join(v, ",")
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"data":{"orderId":"o-1","token":"resp-token"}}`))
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var records []*AuditRecord
+	auditor := NewAuditor(AuditStoreFunc(func(_ context.Context, record *AuditRecord) error {
+		mu.Lock()
+		records = append(records, record)
+		mu.Unlock()
+		return nil
+	}), WithAuditPaths("/pay"), WithAuditRedactKeys("bankAccount"))
+
+	client := NewClient(&protocol.HttpClientConfig{
+		Module:  "payment",
+		Host:    srv.URL,
+		Timeout: 3 * time.Second,
+	})
+	client.SetAuditor(auditor)
+
+	ctx := context.Background()
+	_, err := client.Post(ctx, "/pay/create", RequestOption{
+		RequestBody: map[string]any{"amount": 100, "password": "123456", "bankAccount": "6222"},
+		Headers:     map[string]string{"Authorization": "Bearer secret"},
+	})
+	assert.Nil(t, err)
+	_, err = client.Get(ctx, "/query", RequestOption{})
+	assert.Nil(t, err)
+	auditor.Close()
+
+	assert.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, "payment", record.Service)
+	assert.Equal(t, http.MethodPost, record.Method)
+	assert.Equal(t, "/pay/create", record.Path)
+	assert.Equal(t, http.StatusOK, record.StatusCode)
+	assert.JSONEq(t, `{"amount":100,"password":"***","bankAccount":"***"}`, record.RequestBody)
+	assert.JSONEq(t, `{"code":0,"data":{"orderId":"o-1","token":"***"}}`, record.ResponseBody)
+	assert.Equal(t, "***", record.RequestHeader["Authorization"])
+}
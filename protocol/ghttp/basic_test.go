@@ -6,14 +6,16 @@ import (
 	"time"
 
 	"github.com/morehao/golib/protocol"
+	"github.com/morehao/golib/protocol/protocoltest"
 	"github.com/stretchr/testify/assert"
 )
 
 // TestBasicFunctionality 测试基本功能
 func TestBasicFunctionality(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.Echo("", "/*"))
 	cfg := &protocol.HttpClientConfig{
 		Module:   "test",
-		Host:     "http://httpbin.org",
+		Host:     srv.URL,
 		Timeout:  10 * time.Second,
 		MaxRetry: 1,
 	}
@@ -0,0 +1,300 @@
+package ghttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/protocol"
+)
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerOpenTimeout      = 10 * time.Second
+	defaultBreakerHalfOpenProbes   = 1
+)
+
+// ErrCircuitOpen 熔断器打开或半开探测名额已满，请求未发出直接失败
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerKeyMode 熔断器的统计维度
+type BreakerKeyMode uint8
+
+const (
+	// BreakerKeyService 按服务统计，同一服务的所有主机共用一个熔断器，状态同步上报到 protocol.Stats
+	BreakerKeyService BreakerKeyMode = iota
+	// BreakerKeyHost 按服务和请求主机统计，key 为 {service}@{host}，适用于 Host 为多个实例或请求地址不固定的场景
+	BreakerKeyHost
+)
+
+// BreakerState 单个熔断器的状态快照
+type BreakerState struct {
+	Key      string    `json:"key"`
+	State    string    `json:"state"`     // protocol.BreakerStateClosed、BreakerStateOpen 或 BreakerStateHalfOpen
+	Failures int       `json:"failures"`  // 关闭状态下的连续失败次数
+	OpenedAt time.Time `json:"opened_at"` // 最近一次打开的时间
+}
+
+// CircuitBreaker 熔断器。关闭状态下连续失败达到阈值后打开，打开期间请求直接返回 ErrCircuitOpen，不占用连接池和延迟预算；
+// 打开超过 openTimeout 后进入半开状态，放行少量探测请求，全部成功后关闭，任一失败重新打开。
+// 一次调用（含内置重试）计为一次结果，调用方取消 ctx 不计入结果
+type CircuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenProbes   int
+	keyMode          BreakerKeyMode
+	isFailure        func(resp *http.Response, err error) bool
+	onStateChange    func(key, from, to string)
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+	now      func() time.Time
+}
+
+type breaker struct {
+	state      string
+	failures   int
+	openedAt   time.Time
+	probes     int    // 半开状态下已放行的探测请求数
+	successes  int    // 半开状态下成功的探测请求数
+	generation uint64 // 状态变化时递增，忽略状态变化前放行的请求结果
+}
+
+// BreakerOption 熔断器选项
+type BreakerOption func(*CircuitBreaker)
+
+// WithFailureThreshold 设置打开熔断器的连续失败次数，默认 5
+func WithFailureThreshold(n int) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.failureThreshold = n
+	}
+}
+
+// WithOpenTimeout 设置熔断器打开后进入半开状态前的等待时间，默认 10s
+func WithOpenTimeout(d time.Duration) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.openTimeout = d
+	}
+}
+
+// WithHalfOpenProbes 设置半开状态放行的探测请求数，全部成功后关闭熔断器，默认 1
+func WithHalfOpenProbes(n int) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.halfOpenProbes = n
+	}
+}
+
+// WithBreakerKeyMode 设置熔断器的统计维度，默认 BreakerKeyService
+func WithBreakerKeyMode(mode BreakerKeyMode) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.keyMode = mode
+	}
+}
+
+// WithBreakerFailure 自定义失败判断，默认网络错误、超时或 5xx 响应计为失败，重定向策略错误不计为失败
+func WithBreakerFailure(fn func(resp *http.Response, err error) bool) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.isFailure = fn
+	}
+}
+
+// WithBreakerStateHook 设置状态变化回调，可用于上报指标或告警，回调在持有锁时调用，不应阻塞
+func WithBreakerStateHook(fn func(key, from, to string)) BreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.onStateChange = fn
+	}
+}
+
+// NewCircuitBreaker 创建熔断器，同一熔断器可由多个客户端共用，按服务（或服务和主机）分别统计
+func NewCircuitBreaker(opts ...BreakerOption) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		breakers: make(map[string]*breaker),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	if cb.failureThreshold <= 0 {
+		cb.failureThreshold = defaultBreakerFailureThreshold
+	}
+	if cb.openTimeout <= 0 {
+		cb.openTimeout = defaultBreakerOpenTimeout
+	}
+	if cb.halfOpenProbes <= 0 {
+		cb.halfOpenProbes = defaultBreakerHalfOpenProbes
+	}
+	return cb
+}
+
+// SetCircuitBreaker 设置熔断器，为 nil 时不熔断
+func (c *Client) SetCircuitBreaker(cb *CircuitBreaker) {
+	c.mu.Lock()
+	c.breaker = cb
+	c.mu.Unlock()
+}
+
+// State 返回 key 对应熔断器的状态，未发起过请求时为关闭状态
+func (cb *CircuitBreaker) State(key string) BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	b, ok := cb.breakers[key]
+	if !ok {
+		return BreakerState{Key: key, State: protocol.BreakerStateClosed}
+	}
+	cb.refresh(key, b)
+	return BreakerState{Key: key, State: b.state, Failures: b.failures, OpenedAt: b.openedAt}
+}
+
+// States 返回全部熔断器的状态，按 key 排序
+func (cb *CircuitBreaker) States() []BreakerState {
+	cb.mu.Lock()
+	keys := make([]string, 0, len(cb.breakers))
+	for key := range cb.breakers {
+		keys = append(keys, key)
+	}
+	cb.mu.Unlock()
+	sort.Strings(keys)
+	states := make([]BreakerState, 0, len(keys))
+	for _, key := range keys {
+		states = append(states, cb.State(key))
+	}
+	return states
+}
+
+func (cb *CircuitBreaker) key(service string, req *http.Request) string {
+	if cb.keyMode == BreakerKeyHost {
+		return service + "@" + req.URL.Host
+	}
+	return service
+}
+
+// allow 判断是否放行请求，返回放行时的 generation
+func (cb *CircuitBreaker) allow(key string) (uint64, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	b, ok := cb.breakers[key]
+	if !ok {
+		b = &breaker{state: protocol.BreakerStateClosed}
+		cb.breakers[key] = b
+		if cb.keyMode == BreakerKeyService {
+			protocol.SetBreakerState(key, b.state)
+		}
+	}
+	cb.refresh(key, b)
+	switch b.state {
+	case protocol.BreakerStateOpen:
+		return 0, false
+	case protocol.BreakerStateHalfOpen:
+		if b.probes >= cb.halfOpenProbes {
+			return 0, false
+		}
+		b.probes++
+	}
+	return b.generation, true
+}
+
+// done 记录请求结果，ignored 为 true 时只释放半开状态的探测名额
+func (cb *CircuitBreaker) done(key string, generation uint64, failed, ignored bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	b := cb.breakers[key]
+	if b == nil || b.generation != generation {
+		return
+	}
+	switch b.state {
+	case protocol.BreakerStateClosed:
+		if ignored {
+			return
+		}
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= cb.failureThreshold {
+			cb.transition(key, b, protocol.BreakerStateOpen)
+		}
+	case protocol.BreakerStateHalfOpen:
+		switch {
+		case ignored:
+			b.probes--
+		case failed:
+			cb.transition(key, b, protocol.BreakerStateOpen)
+		default:
+			b.successes++
+			if b.successes >= cb.halfOpenProbes {
+				cb.transition(key, b, protocol.BreakerStateClosed)
+			}
+		}
+	}
+}
+
+// refresh 打开时间超过 openTimeout 时进入半开状态
+func (cb *CircuitBreaker) refresh(key string, b *breaker) {
+	if b.state == protocol.BreakerStateOpen && cb.now().Sub(b.openedAt) >= cb.openTimeout {
+		cb.transition(key, b, protocol.BreakerStateHalfOpen)
+	}
+}
+
+func (cb *CircuitBreaker) transition(key string, b *breaker, state string) {
+	from := b.state
+	b.state = state
+	b.failures, b.probes, b.successes = 0, 0, 0
+	b.generation++
+	if state == protocol.BreakerStateOpen {
+		b.openedAt = cb.now()
+	}
+	if cb.keyMode == BreakerKeyService {
+		protocol.SetBreakerState(key, state)
+	}
+	glog.Warnw(context.Background(), "http circuit breaker state changed",
+		glog.KV("http.breaker.key", key),
+		glog.KV("http.breaker.from", from),
+		glog.KV("http.breaker.to", state),
+	)
+	if cb.onStateChange != nil {
+		cb.onStateChange(key, from, state)
+	}
+}
+
+func (cb *CircuitBreaker) failed(resp *http.Response, err error) bool {
+	if cb.isFailure != nil {
+		return cb.isFailure(resp, err)
+	}
+	if err != nil {
+		return !isRedirectPolicyError(err)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// roundTrip 使用熔断器包装 next
+func (cb *CircuitBreaker) roundTrip(service string, next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		key := cb.key(service, req)
+		generation, ok := cb.allow(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, key)
+		}
+		resp, err := next(req)
+		// 调用方取消不代表下游异常，不计入结果
+		ignored := errors.Is(err, context.Canceled)
+		cb.done(key, generation, !ignored && cb.failed(resp, err), ignored)
+		return resp, err
+	}
+}
+
+// withBreaker 设置了熔断器时使用熔断器包装 next
+func (c *Client) withBreaker(next RoundTripFunc) RoundTripFunc {
+	c.mu.RLock()
+	cb := c.breaker
+	c.mu.RUnlock()
+	if cb == nil {
+		return next
+	}
+	return cb.roundTrip(c.Service, next)
+}
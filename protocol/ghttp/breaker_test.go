@@ -0,0 +1,104 @@
+package ghttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls int
+	healthy := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !healthy {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	var transitions []string
+	cb := NewCircuitBreaker(
+		WithFailureThreshold(2),
+		WithOpenTimeout(time.Minute),
+		WithBreakerStateHook(func(key, from, to string) {
+			transitions = append(transitions, key+":"+from+"->"+to)
+		}),
+	)
+	cb.now = func() time.Time { return now }
+	client := NewClient(&protocol.HttpClientConfig{Module: "breaker", Host: srv.URL, Timeout: 3 * time.Second})
+	client.SetCircuitBreaker(cb)
+	ctx := context.Background()
+
+	// 连续失败达到阈值后打开，请求不再发出
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(ctx, "/", RequestOption{})
+		assert.NotNil(t, err)
+	}
+	assert.Equal(t, protocol.BreakerStateOpen, cb.State("breaker").State)
+	_, err := client.Get(ctx, "/", RequestOption{})
+	assert.True(t, errors.Is(err, ErrCircuitOpen), err)
+	_, err = client.GetStream(ctx, "/", RequestOption{})
+	assert.True(t, errors.Is(err, ErrCircuitOpen), err)
+	assert.Equal(t, 2, calls)
+	for _, s := range protocol.Stats() {
+		if s.Service == "breaker" {
+			assert.Equal(t, protocol.BreakerStateOpen, s.BreakerState)
+		}
+	}
+
+	// 半开探测失败重新打开
+	now = now.Add(time.Minute)
+	assert.Equal(t, protocol.BreakerStateHalfOpen, cb.State("breaker").State)
+	_, err = client.Get(ctx, "/", RequestOption{})
+	assert.False(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, protocol.BreakerStateOpen, cb.State("breaker").State)
+
+	// 半开探测成功后关闭
+	healthy = true
+	now = now.Add(time.Minute)
+	res, err := client.Get(ctx, "/", RequestOption{})
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", res.String())
+	assert.Equal(t, protocol.BreakerStateClosed, cb.State("breaker").State)
+	assert.Equal(t, []string{
+		"breaker:closed->open", "breaker:open->half_open", "breaker:half_open->open",
+		"breaker:open->half_open", "breaker:half_open->closed",
+	}, transitions)
+
+	// 调用方取消不计为失败
+	healthy = false
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.Get(cancelCtx, "/", RequestOption{})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, cb.State("breaker").Failures)
+}
+
+func TestCircuitBreakerKeyByHost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cb := NewCircuitBreaker(WithFailureThreshold(1), WithBreakerKeyMode(BreakerKeyHost))
+	client := NewClient(&protocol.HttpClientConfig{Module: "breaker_host", Host: srv.URL, Timeout: 3 * time.Second})
+	client.SetCircuitBreaker(cb)
+	_, err := client.Get(context.Background(), "/", RequestOption{})
+	assert.NotNil(t, err)
+
+	states := cb.States()
+	assert.Len(t, states, 1)
+	assert.Equal(t, "breaker_host@"+strings.TrimPrefix(srv.URL, "http://"), states[0].Key)
+	assert.Equal(t, protocol.BreakerStateOpen, states[0].State)
+}
@@ -3,6 +3,7 @@ package ghttp
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,15 +18,28 @@ import (
 )
 
 type Client struct {
-	Service         string        `yaml:"service"`
-	Host            string        `yaml:"host"`
-	Timeout         time.Duration `yaml:"timeout"`
-	Retry           int           `yaml:"retry"`
-	MaxIdleConns    int           `yaml:"max_idle_conns"`     // 最大空闲连接数
-	MaxConnsPerHost int           `yaml:"max_conns_per_host"` // 每个主机的最大连接数
-	httpClient      *http.Client  // 缓存的HTTP客户端
-	once            sync.Once     // 确保 httpClient 只初始化一次
-	mu              sync.RWMutex  // 保护配置字段的读写
+	Service             string           `yaml:"service"`
+	Host                string           `yaml:"host"`
+	Timeout             time.Duration    `yaml:"timeout"`
+	Retry               int              `yaml:"retry"`
+	MaxIdleConns        int              `yaml:"max_idle_conns"`        // 最大空闲连接数
+	MaxConnsPerHost     int              `yaml:"max_conns_per_host"`    // 每个主机的最大连接数
+	SuccessCode         int              `yaml:"success_code"`          // 响应包装中表示成功的业务码，默认 0
+	DisableCompression  bool             `yaml:"disable_compression"`   // 关闭 Transport 的自动 gzip 解压，声明并解压全部已注册的编码，用于接收 deflate、br 等编码的响应
+	MaxDecompressedSize int64            `yaml:"max_decompressed_size"` // DisableCompression 时解压后响应体的大小上限（字节），默认 100MB，流式请求不限制
+	httpClient          *http.Client     // 缓存的HTTP客户端
+	auditor             *Auditor         // 出站请求审计器，为 nil 时不审计
+	sloTracker          *SLOTracker      // 接口延迟预算跟踪器，为 nil 时不跟踪
+	redirectPolicy      *RedirectPolicy  // 重定向策略，为 nil 时使用默认策略
+	versionPolicy       *VersionPolicy   // API 版本协商策略，为 nil 时只输出弃用告警
+	middlewares         []Middleware     // 请求中间件，按追加顺序由外到内执行
+	defaultOption       *RequestOption   // 客户端级默认请求选项，为 nil 时不合并
+	retryPolicy         *RetryPolicy     // 重试策略，为 nil 时按 Retry 次数在网络错误时重试
+	breaker             *CircuitBreaker  // 熔断器，为 nil 时不熔断
+	signer              Signer           // 请求签名器，为 nil 时不签名
+	transport           transportOptions // 创建连接池使用的 TLS、代理和建连配置
+	once                sync.Once        // 确保 httpClient 只初始化一次
+	mu                  sync.RWMutex     // 保护配置字段的读写
 }
 
 func NewClient(cfg *protocol.HttpClientConfig) *Client {
@@ -37,6 +51,14 @@ func NewClient(cfg *protocol.HttpClientConfig) *Client {
 		client.Retry = cfg.MaxRetry
 		client.MaxIdleConns = cfg.MaxIdleConns
 		client.MaxConnsPerHost = cfg.MaxConnsPerHost
+		client.SuccessCode = cfg.SuccessCode
+		client.DisableCompression = cfg.DisableCompression
+		client.MaxDecompressedSize = cfg.MaxDecompressedSize
+		var tlsConfig func() (*tls.Config, error)
+		if cfg.TLS != nil {
+			tlsConfig = cfg.TLS.Build
+		}
+		client.transport.applyConfig(cfg.Proxy, tlsConfig)
 	}
 	if client.MaxIdleConns <= 0 {
 		client.MaxIdleConns = 100
@@ -47,22 +69,84 @@ func NewClient(cfg *protocol.HttpClientConfig) *Client {
 	return client
 }
 
-func (c *Client) getHTTPClient(timeout time.Duration) *http.Client {
+// SetAuditor 设置出站请求审计器，匹配的请求会异步持久化脱敏后的请求/响应摘要
+func (c *Client) SetAuditor(auditor *Auditor) {
+	c.mu.Lock()
+	c.auditor = auditor
+	c.mu.Unlock()
+}
+
+// SetRedirectPolicy 设置 3xx 重定向策略，未设置时最多跟随 10 次重定向，Authorization 按标准库规则处理
+func (c *Client) SetRedirectPolicy(policy *RedirectPolicy) {
+	c.mu.Lock()
+	c.redirectPolicy = policy
+	c.mu.Unlock()
+}
+
+// SetSLOTracker 设置接口延迟预算跟踪器，请求耗时超出预算时输出慢调用告警日志
+func (c *Client) SetSLOTracker(tracker *SLOTracker) {
+	c.mu.Lock()
+	c.sloTracker = tracker
+	c.mu.Unlock()
+}
+
+func (c *Client) maxDecompressedSize() int64 {
+	if c.MaxDecompressedSize > 0 {
+		return c.MaxDecompressedSize
+	}
+	return defaultMaxDecompressedSize
+}
+
+// getHTTPClient 返回共用连接池的 HTTP 客户端，不设置 http.Client.Timeout，超时时间按请求通过 context 控制
+func (c *Client) getHTTPClient() *http.Client {
 	c.once.Do(func() {
 		transport := &http.Transport{
 			MaxIdleConns:        c.MaxIdleConns,
 			MaxIdleConnsPerHost: c.MaxConnsPerHost,
 			IdleConnTimeout:     90 * time.Second,
+			DisableCompression:  c.DisableCompression,
 		}
+		c.mu.RLock()
+		c.transport.apply(transport)
+		c.mu.RUnlock()
 
 		c.httpClient = &http.Client{
-			Transport: transport,
-			Timeout:   timeout,
+			Transport:     transport,
+			CheckRedirect: c.checkRedirect,
 		}
 	})
 	return c.httpClient
 }
 
+// requestTimeout 返回本次请求的超时时间：RequestOption.Timeout 与 Client.Timeout 都设置时取最小值，都未设置时为 3s
+func (c *Client) requestTimeout(opt *RequestOption) time.Duration {
+	c.mu.RLock()
+	clientTimeout := c.Timeout
+	c.mu.RUnlock()
+
+	timeout := 3 * time.Second
+	if opt != nil && opt.Timeout > 0 {
+		timeout = opt.Timeout
+		if clientTimeout > 0 && clientTimeout < timeout {
+			timeout = clientTimeout
+		}
+	} else if clientTimeout > 0 {
+		timeout = clientTimeout
+	}
+	return timeout
+}
+
+// checkRedirect 按当前的重定向策略校验每一跳，策略可在客户端初始化后通过 SetRedirectPolicy 修改
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	c.mu.RLock()
+	policy := c.redirectPolicy
+	c.mu.RUnlock()
+	if policy == nil {
+		policy = defaultRedirectPolicy
+	}
+	return policy.check(c.Service, req, via)
+}
+
 func (c *Client) buildQueryParams(data interface{}) (string, error) {
 	values := url.Values{}
 
@@ -95,9 +179,25 @@ func (c *Client) buildQueryParams(data interface{}) (string, error) {
 }
 
 type RequestOption struct {
-	// RequestBody 请求体
+	// RequestBody 请求体，GET、HEAD 以及未设置 SendBody 的 DELETE、OPTIONS 请求编码为查询参数
 	RequestBody any
 
+	// Query 查询参数，所有请求方法都追加到请求地址，支持 map 和结构体
+	Query any
+
+	// SendBody 为 true 时 DELETE、OPTIONS 请求将 RequestBody 作为请求体发送，用于批量删除等需要请求体的接口
+	SendBody bool
+
+	// Files 上传的文件，与 FormFields 任一非空时以 multipart/form-data 发送，忽略 RequestBody 和 ContentType。
+	// 文件内容边读边发，不整体读入内存，请求失败时不重试
+	Files []UploadFile
+
+	// FormFields multipart/form-data 的普通表单字段
+	FormFields map[string]string
+
+	// Progress 下载进度回调，written 为已写入的字节数，total 为响应的 Content-Length，未知时为 -1，仅 Download 使用
+	Progress func(written, total int64)
+
 	// Headers 自定义请求头
 	Headers map[string]string
 
@@ -109,6 +209,12 @@ type RequestOption struct {
 
 	// Timeout 请求超时时间，是接口维度的请求超时时间，与 Client.Timeout 不同，二者取最小值
 	Timeout time.Duration
+
+	// CompressBody 为 true 时使用 gzip 压缩请求体并设置 Content-Encoding: gzip，需服务端支持，multipart 请求不压缩
+	CompressBody bool
+
+	// contentEncoding 请求体的压缩编码，由 buildPayload 设置
+	contentEncoding string
 }
 
 func (opt *RequestOption) getData() ([]byte, error) {
@@ -238,6 +344,20 @@ func (c *Client) Patch(ctx context.Context, path string, opt RequestOption) (*Re
 	return c.httpDo(ctx, http.MethodPatch, path, opt)
 }
 
+func (c *Client) Head(ctx context.Context, path string, opt RequestOption) (*Result, error) {
+	return c.httpDo(ctx, http.MethodHead, path, opt)
+}
+
+func (c *Client) Options(ctx context.Context, path string, opt RequestOption) (*Result, error) {
+	return c.httpDo(ctx, http.MethodOptions, path, opt)
+}
+
+// Do 使用任意请求方法发送请求，方法不区分大小写，用于 Get、Post 等未覆盖的方法，如 WebDAV 的 PROPFIND
+func (c *Client) Do(ctx context.Context, method, path string, opt RequestOption) (*Result, error) {
+	return c.httpDo(ctx, strings.ToUpper(method), path, opt)
+}
+
+// GetJSON 发送 GET 请求，忽略响应的 Content-Type 按 JSON 反序列化到 result，按 Content-Type 自动解码使用 GetAs
 func (c *Client) GetJSON(ctx context.Context, path string, result any, opt RequestOption) error {
 	resp, err := c.Get(ctx, path, opt)
 	if err != nil {
@@ -246,6 +366,7 @@ func (c *Client) GetJSON(ctx context.Context, path string, result any, opt Reque
 	return resp.JSON(result)
 }
 
+// PostJSON 发送 POST 请求，忽略响应的 Content-Type 按 JSON 反序列化到 result，按 Content-Type 自动解码使用 PostAs
 func (c *Client) PostJSON(ctx context.Context, path string, result any, opt RequestOption) error {
 	resp, err := c.Post(ctx, path, opt)
 	if err != nil {
@@ -254,6 +375,7 @@ func (c *Client) PostJSON(ctx context.Context, path string, result any, opt Requ
 	return resp.JSON(result)
 }
 
+// PutJSON 发送 PUT 请求，忽略响应的 Content-Type 按 JSON 反序列化到 result，按 Content-Type 自动解码使用 PutAs
 func (c *Client) PutJSON(ctx context.Context, path string, result any, opt RequestOption) error {
 	resp, err := c.Put(ctx, path, opt)
 	if err != nil {
@@ -262,6 +384,7 @@ func (c *Client) PutJSON(ctx context.Context, path string, result any, opt Reque
 	return resp.JSON(result)
 }
 
+// DeleteJSON 发送 DELETE 请求，忽略响应的 Content-Type 按 JSON 反序列化到 result，按 Content-Type 自动解码使用 DeleteAs
 func (c *Client) DeleteJSON(ctx context.Context, path string, result any, opt RequestOption) error {
 	resp, err := c.Delete(ctx, path, opt)
 	if err != nil {
@@ -270,6 +393,7 @@ func (c *Client) DeleteJSON(ctx context.Context, path string, result any, opt Re
 	return resp.JSON(result)
 }
 
+// PatchJSON 发送 PATCH 请求，忽略响应的 Content-Type 按 JSON 反序列化到 result，按 Content-Type 自动解码使用 PatchAs
 func (c *Client) PatchJSON(ctx context.Context, path string, result any, opt RequestOption) error {
 	resp, err := c.Patch(ctx, path, opt)
 	if err != nil {
@@ -279,43 +403,22 @@ func (c *Client) PatchJSON(ctx context.Context, path string, result any, opt Req
 }
 
 func (c *Client) httpDo(ctx context.Context, method, path string, opt RequestOption) (*Result, error) {
-	reqURL := c.Host + path
-	var payload io.Reader
-	var urlData []byte
-	var err error
-
-	switch method {
-	case http.MethodGet, http.MethodHead, http.MethodDelete:
-		payload = nil
-		if opt.RequestBody != nil {
-			queryParams, err := c.buildQueryParams(opt.RequestBody)
-			if err != nil {
-				glog.Errorf(ctx, "http client build query params error: %s", err.Error())
-				return nil, err
-			}
-			if queryParams != "" {
-				if strings.Contains(reqURL, "?") {
-					reqURL = reqURL + "&" + queryParams
-				} else {
-					reqURL = reqURL + "?" + queryParams
-				}
-			}
-		}
-		urlData = []byte(reqURL)
-	case http.MethodPost, http.MethodPatch, http.MethodPut:
-		urlData, err = opt.getData()
-		if err != nil {
-			glog.Errorf(ctx, "http client get data error: %s", err.Error())
-			return nil, err
-		}
-		payload = bytes.NewReader(urlData)
+	startTime := time.Now()
+	opt = c.mergeOption(opt)
+	reqURL, payload, urlData, err := c.buildPayload(method, path, &opt)
+	if err != nil {
+		glog.Errorf(ctx, "http client build request error: %s", err.Error())
+		return nil, err
 	}
 	request, err := c.makeRequest(ctx, method, reqURL, payload, opt)
 	if err != nil {
+		closePayload(payload)
 		glog.Errorf(ctx, "http client make request error: %s", err.Error())
 		return nil, err
 	}
-	body, fields, err := c.do(ctx, request, &opt, urlData)
+	body, fields, err := c.do(ctx, request, &opt)
+	protocol.RecordCall(c.Service, time.Since(startTime), err)
+	c.getVersionPolicy().observe(ctx, c.Service, method, path, body.Header)
 	reqData, respData := c.formatLogMsg(urlData, body.Response)
 	glog.Debugw(ctx, "http "+method+" request",
 		glog.KV(glog.KeyService, c.Service),
@@ -330,15 +433,127 @@ func (c *Client) httpDo(ctx context.Context, method, path string, opt RequestOpt
 		msg = err.Error()
 	}
 	glog.Infow(ctx, msg, fields)
+
+	c.mu.RLock()
+	auditor, sloTracker := c.auditor, c.sloTracker
+	c.mu.RUnlock()
+	if sloTracker != nil {
+		sloTracker.observe(ctx, &SlowCall{
+			Service:    c.Service,
+			Method:     method,
+			Path:       path,
+			StatusCode: body.HttpCode,
+			Duration:   time.Since(startTime),
+		})
+	}
+	if auditor != nil && auditor.match(path) {
+		c.audit(ctx, auditor, request, method, path, urlData, payload != nil, &body, err, startTime)
+	}
 	return &body, err
 }
 
+// buildPayload 按请求方法构造请求地址和请求体，返回的 reqData 用于日志：有请求体时为请求体，否则为请求地址
+func (c *Client) buildPayload(method, path string, opt *RequestOption) (string, io.Reader, []byte, error) {
+	reqURL := c.Host + path
+	if opt.Query != nil {
+		var err error
+		if reqURL, err = c.appendQueryParams(reqURL, opt.Query); err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	bodyAsQuery := method == http.MethodGet || method == http.MethodHead
+	if method == http.MethodDelete || method == http.MethodOptions {
+		bodyAsQuery = !opt.SendBody
+	}
+	if bodyAsQuery {
+		if opt.RequestBody != nil {
+			var err error
+			if reqURL, err = c.appendQueryParams(reqURL, opt.RequestBody); err != nil {
+				return "", nil, nil, err
+			}
+		}
+		return reqURL, nil, []byte(reqURL), nil
+	}
+
+	if opt.isMultipart() {
+		body, contentType, desc := opt.multipartBody()
+		opt.ContentType = contentType
+		return reqURL, body, desc, nil
+	}
+	data, err := opt.getData()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if opt.CompressBody && len(data) > 0 {
+		compressed, err := gzipBody(data)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("gzip request body failed: %w", err)
+		}
+		opt.contentEncoding = "gzip"
+		return reqURL, bytes.NewReader(compressed), data, nil
+	}
+	return reqURL, bytes.NewReader(data), data, nil
+}
+
+// closePayload 关闭未发送的请求体，使 multipart 的写入协程退出
+func closePayload(payload io.Reader) {
+	if closer, ok := payload.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func (c *Client) appendQueryParams(reqURL string, data any) (string, error) {
+	queryParams, err := c.buildQueryParams(data)
+	if err != nil {
+		return "", err
+	}
+	if queryParams == "" {
+		return reqURL, nil
+	}
+	if strings.Contains(reqURL, "?") {
+		return reqURL + "&" + queryParams, nil
+	}
+	return reqURL + "?" + queryParams, nil
+}
+
+// audit 生成审计记录并提交给审计器
+func (c *Client) audit(ctx context.Context, auditor *Auditor, request *http.Request, method, path string, reqData []byte, hasBody bool, result *Result, err error, startTime time.Time) {
+	record := &AuditRecord{
+		Service:        c.Service,
+		Method:         method,
+		URL:            request.URL.String(),
+		Path:           path,
+		RequestID:      glog.GetRequestID(ctx),
+		RequestHeader:  flattenHeader(request.Header),
+		StatusCode:     result.HttpCode,
+		ResponseHeader: flattenHeader(result.Header),
+		ResponseBody:   string(result.Response),
+		StartTime:      startTime,
+		Duration:       time.Since(startTime),
+	}
+	if hasBody {
+		record.RequestBody = string(reqData)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	auditor.submit(ctx, record)
+}
+
 func (c *Client) makeRequest(ctx context.Context, method, url string, data io.Reader, opts RequestOption) (*http.Request, error) {
+	c.mu.RLock()
+	configErr := c.transport.err
+	c.mu.RUnlock()
+	if configErr != nil {
+		return nil, configErr
+	}
 	request, err := http.NewRequest(method, url, data)
 	if err != nil {
 		return nil, err
 	}
 
+	c.getVersionPolicy().applyHeader(request.Header)
 	if opts.Headers != nil {
 		for k, v := range opts.Headers {
 			request.Header.Set(k, v)
@@ -353,69 +568,28 @@ func (c *Client) makeRequest(ctx context.Context, method, url string, data io.Re
 	}
 
 	request.Header.Set("Content-Type", opts.GetContentType())
+	if opts.contentEncoding != "" {
+		request.Header.Set("Content-Encoding", opts.contentEncoding)
+	}
+	if c.DisableCompression && request.Header.Get("Accept-Encoding") == "" {
+		request.Header.Set("Accept-Encoding", acceptEncoding())
+	}
 
 	request.Header = protocol.InjectTraceAndRequestID(ctx, request.Header)
 
 	return request.WithContext(ctx), nil
 }
 
-func (c *Client) do(ctx context.Context, request *http.Request, opt *RequestOption, requestBody []byte) (Result, []glog.Field, error) {
+func (c *Client) do(ctx context.Context, request *http.Request, opt *RequestOption) (Result, []glog.Field, error) {
 	startTime := time.Now()
 
-	c.mu.RLock()
-	clientTimeout := c.Timeout
-	c.mu.RUnlock()
-
-	timeout := 3 * time.Second
-	if opt != nil && opt.Timeout > 0 {
-		timeout = opt.Timeout
-	} else if clientTimeout > 0 {
-		timeout = clientTimeout
-	}
+	// 超时时间包含重试和读取响应体的时间
+	timeoutCtx, cancel := context.WithTimeout(ctx, c.requestTimeout(opt))
+	defer cancel()
+	request = request.WithContext(timeoutCtx)
+	httpClient := c.getHTTPClient()
 
-	if opt != nil && opt.Timeout > 0 && clientTimeout > 0 {
-		if opt.Timeout < clientTimeout {
-			timeout = opt.Timeout
-		} else {
-			timeout = clientTimeout
-		}
-	}
-
-	httpClient := c.getHTTPClient(timeout)
-
-	var resp *http.Response
-	var err error
-
-	retryCount := c.Retry
-	if retryCount <= 0 {
-		retryCount = 1
-	}
-
-	var originalBody []byte
-	if request.Body != nil && requestBody != nil {
-		originalBody = make([]byte, len(requestBody))
-		copy(originalBody, requestBody)
-	}
-
-	for i := 0; i < retryCount; i++ {
-		if i > 0 && originalBody != nil {
-			request.Body = io.NopCloser(bytes.NewReader(originalBody))
-		}
-
-		resp, err = httpClient.Do(request)
-		if err == nil {
-			break
-		}
-
-		if i < retryCount-1 {
-			delay := time.Millisecond * 100 * time.Duration(i+1)
-			if delay > time.Second {
-				delay = time.Second
-			}
-			time.Sleep(delay)
-			glog.Warnf(ctx, "http request retry %d/%d, error: %v", i+1, retryCount, err)
-		}
-	}
+	resp, err := c.roundTrip(c.withBreaker(c.retryRoundTrip(httpClient, "http request", c.maxDecompressedSize())))(request)
 
 	result := Result{
 		Ctx: ctx,
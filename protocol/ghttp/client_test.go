@@ -2,6 +2,7 @@ package ghttp
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,15 +10,18 @@ import (
 	"time"
 
 	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/gutil"
 	"github.com/morehao/golib/protocol"
+	"github.com/morehao/golib/protocol/protocoltest"
 	"github.com/stretchr/testify/assert"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestGet(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.Echo("", "/*"))
 	cfg := &protocol.HttpClientConfig{
 		Module:   "httpbin",
-		Host:     "http://httpbin.org",
+		Host:     srv.URL,
 		Timeout:  5 * time.Second,
 		MaxRetry: 3,
 	}
@@ -27,13 +31,14 @@ func TestGet(t *testing.T) {
 		RequestBody: map[string]string{"foo": "bar"},
 	})
 	assert.Nil(t, err)
-	t.Log(glog.ToJsonString(res))
+	t.Log(gutil.ToJsonString(res))
 }
 
 func TestGetJSON(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.Echo("", "/*"))
 	cfg := &protocol.HttpClientConfig{
 		Module:   "httpbin",
-		Host:     "http://httpbin.org",
+		Host:     srv.URL,
 		Timeout:  5 * time.Second,
 		MaxRetry: 3,
 	}
@@ -62,9 +67,10 @@ func TestGetJSON(t *testing.T) {
 }
 
 func TestPostJSON(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.Echo("", "/*"))
 	cfg := &protocol.HttpClientConfig{
 		Module:   "httpbin",
-		Host:     "http://httpbin.org",
+		Host:     srv.URL,
 		Timeout:  5 * time.Second,
 		MaxRetry: 3,
 	}
@@ -100,9 +106,10 @@ func TestPostJSON(t *testing.T) {
 }
 
 func TestGetWithChineseParams(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.Echo("", "/*"))
 	cfg := &protocol.HttpClientConfig{
 		Module:   "httpbin",
-		Host:     "http://httpbin.org",
+		Host:     srv.URL,
 		Timeout:  5 * time.Second,
 		MaxRetry: 3,
 	}
@@ -126,14 +133,15 @@ func TestGetWithChineseParams(t *testing.T) {
 	// 检查args中是否包含我们的中文参数
 	assert.Equal(t, "张三", result.Args["name"])
 	// 检查URL是否包含参数（可能是URL编码的）
-	assert.True(t, strings.Contains(result.URL, "name=张三") || strings.Contains(result.URL, "name%3D%E5%BC%A0%E4%B8%89"))
+	assert.True(t, strings.Contains(result.URL, "name=张三") || strings.Contains(result.URL, "name=%E5%BC%A0%E4%B8%89") || strings.Contains(result.URL, "name%3D%E5%BC%A0%E4%B8%89"))
 	t.Logf("Chinese params response: %+v", result)
 }
 
 func TestResultMethods(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.Echo("", "/*"))
 	cfg := &protocol.HttpClientConfig{
 		Module:   "httpbin",
-		Host:     "http://httpbin.org",
+		Host:     srv.URL,
 		Timeout:  5 * time.Second,
 		MaxRetry: 3,
 	}
@@ -150,7 +158,7 @@ func TestResultMethods(t *testing.T) {
 	// 测试 String 方法
 	responseStr := res.String()
 	assert.NotEmpty(t, responseStr)
-	assert.Contains(t, responseStr, "httpbin.org")
+	assert.Contains(t, responseStr, srv.URL)
 
 	// 测试 Bytes 方法
 	responseBytes := res.Bytes()
@@ -164,7 +172,7 @@ func TestResultMethods(t *testing.T) {
 	var result HttpBinResponse
 	err = res.JSON(&result)
 	assert.Nil(t, err)
-	assert.Contains(t, result.URL, "httpbin.org")
+	assert.Contains(t, result.URL, srv.URL)
 }
 
 func TestGetInjectsOTelTraceAndRequestID(t *testing.T) {
@@ -209,3 +217,81 @@ func TestGetInjectsOTelTraceAndRequestID(t *testing.T) {
 	assert.NotEqual(t, preHeaderTraceParent, gotTraceParent)
 	assert.Equal(t, requestID, gotRequestID)
 }
+
+func TestRequestMethods(t *testing.T) {
+	type received struct {
+		method string
+		query  string
+		body   string
+	}
+	var got received
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = received{method: r.Method, query: r.URL.RawQuery, body: string(body)}
+		w.Header().Set("Allow", "GET, DELETE")
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second})
+	ctx := context.Background()
+	body := map[string]any{"id": 1}
+
+	tests := []struct {
+		name string
+		call func() (*Result, error)
+		want received
+	}{
+		{
+			name: "put",
+			call: func() (*Result, error) { return client.Put(ctx, "/users/1", RequestOption{RequestBody: body}) },
+			want: received{method: http.MethodPut, body: `{"id":1}`},
+		},
+		{
+			name: "delete body as query",
+			call: func() (*Result, error) { return client.Delete(ctx, "/users", RequestOption{RequestBody: body}) },
+			want: received{method: http.MethodDelete, query: "id=1"},
+		},
+		{
+			name: "delete with body",
+			call: func() (*Result, error) {
+				return client.Delete(ctx, "/users", RequestOption{RequestBody: body, SendBody: true, Query: map[string]string{"force": "true"}})
+			},
+			want: received{method: http.MethodDelete, query: "force=true", body: `{"id":1}`},
+		},
+		{
+			name: "patch with query",
+			call: func() (*Result, error) {
+				return client.Patch(ctx, "/users/1?v=1", RequestOption{RequestBody: body, Query: map[string]string{"dry": "1"}})
+			},
+			want: received{method: http.MethodPatch, query: "v=1&dry=1", body: `{"id":1}`},
+		},
+		{
+			name: "head",
+			call: func() (*Result, error) { return client.Head(ctx, "/users/1", RequestOption{}) },
+			want: received{method: http.MethodHead},
+		},
+		{
+			name: "options",
+			call: func() (*Result, error) { return client.Options(ctx, "/users", RequestOption{}) },
+			want: received{method: http.MethodOptions},
+		},
+		{
+			name: "do custom method",
+			call: func() (*Result, error) { return client.Do(ctx, "propfind", "/files", RequestOption{RequestBody: "<propfind/>"}) },
+			want: received{method: "PROPFIND", body: "<propfind/>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = received{}
+			res, err := tt.call()
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, "GET, DELETE", res.Header.Get("Allow"))
+		})
+	}
+}
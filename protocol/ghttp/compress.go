@@ -0,0 +1,191 @@
+package ghttp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ContentDecoder 按 Content-Encoding 解压响应体
+type ContentDecoder func(r io.Reader) (io.ReadCloser, error)
+
+// defaultMaxDecompressedSize 解压后响应体的默认大小上限
+const defaultMaxDecompressedSize = 100 << 20
+
+// ErrDecompressedBodyTooLarge 解压后的响应体超过 MaxDecompressedSize，防止压缩炸弹耗尽内存
+var ErrDecompressedBodyTooLarge = errors.New("decompressed response body too large")
+
+var (
+	contentDecoderMu sync.RWMutex
+	contentDecoders  = map[string]ContentDecoder{
+		"gzip":    newGzipReader,
+		"x-gzip":  newGzipReader,
+		"deflate": newDeflateReader,
+		"br":      newBrotliReader,
+	}
+)
+
+// RegisterContentEncoding 注册响应体的解压方式，覆盖同名的已有解压方式，encoding 不区分大小写。
+// 内置 gzip、deflate 和 br
+func RegisterContentEncoding(encoding string, dec ContentDecoder) error {
+	if encoding == "" || dec == nil {
+		return errors.New("content encoding and decoder are required")
+	}
+	contentDecoderMu.Lock()
+	defer contentDecoderMu.Unlock()
+	contentDecoders[strings.ToLower(encoding)] = dec
+	return nil
+}
+
+// acceptEncoding 返回已注册的全部压缩编码，用于 Accept-Encoding 请求头
+func acceptEncoding() string {
+	contentDecoderMu.RLock()
+	encodings := make([]string, 0, len(contentDecoders))
+	for encoding := range contentDecoders {
+		if encoding != "x-gzip" {
+			encodings = append(encodings, encoding)
+		}
+	}
+	contentDecoderMu.RUnlock()
+	sort.Strings(encodings)
+	return strings.Join(encodings, ", ")
+}
+
+func lookupContentDecoder(encoding string) (ContentDecoder, bool) {
+	contentDecoderMu.RLock()
+	defer contentDecoderMu.RUnlock()
+	dec, ok := contentDecoders[encoding]
+	return dec, ok
+}
+
+func newGzipReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+func newBrotliReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(r)), nil
+}
+
+// newDeflateReader 按 zlib 格式解压，兼容部分服务端直接发送的裸 deflate 数据
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	// zlib 头：CM 为 8，且前两个字节按大端序是 31 的倍数
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// decompressResponse 按 Content-Encoding 解压未被 Transport 自动解压的响应体，支持多重编码，
+// 解压后移除 Content-Encoding 和 Content-Length 并设置 resp.Uncompressed。包含未注册的编码时保持原样。
+// maxSize 大于 0 时解压后的数据超过 maxSize 字节返回 ErrDecompressedBodyTooLarge
+func decompressResponse(resp *http.Response, err error, maxSize int64) (*http.Response, error) {
+	if err != nil || resp == nil || resp.Uncompressed {
+		return resp, err
+	}
+	var encodings []string
+	for _, value := range resp.Header.Values("Content-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			if encoding = strings.ToLower(strings.TrimSpace(encoding)); encoding != "" && encoding != "identity" {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+	if len(encodings) == 0 {
+		return resp, nil
+	}
+	decoders := make([]ContentDecoder, len(encodings))
+	for i, encoding := range encodings {
+		dec, ok := lookupContentDecoder(encoding)
+		if !ok {
+			return resp, nil
+		}
+		decoders[i] = dec
+	}
+	// 按编码的逆序解压
+	body := resp.Body
+	for i := len(decoders) - 1; i >= 0; i-- {
+		body = &lazyDecodeReader{src: body, newReader: decoders[i]}
+	}
+	if maxSize > 0 {
+		body = &limitedBodyReader{ReadCloser: body, reader: io.LimitReader(body, maxSize+1), remaining: maxSize}
+	}
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// lazyDecodeReader 首次读取时创建解压器，空响应体（如 HEAD、204）不会因缺少压缩头报错
+type lazyDecodeReader struct {
+	src       io.ReadCloser
+	newReader ContentDecoder
+	reader    io.ReadCloser
+	err       error
+}
+
+func (r *lazyDecodeReader) Read(p []byte) (int, error) {
+	if r.reader == nil && r.err == nil {
+		r.reader, r.err = r.newReader(r.src)
+		if r.err == io.EOF {
+			r.err = nil
+			r.reader = io.NopCloser(bytes.NewReader(nil))
+		}
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.reader.Read(p)
+}
+
+func (r *lazyDecodeReader) Close() error {
+	if r.reader != nil {
+		r.reader.Close()
+	}
+	return r.src.Close()
+}
+
+// limitedBodyReader 限制解压后的读取量，多读一个字节用于判断是否超限
+type limitedBodyReader struct {
+	io.ReadCloser
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *limitedBodyReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n + int(r.remaining), ErrDecompressedBodyTooLarge
+	}
+	return n, err
+}
+
+// gzipBody 使用 gzip 压缩请求体
+func gzipBody(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,131 @@
+package ghttp
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"id":1,"name":"compressed payload"},`), 1<<16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			// 回显解压后的请求体长度和请求的 Content-Encoding、Content-Length
+			body := io.Reader(r.Body)
+			if r.Header.Get("Content-Encoding") == "gzip" {
+				zr, err := gzip.NewReader(r.Body)
+				require.Nil(t, err)
+				body = zr
+			}
+			data, _ := io.ReadAll(body)
+			w.Header().Set("X-Content-Encoding", r.Header.Get("Content-Encoding"))
+			w.Header().Set("X-Content-Length", strconv.FormatInt(r.ContentLength, 10))
+			w.Write([]byte(strconv.Itoa(len(data))))
+			return
+		}
+		w.Header().Set("X-Accept-Encoding", r.Header.Get("Accept-Encoding"))
+		var buf bytes.Buffer
+		var zw io.WriteCloser
+		switch r.URL.Path {
+		case "/gzip":
+			zw = gzip.NewWriter(&buf)
+		case "/deflate":
+			zw = zlib.NewWriter(&buf)
+		case "/br":
+			zw = brotli.NewWriter(&buf)
+		case "/raw-deflate":
+			zw, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		case "/unknown":
+			w.Header().Set("Content-Encoding", "zstd")
+			w.Write([]byte("raw"))
+			return
+		case "/empty":
+			w.Header().Set("Content-Encoding", "gzip")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		zw.Write(payload)
+		zw.Close()
+		encoding := r.URL.Path[1:]
+		if encoding == "raw-deflate" {
+			encoding = "deflate"
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	// 压缩请求体，日志和审计仍使用原始请求体
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL})
+	res, err := client.Post(ctx, "/upload", RequestOption{RequestBody: payload, CompressBody: true})
+	require.Nil(t, err)
+	assert.Equal(t, strconv.Itoa(len(payload)), res.String())
+	assert.Equal(t, "gzip", res.Header.Get("X-Content-Encoding"))
+	sent, _ := strconv.Atoi(res.Header.Get("X-Content-Length"))
+	assert.Greater(t, sent, 0)
+	assert.Less(t, sent, len(payload)/10)
+	res, err = client.Post(ctx, "/upload", RequestOption{RequestBody: payload})
+	require.Nil(t, err)
+	assert.Equal(t, "", res.Header.Get("X-Content-Encoding"))
+
+	// 默认由 Transport 自动解压 gzip
+	res, err = client.Get(ctx, "/gzip", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, payload, res.Response)
+
+	// 关闭自动解压后声明全部已注册的编码，按 Content-Encoding 解压
+	client = NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, DisableCompression: true})
+	for _, path := range []string{"/gzip", "/deflate", "/raw-deflate", "/br"} {
+		res, err = client.Get(ctx, path, RequestOption{})
+		require.Nil(t, err, path)
+		assert.Equal(t, payload, res.Response, path)
+		assert.Equal(t, "br, deflate, gzip", res.Header.Get("X-Accept-Encoding"))
+		assert.Empty(t, res.Header.Get("Content-Encoding"))
+		assert.Empty(t, res.Header.Get("Content-Length"))
+	}
+	stream, err := client.GetStream(ctx, "/gzip", RequestOption{})
+	require.Nil(t, err)
+	content, err := io.ReadAll(stream)
+	stream.Close()
+	require.Nil(t, err)
+	assert.Equal(t, payload, content)
+
+	// 解压后超过大小上限时返回错误，流式请求不限制
+	client = NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, DisableCompression: true, MaxDecompressedSize: int64(len(payload) - 1)})
+	_, err = client.Get(ctx, "/gzip", RequestOption{})
+	assert.ErrorIs(t, err, ErrDecompressedBodyTooLarge)
+	client.MaxDecompressedSize = int64(len(payload))
+	res, err = client.Get(ctx, "/gzip", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, payload, res.Response)
+	client.MaxDecompressedSize = 1
+	stream, err = client.GetStream(ctx, "/deflate", RequestOption{})
+	require.Nil(t, err)
+	content, err = io.ReadAll(stream)
+	stream.Close()
+	require.Nil(t, err)
+	assert.Equal(t, payload, content)
+
+	// 空响应体和未注册的编码保持原样
+	res, err = client.Get(ctx, "/empty", RequestOption{})
+	require.Nil(t, err)
+	assert.Empty(t, res.Response)
+	res, err = client.Get(ctx, "/unknown", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, "raw", res.String())
+	assert.Equal(t, "zstd", res.Header.Get("Content-Encoding"))
+}
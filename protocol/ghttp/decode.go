@@ -0,0 +1,176 @@
+package ghttp
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+)
+
+// Decoder 将响应体反序列化到 v
+type Decoder func(data []byte, v any) error
+
+var (
+	// JSONDecoder 按 JSON 解析响应体
+	JSONDecoder Decoder = json.Unmarshal
+	// XMLDecoder 按 XML 解析响应体，适用于 SOAP/XML 接口
+	XMLDecoder Decoder = xml.Unmarshal
+	// FormDecoder 按 application/x-www-form-urlencoded 解析响应体，v 支持 *url.Values、*map[string]string 和 *map[string][]string，
+	// 同名参数解析到 map[string]string 时取第一个值
+	FormDecoder Decoder = decodeForm
+	// MsgpackDecoder 按 MessagePack 解析响应体，结构体字段名默认使用 codec 标签，未设置时使用字段名
+	MsgpackDecoder Decoder = decodeMsgpack
+)
+
+var msgpackHandle = &codec.MsgpackHandle{}
+
+var (
+	decoderMu sync.RWMutex
+	decoders  = map[string]Decoder{
+		"application/json":                  JSONDecoder,
+		"application/xml":                   XMLDecoder,
+		"text/xml":                          XMLDecoder,
+		"application/soap+xml":              XMLDecoder,
+		"application/x-www-form-urlencoded": FormDecoder,
+		"application/msgpack":               MsgpackDecoder,
+		"application/x-msgpack":             MsgpackDecoder,
+		"application/vnd.msgpack":           MsgpackDecoder,
+	}
+)
+
+// RegisterDecoder 按媒体类型注册解码器，覆盖同名的已有解码器，用于 Result.As 和 GetAs 等方法按 Content-Type 自动解码。
+// mediaType 不区分大小写，不含参数，如 "application/protobuf"
+func RegisterDecoder(mediaType string, dec Decoder) error {
+	if mediaType == "" || dec == nil {
+		return errors.New("media type and decoder are required")
+	}
+	decoderMu.Lock()
+	defer decoderMu.Unlock()
+	decoders[strings.ToLower(mediaType)] = dec
+	return nil
+}
+
+// lookupDecoder 按 Content-Type 查找解码器，未注册的 +json、+xml 后缀类型（如 application/problem+json）按 JSON、XML 解码，
+// Content-Type 为空时按 JSON 解码
+func lookupDecoder(contentType string) (Decoder, error) {
+	if contentType == "" {
+		return JSONDecoder, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response content type %q: %w", contentType, err)
+	}
+	decoderMu.RLock()
+	dec, ok := decoders[mediaType]
+	decoderMu.RUnlock()
+	switch {
+	case ok:
+		return dec, nil
+	case strings.HasSuffix(mediaType, "+json"):
+		return JSONDecoder, nil
+	case strings.HasSuffix(mediaType, "+xml"):
+		return XMLDecoder, nil
+	}
+	return nil, fmt.Errorf("no decoder registered for response content type %q", mediaType)
+}
+
+func decodeForm(data []byte, v any) error {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	switch dst := v.(type) {
+	case *url.Values:
+		*dst = values
+	case *map[string][]string:
+		*dst = values
+	case *map[string]string:
+		m := make(map[string]string, len(values))
+		for k := range values {
+			m[k] = values.Get(k)
+		}
+		*dst = m
+	default:
+		return fmt.Errorf("form decoder does not support %T", v)
+	}
+	return nil
+}
+
+func decodeMsgpack(data []byte, v any) error {
+	return codec.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
+
+// XML 按 XML 反序列化响应体到指定结构体
+func (r *Result) XML(v any) error {
+	return r.Decode(v, XMLDecoder)
+}
+
+// Decode 使用指定的解码器反序列化响应体
+func (r *Result) Decode(v any, decoder Decoder) error {
+	if r.Response == nil {
+		return fmt.Errorf("response body is nil")
+	}
+	return decoder(r.Response, v)
+}
+
+// As 按响应的 Content-Type 选择解码器反序列化响应体，Content-Type 为空时按 JSON 解码，
+// 自定义类型通过 RegisterDecoder 注册
+func (r *Result) As(v any) error {
+	decoder, err := lookupDecoder(r.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	return r.Decode(v, decoder)
+}
+
+// GetAs 发送 GET 请求，按响应的 Content-Type 将响应体反序列化到 result
+func (c *Client) GetAs(ctx context.Context, path string, result any, opt RequestOption) error {
+	resp, err := c.Get(ctx, path, opt)
+	if err != nil {
+		return err
+	}
+	return resp.As(result)
+}
+
+// PostAs 发送 POST 请求，按响应的 Content-Type 将响应体反序列化到 result
+func (c *Client) PostAs(ctx context.Context, path string, result any, opt RequestOption) error {
+	resp, err := c.Post(ctx, path, opt)
+	if err != nil {
+		return err
+	}
+	return resp.As(result)
+}
+
+// PutAs 发送 PUT 请求，按响应的 Content-Type 将响应体反序列化到 result
+func (c *Client) PutAs(ctx context.Context, path string, result any, opt RequestOption) error {
+	resp, err := c.Put(ctx, path, opt)
+	if err != nil {
+		return err
+	}
+	return resp.As(result)
+}
+
+// DeleteAs 发送 DELETE 请求，按响应的 Content-Type 将响应体反序列化到 result
+func (c *Client) DeleteAs(ctx context.Context, path string, result any, opt RequestOption) error {
+	resp, err := c.Delete(ctx, path, opt)
+	if err != nil {
+		return err
+	}
+	return resp.As(result)
+}
+
+// PatchAs 发送 PATCH 请求，按响应的 Content-Type 将响应体反序列化到 result
+func (c *Client) PatchAs(ctx context.Context, path string, result any, opt RequestOption) error {
+	resp, err := c.Patch(ctx, path, opt)
+	if err != nil {
+		return err
+	}
+	return resp.As(result)
+}
@@ -0,0 +1,89 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+type decodeUser struct {
+	ID   int    `json:"id" xml:"id" codec:"id"`
+	Name string `json:"name" xml:"name" codec:"name"`
+}
+
+func TestResultDecode(t *testing.T) {
+	var msgpackBody []byte
+	require.Nil(t, codec.NewEncoderBytes(&msgpackBody, &codec.MsgpackHandle{}).Encode(map[string]any{"id": 3, "name": "carol"}))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xml":
+			w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+			w.Write([]byte(`<user><id>1</id><name>alice</name></user>`))
+		case "/problem":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.Write([]byte(`{"id":2,"name":"bob"}`))
+		case "/msgpack":
+			w.Header().Set("Content-Type", "application/msgpack")
+			w.Write(msgpackBody)
+		case "/form":
+			w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+			w.Write([]byte("id=4&name=dave&name=eve"))
+		case "/csv":
+			w.Header().Set("Content-Type", "text/csv")
+			w.Write([]byte("5,frank"))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL})
+	ctx := context.Background()
+
+	var user decodeUser
+	assert.Nil(t, client.GetAs(ctx, "/xml", &user, RequestOption{}))
+	assert.Equal(t, decodeUser{ID: 1, Name: "alice"}, user)
+	res, err := client.Get(ctx, "/xml", RequestOption{})
+	require.Nil(t, err)
+	user = decodeUser{}
+	assert.Nil(t, res.XML(&user))
+	assert.Equal(t, "alice", user.Name)
+
+	user = decodeUser{}
+	assert.Nil(t, client.GetAs(ctx, "/problem", &user, RequestOption{}))
+	assert.Equal(t, decodeUser{ID: 2, Name: "bob"}, user)
+
+	user = decodeUser{}
+	assert.Nil(t, client.PostAs(ctx, "/msgpack", &user, RequestOption{}))
+	assert.Equal(t, decodeUser{ID: 3, Name: "carol"}, user)
+
+	var values url.Values
+	assert.Nil(t, client.GetAs(ctx, "/form", &values, RequestOption{}))
+	assert.Equal(t, []string{"dave", "eve"}, values["name"])
+	var form map[string]string
+	assert.Nil(t, client.GetAs(ctx, "/form", &form, RequestOption{}))
+	assert.Equal(t, map[string]string{"id": "4", "name": "dave"}, form)
+
+	// 未注册的 Content-Type 返回错误，可通过 Decode 指定解码器或 RegisterDecoder 注册
+	assert.ErrorContains(t, client.GetAs(ctx, "/csv", &user, RequestOption{}), "text/csv")
+	csvDecoder := func(data []byte, v any) error {
+		id, name, _ := strings.Cut(string(data), ",")
+		*v.(*decodeUser) = decodeUser{ID: len(id), Name: name}
+		return nil
+	}
+	res, err = client.Get(ctx, "/csv", RequestOption{})
+	require.Nil(t, err)
+	user = decodeUser{}
+	assert.Nil(t, res.Decode(&user, csvDecoder))
+	assert.Equal(t, "frank", user.Name)
+	require.Nil(t, RegisterDecoder("Text/CSV", csvDecoder))
+	user = decodeUser{}
+	assert.Nil(t, client.GetAs(ctx, "/csv", &user, RequestOption{}))
+	assert.Equal(t, decodeUser{ID: 1, Name: "frank"}, user)
+}
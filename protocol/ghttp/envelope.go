@@ -0,0 +1,53 @@
+package ghttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/morehao/golib/gerror"
+)
+
+// Envelope 内部接口统一的响应包装格式 {code,msg,data}
+type Envelope struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// JSONData 解析响应包装，业务码不等于 successCode 时返回 gerror.Error，否则将 data 反序列化到 v
+func (r *Result) JSONData(v any, successCode int) error {
+	var envelope Envelope
+	if err := r.JSON(&envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal response envelope: %w", err)
+	}
+	if envelope.Code != successCode {
+		return gerror.Error{Code: envelope.Code, Msg: envelope.Msg}
+	}
+	if v == nil || len(envelope.Data) == 0 || bytes.Equal(envelope.Data, []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response data: %w", err)
+	}
+	return nil
+}
+
+// GetJSONData 发送 GET 请求，校验响应包装的业务码并将 data 反序列化到 data
+func (c *Client) GetJSONData(ctx context.Context, path string, data any, opt RequestOption) error {
+	resp, err := c.Get(ctx, path, opt)
+	if err != nil {
+		return err
+	}
+	return resp.JSONData(data, c.SuccessCode)
+}
+
+// PostJSONData 发送 POST 请求，校验响应包装的业务码并将 data 反序列化到 data
+func (c *Client) PostJSONData(ctx context.Context, path string, data any, opt RequestOption) error {
+	resp, err := c.Post(ctx, path, opt)
+	if err != nil {
+		return err
+	}
+	return resp.JSONData(data, c.SuccessCode)
+}
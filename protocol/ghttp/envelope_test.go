@@ -0,0 +1,59 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/gerror"
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte(`{"code":0,"msg":"success","data":{"id":1,"name":"alice"}}`))
+		case "/fail":
+			w.Write([]byte(`{"code":1001,"msg":"user not found","data":null}`))
+		case "/custom":
+			w.Write([]byte(`{"code":200,"msg":"ok","data":{"id":2,"name":"bob"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	type User struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	client := NewClient(&protocol.HttpClientConfig{
+		Module:  "test",
+		Host:    srv.URL,
+		Timeout: 3 * time.Second,
+	})
+	ctx := context.Background()
+
+	var user User
+	err := client.GetJSONData(ctx, "/ok", &user, RequestOption{})
+	assert.Nil(t, err)
+	assert.Equal(t, User{ID: 1, Name: "alice"}, user)
+
+	err = client.PostJSONData(ctx, "/fail", &user, RequestOption{RequestBody: map[string]string{"id": "3"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1001, gerror.GetCode(err))
+	assert.Equal(t, "user not found", gerror.GetMsg(err))
+
+	customClient := NewClient(&protocol.HttpClientConfig{
+		Module:      "test",
+		Host:        srv.URL,
+		Timeout:     3 * time.Second,
+		SuccessCode: 200,
+	})
+	err = customClient.GetJSONData(ctx, "/custom", &user, RequestOption{})
+	assert.Nil(t, err)
+	assert.Equal(t, User{ID: 2, Name: "bob"}, user)
+}
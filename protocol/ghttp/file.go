@@ -0,0 +1,140 @@
+package ghttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UploadFile multipart/form-data 上传的文件
+type UploadFile struct {
+	FieldName   string    // 表单字段名
+	FileName    string    // 文件名
+	Reader      io.Reader // 文件内容，发送时边读边写，实现 io.Closer 时发送结束后关闭
+	ContentType string    // 文件类型，为空时为 application/octet-stream
+}
+
+// isMultipart 是否以 multipart/form-data 发送
+func (opt *RequestOption) isMultipart() bool {
+	return len(opt.Files) > 0 || len(opt.FormFields) > 0
+}
+
+// multipartBody 返回边写边读的 multipart 请求体和 Content-Type，请求体被关闭时停止写入，
+// 返回的描述只包含字段名和文件名，用于日志和审计
+func (opt *RequestOption) multipartBody() (io.ReadCloser, string, []byte) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	fieldNames := make([]string, 0, len(opt.FormFields))
+	for name := range opt.FormFields {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+	files := opt.Files
+
+	go func() {
+		err := writeMultipart(writer, fieldNames, opt.FormFields, files)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	desc := make([]string, 0, len(fieldNames)+len(files))
+	for _, name := range fieldNames {
+		desc = append(desc, name+"="+opt.FormFields[name])
+	}
+	for _, file := range files {
+		desc = append(desc, file.FieldName+"=@"+file.FileName)
+	}
+	return pr, writer.FormDataContentType(), []byte("multipart: " + strings.Join(desc, ", "))
+}
+
+func writeMultipart(writer *multipart.Writer, fieldNames []string, fields map[string]string, files []UploadFile) error {
+	for _, name := range fieldNames {
+		if err := writer.WriteField(name, fields[name]); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		if err := writeMultipartFile(writer, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMultipartFile(writer *multipart.Writer, file UploadFile) error {
+	if closer, ok := file.Reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if file.Reader == nil {
+		return fmt.Errorf("upload file %s reader is nil", file.FileName)
+	}
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeQuotes(file.FieldName), escapeQuotes(file.FileName)))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, file.Reader)
+	return err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// Download 以 GET 请求下载并将响应体边读边写入 w，不将整个响应读入内存，返回写入的字节数。
+// 设置 RequestOption.Progress 时每次写入后回调进度；下载大文件时按需设置 RequestOption.Timeout，超时时间包含读取响应体的时间。
+// 响应状态码大于等于 400 时不写入 w，返回 *HTTPError
+func (c *Client) Download(ctx context.Context, path string, w io.Writer, opt RequestOption) (int64, error) {
+	stream, err := c.GetStream(ctx, path, opt)
+	if stream != nil {
+		defer stream.Close()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	total := int64(-1)
+	if contentLength, parseErr := strconv.ParseInt(stream.Header.Get("Content-Length"), 10, 64); parseErr == nil {
+		total = contentLength
+	}
+	if opt.Progress != nil {
+		w = &progressWriter{w: w, total: total, progress: opt.Progress}
+	}
+	written, err := io.Copy(w, stream)
+	if err != nil {
+		return written, fmt.Errorf("download %s failed: %w", path, err)
+	}
+	return written, nil
+}
+
+// progressWriter 写入后回调累计字节数
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.progress(p.written, p.total)
+	return n, err
+}
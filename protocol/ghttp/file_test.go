@@ -0,0 +1,98 @@
+package ghttp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadFiles(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		w.Write([]byte(r.FormValue("biz") + "|" + header.Filename + "|" + header.Header.Get("Content-Type") + "|" + string(content[:5]) + "|" + strconv.Itoa(len(content))))
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second, MaxRetry: 3})
+	ctx := context.Background()
+	content := strings.Repeat("x", 3<<20)
+	res, err := client.Post(ctx, "/upload", RequestOption{
+		FormFields: map[string]string{"biz": "avatar"},
+		Files: []UploadFile{
+			{FieldName: "file", FileName: `a"b.txt`, Reader: strings.NewReader(content), ContentType: "text/plain"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `avatar|a"b.txt|text/plain|xxxxx|`+strconv.Itoa(3<<20), res.String())
+	assert.Equal(t, 1, calls)
+
+	// 读取文件失败时请求失败且不重试
+	calls = 0
+	_, err = client.Post(ctx, "/upload", RequestOption{
+		Files: []UploadFile{{FieldName: "file", FileName: "bad.txt", Reader: io.MultiReader(strings.NewReader("x"), errReader{})}},
+	})
+	assert.NotNil(t, err)
+	assert.LessOrEqual(t, calls, 1)
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "data.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second})
+	ctx := context.Background()
+	var buf bytes.Buffer
+	var lastWritten, lastTotal int64
+	written, err := client.Download(ctx, "/data.bin", &buf, RequestOption{
+		Progress: func(written, total int64) {
+			lastWritten, lastTotal = written, total
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(content)), written)
+	assert.Equal(t, content, buf.Bytes())
+	assert.Equal(t, int64(len(content)), lastWritten)
+	assert.Equal(t, int64(len(content)), lastTotal)
+
+	buf.Reset()
+	written, err = client.Download(ctx, "/missing", &buf, RequestOption{})
+	var httpErr *HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.HttpCode)
+	assert.Zero(t, written)
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,28 @@
+package ghttp
+
+import "net/http"
+
+// RoundTripFunc 发送一次 HTTP 请求
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware 请求中间件，包装 next 实现鉴权头注入、签名、指标、自定义重试等逻辑
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use 追加请求中间件，先追加的中间件在外层，对之后发起的请求生效，同步请求和流式请求共用。
+// 中间件包在内置重试外层，自定义重试时将 Retry 设为 0 关闭内置重试，重放请求体时使用 req.GetBody
+func (c *Client) Use(mws ...Middleware) {
+	c.mu.Lock()
+	c.middlewares = append(c.middlewares[:len(c.middlewares):len(c.middlewares)], mws...)
+	c.mu.Unlock()
+}
+
+// roundTrip 使用中间件包装 base
+func (c *Client) roundTrip(base RoundTripFunc) RoundTripFunc {
+	c.mu.RLock()
+	mws := c.middlewares
+	c.mu.RUnlock()
+	for i := len(mws) - 1; i >= 0; i-- {
+		base = mws[i](base)
+	}
+	return base
+}
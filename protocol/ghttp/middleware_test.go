@@ -0,0 +1,67 @@
+package ghttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientUse(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		// 前两次返回 503，由自定义重试中间件重试
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization") + "|" + string(body)))
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second})
+	var order []string
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "retry")
+			resp, err := next(req)
+			for i := 0; i < 3 && err == nil && resp.StatusCode >= 500; i++ {
+				resp.Body.Close()
+				if req.GetBody != nil {
+					req.Body, _ = req.GetBody()
+				}
+				resp, err = next(req)
+			}
+			return resp, err
+		}
+	}, func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			order = append(order, "auth")
+			req.Header.Set("Authorization", "Bearer token")
+			return next(req)
+		}
+	})
+
+	ctx := context.Background()
+	res, err := client.Post(ctx, "/users", RequestOption{RequestBody: map[string]string{"name": "foo"}})
+	assert.Nil(t, err)
+	assert.Equal(t, `Bearer token|{"name":"foo"}`, res.String())
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []string{"retry", "auth", "auth", "auth"}, order)
+
+	// 流式请求同样经过中间件
+	order = nil
+	stream, err := client.GetStream(ctx, "/stream", RequestOption{})
+	assert.Nil(t, err)
+	defer stream.Close()
+	content, _ := io.ReadAll(stream)
+	assert.Equal(t, "Bearer token|", string(content))
+	assert.Equal(t, []string{"retry", "auth"}, order)
+}
@@ -0,0 +1,56 @@
+package ghttp
+
+import (
+	"maps"
+	"net/http"
+)
+
+// SetDefaultOption 设置客户端级默认请求选项，只使用其中的 Headers、Cookies、ContentType 和 Timeout，与每次请求的 RequestOption 合并：
+//   - Headers 按规范化后的请求头名合并，Cookies 按名称合并，同名时请求的值优先
+//   - ContentType、Timeout 在请求未设置（为空或 <= 0）时使用默认值，Timeout 仍与 Client.Timeout 取最小值
+//
+// 请求头的最终优先级为：版本协商请求头 < 默认 Headers < 请求 Headers < 链路追踪请求头
+func (c *Client) SetDefaultOption(opt RequestOption) {
+	defaultOpt := &RequestOption{
+		Headers:     make(map[string]string, len(opt.Headers)),
+		Cookies:     maps.Clone(opt.Cookies),
+		ContentType: opt.ContentType,
+		Timeout:     opt.Timeout,
+	}
+	for k, v := range opt.Headers {
+		defaultOpt.Headers[http.CanonicalHeaderKey(k)] = v
+	}
+	c.mu.Lock()
+	c.defaultOption = defaultOpt
+	c.mu.Unlock()
+}
+
+// mergeOption 将客户端级默认请求选项合并到 opt，不修改调用方传入的 map
+func (c *Client) mergeOption(opt RequestOption) RequestOption {
+	c.mu.RLock()
+	defaultOpt := c.defaultOption
+	c.mu.RUnlock()
+	if defaultOpt == nil {
+		return opt
+	}
+
+	if len(defaultOpt.Headers) > 0 {
+		headers := maps.Clone(defaultOpt.Headers)
+		for k, v := range opt.Headers {
+			headers[http.CanonicalHeaderKey(k)] = v
+		}
+		opt.Headers = headers
+	}
+	if len(defaultOpt.Cookies) > 0 {
+		cookies := maps.Clone(defaultOpt.Cookies)
+		maps.Copy(cookies, opt.Cookies)
+		opt.Cookies = cookies
+	}
+	if opt.ContentType == "" {
+		opt.ContentType = defaultOpt.ContentType
+	}
+	if opt.Timeout <= 0 {
+		opt.Timeout = defaultOpt.Timeout
+	}
+	return opt
+}
@@ -0,0 +1,53 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientDefaultOption(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		tenant, _ := r.Cookie("tenant")
+		w.Write([]byte(r.Header.Get("Authorization") + "|" + r.Header.Get("X-App") + "|" + r.Header.Get("Content-Type") + "|" + tenant.Value))
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second})
+	client.SetDefaultOption(RequestOption{
+		Headers:     map[string]string{"authorization": "Bearer default", "X-App": "demo"},
+		Cookies:     map[string]string{"tenant": "t1"},
+		ContentType: "application/x-www-form-urlencoded",
+		Timeout:     100 * time.Millisecond,
+	})
+
+	ctx := context.Background()
+	res, err := client.Post(ctx, "/users", RequestOption{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer default|demo|application/x-www-form-urlencoded|t1", res.String())
+
+	// 请求的值优先，请求头名大小写不同也视为同一个
+	headers := map[string]string{"AUTHORIZATION": "Bearer call"}
+	res, err = client.Post(ctx, "/users", RequestOption{
+		Headers:     headers,
+		Cookies:     map[string]string{"tenant": "t2"},
+		ContentType: "application/json",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer call|demo|application/json|t2", res.String())
+	assert.Equal(t, map[string]string{"AUTHORIZATION": "Bearer call"}, headers)
+
+	// 默认超时生效，请求设置的超时优先
+	_, err = client.Get(ctx, "/slow", RequestOption{})
+	assert.NotNil(t, err)
+	_, err = client.Get(ctx, "/slow", RequestOption{Timeout: time.Second})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,118 @@
+package ghttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/morehao/golib/glog"
+)
+
+const defaultMaxRedirects = 10
+
+var defaultRedirectPolicy = NewRedirectPolicy()
+
+var (
+	// ErrTooManyRedirects 重定向次数超出 RedirectPolicy 的上限
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrRedirectForbidden 重定向被 RedirectPolicy 拒绝，如跨主机重定向
+	ErrRedirectForbidden = errors.New("redirect forbidden")
+)
+
+// AuthorizationMode 重定向时 Authorization 请求头的处理方式
+type AuthorizationMode uint8
+
+const (
+	// AuthorizationDefault 与标准库一致：同域名或子域名时保留，跳转到其他域名时移除
+	AuthorizationDefault AuthorizationMode = iota
+	// AuthorizationStrip 任意重定向都移除 Authorization
+	AuthorizationStrip
+	// AuthorizationPreserve 任意重定向都保留 Authorization，仅用于可信的跨域跳转，建议同时限制跨主机重定向
+	AuthorizationPreserve
+)
+
+// RedirectPolicy 3xx 重定向策略，每一跳都会输出 http redirect 日志，便于排查认证请求被跳转的问题
+type RedirectPolicy struct {
+	maxRedirects    int
+	forbidCrossHost bool
+	authMode        AuthorizationMode
+}
+
+// RedirectOption 重定向策略选项
+type RedirectOption func(*RedirectPolicy)
+
+// WithMaxRedirects 设置最大重定向次数，默认 10；< 0 时不跟随重定向，直接返回 3xx 响应
+func WithMaxRedirects(n int) RedirectOption {
+	return func(p *RedirectPolicy) {
+		p.maxRedirects = n
+	}
+}
+
+// WithForbidCrossHost 禁止跳转到与原始请求不同的主机（含端口），避免请求头和令牌泄露到第三方
+func WithForbidCrossHost() RedirectOption {
+	return func(p *RedirectPolicy) {
+		p.forbidCrossHost = true
+	}
+}
+
+// WithAuthorizationMode 设置重定向时 Authorization 请求头的处理方式，默认 AuthorizationDefault
+func WithAuthorizationMode(mode AuthorizationMode) RedirectOption {
+	return func(p *RedirectPolicy) {
+		p.authMode = mode
+	}
+}
+
+// NewRedirectPolicy 创建重定向策略
+func NewRedirectPolicy(opts ...RedirectOption) *RedirectPolicy {
+	p := &RedirectPolicy{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.maxRedirects == 0 {
+		p.maxRedirects = defaultMaxRedirects
+	}
+	return p
+}
+
+// check 实现 http.Client.CheckRedirect，req 为下一跳请求，via 为已发出的请求，via[0] 为原始请求
+func (p *RedirectPolicy) check(service string, req *http.Request, via []*http.Request) error {
+	if p.maxRedirects < 0 {
+		return http.ErrUseLastResponse
+	}
+	origin, prev := via[0], via[len(via)-1]
+	fields := []any{
+		glog.KV(glog.KeyService, service),
+		glog.KV("http.redirect.from", prev.URL.String()),
+		glog.KV("http.redirect.to", req.URL.String()),
+		glog.KV("http.redirect.hop", len(via)),
+	}
+	if req.Response != nil {
+		fields = append(fields, glog.KV(glog.KeyHttpResponseStatusCode, req.Response.StatusCode))
+	}
+	ctx := req.Context()
+	if len(via) > p.maxRedirects {
+		glog.Warnw(ctx, "http redirect rejected: too many redirects", fields...)
+		return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, p.maxRedirects)
+	}
+	if p.forbidCrossHost && req.URL.Host != origin.URL.Host {
+		glog.Warnw(ctx, "http redirect rejected: cross host", fields...)
+		return fmt.Errorf("%w: cross host redirect from %s to %s", ErrRedirectForbidden, origin.URL.Host, req.URL.Host)
+	}
+
+	// 标准库在调用 CheckRedirect 前已按默认规则复制请求头，这里只需按策略覆盖 Authorization
+	switch p.authMode {
+	case AuthorizationStrip:
+		req.Header.Del("Authorization")
+	case AuthorizationPreserve:
+		if auth := origin.Header.Get("Authorization"); auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+	}
+	glog.Infow(ctx, "http redirect", fields...)
+	return nil
+}
+
+// isRedirectPolicyError 判断请求是否因重定向策略失败，此类错误重试也不会成功
+func isRedirectPolicyError(err error) bool {
+	return errors.Is(err, ErrTooManyRedirects) || errors.Is(err, ErrRedirectForbidden)
+}
@@ -0,0 +1,92 @@
+package ghttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedirectServer 创建测试服务：/hop/N 跳转到 /hop/N-1，/hop/0 返回收到的 Authorization，/away 跳转到 target
+func newRedirectServer(t *testing.T, target string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/away":
+			http.Redirect(w, r, target, http.StatusFound)
+		case r.URL.Path == "/hop/0" || r.URL.Path == "/final":
+			_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+		default:
+			n, _ := strconv.Atoi(r.URL.Path[len("/hop/"):])
+			http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), http.StatusMovedPermanently)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newRedirectClient(host string, opts ...RedirectOption) *Client {
+	client := NewClient(&protocol.HttpClientConfig{Module: "redirect", Host: host, Timeout: 5 * time.Second, MaxRetry: 3})
+	if len(opts) > 0 {
+		client.SetRedirectPolicy(NewRedirectPolicy(opts...))
+	}
+	return client
+}
+
+func TestRedirectMaxRedirects(t *testing.T) {
+	srv := newRedirectServer(t, "")
+	ctx := context.Background()
+
+	res, err := newRedirectClient(srv.URL, WithMaxRedirects(3)).Get(ctx, "/hop/3", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.HttpCode)
+
+	_, err = newRedirectClient(srv.URL, WithMaxRedirects(2)).Get(ctx, "/hop/3", RequestOption{})
+	assert.True(t, errors.Is(err, ErrTooManyRedirects))
+
+	// 不跟随重定向时返回 3xx 响应
+	res, err = newRedirectClient(srv.URL, WithMaxRedirects(-1)).Get(ctx, "/hop/3", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, res.HttpCode)
+	assert.Equal(t, "/hop/2", res.Header.Get("Location"))
+}
+
+func TestRedirectCrossHost(t *testing.T) {
+	other := newRedirectServer(t, "")
+	// 使用不同的主机名，标准库比较域名时忽略端口
+	srv := newRedirectServer(t, strings.Replace(other.URL, "127.0.0.1", "localhost", 1)+"/final")
+	ctx := context.Background()
+	opt := RequestOption{Headers: map[string]string{"Authorization": "Bearer token"}}
+
+	// 默认策略下跨主机跳转时标准库会移除 Authorization
+	res, err := newRedirectClient(srv.URL).Get(ctx, "/away", opt)
+	require.Nil(t, err)
+	assert.Empty(t, res.String())
+
+	res, err = newRedirectClient(srv.URL, WithAuthorizationMode(AuthorizationPreserve)).Get(ctx, "/away", opt)
+	require.Nil(t, err)
+	assert.Equal(t, "Bearer token", res.String())
+
+	_, err = newRedirectClient(srv.URL, WithForbidCrossHost()).Get(ctx, "/away", opt)
+	assert.True(t, errors.Is(err, ErrRedirectForbidden))
+
+	// 同主机跳转不受跨主机限制
+	res, err = newRedirectClient(srv.URL, WithForbidCrossHost()).Get(ctx, "/hop/1", opt)
+	require.Nil(t, err)
+	assert.Equal(t, "Bearer token", res.String())
+}
+
+func TestRedirectStripAuthorization(t *testing.T) {
+	srv := newRedirectServer(t, "")
+	opt := RequestOption{Headers: map[string]string{"Authorization": "Bearer token"}}
+	res, err := newRedirectClient(srv.URL, WithAuthorizationMode(AuthorizationStrip)).Get(context.Background(), "/hop/1", opt)
+	require.Nil(t, err)
+	assert.Empty(t, res.String())
+}
@@ -0,0 +1,238 @@
+package ghttp
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/protocol"
+)
+
+const (
+	defaultRetryBaseDelay     = 100 * time.Millisecond
+	defaultRetryMaxDelay      = time.Second
+	defaultRetryJitter        = 0.2
+	defaultRetryMaxRetryAfter = 10 * time.Second
+)
+
+// BackoffStrategy 重试等待时间的计算方式
+type BackoffStrategy uint8
+
+const (
+	// BackoffExponential 指数退避，第 n 次重试等待 baseDelay * 2^(n-1)
+	BackoffExponential BackoffStrategy = iota
+	// BackoffLinear 线性退避，第 n 次重试等待 baseDelay * n
+	BackoffLinear
+	// BackoffConstant 固定等待 baseDelay
+	BackoffConstant
+)
+
+// RetryPolicy 请求重试策略。默认只在网络错误时重试，可按状态码重试，并遵循响应的 Retry-After；
+// 重放请求体使用 req.GetBody，无法重放的请求体（如 multipart 上传）不重试，等待期间 ctx 取消时立即返回
+type RetryPolicy struct {
+	maxAttempts   int
+	strategy      BackoffStrategy
+	baseDelay     time.Duration
+	maxDelay      time.Duration
+	jitter        float64
+	retryStatus   map[int]bool
+	retryIf       func(resp *http.Response, err error) bool
+	maxRetryAfter time.Duration
+}
+
+// RetryOption 重试策略选项
+type RetryOption func(*RetryPolicy)
+
+// WithMaxAttempts 设置最大请求次数（含首次请求），<= 1 时不重试，默认使用 Client.Retry
+func WithMaxAttempts(n int) RetryOption {
+	return func(p *RetryPolicy) {
+		p.maxAttempts = n
+	}
+}
+
+// WithBackoff 设置退避方式、基础等待时间和最大等待时间，默认指数退避，100ms 起，最多 1s
+func WithBackoff(strategy BackoffStrategy, baseDelay, maxDelay time.Duration) RetryOption {
+	return func(p *RetryPolicy) {
+		p.strategy = strategy
+		p.baseDelay = baseDelay
+		p.maxDelay = maxDelay
+	}
+}
+
+// WithJitter 设置等待时间的随机抖动比例，取值 [0, 1]，等待时间在 delay*(1±jitter) 内随机，默认 0.2
+func WithJitter(jitter float64) RetryOption {
+	return func(p *RetryPolicy) {
+		p.jitter = min(max(jitter, 0), 1)
+	}
+}
+
+// WithRetryStatus 设置需要重试的响应状态码，如 429、502、503，默认不按状态码重试
+func WithRetryStatus(codes ...int) RetryOption {
+	return func(p *RetryPolicy) {
+		for _, code := range codes {
+			p.retryStatus[code] = true
+		}
+	}
+}
+
+// WithRetryIf 自定义是否重试，设置后替代默认判断（网络错误或命中 WithRetryStatus 的状态码）；
+// 每次请求后调用，resp 和 err 与 http.Client.Do 的返回值相同，重定向策略错误和 ctx 取消始终不重试
+func WithRetryIf(fn func(resp *http.Response, err error) bool) RetryOption {
+	return func(p *RetryPolicy) {
+		p.retryIf = fn
+	}
+}
+
+// WithMaxRetryAfter 设置可接受的 Retry-After 上限，默认 10s，超出时不再重试直接返回响应；< 0 时忽略 Retry-After
+func WithMaxRetryAfter(d time.Duration) RetryOption {
+	return func(p *RetryPolicy) {
+		p.maxRetryAfter = d
+	}
+}
+
+// NewRetryPolicy 创建重试策略
+func NewRetryPolicy(opts ...RetryOption) *RetryPolicy {
+	p := &RetryPolicy{
+		baseDelay:     defaultRetryBaseDelay,
+		maxDelay:      defaultRetryMaxDelay,
+		jitter:        defaultRetryJitter,
+		retryStatus:   make(map[int]bool),
+		maxRetryAfter: defaultRetryMaxRetryAfter,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.maxDelay < p.baseDelay {
+		p.maxDelay = p.baseDelay
+	}
+	return p
+}
+
+// SetRetryPolicy 设置重试策略，未设置时按 Client.Retry 次数在网络错误时重试
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.mu.Lock()
+	c.retryPolicy = policy
+	c.mu.Unlock()
+}
+
+func (c *Client) getRetryPolicy() (*RetryPolicy, int) {
+	c.mu.RLock()
+	policy, retry := c.retryPolicy, c.Retry
+	c.mu.RUnlock()
+	if policy == nil {
+		policy = defaultRetryPolicy
+	}
+	maxAttempts := policy.maxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = retry
+	}
+	return policy, max(maxAttempts, 1)
+}
+
+var defaultRetryPolicy = NewRetryPolicy()
+
+// shouldRetry 判断本次请求结果是否需要重试
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil || isRedirectPolicyError(err) {
+		return false
+	}
+	if p.retryIf != nil {
+		return p.retryIf(resp, err)
+	}
+	return err != nil || p.retryStatus[resp.StatusCode]
+}
+
+// delay 返回第 attempt 次重试前的等待时间，响应带有 Retry-After 时优先使用，超出上限时返回 false
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil && p.maxRetryAfter >= 0 {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d, d <= p.maxRetryAfter
+		}
+	}
+	var d time.Duration
+	switch p.strategy {
+	case BackoffLinear:
+		d = p.baseDelay * time.Duration(attempt)
+	case BackoffConstant:
+		d = p.baseDelay
+	default:
+		d = p.baseDelay << min(attempt-1, 30)
+	}
+	if d > p.maxDelay || d <= 0 {
+		d = p.maxDelay
+	}
+	if p.jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.jitter * float64(d))
+	}
+	return d, true
+}
+
+// parseRetryAfter 解析以秒数或 HTTP 日期表示的 Retry-After
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// retryRoundTrip 按重试策略发送请求，maxDecompressedSize 为解压后响应体的大小上限，0 表示不限制
+func (c *Client) retryRoundTrip(httpClient *http.Client, logPrefix string, maxDecompressedSize int64) RoundTripFunc {
+	policy, maxAttempts := c.getRetryPolicy()
+	signer := c.getSigner()
+	return func(request *http.Request) (*http.Response, error) {
+		ctx := request.Context()
+		attempts := maxAttempts
+		if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+			attempts = 1
+		}
+
+		for i := 1; ; i++ {
+			// 每次发送前重新签名，重试时使用新的时间戳和随机串
+			if signer != nil {
+				if err := signRequest(signer, request); err != nil {
+					return nil, err
+				}
+			}
+			resp, err := httpClient.Do(request)
+			resp, err = decompressResponse(resp, err, maxDecompressedSize)
+			if i >= attempts || !policy.shouldRetry(request, resp, err) {
+				return resp, err
+			}
+			delay, ok := policy.delay(i, resp)
+			if !ok {
+				return resp, err
+			}
+			if request.GetBody != nil {
+				body, bodyErr := request.GetBody()
+				if bodyErr != nil {
+					return resp, err
+				}
+				request.Body = body
+			}
+
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+				resp.Body.Close()
+			}
+			protocol.RecordRetry(c.Service)
+			glog.Warnf(ctx, "%s retry %d/%d after %s, status: %d, error: %v", logPrefix, i, attempts-1, delay, status, err)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
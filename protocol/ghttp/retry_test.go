@@ -0,0 +1,93 @@
+package ghttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := NewRetryPolicy(WithBackoff(BackoffExponential, 100*time.Millisecond, 500*time.Millisecond), WithJitter(0))
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 4: 500 * time.Millisecond, 100: 500 * time.Millisecond} {
+		d, ok := p.delay(attempt, nil)
+		assert.True(t, ok)
+		assert.Equal(t, want, d)
+	}
+
+	p = NewRetryPolicy(WithBackoff(BackoffLinear, 100*time.Millisecond, time.Second), WithJitter(0.5))
+	for i := 0; i < 20; i++ {
+		d, _ := p.delay(3, nil)
+		assert.True(t, d >= 150*time.Millisecond && d <= 450*time.Millisecond, d)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}
+	d, ok := p.delay(1, resp)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, d)
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	_, ok = p.delay(1, resp)
+	assert.False(t, ok)
+	_, ok = NewRetryPolicy(WithMaxRetryAfter(-1)).delay(1, resp)
+	assert.True(t, ok)
+}
+
+func TestClientRetryPolicy(t *testing.T) {
+	var calls int
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.URL.Path == "/busy" || calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second})
+	client.SetRetryPolicy(NewRetryPolicy(
+		WithMaxAttempts(3),
+		WithRetryStatus(http.StatusServiceUnavailable),
+		WithBackoff(BackoffConstant, 10*time.Millisecond, 10*time.Millisecond),
+	))
+
+	// POST 重试时重放请求体
+	ctx := context.Background()
+	res, err := client.Post(ctx, "/users", RequestOption{RequestBody: `{"name":"foo"}`})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"foo"}`, res.String())
+	assert.Equal(t, []string{`{"name":"foo"}`, `{"name":"foo"}`, `{"name":"foo"}`}, bodies)
+
+	// 超出次数后返回最后一次响应
+	calls = 0
+	res, err = client.Get(ctx, "/busy", RequestOption{})
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.HttpCode)
+	assert.Equal(t, 3, calls)
+
+	// 等待重试期间 ctx 取消时立即返回
+	client.SetRetryPolicy(NewRetryPolicy(
+		WithMaxAttempts(3),
+		WithRetryStatus(http.StatusServiceUnavailable),
+		WithBackoff(BackoffConstant, time.Second, time.Second),
+		WithMaxRetryAfter(-1),
+	))
+	calls = 0
+	cancelCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.Get(cancelCtx, "/busy", RequestOption{})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, 1, calls)
+}
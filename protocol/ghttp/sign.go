@@ -0,0 +1,236 @@
+package ghttp
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/morehao/golib/gcrypto"
+)
+
+// HMACSigner 写入的请求头
+const (
+	HeaderAccessKey     = "X-Access-Key"
+	HeaderTimestamp     = "X-Timestamp"
+	HeaderNonce         = "X-Nonce"
+	HeaderSignedHeaders = "X-Signed-Headers"
+	HeaderSignature     = "X-Signature"
+	HeaderContentSHA256 = "X-Content-Sha256"
+)
+
+// unsignedPayload 请求体无法重放读取时代替请求体摘要参与签名
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// Signer 请求签名器，每次发送请求前（包括每次重试）调用，在请求头中写入签名。
+// body 为实际发送的请求体（开启 CompressBody 时为压缩后的数据），没有请求体或请求体无法重放读取（如 multipart 上传）时为 nil
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// SignerFunc 函数形式的 Signer
+type SignerFunc func(req *http.Request, body []byte) error
+
+// Sign 实现 Signer 接口
+func (f SignerFunc) Sign(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// HMACSigner 基于 HMAC-SHA256 的请求签名，签名串由以下各行以 \n 连接：
+//
+//	请求方法
+//	转义后的路径，为空时为 /
+//	按参数名、参数值排序的查询参数
+//	SignedHeaders 中各请求头的 小写名称:去除首尾空白的值，按名称排序，每个一行
+//	以 ; 连接的小写 SignedHeaders
+//	X-Timestamp 秒级时间戳
+//	X-Nonce 随机串
+//	请求体 SHA-256 的十六进制，请求体无法重放读取时为 UNSIGNED-PAYLOAD
+//
+// 签名为签名串的 HMAC-SHA256 十六进制，写入 X-Signature，同时写入 X-Access-Key、X-Timestamp、X-Nonce、X-Signed-Headers
+// 和参与签名的请求体摘要 X-Content-Sha256。
+// 服务端调用 Verify 按同样规则校验签名，并校验时间戳范围和 nonce 是否重复以防重放
+type HMACSigner struct {
+	AccessKey     string
+	SecretKey     string
+	SignedHeaders []string         // 参与签名的请求头，如 Content-Type、X-Request-Id，缺少的请求头按空值签名
+	Now           func() time.Time // 获取当前时间，默认 time.Now
+	Nonce         func() string    // 生成随机串，默认 16 字节随机数的十六进制
+	// AllowUnsignedPayload 服务端校验时是否接受请求体以 UNSIGNED-PAYLOAD 签名的请求（如 multipart 上传），
+	// 接受时请求体不受签名保护，默认不接受
+	AllowUnsignedPayload bool
+}
+
+// NewHMACSigner 创建 HMAC-SHA256 签名器
+func NewHMACSigner(accessKey, secretKey string, signedHeaders ...string) *HMACSigner {
+	return &HMACSigner{
+		AccessKey:     accessKey,
+		SecretKey:     secretKey,
+		SignedHeaders: signedHeaders,
+	}
+}
+
+// Sign 实现 Signer 接口
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	if s.SecretKey == "" {
+		return errors.New("hmac signer: secret key is required")
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	nonce := ""
+	if s.Nonce != nil {
+		nonce = s.Nonce()
+	} else {
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return fmt.Errorf("hmac signer: generate nonce: %w", err)
+		}
+		nonce = hex.EncodeToString(b[:])
+	}
+	timestamp := strconv.FormatInt(now().Unix(), 10)
+
+	req.Header.Set(HeaderAccessKey, s.AccessKey)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	if signedHeaders := s.signedHeaderNames(); len(signedHeaders) > 0 {
+		req.Header.Set(HeaderSignedHeaders, strings.Join(signedHeaders, ";"))
+	} else {
+		req.Header.Del(HeaderSignedHeaders)
+	}
+	hash := payloadHash(req, body)
+	req.Header.Set(HeaderContentSHA256, hash)
+	req.Header.Set(HeaderSignature, hex.EncodeToString(s.sum(req, hash, timestamp, nonce)))
+	return nil
+}
+
+// Signature 计算请求的签名，body 为 nil 时按空请求体计算，服务端校验时传入请求头中的时间戳和随机串
+func (s *HMACSigner) Signature(req *http.Request, body []byte, timestamp, nonce string) string {
+	return hex.EncodeToString(s.sum(req, bodyHash(body), timestamp, nonce))
+}
+
+// Verify 按请求头中的时间戳和随机串重新计算签名，并以常量时间与 X-Signature 比较，不校验时间戳范围和 nonce 是否重复。
+// body 为服务端读取到的完整请求体，nil 按空请求体校验；X-Content-Sha256 为 UNSIGNED-PAYLOAD 时仅在 AllowUnsignedPayload 为 true 时通过
+func (s *HMACSigner) Verify(req *http.Request, body []byte) bool {
+	signature, err := hex.DecodeString(req.Header.Get(HeaderSignature))
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+	hash := bodyHash(body)
+	if req.Header.Get(HeaderContentSHA256) == unsignedPayload {
+		if !s.AllowUnsignedPayload {
+			return false
+		}
+		hash = unsignedPayload
+	}
+	timestamp, nonce := req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderNonce)
+	return gcrypto.VerifyHMACSHA256([]byte(s.SecretKey), []byte(s.canonicalRequest(req, hash, timestamp, nonce)), signature)
+}
+
+func (s *HMACSigner) sum(req *http.Request, payloadHash, timestamp, nonce string) []byte {
+	return gcrypto.HMACSHA256([]byte(s.SecretKey), []byte(s.canonicalRequest(req, payloadHash, timestamp, nonce)))
+}
+
+func (s *HMACSigner) canonicalRequest(req *http.Request, payloadHash, timestamp, nonce string) string {
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	signedHeaders := s.signedHeaderNames()
+	lines := []string{req.Method, path, canonicalQuery(req.URL.Query())}
+	for _, name := range signedHeaders {
+		lines = append(lines, name+":"+strings.TrimSpace(req.Header.Get(name)))
+	}
+	lines = append(lines, strings.Join(signedHeaders, ";"), timestamp, nonce, payloadHash)
+	return strings.Join(lines, "\n")
+}
+
+// signedHeaderNames 返回去重、排序后的小写请求头名称
+func (s *HMACSigner) signedHeaderNames() []string {
+	names := make([]string, 0, len(s.SignedHeaders))
+	seen := make(map[string]bool, len(s.SignedHeaders))
+	for _, name := range s.SignedHeaders {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// canonicalQuery 按参数名、参数值排序编码查询参数，空格编码为 %20
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, url.PathEscape(key)+"="+url.PathEscape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// payloadHash 返回客户端请求参与签名的请求体摘要，请求体无法重放读取时为 UNSIGNED-PAYLOAD
+func payloadHash(req *http.Request, body []byte) string {
+	if body == nil && req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return unsignedPayload
+	}
+	return bodyHash(body)
+}
+
+// bodyHash 返回请求体 SHA-256 的十六进制，nil 按空请求体计算
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetSigner 设置请求签名器，每次发送请求前（包括每次重试）重新签名，签名在中间件之后执行，中间件写入的请求头也可参与签名
+func (c *Client) SetSigner(signer Signer) {
+	c.mu.Lock()
+	c.signer = signer
+	c.mu.Unlock()
+}
+
+func (c *Client) getSigner() Signer {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.signer
+}
+
+// signRequest 读取可重放的请求体并签名
+func signRequest(signer Signer, req *http.Request) error {
+	var body []byte
+	if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+		rc, err := req.GetBody()
+		if err != nil {
+			return fmt.Errorf("sign request: %w", err)
+		}
+		body, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("sign request: %w", err)
+		}
+	}
+	if err := signer.Sign(req, body); err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package ghttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACSigner(t *testing.T) {
+	signer := NewHMACSigner("ak", "sk", "Content-Type", "x-tenant")
+	var (
+		calls  int
+		nonces []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		nonces = append(nonces, r.Header.Get(HeaderNonce))
+		assert.Equal(t, "ak", r.Header.Get(HeaderAccessKey))
+		assert.Equal(t, "content-type;x-tenant", r.Header.Get(HeaderSignedHeaders))
+		ts, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), time.Unix(ts, 0), time.Minute)
+
+		want := signer.Signature(r, body, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderNonce))
+		assert.Equal(t, want, r.Header.Get(HeaderSignature))
+		if !signer.Verify(r, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// 首次请求返回 503，重试时重新签名
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second})
+	client.SetRetryPolicy(NewRetryPolicy(WithMaxAttempts(2), WithRetryStatus(http.StatusServiceUnavailable), WithBackoff(BackoffConstant, time.Millisecond, time.Millisecond)))
+	client.SetSigner(signer)
+	// 中间件写入的请求头参与签名
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Tenant", "t1")
+			return next(req)
+		}
+	})
+
+	ctx := context.Background()
+	res, err := client.Post(ctx, "/orders?b=2&a=1%202", RequestOption{RequestBody: map[string]string{"name": "foo"}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.HttpCode)
+	assert.Equal(t, 2, calls)
+	require.Len(t, nonces, 2)
+	assert.NotEqual(t, nonces[0], nonces[1])
+
+	res, err = client.Get(ctx, "/orders", RequestOption{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.HttpCode)
+
+	// 签名串
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com?b=2&a=x%20y&a=1", nil)
+	req.Header.Set("Content-Type", " application/json ")
+	canonical := signer.canonicalRequest(req, bodyHash(nil), "1700000000", "n1")
+	assert.Equal(t, "GET\n/\na=1&a=x%20y&b=2\ncontent-type:application/json\nx-tenant:\ncontent-type;x-tenant\n1700000000\nn1\n"+
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", canonical)
+
+	// 请求体、签名被篡改或密钥不同时校验失败
+	req, _ = http.NewRequest(http.MethodPost, "http://example.com/orders", nil)
+	require.NoError(t, signer.Sign(req, []byte(`{"id":1}`)))
+	assert.True(t, signer.Verify(req, []byte(`{"id":1}`)))
+	assert.False(t, signer.Verify(req, []byte(`{"id":2}`)))
+	assert.False(t, NewHMACSigner("ak", "other").Verify(req, []byte(`{"id":1}`)))
+	req.Header.Set(HeaderSignature, "not-hex")
+	assert.False(t, signer.Verify(req, []byte(`{"id":1}`)))
+	req.Header.Del(HeaderSignature)
+	assert.False(t, signer.Verify(req, []byte(`{"id":1}`)))
+
+	// 缺少密钥时返回错误，不发送请求
+	calls = 0
+	client.SetSigner(NewHMACSigner("ak", ""))
+	_, err = client.Get(ctx, "/orders", RequestOption{})
+	assert.ErrorContains(t, err, "secret key is required")
+	assert.Equal(t, 0, calls)
+}
+
+func TestHMACSignerVerifyServer(t *testing.T) {
+	client := NewHMACSigner("ak", "sk", "Content-Type")
+	server := NewHMACSigner("ak", "sk", "Content-Type")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 服务端收到的请求 Body 非空且 GetBody 为 nil，GET 不读取请求体直接以 nil 校验
+		var body []byte
+		if r.Method != http.MethodGet && r.Header.Get(HeaderContentSHA256) != unsignedPayload {
+			body, _ = io.ReadAll(r.Body)
+		}
+		if !server.Verify(r, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second})
+	c.SetSigner(client)
+	ctx := context.Background()
+
+	res, err := c.Get(ctx, "/orders?id=1", RequestOption{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.HttpCode)
+
+	res, err = c.Post(ctx, "/orders", RequestOption{RequestBody: map[string]string{"name": "foo"}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.HttpCode)
+
+	// multipart 请求体无法重放读取，以 UNSIGNED-PAYLOAD 签名，服务端需显式接受
+	upload := RequestOption{Files: []UploadFile{{FieldName: "file", FileName: "a.txt", Reader: strings.NewReader("content")}}}
+	_, err = c.Post(ctx, "/upload", upload)
+	assert.ErrorContains(t, err, "status=401")
+	server.AllowUnsignedPayload = true
+	upload.Files[0].Reader = strings.NewReader("content")
+	res, err = c.Post(ctx, "/upload", upload)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.HttpCode)
+}
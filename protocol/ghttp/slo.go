@@ -0,0 +1,180 @@
+package ghttp
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/morehao/golib/glog"
+)
+
+// SlowCall 超出延迟预算的一次请求
+type SlowCall struct {
+	Service    string
+	Method     string
+	Path       string
+	Pattern    string // 命中的路径模式
+	StatusCode int
+	Budget     time.Duration
+	Duration   time.Duration
+}
+
+// SLOStats 单个路径模式的调用统计
+type SLOStats struct {
+	Pattern string
+	Budget  time.Duration
+	Total   uint64 // 命中该模式的请求数
+	Slow    uint64 // 超出预算的请求数
+}
+
+// SLOTracker 按路径模式登记下游接口的延迟预算，请求耗时超出预算时输出带 http.slow 标记的 WARN 日志
+// 并累加慢调用计数，用于在下游性能退化时尽早发现问题。
+type SLOTracker struct {
+	rules         []*sloRule
+	defaultBudget time.Duration
+	onSlow        func(ctx context.Context, call *SlowCall)
+}
+
+type sloRule struct {
+	pattern  string
+	segments []string
+	prefix   bool
+	budget   time.Duration
+	total    atomic.Uint64
+	slow     atomic.Uint64
+}
+
+// SLOOption SLO 跟踪器选项
+type SLOOption func(*SLOTracker)
+
+// WithSLOBudget 登记路径模式的延迟预算，多个模式同时命中时先登记的优先。
+// 模式支持精确路径（/users）、":name" 匹配单段路径（/users/:id）以及 "*" 结尾的前缀匹配（/pay/*）
+func WithSLOBudget(pattern string, budget time.Duration) SLOOption {
+	return func(t *SLOTracker) {
+		rule := &sloRule{pattern: pattern, budget: budget}
+		p := pattern
+		if trimmed, ok := strings.CutSuffix(p, "*"); ok {
+			rule.prefix = true
+			p = trimmed
+		}
+		rule.segments = strings.Split(p, "/")
+		t.rules = append(t.rules, rule)
+	}
+}
+
+// WithSLODefaultBudget 设置未登记路径的默认延迟预算，<= 0 时不跟踪未登记的路径
+func WithSLODefaultBudget(budget time.Duration) SLOOption {
+	return func(t *SLOTracker) {
+		t.defaultBudget = budget
+	}
+}
+
+// WithSLOSlowHook 设置慢调用回调，可用于上报 Prometheus 等外部监控，回调在请求协程中同步执行
+func WithSLOSlowHook(fn func(ctx context.Context, call *SlowCall)) SLOOption {
+	return func(t *SLOTracker) {
+		t.onSlow = fn
+	}
+}
+
+// NewSLOTracker 创建 SLO 跟踪器
+func NewSLOTracker(opts ...SLOOption) *SLOTracker {
+	t := &SLOTracker{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.rules = append(t.rules, &sloRule{pattern: "*", prefix: true, segments: []string{""}, budget: t.defaultBudget})
+	return t
+}
+
+// Stats 返回各路径模式的调用统计，按登记顺序排列，未登记路径的统计以 "*" 表示
+func (t *SLOTracker) Stats() []SLOStats {
+	stats := make([]SLOStats, 0, len(t.rules))
+	for _, rule := range t.rules {
+		if rule.budget <= 0 {
+			continue
+		}
+		stats = append(stats, SLOStats{
+			Pattern: rule.pattern,
+			Budget:  rule.budget,
+			Total:   rule.total.Load(),
+			Slow:    rule.slow.Load(),
+		})
+	}
+	return stats
+}
+
+// SlowCalls 返回所有路径模式的慢调用总数
+func (t *SLOTracker) SlowCalls() uint64 {
+	var total uint64
+	for _, rule := range t.rules {
+		total += rule.slow.Load()
+	}
+	return total
+}
+
+// observe 记录一次请求耗时，超出预算时输出告警日志并触发回调
+func (t *SLOTracker) observe(ctx context.Context, call *SlowCall) {
+	rule := t.match(call.Path)
+	if rule == nil || rule.budget <= 0 {
+		return
+	}
+	rule.total.Add(1)
+	if call.Duration <= rule.budget {
+		return
+	}
+	rule.slow.Add(1)
+	call.Pattern = rule.pattern
+	call.Budget = rule.budget
+
+	glog.Warnw(ctx, "http slow call",
+		glog.KV(glog.KeyHttpSlow, true),
+		glog.KV(glog.KeyService, call.Service),
+		glog.KV(glog.KeyHttpRequestMethod, call.Method),
+		glog.KV(glog.KeyUrlPath, call.Path),
+		glog.KV(glog.KeyHttpRoute, call.Pattern),
+		glog.KV(glog.KeyHttpResponseStatusCode, call.StatusCode),
+		glog.KV(glog.KeyHttpSLOBudgetMs, call.Budget.Milliseconds()),
+		glog.KV(glog.KeyAppRequestDurationMs, call.Duration.Milliseconds()),
+	)
+	if t.onSlow != nil {
+		t.onSlow(ctx, call)
+	}
+}
+
+// match 返回第一个匹配路径的规则，path 中的查询参数不参与匹配
+func (t *SLOTracker) match(path string) *sloRule {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for _, rule := range t.rules {
+		if rule.matchSegments(segments) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func (r *sloRule) matchSegments(segments []string) bool {
+	if len(segments) < len(r.segments) || (!r.prefix && len(segments) != len(r.segments)) {
+		return false
+	}
+	for i, seg := range r.segments {
+		last := i == len(r.segments)-1
+		switch {
+		case r.prefix && last:
+			// 前缀模式的最后一段按字符串前缀匹配，如 /pay/* 的最后一段为空、/v1/user* 的最后一段为 user
+			if !strings.HasPrefix(segments[i], seg) {
+				return false
+			}
+		case strings.HasPrefix(seg, ":"):
+			if segments[i] == "" {
+				return false
+			}
+		case seg != segments[i]:
+			return false
+		}
+	}
+	return true
+}
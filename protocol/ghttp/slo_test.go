@@ -0,0 +1,76 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol/protocoltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSLOTrackerMatch(t *testing.T) {
+	tracker := NewSLOTracker(
+		WithSLOBudget("/users/:id", time.Second),
+		WithSLOBudget("/pay/*", time.Second),
+		WithSLOBudget("/users", time.Second),
+	)
+
+	cases := map[string]string{
+		"/users/1":         "/users/:id",
+		"/users/1?debug=1": "/users/:id",
+		"/users":           "/users",
+		"/pay/create":      "/pay/*",
+		"/pay/a/b":         "/pay/*",
+		"/users/1/orders":  "*",
+		"/users/":          "*",
+		"/orders":          "*",
+	}
+	for path, want := range cases {
+		rule := tracker.match(path)
+		require.NotNil(t, rule, path)
+		assert.Equal(t, want, rule.pattern, path)
+	}
+}
+
+func TestClientSLOTracker(t *testing.T) {
+	srv := protocoltest.NewServer(t,
+		protocoltest.Route{Path: "/slow/*", Latency: 80 * time.Millisecond, Body: `{"code":0}`},
+		protocoltest.Route{Path: "/fast", Body: `{"code":0}`},
+	)
+
+	var slowCalls []*SlowCall
+	tracker := NewSLOTracker(
+		WithSLOBudget("/slow/:id", 20*time.Millisecond),
+		WithSLOBudget("/fast", time.Second),
+		WithSLOSlowHook(func(_ context.Context, call *SlowCall) {
+			slowCalls = append(slowCalls, call)
+		}),
+	)
+	client := NewClient(srv.HttpClientConfig())
+	client.SetSLOTracker(tracker)
+
+	ctx := context.Background()
+	_, err := client.Get(ctx, "/slow/1", RequestOption{})
+	require.Nil(t, err)
+	_, err = client.Get(ctx, "/fast", RequestOption{})
+	require.Nil(t, err)
+	// 未登记且未设置默认预算的路径不统计
+	_, err = client.Get(ctx, "/slow/1/detail", RequestOption{})
+	require.Nil(t, err)
+
+	require.Len(t, slowCalls, 1)
+	assert.Equal(t, "/slow/:id", slowCalls[0].Pattern)
+	assert.Equal(t, http.MethodGet, slowCalls[0].Method)
+	assert.Equal(t, http.StatusOK, slowCalls[0].StatusCode)
+	assert.Equal(t, 20*time.Millisecond, slowCalls[0].Budget)
+	assert.Greater(t, slowCalls[0].Duration, slowCalls[0].Budget)
+
+	assert.Equal(t, []SLOStats{
+		{Pattern: "/slow/:id", Budget: 20 * time.Millisecond, Total: 1, Slow: 1},
+		{Pattern: "/fast", Budget: time.Second, Total: 1, Slow: 0},
+	}, tracker.Stats())
+	assert.EqualValues(t, 1, tracker.SlowCalls())
+}
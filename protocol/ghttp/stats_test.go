@@ -0,0 +1,56 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/morehao/golib/protocol/protocoltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientStats(t *testing.T) {
+	protocol.ResetStats()
+	t.Cleanup(protocol.ResetStats)
+
+	srv := protocoltest.NewServer(t,
+		protocoltest.Route{Path: "/ok", Body: `{"code":0}`},
+		protocoltest.Route{Path: "/fail", Status: http.StatusInternalServerError},
+	)
+	cfg := srv.HttpClientConfig()
+	cfg.Module = "user"
+	client := NewClient(cfg)
+
+	ctx := context.Background()
+	_, err := client.Get(ctx, "/ok", RequestOption{})
+	require.Nil(t, err)
+	_, err = client.Get(ctx, "/fail", RequestOption{})
+	require.NotNil(t, err)
+	// 流式请求同样统计
+	stream, err := client.GetStream(ctx, "/ok", RequestOption{})
+	require.Nil(t, err)
+	stream.Close()
+
+	// 连接失败时重试，重试次数单独统计
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	down := NewClient(&protocol.HttpClientConfig{Module: "down", Host: closed.URL, MaxRetry: 3})
+	_, err = down.Get(ctx, "/ok", RequestOption{})
+	require.NotNil(t, err)
+
+	stats := protocol.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "down", stats[0].Service)
+	assert.EqualValues(t, 1, stats[0].Requests)
+	assert.EqualValues(t, 1, stats[0].Errors)
+	assert.EqualValues(t, 2, stats[0].Retries)
+
+	assert.Equal(t, "user", stats[1].Service)
+	assert.EqualValues(t, 3, stats[1].Requests)
+	assert.EqualValues(t, 1, stats[1].Errors)
+	assert.EqualValues(t, 0, stats[1].Retries)
+	assert.InDelta(t, 1.0/3, stats[1].ErrorRate, 1e-9)
+}
@@ -6,10 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/protocol"
 )
 
 type StreamResult struct {
@@ -33,6 +33,18 @@ func (r *StreamResult) Close() error {
 	return r.reader.Close()
 }
 
+// cancelReadCloser 关闭响应体时释放请求的超时 context
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel()
+	return err
+}
+
 func (r *StreamResult) IsSuccess() bool {
 	return r.HttpCode >= 200 && r.HttpCode < 300
 }
@@ -72,40 +84,16 @@ func (c *Client) PostStream(ctx context.Context, path string, opt RequestOption)
 }
 
 func (c *Client) streamDo(ctx context.Context, method, path string, opt RequestOption) (*StreamResult, error) {
-	reqURL := c.Host + path
-	var payload io.Reader
-	var urlData []byte
-	var err error
-
-	switch method {
-	case http.MethodGet, http.MethodHead, http.MethodDelete:
-		payload = nil
-		if opt.RequestBody != nil {
-			queryParams, err := c.buildQueryParams(opt.RequestBody)
-			if err != nil {
-				glog.Errorf(ctx, "http stream client build query params error: %s", err.Error())
-				return nil, err
-			}
-			if queryParams != "" {
-				if strings.Contains(reqURL, "?") {
-					reqURL = reqURL + "&" + queryParams
-				} else {
-					reqURL = reqURL + "?" + queryParams
-				}
-			}
-		}
-		urlData = []byte(reqURL)
-	case http.MethodPost, http.MethodPatch, http.MethodPut:
-		urlData, err = opt.getData()
-		if err != nil {
-			glog.Errorf(ctx, "http stream client get data error: %s", err.Error())
-			return nil, err
-		}
-		payload = bytes.NewReader(urlData)
+	opt = c.mergeOption(opt)
+	reqURL, payload, urlData, err := c.buildPayload(method, path, &opt)
+	if err != nil {
+		glog.Errorf(ctx, "http stream client build request error: %s", err.Error())
+		return nil, err
 	}
 
 	request, err := c.makeRequest(ctx, method, reqURL, payload, opt)
 	if err != nil {
+		closePayload(payload)
 		glog.Errorf(ctx, "http stream client make request error: %s", err.Error())
 		return nil, err
 	}
@@ -117,71 +105,29 @@ func (c *Client) streamDo(ctx context.Context, method, path string, opt RequestO
 		glog.KV(glog.KeyHttpRequestBody, reqData),
 	)
 
-	result, err := c.doStream(ctx, request, &opt, urlData)
+	// 流式请求只统计建连耗时，不包含读取响应流的时间
+	startTime := time.Now()
+	result, err := c.doStream(ctx, request, &opt)
+	protocol.RecordCall(c.Service, time.Since(startTime), err)
 	if err != nil {
 		glog.Errorf(ctx, "http stream request failed: %s", err.Error())
 	}
+	if result != nil {
+		c.getVersionPolicy().observe(ctx, c.Service, method, path, result.Header)
+	}
 
 	return result, err
 }
 
-func (c *Client) doStream(ctx context.Context, request *http.Request, opt *RequestOption, requestBody []byte) (*StreamResult, error) {
+func (c *Client) doStream(ctx context.Context, request *http.Request, opt *RequestOption) (*StreamResult, error) {
 	startTime := time.Now()
 
-	c.mu.RLock()
-	clientTimeout := c.Timeout
-	c.mu.RUnlock()
-
-	timeout := 3 * time.Second
-	if opt != nil && opt.Timeout > 0 {
-		timeout = opt.Timeout
-	} else if clientTimeout > 0 {
-		timeout = clientTimeout
-	}
-
-	if opt != nil && opt.Timeout > 0 && clientTimeout > 0 {
-		if opt.Timeout < clientTimeout {
-			timeout = opt.Timeout
-		} else {
-			timeout = clientTimeout
-		}
-	}
-
-	httpClient := c.getHTTPClient(timeout)
-
-	var resp *http.Response
-	var err error
-
-	retryCount := c.Retry
-	if retryCount <= 0 {
-		retryCount = 1
-	}
-
-	var originalBody []byte
-	if request.Body != nil && requestBody != nil {
-		originalBody = make([]byte, len(requestBody))
-		copy(originalBody, requestBody)
-	}
-
-	for i := 0; i < retryCount; i++ {
-		if i > 0 && originalBody != nil {
-			request.Body = io.NopCloser(bytes.NewReader(originalBody))
-		}
+	// 超时时间包含读取响应流的时间，关闭 StreamResult 时释放
+	timeoutCtx, cancel := context.WithTimeout(ctx, c.requestTimeout(opt))
+	request = request.WithContext(timeoutCtx)
+	httpClient := c.getHTTPClient()
 
-		resp, err = httpClient.Do(request)
-		if err == nil {
-			break
-		}
-
-		if i < retryCount-1 {
-			delay := time.Millisecond * 100 * time.Duration(i+1)
-			if delay > time.Second {
-				delay = time.Second
-			}
-			time.Sleep(delay)
-			glog.Warnf(ctx, "http stream request retry %d/%d, error: %v", i+1, retryCount, err)
-		}
-	}
+	resp, err := c.roundTrip(c.withBreaker(c.retryRoundTrip(httpClient, "http stream request", 0)))(request)
 
 	costTime := time.Since(startTime).Milliseconds()
 
@@ -193,6 +139,7 @@ func (c *Client) doStream(ctx context.Context, request *http.Request, opt *Reque
 			glog.KV(glog.KeyAppRequestDurationMs, costTime),
 			glog.KV("error", err.Error()),
 		)
+		cancel()
 		return nil, fmt.Errorf("http stream request failed: %w", err)
 	}
 
@@ -200,7 +147,7 @@ func (c *Client) doStream(ctx context.Context, request *http.Request, opt *Reque
 		HttpCode: resp.StatusCode,
 		Header:   resp.Header,
 		Ctx:      ctx,
-		reader:   resp.Body,
+		reader:   &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel},
 	}
 
 	glog.Infow(ctx, "http stream request connected",
@@ -213,6 +160,7 @@ func (c *Client) doStream(ctx context.Context, request *http.Request, opt *Reque
 	if resp.StatusCode >= 400 {
 		body, readErr := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		cancel()
 		if readErr != nil {
 			result.reader = nil
 		} else {
@@ -0,0 +1,70 @@
+package ghttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stream" {
+			w.Write([]byte("head"))
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Timeout: 3 * time.Second})
+	ctx := context.Background()
+
+	// 同一客户端的每次请求按各自的超时时间生效，不受首次请求影响
+	res, err := client.Get(ctx, "/slow", RequestOption{Timeout: time.Second})
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", res.String())
+	start := time.Now()
+	_, err = client.Get(ctx, "/slow", RequestOption{Timeout: 50 * time.Millisecond})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	res, err = client.Get(ctx, "/slow", RequestOption{})
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", res.String())
+
+	// RequestOption.Timeout 大于 Client.Timeout 时取 Client.Timeout
+	client.Timeout = 50 * time.Millisecond
+	_, err = client.Get(ctx, "/slow", RequestOption{Timeout: time.Second})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	client.Timeout = 3 * time.Second
+
+	// 流式请求的超时包含读取响应流的时间
+	stream, err := client.GetStream(ctx, "/stream", RequestOption{Timeout: 50 * time.Millisecond})
+	assert.Nil(t, err)
+	_, err = io.ReadAll(stream)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	stream.Close()
+	stream, err = client.GetStream(ctx, "/stream", RequestOption{Timeout: time.Second})
+	assert.Nil(t, err)
+	content, err := io.ReadAll(stream)
+	assert.Nil(t, err)
+	assert.Equal(t, "headok", string(content))
+	stream.Close()
+
+	// 调用方的 ctx 先取消时以调用方为准
+	cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = client.Get(cancelCtx, "/slow", RequestOption{Timeout: time.Second})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
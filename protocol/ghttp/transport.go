@@ -0,0 +1,96 @@
+package ghttp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// DialContextFunc 建立连接的方法，与 net.Dialer.DialContext 签名一致
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// transportOptions 创建连接池使用的 Transport 配置
+type transportOptions struct {
+	tlsConfig   *tls.Config
+	proxy       func(*http.Request) (*url.URL, error)
+	dialContext DialContextFunc
+	err         error // 配置错误，创建请求时返回
+}
+
+// SetTLSConfig 设置 HTTPS 连接的 TLS 配置，用于 mTLS 客户端证书、自定义 CA、跳过证书校验等场景。
+// 连接池在首次请求时创建，需在首次请求前调用
+func (c *Client) SetTLSConfig(cfg *tls.Config) {
+	c.mu.Lock()
+	c.transport.tlsConfig = cfg
+	c.mu.Unlock()
+}
+
+// SetProxy 设置代理地址，支持 http、https、socks5 和 socks5h，为空时不使用代理，需在首次请求前调用
+func (c *Client) SetProxy(proxyURL string) error {
+	proxy, err := parseProxy(proxyURL)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.transport.proxy = proxy
+	c.mu.Unlock()
+	return nil
+}
+
+// SetDialContext 自定义建立连接的方法，用于固定解析地址、服务发现或 unix socket 等场景，需在首次请求前调用
+func (c *Client) SetDialContext(fn DialContextFunc) {
+	c.mu.Lock()
+	c.transport.dialContext = fn
+	c.mu.Unlock()
+}
+
+func parseProxy(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	if proxyURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", proxyURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: missing host", proxyURL)
+	}
+	return http.ProxyURL(u), nil
+}
+
+// applyConfig 按 HttpClientConfig 设置代理和 TLS，配置错误记录在 err 中
+func (o *transportOptions) applyConfig(proxyURL string, tlsCfg func() (*tls.Config, error)) {
+	proxy, err := parseProxy(proxyURL)
+	if err != nil {
+		o.err = fmt.Errorf("http client config: %w", err)
+		return
+	}
+	o.proxy = proxy
+	if tlsCfg == nil {
+		return
+	}
+	if o.tlsConfig, err = tlsCfg(); err != nil {
+		o.err = fmt.Errorf("http client config: %w", err)
+	}
+}
+
+// apply 将配置写入 Transport
+func (o *transportOptions) apply(transport *http.Transport) {
+	if o.tlsConfig != nil {
+		transport.TLSClientConfig = o.tlsConfig.Clone()
+	}
+	if o.proxy != nil {
+		transport.Proxy = o.proxy
+	}
+	if o.dialContext != nil {
+		transport.DialContext = o.dialContext
+	}
+}
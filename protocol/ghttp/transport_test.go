@@ -0,0 +1,130 @@
+package ghttp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	// 生成客户端证书，并将服务端证书作为 CA
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "order-service"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+	caFile, certFile, keyFile := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	require.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+	require.Nil(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	ctx := context.Background()
+
+	client := NewClient(&protocol.HttpClientConfig{
+		Module: "test",
+		Host:   srv.URL,
+		TLS:    &protocol.TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+	})
+	res, err := client.Get(ctx, "/", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, "order-service", res.String())
+
+	// 未配置 CA 时无法校验服务端证书，未配置客户端证书时服务端拒绝
+	_, err = NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL}).Get(ctx, "/", RequestOption{})
+	assert.NotNil(t, err)
+	_, err = NewClient(&protocol.HttpClientConfig{
+		Module: "test",
+		Host:   srv.URL,
+		TLS:    &protocol.TLSConfig{InsecureSkipVerify: true},
+	}).Get(ctx, "/", RequestOption{})
+	assert.NotNil(t, err)
+
+	// 通过 SetTLSConfig 设置
+	client = NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL})
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.Nil(t, err)
+	client.SetTLSConfig(&tls.Config{RootCAs: srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs, Certificates: []tls.Certificate{cert}})
+	res, err = client.Get(ctx, "/", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, "order-service", res.String())
+
+	// 配置错误在请求时返回
+	_, err = NewClient(&protocol.HttpClientConfig{
+		Module: "test",
+		Host:   srv.URL,
+		TLS:    &protocol.TLSConfig{CertFile: certFile},
+	}).Get(ctx, "/", RequestOption{})
+	assert.ErrorContains(t, err, "cert_file and key_file must be set together")
+}
+
+func TestClientProxyAndDialContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("origin"))
+	}))
+	defer srv.Close()
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.String())
+		w.Write([]byte("proxy"))
+	}))
+	defer proxy.Close()
+	ctx := context.Background()
+
+	client := NewClient(&protocol.HttpClientConfig{Module: "test", Host: "http://upstream.internal", Proxy: proxy.URL})
+	res, err := client.Get(ctx, "/users", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, "proxy", res.String())
+	assert.Equal(t, "http://upstream.internal/users", proxied.Load())
+
+	assert.NotNil(t, client.SetProxy("ftp://127.0.0.1:21"))
+	_, err = NewClient(&protocol.HttpClientConfig{Module: "test", Host: srv.URL, Proxy: "127.0.0.1:8080"}).Get(ctx, "/", RequestOption{})
+	assert.ErrorContains(t, err, "proxy")
+
+	// 自定义建连方法将任意主机名连接到测试服务
+	var dials atomic.Int32
+	client = NewClient(&protocol.HttpClientConfig{Module: "test", Host: "http://user-service.consul"})
+	client.SetDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials.Add(1)
+		var d net.Dialer
+		return d.DialContext(ctx, network, srv.Listener.Addr().String())
+	})
+	res, err = client.Get(ctx, "/", RequestOption{})
+	require.Nil(t, err)
+	assert.Equal(t, "origin", res.String())
+	assert.Equal(t, int32(1), dials.Load())
+}
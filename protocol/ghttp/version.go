@@ -0,0 +1,223 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/gutil"
+)
+
+const (
+	// DefaultVersionHeader 默认的 API 版本请求头
+	DefaultVersionHeader = "API-Version"
+	// defaultDeprecationWarnInterval 同一接口弃用告警日志的默认间隔
+	defaultDeprecationWarnInterval = time.Hour
+
+	headerDeprecation = "Deprecation"
+	headerSunset      = "Sunset"
+	headerLink        = "Link"
+)
+
+var defaultVersionPolicy = NewVersionPolicy()
+
+// Deprecation 下游接口通过响应头声明的弃用信息，来自 Deprecation（RFC 9745）、Sunset（RFC 8594）和 Link 响应头
+type Deprecation struct {
+	Service      string
+	Method       string
+	Path         string
+	Version      string    // 请求时发送的 API 版本，未设置时为空
+	Deprecated   bool      // 是否包含 Deprecation 响应头
+	DeprecatedAt time.Time // 弃用时间，Deprecation 为 true 等不含时间的取值时为零值
+	Sunset       time.Time // 下线时间，未声明时为零值
+	Link         string    // rel 为 deprecation 或 sunset 的说明文档链接
+}
+
+// IsSunsetPassed 下线时间是否已过
+func (d *Deprecation) IsSunsetPassed(now time.Time) bool {
+	return !d.Sunset.IsZero() && !now.Before(d.Sunset)
+}
+
+// VersionPolicy API 版本协商策略：请求时携带版本请求头，响应包含弃用或下线声明时输出带 http.deprecated 标记的 WARN 日志
+// 并触发回调，用于尽早发现合作方接口即将发生的不兼容变更。未设置时仍会输出弃用告警，但不发送版本请求头
+type VersionPolicy struct {
+	header       string
+	version      string
+	warnInterval time.Duration
+	onDeprecated func(ctx context.Context, deprecation *Deprecation)
+	lastWarn     gutil.SyncMap[string, time.Time] // 服务 + 方法 + 路径 -> 上次告警时间
+}
+
+// VersionOption 版本策略选项
+type VersionOption func(*VersionPolicy)
+
+// WithAPIVersion 设置请求携带的 API 版本，如 2024-06-01、v2，为空时不发送版本请求头
+func WithAPIVersion(version string) VersionOption {
+	return func(p *VersionPolicy) {
+		p.version = version
+	}
+}
+
+// WithVersionHeader 设置 API 版本请求头，默认 API-Version，如 X-Api-Version、Accept-Version
+func WithVersionHeader(header string) VersionOption {
+	return func(p *VersionPolicy) {
+		p.header = header
+	}
+}
+
+// WithDeprecationWarnInterval 设置同一接口弃用告警日志的最小间隔，默认 1 小时，<= 0 时每次响应都输出
+func WithDeprecationWarnInterval(interval time.Duration) VersionOption {
+	return func(p *VersionPolicy) {
+		p.warnInterval = interval
+	}
+}
+
+// WithDeprecationHook 设置弃用回调，可用于上报监控或告警，每次响应包含弃用声明时都会触发，不受告警间隔限制，
+// 回调在请求协程中同步执行
+func WithDeprecationHook(fn func(ctx context.Context, deprecation *Deprecation)) VersionOption {
+	return func(p *VersionPolicy) {
+		p.onDeprecated = fn
+	}
+}
+
+// NewVersionPolicy 创建版本策略
+func NewVersionPolicy(opts ...VersionOption) *VersionPolicy {
+	p := &VersionPolicy{header: DefaultVersionHeader, warnInterval: defaultDeprecationWarnInterval}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// SetVersionPolicy 设置 API 版本协商策略，RequestOption.Headers 中显式设置的版本优先
+func (c *Client) SetVersionPolicy(policy *VersionPolicy) {
+	c.mu.Lock()
+	c.versionPolicy = policy
+	c.mu.Unlock()
+}
+
+func (c *Client) getVersionPolicy() *VersionPolicy {
+	c.mu.RLock()
+	policy := c.versionPolicy
+	c.mu.RUnlock()
+	if policy == nil {
+		return defaultVersionPolicy
+	}
+	return policy
+}
+
+// applyHeader 设置版本请求头，在 RequestOption.Headers 之前执行，单次请求可通过 Headers 覆盖
+func (p *VersionPolicy) applyHeader(header http.Header) {
+	if p.version == "" || p.header == "" {
+		return
+	}
+	header.Set(p.header, p.version)
+}
+
+// observe 解析响应头中的弃用声明，存在时按间隔输出告警日志并触发回调
+func (p *VersionPolicy) observe(ctx context.Context, service, method, path string, header http.Header) {
+	deprecation, ok := parseDeprecation(header)
+	if !ok {
+		return
+	}
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	deprecation.Service = service
+	deprecation.Method = method
+	deprecation.Path = path
+	deprecation.Version = p.version
+
+	if p.shouldWarn(service+" "+method+" "+path, time.Now()) {
+		fields := []any{
+			glog.KV(glog.KeyHttpDeprecated, true),
+			glog.KV(glog.KeyService, service),
+			glog.KV(glog.KeyHttpRequestMethod, method),
+			glog.KV(glog.KeyUrlPath, path),
+		}
+		if deprecation.Version != "" {
+			fields = append(fields, glog.KV(glog.KeyHttpApiVersion, deprecation.Version))
+		}
+		if !deprecation.DeprecatedAt.IsZero() {
+			fields = append(fields, glog.KV(glog.KeyHttpDeprecatedAt, deprecation.DeprecatedAt.Format(time.RFC3339)))
+		}
+		if !deprecation.Sunset.IsZero() {
+			fields = append(fields, glog.KV(glog.KeyHttpSunset, deprecation.Sunset.Format(time.RFC3339)))
+		}
+		if deprecation.Link != "" {
+			fields = append(fields, glog.KV(glog.KeyHttpDeprecationLink, deprecation.Link))
+		}
+		msg := "http api deprecated"
+		if deprecation.IsSunsetPassed(time.Now()) {
+			msg = "http api sunset passed"
+		}
+		glog.Warnw(ctx, msg, fields...)
+	}
+	if p.onDeprecated != nil {
+		p.onDeprecated(ctx, deprecation)
+	}
+}
+
+func (p *VersionPolicy) shouldWarn(key string, now time.Time) bool {
+	if p.warnInterval <= 0 {
+		return true
+	}
+	if last, ok := p.lastWarn.Load(key); ok && now.Sub(last) < p.warnInterval {
+		return false
+	}
+	p.lastWarn.Store(key, now)
+	return true
+}
+
+// parseDeprecation 解析 Deprecation、Sunset 和 Link 响应头，均不存在时返回 false。
+// Deprecation 支持 RFC 9745 的 @秒级时间戳、HTTP-date 以及早期草案的 true
+func parseDeprecation(header http.Header) (*Deprecation, bool) {
+	deprecationValue := strings.TrimSpace(header.Get(headerDeprecation))
+	sunsetValue := strings.TrimSpace(header.Get(headerSunset))
+	if deprecationValue == "" && sunsetValue == "" {
+		return nil, false
+	}
+	deprecation := &Deprecation{Deprecated: deprecationValue != "" && !strings.EqualFold(deprecationValue, "false")}
+	if deprecation.Deprecated {
+		if seconds, ok := strings.CutPrefix(deprecationValue, "@"); ok {
+			if unix, err := strconv.ParseInt(seconds, 10, 64); err == nil {
+				deprecation.DeprecatedAt = time.Unix(unix, 0).UTC()
+			}
+		} else if t, err := http.ParseTime(deprecationValue); err == nil {
+			deprecation.DeprecatedAt = t
+		}
+	}
+	if sunsetValue != "" {
+		if t, err := http.ParseTime(sunsetValue); err == nil {
+			deprecation.Sunset = t
+		}
+	}
+	if !deprecation.Deprecated && deprecation.Sunset.IsZero() {
+		return nil, false
+	}
+	deprecation.Link = deprecationLink(header.Values(headerLink))
+	return deprecation, true
+}
+
+// deprecationLink 返回 Link 响应头中 rel 为 deprecation 或 sunset 的链接
+func deprecationLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				name, rel, _ := strings.Cut(strings.TrimSpace(param), "=")
+				rel = strings.Trim(rel, `"`)
+				if strings.EqualFold(name, "rel") && (strings.EqualFold(rel, "deprecation") || strings.EqualFold(rel, "sunset")) {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol/protocoltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeprecation(t *testing.T) {
+	_, ok := parseDeprecation(http.Header{})
+	assert.False(t, ok)
+	_, ok = parseDeprecation(http.Header{"Deprecation": {"false"}})
+	assert.False(t, ok)
+
+	header := http.Header{}
+	header.Set("Deprecation", "@1688169599")
+	header.Set("Sunset", "Wed, 11 Nov 2026 23:59:59 GMT")
+	header.Add("Link", `<https://api.example.com/v1>; rel="latest-version", <https://api.example.com/deprecation>; rel="deprecation"; type="text/html"`)
+	deprecation, ok := parseDeprecation(header)
+	require.True(t, ok)
+	assert.True(t, deprecation.Deprecated)
+	assert.Equal(t, time.Unix(1688169599, 0).UTC(), deprecation.DeprecatedAt)
+	assert.Equal(t, time.Date(2026, 11, 11, 23, 59, 59, 0, time.UTC), deprecation.Sunset)
+	assert.Equal(t, "https://api.example.com/deprecation", deprecation.Link)
+	assert.False(t, deprecation.IsSunsetPassed(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, deprecation.IsSunsetPassed(time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)))
+
+	// 早期草案的 true 取值，只声明 Sunset 时同样视为弃用声明
+	deprecation, ok = parseDeprecation(http.Header{"Deprecation": {"true"}})
+	require.True(t, ok)
+	assert.True(t, deprecation.Deprecated)
+	assert.True(t, deprecation.DeprecatedAt.IsZero())
+	deprecation, ok = parseDeprecation(http.Header{"Sunset": {"Wed, 11 Nov 2026 23:59:59 GMT"}})
+	require.True(t, ok)
+	assert.False(t, deprecation.Deprecated)
+}
+
+func TestClientVersionPolicy(t *testing.T) {
+	srv := protocoltest.NewServer(t,
+		protocoltest.Route{Path: "/v1/*", Body: `{"code":0}`, Headers: map[string]string{
+			"Deprecation": "true",
+			"Sunset":      "Wed, 11 Nov 2026 23:59:59 GMT",
+		}},
+		protocoltest.Route{Path: "/v2/*", Body: `{"code":0}`},
+	)
+
+	var deprecations []*Deprecation
+	client := NewClient(srv.HttpClientConfig())
+	client.SetVersionPolicy(NewVersionPolicy(
+		WithAPIVersion("2024-06-01"),
+		WithDeprecationHook(func(_ context.Context, deprecation *Deprecation) {
+			deprecations = append(deprecations, deprecation)
+		}),
+	))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(ctx, "/v1/users?id=1", RequestOption{})
+		require.Nil(t, err)
+	}
+	_, err := client.Get(ctx, "/v2/users", RequestOption{Headers: map[string]string{DefaultVersionHeader: "2025-01-01"}})
+	require.Nil(t, err)
+
+	requests := srv.Requests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, "2024-06-01", requests[0].Header.Get(DefaultVersionHeader))
+	assert.Equal(t, "2025-01-01", requests[2].Header.Get(DefaultVersionHeader))
+
+	// 回调不受告警间隔限制
+	require.Len(t, deprecations, 2)
+	assert.Equal(t, "/v1/users", deprecations[0].Path)
+	assert.Equal(t, http.MethodGet, deprecations[0].Method)
+	assert.Equal(t, "2024-06-01", deprecations[0].Version)
+	assert.True(t, deprecations[0].Deprecated)
+	assert.False(t, deprecations[0].Sunset.IsZero())
+}
+
+func TestVersionPolicyShouldWarn(t *testing.T) {
+	now := time.Now()
+	policy := NewVersionPolicy(WithDeprecationWarnInterval(time.Minute))
+	assert.True(t, policy.shouldWarn("svc GET /a", now))
+	assert.False(t, policy.shouldWarn("svc GET /a", now.Add(time.Second)))
+	assert.True(t, policy.shouldWarn("svc GET /b", now))
+	assert.True(t, policy.shouldWarn("svc GET /a", now.Add(time.Minute)))
+
+	policy = NewVersionPolicy(WithDeprecationWarnInterval(0))
+	assert.True(t, policy.shouldWarn("svc GET /a", now))
+	assert.True(t, policy.shouldWarn("svc GET /a", now))
+}
@@ -0,0 +1,140 @@
+package gresty
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// ErrBatchAborted fail-fast 模式下已有请求失败，后续未发出的请求不再执行
+var ErrBatchAborted = errors.New("batch aborted")
+
+// Spec 批量请求中的单个请求
+type Spec struct {
+	Name   string               // 请求标识，用于聚合结果，如 "orders"
+	Method string               // 请求方法，默认 GET
+	URL    string               // 请求地址，设置了 BaseURL 时可为相对路径
+	Build  func(*resty.Request) // 可选，设置请求头、查询参数、请求体、SetResult 等
+}
+
+// Result 批量请求中单个请求的结果，与 Spec 按下标一一对应
+type Result struct {
+	Name     string
+	Response *resty.Response
+	Err      error
+	Latency  time.Duration // 请求耗时，未执行的请求为 0
+}
+
+// Success 请求成功且响应状态码小于 400
+func (r *Result) Success() bool {
+	return r.Err == nil && r.Response != nil && !r.Response.IsError()
+}
+
+// BatchSummary 批量请求的汇总统计
+type BatchSummary struct {
+	Total      int
+	Succeeded  int
+	Failed     int           // 含 fail-fast 模式下被中止的请求
+	MaxLatency time.Duration // 最慢请求的耗时，即批量请求的整体耗时下限
+}
+
+type batchOptions struct {
+	failFast bool
+}
+
+// BatchOption 批量请求选项
+type BatchOption func(*batchOptions)
+
+// WithBatchFailFast 开启 fail-fast 模式：任一请求失败（含 4xx/5xx 响应）时取消进行中的请求，未发出的请求以 ErrBatchAborted 结束。
+// 默认为 collect-all 模式，等待全部请求完成，适用于看板等允许部分数据缺失的聚合接口
+func WithBatchFailFast() BatchOption {
+	return func(o *batchOptions) {
+		o.failFast = true
+	}
+}
+
+// Batch 以不超过 concurrency 的并发度执行 specs 中的请求，返回与 specs 一一对应的结果，concurrency <= 0 时不限制并发度
+func (c *Client) Batch(ctx context.Context, specs []Spec, concurrency int, opts ...BatchOption) []Result {
+	var o batchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if concurrency <= 0 || concurrency > len(specs) {
+		concurrency = len(specs)
+	}
+
+	results := make([]Result, len(specs))
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := range specs {
+		results[i].Name = specs[i].Name
+		select {
+		case sem <- struct{}{}:
+		case <-batchCtx.Done():
+		}
+		if batchCtx.Err() != nil {
+			// 调用方取消时返回其错误，否则为 fail-fast 中止
+			results[i].Err = ErrBatchAborted
+			if ctx.Err() != nil {
+				results[i].Err = ctx.Err()
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = c.execSpec(batchCtx, &specs[i])
+			if o.failFast && !results[i].Success() {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *Client) execSpec(ctx context.Context, spec *Spec) Result {
+	method := spec.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req := c.R().SetContext(ctx)
+	if spec.Build != nil {
+		spec.Build(req)
+	}
+	startTime := time.Now()
+	resp, err := req.Execute(method, spec.URL)
+	return Result{
+		Name:     spec.Name,
+		Response: resp,
+		Err:      err,
+		Latency:  time.Since(startTime),
+	}
+}
+
+// SummarizeBatch 汇总批量请求结果
+func SummarizeBatch(results []Result) BatchSummary {
+	summary := BatchSummary{Total: len(results)}
+	for i := range results {
+		if results[i].Success() {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+		if results[i].Latency > summary.MaxLatency {
+			summary.MaxLatency = results[i].Latency
+		}
+	}
+	return summary
+}
@@ -0,0 +1,96 @@
+package gresty
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"resty.dev/v3"
+)
+
+func newBatchServer(t *testing.T, inflight, maxInflight *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			m := maxInflight.Load()
+			if n <= m || maxInflight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/slow":
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		default:
+			time.Sleep(20 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBatchCollectAll(t *testing.T) {
+	var inflight, maxInflight atomic.Int32
+	srv := newBatchServer(t, &inflight, &maxInflight)
+	client := NewClient()
+	client.SetBaseURL(srv.URL)
+
+	type body struct {
+		Path string `json:"path"`
+	}
+	var orders body
+	specs := []Spec{
+		{Name: "orders", URL: "/orders", Build: func(req *resty.Request) { req.SetResult(&orders) }},
+		{Name: "users", URL: "/users"},
+		{Name: "fail", URL: "/fail"},
+		{Name: "stats", Method: http.MethodPost, URL: "/stats"},
+	}
+	results := client.Batch(context.Background(), specs, 2)
+	require.Len(t, results, len(specs))
+	for i, res := range results {
+		assert.Equal(t, specs[i].Name, res.Name)
+		assert.Positive(t, res.Latency)
+	}
+	assert.True(t, results[0].Success())
+	assert.Equal(t, "/orders", orders.Path)
+	assert.False(t, results[2].Success())
+	assert.Equal(t, http.StatusInternalServerError, results[2].Response.StatusCode())
+	assert.LessOrEqual(t, maxInflight.Load(), int32(2))
+
+	summary := SummarizeBatch(results)
+	assert.Equal(t, BatchSummary{Total: 4, Succeeded: 3, Failed: 1, MaxLatency: summary.MaxLatency}, summary)
+}
+
+func TestBatchFailFast(t *testing.T) {
+	var inflight, maxInflight atomic.Int32
+	srv := newBatchServer(t, &inflight, &maxInflight)
+	client := NewClient()
+	client.SetBaseURL(srv.URL)
+
+	specs := []Spec{
+		{Name: "slow", URL: "/slow"},
+		{Name: "fail", URL: "/fail"},
+		{Name: "pending", URL: "/orders"},
+	}
+	start := time.Now()
+	results := client.Batch(context.Background(), specs, 2, WithBatchFailFast())
+	assert.Less(t, time.Since(start), time.Second)
+
+	assert.True(t, errors.Is(results[0].Err, context.Canceled))
+	assert.Equal(t, http.StatusInternalServerError, results[1].Response.StatusCode())
+	assert.True(t, errors.Is(results[2].Err, ErrBatchAborted))
+	assert.Zero(t, results[2].Latency)
+}
@@ -1,6 +1,9 @@
 package gresty
 
 import (
+	"sync"
+	"sync/atomic"
+
 	"github.com/morehao/golib/glog"
 	"github.com/morehao/golib/protocol"
 	"resty.dev/v3"
@@ -9,6 +12,10 @@ import (
 type Client struct {
 	*resty.Client
 	logger glog.Logger
+
+	throttleOnce sync.Once
+	bandwidth    atomic.Pointer[bandwidthLimiters]
+	service      atomic.Pointer[string] // 调用统计中的下游服务名
 }
 
 func NewClient() *Client {
@@ -33,6 +40,7 @@ func NewClient() *Client {
 	c.AddResponseMiddleware(func(client *resty.Client, resp *resty.Response) error {
 		return newLoggingMiddleware(logger).handle(resp)
 	})
+	c.enableStats()
 
 	return c
 }
@@ -3,13 +3,13 @@ package gresty
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/morehao/golib/glog"
+	"github.com/morehao/golib/protocol/protocoltest"
 	"github.com/stretchr/testify/assert"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"resty.dev/v3"
@@ -22,31 +22,36 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestClientGetRequest(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.JSON(http.MethodGet, "/get", http.StatusOK, map[string]any{"ok": true}))
 	client := NewClient()
 
 	resp, err := client.R().
 		SetQueryParam("name", "test").
-		Get("https://httpbin.org/get")
+		Get(srv.URL + "/get")
 
 	assert.Nil(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, 200, resp.StatusCode())
+	assert.Equal(t, "name=test", srv.LastRequest().Query)
 }
 
 func TestClientPostRequest(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.JSON(http.MethodPost, "/post", http.StatusOK, map[string]any{"ok": true}))
 	client := NewClient()
 
 	body := map[string]string{"name": "test"}
 	resp, err := client.R().
 		SetBody(body).
-		Post("https://httpbin.org/post")
+		Post(srv.URL + "/post")
 
 	assert.Nil(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, 200, resp.StatusCode())
+	assert.JSONEq(t, `{"name":"test"}`, string(srv.LastRequest().Body))
 }
 
 func TestClientWithTraceID(t *testing.T) {
+	srv := protocoltest.NewServer(t, protocoltest.JSON(http.MethodGet, "/get", http.StatusOK, map[string]any{"ok": true}))
 	client := NewClient()
 
 	ctx := context.WithValue(context.Background(), glog.KeyTraceID, "trace-123")
@@ -54,7 +59,7 @@ func TestClientWithTraceID(t *testing.T) {
 	resp, err := client.R().
 		SetContext(ctx).
 		SetQueryParam("name", "test").
-		Get("https://httpbin.org/get")
+		Get(srv.URL + "/get")
 
 	assert.Nil(t, err)
 	assert.NotNil(t, resp)
@@ -62,62 +67,28 @@ func TestClientWithTraceID(t *testing.T) {
 }
 
 func TestSSEStream(t *testing.T) {
-	counter := 0
-	sseServer := createSSETestServer(
-		10*time.Millisecond,
-		func(w io.Writer) error {
-			if counter >= 5 {
-				return fmt.Errorf("stop sending events")
-			}
-			_, err := fmt.Fprintf(w, "id: %v\ndata: {\"counter\": %d}\n\n", counter, counter)
-			counter++
-			return err
-		},
-	)
-	defer sseServer.Close()
-
+	var events []protocoltest.SSEEvent
+	for i := 0; i < 5; i++ {
+		events = append(events, protocoltest.SSEEvent{
+			ID:    fmt.Sprint(i),
+			Data:  fmt.Sprintf(`{"counter": %d}`, i),
+			Delay: 10 * time.Millisecond,
+		})
+	}
+	srv := protocoltest.NewServer(t, protocoltest.SSE("/events", events...))
+
+	var received []string
 	es := resty.NewEventSource().
-		SetURL(sseServer.URL).
+		SetURL(srv.URL + "/events").
+		SetRetryCount(0).
 		OnMessage(func(e any) {
 			event := e.(*resty.Event)
-			fmt.Printf("Event ID: %s, Data: %s\n", event.ID, event.Data)
+			received = append(received, event.ID)
 		}, nil)
 
 	err := es.Get()
 	assert.NotNil(t, err)
-}
-
-func createSSETestServer(ticker time.Duration, fn func(io.Writer) error) *httptest.Server {
-	return createTestServer(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/event-stream")
-		w.Header().Set("Cache-Control", "no-cache")
-		w.Header().Set("Connection", "keep-alive")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-
-		clientGone := r.Context().Done()
-
-		rc := http.NewResponseController(w)
-		tick := time.NewTicker(ticker)
-		defer tick.Stop()
-
-		for {
-			select {
-			case <-clientGone:
-				return
-			case <-tick.C:
-				if err := fn(w); err != nil {
-					return
-				}
-				if err := rc.Flush(); err != nil {
-					return
-				}
-			}
-		}
-	})
-}
-
-func createTestServer(fn func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(fn))
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, received)
 }
 
 func TestClientInjectsOTelTraceAndRequestID(t *testing.T) {
@@ -0,0 +1,201 @@
+package gresty
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPoolIdleTimeout = 10 * time.Minute
+	defaultPoolMaxClients  = 1000
+)
+
+// ClientPool 按 host 缓存 Client 的连接池，适用于调用大量动态发现的主机（如 https://{tenant}.api.example.com）。
+// 每个 host 复用同一个 Client 及其连接，空闲超时或超过数量上限时关闭最久未使用的 Client，避免每个 host 泄漏一个 resty 客户端。
+type ClientPool struct {
+	mu          sync.Mutex
+	clients     map[string]*pooledClient
+	idleTimeout time.Duration
+	maxClients  int
+	configure   func(host string, c *Client)
+	stop        chan struct{}
+	closeOnce   sync.Once
+}
+
+type pooledClient struct {
+	client   *Client
+	lastUsed time.Time
+}
+
+// PoolOption 连接池选项
+type PoolOption func(*ClientPool)
+
+// WithPoolIdleTimeout 设置 Client 的空闲超时时间，超时未使用的 Client 将被关闭并移出连接池，默认 10 分钟
+func WithPoolIdleTimeout(timeout time.Duration) PoolOption {
+	return func(p *ClientPool) {
+		p.idleTimeout = timeout
+	}
+}
+
+// WithPoolMaxClients 设置连接池中 Client 的最大数量，超过时关闭最久未使用的 Client，默认 1000
+func WithPoolMaxClients(n int) PoolOption {
+	return func(p *ClientPool) {
+		p.maxClients = n
+	}
+}
+
+// WithPoolClientConfig 设置新建 Client 时的公共配置，如超时、重试、公共请求头，参数 host 为 scheme://host 形式
+func WithPoolClientConfig(fn func(host string, c *Client)) PoolOption {
+	return func(p *ClientPool) {
+		p.configure = fn
+	}
+}
+
+// NewClientPool 创建连接池并启动空闲 Client 清理协程，使用完毕需调用 Close
+func NewClientPool(opts ...PoolOption) *ClientPool {
+	p := &ClientPool{
+		clients:     make(map[string]*pooledClient),
+		idleTimeout: defaultPoolIdleTimeout,
+		maxClients:  defaultPoolMaxClients,
+		stop:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.idleTimeout <= 0 {
+		p.idleTimeout = defaultPoolIdleTimeout
+	}
+	if p.maxClients <= 0 {
+		p.maxClients = defaultPoolMaxClients
+	}
+	go p.evictLoop()
+	return p
+}
+
+// Get 获取 host 对应的 Client，不存在时创建并设置 BaseURL。
+// host 支持 "https://a.example.com" 或 "a.example.com" 形式，未指定 scheme 时默认 https。
+func (p *ClientPool) Get(host string) (*Client, error) {
+	key, err := normalizeHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pc, ok := p.clients[key]; ok {
+		pc.lastUsed = time.Now()
+		return pc.client, nil
+	}
+
+	if len(p.clients) >= p.maxClients {
+		p.evictOldestLocked()
+	}
+	client := NewClient()
+	client.SetBaseURL(key)
+	if p.configure != nil {
+		p.configure(key, client)
+	}
+	p.clients[key] = &pooledClient{client: client, lastUsed: time.Now()}
+	return client, nil
+}
+
+// GetByURL 根据完整请求地址获取对应 host 的 Client
+func (p *ClientPool) GetByURL(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid url: %s", rawURL)
+	}
+	return p.Get(u.Scheme + "://" + u.Host)
+}
+
+// Len 返回连接池中 Client 的数量
+func (p *ClientPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.clients)
+}
+
+// Close 停止清理协程并关闭所有 Client
+func (p *ClientPool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for key, pc := range p.clients {
+			_ = pc.client.Close()
+			delete(p.clients, key)
+		}
+	})
+}
+
+func (p *ClientPool) evictLoop() {
+	interval := p.idleTimeout / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.evictIdle()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// evictIdle 关闭超过空闲时间的 Client
+func (p *ClientPool) evictIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for key, pc := range p.clients {
+		if now.Sub(pc.lastUsed) >= p.idleTimeout {
+			_ = pc.client.Close()
+			delete(p.clients, key)
+		}
+	}
+}
+
+// evictOldestLocked 关闭最久未使用的 Client，调用方需持有锁
+func (p *ClientPool) evictOldestLocked() {
+	var (
+		oldestKey  string
+		oldestTime time.Time
+	)
+	for key, pc := range p.clients {
+		if oldestKey == "" || pc.lastUsed.Before(oldestTime) {
+			oldestKey, oldestTime = key, pc.lastUsed
+		}
+	}
+	if pc, ok := p.clients[oldestKey]; ok {
+		_ = pc.client.Close()
+		delete(p.clients, oldestKey)
+	}
+}
+
+// normalizeHost 将 host 统一为小写的 scheme://host[:port] 形式作为连接池的 key
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return "", fmt.Errorf("host is empty")
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid host: %s", host)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
@@ -0,0 +1,63 @@
+package gresty
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Pool", r.Header.Get("X-Pool"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pool := NewClientPool(WithPoolMaxClients(2), WithPoolClientConfig(func(host string, c *Client) {
+		c.SetHeader("X-Pool", "shared")
+	}))
+	defer pool.Close()
+
+	client, err := pool.GetByURL(server.URL + "/path?a=1")
+	require.Nil(t, err)
+	resp, err := client.R().Get("/ping")
+	require.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, "shared", resp.Header().Get("X-Pool"))
+
+	// 同一 host 复用 Client，host 大小写和 scheme 缺省统一处理
+	same, err := pool.Get(server.URL)
+	require.Nil(t, err)
+	assert.Same(t, client, same)
+	a, _ := pool.Get("A.example.com")
+	b, _ := pool.Get("https://a.example.com")
+	assert.Same(t, a, b)
+	assert.Equal(t, 2, pool.Len())
+
+	// 超过上限时淘汰最久未使用的 Client
+	_, err = pool.Get("b.example.com")
+	require.Nil(t, err)
+	assert.Equal(t, 2, pool.Len())
+	again, _ := pool.Get(server.URL)
+	assert.NotSame(t, client, again)
+
+	_, err = pool.Get("")
+	assert.NotNil(t, err)
+	_, err = pool.GetByURL("/relative")
+	assert.NotNil(t, err)
+}
+
+func TestClientPoolEvictIdle(t *testing.T) {
+	pool := NewClientPool(WithPoolIdleTimeout(time.Millisecond))
+	defer pool.Close()
+
+	_, err := pool.Get("a.example.com")
+	require.Nil(t, err)
+	time.Sleep(5 * time.Millisecond)
+	pool.evictIdle()
+	assert.Equal(t, 0, pool.Len())
+}
@@ -0,0 +1,64 @@
+package gresty
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morehao/golib/gutil"
+	"github.com/morehao/golib/protocol"
+	"resty.dev/v3"
+)
+
+// defaultStatsService 未设置服务名时统计使用的服务名。不按请求主机区分，避免动态主机无限增加统计项
+const defaultStatsService = "gresty"
+
+// SetService 设置调用统计（protocol.Stats）中的下游服务名，未设置时所有请求统计到 defaultStatsService
+func (c *Client) SetService(service string) *Client {
+	c.service.Store(&service)
+	return c
+}
+
+// enableStats 将请求的耗时、失败和重试记录到 protocol.Stats，耗时包含重试，
+// 请求失败或响应状态码 >= 400 时计为失败
+func (c *Client) enableStats() {
+	var startTimes gutil.SyncMap[*resty.Request, time.Time]
+
+	c.AddRequestMiddleware(func(_ *resty.Client, req *resty.Request) error {
+		// 每次尝试都会执行请求中间件，首次记录开始时间，之后每次计为一次重试
+		if req.Attempt <= 1 {
+			startTimes.Store(req, time.Now())
+		} else {
+			protocol.RecordRetry(c.statsService())
+		}
+		return nil
+	})
+	record := func(req *resty.Request, err error) {
+		startTime, ok := startTimes.LoadAndDelete(req)
+		if !ok {
+			return
+		}
+		protocol.RecordCall(c.statsService(), time.Since(startTime), err)
+	}
+	c.OnSuccess(func(_ *resty.Client, resp *resty.Response) {
+		var err error
+		if resp.IsError() {
+			err = fmt.Errorf("http status %d", resp.StatusCode())
+		}
+		record(resp.Request, err)
+	})
+	c.OnError(record)
+	// 请求无效或 panic 时未发出请求或无法确定结果，不计入统计
+	forget := func(req *resty.Request, _ error) {
+		startTimes.Delete(req)
+	}
+	c.OnInvalid(forget)
+	c.OnPanic(forget)
+}
+
+// statsService 返回统计使用的下游服务名
+func (c *Client) statsService() string {
+	if service := c.service.Load(); service != nil && *service != "" {
+		return *service
+	}
+	return defaultStatsService
+}
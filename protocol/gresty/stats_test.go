@@ -0,0 +1,45 @@
+package gresty
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+	"github.com/morehao/golib/protocol/protocoltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientStats(t *testing.T) {
+	protocol.ResetStats()
+	t.Cleanup(protocol.ResetStats)
+
+	srv := protocoltest.NewServer(t,
+		protocoltest.JSON(http.MethodGet, "/ok", http.StatusOK, map[string]any{"ok": true}),
+		protocoltest.JSON(http.MethodGet, "/fail", http.StatusInternalServerError, map[string]any{"ok": false}),
+	)
+	client := NewClient().SetService("user")
+	_, err := client.R().Get(srv.URL + "/ok")
+	require.Nil(t, err)
+	_, err = client.R().Get(srv.URL + "/fail")
+	require.Nil(t, err)
+
+	// 未设置服务名时统计到固定的服务名，5xx 响应时重试，重试次数单独统计
+	retry := NewClient()
+	retry.SetRetryCount(2).SetRetryWaitTime(time.Millisecond).SetRetryMaxWaitTime(time.Millisecond)
+	_, err = retry.R().Get(srv.URL + "/fail")
+	require.Nil(t, err)
+
+	stats := protocol.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, defaultStatsService, stats[0].Service)
+	assert.EqualValues(t, 1, stats[0].Requests)
+	assert.EqualValues(t, 1, stats[0].Errors)
+	assert.EqualValues(t, 2, stats[0].Retries)
+
+	assert.Equal(t, "user", stats[1].Service)
+	assert.EqualValues(t, 2, stats[1].Requests)
+	assert.EqualValues(t, 1, stats[1].Errors)
+	assert.EqualValues(t, 0, stats[1].Retries)
+}
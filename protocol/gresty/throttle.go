@@ -0,0 +1,132 @@
+package gresty
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"golang.org/x/time/rate"
+	"resty.dev/v3"
+)
+
+// BandwidthLimit 传输带宽限制，单位字节/秒，<= 0 表示不限制
+type BandwidthLimit struct {
+	Upload   int64 // 请求体的发送速率
+	Download int64 // 响应体的读取速率
+}
+
+// bandwidthLimiters 按 BandwidthLimit 创建的限速器，未限制的方向为 nil
+type bandwidthLimiters struct {
+	upload   *rate.Limiter
+	download *rate.Limiter
+}
+
+func newBandwidthLimiters(limit BandwidthLimit) *bandwidthLimiters {
+	return &bandwidthLimiters{upload: newByteLimiter(limit.Upload), download: newByteLimiter(limit.Download)}
+}
+
+// newByteLimiter 创建每秒 bytesPerSec 字节的限速器，桶容量为一秒的字节数
+func newByteLimiter(bytesPerSec int64) *rate.Limiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	burst := int(min(bytesPerSec, int64(1<<30)))
+	return rate.NewLimiter(rate.Limit(bytesPerSec), burst)
+}
+
+type bandwidthCtxKey struct{}
+
+// SetBandwidthLimit 设置客户端级带宽限制，客户端的所有请求共用该额度，用于后台同步等大文件传输任务避免占满网络带宽。
+// 首次调用 SetBandwidthLimit 或 LimitRequest 时包装客户端当前的 Transport，自定义 Transport 或 TLS 配置需在此之前设置
+func (c *Client) SetBandwidthLimit(limit BandwidthLimit) *Client {
+	c.enableThrottle()
+	c.bandwidth.Store(newBandwidthLimiters(limit))
+	return c
+}
+
+// LimitRequest 为单个请求设置带宽限制，与客户端级限制同时生效，实际速率取二者较小值：
+//
+//	resp, err := client.LimitRequest(client.R(), gresty.BandwidthLimit{Download: 1 << 20}).
+//		SetOutputFileName("/tmp/export.csv").
+//		Get(url)
+func (c *Client) LimitRequest(req *resty.Request, limit BandwidthLimit) *resty.Request {
+	c.enableThrottle()
+	return req.SetContext(context.WithValue(req.Context(), bandwidthCtxKey{}, newBandwidthLimiters(limit)))
+}
+
+func (c *Client) enableThrottle() {
+	c.throttleOnce.Do(func() {
+		c.SetTransport(&throttleTransport{next: c.Transport(), client: c})
+	})
+}
+
+// throttleTransport 按客户端级和请求级带宽限制包装请求体和响应体
+type throttleTransport struct {
+	next   http.RoundTripper
+	client *Client
+}
+
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var uploads, downloads []*rate.Limiter
+	for _, l := range []*bandwidthLimiters{t.client.bandwidth.Load(), requestLimiters(req.Context())} {
+		if l == nil {
+			continue
+		}
+		if l.upload != nil {
+			uploads = append(uploads, l.upload)
+		}
+		if l.download != nil {
+			downloads = append(downloads, l.download)
+		}
+	}
+
+	ctx := req.Context()
+	if len(uploads) > 0 && req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(ctx)
+		req.Body = &throttledReader{ReadCloser: req.Body, ctx: ctx, limiters: uploads}
+		if getBody := req.GetBody; getBody != nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				body, err := getBody()
+				if err != nil {
+					return nil, err
+				}
+				return &throttledReader{ReadCloser: body, ctx: ctx, limiters: uploads}, nil
+			}
+		}
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || len(downloads) == 0 {
+		return resp, err
+	}
+	resp.Body = &throttledReader{ReadCloser: resp.Body, ctx: ctx, limiters: downloads}
+	return resp, nil
+}
+
+func requestLimiters(ctx context.Context) *bandwidthLimiters {
+	l, _ := ctx.Value(bandwidthCtxKey{}).(*bandwidthLimiters)
+	return l
+}
+
+// throttledReader 每次读取后按读取的字节数等待各限速器放行，单次读取不超过限速器的桶容量
+type throttledReader struct {
+	io.ReadCloser
+	ctx      context.Context
+	limiters []*rate.Limiter
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	for _, l := range r.limiters {
+		if len(p) > l.Burst() {
+			p = p[:l.Burst()]
+		}
+	}
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		for _, l := range r.limiters {
+			if waitErr := l.WaitN(r.ctx, n); waitErr != nil {
+				return n, waitErr
+			}
+		}
+	}
+	return n, err
+}
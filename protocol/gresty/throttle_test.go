@@ -0,0 +1,55 @@
+package gresty
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandwidthLimit(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 3000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte{byte(len(body) / 1000)})
+			return
+		}
+		_, _ = w.Write(payload)
+	}))
+	defer srv.Close()
+
+	client := NewClient()
+	start := time.Now()
+	resp, err := client.R().Get(srv.URL)
+	require.Nil(t, err)
+	assert.Len(t, resp.Bytes(), len(payload))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// 下载限速 2000B/s：首个桶容量内的 2000 字节立即读取，剩余 1000 字节约等待 0.5s
+	start = time.Now()
+	resp, err = client.LimitRequest(client.R(), BandwidthLimit{Download: 2000}).Get(srv.URL)
+	require.Nil(t, err)
+	assert.Len(t, resp.Bytes(), len(payload))
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	// 客户端级上传限速对所有请求生效
+	client.SetBandwidthLimit(BandwidthLimit{Upload: 2000})
+	start = time.Now()
+	resp, err = client.R().SetBody(payload).Post(srv.URL)
+	require.Nil(t, err)
+	assert.Equal(t, []byte{3}, resp.Bytes())
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	// 取消限制后恢复正常速率
+	client.SetBandwidthLimit(BandwidthLimit{})
+	start = time.Now()
+	_, err = client.R().SetBody(payload).Post(srv.URL)
+	require.Nil(t, err)
+	assert.Less(t, time.Since(start), 300*time.Millisecond)
+}
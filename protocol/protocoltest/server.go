@@ -0,0 +1,368 @@
+// Package protocoltest 提供 HTTP 客户端测试用的 mock 服务，ghttp、gresty 及业务方的测试共用同一套路由 fixture：
+// 声明式配置 JSON 响应、延迟、错误率和 SSE 事件脚本，并记录收到的请求用于断言。
+package protocoltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/morehao/golib/protocol"
+)
+
+// Route 路由 fixture，按 Method + Path 匹配请求
+type Route struct {
+	// Method 请求方法，为空时匹配任意方法
+	Method string
+	// Path 请求路径，以 "*" 结尾时按前缀匹配
+	Path string
+	// Status 响应状态码，默认 200
+	Status int
+	// Headers 响应头
+	Headers map[string]string
+	// Body 响应体，[]byte 和 string 原样输出，其他类型编码为 JSON
+	Body any
+	// Latency 响应前的延迟，客户端取消请求时提前返回
+	Latency time.Duration
+	// FailFirst 前 N 次请求返回错误响应，用于测试重试
+	FailFirst int
+	// ErrorRate 错误响应比例（0~1），按请求序号均匀分布，结果可复现
+	ErrorRate float64
+	// ErrorStatus 错误响应的状态码，默认 500
+	ErrorStatus int
+	// ErrorBody 错误响应体，规则同 Body
+	ErrorBody any
+	// Events 非空时以 text/event-stream 依次输出事件，输出完毕后关闭连接
+	Events []SSEEvent
+	// Handler 自定义处理函数，设置后忽略上述响应配置，Latency 和错误注入仍然生效
+	Handler http.HandlerFunc
+}
+
+// SSEEvent SSE 事件脚本中的一个事件
+type SSEEvent struct {
+	ID    string
+	Event string
+	// Data 事件数据，多行时按行拆分为多个 data 字段
+	Data string
+	// Retry 建议客户端的重连间隔（毫秒），0 表示不输出
+	Retry int
+	// Delay 发送该事件前的等待时间
+	Delay time.Duration
+}
+
+// RecordedRequest 服务收到的请求
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// JSON 返回响应 JSON 数据的路由
+func JSON(method, path string, status int, body any) Route {
+	return Route{Method: method, Path: path, Status: status, Body: body}
+}
+
+// SSE 返回按脚本输出 SSE 事件的 GET 路由
+func SSE(path string, events ...SSEEvent) Route {
+	return Route{Method: http.MethodGet, Path: path, Events: events}
+}
+
+// Echo 返回回显请求的路由，响应格式与 httpbin 的 /get、/post 一致：
+// args（查询参数）、headers、url、data（原始请求体）、json（请求体为 JSON 时的解析结果）
+func Echo(method, path string) Route {
+	return Route{Method: method, Path: path, Handler: echoHandler}
+}
+
+func echoHandler(w http.ResponseWriter, r *http.Request) {
+	args := make(map[string]string)
+	for k, v := range r.URL.Query() {
+		args[k] = v[0]
+	}
+	headers := make(map[string]string)
+	for k, v := range r.Header {
+		headers[k] = v[0]
+	}
+	body, _ := io.ReadAll(r.Body)
+	var jsonBody any
+	if len(body) > 0 {
+		_ = json.Unmarshal(body, &jsonBody)
+	}
+	writeBody(w, http.StatusOK, map[string]any{
+		"method":  r.Method,
+		"args":    args,
+		"headers": headers,
+		"url":     "http://" + r.Host + r.URL.RequestURI(),
+		"data":    string(body),
+		"json":    jsonBody,
+	})
+}
+
+// Server 基于 httptest 的 mock 服务
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   []*routeState
+	requests []RecordedRequest
+}
+
+type routeState struct {
+	Route
+	hits int
+}
+
+// NewServer 启动 mock 服务，tb 不为 nil 时在测试结束时自动关闭
+func NewServer(tb testing.TB, routes ...Route) *Server {
+	s := &Server{}
+	for _, route := range routes {
+		s.Handle(route)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	if tb != nil {
+		tb.Cleanup(s.Close)
+	}
+	return s
+}
+
+// Handle 注册路由 fixture，与已有路由重复时后注册的优先
+func (s *Server) Handle(route Route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append([]*routeState{{Route: route}}, s.routes...)
+}
+
+// HttpClientConfig 返回指向该服务的 HTTP 客户端配置
+func (s *Server) HttpClientConfig() *protocol.HttpClientConfig {
+	return &protocol.HttpClientConfig{
+		Module:  "protocoltest",
+		Host:    s.URL,
+		Timeout: 5 * time.Second,
+	}
+}
+
+// SSEClientConfig 返回指向该服务的 SSE 客户端配置
+func (s *Server) SSEClientConfig() *protocol.SSEClientConfig {
+	return &protocol.SSEClientConfig{
+		Module:        "protocoltest",
+		Host:          s.URL,
+		RetryWaitTime: 100 * time.Millisecond,
+	}
+}
+
+// Requests 返回收到的全部请求，按到达顺序排列
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// LastRequest 返回最近收到的请求，没有请求时返回 nil
+func (s *Server) LastRequest() *RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return nil
+	}
+	req := s.requests[len(s.requests)-1]
+	return &req
+}
+
+// Hits 返回匹配指定路由的请求次数，method 为空时统计任意方法
+func (s *Server) Hits(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, req := range s.requests {
+		if (method == "" || req.Method == method) && matchPath(path, req.Path) {
+			count++
+		}
+	}
+	return count
+}
+
+// Reset 清空请求记录和路由命中次数
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	for _, route := range s.routes {
+		route.hits = 0
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	route := s.matchLocked(r)
+	var hit int
+	if route != nil {
+		route.hits++
+		hit = route.hits
+	}
+	s.mu.Unlock()
+
+	if route == nil {
+		writeBody(w, http.StatusNotFound, map[string]any{
+			"code": http.StatusNotFound,
+			"msg":  fmt.Sprintf("protocoltest: no fixture for %s %s", r.Method, r.URL.Path),
+		})
+		return
+	}
+
+	if route.Latency > 0 {
+		select {
+		case <-time.After(route.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if route.shouldFail(hit) {
+		status := route.ErrorStatus
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		setHeaders(w, route.Headers)
+		writeBody(w, status, route.ErrorBody)
+		return
+	}
+
+	switch {
+	case route.Handler != nil:
+		route.Handler(w, r)
+	case len(route.Events) > 0:
+		setHeaders(w, route.Headers)
+		writeEvents(w, r, route.Events)
+	default:
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		setHeaders(w, route.Headers)
+		writeBody(w, status, route.Body)
+	}
+}
+
+func (s *Server) matchLocked(r *http.Request) *routeState {
+	for _, route := range s.routes {
+		if route.Method != "" && !strings.EqualFold(route.Method, r.Method) {
+			continue
+		}
+		if matchPath(route.Path, r.URL.Path) {
+			return route
+		}
+	}
+	return nil
+}
+
+// shouldFail 判断第 hit 次请求（从 1 开始）是否返回错误响应
+func (r *routeState) shouldFail(hit int) bool {
+	if hit <= r.FailFirst {
+		return true
+	}
+	if r.ErrorRate <= 0 {
+		return false
+	}
+	if r.ErrorRate >= 1 {
+		return true
+	}
+	// 第 n 次请求累计错误数为 floor(n*rate)，累计值增加时该次请求失败
+	n := hit - r.FailFirst
+	return int(float64(n)*r.ErrorRate) > int(float64(n-1)*r.ErrorRate)
+}
+
+func matchPath(pattern, path string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return pattern == path
+}
+
+func setHeaders(w http.ResponseWriter, headers map[string]string) {
+	for k, v := range headers {
+		w.Header().Set(k, v)
+	}
+}
+
+func writeBody(w http.ResponseWriter, status int, body any) {
+	var data []byte
+	switch v := body.(type) {
+	case nil:
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			http.Error(w, fmt.Sprintf("protocoltest: marshal body: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func writeEvents(w http.ResponseWriter, r *http.Request, events []SSEEvent) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	for _, event := range events {
+		if event.Delay > 0 {
+			select {
+			case <-time.After(event.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if _, err := io.WriteString(w, event.encode()); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// encode 按 SSE 协议格式化事件
+func (e SSEEvent) encode() string {
+	var b strings.Builder
+	if e.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", e.ID)
+	}
+	if e.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", e.Event)
+	}
+	if e.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", e.Retry)
+	}
+	for _, line := range strings.Split(e.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package protocoltest
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerJSON(t *testing.T) {
+	srv := NewServer(t,
+		JSON(http.MethodGet, "/users/1", http.StatusOK, map[string]any{"id": 1}),
+		Route{Path: "/raw/*", Body: "raw", Headers: map[string]string{"X-Fixture": "raw"}},
+	)
+
+	resp, err := http.Get(srv.URL + "/users/1?debug=1")
+	require.Nil(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"id":1}`, string(body))
+
+	resp, err = http.Post(srv.URL+"/raw/a/b", "text/plain", strings.NewReader("payload"))
+	require.Nil(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "raw", string(body))
+	assert.Equal(t, "raw", resp.Header.Get("X-Fixture"))
+
+	resp, err = http.Post(srv.URL+"/users/1", "text/plain", nil)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	last := srv.LastRequest()
+	require.NotNil(t, last)
+	assert.Equal(t, http.MethodPost, last.Method)
+	assert.Equal(t, 3, len(srv.Requests()))
+	assert.Equal(t, 1, srv.Hits(http.MethodGet, "/users/1"))
+	assert.Equal(t, 1, srv.Hits("", "/raw/*"))
+	assert.Equal(t, "debug=1", srv.Requests()[0].Query)
+	assert.Equal(t, "payload", string(srv.Requests()[1].Body))
+
+	srv.Reset()
+	assert.Nil(t, srv.LastRequest())
+}
+
+func TestServerErrorInjection(t *testing.T) {
+	srv := NewServer(t,
+		Route{Path: "/retry", FailFirst: 2, ErrorStatus: http.StatusServiceUnavailable},
+		Route{Path: "/flaky", ErrorRate: 0.5},
+	)
+
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(srv.URL + "/retry")
+		require.Nil(t, err)
+		resp.Body.Close()
+		statuses = append(statuses, resp.StatusCode)
+	}
+	assert.Equal(t, []int{503, 503, 200}, statuses)
+
+	failed := 0
+	for i := 0; i < 10; i++ {
+		resp, err := http.Get(srv.URL + "/flaky")
+		require.Nil(t, err)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusInternalServerError {
+			failed++
+		}
+	}
+	assert.Equal(t, 5, failed)
+}
+
+func TestServerLatency(t *testing.T) {
+	srv := NewServer(t, Route{Path: "/slow", Latency: 200 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/slow", nil)
+	_, err := http.DefaultClient.Do(req)
+	assert.NotNil(t, err)
+}
+
+func TestServerSSE(t *testing.T) {
+	srv := NewServer(t, SSE("/events",
+		SSEEvent{ID: "1", Event: "message", Data: "hello"},
+		SSEEvent{ID: "2", Data: "line1\nline2", Delay: 10 * time.Millisecond},
+	))
+
+	resp, err := http.Get(srv.URL + "/events")
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.Equal(t, []string{
+		"id: 1", "event: message", "data: hello", "",
+		"id: 2", "data: line1", "data: line2", "",
+	}, lines)
+}
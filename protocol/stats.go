@@ -0,0 +1,192 @@
+package protocol
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 熔断器状态，未上报时为 BreakerStateUnknown
+const (
+	BreakerStateUnknown  = "unknown"
+	BreakerStateClosed   = "closed"
+	BreakerStateOpen     = "open"
+	BreakerStateHalfOpen = "half_open"
+)
+
+const (
+	// statsWindow QPS、错误率和 P95 的统计窗口
+	statsWindow = time.Minute
+	// statsMaxSamples 每个服务保留的最近调用样本数，窗口内调用超出时只按最近的样本计算
+	statsMaxSamples = 2048
+)
+
+// ServiceStats 单个下游服务的客户端调用统计，Requests、Errors、Retries 为累计值，
+// QPS、ErrorRate、P95 按最近一分钟的调用计算
+type ServiceStats struct {
+	Service      string        `json:"service"`
+	Requests     uint64        `json:"requests"`      // 累计请求数，重试不重复计数
+	Errors       uint64        `json:"errors"`        // 累计失败请求数
+	Retries      uint64        `json:"retries"`       // 累计重试次数
+	QPS          float64       `json:"qps"`           // 最近一分钟的平均 QPS
+	ErrorRate    float64       `json:"error_rate"`    // 最近一分钟的错误率，0 ~ 1
+	P95          time.Duration `json:"p95"`           // 最近一分钟的 P95 耗时
+	P95Ms        int64         `json:"p95_ms"`        // P95 耗时的毫秒数，便于展示
+	BreakerState string        `json:"breaker_state"` // 熔断器状态
+	LastCallAt   time.Time     `json:"last_call_at"`  // 最近一次调用的时间
+}
+
+type callSample struct {
+	at       time.Time
+	duration time.Duration
+	failed   bool
+}
+
+type serviceStats struct {
+	mu           sync.Mutex
+	requests     uint64
+	errors       uint64
+	retries      uint64
+	breakerState string
+	samples      []callSample // 环形缓冲区
+	next         int
+}
+
+var statsRegistry = struct {
+	sync.RWMutex
+	services map[string]*serviceStats
+}{services: make(map[string]*serviceStats)}
+
+func getServiceStats(service string) *serviceStats {
+	statsRegistry.RLock()
+	s, ok := statsRegistry.services[service]
+	statsRegistry.RUnlock()
+	if ok {
+		return s
+	}
+	statsRegistry.Lock()
+	defer statsRegistry.Unlock()
+	if s, ok = statsRegistry.services[service]; !ok {
+		s = &serviceStats{breakerState: BreakerStateUnknown}
+		statsRegistry.services[service] = s
+	}
+	return s
+}
+
+// RecordCall 记录一次对下游服务的调用，duration 包含重试耗时，err 不为 nil 时计为失败。
+// ghttp（流式请求只统计建连耗时）和 gresty 客户端已自动记录，resty.EventSource 等其他客户端可调用此方法接入
+func RecordCall(service string, duration time.Duration, err error) {
+	s := getServiceStats(service)
+	sample := callSample{at: time.Now(), duration: duration, failed: err != nil}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if sample.failed {
+		s.errors++
+	}
+	if len(s.samples) < statsMaxSamples {
+		s.samples = append(s.samples, sample)
+		return
+	}
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % statsMaxSamples
+}
+
+// RecordRetry 记录对下游服务的一次重试
+func RecordRetry(service string) {
+	s := getServiceStats(service)
+	s.mu.Lock()
+	s.retries++
+	s.mu.Unlock()
+}
+
+// SetBreakerState 上报下游服务的熔断器状态，供熔断组件在状态变化时调用
+func SetBreakerState(service, state string) {
+	s := getServiceStats(service)
+	s.mu.Lock()
+	s.breakerState = state
+	s.mu.Unlock()
+}
+
+// Stats 返回各下游服务的调用统计快照，按服务名排序
+func Stats() []ServiceStats {
+	statsRegistry.RLock()
+	services := make(map[string]*serviceStats, len(statsRegistry.services))
+	for name, s := range statsRegistry.services {
+		services[name] = s
+	}
+	statsRegistry.RUnlock()
+
+	now := time.Now()
+	res := make([]ServiceStats, 0, len(services))
+	for name, s := range services {
+		res = append(res, s.snapshot(name, now))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Service < res[j].Service })
+	return res
+}
+
+// ResetStats 清空全部调用统计，用于测试
+func ResetStats() {
+	statsRegistry.Lock()
+	statsRegistry.services = make(map[string]*serviceStats)
+	statsRegistry.Unlock()
+}
+
+// StatsHandler 以 JSON 输出 Stats 的结果，可挂载为管理接口，如 router.GET("/admin/downstream", gin.WrapF(protocol.StatsHandler))
+func StatsHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(Stats())
+}
+
+func (s *serviceStats) snapshot(service string, now time.Time) ServiceStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := ServiceStats{
+		Service:      service,
+		Requests:     s.requests,
+		Errors:       s.errors,
+		Retries:      s.retries,
+		BreakerState: s.breakerState,
+	}
+
+	since := now.Add(-statsWindow)
+	durations := make([]time.Duration, 0, len(s.samples))
+	var (
+		failed int
+		oldest = now
+	)
+	for _, sample := range s.samples {
+		if sample.at.After(stats.LastCallAt) {
+			stats.LastCallAt = sample.at
+		}
+		if sample.at.Before(since) {
+			continue
+		}
+		if sample.at.Before(oldest) {
+			oldest = sample.at
+		}
+		durations = append(durations, sample.duration)
+		if sample.failed {
+			failed++
+		}
+	}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	// 样本数未达上限时窗口内的调用均已记录，按完整窗口计算 QPS；否则按保留样本覆盖的时长计算
+	span := statsWindow
+	if len(s.samples) == statsMaxSamples && now.Sub(oldest) < statsWindow {
+		span = max(now.Sub(oldest), time.Second)
+	}
+	stats.QPS = float64(len(durations)) / span.Seconds()
+	stats.ErrorRate = float64(failed) / float64(len(durations))
+	slices.Sort(durations)
+	stats.P95 = durations[(len(durations)*95+99)/100-1]
+	stats.P95Ms = stats.P95.Milliseconds()
+	return stats
+}
@@ -0,0 +1,63 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	ResetStats()
+	t.Cleanup(ResetStats)
+
+	for i := 1; i <= 20; i++ {
+		var err error
+		if i%5 == 0 {
+			err = errors.New("mock error")
+		}
+		RecordCall("user", time.Duration(i)*time.Millisecond, err)
+	}
+	RecordRetry("user")
+	RecordRetry("user")
+	SetBreakerState("user", BreakerStateHalfOpen)
+	RecordCall("order", 5*time.Millisecond, nil)
+
+	stats := Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "order", stats[0].Service)
+	assert.Equal(t, BreakerStateUnknown, stats[0].BreakerState)
+
+	user := stats[1]
+	assert.Equal(t, "user", user.Service)
+	assert.EqualValues(t, 20, user.Requests)
+	assert.EqualValues(t, 4, user.Errors)
+	assert.EqualValues(t, 2, user.Retries)
+	assert.InDelta(t, 0.2, user.ErrorRate, 1e-9)
+	assert.InDelta(t, 20.0/60, user.QPS, 1e-9)
+	assert.Equal(t, 19*time.Millisecond, user.P95)
+	assert.EqualValues(t, 19, user.P95Ms)
+	assert.Equal(t, BreakerStateHalfOpen, user.BreakerState)
+	assert.False(t, user.LastCallAt.IsZero())
+}
+
+func TestStatsHandler(t *testing.T) {
+	ResetStats()
+	t.Cleanup(ResetStats)
+	RecordCall("user", time.Millisecond, nil)
+
+	w := httptest.NewRecorder()
+	StatsHandler(w, httptest.NewRequest(http.MethodGet, "/admin/downstream", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stats []ServiceStats
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	require.Len(t, stats, 1)
+	assert.Equal(t, "user", stats[0].Service)
+	assert.EqualValues(t, 1, stats[0].Requests)
+}